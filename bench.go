@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/collectors"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/utils"
+)
+
+// runBench implements `pc bench`, measuring the throughput of the checks on generated corpora
+func runBench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	var names []string
+	for _, corpus := range performance.Corpora {
+		names = append(names, corpus.Name)
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc bench [flags]")
+		fmt.Fprintln(flags.Output(), "Runs the checks of the config on generated packages and prints their throughput. The corpora are:")
+		for _, corpus := range performance.Corpora {
+			fmt.Fprintf(flags.Output(), "  %-14s %s\n", corpus.Name, corpus.Description)
+		}
+		flags.PrintDefaults()
+	}
+	cfgPath := flags.String("config", config.FindConfigFile(), "Path or https:// URL of the config file (the built-in default is used if none is found)")
+	offline := flags.Bool("offline", false, "Use the cached copy of a remote -config URL without fetching it")
+	var overrides stringList
+	flags.Var(&overrides, "set", setFlagUsage)
+	profile := flags.String("profile", "", "Use the [operation.<profile>] section of the config instead of [operation.main]")
+	corpora := flags.String("corpus", strings.Join(names, ","), "Corpora to run, comma-separated")
+	scale := flags.Float64("scale", 1, "Multiply the number of files and the size of the huge file, e.g. 0.1 for a quick run")
+	runs := flags.Int("runs", 3, "Runs per corpus, the median is reported")
+	dir := flags.String("dir", "", "Generate the corpora in this directory and keep them (default: a temporary directory that is removed)")
+	jsonOutput := flags.Bool("json", false, "Output JSON format to stdout")
+	save := flags.String("save", "", "Write the results as JSON to this file, to compare later runs with -baseline")
+	baselinePath := flags.String("baseline", "", "Compare with the results saved by an earlier run and exit with 1 if a corpus got slower")
+	maxRegression := flags.Float64("max-regression", 10, "Percent the throughput of a corpus may drop compared to -baseline")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *runs < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	var selected []performance.Corpus
+	for _, name := range checkList(*corpora) {
+		corpus, ok := performance.FindCorpus(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown corpus '%s', expected one of %s\n", name, strings.Join(names, ", "))
+			os.Exit(2)
+		}
+		selected = append(selected, corpus)
+	}
+	var baseline []performance.BenchResult
+	if *baselinePath != "" {
+		data, err := os.ReadFile(*baselinePath)
+		if err == nil {
+			err = json.Unmarshal(data, &baseline)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading baseline '%s': %v\n", *baselinePath, err)
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := loadConfig(*cfgPath, overrides, *offline)
+	if err == nil {
+		err = cfg.ApplyProfile(*profile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	// The findings are not shown, the logger keeps the warnings instead of printing them
+	output.GlobalLogger.SetJSONMode(true)
+
+	root := *dir
+	if root == "" {
+		if root, err = os.MkdirTemp("", "pc-bench-"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(root)
+	}
+
+	var results []performance.BenchResult
+	for _, corpus := range selected {
+		if !*jsonOutput {
+			fmt.Fprintf(os.Stderr, "Running %s...\n", corpus.Name)
+		}
+		result, err := benchCorpus(*cfg, corpus, filepath.Join(root, corpus.Name), *scale, *runs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", corpus.Name, err)
+			os.Exit(1)
+		}
+		results = append(results, result)
+	}
+
+	data, _ := json.MarshalIndent(results, "", "  ")
+	if *save != "" {
+		if err := os.WriteFile(*save, append(data, '\n'), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *jsonOutput {
+		fmt.Println(string(data))
+	} else {
+		printBenchResults(results)
+	}
+
+	if regressions := performance.Regressions(baseline, results, *maxRegression); len(regressions) > 0 {
+		fmt.Fprintln(os.Stderr, "Throughput regressions compared to the baseline:")
+		for _, regression := range regressions {
+			fmt.Fprintln(os.Stderr, "  "+regression)
+		}
+		os.Exit(1)
+	}
+}
+
+// benchCorpus generates corpus in dir unless it is there already and returns the median of runs
+// scans of it: collecting the files and running all checks, as `pc scan` does. Files up to the
+// size of the corpus are checked whatever the maxContentScanFileSize.
+func benchCorpus(cfg config.Config, corpus performance.Corpus, dir string, scale float64, runs int) (performance.BenchResult, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := corpus.Write(dir, scale); err != nil {
+			return performance.BenchResult{}, err
+		}
+	}
+	var bytes int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			bytes += info.Size()
+		}
+		return err
+	})
+	if err != nil {
+		return performance.BenchResult{}, err
+	}
+	// The huge file measures the streaming of large files rather than being skipped as too large
+	general := *cfg.General
+	general.MaxContentScanFileSize = max(general.MaxContentScanFileSize, bytes)
+	cfg.General = &general
+
+	var files int
+	durations := make([]float64, runs)
+	for i := range durations {
+		output.GlobalLogger.ClearMessages()
+		start := time.Now()
+		collected, err := collectors.LocalCollector(dir, cfg)
+		if err != nil {
+			return performance.BenchResult{}, err
+		}
+		utils.ApplyAllChecks(cfg.WithPackageRoot(dir), collected, true)
+		durations[i] = time.Since(start).Seconds()
+		files = len(collected)
+	}
+	output.GlobalLogger.ClearMessages()
+	sort.Float64s(durations)
+	return performance.NewBenchResult(corpus.Name, files, bytes, durations[runs/2]), nil
+}
+
+// printBenchResults prints the results as a table
+func printBenchResults(results []performance.BenchResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CORPUS\tFILES\tMB\tSECONDS\tFILES/S\tMB/S\t")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%.2f\t%.0f\t%.1f\t\n", result.Corpus, result.Files,
+			float64(result.Bytes)/(1<<20), result.Seconds, result.FilesPerSecond, result.MBPerSecond)
+	}
+	w.Flush()
+}
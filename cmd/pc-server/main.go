@@ -1,23 +1,33 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"sort"
+	"strings"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/server"
 )
 
+// overrideList collects the repeatable -set flag
+type overrideList []string
+
+func (l *overrideList) String() string     { return strings.Join(*l, ", ") }
+func (l *overrideList) Set(v string) error { *l = append(*l, v); return nil }
+
 func main() {
 	// Parse command line flags
-	addr := flag.String("addr", ":8080", "Server listen address (e.g., :8080 or 0.0.0.0:8080)")
-	configPath := flag.String("config", "", "Path to PC config file (pc.toml)")
+	addr := flag.String("addr", server.DefaultAddress(), "Server listen address (e.g., :8080 or 0.0.0.0:8080, default :$PORT if PORT is set)")
+	configPath := flag.String("config", "", "Path or https:// URL of the PC config file (pc.toml)")
 	ckanURL := flag.String("ckan-url", "", "CKAN base URL (overrides config)")
+	profile := flag.String("profile", "", "Use the [operation.<profile>] section of the config instead of [operation.main]")
+	var overrides overrideList
+	flag.Var(&overrides, "set", "Override a config value, e.g. -set collector.CkanCollector.attrs.verify=false (repeatable)")
+	verbose := flag.Bool("verbose", false, "Log debug messages, such as every request")
+	quiet := flag.Bool("quiet", false, "Log errors only")
+	logFormat := flag.String("log-format", output.FormatConsole, "Format of the log lines: console or json")
 	help := flag.Bool("help", false, "Show usage information")
 	flag.Parse()
 
@@ -26,6 +36,10 @@ func main() {
 		return
 	}
 
+	if err := output.GlobalLogger.Configure(*verbose, *quiet, *logFormat); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
 	// Find config file if not specified
 	if *configPath == "" {
 		*configPath = config.FindConfigFile()
@@ -39,6 +53,8 @@ func main() {
 		Address:     *addr,
 		ConfigPath:  *configPath,
 		CKANBaseURL: *ckanURL,
+		Profile:     *profile,
+		Overrides:   overrides,
 		VerifyTLS:   true, // Default to secure
 	}
 
@@ -48,31 +64,10 @@ func main() {
 		log.Fatalf("Failed to create server: %v", err)
 	}
 
-	// Set up graceful shutdown
-	done := make(chan bool, 1)
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		<-quit
-		log.Println("Server is shutting down...")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		if err := srv.Shutdown(ctx); err != nil {
-			log.Fatalf("Could not gracefully shutdown the server: %v", err)
-		}
-		close(done)
-	}()
-
-	// Start server
-	if err := srv.ListenAndServe(); err != nil {
-		log.Printf("Server stopped: %v", err)
+	// Serve until interrupted
+	if err := srv.Run(); err != nil {
+		log.Fatalf("Server stopped: %v", err)
 	}
-
-	<-done
-	log.Println("Server stopped")
 }
 
 func printUsage() {
@@ -84,16 +79,33 @@ func printUsage() {
 	log.Println("Flags:")
 	flag.PrintDefaults()
 	log.Println("")
-	log.Println("Environment Variables:")
-	log.Println("  (none currently)")
+	log.Println("Environment Variables (override the config file, -set takes precedence):")
+	var names []string
+	for name := range config.EnvOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		log.Printf("  %-30s %s\n", name, config.EnvOverrides[name].Key)
+	}
+	log.Printf("  %-30s %s\n", config.ConfigEnvVar, "Path or https:// URL of the config file, if -config is not given")
+	log.Printf("  %-30s %s\n", config.AuthEnvVar, "Authorization header for an https:// -config URL")
+	log.Printf("  %-30s %s\n", "PORT", "Listen on this port, if -addr is not given")
 	log.Println("")
 	log.Println("Examples:")
 	log.Println("  pc-server -config ./pc.toml")
 	log.Println("  pc-server -addr :9000 -config /etc/pc/pc.toml")
+	log.Println("  pc-server -config ./pc.toml -profile server")
+	log.Println("  pc-server -config https://policies.example.org/pc.toml")
 	log.Println("")
 	log.Println("API Endpoints:")
 	log.Println("  GET  /health              - Health check")
+	log.Println("  GET  /ready               - Readiness probe")
 	log.Println("  POST /api/v1/analyze      - Analyze a CKAN package")
+	log.Println("  GET  /api/v1/diff         - Compare the last two scans of a package")
+	log.Println("  GET  /api/v1/stats        - Statistics of the stored scans")
+	log.Println("  GET  /ui                  - Web UI listing the stored scans with their HTML reports")
+	log.Println("  POST /api/v1/webhook      - Scan a package when CKAN notifies a changed resource")
 	log.Println("")
 	log.Println("Authentication:")
 	log.Println("  Use your CKAN API token in the Authorization header:")
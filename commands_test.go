@@ -0,0 +1,725 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// buildBinary builds pc into a temporary directory
+func buildBinary(t *testing.T) string {
+	binaryPath := filepath.Join(t.TempDir(), "pc")
+	cmd := exec.Command("go", "build", "-o", binaryPath, ".")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\nOutput: %s", err, string(output))
+	}
+	return binaryPath
+}
+
+func TestScanSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	cmd := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-json")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if _, ok := result["details_check_focused"]; !ok {
+		t.Error("JSON output missing 'details_check_focused'")
+	}
+}
+
+func TestScanRedact(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	output, err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-json", "-redact").Output()
+	if err != nil {
+		t.Fatalf("scan -redact failed: %v", err)
+	}
+	var result struct {
+		Location            string `json:"location"`
+		DetailsCheckFocused []struct {
+			Checkname string `json:"checkname"`
+			Issues    []struct {
+				Path    string `json:"path"`
+				Message string `json:"message"`
+				Snippet string `json:"snippet"`
+			} `json:"issues"`
+		} `json:"details_check_focused"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if strings.Contains(string(output), tempDir) {
+		t.Errorf("Expected no absolute paths in the redacted report:\n%s", string(output))
+	}
+	if result.Location != "test_scan" {
+		t.Errorf("Expected the location to be the name of the folder, got %q", result.Location)
+	}
+	found := false
+	for _, check := range result.DetailsCheckFocused {
+		if check.Checkname != "IsFreeOfKeywords" {
+			continue
+		}
+		for _, issue := range check.Issues {
+			found = true
+			if strings.Contains(issue.Message, "'password'") || strings.Contains(issue.Snippet, "secret") {
+				t.Errorf("Expected the keywords to be redacted, got %q and %q", issue.Message, issue.Snippet)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected keyword findings, got %s", string(output))
+	}
+}
+
+func TestScanNDJSON(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	output, err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-ndjson").Output()
+	if err != nil {
+		t.Fatalf("scan -ndjson failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for i, line := range lines {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\n%s", i+1, err, line)
+		}
+		expectedType := "finding"
+		if i == len(lines)-1 {
+			expectedType = "summary"
+		}
+		if object["type"] != expectedType {
+			t.Errorf("line %d: expected type %q, got %v", i+1, expectedType, object["type"])
+		}
+	}
+	if len(lines) < 2 {
+		t.Errorf("expected findings before the summary, got:\n%s", output)
+	}
+
+	if err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-ndjson", "-json").Run(); err == nil {
+		t.Error("expected -ndjson with -json to fail")
+	}
+}
+
+func TestScanListArchives(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	archive, err := os.Create(filepath.Join(testDir, "data.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(archive)
+	member, err := writer.Create("raw/secret.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	member.Write([]byte("password"))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+
+	output, err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-list-archives", "-json").Output()
+	if err != nil {
+		t.Fatalf("scan -list-archives -json failed: %v", err)
+	}
+	var result struct {
+		Archives []struct {
+			Archive string `json:"archive"`
+			Members []struct {
+				Name string `json:"name"`
+				Size int64  `json:"size"`
+			} `json:"members"`
+		} `json:"archives"`
+		DetailsCheckFocused []interface{} `json:"details_check_focused"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if len(result.Archives) != 1 || result.Archives[0].Archive != "data.zip" || len(result.Archives[0].Members) != 1 ||
+		result.Archives[0].Members[0].Name != "raw/secret.txt" || result.Archives[0].Members[0].Size != 8 {
+		t.Errorf("Unexpected archive listing:\n%s", string(output))
+	}
+	if len(result.DetailsCheckFocused) != 0 {
+		t.Errorf("Expected the checks not to run, got %v", result.DetailsCheckFocused)
+	}
+
+	output, err = exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-list-archives").Output()
+	if err != nil {
+		t.Fatalf("scan -list-archives failed: %v", err)
+	}
+	if !strings.Contains(string(output), "raw/secret.txt") {
+		t.Errorf("Expected the text listing to show the members, got:\n%s", string(output))
+	}
+
+	if err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-list-archives", "-plain").Run(); err == nil {
+		t.Error("expected -list-archives with -plain to fail")
+	}
+}
+
+func TestScanBaseline(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	scan := func(args ...string) map[string]interface{} {
+		t.Helper()
+		args = append([]string{"scan", "-config", configPath, "-location", testDir, "-json"}, args...)
+		output, err := exec.Command(binaryPath, args...).Output()
+		if err != nil {
+			t.Fatalf("scan %v failed: %v", args, err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return result
+	}
+	countIssues := func(result map[string]interface{}) int {
+		count := 0
+		for _, check := range result["details_check_focused"].([]interface{}) {
+			count += len(check.(map[string]interface{})["issues"].([]interface{}))
+		}
+		return count
+	}
+
+	before := scan()
+	check := before["details_check_focused"].([]interface{})[0].(map[string]interface{})
+	issue := check["issues"].([]interface{})[0].(map[string]interface{})
+	entry := map[string]interface{}{"checkname": check["checkname"], "path": issue["path"], "message": issue["message"], "status": "accepted"}
+	if archive, ok := issue["archive_name"]; ok {
+		entry["archive_name"] = archive
+	}
+	content, _ := json.Marshal(map[string]interface{}{"entries": []interface{}{entry}})
+	baselinePath := filepath.Join(tempDir, "baseline.json")
+	if err := os.WriteFile(baselinePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after := scan("-baseline", baselinePath)
+	if countIssues(after) != countIssues(before)-1 {
+		t.Errorf("expected the accepted issue to be hidden, got %d issues before and %d after", countIssues(before), countIssues(after))
+	}
+	suppressed, ok := after["suppressed"].(map[string]interface{})
+	if !ok || suppressed["accepted"] != float64(1) {
+		t.Errorf("expected 1 accepted finding in the report, got %v", after["suppressed"])
+	}
+
+	all := scan("-baseline", baselinePath, "-no-baseline")
+	if countIssues(all) != countIssues(before) || all["suppressed"] != nil {
+		t.Errorf("expected -no-baseline to report all findings, got %d issues and %v", countIssues(all), all["suppressed"])
+	}
+}
+
+func TestReportSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	jsonPath := filepath.Join(tempDir, "report.json")
+	output, err := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-json").Output()
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if err := os.WriteFile(jsonPath, output, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	htmlPath := filepath.Join(tempDir, "report.html")
+	if output, err := exec.Command(binaryPath, "report", "-html", htmlPath, jsonPath).CombinedOutput(); err != nil {
+		t.Fatalf("report failed: %v\nOutput: %s", err, string(output))
+	}
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("HTML report was not written: %v", err)
+	}
+	if !strings.Contains(string(html), "<html") {
+		t.Error("HTML report does not look like HTML")
+	}
+
+	splitPath := filepath.Join(tempDir, "split", "index.html")
+	if output, err := exec.Command(binaryPath, "report", "-html", splitPath, "-html-mode", "split", "-html-title", "Review", jsonPath).CombinedOutput(); err != nil {
+		t.Fatalf("report failed: %v\nOutput: %s", err, string(output))
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "split", "index_files", "data.json")); err != nil {
+		t.Errorf("split report data was not written: %v", err)
+	}
+
+	markdown, err := exec.Command(binaryPath, "report", "-markdown", jsonPath).Output()
+	if err != nil {
+		t.Fatalf("report -markdown failed: %v", err)
+	}
+	if !strings.Contains(string(markdown), "## Package Checker Scan Summary") {
+		t.Errorf("Markdown summary missing heading:\n%s", string(markdown))
+	}
+
+	csv, err := exec.Command(binaryPath, "report", "-csv", jsonPath).Output()
+	if err != nil {
+		t.Fatalf("report -csv failed: %v", err)
+	}
+	if !strings.HasPrefix(string(csv), "file,path,archive,line,check,severity,count,message,id\r\n") || !strings.Contains(string(csv), ",IsFreeOfKeywords,") {
+		t.Errorf("Unexpected CSV output:\n%s", string(csv))
+	}
+
+	sarif, err := exec.Command(binaryPath, "report", jsonPath, "-sarif").Output()
+	if err != nil {
+		t.Fatalf("report -sarif failed: %v", err)
+	}
+	var sarifLog map[string]interface{}
+	if err := json.Unmarshal(sarif, &sarifLog); err != nil || sarifLog["version"] != "2.1.0" {
+		t.Errorf("Unexpected SARIF output (%v):\n%s", err, string(sarif))
+	}
+
+	templatePath := filepath.Join(tempDir, "letter.tmpl")
+	if err := os.WriteFile(templatePath, []byte(`{{range groupBy "check" (issues .)}}{{.Key}}: {{len .Issues}}{{"\n"}}{{end}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	letter, err := exec.Command(binaryPath, "report", "-template", templatePath, jsonPath).Output()
+	if err != nil {
+		t.Fatalf("report -template failed: %v", err)
+	}
+	if !strings.Contains(string(letter), "IsFreeOfKeywords: ") {
+		t.Errorf("Template output missing check counts:\n%s", string(letter))
+	}
+
+	// Missing arguments are a usage error
+	if err := exec.Command(binaryPath, "report", jsonPath).Run(); err == nil {
+		t.Error("expected report without -html to fail")
+	}
+	if err := exec.Command(binaryPath, "report", "-html", htmlPath, "-markdown", jsonPath).Run(); err == nil {
+		t.Error("expected report with -html and -markdown to fail")
+	}
+	if err := exec.Command(binaryPath, "report", "-csv", "-sarif", jsonPath).Run(); err == nil {
+		t.Error("expected report with -csv and -sarif to fail")
+	}
+	if err := exec.Command(binaryPath, "report", "-template", filepath.Join(tempDir, "missing.tmpl"), jsonPath).Run(); err == nil {
+		t.Error("expected report with a missing template to fail")
+	}
+	if err := exec.Command(binaryPath, "report", "-html", htmlPath, "-html-mode", "zip", jsonPath).Run(); err == nil {
+		t.Error("expected report with an unknown -html-mode to fail")
+	}
+}
+
+func TestReportDiffSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	oldPath := filepath.Join(tempDir, "old.json")
+	newPath := filepath.Join(tempDir, "new.json")
+	if err := os.WriteFile(oldPath, []byte(`{"details_check_focused": [{"checkname": "HasNoWhiteSpace", "issues": [{"subject": "a b.txt", "message": "File name contains spaces"}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, []byte(`{"details_check_focused": [{"checkname": "IsFreeOfKeywords", "issues": [{"subject": "c.txt", "message": "found token"}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	htmlPath := filepath.Join(tempDir, "diff.html")
+	if output, err := exec.Command(binaryPath, "report", "diff", oldPath, newPath, "-html", htmlPath).CombinedOutput(); err != nil {
+		t.Fatalf("report diff failed: %v\nOutput: %s", err, string(output))
+	}
+	if html, err := os.ReadFile(htmlPath); err != nil || !strings.Contains(string(html), "found token") {
+		t.Errorf("HTML diff was not written: %v", err)
+	}
+
+	output, err := exec.Command(binaryPath, "report", "diff", oldPath, newPath).Output()
+	if err != nil {
+		t.Fatalf("report diff failed: %v", err)
+	}
+	if !strings.Contains(string(output), "1 new, 1 fixed, 0 unchanged") {
+		t.Errorf("unexpected text diff:\n%s", output)
+	}
+
+	if err := exec.Command(binaryPath, "report", "diff", oldPath).Run(); err == nil {
+		t.Error("expected report diff with one report to fail")
+	}
+}
+
+func TestCompareSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success": true, "result": {"name": "lake-ice", "resources": [
+			{"name": "data.csv", "url": "https://ckan.example/dataset/p/resource/f46e74be-1c61/download/data.csv", "url_type": "upload", "size": 4}
+		]}}`)
+	}))
+	defer server.Close()
+	configPath := filepath.Join(tempDir, "pc.toml")
+	configContent := "[operation.main]\ncollector = \"LocalCollector\"\n\n[collector.CkanCollector]\nattrs = {url = \"" + server.URL + "\", token = \"\", verify = true, ckan_storage_path = \"\"}\n"
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	folder := filepath.Join(tempDir, "upload")
+	os.MkdirAll(folder, 0755)
+	os.WriteFile(filepath.Join(folder, "data.csv"), []byte("a,b\n"), 0644)
+
+	output, err := exec.Command(binaryPath, "compare", "-config", configPath, folder, "lake-ice").Output()
+	if err != nil || !strings.Contains(string(output), "1 matching, 0 different, 0 only local, 0 only in CKAN") {
+		t.Errorf("expected the folder to match the package: %v\n%s", err, output)
+	}
+
+	os.WriteFile(filepath.Join(folder, "extra.csv"), []byte("c\n"), 0644)
+	output, err = exec.Command(binaryPath, "compare", "-config", configPath, "-json", folder, "lake-ice").Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1 for differences, got %v", err)
+	}
+	var comparison struct {
+		OnlyLocal []struct {
+			Name string `json:"name"`
+		} `json:"only_local"`
+	}
+	if err := json.Unmarshal(output, &comparison); err != nil || len(comparison.OnlyLocal) != 1 || comparison.OnlyLocal[0].Name != "extra.csv" {
+		t.Errorf("expected extra.csv only local, got %v\n%s", err, output)
+	}
+}
+
+func TestReportMergeSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	reportsDir := filepath.Join(tempDir, "reports")
+	if err := os.Mkdir(reportsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lakePath := filepath.Join(reportsDir, "lake.json")
+	riverPath := filepath.Join(reportsDir, "river.json")
+	if err := os.WriteFile(lakePath, []byte(`{"location": "lake-ice", "timestamp": "2024-01-14T10:30:00Z", "details_check_focused": [{"checkname": "HasNoWhiteSpace", "issues": [{"subject": "a b.txt", "message": "File name contains spaces"}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(riverPath, []byte(`{"timestamp": "2024-02-01T08:00:00Z", "details_check_focused": [{"checkname": "HasNoWhiteSpace", "issues": [{"subject": "c d.txt", "message": "File name contains spaces"}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	combinedPath := filepath.Join(tempDir, "combined.json")
+	if output, err := exec.Command(binaryPath, "report", "merge", reportsDir, "-o", combinedPath).CombinedOutput(); err != nil {
+		t.Fatalf("report merge failed: %v\nOutput: %s", err, string(output))
+	}
+	var combined struct {
+		Locations []struct {
+			Location string `json:"location"`
+		} `json:"locations"`
+		DetailsCheckFocused []struct {
+			Issues []struct {
+				Location string `json:"location"`
+			} `json:"issues"`
+		} `json:"details_check_focused"`
+	}
+	raw, err := os.ReadFile(combinedPath)
+	if err != nil {
+		t.Fatalf("combined report was not written: %v", err)
+	}
+	if err := json.Unmarshal(raw, &combined); err != nil {
+		t.Fatalf("combined report is not valid JSON: %v", err)
+	}
+	if len(combined.Locations) != 2 || combined.Locations[0].Location != "lake-ice" || combined.Locations[1].Location != riverPath {
+		t.Errorf("unexpected locations: %+v", combined.Locations)
+	}
+	if len(combined.DetailsCheckFocused) != 1 || len(combined.DetailsCheckFocused[0].Issues) != 2 || combined.DetailsCheckFocused[0].Issues[1].Location != riverPath {
+		t.Errorf("unexpected issues: %s", raw)
+	}
+
+	// Combined reports can be converted like any other
+	if output, err := exec.Command(binaryPath, "report", "-markdown", combinedPath).CombinedOutput(); err != nil {
+		t.Errorf("report -markdown of the combined report failed: %v\nOutput: %s", err, string(output))
+	}
+	if err := exec.Command(binaryPath, "report", "merge").Run(); err == nil {
+		t.Error("expected report merge without reports to fail")
+	}
+}
+
+func TestReportStatsSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	for name, report := range map[string]string{
+		"lake-1.json":  `{"location": "lake", "timestamp": "2024-01-14T10:30:00Z", "details_check_focused": [{"checkname": "HasNoWhiteSpace", "issues": [{"subject": "a b.txt", "message": "File name contains spaces"}, {"subject": "c d.txt", "message": "File name contains spaces"}]}]}`,
+		"lake-2.json":  `{"location": "lake", "timestamp": "2024-02-14T10:30:00Z", "details_check_focused": [{"checkname": "HasNoWhiteSpace", "issues": [{"subject": "a b.txt", "message": "File name contains spaces"}]}]}`,
+		"river-1.json": `{"location": "river", "timestamp": "2024-02-20T10:30:00Z", "details_check_focused": []}`,
+	} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(report), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := exec.Command(binaryPath, "report", "stats", tempDir).Output()
+	if err != nil {
+		t.Fatalf("report stats failed: %v", err)
+	}
+	if !strings.Contains(string(output), "3 scans of 2 locations") || !strings.Contains(string(output), "HasNoWhiteSpace") {
+		t.Errorf("unexpected statistics:\n%s", output)
+	}
+
+	htmlPath := filepath.Join(tempDir, "dashboard", "stats.html")
+	if output, err := exec.Command(binaryPath, "report", "stats", "-html", htmlPath, "-period", "week", tempDir).CombinedOutput(); err != nil {
+		t.Fatalf("report stats -html failed: %v\nOutput: %s", err, string(output))
+	}
+	if html, err := os.ReadFile(htmlPath); err != nil || !strings.Contains(string(html), "2024-W07") {
+		t.Errorf("HTML dashboard was not written: %v", err)
+	}
+
+	if err := exec.Command(binaryPath, "report", "stats", "-period", "year", tempDir).Run(); err == nil {
+		t.Error("expected report stats with an unknown period to fail")
+	}
+	if err := exec.Command(binaryPath, "report", "stats").Run(); err == nil {
+		t.Error("expected report stats without reports to fail")
+	}
+}
+
+func TestViewReportPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"v2.json", "v1.json", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := reportPaths([]string{"latest.json", tempDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"latest.json", filepath.Join(tempDir, "v1.json"), filepath.Join(tempDir, "v2.json")}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	if _, err := reportPaths([]string{t.TempDir()}); err == nil {
+		t.Error("expected a directory without reports to fail")
+	}
+}
+
+func TestSchemaSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+
+	output, err := exec.Command(binaryPath, "schema").Output()
+	if err != nil {
+		t.Fatalf("schema failed: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(output, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	if schema["title"] != "Package Checker scan result" {
+		t.Errorf("unexpected schema title: %v", schema["title"])
+	}
+
+	if scanOutput, err := exec.Command(binaryPath, "scan", "-schema").Output(); err != nil || string(scanOutput) != string(output) {
+		t.Errorf("expected scan -schema to print the same schema, got error %v", err)
+	}
+}
+
+func TestBenchSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	saved := filepath.Join(tempDir, "bench.json")
+
+	output, err := exec.Command(binaryPath, "bench", "-config", configPath, "-corpus", "small-files,deep-archive", "-scale", "0.01", "-runs", "1", "-json", "-save", saved).Output()
+	if err != nil {
+		t.Fatalf("bench failed: %v", err)
+	}
+	var results []struct {
+		Corpus      string  `json:"corpus"`
+		Files       int     `json:"files"`
+		Bytes       int64   `json:"bytes"`
+		MBPerSecond float64 `json:"mb_per_second"`
+	}
+	if err := json.Unmarshal(output, &results); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if len(results) != 2 || results[0].Corpus != "small-files" || results[0].Files != 20 || results[1].MBPerSecond <= 0 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if data, err := os.ReadFile(saved); err != nil || !strings.Contains(string(data), "deep-archive") {
+		t.Errorf("expected the results to be saved, got %s (%v)", data, err)
+	}
+
+	// A baseline far faster than any machine fails the gate
+	os.WriteFile(saved, []byte(`[{"corpus": "small-files", "files": 20, "bytes": `+strconv.FormatInt(results[0].Bytes, 10)+`, "mb_per_second": 1e9}]`), 0644)
+	cmd := exec.Command(binaryPath, "bench", "-config", configPath, "-corpus", "small-files", "-scale", "0.01", "-runs", "1", "-baseline", saved)
+	if combined, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(combined), "small-files:") {
+		t.Errorf("expected a regression, got %v: %s", err, combined)
+	}
+
+	if err := exec.Command(binaryPath, "bench", "-corpus", "unknown").Run(); err == nil {
+		t.Error("expected an error for an unknown corpus")
+	}
+}
+
+func TestConfigValidateSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+
+	output, err := exec.Command(binaryPath, "config", "validate", "-config", configPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("validate failed for a valid config: %v\nOutput: %s", err, string(output))
+	}
+	if !strings.Contains(string(output), "is valid") {
+		t.Errorf("unexpected output: %s", string(output))
+	}
+
+	invalidPath := filepath.Join(tempDir, "invalid.toml")
+	os.WriteFile(invalidPath, []byte("[rule.Broken]\nrule = \"file.size >\"\n"), 0644)
+	output, err = exec.Command(binaryPath, "config", "validate", "-config", invalidPath).CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected validate to fail for an invalid config\nOutput: %s", string(output))
+	}
+	if !strings.Contains(string(output), "invalid.toml:2: error: rule.Broken.rule:") {
+		t.Errorf("expected the line and field of the broken rule, got: %s", string(output))
+	}
+}
+
+func TestConfigInitSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+
+	cmd := exec.Command(binaryPath, "config", "init")
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("config init failed: %v\nOutput: %s", err, string(output))
+	}
+	written, err := os.ReadFile(filepath.Join(tempDir, "pc.toml"))
+	if err != nil || string(written) != string(defaultConfig) {
+		t.Fatalf("expected the default config to be written, got error %v", err)
+	}
+
+	cmd = exec.Command(binaryPath, "config", "init")
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(output), "already exists") {
+		t.Errorf("expected config init to refuse overwriting, got: %s", string(output))
+	}
+
+	cmd = exec.Command(binaryPath, "config", "init", "-xdg")
+	cmd.Env = append(os.Environ(), "XDG_CONFIG_HOME="+filepath.Join(tempDir, "xdg"))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("config init -xdg failed: %v\nOutput: %s", err, string(output))
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "xdg", "pc", "pc.toml")); err != nil {
+		t.Errorf("expected the per-user config to be written: %v", err)
+	}
+}
+
+func TestScanWithDefaultConfig(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	testDir := createTestFiles(t, tempDir)
+
+	cmd := exec.Command(binaryPath, "scan", "-location", testDir, "-json")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+tempDir, "XDG_CONFIG_HOME="+filepath.Join(tempDir, "xdg"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if _, ok := result["error"]; ok {
+		t.Errorf("expected the built-in default config to be used, got: %s", string(output))
+	}
+}
+
+func TestListChecksSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+
+	output, err := exec.Command(binaryPath, "list-checks", "-config", "", "-json").Output()
+	if err != nil {
+		t.Fatalf("list-checks failed: %v", err)
+	}
+	var listings []CheckListing
+	if err := json.Unmarshal(output, &listings); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+	}
+	if len(listings) == 0 || listings[0].Name != "HasOnlyASCII" {
+		t.Errorf("unexpected listing: %+v", listings)
+	}
+}
+
+func TestUnknownSubcommand(t *testing.T) {
+	binaryPath := buildBinary(t)
+
+	output, err := exec.Command(binaryPath, "frobnicate").CombinedOutput()
+	if err == nil {
+		t.Fatal("expected an unknown command to fail")
+	}
+	if !strings.Contains(string(output), "unknown command 'frobnicate'") {
+		t.Errorf("unexpected output: %s", string(output))
+	}
+}
+
+func TestScanSelectChecks(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir := t.TempDir()
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	checkNames := func(args ...string) map[string]bool {
+		t.Helper()
+		args = append([]string{"scan", "-config", configPath, "-location", testDir, "-json"}, args...)
+		output, err := exec.Command(binaryPath, args...).Output()
+		if err != nil {
+			t.Fatalf("scan %v failed: %v", args, err)
+		}
+		var result struct {
+			Checks []struct {
+				Checkname string `json:"checkname"`
+			} `json:"details_check_focused"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+		}
+		names := map[string]bool{}
+		for _, check := range result.Checks {
+			names[check.Checkname] = true
+		}
+		return names
+	}
+
+	all := checkNames()
+	if len(all) < 2 {
+		t.Fatalf("expected findings of several checks, got %v", all)
+	}
+	var first string
+	for name := range all {
+		first = name
+		break
+	}
+	if only := checkNames("-only-checks", first); len(only) != 1 || !only[first] {
+		t.Errorf("expected only the findings of %s, got %v", first, only)
+	}
+	if skipped := checkNames("-skip-checks", " "+first+" ,"); skipped[first] || len(skipped) != len(all)-1 {
+		t.Errorf("expected all findings but those of %s, got %v", first, skipped)
+	}
+
+	output, _ := exec.Command(binaryPath, "scan", "-config", configPath, "-location", testDir, "-json", "-only-checks", "IsFreeOfKeyword").Output()
+	if !strings.Contains(string(output), "config_error") || !strings.Contains(string(output), "unknown check 'IsFreeOfKeyword'") {
+		t.Errorf("expected an error for an unknown check, got %s", output)
+	}
+}
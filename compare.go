@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/collectors"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+)
+
+// runCompare implements `pc compare`, verifying that a local folder and a CKAN package have the
+// same files. It exits with 1 if they differ, so it can gate an upload in scripts.
+func runCompare(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	cfgPath := flags.String("config", config.FindConfigFile(), "Path or https:// URL of the config file (the built-in default is used if none is found)")
+	offline := flags.Bool("offline", false, "Use the cached copy of a remote -config URL without fetching it")
+	var overrides stringList
+	flags.Var(&overrides, "set", setFlagUsage)
+	jsonOutput := flags.Bool("json", false, "Print the matching, different and missing files as JSON")
+	logging := addLogFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc compare [-config <pc.toml>] [-json] <folder> <ckan-package>")
+		fmt.Fprintln(flags.Output(), "Lists the files missing on either side and those whose size or hash differ; exits with 1 if there are any.")
+		flags.PrintDefaults()
+	}
+	locations := parseInterspersed(flags, args)
+
+	if len(locations) != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// The warnings of the collectors are kept instead of printed between the results
+	output.GlobalLogger.SetJSONMode(true)
+	if err := logging.apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	cfg, err := loadConfig(*cfgPath, overrides, *offline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(2)
+	}
+
+	comparison, err := collectors.ComparePackage(locations[0], locations[1], *cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(comparison.FormatText())
+	}
+	if !comparison.Identical() {
+		os.Exit(1)
+	}
+}
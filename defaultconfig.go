@@ -0,0 +1,38 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+// defaultConfig is used when no config file is found, and written by `pc config init`
+//
+//go:embed pc.toml.example
+var defaultConfig []byte
+
+// defaultConfigSource names the built-in config in messages
+const defaultConfigSource = "built-in default config"
+
+// loadConfig loads the config file or URL at path, or the built-in default if path is empty.
+// With offline set a remote config is taken from the local cache only.
+func loadConfig(path string, overrides []string, offline bool) (*config.Config, error) {
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "No config file found, using the built-in default. Run 'pc config init' to create pc.toml.")
+		return config.LoadConfigData(defaultConfig, defaultConfigSource, overrides, offline)
+	}
+	data, err := config.ReadConfig(path, offline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", path, err)
+	}
+	cfg, err := config.LoadConfigData(data, path, overrides, offline)
+	if err != nil {
+		return nil, err
+	}
+	if !offline {
+		config.CacheConfig(path, data)
+	}
+	return cfg, nil
+}
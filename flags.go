@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/output"
+	htmlformatter "github.com/eawag-rdm/pc/pkg/output/html"
+	"github.com/eawag-rdm/pc/pkg/output/tui"
+)
+
+// stringList is a flag that may be given several times, e.g. -set a=1 -set b=2
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// setFlagUsage documents the -set flag of the commands loading a config
+const setFlagUsage = "Override a config value, e.g. -set general.maxArchiveFileSize=50MB (repeatable, takes precedence over PC_* environment variables)"
+
+// langFlagUsage documents the -lang flag of the commands writing reports
+var langFlagUsage = "Language of the report: " + strings.Join(i18n.Languages(), ", ") + " (overrides 'language' in the config)"
+
+// selectLanguage selects the language of the -lang flag, else the configured one
+func selectLanguage(flagValue string, configured string) error {
+	if flagValue != "" {
+		configured = flagValue
+	}
+	return i18n.SetLanguage(configured)
+}
+
+// timezoneFlagUsage documents the -timezone flag of the commands showing times
+const timezoneFlagUsage = "Time zone of the times in summaries, the TUI and HTML reports, e.g. Europe/Zurich or UTC"
+
+// selectTimezone selects the time zone of the -timezone flag, else the configured one
+func selectTimezone(flagValue string, configured string) error {
+	if flagValue != "" {
+		configured = flagValue
+	}
+	return i18n.SetTimezone(configured)
+}
+
+// logOptions are the flags selecting what is logged and how
+type logOptions struct {
+	verbose *bool
+	quiet   *bool
+	format  *string
+}
+
+// addLogFlags adds -verbose, -quiet and -log-format to the flags of a command
+func addLogFlags(flags *flag.FlagSet) logOptions {
+	return logOptions{
+		verbose: flags.Bool("verbose", false, "Log debug messages and write the log to stderr as it happens"),
+		quiet:   flags.Bool("quiet", false, "Log errors only"),
+		format:  flags.String("log-format", output.FormatConsole, "Format of the log lines: console or json"),
+	}
+}
+
+// apply configures the global logger with the flags
+func (o logOptions) apply() error {
+	return output.GlobalLogger.Configure(*o.verbose, *o.quiet, *o.format)
+}
+
+// fullSummaryUsage documents the -full-summary flag of the commands writing summaries
+const fullSummaryUsage = "List every issue in the summaries instead of the first ones of groups of similar issues"
+
+// summaryTruncation returns how many issues of groups of similar issues the summaries list:
+// none with -full-summary, else those of the config, if any
+func summaryTruncation(cfg *config.Config, full bool) tui.Truncation {
+	switch {
+	case full:
+		return tui.NoTruncation
+	case cfg != nil && cfg.General != nil:
+		return tui.Truncation{MaxIssues: cfg.General.SummaryMaxIssues, MinGroupSize: cfg.General.SummaryMinGroupSize}
+	}
+	return tui.DefaultTruncation
+}
+
+// checkList splits the comma-separated check names of -only-checks and -skip-checks
+func checkList(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// selectChecks applies -only-checks and -skip-checks to cfg, rejecting names that are neither
+// built-in checks nor rules or plugins of cfg
+func selectChecks(cfg *config.Config, only, skip string) error {
+	known := map[string]bool{}
+	for _, check := range checks.Registry {
+		known[check.Name] = true
+	}
+	for name := range cfg.Rules {
+		known[name] = true
+	}
+	for name := range cfg.Plugins {
+		known[name] = true
+	}
+	onlyChecks, skipChecks := checkList(only), checkList(skip)
+	for _, name := range append(append([]string{}, onlyChecks...), skipChecks...) {
+		if !known[name] {
+			return fmt.Errorf("unknown check '%s', 'pc list-checks' shows the available checks", name)
+		}
+	}
+	cfg.SelectChecks(onlyChecks, skipChecks)
+	return nil
+}
+
+// parseInterspersed parses flags that may follow the positional arguments, as in
+// `pc report diff old.json new.json -html diff.html`, and returns the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// htmlFlags are the options of the HTML report of the scan and report commands
+type htmlFlags struct {
+	mode  *string
+	title *string
+	logo  *string
+}
+
+func addHTMLFlags(flags *flag.FlagSet) htmlFlags {
+	return htmlFlags{
+		mode:  flags.String("html-mode", htmlformatter.ModeSingle, "How the HTML report stores the scan data: 'single' (in the HTML file), 'gzip' (compressed in the HTML file, for very large scans) or 'split' (in a directory next to the HTML file)"),
+		title: flags.String("html-title", "", "Title of the HTML report (default \""+htmlformatter.DefaultTitle+"\")"),
+		logo:  flags.String("html-logo", "", "Image file shown next to the title of the HTML report"),
+	}
+}
+
+func (f htmlFlags) formatter() (*htmlformatter.HTMLFormatter, error) {
+	return htmlformatter.NewHTMLFormatterWithOptions(htmlformatter.Options{Mode: *f.mode, Title: *f.title, Logo: *f.logo})
+}
+
+// attachable reports whether the HTML report file can be sent on its own, e.g. by email
+func (f htmlFlags) attachable() bool {
+	return *f.mode != htmlformatter.ModeSplit
+}
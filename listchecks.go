@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+// runListChecks implements `pc list-checks`
+func runListChecks(args []string) {
+	flags := flag.NewFlagSet("list-checks", flag.ExitOnError)
+	cfg := flags.String("config", config.FindConfigFile(), "Path or https:// URL of the config file, used to show the configuration status")
+	offline := flags.Bool("offline", false, "Use the cached copy of a remote -config URL without fetching it")
+	profile := flags.String("profile", "", "Show the status for the [operation.<profile>] section instead of [operation.main]")
+	var overrides stringList
+	flags.Var(&overrides, "set", setFlagUsage)
+	jsonOutput := flags.Bool("json", false, "Output JSON format to stdout")
+	flags.Parse(args)
+
+	listChecksWithConfig(*cfg, *profile, overrides, *offline, *jsonOutput)
+}
+
+// listChecksWithConfig prints the check list, with configuration status if a config file is given
+func listChecksWithConfig(configPath, profile string, overrides []string, offline, asJSON bool) {
+	var listConfig *config.Config
+	if configPath != "" {
+		loaded, err := loadConfig(configPath, overrides, offline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if err := loaded.ApplyProfile(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		listConfig = loaded
+	}
+	printCheckList(listConfig, asJSON)
+}
+
+// CheckListing is one row of the list-checks output
+type CheckListing struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Severity    string   `json:"severity"`
+	Scopes      []string `json:"scopes"`
+	Status      string   `json:"status,omitempty"`
+}
+
+// printCheckList prints the built-in checks followed by the rules and plugins of the config.
+// Without a config the status column is omitted.
+func printCheckList(cfg *config.Config, asJSON bool) {
+	var listings []CheckListing
+	for _, check := range checks.Registry {
+		listing := CheckListing{
+			Name:        check.Name,
+			Description: check.Description,
+			Category:    check.Category,
+			Severity:    string(check.Severity),
+		}
+		for _, scope := range check.Scopes {
+			listing.Scopes = append(listing.Scopes, string(scope))
+		}
+		if cfg != nil {
+			listing.Severity = string(checks.ConfiguredSeverity(*cfg, check.Name))
+			listing.Status = check.ConfigStatus(*cfg)
+		}
+		listings = append(listings, listing)
+	}
+
+	if cfg != nil {
+		var ruleNames []string
+		for name := range cfg.Rules {
+			ruleNames = append(ruleNames, name)
+		}
+		sort.Strings(ruleNames)
+		for _, name := range ruleNames {
+			listings = append(listings, CheckListing{
+				Name:        name,
+				Description: cfg.Rules[name].Rule,
+				Category:    "rule",
+				Severity:    string(checks.ConfiguredSeverity(*cfg, name)),
+				Scopes:      []string{string(checks.ScopeFile)},
+				Status:      extensionStatus(*cfg, name),
+			})
+		}
+
+		var pluginNames []string
+		for name := range cfg.Plugins {
+			pluginNames = append(pluginNames, name)
+		}
+		sort.Strings(pluginNames)
+		for _, name := range pluginNames {
+			listings = append(listings, CheckListing{
+				Name:        name,
+				Description: strings.Join(cfg.Plugins[name].Command, " "),
+				Category:    "plugin",
+				Severity:    string(checks.ConfiguredSeverity(*cfg, name)),
+				Scopes:      []string{cfg.Plugins[name].Scope},
+				Status:      extensionStatus(*cfg, name),
+			})
+		}
+	}
+
+	if asJSON {
+		jsonBytes, _ := json.MarshalIndent(listings, "", "  ")
+		fmt.Println(string(jsonBytes))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if cfg != nil {
+		fmt.Fprintln(w, "NAME\tCATEGORY\tSEVERITY\tSCOPE\tSTATUS\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(w, "NAME\tCATEGORY\tSEVERITY\tSCOPE\tDESCRIPTION")
+	}
+	for _, l := range listings {
+		if cfg != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", l.Name, l.Category, l.Severity, strings.Join(l.Scopes, ","), l.Status, l.Description)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.Name, l.Category, l.Severity, strings.Join(l.Scopes, ","), l.Description)
+		}
+	}
+	w.Flush()
+}
+
+// extensionStatus is the status of a rule or plugin, which only run when configured
+func extensionStatus(cfg config.Config, name string) string {
+	if !cfg.IsCheckEnabled(name) {
+		return "disabled (profile)"
+	}
+	return "configured"
+}
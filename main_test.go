@@ -729,4 +729,59 @@ func TestJSONAndPlainConflict(t *testing.T) {
 	if !strings.Contains(outputStr, "--json and --plain cannot be used together") {
 		t.Errorf("Expected conflict error message, got: %s", outputStr)
 	}
-}
\ No newline at end of file
+}
+
+func TestIssueIDsIgnoreLocationSpelling(t *testing.T) {
+	if os.Getenv("CI") != "" {
+		t.Skip("Skipping issue ID test in CI environment")
+	}
+
+	tempDir := t.TempDir()
+	binaryPath := filepath.Join(tempDir, "pc")
+
+	// Build the binary
+	cmd := exec.Command("go", "build", "-o", binaryPath, ".")
+	_, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to build binary: %v", err)
+	}
+
+	configPath := createTestConfigFile(t, tempDir)
+	testDir := createTestFiles(t, tempDir)
+
+	// Scan the same folder once by its absolute path and once by a relative one
+	scanIDs := func(location string) []string {
+		cmd := exec.Command(binaryPath, "-config", configPath, "-location", location, "-json")
+		cmd.Dir = tempDir
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("Scanner failed for %s: %v\nOutput: %s", location, err, string(output))
+		}
+		var result struct {
+			DetailsCheckFocused []struct {
+				Issues []struct {
+					ID string `json:"id"`
+				} `json:"issues"`
+			} `json:"details_check_focused"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, string(output))
+		}
+		var ids []string
+		for _, check := range result.DetailsCheckFocused {
+			for _, issue := range check.Issues {
+				ids = append(ids, issue.ID)
+			}
+		}
+		return ids
+	}
+
+	absolute := scanIDs(testDir)
+	relative := scanIDs("./" + filepath.Base(testDir) + "/")
+	if len(absolute) == 0 {
+		t.Fatal("Expected findings in the test files")
+	}
+	if strings.Join(absolute, ",") != strings.Join(relative, ",") {
+		t.Errorf("IDs depend on the spelling of the location:\nabsolute: %v\nrelative: %v", absolute, relative)
+	}
+}
@@ -0,0 +1,188 @@
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// DefaultPath is the baseline used when neither -baseline nor 'baseline' in the config is set
+const DefaultPath = "pc-baseline.json"
+
+// Statuses of triaged findings
+const (
+	// StatusAccepted marks a finding that is real but accepted for the package
+	StatusAccepted = "accepted"
+	// StatusFalsePositive marks a finding that is wrong
+	StatusFalsePositive = "false_positive"
+)
+
+// Entry is a triaged finding. Findings are identified by their check, file and message, so an
+// entry still matches when the line of the finding moves. The path of the file is relative to
+// the scanned folder, so it also matches when the folder is scanned by another path. Entries
+// written by hand may name only the stable ID of the finding, as reported in the id field of
+// the JSON report.
+type Entry struct {
+	ID          string `json:"id,omitempty"` // Stable ID of the finding, see structs.IssueID
+	Checkname   string `json:"checkname,omitempty"`
+	Path        string `json:"path,omitempty"` // Relative to the scanned folder, empty for findings of the whole package
+	ArchiveName string `json:"archive_name,omitempty"`
+	Message     string `json:"message"`
+	Status      string `json:"status"` // StatusAccepted or StatusFalsePositive
+}
+
+// key identifies the finding of an entry, in whatever language its message was shown
+func (e Entry) key() string {
+	if e.Checkname == "" {
+		return "\x00" + e.ID
+	}
+	return e.Checkname + "\x00" + e.Path + "\x00" + e.ArchiveName + "\x00" + i18n.ToEnglish(e.Message)
+}
+
+// Counts are the numbers of findings hidden by a baseline
+type Counts struct {
+	Accepted       int
+	FalsePositives int
+}
+
+// Total returns the number of hidden findings
+func (c Counts) Total() int {
+	return c.Accepted + c.FalsePositives
+}
+
+// Baseline is the set of triaged findings that scans hide
+type Baseline struct {
+	entries map[string]Entry
+	ids     map[string]string // Stable IDs of the findings -> status
+}
+
+// file is the JSON layout of a baseline file
+type file struct {
+	Entries []Entry `json:"entries"`
+}
+
+// New creates an empty baseline
+func New() *Baseline {
+	return &Baseline{entries: make(map[string]Entry), ids: make(map[string]string)}
+}
+
+// Load reads a baseline file; a missing file is an empty baseline
+func Load(path string) (*Baseline, error) {
+	b := New()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var contents file
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline '%s': %w", path, err)
+	}
+	for _, entry := range contents.Entries {
+		if entry.Status != StatusAccepted && entry.Status != StatusFalsePositive {
+			return nil, fmt.Errorf("baseline '%s': unknown status '%s' of %s finding, expected %s or %s", path, entry.Status, entry.Checkname, StatusAccepted, StatusFalsePositive)
+		}
+		if entry.Checkname == "" && entry.ID == "" {
+			return nil, fmt.Errorf("baseline '%s': entry without checkname or id", path)
+		}
+		b.Mark("", entry)
+	}
+	return b, nil
+}
+
+// Save writes the baseline to path, sorted so that changes diff well
+func (b *Baseline) Save(path string) error {
+	contents := file{Entries: b.Entries()}
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create baseline directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the triaged findings sorted by check, path, archive and message
+func (b *Baseline) Entries() []Entry {
+	entries := make([]Entry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key() < entries[j].key() })
+	return entries
+}
+
+// Status returns the status of a finding in the package scanned at root, empty if it is not
+// triaged or b is nil. Entries with the path as it was collected, as older baselines hold
+// them, match as well.
+func (b *Baseline) Status(root, checkname, path, archiveName, message string) string {
+	if b == nil {
+		return ""
+	}
+	entry := Entry{Checkname: checkname, Path: structs.RelativePath(root, path), ArchiveName: archiveName, Message: message}
+	if status := b.entries[entry.key()].Status; status != "" || entry.Path == path {
+		return status
+	}
+	entry.Path = path
+	return b.entries[entry.key()].Status
+}
+
+// Mark sets the status of the finding of entry in the package scanned at root, storing its path
+// relative to root; an empty status removes it from the baseline
+func (b *Baseline) Mark(root string, entry Entry) {
+	collected := entry
+	entry.Path = structs.RelativePath(root, entry.Path)
+	if entry.Status == "" {
+		delete(b.entries, entry.key())
+		delete(b.entries, collected.key())
+		delete(b.ids, entry.ID)
+		return
+	}
+	b.entries[entry.key()] = entry
+	if entry.ID != "" {
+		b.ids[entry.ID] = entry.Status
+	}
+}
+
+// Filter returns the messages of the package scanned at root that are not in the baseline and
+// counts the hidden ones. A nil baseline hides nothing.
+func (b *Baseline) Filter(messages []structs.Message, root string) ([]structs.Message, Counts) {
+	var counts Counts
+	if b == nil || len(b.entries) == 0 {
+		return messages, counts
+	}
+	kept := make([]structs.Message, 0, len(messages))
+	for _, msg := range messages {
+		path, archiveName := "", ""
+		if file, isFile := msg.Source.(structs.File); isFile {
+			path, archiveName = file.Path, file.ArchiveName
+		}
+		status := b.Status(root, msg.TestName, path, archiveName, msg.Content)
+		if status == "" {
+			status = b.ids[msg.ID(root)]
+		}
+		switch status {
+		case StatusAccepted:
+			counts.Accepted++
+		case StatusFalsePositive:
+			counts.FalsePositives++
+		default:
+			kept = append(kept, msg)
+		}
+	}
+	return kept, counts
+}
@@ -0,0 +1,156 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	b, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected a missing baseline to be empty, got %v", err)
+	}
+	if len(b.Entries()) != 0 {
+		t.Errorf("Expected no entries, got %v", b.Entries())
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "baseline.json")
+	b := New()
+	b.Mark("", Entry{Checkname: "IsFreeOfKeywords", Path: "/data/b.txt", Message: "secret", Status: StatusFalsePositive})
+	b.Mark("", Entry{Checkname: "HasNoWhiteSpace", Path: "/data/a b.txt", Message: "spaces", Status: StatusAccepted})
+	b.Mark("", Entry{Checkname: "HasReadme", Message: "no readme", Status: StatusAccepted})
+	b.Mark("", Entry{Checkname: "HasReadme", Message: "no readme"})
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	entries := loaded.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	if entries[0].Checkname != "HasNoWhiteSpace" || entries[1].Checkname != "IsFreeOfKeywords" {
+		t.Errorf("Expected entries sorted by check, got %v", entries)
+	}
+	if status := loaded.Status("", "IsFreeOfKeywords", "/data/b.txt", "", "secret"); status != StatusFalsePositive {
+		t.Errorf("Expected %s, got %q", StatusFalsePositive, status)
+	}
+	if status := loaded.Status("", "HasReadme", "", "", "no readme"); status != "" {
+		t.Errorf("Expected the removed entry to be untriaged, got %q", status)
+	}
+}
+
+func TestLoadUnknownStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	content := `{"entries": [{"checkname": "HasReadme", "message": "no readme", "status": "ignored"}]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unknown status 'ignored'") {
+		t.Errorf("Expected an unknown status error, got %v", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	file := structs.File{Name: "a.txt", Path: "/data/a.txt"}
+	archived := structs.File{Name: "a.txt", Path: "a.txt", ArchiveName: "data.zip"}
+	messages := []structs.Message{
+		{TestName: "IsFreeOfKeywords", Content: "secret", Source: file},
+		{TestName: "IsFreeOfKeywords", Content: "secret", Source: archived},
+		{TestName: "IsFreeOfKeywords", Content: "password", Source: file},
+		{TestName: "HasReadme", Content: "no readme", Source: structs.Repository{}},
+	}
+
+	b := New()
+	b.Mark("", Entry{Checkname: "IsFreeOfKeywords", Path: "/data/a.txt", Message: "secret", Status: StatusAccepted})
+	b.Mark("", Entry{Checkname: "HasReadme", Message: "no readme", Status: StatusFalsePositive})
+
+	kept, counts := b.Filter(messages, "")
+	if len(kept) != 2 || kept[0].Source != archived || kept[1].Content != "password" {
+		t.Errorf("Expected the archived and password findings to be kept, got %v", kept)
+	}
+	if counts != (Counts{Accepted: 1, FalsePositives: 1}) || counts.Total() != 2 {
+		t.Errorf("Expected 1 accepted and 1 false positive, got %+v", counts)
+	}
+}
+
+func TestFilterByID(t *testing.T) {
+	file := structs.File{Name: "a.txt", Path: "/data/a.txt"}
+	secret := structs.Message{TestName: "IsFreeOfKeywords", Content: "secret", Source: file, Line: 4}
+	password := structs.Message{TestName: "IsFreeOfKeywords", Content: "password", Source: file}
+
+	// A suppression file written by hand names only the IDs of the findings
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	content := `{"entries": [{"id": "` + secret.ID("") + `", "status": "accepted"}, {"id": "0123456789abcdef", "status": "false_positive"}]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(b.Entries()) != 2 {
+		t.Errorf("Expected 2 entries, got %v", b.Entries())
+	}
+
+	kept, counts := b.Filter([]structs.Message{secret, password}, "")
+	if len(kept) != 1 || kept[0].Content != "password" || counts != (Counts{Accepted: 1}) {
+		t.Errorf("Expected the finding to be hidden by its ID, got %v and %+v", kept, counts)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"entries": [{"status": "accepted"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "without checkname or id") {
+		t.Errorf("Expected an error for an entry naming no finding, got %v", err)
+	}
+}
+
+func TestStatusAcrossLanguages(t *testing.T) {
+	b := New()
+	b.Mark("", Entry{Checkname: "HasReadMe", Message: "Das Repository enthält keine ReadMe-Datei.", Status: StatusAccepted})
+	if status := b.Status("", "HasReadMe", "", "", "No ReadMe file in repository."); status != StatusAccepted {
+		t.Errorf("Expected a finding triaged in German to be recognized in English, got %q", status)
+	}
+}
+
+func TestRelativePaths(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Triaged in a scan of "data", the path is stored relative to the scanned folder
+	b := New()
+	b.Mark("data", Entry{Checkname: "IsFreeOfKeywords", Path: filepath.Join("data", "raw", "a.txt"), Message: "secret", Status: StatusAccepted})
+	if entries := b.Entries(); len(entries) != 1 || entries[0].Path != "raw/a.txt" {
+		t.Fatalf("Expected the path relative to the scanned folder, got %v", entries)
+	}
+
+	// The finding is hidden in a scan of the same folder by its absolute path
+	root := filepath.Join(wd, "data")
+	message := structs.Message{TestName: "IsFreeOfKeywords", Content: "secret", Source: structs.File{Name: "a.txt", Path: filepath.Join(root, "raw", "a.txt")}}
+	if kept, counts := b.Filter([]structs.Message{message}, root); len(kept) != 0 || counts.Accepted != 1 {
+		t.Errorf("Expected the finding to be hidden in a scan by another path, got %v and %+v", kept, counts)
+	}
+
+	// Entries with the path as collected, as older baselines hold them, still match and can be removed
+	legacy := New()
+	legacy.Mark("", Entry{Checkname: "IsFreeOfKeywords", Path: filepath.Join(root, "raw", "a.txt"), Message: "secret", Status: StatusFalsePositive})
+	if status := legacy.Status(root, "IsFreeOfKeywords", filepath.Join(root, "raw", "a.txt"), "", "secret"); status != StatusFalsePositive {
+		t.Errorf("Expected an entry with the collected path to match, got %q", status)
+	}
+	legacy.Mark(root, Entry{Checkname: "IsFreeOfKeywords", Path: filepath.Join(root, "raw", "a.txt"), Message: "secret"})
+	if entries := legacy.Entries(); len(entries) != 0 {
+		t.Errorf("Expected the entry to be removed, got %v", entries)
+	}
+}
@@ -0,0 +1,88 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// pathCharacters are the characters of an absolute path up to the next space, quote, bracket,
+// colon or separator of a list
+const pathCharacters = "[^\\s\"'`<>|*?,;:()\\[\\]{}]"
+
+// separator separates the folders of Windows paths, also written with / or escaped backslashes
+const separator = `(?:\\{1,2}|/)`
+
+// absolutePathPatterns find Windows drive paths (C:\Users\name; lower case drive letters only
+// with \Users, as they are common in escaped strings such as "a:\n"), POSIX home paths
+// (/home/name, /Users/name) and UNC paths (\\server\share)
+var absolutePathPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:[A-Z]:` + separator + `|[a-z]:` + separator + `(?i:users)` + separator + `)` + pathCharacters + `+`),
+	regexp.MustCompile(`\B/(?:home|Users)/` + pathCharacters + `+`),
+	regexp.MustCompile(`\B\\{2}[A-Za-z0-9][A-Za-z0-9._$-]+\\{1,2}` + pathCharacters + `+`),
+}
+
+// homeFolders are the folders holding the home folders of the users
+var homeFolders = []string{"users", "home"}
+
+// placeholderUsers are user names of examples and shared folders, which disclose no one
+var placeholderUsers = []string{"user", "username", "user_name", "yourname", "your_name", "your-name", "name", "me", "you", "someone", "example", "xxx", "foo", "shared", "public", "default", "guest"}
+
+// pathUser returns the user whose home folder the path is in, "" if it is in none
+func pathUser(path string) string {
+	parts := strings.FieldsFunc(path, func(r rune) bool { return r == '\\' || r == '/' })
+	for i, part := range parts[:max(0, len(parts)-1)] {
+		for _, folder := range homeFolders {
+			if strings.EqualFold(part, folder) {
+				return parts[i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// isPlaceholderUser reports whether the user name is a placeholder or a variable such as $USER
+func isPlaceholderUser(user string) bool {
+	if strings.ContainsAny(user[:1], "$%<~") {
+		return true
+	}
+	for _, placeholder := range placeholderUsers {
+		if strings.EqualFold(user, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// absolutePathRule finds the absolute paths and names the users they disclose. The redactor of
+// the scan hides the users from the reports.
+func absolutePathRule(cfg config.Config) keywordRule {
+	return keywordRule{
+		Patterns: absolutePathPatterns,
+		Disjoint: true,
+		Skip: func(path string) bool {
+			user := pathUser(path)
+			return user != "" && isPlaceholderUser(user)
+		},
+		Describe: func(path string) string {
+			if user := pathUser(path); user != "" {
+				cfg.Redactor().AddUsers(user)
+				return "Absolute path '" + path + "' discloses the user name '" + user + "'"
+			}
+			return "Absolute path '" + path + "'"
+		},
+	}
+}
+
+// File contents contain no absolute paths, which only work on the computer of the author and may
+// disclose user names
+func IsFreeOfAbsolutePaths(file structs.File, config config.Config) []structs.Message {
+	return findInContent(file, config, []keywordRule{absolutePathRule(config)})
+}
+
+// Files inside archives contain no absolute paths
+func IsArchiveFreeOfAbsolutePaths(file structs.File, config config.Config) []structs.Message {
+	return findInArchive(file, config, "IsFreeOfAbsolutePaths", []keywordRule{absolutePathRule(config)})
+}
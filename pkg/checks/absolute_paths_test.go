@@ -0,0 +1,150 @@
+package checks
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func pathsConfig() config.Config {
+	return config.Config{General: &config.GeneralConfig{
+		MaxContentScanFileSize: 1024 * 1024,
+		MaxArchiveFileSize:     1024 * 1024,
+		MaxTotalArchiveMemory:  10 * 1024 * 1024,
+	}}
+}
+
+func TestIsFreeOfAbsolutePaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"No paths", "data/raw/table.csv and ./results\n", nil},
+		{
+			"Windows user folder",
+			`read.csv("C:\Users\jdoe\Desktop\data.csv")`,
+			[]string{`Absolute path 'C:\Users\jdoe\Desktop\data.csv' discloses the user name 'jdoe'`},
+		},
+		{
+			"Escaped backslashes and lower case drive",
+			`path = "c:\\users\\jdoe\\data"; print("a:\n")`,
+			[]string{`Absolute path 'c:\\users\\jdoe\\data' discloses the user name 'jdoe'`},
+		},
+		{
+			"Windows drive without user",
+			"setwd('D:/projects/lake')\n",
+			[]string{"Absolute path 'D:/projects/lake'"},
+		},
+		{
+			"POSIX home folders",
+			"cd /home/alice/analysis\nopen('/Users/bob/data.nc')\n",
+			[]string{
+				"Absolute path '/home/alice/analysis' discloses the user name 'alice'",
+				"Absolute path '/Users/bob/data.nc' discloses the user name 'bob'",
+			},
+		},
+		{
+			"Windows path with forward slashes is reported once",
+			"C:/Users/carol/x.txt\n",
+			[]string{"Absolute path 'C:/Users/carol/x.txt' discloses the user name 'carol'"},
+		},
+		{
+			"UNC path",
+			`copy \\fileserver\projects\lake\data.csv .`,
+			[]string{`Absolute path '\\fileserver\projects\lake\data.csv'`},
+		},
+		{
+			"UNC path to a home folder",
+			`\\fs01\home\dave\notes.txt`,
+			[]string{`Absolute path '\\fs01\home\dave\notes.txt' discloses the user name 'dave'`},
+		},
+		{
+			"Placeholders and URLs",
+			"/home/user/project\n/home/$USER/data\nC:\\Users\\Public\\x\nhttps://example.com/home/alice\n",
+			nil,
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var contents []string
+			for _, message := range IsFreeOfAbsolutePaths(structs.File{Path: path, Name: filepath.Base(path)}, pathsConfig()) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+}
+
+func TestIsArchiveFreeOfAbsolutePaths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scripts.zip")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(file)
+	for name, content := range map[string]string{
+		"scripts/run.R":   "setwd('/home/erin/lake')\n",
+		"scripts/info.md": "No paths here.\n",
+	} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	writer.Close()
+	file.Close()
+
+	archive := structs.File{Path: path, Name: "scripts.zip", IsArchive: true}
+	messages := IsArchiveFreeOfAbsolutePaths(archive, pathsConfig())
+	if len(messages) != 1 || messages[0].Content != "Absolute path '/home/erin/lake' discloses the user name 'erin'" {
+		t.Fatalf("expected the path in run.R, got %v", messages)
+	}
+	if source := messages[0].Source.(structs.File); source.Name != "scripts/run.R" || source.ArchiveName != "scripts.zip" || messages[0].Line != 1 {
+		t.Errorf("unexpected source %+v on line %d", source, messages[0].Line)
+	}
+}
+
+func TestIsFreeOfAbsolutePathsRedactedReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.sh")
+	if err := os.WriteFile(path, []byte("password=hunter2 cd /home/alice/lake\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	redactor := &output.Redactor{}
+	cfg := pathsConfig().WithRedactor(redactor)
+	cfg.General.Redact = true
+	cfg.Tests = map[string]*config.TestConfig{"IsFreeOfKeywords": {KeywordArguments: []map[string]interface{}{
+		{"keywords": []string{"password"}, "patterns": []string{`hunter\d`}, "info": "Credentials detected"},
+	}}}
+
+	messages := IsFreeOfAbsolutePaths(structs.File{Path: path, Name: "run.sh"}, cfg)
+	if len(messages) != 1 {
+		t.Fatalf("expected the path, got %v", messages)
+	}
+	// The secrets next to the path are redacted like in the findings of IsFreeOfKeywords
+	if snippet := messages[0].Snippet; snippet != "pa****rd=h****2 cd »/home/alice/lake«" {
+		t.Errorf("expected the secrets in the snippet to be redacted, got %q", snippet)
+	}
+	// The user name found is hidden wherever the report mentions it
+	if text := redactor.Text(messages[0].Content); text != "Absolute path '<redacted>/lake' discloses the user name '<user>'" {
+		t.Errorf("expected the user name to be redacted, got %q", text)
+	}
+	if text := redactor.Text("Owner: alice"); text != "Owner: <user>" {
+		t.Errorf("expected the user name to be redacted in other texts, got %q", text)
+	}
+}
@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// compressedSuffixes are formats whose data is already compressed, so archiving them saves little
+var compressedSuffixes = map[string]bool{
+	// Archives
+	".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".7z": true, ".rar": true, ".zst": true,
+	// Images
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true, ".heic": true,
+	// Video and audio
+	".mp4": true, ".mov": true, ".avi": true, ".mkv": true, ".webm": true,
+	".mp3": true, ".aac": true, ".m4a": true, ".ogg": true, ".opus": true, ".flac": true,
+}
+
+// maxNamedExamples is the number of files named in a message about many files
+const maxNamedExamples = 3
+
+// Archives hold more than one file and do not compress already compressed files again
+func HasNoRedundantCompression(file structs.File, config config.Config) []structs.Message {
+	fileList, err := readers.ReadArchiveFileList(file)
+	if err != nil {
+		// Unreadable archives are reported by the archive file list checks
+		return nil
+	}
+	var members []string
+	for _, member := range fileList {
+		if !strings.HasSuffix(member.Name, "/") {
+			members = append(members, member.Name)
+		}
+	}
+
+	if len(members) == 1 {
+		return []structs.Message{{
+			Content: fmt.Sprintf("The archive contains only '%s', consider publishing the file itself.", members[0]),
+			Source:  file,
+		}}
+	}
+
+	// A tar archive bundles the files without compressing them
+	if strings.HasSuffix(file.Name, ".tar") {
+		return nil
+	}
+	var compressed []string
+	for _, member := range members {
+		if compressedSuffixes[strings.ToLower(path.Ext(member))] {
+			compressed = append(compressed, member)
+		}
+	}
+	if len(compressed) == 0 {
+		return nil
+	}
+	if len(compressed) == 1 {
+		return []structs.Message{{
+			Content: fmt.Sprintf("The archive contains the already compressed file '%s', which archiving does not make smaller.", compressed[0]),
+			Source:  file,
+		}}
+	}
+	examples := "'" + strings.Join(compressed[:min(len(compressed), maxNamedExamples)], "', '") + "'"
+	if len(compressed) > maxNamedExamples {
+		examples += ", ..."
+	}
+	return []structs.Message{{
+		Content: fmt.Sprintf("The archive contains %d already compressed files (%s), which archiving does not make smaller, consider publishing them without the archive.", len(compressed), examples),
+		Source:  file,
+	}}
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// writeZip creates a zip archive with empty members of the given names in dir
+func writeZip(t *testing.T, dir string, name string, members ...string) structs.File {
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	writer := zip.NewWriter(file)
+	for _, member := range members {
+		if _, err := writer.Create(member); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name, IsArchive: true}
+}
+
+func TestHasNoRedundantCompression(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected []string
+	}{
+		{"Uncompressed files", writeZip(t, dir, "tables.zip", "data/", "data/a.csv", "data/b.csv"), nil},
+		{
+			"Single file",
+			writeZip(t, dir, "single.zip", "data/", "data/a.csv"),
+			[]string{"The archive contains only 'data/a.csv', consider publishing the file itself."},
+		},
+		{
+			"One compressed file",
+			writeZip(t, dir, "one.zip", "a.csv", "photo.JPG"),
+			[]string{"The archive contains the already compressed file 'photo.JPG', which archiving does not make smaller."},
+		},
+		{
+			"Many compressed files",
+			writeZip(t, dir, "media.zip", "readme.txt", "a.jpg", "b.mp4", "c.zip", "d.gz"),
+			[]string{"The archive contains 4 already compressed files ('a.jpg', 'b.mp4', 'c.zip', ...), which archiving does not make smaller, consider publishing them without the archive."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range HasNoRedundantCompression(tt.file, config.Config{}) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+
+	// Tar archives do not compress, so compressed files in them are fine
+	path := filepath.Join(dir, "photos.tar")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := tar.NewWriter(file)
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg})
+	}
+	writer.Close()
+	file.Close()
+	if messages := HasNoRedundantCompression(structs.File{Path: path, Name: "photos.tar", IsArchive: true}, config.Config{}); messages != nil {
+		t.Errorf("expected no messages for a tar archive, got %v", messages)
+	}
+}
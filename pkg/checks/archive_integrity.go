@@ -0,0 +1,33 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// Archives can be opened and all their members read without errors, so depositors are asked to
+// upload corrupt or truncated archives again
+func IsArchiveIntact(file structs.File, config config.Config) []structs.Message {
+	err := readers.VerifyArchive(config.Context(), file)
+	if err == nil {
+		return nil
+	}
+	// The archive file itself could not be read, which says nothing about the upload
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		skipFile(config, file, output.SkipReadError, "Error reading the archive: %v", err)
+		return nil
+	}
+	// The other checks cannot open it either, this message tells why
+	config.SkippedFiles().Reported(file.Path)
+	return []structs.Message{{
+		Content: fmt.Sprintf("The archive is corrupt or truncated, please upload it again: %s.", err),
+		Source:  file,
+	}}
+}
@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/hex"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestIsArchiveIntact(t *testing.T) {
+	skippedFiles := output.NewSkippedFiles()
+	cfg := config.Config{}.WithSkippedFiles(skippedFiles)
+	dir := t.TempDir()
+	intact := writeZip(t, dir, "tables.zip", "data/", "data/a.csv", "data/b.csv")
+	if messages := IsArchiveIntact(intact, cfg); messages != nil {
+		t.Errorf("expected no messages for an intact archive, got %v", messages)
+	}
+
+	data, err := os.ReadFile(intact.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := structs.File{Path: filepath.Join(dir, "truncated.zip"), Name: "truncated.zip", IsArchive: true}
+	if err := os.WriteFile(truncated.Path, data[:len(data)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	messages := IsArchiveIntact(truncated, cfg)
+	if len(messages) != 1 || !strings.HasPrefix(messages[0].Content, "The archive is corrupt or truncated, please upload it again: zip: not a valid zip file") {
+		t.Fatalf("expected the truncated archive to be reported, got %v", messages)
+	}
+	if source := messages[0].Source.GetValue(); len(source) != 1 || source[0].Name != "truncated.zip" {
+		t.Errorf("expected the message to be attached to the archive, got %v", source)
+	}
+
+	missing := structs.File{Path: filepath.Join(dir, "missing.zip"), Name: "missing.zip", IsArchive: true}
+	if messages := IsArchiveIntact(missing, cfg); messages != nil {
+		t.Errorf("expected an unreadable file not to be reported as corrupt, got %v", messages)
+	}
+	skipped := skippedFiles.Files()
+	if len(skipped) != 1 || skipped[0].Filename != "missing.zip" || skipped[0].Code != output.SkipReadError {
+		t.Errorf("expected the unreadable archive to be skipped, got %+v", skipped)
+	}
+}
+
+func TestIsArchiveIntactSkipped(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	corrupt := structs.File{Path: filepath.Join(t.TempDir(), "corrupt.zip"), Name: "corrupt.zip", IsArchive: true}
+	if err := os.WriteFile(corrupt.Path, []byte("PK\x03\x04 not a zip file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without IsArchiveIntact the content checks skip it
+	skippedFiles := output.NewSkippedFiles()
+	scan := cfg.WithSkippedFiles(skippedFiles)
+	IsArchiveFreeOfKeywords(corrupt, scan)
+	if skipped := skippedFiles.Files(); len(skipped) != 1 || skipped[0].Code != output.SkipReadError {
+		t.Errorf("expected the corrupt archive to be skipped, got %+v", skipped)
+	}
+
+	// Reported by IsArchiveIntact it is not skipped at all
+	if messages := IsArchiveIntact(corrupt, scan); len(messages) != 1 {
+		t.Fatalf("expected the corrupt archive to be reported, got %v", messages)
+	}
+	IsArchiveFreeOfKeywords(corrupt, scan)
+	if skipped := skippedFiles.Files(); len(skipped) != 0 {
+		t.Errorf("expected no skipped files for a reported archive, got %+v", skipped)
+	}
+}
+
+func TestIsArchiveIntactTruncatedTarGz(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Text that does not compress well, so the cut falls into the member
+	random := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(random)
+	content := []byte(hex.EncodeToString(random))
+
+	archive := structs.File{Path: filepath.Join(t.TempDir(), "upload.tar.gz"), Name: "upload.tar.gz", IsArchive: true}
+	out, err := os.Create(archive.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "./big.txt", Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(archive.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(archive.Path, info.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+
+	skippedFiles := output.NewSkippedFiles()
+	scan := cfg.WithSkippedFiles(skippedFiles)
+	if messages := IsArchiveIntact(archive, scan); len(messages) != 1 {
+		t.Fatalf("expected the truncated archive to be reported, got %v", messages)
+	}
+	IsArchiveFreeOfKeywords(archive, scan)
+	if skipped := skippedFiles.Files(); len(skipped) != 0 {
+		t.Errorf("expected no skipped members of a reported archive, got %+v", skipped)
+	}
+}
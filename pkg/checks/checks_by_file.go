@@ -1,21 +1,30 @@
 package checks
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/filetype"
 	"github.com/eawag-rdm/pc/pkg/optimization"
 	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
 	"github.com/eawag-rdm/pc/pkg/readers"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
+// logger logs the files the checks cannot read
+var logger = output.GlobalLogger.Component("checks")
+
 var invalidFileNameChars [256]bool
 
 func init() {
@@ -48,11 +57,60 @@ func HasFileNameSpecialChars(file structs.File, cfg config.Config) []structs.Mes
 	return []structs.Message{}
 }
 
+// defaultMaxPathLength is MAX_PATH of Windows, longer paths often fail to extract there
+const defaultMaxPathLength = 260
+
+// maxPathLength returns the limit of the full path check of IsFileNameTooLong, 0 if it is not enabled
+func maxPathLength(config config.Config) int64 {
+	test := config.Tests["IsFileNameTooLong"]
+	if test == nil {
+		return 0
+	}
+	for _, argumentSet := range test.KeywordArguments {
+		if enabled, _ := argumentSet["full_path"].(bool); enabled {
+			if limit, ok := argumentSet["max_path_length"].(int64); ok {
+				return limit
+			}
+			return defaultMaxPathLength
+		}
+	}
+	return 0
+}
+
+// packagePath returns the path of the file relative to the package root if it is known, else its
+// name. Files inside archives are placed in a folder named after the archive, as unpacking does.
+func packagePath(file structs.File, config config.Config) string {
+	location := file.Name
+	if file.ArchiveName != "" {
+		location = file.ArchiveName
+	}
+	if root := config.PackageRoot(); root != "" {
+		if relative, err := filepath.Rel(root, file.Path); err == nil && !strings.HasPrefix(relative, "..") {
+			location = filepath.ToSlash(relative)
+		}
+	}
+	if file.ArchiveName == "" {
+		return location
+	}
+	return strings.TrimSuffix(location, path.Ext(location)) + "/" + strings.TrimSuffix(file.Name, "/")
+}
+
 func IsFileNameTooLong(file structs.File, config config.Config) []structs.Message {
-	if len(file.Name) > 64 {
-		return []structs.Message{{Content: "File name is too long.", Source: file}}
+	messages := []structs.Message{}
+	// Files inside archives are named by their path, only its length is checked
+	if file.ArchiveName == "" && len(file.Name) > 64 {
+		messages = append(messages, structs.Message{Content: "File name is too long.", Source: file})
+	}
+	if limit := maxPathLength(config); limit > 0 {
+		location := packagePath(file, config)
+		if length := utf8.RuneCountInString(location); int64(length) > limit {
+			messages = append(messages, structs.Message{
+				Content: fmt.Sprintf("Path '%s' is %d characters long, more than %d, unpacking the package on Windows may fail.", location, length, limit),
+				Source:  file,
+			})
+		}
 	}
-	return []structs.Message{}
+	return messages
 }
 
 // streamingReadFile reads a file in chunks and applies pattern matching
@@ -79,7 +137,7 @@ func streamingReadFileList(filePath string, patternList []string) ([]string, err
 		return []string{}, nil
 	}
 
-	matcher := optimization.GetMatcher(patternList)
+	matcher := optimization.GetMatcher(nil, patternList)
 
 	// For small files (under 1MB), read normally
 	if fileInfo.Size() < chunkSize {
@@ -166,79 +224,38 @@ func HasNoWhiteSpace(file structs.File, config config.Config) []structs.Message
 	return []structs.Message{}
 }
 
-// Common text file extensions
-var textExtensions = map[string]bool{
-	".txt": true, ".log": true, ".md": true, ".csv": true, ".json": true,
-	".xml": true, ".html": true, ".css": true, ".js": true, ".py": true,
-	".go": true, ".java": true, ".cpp": true, ".c": true, ".h": true,
-	".sql": true, ".yml": true, ".yaml": true, ".toml": true, ".ini": true,
-	".conf": true, ".config": true, ".properties": true, ".sh": true,
-	".bat": true, ".ps1": true, ".rb": true, ".php": true, ".pl": true,
-}
-
-// isTextFile checks if a file is a text file using DetectContentType from the http package.
-// Enhanced to handle large files and improve detection accuracy.
+// isTextFile reports whether the file at path is text, see filetype.Detect
 func isTextFile(filePath string) (bool, error) {
-	// Check file extension first for common text types
-	ext := strings.ToLower(filepath.Ext(filePath))
-	if textExtensions[ext] {
-		return true, nil
-	}
+	return isTextContent(performance.NewFileContent(filePath, nil))
+}
 
-	// Open the file for reading
-	file, err := os.Open(filePath)
+// isTextContent is isTextFile for content shared by the checks of a file
+func isTextContent(content *performance.FileContent) (bool, error) {
+	sample, err := content.Sample()
 	if err != nil {
 		return false, err
 	}
-	defer file.Close()
-
-	// Read a larger sample for better detection
-	const sampleSize = 8192 // Increased from 512 to 8KB
-	buffer := make([]byte, sampleSize)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return false, err
-	}
-
-	if n == 0 {
-		return true, nil // Empty files are considered text
-	}
-
-	// Check for null bytes (common in binary files)
-	for i := 0; i < n; i++ {
-		if buffer[i] == 0 {
-			return false, nil // Binary file likely
-		}
-	}
-
-	// Use HTTP detection as secondary check
-	filetype := http.DetectContentType(buffer[:n])
-	if strings.HasPrefix(filetype, "text/") {
-		return true, nil
-	}
-
-	// Additional heuristic: check if most bytes are printable ASCII or common UTF-8
-	printableCount := 0
-	for i := 0; i < n; i++ {
-		b := buffer[i]
-		if (b >= 32 && b <= 126) || b == '\t' || b == '\n' || b == '\r' || b >= 128 {
-			printableCount++
-		}
-	}
-
-	// If more than 95% of sampled bytes are printable, consider it text
-	textRatio := float64(printableCount) / float64(n)
-	return textRatio >= 0.95, nil
+	return filetype.IsText(content.Path(), sample), nil
 }
 
 func IsArchiveFreeOfKeywords(file structs.File, config config.Config) []structs.Message {
+	return findInArchive(file, config, "IsFreeOfKeywords", keywordRules(config))
+}
+
+// findInArchive reports the matches of the rules in the files inside the archive, filtered by the
+// whitelist or blacklist of the [test.<testName>] section
+func findInArchive(file structs.File, config config.Config, testName string, rules []keywordRule) []structs.Message {
 	var messages []structs.Message
+	rules = hidingSecrets(config, rules)
 
 	// Check if the archive file itself exceeds the configured maximum size for content scanning
 	// This prevents conflicting behavior where archive is listed as "skipped" but contents still scanned
-	fileInfo, err := os.Stat(file.Path)
+	content, done := fileContent(file, config)
+	defer done()
+	fileInfo, err := content.Stat()
 	if err != nil {
-		output.GlobalLogger.Warning("Error getting file info '%s': %v", file.Path, err)
+		logger.Warning("Error getting file info '%s': %v", file.Path, err)
+		skipFile(config, file, output.SkipReadError, "Error getting file info: %v", err)
 		return messages
 	}
 
@@ -253,8 +270,10 @@ func IsArchiveFreeOfKeywords(file structs.File, config config.Config) []structs.
 		maxFileSize = 10 * 1024 * 1024 // Default to 10MB if not configured
 	}
 
-	whitelist := config.Tests["IsFreeOfKeywords"].Whitelist
-	blacklist := config.Tests["IsFreeOfKeywords"].Blacklist
+	var whitelist, blacklist []string
+	if test, ok := config.Tests[testName]; ok {
+		whitelist, blacklist = test.Whitelist, test.Blacklist
+	}
 
 	// Use configurable total memory limit
 	maxTotalMemory := config.General.MaxTotalArchiveMemory
@@ -263,6 +282,11 @@ func IsArchiveFreeOfKeywords(file structs.File, config config.Config) []structs.
 	}
 
 	archiveIterator := readers.InitArchiveIteratorWithMemoryLimit(file.Path, file.Name, maxFileSize, whitelist, blacklist, maxTotalMemory)
+	archiveIterator.SetMemoryBudget(config.MemoryBudget())
+	archiveIterator.SetPatternCache(config.PatternCache())
+	archiveIterator.SetSkippedFiles(config.SkippedFiles())
+	// Gives the memory of the unpacked members back to the scan
+	defer archiveIterator.Close()
 	if !archiveIterator.HasFilesToUnpack() {
 		return messages
 	}
@@ -270,17 +294,25 @@ func IsArchiveFreeOfKeywords(file structs.File, config config.Config) []structs.
 	// Get the archive's display name for consistent output
 	archiveDisplayName := file.GetDisplayName()
 
+	members := 0
 	for archiveIterator.HasNext() {
+		if config.Context().Err() != nil {
+			// Stop unpacking a cancelled scan and release the archive
+			archiveIterator.Close()
+			break
+		}
 
 		archiveIterator.Next()
 		fileName, fileContent, fileSize := archiveIterator.UnpackedFile()
+		members++
 
-		for _, argumentSet := range config.Tests["IsFreeOfKeywords"].KeywordArguments {
-			var keywordList = argumentSet["keywords"].([]string)
-			var info = argumentSet["info"].(string)
-			foundKeywordsStr := matchPatternsList(keywordList, fileContent)
+		// Every file in the archive has its own budget
+		budget := newFindingBudget(config)
+		start := len(messages)
+		for _, rule := range rules {
+			found := rule.find(fileContent, 1, budget)
 
-			if foundKeywordsStr != "" {
+			if len(found) > 0 {
 				// Create a File struct for the archived file with proper archive reference
 				archivedFile := structs.ToFileWithDisplay(
 					file.Path,         // path stays as archive path
@@ -290,96 +322,190 @@ func IsArchiveFreeOfKeywords(file structs.File, config config.Config) []structs.
 					"",                // suffix (auto-detected)
 					archiveDisplayName, // archive name reference
 				)
-				messages = append(messages, structs.Message{
-					Content: info + " '" + foundKeywordsStr + "'",
-					Source:  archivedFile,
-				})
+				for _, match := range found {
+					messages = append(messages, rule.message(archivedFile, match))
+				}
 			}
 		}
+		budget.apply(messages[start:])
+		config.ArchiveProgress()(archiveDisplayName, members, archiveIterator.MemberCount())
 
 	}
 	return messages
 }
 
 func IsFreeOfKeywords(file structs.File, config config.Config) []structs.Message {
+	return findInContent(file, config, keywordRules(config))
+}
+
+// findInContent reports the matches of the rules in the text of the file
+func findInContent(file structs.File, config config.Config, rules []keywordRule) []structs.Message {
 	var messages []structs.Message
+	rules = hidingSecrets(config, rules)
 
 	// Large file warning removed - processing continues without notification
 
+	// Only the footer of columnar files is read, whatever their size
+	if isColumnarFile(file.Name) {
+		return findInColumnarSchema(file, config, rules)
+	}
+
 	// Check file size limit for content scanning
-	fileInfo, err := os.Stat(file.Path)
+	content, done := fileContent(file, config)
+	defer done()
+	fileInfo, err := content.Stat()
 	if err != nil {
-		output.GlobalLogger.Warning("Error getting file info '%s': %v", file.Path, err)
+		logger.Warning("Error getting file info '%s': %v", file.Path, err)
+		skipFile(config, file, output.SkipReadError, "Error getting file info: %v", err)
+		return messages
+	}
+	// Folders collected with includeFolders have no content to check
+	if fileInfo.IsDir() {
 		return messages
 	}
 
+	// With scanStrategy = "sample" large text files are checked in samples, whatever their size
+	if sampled(config, fileInfo.Size()) {
+		if isText, err := isTextContent(content); err == nil && isText {
+			return findInSamples(file, config, fileInfo.Size(), rules)
+		}
+	}
+
 	// Check if file exceeds the configured maximum size for content scanning
 	if fileInfo.Size() > config.General.MaxContentScanFileSize {
-		output.GlobalLogger.Info("Skipping content scan of file: '%s' (path: '%s'). File size (%d bytes) exceeds maximum (%d bytes).",
-			file.Name, file.Path, fileInfo.Size(), config.General.MaxContentScanFileSize)
+		skipFile(config, file, output.SkipTooLarge, "File size (%d bytes) exceeds maximum (%d bytes).",
+			fileInfo.Size(), config.General.MaxContentScanFileSize)
 		return messages
 	}
 
-	isText, err := isTextFile(file.Path)
+	isText, err := isTextContent(content)
 	if err != nil {
+		skipFile(config, file, output.SkipReadError, "Error reading file: %v", err)
 		return messages
 	}
 
+	// Files read at once take their size from the memory budget of the scan, streamed files only
+	// hold a chunk. The content of text files reserves its memory when it is read.
+	// Notebooks are read at once to find the text of their cells.
+	streamed := isText && fileInfo.Size() > 1024*1024 && !isNotebook(file.Path)
+	if !isText && isOfficeFile(file.Path) {
+		if !config.MemoryBudget().Reserve(fileInfo.Size()) {
+			skipForMemory(file, config)
+			return messages
+		}
+		defer config.MemoryBudget().Release(fileInfo.Size())
+	}
+
+	budget := newFindingBudget(config)
 	if isText {
 		// Use streaming for files larger than 1MB (reduced threshold for better performance)
-		if fileInfo.Size() > 1024*1024 {
-			for _, argumentSet := range config.Tests["IsFreeOfKeywords"].KeywordArguments {
-				var keywordList = argumentSet["keywords"].([]string)
-				var info = argumentSet["info"].(string)
-
-				foundMatches, err := streamingReadFileList(file.Path, keywordList)
+		if streamed {
+			for _, rule := range rules {
+				foundMatches, err := rule.findInFile(file.Path, budget)
 				if err != nil {
-					output.GlobalLogger.Warning("Error streaming file '%s': %v", file.Path, err)
+					logger.Warning("Error streaming file '%s': %v", file.Path, err)
+					skipFile(config, file, output.SkipReadError, "Error streaming file: %v", err)
 					continue
 				}
 
 				for _, match := range foundMatches {
-					messages = append(messages, structs.Message{
-						Content: info + " '" + match + "'",
-						Source:  file,
-					})
+					messages = append(messages, rule.message(file, match))
 				}
 			}
 		} else {
 			// Use regular reading for smaller files
-			content, err := os.ReadFile(file.Path)
+			data, err := content.Bytes()
+			if errors.Is(err, performance.ErrMemoryBudget) {
+				skipForMemory(file, config)
+				return messages
+			}
 			if err != nil {
-				output.GlobalLogger.Warning("Error reading file '%s': %v", file.Path, err)
+				logger.Warning("Error reading file '%s': %v", file.Path, err)
+				skipFile(config, file, output.SkipReadError, "Error reading file: %v", err)
 				return messages
 			}
-			body := [][]byte{content}
-
-			for _, argumentSet := range config.Tests["IsFreeOfKeywords"].KeywordArguments {
-				var keywordList = argumentSet["keywords"].([]string)
-				var info = argumentSet["info"].(string)
-
-				ret := IsFreeOfKeywordsCoreList(file, keywordList, info, body, false)
-				if ret != nil {
-					messages = append(messages, ret...)
+			if isNotebook(file.Path) {
+				if cells, err := readers.ReadNotebook(data); err == nil {
+					return budget.apply(findInNotebook(file, cells, rules, budget))
 				}
+				// Notebooks that cannot be read are searched as text
+			}
+			body := [][]byte{data}
+
+			for _, rule := range rules {
+				messages = append(messages, rule.messages(file, body, false, budget)...)
 			}
 		}
 	} else {
 		// Handle binary files
-		body := tryReadBinary(file)
-		for _, argumentSet := range config.Tests["IsFreeOfKeywords"].KeywordArguments {
-			var keywordList = argumentSet["keywords"].([]string)
-			var info = argumentSet["info"].(string)
-
-			ret := IsFreeOfKeywordsCoreList(file, keywordList, info, body, true)
-			if ret != nil {
-				messages = append(messages, ret...)
+		body := tryReadBinary(file, config)
+		if isLegacyOfficeFile(file.Path) {
+			// The strings of legacy Office files have neither lines nor sheets or paragraphs
+			return budget.apply(findWithoutLocation(file, body, rules, budget))
+		}
+		for _, rule := range rules {
+			messages = append(messages, rule.messages(file, body, true, budget)...)
+		}
+	}
+	return budget.apply(messages)
+}
+
+// findWithoutLocation reports the matches of the rules in text extracted from a file, whose lines
+// are not those of the file
+func findWithoutLocation(file structs.File, body [][]byte, rules []keywordRule, budget *findingBudget) []structs.Message {
+	var messages []structs.Message
+	for _, rule := range rules {
+		for _, message := range rule.messages(file, body, false, budget) {
+			message.Line = 0
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// findInNotebook reports the matches of the rules in the source and outputs of the cells of a
+// notebook. Lines are counted within a cell, so only the cell is reported.
+func findInNotebook(file structs.File, cells []readers.NotebookCell, rules []keywordRule, budget *findingBudget) []structs.Message {
+	var messages []structs.Message
+	for _, rule := range rules {
+		for idx, cell := range cells {
+			for _, m := range rule.find(cell.Text(), 1, budget) {
+				message := rule.message(file, m)
+				message.Content += fmt.Sprintf(" in cell %d", idx+1)
+				message.Line = 0
+				messages = append(messages, message)
 			}
 		}
 	}
 	return messages
 }
 
+// fileContent returns the content shared by the checks of the file, or the content of the file
+// on its own when it is checked outside of a scan; done gives the memory of the latter back
+func fileContent(file structs.File, config config.Config) (content *performance.FileContent, done func()) {
+	if content := config.FileContent(); content != nil && content.Path() == file.Path {
+		return content, func() {}
+	}
+	content = performance.NewFileContent(file.Path, config.MemoryBudget())
+	return content, content.Close
+}
+
+// skipFile records that the contents of file were not checked and why
+func skipFile(config config.Config, file structs.File, code output.SkipCode, format string, args ...interface{}) {
+	config.SkippedFiles().Add(output.SkippedFile{
+		Filename:    file.Name,
+		Path:        file.Path,
+		ArchiveName: file.ArchiveName,
+		Code:        code,
+		Reason:      fmt.Sprintf(format, args...),
+	})
+}
+
+// skipForMemory reports a file skipped because its content does not fit into the memory budget
+func skipForMemory(file structs.File, config config.Config) {
+	skipFile(config, file, output.SkipMemoryLimit, "The memory budget of the scan (%d bytes) is exhausted.", config.MemoryBudget().Limit())
+}
+
 func IsFreeOfKeywordsCore(file structs.File, keywords string, info string, body [][]byte, isBinary bool) []structs.Message {
 	// Split patterns and delegate to optimized version
 	patternList := strings.Split(keywords, "|")
@@ -393,7 +519,7 @@ func IsFreeOfKeywordsCoreList(file structs.File, keywordList []string, info stri
 		foundKeywordsStr := matchPatternsList(keywordList, entry)
 		if foundKeywordsStr != "" {
 			if isBinary {
-				messages = append(messages, structs.Message{Content: info + " '" + foundKeywordsStr + "' in sheet/paragraph/table " + fmt.Sprintf("%d", idx), Source: file})
+				messages = append(messages, structs.Message{Content: info + " '" + foundKeywordsStr + "'" + partLocation(file, idx), Source: file})
 			} else {
 				messages = append(messages, structs.Message{Content: info + " '" + foundKeywordsStr + "'", Source: file})
 			}
@@ -409,7 +535,7 @@ func matchPatternsList(patternList []string, body []byte) string {
 	}
 
 	// Use fast matcher for pattern detection with original case preservation
-	matcher := optimization.GetMatcher(patternList)
+	matcher := optimization.GetMatcher(nil, patternList)
 	foundMatches := matcher.FindMatchesWithOriginalCase(body)
 
 	if len(foundMatches) > 0 {
@@ -433,23 +559,57 @@ func matchPatternsList(patternList []string, body []byte) string {
 	return ""
 }
 
-func tryReadBinary(file structs.File) [][]byte {
+// isOfficeFile reports whether the text of the file is read by an office reader
+func isOfficeFile(path string) bool {
+	return strings.HasSuffix(path, ".xlsx") || strings.HasSuffix(path, ".docx") || strings.HasSuffix(path, ".pptx") ||
+		isLegacyOfficeFile(path)
+}
+
+// isLegacyOfficeFile reports whether the file is a binary .xls or .doc file, whose strings are extracted
+func isLegacyOfficeFile(path string) bool {
+	return strings.HasSuffix(path, ".xls") || strings.HasSuffix(path, ".doc")
+}
+
+// isNotebook reports whether the file is a Jupyter notebook
+func isNotebook(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".ipynb")
+}
+
+func tryReadBinary(file structs.File, config config.Config) [][]byte {
 	if strings.HasSuffix(file.Path, ".xlsx") {
 		content, err := readers.ReadXLSXFile(file)
 		if err != nil {
-			output.GlobalLogger.Warning("Error reading XLSX file '%s': %v", file.Path, err)
+			logger.Warning("Error reading XLSX file '%s': %v", file.Path, err)
+			skipFile(config, file, output.SkipReadError, "Error reading XLSX file: %v", err)
 			return [][]byte{} // Return empty instead of panicking
 		}
 		return content
 	} else if strings.HasSuffix(file.Path, ".docx") {
 		content, err := readers.ReadDOCXFile(file)
 		if err != nil {
-			output.GlobalLogger.Warning("Error reading DOCX file '%s': %v", file.Path, err)
+			logger.Warning("Error reading DOCX file '%s': %v", file.Path, err)
+			skipFile(config, file, output.SkipReadError, "Error reading DOCX file: %v", err)
 			return [][]byte{} // Return empty instead of panicking
 		}
 		return content
+	} else if strings.HasSuffix(file.Path, ".pptx") {
+		content, err := readers.ReadPPTXFile(file)
+		if err != nil {
+			logger.Warning("Error reading PPTX file '%s': %v", file.Path, err)
+			skipFile(config, file, output.SkipReadError, "Error reading PPTX file: %v", err)
+			return [][]byte{}
+		}
+		return content
+	} else if isLegacyOfficeFile(file.Path) {
+		content, err := readers.ReadLegacyOfficeFile(file)
+		if err != nil {
+			logger.Warning("Error reading legacy Office file '%s': %v", file.Path, err)
+			skipFile(config, file, output.SkipReadError, "Error reading legacy Office file: %v", err)
+			return [][]byte{}
+		}
+		return content
 	} else if !readers.IsSupportedArchive(file.Name) {
-		output.GlobalLogger.Info("Not checking contents of file: '%s' (path: '%s'). The file seems to be binary.", file.Name, file.Path)
+		skipFile(config, file, output.SkipBinary, "The file seems to be binary.")
 	}
 	return [][]byte{}
 }
@@ -473,7 +633,7 @@ func IsValidNameCore(file structs.File, invalidFileNames []string) []structs.Mes
 	name = file.Name
 	// Check if the file name is a path and if it is, split it
 	if strings.Contains(file.Name, "/") || strings.Contains(file.Name, "\\") {
-		folders = strings.Split(file.Name, "/")
+		folders = splitName(file.Name)
 		name = folders[len(folders)-1]
 		// remove the file name from the path
 		folders = folders[:len(folders)-1]
@@ -496,3 +656,173 @@ func IsValidNameCore(file structs.File, invalidFileNames []string) []structs.Mes
 	}
 	return messages
 }
+
+// junkFile is a file or folder operating systems and tools leave behind
+type junkFile struct {
+	pattern string // path.Match pattern of the lower case name
+	origin  string
+}
+
+// junkFiles is the built-in list of junk reported by HasNoJunkFiles
+var junkFiles = []junkFile{
+	{"thumbs.db", "Windows thumbnail cache"},
+	{"ehthumbs.db", "Windows thumbnail cache"},
+	{"desktop.ini", "Windows folder settings file"},
+	{".ds_store", "macOS Finder settings file"},
+	{"._*", "macOS resource fork"},
+	{"__macosx", "macOS archive metadata folder"},
+	{".spotlight-v100", "macOS Spotlight index"},
+	{".trashes", "macOS trash folder"},
+	{".fseventsd", "macOS file system event log"},
+	{"~$*", "Microsoft Office lock file"},
+	{".~lock.*#", "LibreOffice lock file"},
+	{".*.swp", "Vim swap file"},
+	{".ipynb_checkpoints", "Jupyter checkpoint folder"},
+	{"__pycache__", "Python bytecode cache"},
+}
+
+// junkOrigin tells what left the file or folder with the given name behind, if it is junk
+func junkOrigin(name string) (string, bool) {
+	name = strings.ToLower(name)
+	for _, junk := range junkFiles {
+		if matched, _ := path.Match(junk.pattern, name); matched {
+			return junk.origin, true
+		}
+	}
+	return "", false
+}
+
+// Files and folders are no junk left behind by operating systems and tools
+func HasNoJunkFiles(file structs.File, config config.Config) []structs.Message {
+	return reportUnwanted(file, junkOrigin)
+}
+
+// vcsFolders are the folders version control systems keep the history of a project in
+var vcsFolders = map[string]string{
+	".git":   "Git",
+	".svn":   "Subversion",
+	".hg":    "Mercurial",
+	".bzr":   "Bazaar",
+	"CVS":    "CVS",
+	"_darcs": "Darcs",
+}
+
+// Folders are no repositories of version control systems, which hold the full history of a project
+func HasNoVCSMetadata(file structs.File, config config.Config) []structs.Message {
+	return reportUnwanted(file, func(name string) (string, bool) {
+		system, ok := vcsFolders[name]
+		return system + " repository with the full history of the project", ok
+	})
+}
+
+// reportUnwanted reports the file if identify tells what it is. Files inside archives are named by
+// their path, the first folder on it identify knows is reported instead.
+func reportUnwanted(file structs.File, identify func(name string) (string, bool)) []structs.Message {
+	remove := "please delete it before publishing the package."
+	if file.ArchiveName != "" {
+		remove = "please remove it from the archive."
+	}
+	parts := splitName(strings.TrimRight(file.Name, "/\\"))
+	for i, part := range parts {
+		what, ok := identify(part)
+		if !ok {
+			continue
+		}
+		source := file
+		if name := strings.Join(parts[:i+1], "/"); name != file.Name {
+			// The files in the folder report the folder, so their messages are merged
+			source.Name = name
+			source.DisplayName = name
+		}
+		return []structs.Message{{Content: "'" + source.Name + "' is a " + what + ", " + remove, Source: source}}
+	}
+	return nil
+}
+
+// splitName splits the name of a file into its folders and the base name. Members of archives
+// written on Windows may be separated by backslashes instead of slashes.
+func splitName(name string) []string {
+	return strings.Split(strings.ReplaceAll(name, "\\", "/"), "/")
+}
+
+// executableSuffixes are the suffixes of Windows programs and scripts
+var executableSuffixes = map[string]string{
+	".exe": "Windows program",
+	".com": "Windows program",
+	".scr": "Windows program",
+	".dll": "Windows library",
+	".msi": "Windows installer",
+	".bat": "Windows batch script",
+	".cmd": "Windows batch script",
+	".ps1": "PowerShell script",
+	".vbs": "VBScript",
+}
+
+// executableMagic are the first bytes of Linux and macOS executables
+var executableMagic = []struct {
+	magic string
+	kind  string
+}{
+	{"\x7fELF", "Linux executable (ELF)"},
+	{"\xfe\xed\xfa\xce", "macOS executable (Mach-O)"},
+	{"\xfe\xed\xfa\xcf", "macOS executable (Mach-O)"},
+	{"\xce\xfa\xed\xfe", "macOS executable (Mach-O)"},
+	{"\xcf\xfa\xed\xfe", "macOS executable (Mach-O)"},
+}
+
+// shells are the interpreters of shell scripts, as named in their #! line
+var shells = []string{"sh", "bash", "dash", "zsh", "ksh", "csh", "tcsh", "fish"}
+
+// executableKind tells what kind of executable the file at path is from its first bytes, ""
+// for other files
+func executableKind(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	head = head[:n]
+
+	for _, executable := range executableMagic {
+		if bytes.HasPrefix(head, []byte(executable.magic)) {
+			return executable.kind
+		}
+	}
+	// DOS stubs start with MZ and point at the PE header of the Windows program
+	if bytes.HasPrefix(head, []byte("MZ")) && len(head) >= 0x40 {
+		signature := make([]byte, 4)
+		if _, err := file.ReadAt(signature, int64(binary.LittleEndian.Uint32(head[0x3c:]))); err == nil && string(signature) == "PE\x00\x00" {
+			return "Windows program (PE)"
+		}
+	}
+	if line, ok := bytes.CutPrefix(head, []byte("#!")); ok {
+		line, _, _ = bytes.Cut(line, []byte("\n"))
+		fields := strings.Fields(string(line))
+		if len(fields) > 1 && filepath.Base(fields[0]) == "env" {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 {
+			for _, shell := range shells {
+				if filepath.Base(fields[0]) == shell {
+					return "shell script"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Files are no programs or scripts, which repositories do not distribute
+func IsFreeOfExecutables(file structs.File, config config.Config) []structs.Message {
+	kind, ok := executableSuffixes[strings.ToLower(filepath.Ext(file.Name))]
+	if !ok && file.ArchiveName == "" {
+		// The contents of files inside archives are not at hand for the file list checks
+		kind = executableKind(file.Path)
+	}
+	if kind == "" {
+		return nil
+	}
+	return []structs.Message{{Content: "'" + file.Name + "' is a " + kind + ", executables should not be part of a data package.", Source: file}}
+}
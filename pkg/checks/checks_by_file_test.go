@@ -1,11 +1,16 @@
 package checks
 
 import (
+	"context"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
@@ -201,6 +206,73 @@ func TestIsFileNameTooLong(t *testing.T) {
 	}
 }
 
+func TestIsFileNameTooLongFullPath(t *testing.T) {
+	deep := strings.Repeat("folder/", 30)
+	root := "package"
+	pathConfig := func(arguments map[string]interface{}) config.Config {
+		return config.Config{Tests: map[string]*config.TestConfig{
+			"IsFileNameTooLong": {KeywordArguments: []map[string]interface{}{arguments}},
+		}}.WithPackageRoot(root)
+	}
+	enabled := pathConfig(map[string]interface{}{"full_path": true, "max_path_length": int64(200)})
+	tests := []struct {
+		name     string
+		file     structs.File
+		config   config.Config
+		expected []string
+	}{
+		{
+			"Short path",
+			structs.File{Name: "data.csv", Path: filepath.Join(root, "raw", "data.csv")},
+			enabled,
+			nil,
+		},
+		{
+			"Not enabled",
+			structs.File{Name: "data.csv", Path: filepath.Join(root, filepath.FromSlash(deep), "data.csv")},
+			pathConfig(map[string]interface{}{"max_path_length": int64(200)}),
+			nil,
+		},
+		{
+			"Deep path",
+			structs.File{Name: "data.csv", Path: filepath.Join(root, filepath.FromSlash(deep), "data.csv")},
+			enabled,
+			[]string{"Path '" + deep + "data.csv' is 218 characters long, more than 200, unpacking the package on Windows may fail."},
+		},
+		{
+			"Inside an archive",
+			structs.File{Name: deep + "x.csv", Path: filepath.Join(root, "raw", "data.zip"), ArchiveName: "data.zip"},
+			enabled,
+			[]string{"Path 'raw/data/" + deep + "x.csv' is 224 characters long, more than 200, unpacking the package on Windows may fail."},
+		},
+		{
+			"Default limit",
+			structs.File{Name: "data.csv", Path: filepath.Join(root, filepath.FromSlash(deep), "data.csv")},
+			pathConfig(map[string]interface{}{"full_path": true}),
+			nil,
+		},
+		{
+			"Without package root",
+			structs.File{Name: strings.Repeat("a", 60) + ".csv", Path: "/downloads/" + strings.Repeat("b", 300)},
+			config.Config{Tests: map[string]*config.TestConfig{
+				"IsFileNameTooLong": {KeywordArguments: []map[string]interface{}{{"full_path": true, "max_path_length": int64(50)}}},
+			}},
+			[]string{"Path '" + strings.Repeat("a", 60) + ".csv' is 64 characters long, more than 50, unpacking the package on Windows may fail."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range IsFileNameTooLong(tt.file, tt.config) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+}
+
 func TestHasFileNameSpecialChars(t *testing.T) {
 	var config = config.Config{}
 	tests := []struct {
@@ -464,6 +536,12 @@ func TestIsValidNameExtended(t *testing.T) {
 			disallowedNames:      []string{"__pycache__", "invalidfile.txt", ".txt"},
 			expectedMessageCount: 3,
 		},
+		{
+			name:                 "Folder separated by a backslash",
+			file:                 structs.File{Name: "__pycache__\\invalidfile.txt"},
+			disallowedNames:      []string{"__pycache__", "invalidfile.txt", ".txt"},
+			expectedMessageCount: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -476,6 +554,174 @@ func TestIsValidNameExtended(t *testing.T) {
 	}
 }
 
+func TestSplitNameWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"file.txt", []string{"file.txt"}},
+		{"data/raw/file.txt", []string{"data", "raw", "file.txt"}},
+		{"data\\raw\\file.txt", []string{"data", "raw", "file.txt"}},
+		{"data\\raw/file.txt", []string{"data", "raw", "file.txt"}},
+	}
+	for _, tt := range tests {
+		if parts := splitName(tt.name); !reflect.DeepEqual(parts, tt.expected) {
+			t.Errorf("splitName(%q) = %q, expected %q", tt.name, parts, tt.expected)
+		}
+	}
+}
+
+func TestHasNoJunkFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected string
+		source   string
+	}{
+		{"Regular file", structs.File{Name: "data.csv"}, "", ""},
+		{"Windows thumbnail cache", structs.File{Name: "Thumbs.db"}, "'Thumbs.db' is a Windows thumbnail cache, please delete it before publishing the package.", "Thumbs.db"},
+		{"Finder settings", structs.File{Name: ".DS_Store"}, "'.DS_Store' is a macOS Finder settings file, please delete it before publishing the package.", ".DS_Store"},
+		{"Office lock file", structs.File{Name: "~$report.docx"}, "'~$report.docx' is a Microsoft Office lock file, please delete it before publishing the package.", "~$report.docx"},
+		{"LibreOffice lock file", structs.File{Name: ".~lock.table.ods#"}, "'.~lock.table.ods#' is a LibreOffice lock file, please delete it before publishing the package.", ".~lock.table.ods#"},
+		{"Name containing a junk name", structs.File{Name: "my__pycache__notes.txt"}, "", ""},
+		{
+			"File in a junk folder of an archive",
+			structs.File{Name: "code/__pycache__/model.cpython-312.pyc", ArchiveName: "code.zip"},
+			"'code/__pycache__' is a Python bytecode cache, please remove it from the archive.", "code/__pycache__",
+		},
+		{
+			"Junk folder entry of an archive",
+			structs.File{Name: "code/__pycache__/", ArchiveName: "code.zip"},
+			"'code/__pycache__' is a Python bytecode cache, please remove it from the archive.", "code/__pycache__",
+		},
+		{
+			"Resource fork in an archive",
+			structs.File{Name: "__MACOSX/data/._table.csv", ArchiveName: "data.zip"},
+			"'__MACOSX' is a macOS archive metadata folder, please remove it from the archive.", "__MACOSX",
+		},
+		{
+			"Junk folder of an archive written on Windows",
+			structs.File{Name: "code\\__pycache__\\model.cpython-312.pyc", ArchiveName: "code.zip"},
+			"'code/__pycache__' is a Python bytecode cache, please remove it from the archive.", "code/__pycache__",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HasNoJunkFiles(tt.file, config.Config{})
+			if tt.expected == "" {
+				if len(result) != 0 {
+					t.Errorf("expected no messages, got %v", result)
+				}
+				return
+			}
+			if len(result) != 1 || result[0].Content != tt.expected {
+				t.Fatalf("expected %q, got %v", tt.expected, result)
+			}
+			if source := result[0].Source.(structs.File); source.Name != tt.source || source.ArchiveName != tt.file.ArchiveName {
+				t.Errorf("expected the source %q, got %+v", tt.source, source)
+			}
+		})
+	}
+}
+
+func TestHasNoVCSMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected string
+	}{
+		{"Regular folder", structs.File{Name: "data"}, ""},
+		{"Git ignore file", structs.File{Name: ".gitignore"}, ""},
+		{"Git repository", structs.File{Name: ".git"}, "'.git' is a Git repository with the full history of the project, please delete it before publishing the package."},
+		{
+			"Object of a Git repository in an archive",
+			structs.File{Name: "project/.git/objects/pack/pack-1a2b.pack", ArchiveName: "project.zip"},
+			"'project/.git' is a Git repository with the full history of the project, please remove it from the archive.",
+		},
+		{
+			"Subversion folder in an archive",
+			structs.File{Name: "code/.svn/", ArchiveName: "code.tar"},
+			"'code/.svn' is a Subversion repository with the full history of the project, please remove it from the archive.",
+		},
+		{"Mercurial repository", structs.File{Name: ".hg"}, "'.hg' is a Mercurial repository with the full history of the project, please delete it before publishing the package."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HasNoVCSMetadata(tt.file, config.Config{})
+			if tt.expected == "" {
+				if len(result) != 0 {
+					t.Errorf("expected no messages, got %v", result)
+				}
+				return
+			}
+			if len(result) != 1 || result[0].Content != tt.expected {
+				t.Errorf("expected %q, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsFreeOfExecutables(t *testing.T) {
+	dir := t.TempDir()
+	pe := make([]byte, 0x80)
+	copy(pe, "MZ")
+	pe[0x3c] = 0x40
+	copy(pe[0x40:], "PE\x00\x00")
+	files := map[string]string{
+		"tool":          "\x7fELF\x02\x01\x01",
+		"setup.bin":     string(pe),
+		"notes.txt":     "MZ is the abbreviation of the station, see the table.\n" + strings.Repeat("x", 100),
+		"run":           "#!/bin/bash\necho hello\n",
+		"clean.sh":      "#!/usr/bin/env sh\nrm -rf out\n",
+		"analysis.py":   "#!/usr/bin/env python3\nprint(1)\n",
+		"install.bat":   "@echo off\n",
+		"results.csv":   "a,b\n1,2\n",
+		"library.dylib": "\xcf\xfa\xed\xfe",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		file     structs.File
+		expected string
+	}{
+		{structs.File{Name: "tool"}, "Linux executable (ELF)"},
+		{structs.File{Name: "setup.bin"}, "Windows program (PE)"},
+		{structs.File{Name: "notes.txt"}, ""},
+		{structs.File{Name: "run"}, "shell script"},
+		{structs.File{Name: "clean.sh"}, "shell script"},
+		{structs.File{Name: "analysis.py"}, ""},
+		{structs.File{Name: "install.bat"}, "Windows batch script"},
+		{structs.File{Name: "results.csv"}, ""},
+		{structs.File{Name: "library.dylib"}, "macOS executable (Mach-O)"},
+		{structs.File{Name: "bin/Setup.EXE", Path: filepath.Join(dir, "results.csv"), ArchiveName: "tools.zip"}, "Windows program"},
+		{structs.File{Name: "bin/tool", Path: filepath.Join(dir, "tool"), ArchiveName: "tools.zip"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file.Name, func(t *testing.T) {
+			if tt.file.Path == "" {
+				tt.file.Path = filepath.Join(dir, tt.file.Name)
+			}
+			result := IsFreeOfExecutables(tt.file, config.Config{})
+			if tt.expected == "" {
+				if len(result) != 0 {
+					t.Errorf("expected no messages, got %v", result)
+				}
+				return
+			}
+			expected := "'" + tt.file.Name + "' is a " + tt.expected + ", executables should not be part of a data package."
+			if len(result) != 1 || result[0].Content != expected {
+				t.Errorf("expected %q, got %v", expected, result)
+			}
+		})
+	}
+}
+
 func TestIsTextFile(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -572,12 +818,13 @@ func TestIsArchiveFreeOfKeywordsWithRealArchives(t *testing.T) {
 	sevenZipFile := structs.File{Path: "../../testdata/archives/complex_archive.7z", Name: "complex_archive.7z", DisplayName: "complex_archive.7z", IsArchive: true}
 	tarFile := structs.File{Path: "../../testdata/archives/complex_archive.tar", Name: "complex_archive.tar", DisplayName: "complex_archive.tar", IsArchive: true}
 
-	// Expected message contents (without archive suffix - now stored in ArchiveName field)
+	// Expected message contents (without archive suffix - now stored in ArchiveName field), one per match
 	expectedContents := []string{
 		"Possible credentials in file 'User'",
 		"Possible internal information in file 'Q:'",
 		"Do you have hardcoded filepaths in your files?  Found suspicious keyword(s): '/Users/'",
-		"Possible credentials in file 'PASSWORD', 'USER'",
+		"Possible credentials in file 'PASSWORD'",
+		"Possible credentials in file 'USER'",
 		"Possible credentials in file 'Password'",
 		"Possible internal information in file 'Q:'",
 	}
@@ -591,19 +838,19 @@ func TestIsArchiveFreeOfKeywordsWithRealArchives(t *testing.T) {
 		{
 			name:            "Complex zip archive",
 			file:            zipFile,
-			expectedCount:   6,
+			expectedCount:   7,
 			archiveNameInSource: "complex_archive.zip",
 		},
 		{
 			name:            "Complex 7z archive",
 			file:            sevenZipFile,
-			expectedCount:   6,
+			expectedCount:   7,
 			archiveNameInSource: "complex_archive.7z",
 		},
 		{
 			name:            "Complex tar archive",
 			file:            tarFile,
-			expectedCount:   6,
+			expectedCount:   7,
 			archiveNameInSource: "complex_archive.tar",
 		},
 	}
@@ -643,3 +890,180 @@ func TestIsArchiveFreeOfKeywordsWithRealArchives(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoryBudget(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+	*cfg = cfg.WithSkippedFiles(output.NewSkippedFiles())
+
+	path := tempFile([]byte("user = admin\npassword = secret\n"))
+	defer os.Remove(path)
+	file := structs.File{Path: path, Name: "credentials.txt"}
+	archive := structs.File{Path: "../../testdata/archives/complex_archive.zip", Name: "complex_archive.zip", IsArchive: true}
+
+	budget := performance.NewMemoryBudget(1024 * 1024)
+	if messages := IsFreeOfKeywords(file, cfg.WithMemoryBudget(budget)); len(messages) == 0 {
+		t.Error("Expected findings within the memory budget")
+	}
+	if messages := IsArchiveFreeOfKeywords(archive, cfg.WithMemoryBudget(budget)); len(messages) != 7 {
+		t.Errorf("Expected 7 findings in the archive within the memory budget, got %d", len(messages))
+	}
+	if budget.Used() != 0 {
+		t.Errorf("Expected the checks to give back their memory, %d bytes are still used", budget.Used())
+	}
+	if skipped := skippedForMemory(cfg.SkippedFiles()); len(skipped) != 0 {
+		t.Errorf("Expected no file to be skipped, got %v", skipped)
+	}
+
+	budget = performance.NewMemoryBudget(4)
+	if messages := IsFreeOfKeywords(file, cfg.WithMemoryBudget(budget)); len(messages) != 0 {
+		t.Errorf("Expected the file to be skipped once the memory budget is exhausted, got %v", messages)
+	}
+	if messages := IsArchiveFreeOfKeywords(archive, cfg.WithMemoryBudget(budget)); len(messages) != 0 {
+		t.Errorf("Expected the archive members to be skipped once the memory budget is exhausted, got %v", messages)
+	}
+	skipped := skippedForMemory(cfg.SkippedFiles())
+	if len(skipped) != 2 || skipped[0] != "credentials.txt" || skipped[1] != "complex_archive.zip" {
+		t.Errorf("Expected the file and the archive to be reported as skipped, got %v", skipped)
+	}
+}
+
+func TestSkippedFiles(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	*cfg = cfg.WithSkippedFiles(output.NewSkippedFiles())
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+	cfg.General.MaxContentScanFileSize = 64
+
+	binary := tempFile([]byte{0x00, 0x01, 0x02, 0xff, 0xfe, 0x00})
+	defer os.Remove(binary)
+	large := tempFile([]byte(strings.Repeat("password = secret\n", 10)))
+	defer os.Remove(large)
+
+	IsFreeOfKeywords(structs.File{Path: binary, Name: "data.bin"}, *cfg)
+	IsFreeOfKeywords(structs.File{Path: large, Name: "large.txt"}, *cfg)
+	IsFreeOfKeywords(structs.File{Path: filepath.Join(t.TempDir(), "missing.txt"), Name: "missing.txt"}, *cfg)
+
+	codes := map[string]output.SkipCode{}
+	for _, file := range cfg.SkippedFiles().Files() {
+		codes[file.Filename] = file.Code
+	}
+	expected := map[string]output.SkipCode{"data.bin": output.SkipBinary, "large.txt": output.SkipTooLarge, "missing.txt": output.SkipReadError}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Expected skipped files %v, got %v", expected, codes)
+	}
+}
+
+func TestIsFreeOfKeywordsFolder(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	*cfg = cfg.WithSkippedFiles(output.NewSkippedFiles())
+
+	// Folders collected with includeFolders are not read
+	folder := structs.File{Path: t.TempDir(), Name: "raw", Size: -1}
+	if messages := IsFreeOfKeywords(folder, *cfg); len(messages) != 0 {
+		t.Errorf("Expected no findings for a folder, got %v", messages)
+	}
+	if skipped := cfg.SkippedFiles().Files(); len(skipped) != 0 {
+		t.Errorf("Expected the folder not to be skipped, got %+v", skipped)
+	}
+}
+
+func TestSharedFileContent(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+
+	path := tempFile([]byte("user = admin\npassword = secret\n"))
+	defer os.Remove(path)
+	file := structs.File{Path: path, Name: "credentials.txt"}
+	expected := IsFreeOfKeywords(file, *cfg)
+	if len(expected) == 0 {
+		t.Fatal("Expected findings in the file")
+	}
+
+	// Once read, the content is taken from the provider instead of the file
+	content := performance.NewFileContent(path, nil)
+	defer content.Close()
+	if _, err := content.Bytes(); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte("nothing to see\n"), 0644)
+	if messages := IsFreeOfKeywords(file, cfg.WithFileContent(content)); len(messages) != len(expected) {
+		t.Errorf("Expected the shared content to be checked, got %v", messages)
+	}
+	if messages := IsFreeOfKeywords(file, *cfg); len(messages) != 0 {
+		t.Errorf("Expected the file to be read without a provider, got %v", messages)
+	}
+}
+
+// skippedForMemory returns the recorded files skipped for the memory budget of the scan
+func skippedForMemory(skippedFiles *output.SkippedFiles) []string {
+	var skipped []string
+	for _, file := range skippedFiles.Files() {
+		if file.Code == output.SkipMemoryLimit {
+			skipped = append(skipped, file.Filename)
+		}
+	}
+	return skipped
+}
+
+func TestArchiveProgress(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+	file := structs.File{Path: "../../testdata/archives/ten_valid_files.zip", Name: "ten_valid_files.zip", IsArchive: true}
+
+	var members []int
+	IsArchiveFreeOfKeywords(file, cfg.WithArchiveProgress(func(archive string, member, total int) {
+		if archive != "ten_valid_files.zip" || total != 10 {
+			t.Errorf("Expected progress in ten_valid_files.zip with 10 members, got %s with %d", archive, total)
+		}
+		members = append(members, member)
+	}))
+
+	if len(members) != 10 || members[0] != 1 || members[9] != 10 {
+		t.Errorf("Expected members 1 to 10, got %v", members)
+	}
+
+	// Without a progress function the archive is scanned as before
+	if messages := IsArchiveFreeOfKeywords(file, *cfg); len(members) != 10 || len(messages) != 0 {
+		t.Errorf("Expected no progress and no findings without a progress function, got %v and %v", members, messages)
+	}
+}
+
+func TestArchiveCancelled(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+	file := structs.File{Path: "../../testdata/archives/complex_archive.zip", Name: "complex_archive.zip", IsArchive: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if messages := IsArchiveFreeOfKeywords(file, cfg.WithContext(ctx)); len(messages) != 0 {
+		t.Errorf("Expected a cancelled scan not to unpack the archive, got %v", messages)
+	}
+}
@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+/*
+This file contains tests of the metadata of a CKAN package, such as its description and license.
+*/
+
+// Package has a description
+func HasDescription(metadata structs.Metadata, config config.Config) []structs.Message {
+	if strings.TrimSpace(metadata.Notes) == "" {
+		return []structs.Message{{Content: "The package has no description.", Source: metadata}}
+	}
+	return nil
+}
+
+// Licenses that do not tell how the data may be used
+var unspecifiedLicenses = []string{"", "notspecified", "other-closed"}
+
+// Package has a license
+func HasLicense(metadata structs.Metadata, config config.Config) []structs.Message {
+	license := strings.ToLower(strings.TrimSpace(metadata.LicenseID))
+	for _, unspecified := range unspecifiedLicenses {
+		if license == unspecified {
+			return []structs.Message{{Content: "The package has no license.", Source: metadata}}
+		}
+	}
+	return nil
+}
+
+// Domains and mailbox names of email addresses used as placeholders
+var (
+	placeholderDomains   = []string{"example.com", "example.org", "example.net", "example.ch", "test.com", "domain.com", "email.com", "localhost", "invalid"}
+	placeholderMailboxes = []string{"test", "foo", "xxx", "todo", "changeme", "user", "name", "your.name", "yourname", "firstname.lastname", "noreply", "no-reply"}
+)
+
+// isPlaceholderEmail reports whether address is not a valid email address or a placeholder
+func isPlaceholderEmail(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return true
+	}
+	mailbox, domain, _ := strings.Cut(strings.ToLower(parsed.Address), "@")
+	for _, placeholder := range placeholderDomains {
+		if domain == placeholder || strings.HasSuffix(domain, "."+placeholder) {
+			return true
+		}
+	}
+	for _, placeholder := range placeholderMailboxes {
+		if mailbox == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+// Author and maintainer emails are real addresses, not placeholders
+func HasValidAuthorEmail(metadata structs.Metadata, config config.Config) []structs.Message {
+	var messages []structs.Message
+	for _, field := range []struct{ name, value string }{
+		{"author", metadata.AuthorEmail},
+		{"maintainer", metadata.MaintainerEmail},
+	} {
+		if value := strings.TrimSpace(field.value); value != "" && isPlaceholderEmail(value) {
+			messages = append(messages, structs.Message{Content: "The " + field.name + " email '" + value + "' looks like a placeholder.", Source: metadata})
+		}
+	}
+	return messages
+}
+
+// Title and description contain none of the keywords of IsFreeOfKeywords
+func IsMetadataFreeOfKeywords(metadata structs.Metadata, config config.Config) []structs.Message {
+	budget := newFindingBudget(config)
+	var messages []structs.Message
+	for _, rule := range hidingSecrets(config, keywordRules(config)) {
+		for _, field := range []struct{ name, value string }{
+			{"title", metadata.Title},
+			{"description", metadata.Notes},
+		} {
+			for _, m := range rule.find([]byte(field.value), 1, budget) {
+				message := rule.message(metadata, m)
+				message.Content += " in the " + field.name
+				messages = append(messages, message)
+			}
+		}
+	}
+	return budget.apply(messages)
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestHasDescriptionAndLicense(t *testing.T) {
+	complete := structs.Metadata{Package: "lake-ice", Notes: "Ice cover of Swiss lakes", LicenseID: "cc-by"}
+	if messages := append(HasDescription(complete, config.Config{}), HasLicense(complete, config.Config{})...); len(messages) != 0 {
+		t.Errorf("Expected no issues, got %v", messages)
+	}
+
+	incomplete := structs.Metadata{Package: "lake-ice", Notes: " \n", LicenseID: "notspecified"}
+	messages := append(HasDescription(incomplete, config.Config{}), HasLicense(incomplete, config.Config{})...)
+	if len(messages) != 2 || messages[0].Content != "The package has no description." || messages[1].Content != "The package has no license." {
+		t.Errorf("Expected a missing description and license, got %v", messages)
+	}
+	if messages[0].Source != incomplete {
+		t.Errorf("Expected the metadata as source, got %v", messages[0].Source)
+	}
+}
+
+func TestHasValidAuthorEmail(t *testing.T) {
+	tests := []struct {
+		email       string
+		placeholder bool
+	}{
+		{"jane.doe@eawag.ch", false},
+		{"Jane Doe <jane.doe@eawag.ch>", false},
+		{"jane.doe@example.com", true},
+		{"someone@mail.example.org", true},
+		{"test@eawag.ch", true},
+		{"TODO", true},
+		{"user@domain.com", true},
+	}
+	for _, tt := range tests {
+		messages := HasValidAuthorEmail(structs.Metadata{AuthorEmail: tt.email}, config.Config{})
+		if (len(messages) == 1) != tt.placeholder {
+			t.Errorf("%q: expected placeholder=%v, got %v", tt.email, tt.placeholder, messages)
+		}
+	}
+
+	messages := HasValidAuthorEmail(structs.Metadata{MaintainerEmail: "foo@test.com"}, config.Config{})
+	if len(messages) != 1 || !strings.Contains(messages[0].Content, "maintainer email 'foo@test.com'") {
+		t.Errorf("Expected the maintainer email to be reported, got %v", messages)
+	}
+	if messages := HasValidAuthorEmail(structs.Metadata{}, config.Config{}); len(messages) != 0 {
+		t.Errorf("Expected missing emails not to be reported, got %v", messages)
+	}
+}
+
+func TestIsMetadataFreeOfKeywords(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	metadata := structs.Metadata{
+		Title: "Lake ice password",
+		Notes: "Measurements of lake ice.\nThe password for the raw data is secret.",
+	}
+	messages := IsMetadataFreeOfKeywords(metadata, *cfg)
+	if len(messages) != 2 {
+		t.Fatalf("Expected the keyword in the title and the description, got %v", messages)
+	}
+	if !strings.HasSuffix(messages[0].Content, "in the title") || !strings.HasSuffix(messages[1].Content, "in the description") || messages[1].Line != 2 {
+		t.Errorf("Unexpected messages %+v", messages)
+	}
+
+	if messages := IsMetadataFreeOfKeywords(structs.Metadata{Title: "Lake ice"}, *cfg); len(messages) != 0 {
+		t.Errorf("Expected no keywords, got %v", messages)
+	}
+}
@@ -3,6 +3,7 @@ package checks
 import (
 	"bytes"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -69,3 +70,75 @@ func ReadMeContainsTOC(repository structs.Repository, config config.Config) []st
 	}
 	return nil
 }
+
+// commonRoot returns the directory containing all files of the package
+func commonRoot(repository structs.Repository) string {
+	var root []string
+	for i, file := range repository.Files {
+		parts := strings.Split(filepath.Dir(filepath.Clean(file.Path)), string(filepath.Separator))
+		if i == 0 {
+			root = parts
+			continue
+		}
+		n := 0
+		for n < len(root) && n < len(parts) && root[n] == parts[n] {
+			n++
+		}
+		root = root[:n]
+	}
+	return strings.Join(root, string(filepath.Separator))
+}
+
+// packageFolders returns the folders collected with the package, as slash separated paths relative
+// to the directory containing all files of the package
+func packageFolders(repository structs.Repository) []string {
+	rootPath := commonRoot(repository)
+	var folders []string
+	for _, file := range repository.Files {
+		if info, err := os.Stat(file.Path); err != nil || !info.IsDir() {
+			continue
+		}
+		if relative, err := filepath.Rel(rootPath, filepath.Clean(file.Path)); err == nil {
+			folders = append(folders, filepath.ToSlash(relative))
+		}
+	}
+	return folders
+}
+
+// The package has the required folders and none of the forbidden ones
+func HasValidFolderStructure(repository structs.Repository, config config.Config) []structs.Message {
+	test, ok := config.Tests["HasValidFolderStructure"]
+	if !ok {
+		return nil
+	}
+	folders := packageFolders(repository)
+
+	var messages []structs.Message
+	for _, argumentSet := range test.KeywordArguments {
+		required, _ := argumentSet["required_folders"].([]string)
+		for _, folder := range required {
+			folder = strings.Trim(filepath.ToSlash(folder), "/")
+			found := false
+			for _, existing := range folders {
+				if strings.EqualFold(existing, folder) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				messages = append(messages, structs.Message{Content: "The package has no '" + folder + "/' folder.", Source: repository})
+			}
+		}
+
+		forbidden, _ := argumentSet["forbidden_folders"].([]string)
+		for _, existing := range folders {
+			name := path.Base(existing)
+			for _, folder := range forbidden {
+				if strings.EqualFold(name, strings.Trim(filepath.ToSlash(folder), "/")) {
+					messages = append(messages, structs.Message{Content: "The folder '" + existing + "/' is not allowed in the package.", Source: repository})
+				}
+			}
+		}
+	}
+	return messages
+}
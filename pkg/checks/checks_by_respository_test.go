@@ -2,6 +2,7 @@ package checks
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/eawag-rdm/pc/pkg/config"
@@ -112,3 +113,51 @@ func TestReadMeContainsTOC(t *testing.T) {
 		})
 	}
 }
+
+func TestHasValidFolderStructure(t *testing.T) {
+	root := t.TempDir()
+	var files []structs.File
+	for _, dir := range []string{"data", "data/raw", "code", "code/__MACOSX", ".git"} {
+		path := filepath.Join(root, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, structs.File{Name: filepath.Base(path), Path: path})
+	}
+	readme := filepath.Join(root, "readme.md")
+	if err := os.WriteFile(readme, []byte("# Data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, structs.File{Name: "readme.md", Path: readme})
+	repository := structs.Repository{Files: files}
+
+	tests := []struct {
+		name      string
+		arguments []map[string]interface{}
+		expected  []string
+	}{
+		{"Test with no policy", nil, nil},
+		{
+			"Test with required folders",
+			[]map[string]interface{}{{"required_folders": []string{"data/", "Code", "data/raw", "docs"}}},
+			[]string{"The package has no 'docs/' folder."},
+		},
+		{
+			"Test with forbidden folders",
+			[]map[string]interface{}{{"forbidden_folders": []string{"__macosx", ".git"}}},
+			[]string{"The folder 'code/__MACOSX/' is not allowed in the package.", "The folder '.git/' is not allowed in the package."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Tests: map[string]*config.TestConfig{"HasValidFolderStructure": {KeywordArguments: tt.arguments}}}
+			var contents []string
+			for _, message := range HasValidFolderStructure(repository, cfg) {
+				contents = append(contents, message.Content)
+			}
+			assert.ElementsMatch(t, tt.expected, contents)
+		})
+	}
+
+	assert.Nil(t, HasValidFolderStructure(repository, config.Config{}), "Expected no messages without a [test.HasValidFolderStructure] section")
+}
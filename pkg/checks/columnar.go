@@ -0,0 +1,30 @@
+package checks
+
+import (
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// isColumnarFile reports whether the file is a Parquet or Feather file, of which only the
+// schema and metadata are searched
+func isColumnarFile(name string) bool {
+	return strings.HasSuffix(name, ".parquet") || strings.HasSuffix(name, ".feather")
+}
+
+// findInColumnarSchema reports the matches of the rules in the column names and key-value
+// metadata of a Parquet or Feather file, where identifiers of people or samples often end up.
+// The data is not searched.
+func findInColumnarSchema(file structs.File, config config.Config, rules []keywordRule) []structs.Message {
+	schema, err := readers.ReadColumnarSchema(file)
+	if err != nil {
+		logger.Warning("Error reading the schema of '%s': %v", file.Path, err)
+		skipFile(config, file, output.SkipReadError, "Error reading the schema: %v", err)
+		return nil
+	}
+	budget := newFindingBudget(config)
+	return budget.apply(findWithoutLocation(file, [][]byte{schema.Text()}, rules, budget))
+}
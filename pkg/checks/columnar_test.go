@@ -0,0 +1,50 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFreeOfKeywordsColumnarSchema(t *testing.T) {
+	cfg := keywordConfig(map[string]interface{}{"keywords": []string{"patient", "jdoe"}, "info": "Personal data"})
+	// Larger files are searched as well, only their footer is read
+	cfg.General.MaxContentScanFileSize = 1
+	expected := map[string][]string{
+		"test.parquet": {"Personal data 'patient'", "Personal data 'jdoe'"},
+		// The metadata of a column has the name of the column in front of its keys
+		"test.feather": {"Personal data 'patient'", "Personal data 'patient'", "Personal data 'jdoe'"},
+	}
+	for name, contents := range expected {
+		messages := IsFreeOfKeywords(structs.File{Path: "../../testdata/" + name, Name: name}, cfg)
+		var actual []string
+		for _, message := range messages {
+			assert.Equal(t, 0, message.Line, name)
+			actual = append(actual, message.Content)
+		}
+		if assert.Equal(t, contents, actual, name) {
+			assert.Equal(t, "column »patient«_name", messages[0].Snippet, name)
+		}
+	}
+
+	paths := IsFreeOfAbsolutePaths(structs.File{Path: "../../testdata/test.parquet", Name: "test.parquet"}, pathsConfig())
+	if assert.Len(t, paths, 1) {
+		assert.Equal(t, `source: »Q:\lake\raw.csv«`, paths[0].Snippet)
+	}
+}
+
+func TestIsFreeOfKeywordsColumnarSchemaUnreadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "truncated.parquet")
+	assert.NoError(t, os.WriteFile(path, []byte("PAR1\x15\x00"), 0644))
+	skippedFiles := output.NewSkippedFiles()
+	messages := IsFreeOfKeywords(structs.File{Path: path, Name: "truncated.parquet"}, keywordConfig(map[string]interface{}{"keywords": []string{"PAR"}}).WithSkippedFiles(skippedFiles))
+	assert.Empty(t, messages)
+	skipped := skippedFiles.Files()
+	if assert.Len(t, skipped, 1) {
+		assert.Equal(t, output.SkipReadError, skipped[0].Code)
+	}
+}
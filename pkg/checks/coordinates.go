@@ -0,0 +1,203 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// BoundingBox is the area the coordinates of a package are expected in, in degrees
+type BoundingBox struct {
+	MinLon, MinLat, MaxLon, MaxLat float64
+}
+
+// ParseBoundingBox parses a bounding box written as "min_lon,min_lat,max_lon,max_lat", the
+// order of the bbox member of GeoJSON, e.g. "5.9,45.8,10.5,47.9" for Switzerland
+func ParseBoundingBox(text string) (BoundingBox, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, errors.New("expected four numbers: min_lon,min_lat,max_lon,max_lat")
+	}
+	var numbers [4]float64
+	for i, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("'%s' is not a number", strings.TrimSpace(part))
+		}
+		numbers[i] = n
+	}
+	box := BoundingBox{MinLon: numbers[0], MinLat: numbers[1], MaxLon: numbers[2], MaxLat: numbers[3]}
+	if !isLonLat(box.MinLon, box.MinLat) || !isLonLat(box.MaxLon, box.MaxLat) {
+		return BoundingBox{}, errors.New("longitudes must be within -180 and 180, latitudes within -90 and 90")
+	}
+	if box.MinLon > box.MaxLon || box.MinLat > box.MaxLat {
+		return BoundingBox{}, errors.New("the minimum is larger than the maximum")
+	}
+	return box, nil
+}
+
+// Contains reports whether the position is inside the box, its edges included
+func (b BoundingBox) Contains(lon, lat float64) bool {
+	return lon >= b.MinLon && lon <= b.MaxLon && lat >= b.MinLat && lat <= b.MaxLat
+}
+
+func (b BoundingBox) String() string {
+	return fmt.Sprintf("%g,%g,%g,%g", b.MinLon, b.MinLat, b.MaxLon, b.MaxLat)
+}
+
+// isLonLat reports whether a position is within the range of longitude and latitude
+func isLonLat(lon, lat float64) bool {
+	return lon >= -180 && lon <= 180 && lat >= -90 && lat <= 90
+}
+
+// formatPosition writes a position as (lon, lat)
+func formatPosition(lon, lat float64) string {
+	return fmt.Sprintf("(%g, %g)", lon, lat)
+}
+
+// isGeographicCRS reports whether a GeoJSON crs name is WGS 84 longitude and latitude, which
+// files without a crs member use
+func isGeographicCRS(name string) bool {
+	return name == "" || strings.HasSuffix(name, "CRS84") || strings.HasSuffix(name, ":4326")
+}
+
+// isGeographicPRJ reports whether the well-known text of a .prj file declares a geographic
+// coordinate system rather than a projected one
+func isGeographicPRJ(wkt string) bool {
+	wkt = strings.ToUpper(strings.TrimSpace(wkt))
+	return strings.HasPrefix(wkt, "GEOGCS[") || strings.HasPrefix(wkt, "GEOGCRS[") || strings.HasPrefix(wkt, "GEODCRS[")
+}
+
+// GeoJSON files and shapefiles have coordinates of longitude and latitude within their range and,
+// if bounding_box is configured, within the box, and shapefiles declare their coordinate reference
+// system in a .prj file. Projected coordinates are not checked against the ranges or the box.
+func HasPlausibleCoordinates(repository structs.Repository, config config.Config) []structs.Message {
+	var box *BoundingBox
+	if test, ok := config.Tests["HasPlausibleCoordinates"]; ok {
+		for _, argumentSet := range test.KeywordArguments {
+			if text, ok := argumentSet["bounding_box"].(string); ok {
+				// Bounding boxes were validated when the config was loaded, invalid ones are skipped
+				if parsed, err := ParseBoundingBox(text); err == nil {
+					box = &parsed
+				}
+			}
+		}
+	}
+
+	var messages []structs.Message
+	for _, file := range repository.Files {
+		switch strings.ToLower(path.Ext(file.Name)) {
+		case ".geojson":
+			messages = append(messages, checkGeoJSONCoordinates(file, repository, box, config)...)
+		case ".shp":
+			messages = append(messages, checkShapefileCoordinates(file, repository, box, config)...)
+		}
+	}
+	return messages
+}
+
+func checkGeoJSONCoordinates(file structs.File, repository structs.Repository, box *BoundingBox, config config.Config) []structs.Message {
+	var count, invalid, outside int
+	var firstInvalid, firstOutside string
+	crs, err := readers.ReadGeoJSONPositions(file, func(lon, lat float64) {
+		count++
+		if !isLonLat(lon, lat) {
+			if invalid == 0 {
+				firstInvalid = formatPosition(lon, lat)
+			}
+			invalid++
+		} else if box != nil && !box.Contains(lon, lat) {
+			if outside == 0 {
+				firstOutside = formatPosition(lon, lat)
+			}
+			outside++
+		}
+	})
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		skipFile(config, file, output.SkipReadError, "Error reading the GeoJSON file: %v", err)
+		return nil
+	}
+	if err != nil {
+		return []structs.Message{{Content: "The GeoJSON file '" + file.Name + "' is corrupt: " + err.Error() + ".", Source: repository}}
+	}
+	// Projected coordinates are in metres or feet, not degrees
+	if !isGeographicCRS(crs) {
+		return nil
+	}
+
+	var messages []structs.Message
+	if invalid > 0 {
+		messages = append(messages, structs.Message{
+			Content: fmt.Sprintf("'%s' has %d of %d positions outside the range of longitude (-180 to 180) and latitude (-90 to 90), e.g. %s, the coordinates may be projected or have longitude and latitude swapped.", file.Name, invalid, count, firstInvalid),
+			Source:  repository,
+		})
+	}
+	if outside > 0 {
+		messages = append(messages, structs.Message{
+			Content: fmt.Sprintf("'%s' has %d of %d positions outside the bounding box %s, e.g. %s.", file.Name, outside, count, box.String(), firstOutside),
+			Source:  repository,
+		})
+	}
+	return messages
+}
+
+func checkShapefileCoordinates(file structs.File, repository structs.Repository, box *BoundingBox, config config.Config) []structs.Message {
+	header, err := readers.ReadShapefileHeader(file)
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		skipFile(config, file, output.SkipReadError, "Error reading the shapefile: %v", err)
+		return nil
+	}
+	if err != nil {
+		return []structs.Message{{Content: "The shapefile '" + file.Name + "' is corrupt: " + err.Error() + ".", Source: repository}}
+	}
+
+	prj := shapefilePRJ(file, repository)
+	if prj == nil {
+		return []structs.Message{{Content: "The shapefile '" + file.Name + "' has no .prj file, its coordinate reference system is unknown.", Source: repository}}
+	}
+	wkt, err := os.ReadFile(prj.Path)
+	if err != nil {
+		skipFile(config, *prj, output.SkipReadError, "Error reading file: %v", err)
+		return nil
+	}
+	// Shapefiles without shapes have no extent, projected ones are in metres or feet
+	if header.ShapeType == 0 || !isGeographicPRJ(string(wkt)) {
+		return nil
+	}
+
+	extent := formatPosition(header.MinX, header.MinY) + " - " + formatPosition(header.MaxX, header.MaxY)
+	if !isLonLat(header.MinX, header.MinY) || !isLonLat(header.MaxX, header.MaxY) {
+		return []structs.Message{{
+			Content: "The extent " + extent + " of the shapefile '" + file.Name + "' is outside the range of longitude (-180 to 180) and latitude (-90 to 90), although its .prj file declares a geographic coordinate system.",
+			Source:  repository,
+		}}
+	}
+	if box != nil && (!box.Contains(header.MinX, header.MinY) || !box.Contains(header.MaxX, header.MaxY)) {
+		return []structs.Message{{
+			Content: "The extent " + extent + " of the shapefile '" + file.Name + "' reaches outside the bounding box " + box.String() + ".",
+			Source:  repository,
+		}}
+	}
+	return nil
+}
+
+// shapefilePRJ returns the .prj file next to the main file of a shapefile, nil if there is none
+func shapefilePRJ(file structs.File, repository structs.Repository) *structs.File {
+	name := strings.TrimSuffix(file.Name, path.Ext(file.Name))
+	for i, other := range repository.Files {
+		if strings.EqualFold(other.Name, name+".prj") {
+			return &repository.Files[i]
+		}
+	}
+	return nil
+}
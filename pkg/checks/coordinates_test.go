@@ -0,0 +1,152 @@
+package checks
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func coordinatesConfig(boundingBox string) config.Config {
+	return config.Config{Tests: map[string]*config.TestConfig{
+		"HasPlausibleCoordinates": {KeywordArguments: []map[string]interface{}{{"bounding_box": boundingBox}}},
+	}}
+}
+
+// writeGeoFiles writes the files into a package, sorted by name
+func writeGeoFiles(t *testing.T, files map[string][]byte) structs.Repository {
+	dir := t.TempDir()
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var repository structs.Repository
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.Base(name))
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			t.Fatal(err)
+		}
+		repository.Files = append(repository.Files, structs.File{Path: path, Name: name})
+	}
+	return repository
+}
+
+func polygonShapefile(minX, minY, maxX, maxY float64) []byte {
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header, 9994)
+	binary.BigEndian.PutUint32(header[24:], 50)
+	binary.LittleEndian.PutUint32(header[28:], 1000)
+	binary.LittleEndian.PutUint32(header[32:], 5)
+	for i, value := range []float64{minX, minY, maxX, maxY} {
+		binary.LittleEndian.PutUint64(header[36+8*i:], math.Float64bits(value))
+	}
+	return header
+}
+
+const (
+	wgs84PRJ = `GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+	lv95PRJ  = `PROJCS["CH1903+_LV95",GEOGCS["GCS_CH1903+",DATUM["D_CH1903+",SPHEROID["Bessel_1841",6377397.155,299.1528128]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],PROJECTION["Hotine_Oblique_Mercator_Azimuth_Center"],UNIT["Meter",1.0]]`
+)
+
+func TestHasPlausibleCoordinates(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string][]byte
+		config   config.Config
+		expected []string
+	}{
+		{
+			name: "plausible",
+			files: map[string][]byte{
+				"stations.geojson": []byte(`{"type": "Point", "coordinates": [8.5417, 47.3769]}`),
+				"lakes.shp":        polygonShapefile(8.5, 47.2, 8.8, 47.4),
+				"lakes.prj":        []byte(wgs84PRJ),
+			},
+			config: coordinatesConfig("5.9,45.8,10.5,47.9"),
+		},
+		{
+			name: "swapped and projected",
+			files: map[string][]byte{
+				"stations.geojson": []byte(`{"type": "MultiPoint", "coordinates": [[8.5417, 47.3769], [47.3769, 8.5417], [2683000, 1247000]]}`),
+			},
+			expected: []string{"'stations.geojson' has 1 of 3 positions outside the range of longitude (-180 to 180) and latitude (-90 to 90), e.g. (2.683e+06, 1.247e+06), the coordinates may be projected or have longitude and latitude swapped."},
+		},
+		{
+			name: "outside the bounding box",
+			files: map[string][]byte{
+				"stations.geojson": []byte(`{"type": "MultiPoint", "coordinates": [[8.5417, 47.3769], [47.3769, 8.5417]]}`),
+				"data/lakes.shp":   polygonShapefile(8.5, 47.2, 18.8, 47.4),
+				"data/lakes.prj":   []byte(wgs84PRJ),
+			},
+			config: coordinatesConfig("5.9,45.8,10.5,47.9"),
+			expected: []string{
+				"The extent (8.5, 47.2) - (18.8, 47.4) of the shapefile 'data/lakes.shp' reaches outside the bounding box 5.9,45.8,10.5,47.9.",
+				"'stations.geojson' has 1 of 2 positions outside the bounding box 5.9,45.8,10.5,47.9, e.g. (47.3769, 8.5417).",
+			},
+		},
+		{
+			name: "projected",
+			files: map[string][]byte{
+				"stations.geojson": []byte(`{"type": "Point", "crs": {"type": "name", "properties": {"name": "urn:ogc:def:crs:EPSG::2056"}}, "coordinates": [2683000, 1247000]}`),
+				"lakes.shp":        polygonShapefile(2683000, 1247000, 2690000, 1250000),
+				"lakes.prj":        []byte(lv95PRJ),
+			},
+			config: coordinatesConfig("5.9,45.8,10.5,47.9"),
+		},
+		{
+			name: "projected without projection",
+			files: map[string][]byte{
+				"lakes.shp":  polygonShapefile(2683000, 1247000, 2690000, 1250000),
+				"rivers.shp": polygonShapefile(2683000, 1247000, 2690000, 1250000),
+				"rivers.prj": []byte(wgs84PRJ),
+			},
+			expected: []string{
+				"The shapefile 'lakes.shp' has no .prj file, its coordinate reference system is unknown.",
+				"The extent (2.683e+06, 1.247e+06) - (2.69e+06, 1.25e+06) of the shapefile 'rivers.shp' is outside the range of longitude (-180 to 180) and latitude (-90 to 90), although its .prj file declares a geographic coordinate system.",
+			},
+		},
+		{
+			name: "corrupt",
+			files: map[string][]byte{
+				"stations.geojson": []byte(`{"type": "Point", "coordinates": [8.5417`),
+				"lakes.shp":        []byte("lakes"),
+				"lakes.prj":        []byte(wgs84PRJ),
+			},
+			expected: []string{
+				"The shapefile 'lakes.shp' is corrupt: not a shapefile.",
+				"The GeoJSON file 'stations.geojson' is corrupt: unexpected end of JSON input.",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := writeGeoFiles(t, test.files)
+			var contents []string
+			for _, message := range HasPlausibleCoordinates(repository, test.config) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, contents)
+			}
+		})
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	box, err := ParseBoundingBox(" 5.9, 45.8, 10.5, 47.9")
+	if err != nil || box != (BoundingBox{MinLon: 5.9, MinLat: 45.8, MaxLon: 10.5, MaxLat: 47.9}) {
+		t.Errorf("unexpected bounding box %v, %v", box, err)
+	}
+	for _, text := range []string{"5.9,45.8,10.5", "5.9,45.8,10.5,north", "10.5,45.8,5.9,47.9", "5.9,45.8,10.5,147.9"} {
+		if _, err := ParseBoundingBox(text); err == nil {
+			t.Errorf("expected %q to be invalid", text)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// defaultDateFormats are the ISO 8601 formats, preferred unless preferred_formats is configured
+var defaultDateFormats = []string{"YYYY-MM-DD", "YYYYMMDD"}
+
+// numberRun finds numbers separated by -, . or _, which dates in file names are made of
+var numberRun = regexp.MustCompile(`\d+(?:[-._]\d+)*`)
+
+// numberSeparator separates the numbers of a run and the parts of a date format
+var numberSeparator = regexp.MustCompile(`[-._]`)
+
+// nameDate is a date found in a file name
+type nameDate struct {
+	Text   string // The date as written in the name, e.g. 31.12.2023
+	Format string // Its layout, e.g. DD.MM.YYYY
+}
+
+// isDate reports whether the numbers are a plausible date; four digit years are 1900 to 2099
+func isDate(year, month, day string) bool {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	if len(year) == 4 && (y < 1900 || y > 2099) {
+		return false
+	}
+	return m >= 1 && m <= 12 && d >= 1 && d <= 31
+}
+
+// layoutOf returns the layout of a date part, e.g. MM for "05" and M for "5"
+func layoutOf(part string, letter string) string {
+	return strings.Repeat(letter, len(part))
+}
+
+// dateFormat returns the layout of three numbers joined by separator, "" if they are no date.
+// Dates whose day and month could be swapped are taken as day first.
+func dateFormat(numbers []string, separator string) string {
+	first, second, third := numbers[0], numbers[1], numbers[2]
+	switch {
+	case len(first) == 4 && len(second) <= 2 && len(third) <= 2:
+		if isDate(first, second, third) {
+			return strings.Join([]string{"YYYY", layoutOf(second, "M"), layoutOf(third, "D")}, separator)
+		}
+	case len(third) == 4 && len(first) <= 2 && len(second) <= 2,
+		len(third) == 2 && len(first) == 2 && len(second) == 2:
+		if isDate(third, second, first) {
+			return strings.Join([]string{layoutOf(first, "D"), layoutOf(second, "M"), layoutOf(third, "Y")}, separator)
+		}
+		if isDate(third, first, second) {
+			return strings.Join([]string{layoutOf(first, "M"), layoutOf(second, "D"), layoutOf(third, "Y")}, separator)
+		}
+	}
+	return ""
+}
+
+// datesInName returns the dates in a file name, e.g. 2023-12-31, 20231231, 31.12.23 or 12_31_2023
+func datesInName(name string) []nameDate {
+	var dates []nameDate
+	for _, run := range numberRun.FindAllString(name, -1) {
+		numbers := numberSeparator.Split(run, -1)
+		separators := numberSeparator.FindAllString(run, -1)
+		for i := 0; i < len(numbers); i++ {
+			if len(numbers[i]) == 8 && isDate(numbers[i][:4], numbers[i][4:6], numbers[i][6:]) {
+				dates = append(dates, nameDate{Text: numbers[i], Format: "YYYYMMDD"})
+				continue
+			}
+			if i+2 >= len(numbers) || separators[i] != separators[i+1] {
+				continue
+			}
+			if format := dateFormat(numbers[i:i+3], separators[i]); format != "" {
+				dates = append(dates, nameDate{Text: strings.Join(numbers[i:i+3], separators[i]), Format: format})
+				i += 2
+			}
+		}
+	}
+	return dates
+}
+
+// IsDateFormat reports whether layout is a date format found by HasConsistentDateFormats, such as
+// YYYY-MM-DD, YYYYMMDD or DD.MM.YY
+func IsDateFormat(layout string) bool {
+	if layout == "YYYYMMDD" {
+		return true
+	}
+	parts := numberSeparator.Split(layout, -1)
+	separators := numberSeparator.FindAllString(layout, -1)
+	if len(parts) != 3 || separators[0] != separators[1] {
+		return false
+	}
+	isMonth := func(part string) bool { return part == "M" || part == "MM" }
+	isDay := func(part string) bool { return part == "D" || part == "DD" }
+	switch parts[2] {
+	case "YYYY", "YY":
+		return isDay(parts[0]) && isMonth(parts[1]) || isMonth(parts[0]) && isDay(parts[1])
+	}
+	return parts[0] == "YYYY" && isMonth(parts[1]) && isDay(parts[2])
+}
+
+// isPreferredFormat reports whether format is one of the preferred formats
+func isPreferredFormat(format string, preferred []string) bool {
+	for _, layout := range preferred {
+		if layout == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Dates in file names are written in a preferred format, ISO 8601 by default, and the same way
+// across the package
+func HasConsistentDateFormats(repository structs.Repository, config config.Config) []structs.Message {
+	preferred := defaultDateFormats
+	if test, ok := config.Tests["HasConsistentDateFormats"]; ok {
+		for _, argumentSet := range test.KeywordArguments {
+			if formats, ok := argumentSet["preferred_formats"].([]string); ok && len(formats) > 0 {
+				preferred = formats
+			}
+		}
+	}
+
+	var messages []structs.Message
+	filesByFormat := map[string]int{}
+	for _, file := range repository.Files {
+		formats := map[string]bool{}
+		for _, date := range datesInName(file.Name) {
+			formats[date.Format] = true
+			if !isPreferredFormat(date.Format, preferred) {
+				messages = append(messages, structs.Message{
+					Content: fmt.Sprintf("The date '%s' in the file name '%s' is written as %s, write dates as %s.", date.Text, file.Name, date.Format, preferred[0]),
+					Source:  repository,
+				})
+			}
+		}
+		for format := range formats {
+			filesByFormat[format]++
+		}
+	}
+
+	if len(filesByFormat) > 1 {
+		var formats []string
+		for format := range filesByFormat {
+			formats = append(formats, format)
+		}
+		sort.Slice(formats, func(i, j int) bool {
+			if filesByFormat[formats[i]] != filesByFormat[formats[j]] {
+				return filesByFormat[formats[i]] > filesByFormat[formats[j]]
+			}
+			return formats[i] < formats[j]
+		})
+		var counts []string
+		for _, format := range formats {
+			if filesByFormat[format] == 1 {
+				counts = append(counts, format+" (1 file)")
+			} else {
+				counts = append(counts, fmt.Sprintf("%s (%d files)", format, filesByFormat[format]))
+			}
+		}
+		messages = append(messages, structs.Message{
+			Content: "File names write dates in " + strconv.Itoa(len(formats)) + " formats: " + strings.Join(counts, ", ") + ", use one format in the package.",
+			Source:  repository,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestDatesInName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []nameDate
+	}{
+		{"results.csv", nil},
+		{"lake_2023-12-31.csv", []nameDate{{"2023-12-31", "YYYY-MM-DD"}}},
+		{"lake_20231231_1200.csv", []nameDate{{"20231231", "YYYYMMDD"}}},
+		{"report 31.12.23.pdf", []nameDate{{"31.12.23", "DD.MM.YY"}}},
+		{"report_12-31-2023.pdf", []nameDate{{"12-31-2023", "MM-DD-YYYY"}}},
+		{"report_5.6.2023.pdf", []nameDate{{"5.6.2023", "D.M.YYYY"}}},
+		{"2023-01-01_2023-02-01.nc", []nameDate{{"2023-01-01", "YYYY-MM-DD"}, {"2023-02-01", "YYYY-MM-DD"}}},
+		{"model_v1.2.3.py", nil},
+		{"model_v2.10.15.py", nil},
+		{"sample_2023-13-01.csv", nil},
+		{"ids_12345678.txt", nil},
+	}
+	for _, tt := range tests {
+		if dates := datesInName(tt.name); !reflect.DeepEqual(dates, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, dates)
+		}
+	}
+}
+
+func TestIsDateFormat(t *testing.T) {
+	for _, layout := range []string{"YYYY-MM-DD", "YYYYMMDD", "DD.MM.YYYY", "MM-DD-YY", "YYYY_M_D"} {
+		if !IsDateFormat(layout) {
+			t.Errorf("expected %s to be a date format", layout)
+		}
+	}
+	for _, layout := range []string{"", "YYYY-MM", "DD.MM-YYYY", "YYYY-DD-MM", "YY-MM-DD", "MM.MM.YYYY"} {
+		if IsDateFormat(layout) {
+			t.Errorf("expected %s not to be a date format", layout)
+		}
+	}
+}
+
+func TestHasConsistentDateFormats(t *testing.T) {
+	repository := structs.Repository{Files: []structs.File{
+		{Name: "lake_2023-01-31.csv"},
+		{Name: "lake_2023-02-28.csv"},
+		{Name: "river_20230131.csv"},
+		{Name: "notes 31.01.2023.txt"},
+		{Name: "readme.md"},
+	}}
+	tests := []struct {
+		name     string
+		config   config.Config
+		expected []string
+	}{
+		{
+			"ISO 8601 by default",
+			config.Config{},
+			[]string{
+				"The date '31.01.2023' in the file name 'notes 31.01.2023.txt' is written as DD.MM.YYYY, write dates as YYYY-MM-DD.",
+				"File names write dates in 3 formats: YYYY-MM-DD (2 files), DD.MM.YYYY (1 file), YYYYMMDD (1 file), use one format in the package.",
+			},
+		},
+		{
+			"Preferred formats",
+			config.Config{Tests: map[string]*config.TestConfig{
+				"HasConsistentDateFormats": {KeywordArguments: []map[string]interface{}{{"preferred_formats": []string{"YYYYMMDD"}}}},
+			}},
+			[]string{
+				"The date '2023-01-31' in the file name 'lake_2023-01-31.csv' is written as YYYY-MM-DD, write dates as YYYYMMDD.",
+				"The date '2023-02-28' in the file name 'lake_2023-02-28.csv' is written as YYYY-MM-DD, write dates as YYYYMMDD.",
+				"The date '31.01.2023' in the file name 'notes 31.01.2023.txt' is written as DD.MM.YYYY, write dates as YYYYMMDD.",
+				"File names write dates in 3 formats: YYYY-MM-DD (2 files), DD.MM.YYYY (1 file), YYYYMMDD (1 file), use one format in the package.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range HasConsistentDateFormats(repository, tt.config) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+
+	consistent := structs.Repository{Files: []structs.File{{Name: "a_2023-01-31.csv"}, {Name: "b_2024-06-01.csv"}}}
+	if messages := HasConsistentDateFormats(consistent, config.Config{}); messages != nil {
+		t.Errorf("expected no messages for consistent ISO dates, got %v", messages)
+	}
+}
@@ -0,0 +1,298 @@
+package checks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// environmentFileKind returns "pip" for requirements files, "conda" for conda environments and
+// "renv" for R lock files, "" for other files
+func environmentFileKind(name string) string {
+	base := strings.ToLower(filepath.Base(name))
+	switch {
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return "pip"
+	case base == "environment.yml" || base == "environment.yaml":
+		return "conda"
+	case base == "renv.lock":
+		return "renv"
+	}
+	return ""
+}
+
+// credentialURL matches URLs with a user name, password or token before the host
+var credentialURL = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://([^\s/@'"]+)@[^\s'"]*`)
+
+// localPath matches file URLs and absolute POSIX, home, Windows drive and UNC paths
+var localPath = regexp.MustCompile(`^(?:file:|/|~|[A-Za-z]:[\\/]|\\\\)`)
+
+// requirementName matches the package name at the start of a requirement with its extras
+var requirementName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(?:\[[^\]]*\])?`)
+
+// condaPin matches the version of a conda match spec, e.g. numpy=1.26, numpy==1.26 or numpy 1.26
+var condaPin = regexp.MustCompile(`(?:^|[^<>!~=])=|\s\d`)
+
+// pinVersions returns the pin_versions of HasSafeEnvironmentFiles, true if it is not set
+func pinVersions(config config.Config) bool {
+	if test := config.Tests["HasSafeEnvironmentFiles"]; test != nil {
+		for _, argumentSet := range test.KeywordArguments {
+			if pin, ok := argumentSet["pin_versions"].(bool); ok {
+				return pin
+			}
+		}
+	}
+	return true
+}
+
+// credentials returns the URL of the match with the password or token redacted, "" if the user
+// information is only a user name such as git or a placeholder such as ${TOKEN}
+func credentials(url string, userInfo string) string {
+	if strings.ContainsAny(userInfo, "$%") || !strings.Contains(userInfo, ":") && userInfo == "git" {
+		return ""
+	}
+	shown := redact(userInfo)
+	if user, password, found := strings.Cut(userInfo, ":"); found {
+		shown = user + ":" + redact(password)
+	}
+	return strings.Replace(url, userInfo, shown, 1)
+}
+
+// pipRequirement reads a line of a requirements file and returns the package it names, if its
+// version is pinned and the local path it refers to
+func pipRequirement(line string) (name string, pinned bool, local string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", false, ""
+	}
+	// Options such as -e, -f, -r or --index-url are followed by a path or URL
+	if strings.HasPrefix(fields[0], "-") {
+		target := ""
+		if _, value, found := strings.Cut(fields[0], "="); found {
+			target = value
+		} else if len(fields) > 1 {
+			target = fields[1]
+		} else if len(fields[0]) > 2 && fields[0][1] != '-' {
+			target = fields[0][2:]
+		}
+		if localPath.MatchString(target) {
+			local = target
+		}
+		return "", false, local
+	}
+	requirement, _, _ := strings.Cut(line, ";")
+	if localPath.MatchString(fields[0]) {
+		return "", false, fields[0]
+	}
+	// Direct references (name @ url) and URLs name no version
+	if name, url, found := strings.Cut(requirement, "@"); found && !strings.Contains(name, "://") {
+		if url = strings.TrimSpace(url); localPath.MatchString(url) {
+			local = url
+		}
+		return strings.TrimSpace(name), true, local
+	}
+	if strings.Contains(requirement, "://") {
+		return "", false, ""
+	}
+	name = requirementName.FindString(requirement)
+	return name, strings.Contains(requirement[len(name):], "=="), ""
+}
+
+// localPathMessage reports a dependency installed from a path on the computer of the author
+func localPathMessage(file structs.File, path string, line int) structs.Message {
+	return structs.Message{
+		Content: fmt.Sprintf("Local path '%s' does not exist on other computers, install the dependency from a public index or include it in the package.", path),
+		Source:  file,
+		Line:    line,
+	}
+}
+
+// Environment files (requirements.txt, environment.yml, renv.lock) refer to no local paths,
+// contain no credentials of package indexes and pin the versions of their dependencies
+func HasSafeEnvironmentFiles(file structs.File, config config.Config) []structs.Message {
+	kind := environmentFileKind(file.Name)
+	if kind == "" {
+		return nil
+	}
+	content, done := fileContent(file, config)
+	defer done()
+	data, err := content.Bytes()
+	if errors.Is(err, performance.ErrMemoryBudget) {
+		skipForMemory(file, config)
+		return nil
+	}
+	if err != nil {
+		skipFile(config, file, output.SkipReadError, "Error reading file: %v", err)
+		return nil
+	}
+
+	var messages []structs.Message
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		for _, match := range credentialURL.FindAllStringSubmatch(line, -1) {
+			if url := credentials(match[0], match[1]); url != "" {
+				messages = append(messages, structs.Message{
+					Content: fmt.Sprintf("The URL '%s' contains credentials and may disclose an internal package index, remove them before publishing.", url),
+					Source:  file,
+					Line:    i + 1,
+				})
+			}
+		}
+	}
+
+	var unpinned []string
+	switch kind {
+	case "pip":
+		unpinned, messages = checkRequirements(file, lines, messages)
+	case "conda":
+		unpinned, messages = checkCondaEnvironment(file, lines, messages)
+	case "renv":
+		messages = append(messages, checkRenvLock(file, data)...)
+	}
+
+	if len(unpinned) > 0 && pinVersions(config) {
+		content := fmt.Sprintf("The dependency '%s' has no pinned version, pin it so the environment can be recreated.", unpinned[0])
+		if len(unpinned) > 1 {
+			content = fmt.Sprintf("The dependencies '%s' have no pinned version, pin them so the environment can be recreated.", strings.Join(unpinned, "', '"))
+		}
+		messages = append(messages, structs.Message{Content: content, Source: file})
+	}
+	return messages
+}
+
+// withoutComment removes a comment starting at the line or after white space
+func withoutComment(line string) string {
+	for i, r := range line {
+		if r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// checkRequirements reports the local paths of a requirements file and returns its unpinned packages
+func checkRequirements(file structs.File, lines []string, messages []structs.Message) ([]string, []structs.Message) {
+	var unpinned []string
+	for i := 0; i < len(lines); i++ {
+		number := i + 1
+		line := withoutComment(lines[i])
+		// Lines ending in a backslash continue on the next line
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + " " + withoutComment(lines[i])
+		}
+		name, pinned, local := pipRequirement(line)
+		if local != "" {
+			messages = append(messages, localPathMessage(file, local, number))
+		}
+		if name != "" && !pinned {
+			unpinned = append(unpinned, name)
+		}
+	}
+	return unpinned, messages
+}
+
+// checkCondaEnvironment reports the local paths of a conda environment file and returns its
+// unpinned packages, those installed by pip included
+func checkCondaEnvironment(file structs.File, lines []string, messages []structs.Message) ([]string, []structs.Message) {
+	var unpinned []string
+	dependencies, pip := -1, -1 // Indentation of the dependencies and of the pip packages
+	for i, raw := range lines {
+		line := withoutComment(raw)
+		if line == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if line == "dependencies:" {
+			dependencies = indent
+			continue
+		}
+		if dependencies < 0 {
+			continue
+		}
+		if !strings.HasPrefix(line, "-") {
+			if indent <= dependencies {
+				dependencies, pip = -1, -1
+			}
+			continue
+		}
+		if pip >= 0 && indent <= pip {
+			pip = -1
+		}
+		item := strings.Trim(strings.TrimSpace(line[1:]), `"'`)
+		if item == "pip:" {
+			pip = indent
+			continue
+		}
+		if pip >= 0 {
+			name, pinned, local := pipRequirement(item)
+			if local != "" {
+				messages = append(messages, localPathMessage(file, local, i+1))
+			}
+			if name != "" && !pinned {
+				unpinned = append(unpinned, name)
+			}
+			continue
+		}
+		if localPath.MatchString(item) {
+			messages = append(messages, localPathMessage(file, item, i+1))
+			continue
+		}
+		// pip is listed to install the pip packages, its version does not matter
+		if item != "pip" && !strings.Contains(item, "://") && !condaPin.MatchString(item) {
+			if end := strings.IndexAny(item, " =<>!~["); end > 0 {
+				item = item[:end]
+			}
+			unpinned = append(unpinned, item)
+		}
+	}
+	return unpinned, messages
+}
+
+// renvLock is the part of an renv.lock file describing where the packages come from
+type renvLock struct {
+	Packages map[string]struct {
+		Source     string
+		RemoteType string
+		RemoteUrl  string
+	}
+}
+
+// checkRenvLock reports the R packages of an renv lock file installed from local sources
+func checkRenvLock(file structs.File, data []byte) []structs.Message {
+	var lock renvLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return []structs.Message{{Content: fmt.Sprintf("The renv lock file cannot be read, the file may be corrupt: %v.", err), Source: file}}
+	}
+	names := make([]string, 0, len(lock.Packages))
+	for name := range lock.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var messages []structs.Message
+	for _, name := range names {
+		pkg := lock.Packages[name]
+		if !strings.EqualFold(pkg.Source, "Local") && !strings.EqualFold(pkg.RemoteType, "local") {
+			continue
+		}
+		if pkg.RemoteUrl != "" {
+			messages = append(messages, localPathMessage(file, pkg.RemoteUrl, 0))
+			continue
+		}
+		messages = append(messages, structs.Message{
+			Content: fmt.Sprintf("The R package '%s' is installed from a local source, which does not exist on other computers.", name),
+			Source:  file,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,132 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// characterClasses are the names of the classes allowed_characters may list besides literal characters
+var characterClasses = map[string]func(r rune) bool{
+	"letters":       unicode.IsLetter,
+	"ascii_letters": func(r rune) bool { return r <= unicode.MaxASCII && unicode.IsLetter(r) },
+	"lowercase":     func(r rune) bool { return r >= 'a' && r <= 'z' },
+	"uppercase":     func(r rune) bool { return r >= 'A' && r <= 'Z' },
+	"digits":        func(r rune) bool { return r >= '0' && r <= '9' },
+	"space":         func(r rune) bool { return r == ' ' },
+}
+
+// reservedDeviceNames are the names Windows reserves for devices, also with an extension
+var reservedDeviceNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// fileNamePolicy is one keywordArguments set of MatchesFileNamePolicy
+type fileNamePolicy struct {
+	Name              string // Optional name shown in the messages
+	Pattern           string // Regular expression the whole name must match
+	MaxLength         int64  // Maximum number of characters, 0 for no limit
+	AllowedCharacters []string
+	ReservedNames     bool // Report the device names reserved by Windows
+
+	patterns *performance.PatternCache // Compiles Pattern once for the scan, see config.PatternCache
+}
+
+// fileNamePolicies returns the keywordArguments sets of MatchesFileNamePolicy
+func fileNamePolicies(cfg config.Config) []fileNamePolicy {
+	test := cfg.Tests["MatchesFileNamePolicy"]
+	if test == nil {
+		return nil
+	}
+	var policies []fileNamePolicy
+	for _, argumentSet := range test.KeywordArguments {
+		policy := fileNamePolicy{patterns: cfg.PatternCache()}
+		policy.Name, _ = argumentSet["name"].(string)
+		policy.Pattern, _ = argumentSet["pattern"].(string)
+		policy.MaxLength, _ = argumentSet["max_length"].(int64)
+		policy.AllowedCharacters, _ = argumentSet["allowed_characters"].([]string)
+		policy.ReservedNames, _ = argumentSet["reserved_names"].(bool)
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// allows reports whether the character is in allowed_characters, as a class or literally
+func (p fileNamePolicy) allows(r rune) bool {
+	for _, allowed := range p.AllowedCharacters {
+		if class, ok := characterClasses[allowed]; ok {
+			if class(r) {
+				return true
+			}
+		} else if strings.ContainsRune(allowed, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// violations describes how the name breaks the policy
+func (p fileNamePolicy) violations(name string) []string {
+	var violations []string
+	if p.Pattern != "" {
+		// Patterns were validated when the config was loaded, invalid ones are skipped
+		if re := p.patterns.Regexp("^(?:" + p.Pattern + ")$"); re != nil && !re.MatchString(name) {
+			violations = append(violations, fmt.Sprintf("File name '%s' does not match the pattern '%s'", name, p.Pattern))
+		}
+	}
+	if length := utf8.RuneCountInString(name); p.MaxLength > 0 && int64(length) > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("File name '%s' is %d characters long, more than %d", name, length, p.MaxLength))
+	}
+	if len(p.AllowedCharacters) > 0 {
+		var disallowed []rune
+		for _, r := range name {
+			if !p.allows(r) && !strings.ContainsRune(string(disallowed), r) {
+				disallowed = append(disallowed, r)
+			}
+		}
+		if len(disallowed) > 0 {
+			violations = append(violations, fmt.Sprintf("File name '%s' contains characters that are not allowed: '%s'", name, string(disallowed)))
+		}
+	}
+	if p.ReservedNames {
+		if device, reserved := reservedDevice(name); reserved {
+			violations = append(violations, "File name '"+name+"' is reserved for the device "+device+" on Windows")
+		}
+	}
+	return violations
+}
+
+// reservedDevice returns the device Windows reserves the name for, whatever its extension
+func reservedDevice(name string) (string, bool) {
+	base, _, _ := strings.Cut(name, ".")
+	for _, device := range reservedDeviceNames {
+		if strings.EqualFold(strings.TrimRight(base, " "), device) {
+			return device, true
+		}
+	}
+	return "", false
+}
+
+// File and folder names follow the policies configured for the institution
+func MatchesFileNamePolicy(file structs.File, config config.Config) []structs.Message {
+	// Files inside archives are named by their path
+	name := path.Base(strings.TrimSuffix(file.Name, "/"))
+	var messages []structs.Message
+	for _, policy := range fileNamePolicies(config) {
+		for _, violation := range policy.violations(name) {
+			if policy.Name != "" {
+				violation = policy.Name + ": " + violation
+			}
+			messages = append(messages, structs.Message{Content: violation + ".", Source: file})
+		}
+	}
+	return messages
+}
@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func policyConfig(policies ...map[string]interface{}) config.Config {
+	return config.Config{Tests: map[string]*config.TestConfig{
+		"MatchesFileNamePolicy": {KeywordArguments: policies},
+	}}
+}
+
+func TestMatchesFileNamePolicy(t *testing.T) {
+	cfg := policyConfig(
+		map[string]interface{}{"name": "Eawag", "pattern": "[a-z0-9_]+(\\.[a-z0-9]+)?", "max_length": int64(12)},
+		map[string]interface{}{"allowed_characters": []string{"ascii_letters", "digits", "._-"}, "reserved_names": true},
+	)
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected []string
+	}{
+		{"Valid", structs.File{Name: "lake_01.csv"}, nil},
+		{
+			"Pattern and length",
+			structs.File{Name: "Lake Data 2021.csv"},
+			[]string{
+				"Eawag: File name 'Lake Data 2021.csv' does not match the pattern '[a-z0-9_]+(\\.[a-z0-9]+)?'.",
+				"Eawag: File name 'Lake Data 2021.csv' is 18 characters long, more than 12.",
+				"File name 'Lake Data 2021.csv' contains characters that are not allowed: ' '.",
+			},
+		},
+		{
+			"Characters are reported once",
+			structs.File{Name: "a#b#c%é.txt"},
+			[]string{
+				"Eawag: File name 'a#b#c%é.txt' does not match the pattern '[a-z0-9_]+(\\.[a-z0-9]+)?'.",
+				"File name 'a#b#c%é.txt' contains characters that are not allowed: '#%é'.",
+			},
+		},
+		{
+			"Reserved device name",
+			structs.File{Name: "aux.txt"},
+			[]string{"File name 'aux.txt' is reserved for the device AUX on Windows."},
+		},
+		{
+			"Archive member uses its base name",
+			structs.File{Name: "raw/con/", ArchiveName: "data.zip"},
+			[]string{"File name 'con' is reserved for the device CON on Windows."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range MatchesFileNamePolicy(tt.file, cfg) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+
+	if messages := MatchesFileNamePolicy(structs.File{Name: "Anything Goes #1"}, config.Config{}); messages != nil {
+		t.Errorf("expected no messages without the test section, got %v", messages)
+	}
+}
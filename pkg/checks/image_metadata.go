@@ -0,0 +1,51 @@
+package checks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// imageSuffixes are the extensions of the images whose metadata is read
+var imageSuffixes = map[string]bool{".jpg": true, ".jpeg": true, ".tif": true, ".tiff": true, ".png": true}
+
+// Images carry no GPS position, serial numbers or author names in their EXIF and XMP metadata
+func HasNoPrivateImageMetadata(file structs.File, config config.Config) []structs.Message {
+	if !imageSuffixes[strings.ToLower(filepath.Ext(file.Name))] {
+		return nil
+	}
+	metadata, err := readers.ReadImageMetadata(file)
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		skipFile(config, file, output.SkipReadError, "Error reading the image metadata: %v", err)
+		return nil
+	}
+	// Files that are no images or corrupt are not this check's concern
+	if err != nil {
+		return nil
+	}
+
+	var disclosed []string
+	if metadata.GPS != "" {
+		disclosed = append(disclosed, "the GPS position "+metadata.GPS)
+	}
+	for _, serial := range metadata.SerialNumbers {
+		disclosed = append(disclosed, "the serial number '"+serial+"'")
+	}
+	for _, author := range metadata.Authors {
+		disclosed = append(disclosed, "the name '"+author+"'")
+	}
+	if len(disclosed) == 0 {
+		return nil
+	}
+	return []structs.Message{{
+		Content: "The image metadata contains " + strings.Join(disclosed, ", ") + ", remove it before publishing if it is private.",
+		Source:  file,
+	}}
+}
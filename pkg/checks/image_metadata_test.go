@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// writeJPEG writes a JPEG file with the XMP packet, none if it is empty
+func writeJPEG(t *testing.T, name string, xmp string) structs.File {
+	content := []byte{0xFF, 0xD8}
+	if xmp != "" {
+		segment := "http://ns.adobe.com/xap/1.0/\x00" + xmp
+		content = append(content, 0xFF, 0xE1)
+		content = binary.BigEndian.AppendUint16(content, uint16(len(segment)+2))
+		content = append(content, segment...)
+	}
+	content = append(content, 0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9)
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name}
+}
+
+func TestHasNoPrivateImageMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected []string
+	}{
+		{"No metadata", writeJPEG(t, "lake.jpg", ""), nil},
+		{
+			"Private metadata",
+			writeJPEG(t, "sampling.JPG", `<rdf:Description exif:GPSLatitude="47,24.26N" exif:GPSLongitude="8,36.573E" exifEX:BodySerialNumber="A123"><dc:creator>Jane Doe</dc:creator></rdf:Description>`),
+			[]string{"The image metadata contains the GPS position 47.40433, 8.60955, the serial number 'A123', the name 'Jane Doe', remove it before publishing if it is private."},
+		},
+		{"Other files", writeJPEG(t, "lake.txt", `<dc:creator>Jane Doe</dc:creator>`), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range HasNoPrivateImageMetadata(tt.file, config.Config{}) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/optimization"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// keywordRule is one keywordArguments set of IsFreeOfKeywords
+type keywordRule struct {
+	Name     string           // Optional name shown in the messages
+	Info     string           // Message text
+	Keywords []string         // Literal strings, matched case-insensitively
+	Patterns []*regexp.Regexp // Regular expressions
+	Redact   bool             // Show only the first and last characters of the matched values
+	// patterns keeps the keyword matcher for the scan the rule was read for, see config.PatternCache
+	patterns *performance.PatternCache
+	// Disjoint leaves out matches inside an earlier one, for rules built into pc
+	Disjoint bool
+	// Skip leaves out matches such as placeholders, for rules built into pc
+	Skip func(value string) bool
+	// Describe builds the message text of a match instead of Info, for rules built into pc
+	Describe func(value string) string
+	// hidden are the rules whose matches the snippets redact as well, see hidingSecrets
+	hidden []keywordRule
+}
+
+// keywordRules returns the keywordArguments sets of IsFreeOfKeywords.
+// Patterns were validated when the config was loaded, invalid ones are skipped.
+func keywordRules(cfg config.Config) []keywordRule {
+	test := cfg.Tests["IsFreeOfKeywords"]
+	if test == nil {
+		return nil
+	}
+	var rules []keywordRule
+	for _, argumentSet := range test.KeywordArguments {
+		rule := keywordRule{patterns: cfg.PatternCache()}
+		rule.Name, _ = argumentSet["name"].(string)
+		rule.Info, _ = argumentSet["info"].(string)
+		rule.Keywords, _ = argumentSet["keywords"].([]string)
+		rule.Redact, _ = argumentSet["redact"].(bool)
+		// Reports redacted as a whole redact every match
+		rule.Redact = rule.Redact || cfg.General != nil && cfg.General.Redact
+		patterns, _ := argumentSet["patterns"].([]string)
+		for _, pattern := range patterns {
+			if re := cfg.PatternCache().Regexp(pattern); re != nil {
+				rule.Patterns = append(rule.Patterns, re)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// hidingSecrets returns the rules with snippets that also redact the matches of the redacting
+// IsFreeOfKeywords rules, all of them in redacted reports, so a secret next to an absolute path
+// or the match of another rule is not shown either
+func hidingSecrets(cfg config.Config, rules []keywordRule) []keywordRule {
+	var secrets []keywordRule
+	for _, rule := range keywordRules(cfg) {
+		if rule.Redact {
+			secrets = append(secrets, rule)
+		}
+	}
+	if len(secrets) == 0 {
+		return rules
+	}
+	hiding := make([]keywordRule, len(rules))
+	for i, rule := range rules {
+		rule.hidden = secrets
+		hiding[i] = rule
+	}
+	return hiding
+}
+
+// keywordMatch is one occurrence of a keyword or pattern
+type keywordMatch struct {
+	Value   string
+	Line    int    // 1-based line of the match
+	Snippet string // Text around the match on its line, with the match marked »like this«
+}
+
+// snippetContext is the number of bytes shown on either side of a match
+const snippetContext = 30
+
+// locate returns the start and end offsets of all keyword and pattern matches in body, in order
+func (r keywordRule) locate(body []byte) [][]int {
+	var locations [][]int
+	// The fast matcher finds the positions of all keywords in one pass
+	for _, match := range optimization.GetMatcher(r.patterns, r.Keywords).FindAll(body) {
+		locations = append(locations, []int{match.Start, match.End})
+	}
+	for _, re := range r.Patterns {
+		locations = append(locations, re.FindAllIndex(body, -1)...)
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i][0] != locations[j][0] {
+			return locations[i][0] < locations[j][0]
+		}
+		return locations[i][1] < locations[j][1]
+	})
+	result := locations[:0]
+	for _, loc := range locations {
+		if loc[0] == loc[1] {
+			continue
+		}
+		if n := len(result); n > 0 && result[n-1][0] == loc[0] && result[n-1][1] == loc[1] {
+			continue
+		}
+		if n := len(result); r.Disjoint && n > 0 && loc[0] < result[n-1][1] {
+			continue
+		}
+		if r.Skip != nil && r.Skip(string(body[loc[0]:loc[1]])) {
+			continue
+		}
+		result = append(result, loc)
+	}
+	return result
+}
+
+// findingBudget limits the findings a check builds for one file, see maxFindingsPerCheck.
+// A nil budget is unlimited.
+type findingBudget struct {
+	left    int // Findings that may still be built
+	omitted int // Findings that were found after the budget was spent
+}
+
+// newFindingBudget returns the budget configured in [general], nil if findings are not limited
+func newFindingBudget(cfg config.Config) *findingBudget {
+	if cfg.General == nil || cfg.General.MaxFindingsPerCheck <= 0 {
+		return nil
+	}
+	return &findingBudget{left: cfg.General.MaxFindingsPerCheck}
+}
+
+// allow reports whether one more finding may be built, and counts it as omitted otherwise
+func (b *findingBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+	if b.left == 0 {
+		b.omitted++
+		return false
+	}
+	b.left--
+	return true
+}
+
+// apply records the omitted findings on the last message
+func (b *findingBudget) apply(messages []structs.Message) []structs.Message {
+	if b != nil && b.omitted > 0 && len(messages) > 0 {
+		messages[len(messages)-1].Omitted += b.omitted
+		b.omitted = 0
+	}
+	return messages
+}
+
+// find returns the matches in body that fit into budget; line is the line number body starts at
+func (r keywordRule) find(body []byte, line int, budget *findingBudget) []keywordMatch {
+	var matches []keywordMatch
+	offset := 0
+	for _, loc := range r.locate(body) {
+		if !budget.allow() {
+			continue
+		}
+		line += bytes.Count(body[offset:loc[0]], []byte("\n"))
+		offset = loc[0]
+		matches = append(matches, r.match(body, loc[0], loc[1], line))
+	}
+	return matches
+}
+
+// match describes the match at body[start:end]
+func (r keywordRule) match(body []byte, start, end, line int) keywordMatch {
+	value := string(body[start:end])
+	shown := value
+	if r.Redact {
+		shown = redact(value)
+	}
+
+	before := body[max(0, start-snippetContext):start]
+	if i := bytes.LastIndexByte(before, '\n'); i >= 0 {
+		before = before[i+1:]
+	}
+	for len(before) > 0 && !utf8.RuneStart(before[0]) {
+		before = before[1:]
+	}
+	after := body[end:min(len(body), end+snippetContext)]
+	if i := bytes.IndexByte(after, '\n'); i >= 0 {
+		after = after[:i]
+	}
+	for len(after) > 0 && !utf8.Valid(after) {
+		after = after[:len(after)-1]
+	}
+
+	snippet := strings.TrimLeft(r.redactContext(before), " \t") + "»" + shown + "«" + strings.TrimRight(r.redactContext(after), " \t\r")
+	snippet = strings.NewReplacer("\t", " ", "\r", " ").Replace(snippet)
+	return keywordMatch{Value: value, Line: line, Snippet: snippet}
+}
+
+// redactContext returns the text around a match with the further matches of the rule and the
+// matches of its hidden rules redacted
+func (r keywordRule) redactContext(text []byte) string {
+	redacted := r.redactMatches(text)
+	for _, hidden := range r.hidden {
+		redacted = hidden.redactMatches([]byte(redacted))
+	}
+	return redacted
+}
+
+// redactMatches returns the text around a match, with further matches of a redacting rule redacted
+// so the snippet does not show them either
+func (r keywordRule) redactMatches(text []byte) string {
+	if !r.Redact {
+		return string(text)
+	}
+	var redacted strings.Builder
+	offset := 0
+	for _, loc := range r.locate(text) {
+		if loc[0] < offset {
+			continue
+		}
+		redacted.Write(text[offset:loc[0]])
+		redacted.WriteString(redact(string(text[loc[0]:loc[1]])))
+		offset = loc[1]
+	}
+	redacted.Write(text[offset:])
+	return redacted.String()
+}
+
+// Large files are searched in chunks that overlap so matches spanning chunks are caught
+const (
+	fileChunkSize = 1024 * 1024
+	chunkOverlap  = 2048
+)
+
+// mapThreshold is the size from which files are mapped into memory instead of read in chunks
+var mapThreshold int64 = 64 * 1024 * 1024
+
+// findInFile is find for large files. Files of at least mapThreshold bytes are mapped into
+// memory where the platform allows it, other files are read in chunks.
+func (r keywordRule) findInFile(path string, budget *findingBudget) ([]keywordMatch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil && info.Size() >= mapThreshold {
+		// Falls back to reading chunks, e.g. on file systems that cannot be mapped
+		if data, unmap, err := readers.MapFile(file); err == nil {
+			defer unmap()
+			return r.findInMapped(data, budget)
+		}
+	}
+	return r.findInChunks(file, budget)
+}
+
+// findInChunks is find for a file read in chunks
+func (r keywordRule) findInChunks(file io.Reader, budget *findingBudget) ([]keywordMatch, error) {
+	var matches []keywordMatch
+	buffer := make([]byte, chunkOverlap+fileChunkSize)
+	kept := 0
+	line := 1 // Line at the start of the buffer
+	for {
+		n, err := io.ReadFull(file, buffer[kept:])
+		if n > 0 {
+			chunk := buffer[:kept+n]
+			matches = append(matches, r.findInChunk(chunk, kept, line, budget)...)
+			drop := len(chunk) - min(chunkOverlap, len(chunk))
+			line += bytes.Count(chunk[:drop], []byte("\n"))
+			kept = copy(buffer, chunk[drop:])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// findInMapped is find for a file mapped into memory. The chunks are windows of data, so nothing
+// is copied; the matcher lowercases a chunk at a time rather than the whole file.
+func (r keywordRule) findInMapped(data []byte, budget *findingBudget) (matches []keywordMatch, err error) {
+	// A file truncated while it is mapped faults instead of returning an error
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, fault := recovered.(interface{ Addr() uintptr }); !fault {
+				panic(recovered)
+			}
+			matches, err = nil, fmt.Errorf("file changed while it was read: %v", recovered)
+		}
+	}()
+
+	start, kept := 0, 0
+	line := 1 // Line at the start of the chunk
+	for {
+		end := min(len(data), start+kept+fileChunkSize)
+		chunk := data[start:end]
+		matches = append(matches, r.findInChunk(chunk, kept, line, budget)...)
+		if end == len(data) {
+			return matches, nil
+		}
+		drop := len(chunk) - min(chunkOverlap, len(chunk))
+		line += bytes.Count(chunk[:drop], []byte("\n"))
+		start += drop
+		kept = len(chunk) - drop
+	}
+}
+
+// findInChunk returns the matches in a chunk starting at line whose first kept bytes overlap
+// with the previous chunk
+func (r keywordRule) findInChunk(chunk []byte, kept, line int, budget *findingBudget) []keywordMatch {
+	var matches []keywordMatch
+	offset := 0
+	for _, loc := range r.locate(chunk) {
+		// Matches ending in the overlap were found with the previous chunk
+		if kept > 0 && loc[1] <= kept {
+			continue
+		}
+		if !budget.allow() {
+			continue
+		}
+		line += bytes.Count(chunk[offset:loc[0]], []byte("\n"))
+		offset = loc[0]
+		matches = append(matches, r.match(chunk, loc[0], loc[1], line))
+	}
+	return matches
+}
+
+// message reports a match of the rule
+func (r keywordRule) message(source structs.Source, m keywordMatch) structs.Message {
+	value := m.Value
+	if r.Redact {
+		value = redact(value)
+	}
+	content := r.Info + " '" + value + "'"
+	if r.Describe != nil {
+		content = r.Describe(value)
+	}
+	if r.Name != "" {
+		content = r.Name + ": " + content
+	}
+	return structs.Message{Content: content, Source: source, Line: m.Line, Snippet: m.Snippet}
+}
+
+// messages reports the matches in each part of body. Parts of binary files are slides, sheets,
+// paragraphs or tables, see partLocation; their line numbers are not reported.
+func (r keywordRule) messages(file structs.File, body [][]byte, isBinary bool, budget *findingBudget) []structs.Message {
+	var messages []structs.Message
+	for idx, entry := range body {
+		for _, m := range r.find(entry, 1, budget) {
+			message := r.message(file, m)
+			if isBinary {
+				message.Content += partLocation(file, idx)
+				message.Line = 0
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// partLocation names the part of an office file a finding is in: the slides of a .pptx are
+// numbered from 1 as in PowerPoint, the sheets, paragraphs or tables of other files by their index
+func partLocation(file structs.File, idx int) string {
+	if strings.HasSuffix(file.Path, ".pptx") {
+		return fmt.Sprintf(" on slide %d", idx+1)
+	}
+	return fmt.Sprintf(" in sheet/paragraph/table %d", idx)
+}
+
+// redact keeps only the first and last characters of a value, short values are hidden completely
+func redact(value string) string {
+	runes := []rune(value)
+	keep := 0
+	switch {
+	case len(runes) >= 8:
+		keep = 2
+	case len(runes) >= 4:
+		keep = 1
+	}
+	return string(runes[:keep]) + "****" + string(runes[len(runes)-keep:])
+}
@@ -0,0 +1,156 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// codepage is a single-byte encoding UTF-8 text is mistakenly read as, turning each byte of a
+// character into a character of its own: é (0xC3 0xA9) becomes Ã© in Windows-1252 and √© in
+// Mac Roman
+type codepage struct {
+	name  string
+	high  []rune        // Characters of the bytes 0x80 to 0xFF
+	bytes map[rune]byte // Byte of each character of high
+}
+
+func newCodepage(name, high string) *codepage {
+	cp := &codepage{name: name, high: []rune(high), bytes: make(map[rune]byte)}
+	for i, r := range cp.high {
+		cp.bytes[r] = byte(0x80 + i)
+	}
+	return cp
+}
+
+// windows1252 also stands for Latin-1, which differs in the control characters at 0x80 to 0x9F
+// only. The bytes Windows-1252 leaves undefined are read as those control characters.
+var windows1252 = func() *codepage {
+	cp := newCodepage("Windows-1252",
+		"€\u0081‚ƒ„…†‡ˆ‰Š‹Œ\u008dŽ\u008f\u0090‘’“”•–—˜™š›œ\u009džŸ"+
+			"\u00a0¡¢£¤¥¦§¨©ª«¬\u00ad®¯°±²³´µ¶·¸¹º»¼½¾¿ÀÁÂÃÄÅÆÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖ×ØÙÚÛÜÝÞßàáâãäåæçèéêëìíîïðñòóôõö÷øùúûüýþÿ")
+	for r := rune(0x80); r < 0xa0; r++ {
+		cp.bytes[r] = byte(r)
+	}
+	return cp
+}()
+
+var macRoman = newCodepage("Mac Roman",
+	"ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø"+
+		"¿¡¬√ƒ≈∆«»…\u00a0ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄€‹›ﬁﬂ‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔ\uf8ffÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ")
+
+var codepages = []*codepage{windows1252, macRoman}
+
+// class returns a regular expression class of the characters of the bytes from to to
+func (cp *codepage) class(from, to byte) string {
+	var class []rune
+	for r, b := range cp.bytes {
+		if b >= from && b <= to {
+			class = append(class, r)
+		}
+	}
+	slices.Sort(class)
+	return "[" + regexp.QuoteMeta(string(class)) + "]"
+}
+
+// pattern finds runs of characters that are the bytes of UTF-8 characters of two or three bytes
+func (cp *codepage) pattern() *regexp.Regexp {
+	continuation := cp.class(0x80, 0xbf)
+	return regexp.MustCompile("(?:" + cp.class(0xc2, 0xdf) + continuation + "|" + cp.class(0xe0, 0xef) + continuation + "{2})+")
+}
+
+// decode returns the UTF-8 text whose bytes text shows as characters of the codepage, false if
+// text is no such text or it decodes to characters that are unlikely to be meant
+func (cp *codepage) decode(text string) (string, bool) {
+	var encoded []byte
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			encoded = append(encoded, byte(r))
+			continue
+		}
+		b, ok := cp.bytes[r]
+		if !ok {
+			return "", false
+		}
+		encoded = append(encoded, b)
+	}
+	if !utf8.Valid(encoded) {
+		return "", false
+	}
+	for _, r := range string(encoded) {
+		if r >= utf8.RuneSelf && !plausibleRune(r) {
+			return "", false
+		}
+	}
+	return string(encoded), true
+}
+
+// plausibleRune reports whether r is a character of European text that mojibake often stands
+// for: accented letters, symbols such as ° and ±, quotes, dashes and €. Other characters are
+// more likely to be text that just looks like mojibake, such as «é».
+func plausibleRune(r rune) bool {
+	return (r >= 0xa0 && r <= 0x17f) || (r >= 0x2010 && r <= 0x203a) || r == 0x20ac || r == 0x2122
+}
+
+// maxMojibakeLayers limits how often text is decoded again, text converted to UTF-8 more often
+// is garbled beyond recognition
+const maxMojibakeLayers = 3
+
+// repairMojibake returns the text mojibake stands for, the codepage UTF-8 was read as and how
+// often, 0 if it is no mojibake
+func repairMojibake(mojibake string) (repaired, codepageName string, layers int) {
+	repaired = mojibake
+	for layers < maxMojibakeLayers {
+		decoded := ""
+		for _, cp := range codepages {
+			if text, ok := cp.decode(repaired); ok {
+				decoded = text
+				if layers == 0 {
+					codepageName = cp.name
+				}
+				break
+			}
+		}
+		if decoded == "" {
+			break
+		}
+		repaired = decoded
+		layers++
+	}
+	return repaired, codepageName, layers
+}
+
+// mojibakeRule finds UTF-8 text that was read in another encoding and saved as UTF-8 again,
+// possibly several times
+var mojibakeRule = keywordRule{
+	Patterns: []*regexp.Regexp{windows1252.pattern(), macRoman.pattern()},
+	Disjoint: true,
+	Skip: func(value string) bool {
+		_, _, layers := repairMojibake(value)
+		return layers == 0
+	},
+	Describe: func(value string) string {
+		repaired, codepageName, layers := repairMojibake(value)
+		times := ""
+		if layers > 1 {
+			times = fmt.Sprintf(" %d times", layers)
+		}
+		return "Mojibake '" + value + "' where '" + repaired + "' was meant, UTF-8 text was read as " + codepageName + " and encoded again" + times
+	},
+}
+
+// Text files contain no mojibake, the garbled characters of UTF-8 text that was read in another
+// encoding and saved again, e.g. Ã© or √© for é. Curators are warned that the encoding chain of
+// the file is broken, the text has to be exported again from its source.
+func IsFreeOfMojibake(file structs.File, config config.Config) []structs.Message {
+	return findInContent(file, config, []keywordRule{mojibakeRule})
+}
+
+// Files inside archives contain no mojibake
+func IsArchiveFreeOfMojibake(file structs.File, config config.Config) []structs.Message {
+	return findInArchive(file, config, "IsFreeOfMojibake", []keywordRule{mojibakeRule})
+}
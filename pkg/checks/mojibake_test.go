@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestRepairMojibake(t *testing.T) {
+	tests := []struct {
+		mojibake string
+		repaired string
+		codepage string
+		layers   int
+	}{
+		{"Ã©", "é", "Windows-1252", 1},
+		{"Ã¼Ã¶", "üö", "Windows-1252", 1},
+		{"â€™", "’", "Windows-1252", 1},
+		{"Â°", "°", "Windows-1252", 1},
+		{"ÃƒÂ¤", "ä", "Windows-1252", 2},
+		{"Ã\u0083Â¤", "ä", "Windows-1252", 2},
+		{"√±", "ñ", "Mac Roman", 1},
+		{"‚Äô", "’", "Mac Roman", 1},
+		{"«é", "«é", "", 0},
+		{"Ü“", "Ü“", "", 0},
+	}
+	for _, tt := range tests {
+		repaired, codepage, layers := repairMojibake(tt.mojibake)
+		if repaired != tt.repaired || codepage != tt.codepage || layers != tt.layers {
+			t.Errorf("%q: expected %q, %q, %d, got %q, %q, %d", tt.mojibake, tt.repaired, tt.codepage, tt.layers, repaired, codepage, layers)
+		}
+	}
+}
+
+func TestIsFreeOfMojibake(t *testing.T) {
+	cfg := config.Config{General: &config.GeneralConfig{MaxContentScanFileSize: 1 << 20}}
+
+	dir := t.TempDir()
+	clean := filepath.Join(dir, "clean.csv")
+	os.WriteFile(clean, []byte("Ort;Temperatur °C\nZürich;12.5\nGenève;13.1\nA Coruña;«élan» 15 ± 2\n"), 0644)
+	if messages := IsFreeOfMojibake(structs.ToFile(clean, "clean.csv", -1, ""), cfg); len(messages) != 0 {
+		t.Errorf("Expected no mojibake in correctly encoded text, got %v", messages)
+	}
+
+	garbled := filepath.Join(dir, "garbled.csv")
+	os.WriteFile(garbled, []byte("Ort;Temperatur\nZÃ¼rich;12.5\nA Coru√±a;15\n"), 0644)
+	messages := IsFreeOfMojibake(structs.ToFile(garbled, "garbled.csv", -1, ""), cfg)
+	expected := []string{
+		"Mojibake 'Ã¼' where 'ü' was meant, UTF-8 text was read as Windows-1252 and encoded again",
+		"Mojibake '√±' where 'ñ' was meant, UTF-8 text was read as Mac Roman and encoded again",
+	}
+	if len(messages) != len(expected) {
+		t.Fatalf("Expected %d messages, got %v", len(expected), messages)
+	}
+	for i, message := range messages {
+		if message.Content != expected[i] || message.Line != i+2 {
+			t.Errorf("Expected %q on line %d, got %q on line %d", expected[i], i+2, message.Content, message.Line)
+		}
+	}
+
+	// The readme of the test data was converted twice
+	messages = IsFreeOfMojibake(structs.ToFile("../../testdata/readme.txt", "readme.txt", -1, ""), cfg)
+	if len(messages) == 0 || !strings.Contains(messages[0].Content, "'ÃƒÂ¤' where 'ä' was meant") || !strings.HasSuffix(messages[0].Content, "encoded again 2 times") {
+		t.Errorf("Expected the mojibake of the readme to be found, got %v", messages)
+	}
+}
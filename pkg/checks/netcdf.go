@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// netCDFSuffixes are the extensions of NetCDF and HDF5 files
+var netCDFSuffixes = map[string]bool{".nc": true, ".nc4": true, ".cdf": true, ".netcdf": true, ".h5": true, ".hdf5": true, ".he5": true}
+
+// defaultGlobalAttributes are the global attributes the NetCDF conventions ask every file to have
+var defaultGlobalAttributes = []string{"title", "institution", "Conventions"}
+
+// NetCDF and HDF5 files can be read and have the required global attributes
+func HasNetCDFMetadata(file structs.File, config config.Config) []structs.Message {
+	if !netCDFSuffixes[strings.ToLower(filepath.Ext(file.Name))] {
+		return nil
+	}
+	required := defaultGlobalAttributes
+	if test, ok := config.Tests["HasNetCDFMetadata"]; ok {
+		for _, argumentSet := range test.KeywordArguments {
+			if attributes, ok := argumentSet["required_attributes"].([]string); ok {
+				required = attributes
+			}
+		}
+	}
+
+	header, err := readers.ReadNetCDFHeader(file)
+	if errors.Is(err, readers.ErrNotNetCDF) {
+		return []structs.Message{{Content: "'" + file.Name + "' is " + readers.ErrNotNetCDF.Error() + ", the file may be corrupt.", Source: file}}
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		skipFile(config, file, output.SkipReadError, "Error reading the NetCDF header: %v", err)
+		return nil
+	}
+	if err != nil {
+		format := header.Format
+		if format == "" {
+			format = "NetCDF"
+		}
+		return []structs.Message{{Content: "The " + format + " file '" + file.Name + "' is corrupt: " + err.Error() + ".", Source: file}}
+	}
+	// Attributes stored in a heap are not read, so none can be reported missing
+	if header.DenseAttributes {
+		return nil
+	}
+
+	var missing []string
+	for _, attribute := range required {
+		found := false
+		for _, name := range header.GlobalAttributes {
+			if strings.EqualFold(name, attribute) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, attribute)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	attributes := "attributes"
+	if len(missing) == 1 {
+		attributes = "attribute"
+	}
+	return []structs.Message{{
+		Content: "The " + header.Format + " file '" + file.Name + "' lacks the global " + attributes + " '" + strings.Join(missing, "', '") + "'.",
+		Source:  file,
+	}}
+}
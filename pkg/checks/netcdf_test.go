@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// writeNetCDF writes a NetCDF classic file with empty text attributes of the given names
+func writeNetCDF(t *testing.T, name string, attributes ...string) structs.File {
+	var b bytes.Buffer
+	u32 := func(n int) { binary.Write(&b, binary.BigEndian, uint32(n)) }
+	b.WriteString("CDF\x01")
+	u32(0)
+	u32(0)
+	u32(0)
+	u32(0x0C)
+	u32(len(attributes))
+	for _, attribute := range attributes {
+		u32(len(attribute))
+		b.WriteString(attribute)
+		b.Write(make([]byte, (4-len(attribute)%4)%4))
+		u32(2)
+		u32(0)
+	}
+	u32(0)
+	u32(0)
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name}
+}
+
+func TestHasNetCDFMetadata(t *testing.T) {
+	corrupt := filepath.Join(t.TempDir(), "corrupt.nc")
+	if err := os.WriteFile(corrupt, []byte("CDF\x01\x00\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	text := filepath.Join(t.TempDir(), "table.h5")
+	if err := os.WriteFile(text, []byte("id,value\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	required := config.Config{Tests: map[string]*config.TestConfig{
+		"HasNetCDFMetadata": {KeywordArguments: []map[string]interface{}{{"required_attributes": []string{"title", "source"}}}},
+	}}
+
+	tests := []struct {
+		name     string
+		file     structs.File
+		config   config.Config
+		expected []string
+	}{
+		{"Complete", writeNetCDF(t, "lake.nc", "title", "institution", "conventions"), config.Config{}, nil},
+		{"Other files", structs.File{Path: text, Name: "table.csv"}, config.Config{}, nil},
+		{
+			"Missing attributes",
+			writeNetCDF(t, "river.nc", "title"),
+			config.Config{},
+			[]string{"The NetCDF classic file 'river.nc' lacks the global attributes 'institution', 'Conventions'."},
+		},
+		{
+			"Configured attributes",
+			writeNetCDF(t, "river.nc", "title"),
+			required,
+			[]string{"The NetCDF classic file 'river.nc' lacks the global attribute 'source'."},
+		},
+		{
+			"Corrupt",
+			structs.File{Path: corrupt, Name: "corrupt.nc"},
+			config.Config{},
+			[]string{"The NetCDF classic file 'corrupt.nc' is corrupt: the header is truncated."},
+		},
+		{
+			"Not NetCDF",
+			structs.File{Path: text, Name: "table.h5"},
+			config.Config{},
+			[]string{"'table.h5' is neither a NetCDF nor an HDF5 file, the file may be corrupt."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range HasNetCDFMetadata(tt.file, tt.config) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// defaultMaxEmbeddedBytes is the size from which images and other data embedded into a notebook
+// are reported
+const defaultMaxEmbeddedBytes = 100 * 1000
+
+// maxEmbeddedBytes returns the max_embedded_bytes of HasCleanNotebookOutputs
+func maxEmbeddedBytes(config config.Config) int64 {
+	if test := config.Tests["HasCleanNotebookOutputs"]; test != nil {
+		for _, argumentSet := range test.KeywordArguments {
+			if limit, ok := argumentSet["max_embedded_bytes"].(int64); ok {
+				return limit
+			}
+		}
+	}
+	return defaultMaxEmbeddedBytes
+}
+
+// Executed Jupyter notebooks disclose no absolute paths or user names in their outputs and
+// tracebacks and embed no large images or other data
+func HasCleanNotebookOutputs(file structs.File, config config.Config) []structs.Message {
+	if !isNotebook(file.Name) {
+		return nil
+	}
+	content, done := fileContent(file, config)
+	defer done()
+	data, err := content.Bytes()
+	if errors.Is(err, performance.ErrMemoryBudget) {
+		skipForMemory(file, config)
+		return nil
+	}
+	if err != nil {
+		skipFile(config, file, output.SkipReadError, "Error reading file: %v", err)
+		return nil
+	}
+	cells, err := readers.ReadNotebook(data)
+	if err != nil {
+		return []structs.Message{{Content: fmt.Sprintf("The notebook cannot be read, the file may be corrupt: %v.", err), Source: file}}
+	}
+
+	var messages []structs.Message
+	limit := maxEmbeddedBytes(config)
+	paths := absolutePathRule(config)
+	for idx, cell := range cells {
+		for _, cellOutput := range cell.Outputs {
+			part := "output"
+			if cellOutput.Type == "error" {
+				part = "traceback"
+			}
+			seen := map[string]bool{}
+			for _, loc := range paths.locate([]byte(cellOutput.Text)) {
+				path := cellOutput.Text[loc[0]:loc[1]]
+				if seen[path] {
+					continue
+				}
+				seen[path] = true
+				messages = append(messages, structs.Message{
+					Content: fmt.Sprintf("%s in the %s of cell %d.", paths.Describe(path), part, idx+1),
+					Source:  file,
+				})
+			}
+			messages = append(messages, embeddedMessages(file, cellOutput.Blobs, "The output", idx, limit)...)
+		}
+		messages = append(messages, embeddedMessages(file, cell.Attachments, "An attachment", idx, limit)...)
+	}
+	return messages
+}
+
+// embeddedMessages reports the blobs of cell idx of more than limit bytes
+func embeddedMessages(file structs.File, blobs []readers.NotebookBlob, part string, idx int, limit int64) []structs.Message {
+	var messages []structs.Message
+	for _, blob := range blobs {
+		if int64(blob.Size) > limit {
+			messages = append(messages, structs.Message{
+				Content: fmt.Sprintf("%s of cell %d embeds %d bytes of %s, clear the outputs or publish the data as a separate file.", part, idx+1, blob.Size, blob.MIMEType),
+				Source:  file,
+			})
+		}
+	}
+	return messages
+}
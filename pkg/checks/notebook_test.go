@@ -0,0 +1,101 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// writeNotebook writes a notebook with the given cells in JSON
+func writeNotebook(t *testing.T, name string, cells ...string) structs.File {
+	content := `{"cells": [` + strings.Join(cells, ",") + `], "nbformat": 4, "nbformat_minor": 5}`
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name}
+}
+
+func TestHasCleanNotebookOutputs(t *testing.T) {
+	plot := `{"cell_type": "code", "source": "plot()", "outputs": [{"output_type": "display_data", "data": {"image/png": "` + strings.Repeat("A", 200) + `"}}]}`
+	smallLimit := config.Config{Tests: map[string]*config.TestConfig{
+		"HasCleanNotebookOutputs": {KeywordArguments: []map[string]interface{}{{"max_embedded_bytes": int64(100)}}},
+	}}
+
+	tests := []struct {
+		name     string
+		file     structs.File
+		config   config.Config
+		expected []string
+	}{
+		{
+			"Clean",
+			writeNotebook(t, "clean.ipynb", `{"cell_type": "code", "source": "open('/home/jdoe/x')", "outputs": [{"output_type": "stream", "text": "done\n"}]}`, plot),
+			config.Config{},
+			nil,
+		},
+		{
+			"Paths in outputs",
+			writeNotebook(t, "lake.ipynb",
+				`{"cell_type": "markdown", "source": "# Lake"}`,
+				`{"cell_type": "code", "source": "print(path)", "outputs": [{"output_type": "stream", "text": ["D:/lake/data.csv\n", "D:/lake/data.csv\n", "/home/user/x\n"]}]}`,
+				`{"cell_type": "code", "source": "load()", "outputs": [{"output_type": "error", "ename": "OSError", "evalue": "missing", "traceback": ["\u001b[0;31mOSError\u001b[0m", "File \u001b[0;32m/home/jdoe/lake.py:3\u001b[0m"]}]}`,
+			),
+			config.Config{},
+			[]string{
+				"Absolute path 'D:/lake/data.csv' in the output of cell 2.",
+				"Absolute path '/home/jdoe/lake.py' discloses the user name 'jdoe' in the traceback of cell 3.",
+			},
+		},
+		{
+			"Embedded data",
+			writeNotebook(t, "plots.ipynb", plot, `{"cell_type": "markdown", "source": "![x](attachment:x.jpg)", "attachments": {"x.jpg": {"image/jpeg": "`+strings.Repeat("A", 200)+`"}}}`),
+			smallLimit,
+			[]string{
+				"The output of cell 1 embeds 150 bytes of image/png, clear the outputs or publish the data as a separate file.",
+				"An attachment of cell 2 embeds 150 bytes of image/jpeg, clear the outputs or publish the data as a separate file.",
+			},
+		},
+		{
+			"Invalid notebook",
+			writeNotebook(t, "broken.ipynb", `{"cell_type": "code", "source": 1}`),
+			config.Config{},
+			[]string{"The notebook cannot be read, the file may be corrupt: invalid notebook: json: cannot unmarshal number into Go value of type string."},
+		},
+		{"Other files", writeNotebook(t, "notes.json", plot), smallLimit, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range HasCleanNotebookOutputs(tt.file, tt.config) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+}
+
+func TestFindInNotebookCells(t *testing.T) {
+	file := writeNotebook(t, "analysis.ipynb",
+		`{"cell_type": "markdown", "source": "# Analysis"}`,
+		`{"cell_type": "code", "source": "x = 1", "outputs": [{"output_type": "stream", "text": "saved to C:\\Users\\jdoe\\out.csv\n"}]}`,
+	)
+	var contents []string
+	for _, message := range IsFreeOfAbsolutePaths(file, pathsConfig()) {
+		if message.Line != 0 {
+			t.Errorf("expected no line for a match in a cell, got %d", message.Line)
+		}
+		contents = append(contents, message.Content)
+	}
+	expected := []string{`Absolute path 'C:\Users\jdoe\out.csv' discloses the user name 'jdoe' in cell 2`}
+	if !reflect.DeepEqual(contents, expected) {
+		t.Errorf("expected %q, got %q", expected, contents)
+	}
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// windowsIllegalChars are the characters Windows does not allow in file and folder names, besides
+// the control characters
+const windowsIllegalChars = `<>:"|?*\`
+
+// portabilityProblem describes why Windows cannot create a file or folder of the name, empty if it can
+func portabilityProblem(name string) string {
+	var illegal []string
+	for _, r := range name {
+		if r < 32 || strings.ContainsRune(windowsIllegalChars, r) {
+			if quoted := fmt.Sprintf("%q", r); !slices.Contains(illegal, quoted) {
+				illegal = append(illegal, quoted)
+			}
+		}
+	}
+	if len(illegal) > 0 {
+		return fmt.Sprintf("Name '%s' contains characters Windows does not allow: %s, the package cannot be extracted on Windows.", name, strings.Join(illegal, " "))
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Sprintf("Name '%s' ends with a dot or space, which Windows does not allow, the package cannot be extracted on Windows.", name)
+	}
+	if device, reserved := reservedDevice(name); reserved {
+		return fmt.Sprintf("File name '%s' is reserved for the device %s on Windows.", name, device)
+	}
+	return ""
+}
+
+// Files and folders have names Windows can create, so Windows users can extract the package.
+// Inside archives and below the package root the folders on the path are checked as well and
+// reported once for all their files.
+func HasPortableNames(file structs.File, config config.Config) []structs.Message {
+	var parts []string
+	root := config.PackageRoot()
+	switch {
+	case file.ArchiveName != "":
+		// Members of archives written on Windows may be separated by backslashes
+		parts = splitName(strings.TrimRight(file.Name, "/\\"))
+	case root != "":
+		parts = strings.Split(packagePath(file, config), "/")
+	default:
+		parts = []string{file.Name}
+	}
+	for i, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			continue
+		}
+		problem := portabilityProblem(part)
+		if problem == "" {
+			continue
+		}
+		source := file
+		if i < len(parts)-1 {
+			// The files in the folder report the folder, so their messages are merged
+			folder := strings.Join(parts[:i+1], "/")
+			source.Name = folder
+			source.DisplayName = folder
+			if file.ArchiveName == "" {
+				source.Path = filepath.Join(root, filepath.FromSlash(folder))
+				source.Size = 0
+			}
+		}
+		return []structs.Message{{Content: problem, Source: source}}
+	}
+	return []structs.Message{}
+}
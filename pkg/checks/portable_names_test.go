@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestHasPortableNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected string
+		source   string
+	}{
+		{"Regular file", structs.File{Name: "lake_2021-06-01.csv"}, "", ""},
+		{"Dot in the name", structs.File{Name: "v1.2.final.txt"}, "", ""},
+		{
+			"Time in the name",
+			structs.File{Name: "log 10:30.txt"},
+			"Name 'log 10:30.txt' contains characters Windows does not allow: ':', the package cannot be extracted on Windows.", "log 10:30.txt",
+		},
+		{
+			"Characters are reported once",
+			structs.File{Name: "what?<is>this?.csv"},
+			"Name 'what?<is>this?.csv' contains characters Windows does not allow: '?' '<' '>', the package cannot be extracted on Windows.", "what?<is>this?.csv",
+		},
+		{
+			"Control character",
+			structs.File{Name: "data\t.csv"},
+			"Name 'data\t.csv' contains characters Windows does not allow: '\\t', the package cannot be extracted on Windows.", "data\t.csv",
+		},
+		{
+			"Trailing dot",
+			structs.File{Name: "notes."},
+			"Name 'notes.' ends with a dot or space, which Windows does not allow, the package cannot be extracted on Windows.", "notes.",
+		},
+		{
+			"Reserved device name",
+			structs.File{Name: "nul.txt"},
+			"File name 'nul.txt' is reserved for the device NUL on Windows.", "nul.txt",
+		},
+		{
+			"Folder of an archive",
+			structs.File{Name: "raw/aux/data.csv", ArchiveName: "data.zip"},
+			"File name 'aux' is reserved for the device AUX on Windows.", "raw/aux",
+		},
+		{
+			"Folder with a trailing space in an archive written on Windows",
+			structs.File{Name: "raw \\data.csv", ArchiveName: "data.zip"},
+			"Name 'raw ' ends with a dot or space, which Windows does not allow, the package cannot be extracted on Windows.", "raw ",
+		},
+		{"Folder entry of an archive", structs.File{Name: "./raw/", ArchiveName: "data.zip"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HasPortableNames(tt.file, config.Config{})
+			if tt.expected == "" {
+				if len(result) != 0 {
+					t.Errorf("expected no messages, got %v", result)
+				}
+				return
+			}
+			if len(result) != 1 || result[0].Content != tt.expected {
+				t.Fatalf("expected %q, got %v", tt.expected, result)
+			}
+			if source := result[0].Source.(structs.File); source.Name != tt.source || source.ArchiveName != tt.file.ArchiveName {
+				t.Errorf("expected the source %q, got %+v", tt.source, source)
+			}
+		})
+	}
+}
+
+func TestHasPortableNamesFolders(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.Config{}.WithPackageRoot(root)
+	file := structs.File{Path: filepath.Join(root, "2021", "run 3.", "data.csv"), Name: "data.csv"}
+
+	result := HasPortableNames(file, cfg)
+	if len(result) != 1 {
+		t.Fatalf("expected the folder to be reported, got %v", result)
+	}
+	source := result[0].Source.(structs.File)
+	if source.Name != "2021/run 3." || source.Path != filepath.Join(root, "2021", "run 3.") {
+		t.Errorf("expected the folder as source, got %+v", source)
+	}
+
+	file.Path = filepath.Join(root, "2021", "data.csv")
+	if result := HasPortableNames(file, cfg); len(result) != 0 {
+		t.Errorf("expected no messages, got %v", result)
+	}
+}
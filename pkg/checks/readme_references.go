@@ -0,0 +1,207 @@
+package checks
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// referenceExtensions are the extensions of the file names recognized in the text of a readme,
+// names in links and images are recognized with any extension
+const referenceExtensions = `csv|tsv|txt|md|rst|pdf|docx?|xlsx?|ods|odt|json|xml|ya?ml|toml|ini|cfg|zip|tar|gz|tgz|bz2|xz|zst|7z|rar|` +
+	`nc|nc4|h5|hdf5|mat|rds|rdata|rda|sav|dta|parquet|feather|sqlite|db|sql|py|r|rmd|qmd|ipynb|m|jl|sh|sas|` +
+	`f90|c|cpp|java|tiff?|png|jpe?g|gif|svg|shp|dbf|shx|prj|gpkg|geojson|kml|las|laz|dat|log|html?`
+
+var (
+	// textReference matches file names and relative paths in the text, e.g. data/raw/lake.csv
+	textReference = regexp.MustCompile("(?i)(?:^|[\\s(\\[`'\"*_])((?:[\\w.-]+/)*[\\w-][\\w.-]*\\.(?:" + referenceExtensions + "))\\b")
+	// linkReference matches the targets of Markdown links and images and of HTML href and src attributes
+	linkReference = regexp.MustCompile(`\[[^\]\n]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)|(?:href|src)\s*=\s*"([^"]+)"`)
+)
+
+// readmeReferences returns the relative paths the readme refers to, in the order they appear
+func readmeReferences(content string) []string {
+	var references []string
+	seen := map[string]bool{}
+	add := func(reference string, link bool) {
+		if link {
+			if strings.Contains(reference, "://") || strings.HasPrefix(reference, "mailto:") || strings.HasPrefix(reference, "/") {
+				return
+			}
+			reference, _, _ = strings.Cut(reference, "#")
+			reference, _, _ = strings.Cut(reference, "?")
+			if unescaped, err := url.PathUnescape(reference); err == nil {
+				reference = unescaped
+			}
+		} else if first, _, found := strings.Cut(reference, "/"); found && strings.Contains(first, ".") && first != "." && first != ".." {
+			// Host names such as www.example.org/data.csv
+			return
+		}
+		if reference == "" || strings.ContainsAny(reference, "*?{}<>$") {
+			return
+		}
+		folder := strings.HasSuffix(reference, "/")
+		reference = path.Clean(reference)
+		if reference == "." || strings.HasPrefix(reference, "../") {
+			return
+		}
+		if folder {
+			reference += "/"
+		}
+		if !seen[reference] {
+			seen[reference] = true
+			references = append(references, reference)
+		}
+	}
+
+	type found struct {
+		offset    int
+		reference string
+		link      bool
+	}
+	var all []found
+	var links [][]int
+	for _, m := range linkReference.FindAllStringSubmatchIndex(content, -1) {
+		links = append(links, m[:2])
+		if m[2] >= 0 {
+			all = append(all, found{m[2], content[m[2]:m[3]], true})
+		} else {
+			all = append(all, found{m[4], content[m[4]:m[5]], true})
+		}
+	}
+	for _, m := range textReference.FindAllStringSubmatchIndex(content, -1) {
+		// The text and target of links are taken from the link
+		inLink := false
+		for _, link := range links {
+			inLink = inLink || m[2] >= link[0] && m[2] < link[1]
+		}
+		if !inLink {
+			all = append(all, found{m[2], content[m[2]:m[3]], false})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].offset < all[j].offset })
+	for _, f := range all {
+		add(f.reference, f.link)
+	}
+	return references
+}
+
+// packageEntry is a file or folder of the package with its slash separated path relative to the root
+type packageEntry struct {
+	path     string
+	isFolder bool
+	file     structs.File
+}
+
+// packageEntries returns the files and folders of the package relative to the folder the package
+// was collected from, or to the directory containing all its files
+func packageEntries(repository structs.Repository, config config.Config) []packageEntry {
+	root := config.PackageRoot()
+	if root == "" {
+		root = commonRoot(repository)
+	}
+	var entries []packageEntry
+	for _, file := range repository.Files {
+		relative, err := filepath.Rel(root, filepath.Clean(file.Path))
+		if err != nil || strings.HasPrefix(relative, "..") {
+			relative = file.Name
+		}
+		info, err := os.Stat(file.Path)
+		entries = append(entries, packageEntry{path: filepath.ToSlash(relative), isFolder: err == nil && info.IsDir(), file: file})
+	}
+	return entries
+}
+
+// referenceExists reports whether the reference names a file or folder of the package, matched
+// case-insensitively by its full path or the end of it, or a member of an archive of the package
+func referenceExists(reference string, entries []packageEntry) bool {
+	reference = strings.ToLower(strings.TrimSuffix(reference, "/"))
+	first, _, inFolder := strings.Cut(reference, "/")
+	for _, entry := range entries {
+		entryPath := strings.ToLower(entry.path)
+		if entryPath == reference || strings.HasSuffix(entryPath, "/"+reference) ||
+			strings.HasPrefix(entryPath, reference+"/") || strings.Contains(entryPath, "/"+reference+"/") {
+			return true
+		}
+		// Files in archives are referred to by the folder the archive unpacks into
+		if inFolder && readers.IsSupportedArchive(entryPath) {
+			name := strings.TrimSuffix(strings.TrimSuffix(path.Base(entryPath), path.Ext(entryPath)), ".tar")
+			if name == first {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The files and folders the readme refers to are part of the package
+func ReadMeReferencesExist(repository structs.Repository, config config.Config) []structs.Message {
+	var readme *structs.File
+	for i, file := range repository.Files {
+		if isReadMe(file) {
+			readme = &repository.Files[i]
+			break
+		}
+	}
+	// Without a readme the check is not applicable
+	if readme == nil {
+		return nil
+	}
+	content, err := os.ReadFile(readme.Path)
+	if err != nil {
+		skipFile(config, *readme, output.SkipReadError, "Error reading file: %v", err)
+		return nil
+	}
+
+	var messages []structs.Message
+	entries := packageEntries(repository, config)
+	for _, reference := range readmeReferences(string(content)) {
+		if strings.EqualFold(path.Base(reference), readme.Name) {
+			continue
+		}
+		if !referenceExists(reference, entries) {
+			messages = append(messages, structs.Message{Content: "The readme refers to '" + reference + "', which is not in the package.", Source: repository})
+		}
+	}
+
+	if !reportUnmentioned(config) {
+		return messages
+	}
+	lowerContent := strings.ToLower(string(content))
+	var unmentioned []string
+	for _, entry := range entries {
+		if entry.isFolder || isReadMe(entry.file) || strings.Contains(lowerContent, strings.ToLower(entry.file.Name)) {
+			continue
+		}
+		unmentioned = append(unmentioned, entry.path)
+	}
+	switch len(unmentioned) {
+	case 0:
+	case 1:
+		messages = append(messages, structs.Message{Content: "The readme does not mention the file '" + unmentioned[0] + "'.", Source: repository})
+	default:
+		messages = append(messages, structs.Message{Content: "The readme does not mention the files '" + strings.Join(unmentioned, "', '") + "'.", Source: repository})
+	}
+	return messages
+}
+
+// reportUnmentioned returns the report_unmentioned of ReadMeReferencesExist, false if it is not set
+func reportUnmentioned(config config.Config) bool {
+	if test := config.Tests["ReadMeReferencesExist"]; test != nil {
+		for _, argumentSet := range test.KeywordArguments {
+			if report, ok := argumentSet["report_unmentioned"].(bool); ok {
+				return report
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestReadmeReferences(t *testing.T) {
+	content := "# Lake data\n\nThe measurements are in data/raw/lake.csv and `results.xlsx`, see [the protocol](docs/Protocol%20v2.pdf#page=3).\n" +
+		"![Map](./figures/map.png \"Map\") [Data](data/) [Site](https://www.example.org/x.csv) [Top](#top)\n" +
+		"Downloaded from www.example.org/data.csv, e.g. as *.csv files; run analysis.R.\n" +
+		"<img src=\"figures/logo.svg\"> Mentioned twice: results.xlsx\n"
+	expected := []string{"data/raw/lake.csv", "results.xlsx", "docs/Protocol v2.pdf", "figures/map.png", "data/", "analysis.R", "figures/logo.svg"}
+	if references := readmeReferences(content); !reflect.DeepEqual(references, expected) {
+		t.Errorf("expected %q, got %q", expected, references)
+	}
+}
+
+func TestReadMeReferencesExist(t *testing.T) {
+	root := t.TempDir()
+	var files []structs.File
+	write := func(name, content string) {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, structs.File{Name: filepath.Base(path), Path: path})
+	}
+	write("README.md", "Data: data/lake.csv, DATA/River.csv, raw/2023/probe.csv, [code](code/) and model.py.\nSee also results/summary.pdf and [notes](notes.txt).\n")
+	write("data/lake.csv", "")
+	write("data/river.csv", "")
+	write("raw.zip", "")
+	write("code/analysis.R", "")
+	write("model.py", "")
+	write("extra/unused.txt", "")
+	repository := structs.Repository{Files: files}
+
+	tests := []struct {
+		name     string
+		config   config.Config
+		expected []string
+	}{
+		{
+			"Missing references",
+			config.Config{},
+			[]string{
+				"The readme refers to 'results/summary.pdf', which is not in the package.",
+				"The readme refers to 'notes.txt', which is not in the package.",
+			},
+		},
+		{
+			"Unmentioned files",
+			config.Config{Tests: map[string]*config.TestConfig{
+				"ReadMeReferencesExist": {KeywordArguments: []map[string]interface{}{{"report_unmentioned": true}}},
+			}},
+			[]string{
+				"The readme refers to 'results/summary.pdf', which is not in the package.",
+				"The readme refers to 'notes.txt', which is not in the package.",
+				"The readme does not mention the files 'raw.zip', 'code/analysis.R', 'extra/unused.txt'.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			for _, message := range ReadMeReferencesExist(repository, tt.config.WithPackageRoot(root)) {
+				contents = append(contents, message.Content)
+			}
+			if !reflect.DeepEqual(contents, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, contents)
+			}
+		})
+	}
+
+	if messages := ReadMeReferencesExist(structs.Repository{Files: files[1:]}, config.Config{}); messages != nil {
+		t.Errorf("expected no messages without a readme, got %v", messages)
+	}
+}
@@ -0,0 +1,496 @@
+package checks
+
+import (
+	"sync"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// Scope tells which subjects a check is applied to
+type Scope string
+
+const (
+	ScopeFile            Scope = "file"              // files of the package
+	ScopeArchiveFileList Scope = "archive-file-list" // names of the files inside archives
+	ScopeArchiveContent  Scope = "archive-content"   // contents of the files inside archives
+	ScopeRepository      Scope = "repository"        // the package as a whole
+	ScopeMetadata        Scope = "metadata"          // the CKAN metadata of the package
+)
+
+// Severity is the default importance of the issues a check reports
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ArgumentSpec describes a key of the keywordArguments sets a check reads
+type ArgumentSpec struct {
+	Name     string
+	Type     string // "string", "bool", "int" or "list" (of strings)
+	Required bool
+	// Alternatives are keys that satisfy Required in place of Name
+	Alternatives []string
+}
+
+// CheckInfo describes a built-in check
+type CheckInfo struct {
+	Name        string
+	Description string
+	Category    string
+	Severity    Severity
+	Scopes      []Scope
+	// ConfigName is the [test.<ConfigName>] section configuring the check, if it differs from Name
+	ConfigName string
+	// Arguments are the keys of each keywordArguments set; checks with required arguments need a [test.*] section
+	Arguments       []ArgumentSpec
+	FileCheck       func(file structs.File, config config.Config) []structs.Message
+	RepositoryCheck func(repository structs.Repository, config config.Config) []structs.Message
+	MetadataCheck   func(metadata structs.Metadata, config config.Config) []structs.Message
+}
+
+// Registry lists all built-in checks in the order they are run.
+// Name must match the function name, it is used to attribute messages to the check.
+var Registry = []CheckInfo{
+	{
+		Name:        "HasOnlyASCII",
+		Description: "File names contain only ASCII characters",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   HasOnlyASCII,
+	},
+	{
+		Name:        "HasNoWhiteSpace",
+		Description: "File names contain no spaces",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   HasNoWhiteSpace,
+	},
+	{
+		Name:        "IsFreeOfKeywords",
+		Description: "File contents contain none of the configured keywords (credentials, private paths, ...)",
+		Category:    "content",
+		Severity:    SeverityError,
+		Scopes:      []Scope{ScopeFile},
+		Arguments: []ArgumentSpec{
+			{Name: "keywords", Type: "list", Required: true, Alternatives: []string{"keywords_file", "keyword_lists", "patterns"}},
+			{Name: "keywords_file", Type: "string"},
+			{Name: "keyword_lists", Type: "list"},
+			{Name: "patterns", Type: "list"},
+			{Name: "name", Type: "string"},
+			{Name: "redact", Type: "bool"},
+			{Name: "info", Type: "string", Required: true},
+		},
+		FileCheck: IsFreeOfKeywords,
+	},
+	{
+		Name:        "IsFreeOfAbsolutePaths",
+		Description: "File contents contain no absolute Windows, home folder or UNC paths, which may disclose user names",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		FileCheck:   IsFreeOfAbsolutePaths,
+	},
+	{
+		Name:        "IsFreeOfMojibake",
+		Description: "Text files contain no mojibake such as Ã© or √© for é, the garbled characters of UTF-8 text that was read in another encoding and saved again",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		FileCheck:   IsFreeOfMojibake,
+	},
+	{
+		Name:        "IsValidName",
+		Description: "Files and folders are not on the list of disallowed names",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		Arguments: []ArgumentSpec{
+			{Name: "disallowed_names", Type: "list", Required: true},
+		},
+		FileCheck: IsValidName,
+	},
+	{
+		Name:        "HasNoJunkFiles",
+		Description: "Files and folders left behind by operating systems and tools (.DS_Store, Thumbs.db, __pycache__, ...) are not part of the package",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   HasNoJunkFiles,
+	},
+	{
+		Name:        "HasNoVCSMetadata",
+		Description: "The package contains no version control repositories (.git, .svn, .hg, ...) with the history of the project",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   HasNoVCSMetadata,
+	},
+	{
+		Name:        "HasFileNameSpecialChars",
+		Description: "File names contain no control or special characters",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		FileCheck:   HasFileNameSpecialChars,
+	},
+	{
+		Name:        "IsFileNameTooLong",
+		Description: "File names are at most 64 characters long, paths optionally at most 260",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		Arguments: []ArgumentSpec{
+			{Name: "full_path", Type: "bool"},
+			{Name: "max_path_length", Type: "int"},
+		},
+		FileCheck: IsFileNameTooLong,
+	},
+	{
+		Name:        "MatchesFileNamePolicy",
+		Description: "File and folder names follow the configured pattern, length, characters and reserved names",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		Arguments: []ArgumentSpec{
+			{Name: "name", Type: "string"},
+			{Name: "pattern", Type: "string"},
+			{Name: "max_length", Type: "int"},
+			{Name: "allowed_characters", Type: "list"},
+			{Name: "reserved_names", Type: "bool"},
+		},
+		FileCheck: MatchesFileNamePolicy,
+	},
+	{
+		Name:        "HasPortableNames",
+		Description: "File and folder names can be created on Windows: no <>:\"|?* or control characters, no trailing dot or space and no reserved device names such as CON or NUL",
+		Category:    "naming",
+		Severity:    SeverityError,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   HasPortableNames,
+	},
+	{
+		Name:        "IsFreeOfExecutables",
+		Description: "The package contains no programs or scripts (.exe, .dll, .bat, shell scripts, ELF, PE and Mach-O binaries)",
+		Category:    "content",
+		Severity:    SeverityError,
+		Scopes:      []Scope{ScopeFile, ScopeArchiveFileList},
+		FileCheck:   IsFreeOfExecutables,
+	},
+	{
+		Name:        "HasNetCDFMetadata",
+		Description: "NetCDF and HDF5 files can be read and have the global attributes title, institution and Conventions",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		Arguments: []ArgumentSpec{
+			{Name: "required_attributes", Type: "list"},
+		},
+		FileCheck: HasNetCDFMetadata,
+	},
+	{
+		Name:        "HasNoPrivateImageMetadata",
+		Description: "JPEG, TIFF and PNG images carry no GPS position, serial numbers or author names in their EXIF and XMP metadata",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		FileCheck:   HasNoPrivateImageMetadata,
+	},
+	{
+		Name:        "HasCleanNotebookOutputs",
+		Description: "Executed Jupyter notebooks disclose no absolute paths or user names in their outputs and tracebacks and embed no large images or other data",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		Arguments: []ArgumentSpec{
+			{Name: "max_embedded_bytes", Type: "int"},
+		},
+		FileCheck: HasCleanNotebookOutputs,
+	},
+	{
+		Name:        "HasSafeEnvironmentFiles",
+		Description: "Environment files (requirements.txt, environment.yml, renv.lock) refer to no local paths, contain no credentials of package indexes and pin the versions of their dependencies",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeFile},
+		Arguments: []ArgumentSpec{
+			{Name: "pin_versions", Type: "bool"},
+		},
+		FileCheck: HasSafeEnvironmentFiles,
+	},
+	{
+		Name:        "IsArchiveFreeOfKeywords",
+		Description: "Files inside archives contain none of the configured keywords",
+		Category:    "content",
+		Severity:    SeverityError,
+		Scopes:      []Scope{ScopeArchiveContent},
+		ConfigName:  "IsFreeOfKeywords",
+		FileCheck:   IsArchiveFreeOfKeywords,
+	},
+	{
+		Name:        "IsArchiveFreeOfAbsolutePaths",
+		Description: "Files inside archives contain no absolute paths",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeArchiveContent},
+		ConfigName:  "IsFreeOfAbsolutePaths",
+		FileCheck:   IsArchiveFreeOfAbsolutePaths,
+	},
+	{
+		Name:        "IsArchiveFreeOfMojibake",
+		Description: "Files inside archives contain no mojibake",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeArchiveContent},
+		ConfigName:  "IsFreeOfMojibake",
+		FileCheck:   IsArchiveFreeOfMojibake,
+	},
+	{
+		Name:        "IsArchiveIntact",
+		Description: "Archives can be opened and all their members read, they are neither corrupt nor truncated",
+		Category:    "structure",
+		Severity:    SeverityError,
+		Scopes:      []Scope{ScopeArchiveContent},
+		FileCheck:   IsArchiveIntact,
+	},
+	{
+		Name:        "HasNoRedundantCompression",
+		Description: "Archives hold more than one file and no files that are already compressed",
+		Category:    "structure",
+		Severity:    SeverityInfo,
+		Scopes:      []Scope{ScopeArchiveContent},
+		FileCheck:   HasNoRedundantCompression,
+	},
+	{
+		Name:            "HasReadme",
+		Description:     "The package contains a readme.md or readme.txt",
+		Category:        "documentation",
+		Severity:        SeverityError,
+		Scopes:          []Scope{ScopeRepository},
+		RepositoryCheck: HasReadme,
+	},
+	{
+		Name:            "ReadMeContainsTOC",
+		Description:     "The readme mentions every file of the package",
+		Category:        "documentation",
+		Severity:        SeverityWarning,
+		Scopes:          []Scope{ScopeRepository},
+		RepositoryCheck: ReadMeContainsTOC,
+	},
+	{
+		Name:        "ReadMeReferencesExist",
+		Description: "The files and folders the readme refers to are part of the package",
+		Category:    "documentation",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeRepository},
+		Arguments: []ArgumentSpec{
+			{Name: "report_unmentioned", Type: "bool"},
+		},
+		RepositoryCheck: ReadMeReferencesExist,
+	},
+	{
+		Name:        "HasValidFolderStructure",
+		Description: "The package has the required folders and none of the forbidden ones",
+		Category:    "structure",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeRepository},
+		Arguments: []ArgumentSpec{
+			{Name: "required_folders", Type: "list"},
+			{Name: "forbidden_folders", Type: "list"},
+		},
+		RepositoryCheck: HasValidFolderStructure,
+	},
+	{
+		Name:        "HasConsistentDateFormats",
+		Description: "Dates in file names are written in the preferred format, ISO 8601 by default, and the same way throughout the package",
+		Category:    "naming",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeRepository},
+		Arguments: []ArgumentSpec{
+			{Name: "preferred_formats", Type: "list"},
+		},
+		RepositoryCheck: HasConsistentDateFormats,
+	},
+	{
+		Name:        "HasPlausibleCoordinates",
+		Description: "GeoJSON files and shapefiles have longitudes and latitudes within their range and the configured bounding box, and shapefiles have a .prj file declaring their coordinate reference system",
+		Category:    "content",
+		Severity:    SeverityWarning,
+		Scopes:      []Scope{ScopeRepository},
+		Arguments: []ArgumentSpec{
+			{Name: "bounding_box", Type: "string"},
+		},
+		RepositoryCheck: HasPlausibleCoordinates,
+	},
+	{
+		Name:          "HasDescription",
+		Description:   "The CKAN package has a description",
+		Category:      "metadata",
+		Severity:      SeverityWarning,
+		Scopes:        []Scope{ScopeMetadata},
+		MetadataCheck: HasDescription,
+	},
+	{
+		Name:          "HasLicense",
+		Description:   "The CKAN package has a license",
+		Category:      "metadata",
+		Severity:      SeverityError,
+		Scopes:        []Scope{ScopeMetadata},
+		MetadataCheck: HasLicense,
+	},
+	{
+		Name:          "HasValidAuthorEmail",
+		Description:   "The author and maintainer emails of the CKAN package are not placeholders",
+		Category:      "metadata",
+		Severity:      SeverityWarning,
+		Scopes:        []Scope{ScopeMetadata},
+		MetadataCheck: HasValidAuthorEmail,
+	},
+	{
+		Name:          "IsMetadataFreeOfKeywords",
+		Description:   "The title and description of the CKAN package contain none of the configured keywords",
+		Category:      "content",
+		Severity:      SeverityError,
+		Scopes:        []Scope{ScopeMetadata},
+		ConfigName:    "IsFreeOfKeywords",
+		MetadataCheck: IsMetadataFreeOfKeywords,
+	},
+}
+
+// HasScope reports whether the check is applied to the given scope
+func (c CheckInfo) HasScope(scope Scope) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConfigName returns the name of the [test.*] section configuring the check
+func (c CheckInfo) GetConfigName() string {
+	if c.ConfigName != "" {
+		return c.ConfigName
+	}
+	return c.Name
+}
+
+// ConfigStatus describes how the check is configured in cfg.
+// Checks without a [test.*] section still run with their defaults.
+func (c CheckInfo) ConfigStatus(cfg config.Config) string {
+	if !cfg.IsCheckEnabled(c.Name) {
+		return "disabled (profile)"
+	}
+	test, ok := cfg.Tests[c.GetConfigName()]
+	if !ok {
+		return "defaults"
+	}
+	if len(test.Whitelist) > 0 {
+		return "configured (whitelist)"
+	}
+	if len(test.Blacklist) > 0 {
+		return "configured (blacklist)"
+	}
+	return "configured"
+}
+
+// Lookup returns the registered check with the given name
+func Lookup(name string) (CheckInfo, bool) {
+	for _, check := range Registry {
+		if check.Name == name {
+			return check, true
+		}
+	}
+	return CheckInfo{}, false
+}
+
+var (
+	severitiesMutex sync.RWMutex
+	severities      = config.Config{}
+)
+
+// SetSeverities makes the 'severity' and 'report_only' of the [test.*] sections of cfg override
+// what SeverityOf and IsReportOnly return, for the reports of scans with cfg
+func SetSeverities(cfg config.Config) {
+	severitiesMutex.Lock()
+	defer severitiesMutex.Unlock()
+	severities = config.Config{Tests: cfg.Tests}
+}
+
+// SeverityOf returns the severity of the named check, as set with SetSeverities. Rules and
+// plugins are not registered and report warnings by default.
+func SeverityOf(name string) Severity {
+	severitiesMutex.RLock()
+	defer severitiesMutex.RUnlock()
+	return ConfiguredSeverity(severities, name)
+}
+
+// ConfiguredSeverity returns the severity of the named check in cfg, that of its [test.*] section
+// if it sets one
+func ConfiguredSeverity(cfg config.Config, name string) Severity {
+	severity, configName := SeverityWarning, name
+	if check, ok := Lookup(name); ok {
+		severity, configName = check.Severity, check.GetConfigName()
+	}
+	if test, ok := cfg.Tests[configName]; ok && test.Severity != "" {
+		return Severity(test.Severity)
+	}
+	return severity
+}
+
+// IsReportOnly reports whether the issues of the named check are informational only, as set with
+// SetSeverities: they are reported but do not count towards notification thresholds
+func IsReportOnly(name string) bool {
+	severitiesMutex.RLock()
+	defer severitiesMutex.RUnlock()
+	return ConfiguredReportOnly(severities, name)
+}
+
+// ConfiguredReportOnly reports whether the [test.*] section of the named check in cfg sets
+// report_only
+func ConfiguredReportOnly(cfg config.Config, name string) bool {
+	configName := name
+	if check, ok := Lookup(name); ok {
+		configName = check.GetConfigName()
+	}
+	test, ok := cfg.Tests[configName]
+	return ok && test.ReportOnly
+}
+
+// FileChecks returns the file checks registered for scope, in registry order
+func FileChecks(scope Scope) []func(file structs.File, config config.Config) []structs.Message {
+	var result []func(file structs.File, config config.Config) []structs.Message
+	for _, check := range Registry {
+		if check.FileCheck != nil && check.HasScope(scope) {
+			result = append(result, check.FileCheck)
+		}
+	}
+	return result
+}
+
+// RepositoryChecks returns the repository checks, in registry order
+func RepositoryChecks() []func(repository structs.Repository, config config.Config) []structs.Message {
+	var result []func(repository structs.Repository, config config.Config) []structs.Message
+	for _, check := range Registry {
+		if check.RepositoryCheck != nil && check.HasScope(ScopeRepository) {
+			result = append(result, check.RepositoryCheck)
+		}
+	}
+	return result
+}
+
+// MetadataChecks returns the metadata checks, in registry order
+func MetadataChecks() []func(metadata structs.Metadata, config config.Config) []structs.Message {
+	var result []func(metadata structs.Metadata, config config.Config) []structs.Message
+	for _, check := range Registry {
+		if check.MetadataCheck != nil && check.HasScope(ScopeMetadata) {
+			result = append(result, check.MetadataCheck)
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package checks
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+func functionName(i interface{}) string {
+	parts := strings.Split(runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name(), ".")
+	return parts[len(parts)-1]
+}
+
+func TestRegistryNamesMatchFunctions(t *testing.T) {
+	seen := map[string]bool{}
+	for _, check := range Registry {
+		if seen[check.Name] {
+			t.Errorf("check %s is registered twice", check.Name)
+		}
+		seen[check.Name] = true
+
+		if check.Description == "" || check.Category == "" || check.Severity == "" || len(check.Scopes) == 0 {
+			t.Errorf("check %s has incomplete metadata: %+v", check.Name, check)
+		}
+
+		switch {
+		case check.FileCheck != nil:
+			if name := functionName(check.FileCheck); name != check.Name {
+				t.Errorf("check %s is registered with function %s", check.Name, name)
+			}
+		case check.RepositoryCheck != nil:
+			if name := functionName(check.RepositoryCheck); name != check.Name {
+				t.Errorf("check %s is registered with function %s", check.Name, name)
+			}
+		case check.MetadataCheck != nil:
+			if name := functionName(check.MetadataCheck); name != check.Name {
+				t.Errorf("check %s is registered with function %s", check.Name, name)
+			}
+		default:
+			t.Errorf("check %s has no function", check.Name)
+		}
+	}
+}
+
+func TestFileChecksByScope(t *testing.T) {
+	var names []string
+	for _, check := range FileChecks(ScopeArchiveFileList) {
+		names = append(names, functionName(check))
+	}
+	expected := []string{"HasOnlyASCII", "HasNoWhiteSpace", "IsValidName", "HasNoJunkFiles", "HasNoVCSMetadata", "IsFileNameTooLong", "MatchesFileNamePolicy", "HasPortableNames", "IsFreeOfExecutables"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if len(FileChecks(ScopeFile)) != 17 || len(FileChecks(ScopeArchiveContent)) != 5 || len(RepositoryChecks()) != 6 || len(MetadataChecks()) != 4 {
+		t.Error("unexpected number of registered checks")
+	}
+}
+
+func TestLookupAndConfigStatus(t *testing.T) {
+	check, ok := Lookup("IsArchiveFreeOfKeywords")
+	if !ok {
+		t.Fatal("IsArchiveFreeOfKeywords is not registered")
+	}
+	if check.GetConfigName() != "IsFreeOfKeywords" {
+		t.Errorf("expected config name IsFreeOfKeywords, got %s", check.GetConfigName())
+	}
+	if _, ok := Lookup("DoesNotExist"); ok {
+		t.Error("expected lookup of an unknown check to fail")
+	}
+	if SeverityOf("IsFreeOfKeywords") != SeverityError || SeverityOf("MyRule") != SeverityWarning {
+		t.Error("unexpected severities of a check and a rule")
+	}
+
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"IsFreeOfKeywords": {Whitelist: []string{".*"}},
+		"HasReadme":        {},
+	}}
+	if status := check.ConfigStatus(cfg); status != "configured (whitelist)" {
+		t.Errorf("unexpected status %q", status)
+	}
+	readme, _ := Lookup("HasReadme")
+	if status := readme.ConfigStatus(cfg); status != "configured" {
+		t.Errorf("unexpected status %q", status)
+	}
+	toc, _ := Lookup("ReadMeContainsTOC")
+	if status := toc.ConfigStatus(cfg); status != "defaults" {
+		t.Errorf("unexpected status %q", status)
+	}
+}
+
+func TestConfiguredSeverity(t *testing.T) {
+	defer SetSeverities(config.Config{})
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"IsFreeOfExecutables": {Severity: "warning"},
+		"IsFreeOfKeywords":    {Severity: "info"},
+		"MyRule":              {Severity: "error"},
+		"HasReadme":           {},
+	}}
+	tests := map[string]Severity{
+		"IsFreeOfExecutables":     SeverityWarning,
+		"IsArchiveFreeOfKeywords": SeverityInfo,
+		"MyRule":                  SeverityError,
+		"HasReadme":               SeverityError,
+		"OtherRule":               SeverityWarning,
+	}
+	for name, expected := range tests {
+		if severity := ConfiguredSeverity(cfg, name); severity != expected {
+			t.Errorf("ConfiguredSeverity(%s): expected %s, got %s", name, expected, severity)
+		}
+	}
+
+	if SeverityOf("IsFreeOfExecutables") != SeverityError {
+		t.Error("expected the registered severity before SetSeverities")
+	}
+	SetSeverities(cfg)
+	if SeverityOf("IsFreeOfExecutables") != SeverityWarning || SeverityOf("MyRule") != SeverityError {
+		t.Error("expected SeverityOf to return the severities set with SetSeverities")
+	}
+}
+
+func TestConfiguredReportOnly(t *testing.T) {
+	defer SetSeverities(config.Config{})
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"IsFreeOfKeywords": {ReportOnly: true},
+		"MyRule":           {ReportOnly: true},
+		"HasReadme":        {Severity: "info"},
+	}}
+	tests := map[string]bool{
+		"IsFreeOfKeywords":        true,
+		"IsArchiveFreeOfKeywords": true,
+		"MyRule":                  true,
+		"HasReadme":               false,
+		"OtherRule":               false,
+	}
+	for name, expected := range tests {
+		if reportOnly := ConfiguredReportOnly(cfg, name); reportOnly != expected {
+			t.Errorf("ConfiguredReportOnly(%s): expected %v, got %v", name, expected, reportOnly)
+		}
+	}
+
+	if IsReportOnly("IsFreeOfKeywords") {
+		t.Error("expected no report-only checks before SetSeverities")
+	}
+	SetSeverities(cfg)
+	if !IsReportOnly("IsFreeOfKeywords") || IsReportOnly("HasReadme") {
+		t.Error("expected IsReportOnly to return the report_only set with SetSeverities")
+	}
+}
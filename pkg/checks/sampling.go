@@ -0,0 +1,99 @@
+package checks
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// sampleWindow is a part of a file checked with the sample scan strategy
+type sampleWindow struct {
+	start  int64
+	length int64
+}
+
+// sampled reports whether a text file of size bytes is checked in samples rather than in full
+func sampled(cfg config.Config, size int64) bool {
+	general := cfg.General
+	return general != nil && general.ScanStrategy == config.ScanStrategySample &&
+		general.SampleWindow > 0 && size > general.SampleThreshold
+}
+
+// sampleWindows returns the windows of a file of size bytes that are checked: the first and last
+// window bytes and blocks of window bytes in between, one at a random position in each of blocks
+// equal parts of the rest of the file. The positions are drawn from seed, so every scan of a file
+// checks the same windows. A file too small for the windows to leave gaps is checked in one window.
+func sampleWindows(size, window int64, blocks int, seed int64) []sampleWindow {
+	if size <= (int64(blocks)+2)*window {
+		return []sampleWindow{{0, size}}
+	}
+	windows := []sampleWindow{{0, window}}
+	if blocks > 0 {
+		random := rand.New(rand.NewSource(seed))
+		part := (size - 2*window) / int64(blocks)
+		for i := int64(0); i < int64(blocks); i++ {
+			start := window + i*part + random.Int63n(part-window+1)
+			windows = append(windows, sampleWindow{start, window})
+		}
+	}
+	return append(windows, sampleWindow{size - window, window})
+}
+
+// sampleSeed returns the seed of the windows of file, the same for every scan of it
+func sampleSeed(file structs.File, size int64) int64 {
+	h := fnv.New64a()
+	io.WriteString(h, file.Name+"\x00"+strconv.FormatInt(size, 10))
+	return int64(h.Sum64())
+}
+
+// findInSamples is findInContent for a text file checked in samples. Matches in the first window
+// have their line number; the line of later matches is not known, their message names the byte the
+// window starts at instead. The file is listed in skipped as sampled with the bytes checked.
+func findInSamples(file structs.File, cfg config.Config, size int64, rules []keywordRule) []structs.Message {
+	var messages []structs.Message
+	f, err := os.Open(file.Path)
+	if err != nil {
+		logger.Warning("Error reading file '%s': %v", file.Path, err)
+		skipFile(cfg, file, output.SkipReadError, "Error reading file: %v", err)
+		return messages
+	}
+	defer f.Close()
+
+	windows := sampleWindows(size, cfg.General.SampleWindow, cfg.General.SampleBlocks, sampleSeed(file, size))
+	budget := newFindingBudget(cfg)
+	var checked int64
+	for _, window := range windows {
+		if cfg.Context().Err() != nil {
+			return budget.apply(messages)
+		}
+		for _, rule := range rules {
+			found, err := rule.findInChunks(io.NewSectionReader(f, window.start, window.length), budget)
+			if err != nil {
+				logger.Warning("Error reading file '%s': %v", file.Path, err)
+				skipFile(cfg, file, output.SkipReadError, "Error reading file: %v", err)
+				return budget.apply(messages)
+			}
+			for _, match := range found {
+				message := rule.message(file, match)
+				if window.start > 0 {
+					message.Content += fmt.Sprintf(" in the sample from byte %d", window.start)
+					message.Line = 0
+				}
+				messages = append(messages, message)
+			}
+		}
+		checked += window.length
+	}
+	if checked < size {
+		skipFile(cfg, file, output.SkipSampled, "Only %d of %d bytes were checked: the first and last %d bytes and %d blocks in between (scanStrategy = \"sample\").",
+			checked, size, cfg.General.SampleWindow, len(windows)-2)
+	}
+	return budget.apply(messages)
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestSampleWindows(t *testing.T) {
+	if windows := sampleWindows(500, 100, 3, 1); !reflect.DeepEqual(windows, []sampleWindow{{0, 500}}) {
+		t.Errorf("Expected a small file to be checked in one window, got %v", windows)
+	}
+
+	windows := sampleWindows(10000, 100, 4, 42)
+	if len(windows) != 6 || windows[0] != (sampleWindow{0, 100}) || windows[5] != (sampleWindow{9900, 100}) {
+		t.Fatalf("Expected the first and last 100 bytes and 4 blocks, got %v", windows)
+	}
+	for i := 1; i < len(windows); i++ {
+		if windows[i].start < windows[i-1].start+windows[i-1].length {
+			t.Errorf("Expected windows in order without overlap, got %v", windows)
+		}
+	}
+	if again := sampleWindows(10000, 100, 4, 42); !reflect.DeepEqual(again, windows) {
+		t.Errorf("Expected the same windows for the same seed, got %v and %v", windows, again)
+	}
+}
+
+func TestIsFreeOfKeywordsSampled(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	*cfg = cfg.WithSkippedFiles(output.NewSkippedFiles())
+	cfg.Tests["IsFreeOfKeywords"].Whitelist = []string{}
+	cfg.Tests["IsFreeOfKeywords"].Blacklist = []string{}
+	cfg.General.MaxContentScanFileSize = 1000
+	cfg.General.ScanStrategy = config.ScanStrategySample
+	cfg.General.SampleThreshold = 1000
+	cfg.General.SampleWindow = 100
+	cfg.General.SampleBlocks = 0
+
+	// Larger than the maxContentScanFileSize, the middle is not checked
+	filler := strings.Repeat("nothing to see here\n", 100)
+	path := tempFile([]byte("password = 1\n" + filler + "password = 2\n" + filler + "password = 3\n"))
+	defer os.Remove(path)
+
+	messages := IsFreeOfKeywords(structs.File{Path: path, Name: "large.log"}, *cfg)
+	if len(messages) != 2 {
+		t.Fatalf("Expected the matches at the start and end, got %v", messages)
+	}
+	if messages[0].Line != 1 || strings.Contains(messages[0].Content, "sample") {
+		t.Errorf("Expected the match at the start with its line, got %+v", messages[0])
+	}
+	if messages[1].Line != 0 || !strings.Contains(messages[1].Content, "in the sample from byte") {
+		t.Errorf("Expected the match at the end with the start of its sample, got %+v", messages[1])
+	}
+	skipped := cfg.SkippedFiles().Files()
+	if len(skipped) != 1 || skipped[0].Code != output.SkipSampled || !strings.Contains(skipped[0].Reason, "Only 200 of") {
+		t.Errorf("Expected the file to be listed as sampled, got %+v", skipped)
+	}
+
+	// Without the sample strategy the file is too large to be checked
+	cfg.SkippedFiles().Reset()
+	cfg.General.ScanStrategy = ""
+	if messages := IsFreeOfKeywords(structs.File{Path: path, Name: "large.log"}, *cfg); len(messages) != 0 {
+		t.Errorf("Expected no findings, got %v", messages)
+	}
+	if skipped := cfg.SkippedFiles().Files(); len(skipped) != 1 || skipped[0].Code != output.SkipTooLarge {
+		t.Errorf("Expected the file to be skipped as too large, got %+v", skipped)
+	}
+}
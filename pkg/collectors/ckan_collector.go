@@ -1,6 +1,7 @@
 package collectors
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -10,24 +11,19 @@ import (
 	"strings"
 
 	"github.com/eawag-rdm/pc/pkg/config"
-	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
 func Request(url, ckanToken string, verifyTLS bool) (string, error) {
+	return RequestContext(context.Background(), url, ckanToken, verifyTLS)
+}
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: !verifyTLS,
-			// If verifyTLS=false => InsecureSkipVerify=true
-		},
-	}
+// RequestContext is like Request but gives up when ctx is done, e.g. when the scan is cancelled
+func RequestContext(ctx context.Context, url, ckanToken string, verifyTLS bool) (string, error) {
 
-	client := &http.Client{
-		Transport: transport,
-	}
+	client := httpClient(verifyTLS)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
@@ -43,7 +39,7 @@ func Request(url, ckanToken string, verifyTLS bool) (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Println("Request URL:", url, "token:", ckanToken)
+		logger.Debug("Request to %s failed: %s", url, resp.Status)
 		return "", fmt.Errorf("request failed with status code %d. This might indicate the package is private and needs to be set to public", resp.StatusCode)
 	}
 
@@ -55,6 +51,20 @@ func Request(url, ckanToken string, verifyTLS bool) (string, error) {
 	return string(bodyBytes), nil
 }
 
+// httpClient returns a client for requests to CKAN, checking its certificate unless verifyTLS is false
+func httpClient(verifyTLS bool) *http.Client {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: !verifyTLS,
+			// If verifyTLS=false => InsecureSkipVerify=true
+		},
+	}
+
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
 // JSON string to map
 func JSONToMap(jsonStr string) (map[string]interface{}, error) {
 	var result map[string]interface{}
@@ -106,14 +116,14 @@ func getLocalResourcePath(resourceURL string, ckanStoragePath string) string {
 	}
 	parts := strings.Split(parsedURL.Path, "/")
 	if len(parts) <= 4 {
-		output.GlobalLogger.Warning("Error: resource URL has invalid format '%s' - are the resources restricted?", resourceURL)
+		logger.Warning("Error: resource URL has invalid format '%s' - are the resources restricted?", resourceURL)
 		return "" // Return empty string instead of panicking
 	}
 	resourceID := parts[4]
 
 	// Validate resourceID has at least 6 characters
 	if len(resourceID) < 6 {
-		output.GlobalLogger.Warning("Error: resource ID '%s' is too short (needs at least 6 characters)", resourceID)
+		logger.Warning("Error: resource ID '%s' is too short (needs at least 6 characters)", resourceID)
 		return "" // Return empty string instead of panicking
 	}
 
@@ -138,8 +148,47 @@ func getLocalResourcePath(resourceURL string, ckanStoragePath string) string {
 	return ckanStoragePath + localResourcePath
 }
 
-func CkanCollector(package_id string, config config.Config) ([]structs.File, error) {
+// Metadata modes of the CkanCollector, set with its 'metadata' attr
+const (
+	// MetadataModeCheck checks the metadata of the package along with its files
+	MetadataModeCheck = "check"
+	// MetadataModeOnly checks the metadata of the package but none of its files
+	MetadataModeOnly = "only"
+)
+
+// MetadataMode returns the metadata mode configured for the CkanCollector ("" if the metadata is
+// not checked)
+func MetadataMode(config config.Config) string {
+	if collector, ok := config.Collectors["CkanCollector"]; ok {
+		if mode, ok := collector.Attrs["metadata"].(string); ok {
+			return mode
+		}
+	}
+	return ""
+}
 
+// GetCKANMetadata returns the fields of the CKAN package the metadata checks look at
+func GetCKANMetadata(jsonMap map[string]interface{}) structs.Metadata {
+	result, _ := jsonMap["result"].(map[string]interface{})
+	field := func(key string) string {
+		value, _ := result[key].(string)
+		return value
+	}
+	return structs.Metadata{
+		Package:         field("name"),
+		Title:           field("title"),
+		Notes:           field("notes"),
+		LicenseID:       field("license_id"),
+		LicenseTitle:    field("license_title"),
+		Author:          field("author"),
+		AuthorEmail:     field("author_email"),
+		Maintainer:      field("maintainer"),
+		MaintainerEmail: field("maintainer_email"),
+	}
+}
+
+// packageShow requests the package from the CKAN API of the CkanCollector
+func packageShow(package_id string, config config.Config) (map[string]interface{}, error) {
 	collectorName := "CkanCollector"
 
 	urlAttr, ok := config.Collectors[collectorName].Attrs["url"].(string)
@@ -151,18 +200,65 @@ func CkanCollector(package_id string, config config.Config) ([]structs.File, err
 	token := config.Collectors[collectorName].Attrs["token"].(string)
 	verify := config.Collectors[collectorName].Attrs["verify"].(bool)
 
-	jsonStr, err := Request(url, token, verify)
+	jsonStr, err := RequestContext(config.Context(), url, token, verify)
 	if err != nil {
 		return nil, err
 	}
-	jsonMap, err := JSONToMap(jsonStr)
+	return JSONToMap(jsonStr)
+}
+
+// PackageOrganization returns the id and the name of the organization owning the CKAN package,
+// empty if it has none
+func PackageOrganization(package_id string, config config.Config) (string, string, error) {
+	jsonMap, err := packageShow(package_id, config)
 	if err != nil {
-		return nil, err
+		return "", "", err
+	}
+	result, _ := jsonMap["result"].(map[string]interface{})
+	id, _ := result["owner_org"].(string)
+	name := ""
+	if organization, ok := result["organization"].(map[string]interface{}); ok {
+		name, _ = organization["name"].(string)
+	}
+	return id, name, nil
+}
+
+// CkanCollector returns the resources of the CKAN package, as files in the CKAN storage or, with
+// the 'download' attr, downloaded into the directory set up by PrepareDownloads
+func CkanCollector(package_id string, config config.Config) ([]structs.File, error) {
+	_, files, err := CkanCollectorWithMetadata(package_id, config)
+	return files, err
+}
+
+// CkanCollectorWithMetadata is CkanCollector that also returns a copy of the config carrying the
+// metadata of the package if the 'metadata' attr asks to check it. In the "only" mode no files
+// are returned.
+func CkanCollectorWithMetadata(package_id string, config config.Config) (config.Config, []structs.File, error) {
+
+	collectorName := "CkanCollector"
+
+	jsonMap, err := packageShow(package_id, config)
+	if err != nil {
+		return config, nil, err
+	}
+
+	switch MetadataMode(config) {
+	case MetadataModeCheck:
+		config = config.WithMetadata(GetCKANMetadata(jsonMap))
+	case MetadataModeOnly:
+		return config.WithMetadata(GetCKANMetadata(jsonMap)), nil, nil
 	}
 
 	files, err := GetCKANResources(jsonMap)
 	if err != nil {
-		return nil, err
+		return config, nil, err
+	}
+	logger.Debug("Package '%s' has %d file resources", package_id, len(files))
+
+	// With the 'download' attr the resources are downloaded instead of read from the CKAN storage
+	if downloadsEnabled(config) {
+		files, err = downloadResources(config, files, resourceHashes(jsonMap))
+		return config, files, err
 	}
 
 	localStoragePath := config.Collectors[collectorName].Attrs["ckan_storage_path"].(string)
@@ -171,5 +267,5 @@ func CkanCollector(package_id string, config config.Config) ([]structs.File, err
 		files[i].Path = getLocalResourcePath(file.Path, localStoragePath)
 	}
 
-	return files, nil
+	return config, files, nil
 }
@@ -2,6 +2,7 @@ package collectors
 
 import (
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -140,3 +141,57 @@ func TestGetLocalResourcePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCkanCollectorWithMetadata(t *testing.T) {
+	pkg := map[string]interface{}{
+		"name":         "lake-ice",
+		"title":        "Lake ice",
+		"notes":        "Ice cover of Swiss lakes",
+		"license_id":   "cc-by",
+		"author_email": "jane.doe@example.com",
+		"resources": []interface{}{
+			map[string]interface{}{"name": "readme.md", "url": "https://ckan.example/dataset/lake-ice/resource/f46e74be-1c61/download/readme.md", "url_type": "upload", "size": 10},
+		},
+	}
+	server := newFakeCKAN(t, pkg, map[string]*http.Request{}, map[string][]byte{})
+	defer server.Close()
+	cfg := publishConfig(server.URL)
+	cfg.Collectors["CkanCollector"].Attrs["ckan_storage_path"] = "/var/lib/ckan"
+
+	scanConfig, files, err := CkanCollectorWithMetadata("lake-ice", cfg)
+	if err != nil || len(files) != 1 || scanConfig.Metadata() != nil {
+		t.Fatalf("Expected the files without metadata by default, got %v, %v (%v)", files, scanConfig.Metadata(), err)
+	}
+
+	cfg.Collectors["CkanCollector"].Attrs["metadata"] = MetadataModeCheck
+	scanConfig, files, err = CkanCollectorWithMetadata("lake-ice", cfg)
+	expected := structs.Metadata{Package: "lake-ice", Title: "Lake ice", Notes: "Ice cover of Swiss lakes", LicenseID: "cc-by", AuthorEmail: "jane.doe@example.com"}
+	if err != nil || len(files) != 1 || scanConfig.Metadata() == nil || *scanConfig.Metadata() != expected {
+		t.Fatalf("Expected the files and the metadata, got %v, %+v (%v)", files, scanConfig.Metadata(), err)
+	}
+
+	cfg.Collectors["CkanCollector"].Attrs["metadata"] = MetadataModeOnly
+	scanConfig, files, err = CkanCollectorWithMetadata("lake-ice", cfg)
+	if err != nil || len(files) != 0 || scanConfig.Metadata() == nil {
+		t.Errorf("Expected the metadata only, got %v, %+v (%v)", files, scanConfig.Metadata(), err)
+	}
+	if cfg.Metadata() != nil {
+		t.Error("Expected the config passed in to be left unchanged")
+	}
+}
+
+func TestPackageOrganization(t *testing.T) {
+	pkg := map[string]interface{}{
+		"name":         "lake-ice",
+		"owner_org":    "5f3c2a9e",
+		"organization": map[string]interface{}{"id": "5f3c2a9e", "name": "limnology"},
+		"resources":    []interface{}{},
+	}
+	server := newFakeCKAN(t, pkg, map[string]*http.Request{}, map[string][]byte{})
+	defer server.Close()
+
+	id, name, err := PackageOrganization("lake-ice", publishConfig(server.URL))
+	if err != nil || id != "5f3c2a9e" || name != "limnology" {
+		t.Errorf("Expected the organization 5f3c2a9e/limnology, got %q/%q (%v)", id, name, err)
+	}
+}
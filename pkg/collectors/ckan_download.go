@@ -0,0 +1,467 @@
+package collectors
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/eawag-rdm/pc/pkg/workspace"
+)
+
+// Defaults of the download attrs of the CkanCollector
+const (
+	defaultDownloadParallelism = 4
+	defaultDownloadRetries     = 3
+)
+
+// retryDelay is the wait before the first retry of a download, doubled for each further retry
+var retryDelay = 500 * time.Millisecond
+
+// downloadOptions are the attrs of the CkanCollector used for downloads
+type downloadOptions struct {
+	token       string
+	verifyTLS   bool
+	parallelism int
+	retries     int
+	workspace   *workspace.Workspace // Limits the bytes downloaded, see config.Workspace
+	stagingPath string               // Directory with local copies of the resources, see stagedCopy
+}
+
+// transientError is a download error worth retrying, such as a dropped connection or a 503
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// downloadsEnabled reports whether the CkanCollector downloads the resources instead of reading
+// them from the CKAN storage
+func downloadsEnabled(cfg config.Config) bool {
+	collector, ok := cfg.Collectors["CkanCollector"]
+	if !ok {
+		return false
+	}
+	download, _ := collector.Attrs["download"].(bool)
+	return download
+}
+
+// PrepareDownloads returns a copy of the config whose CkanCollector downloads into a new
+// directory in the workspace of the scan, or in the 'download_dir' attr if set, and a function
+// removing the directory with all downloads. The downloads count against the maxWorkspaceSize
+// either way; a config without a workspace gets one, which the function removes as well.
+// Without the 'download' attr the config is returned as is.
+func PrepareDownloads(cfg config.Config) (config.Config, func(), error) {
+	if !downloadsEnabled(cfg) {
+		return cfg, func() {}, nil
+	}
+	ws, ownWorkspace := cfg.Workspace(), false
+	if ws == nil {
+		ws, ownWorkspace = cfg.NewWorkspace(), true
+		cfg = cfg.WithWorkspace(ws)
+	}
+	var dir string
+	var err error
+	if parent, _ := cfg.Collectors["CkanCollector"].Attrs["download_dir"].(string); parent != "" {
+		dir, err = os.MkdirTemp(parent, "pc-downloads-")
+	} else {
+		dir, err = ws.Mkdir("downloads-")
+	}
+	if err != nil {
+		if ownWorkspace {
+			ws.Close()
+		}
+		return cfg, func() {}, fmt.Errorf("cannot create download directory: %w", err)
+	}
+	return cfg.WithDownloadDir(dir), func() {
+		ws.Remove(dir)
+		if ownWorkspace {
+			ws.Close()
+		}
+	}, nil
+}
+
+// readDownloadOptions reads the download attrs, numbers as written in TOML (int64) or JSON (float64)
+func readDownloadOptions(attrs map[string]interface{}) downloadOptions {
+	options := downloadOptions{parallelism: defaultDownloadParallelism, retries: defaultDownloadRetries}
+	options.token, _ = attrs["token"].(string)
+	options.verifyTLS, _ = attrs["verify"].(bool)
+	options.stagingPath, _ = attrs["staging_path"].(string)
+	number := func(key string) (int, bool) {
+		switch value := attrs[key].(type) {
+		case int64:
+			return int(value), true
+		case float64:
+			return int(value), true
+		}
+		return 0, false
+	}
+	if n, ok := number("download_parallelism"); ok && n > 0 {
+		options.parallelism = n
+	}
+	if n, ok := number("download_retries"); ok && n >= 0 {
+		options.retries = n
+	}
+	return options
+}
+
+// resourceHashes maps the URLs of the resources of the package to the hashes CKAN has for them
+func resourceHashes(jsonMap map[string]interface{}) map[string]string {
+	hashes := map[string]string{}
+	result, _ := jsonMap["result"].(map[string]interface{})
+	resources, _ := result["resources"].([]interface{})
+	for _, resource := range resources {
+		res, _ := resource.(map[string]interface{})
+		resourceURL, _ := res["url"].(string)
+		if hash, _ := res["hash"].(string); resourceURL != "" && hash != "" {
+			hashes[resourceURL] = hash
+		}
+	}
+	return hashes
+}
+
+// downloadProgress adds up the bytes of parallel downloads. The total starts at the sizes in the
+// CKAN metadata and is corrected with the Content-Length of each response.
+type downloadProgress struct {
+	mutex  sync.Mutex
+	done   int64
+	total  int64
+	report func(done, total int64)
+}
+
+func (p *downloadProgress) add(done, total int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.done += done
+	p.total += total
+	p.report(p.done, p.total)
+}
+
+// progressWriter reports the bytes written through it
+type progressWriter struct {
+	progress *downloadProgress
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.progress.add(int64(len(p)), 0)
+	return len(p), nil
+}
+
+// downloadResources downloads the resources of files, whose paths are their URLs, in parallel into
+// the download directory of the config. Resources with a matching copy in the 'staging_path' attr
+// are checked there instead. Resources that cannot be downloaded are reported as warnings and left
+// out.
+func downloadResources(cfg config.Config, files []structs.File, hashes map[string]string) ([]structs.File, error) {
+	options := readDownloadOptions(cfg.Collectors["CkanCollector"].Attrs)
+	options.workspace = cfg.Workspace()
+	parent := cfg.DownloadDir()
+	if parent == "" {
+		// Without PrepareDownloads the caller removes the downloads
+		parent, _ = cfg.Collectors["CkanCollector"].Attrs["download_dir"].(string)
+	}
+	dir, err := os.MkdirTemp(parent, "package-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create download directory: %w", err)
+	}
+
+	client := httpClient(options.verifyTLS)
+	progress := &downloadProgress{report: cfg.DownloadProgress()}
+	for _, file := range files {
+		progress.total += file.Size
+	}
+	progress.report(0, progress.total)
+
+	errs := make([]error, len(files))
+	downloaded := make([]structs.File, len(files))
+	slots := make(chan struct{}, options.parallelism)
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, file structs.File) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if local := stagedCopy(options.stagingPath, file, hashes[file.Path]); local != "" {
+				logger.Debug("Using the local copy '%s' of resource '%s' instead of downloading it", local, file.Name)
+				progress.add(0, -file.Size)
+				file.Path = local
+				downloaded[i] = file
+				return
+			}
+			target := filepath.Join(dir, strconv.Itoa(i), resourceFileName(file))
+			expected := file.Size
+			err := os.MkdirAll(filepath.Dir(target), 0700)
+			var size int64
+			if err == nil {
+				size, err = downloadFile(cfg.Context(), client, options, file.Path, target, &expected, progress)
+			}
+			if err == nil {
+				err = verifyHash(target, hashes[file.Path])
+			}
+			if err != nil {
+				// The progress counts the resources that were downloaded only
+				var written int64
+				if info, statErr := os.Stat(target); statErr == nil {
+					written = info.Size()
+				}
+				progress.add(-written, -expected)
+				options.workspace.Remove(target)
+				errs[i] = err
+				return
+			}
+			file.Path = target
+			file.Size = size
+			downloaded[i] = file
+		}(i, file)
+	}
+	wg.Wait()
+
+	if err := cfg.Context().Err(); err != nil {
+		return nil, err
+	}
+	// The package does not fit into the workspace, checking some of the resources would hide that
+	for _, err := range errs {
+		if errors.Is(err, workspace.ErrDiskLimit) {
+			return nil, fmt.Errorf("cannot download the resources: %w", err)
+		}
+	}
+	var result []structs.File
+	for i, file := range files {
+		if errs[i] != nil {
+			logger.Warning("Skipping file, the download failed: '%s' (path: '%s'). %v", file.Name, file.Path, errs[i])
+			cfg.SkippedFiles().Add(output.SkippedFile{
+				Filename: file.Name,
+				Path:     file.Path,
+				Code:     output.SkipDownloadFailed,
+				Reason:   fmt.Sprintf("The download failed: %v", errs[i]),
+			})
+			continue
+		}
+		result = append(result, downloaded[i])
+	}
+	if len(result) == 0 && len(files) > 0 {
+		return nil, fmt.Errorf("none of the %d resources could be downloaded: %w", len(files), errors.Join(errs...))
+	}
+	return result, nil
+}
+
+// resourceFileName returns the name of the downloaded file, taken from the URL of the resource
+func resourceFileName(file structs.File) string {
+	if parsed, err := url.Parse(file.Path); err == nil {
+		if name := path.Base(parsed.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+	return "resource" + file.Suffix
+}
+
+// downloadFile downloads resourceURL to target and returns its size. Transient errors are retried
+// with backoff, each retry resumes where the previous attempt stopped if the server supports it.
+// expected is the size of the resource, from the CKAN metadata until a response tells it.
+func downloadFile(ctx context.Context, client *http.Client, options downloadOptions, resourceURL, target string, expected *int64, progress *downloadProgress) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		size, err := fetch(ctx, client, options, resourceURL, target, expected, progress)
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || attempt >= options.retries {
+			return size, err
+		}
+		select {
+		case <-time.After(retryDelay << attempt):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// fetch makes one attempt to download resourceURL to target, continuing a partial download left
+// by an earlier attempt. expected is updated with the Content-Length of the response.
+func fetch(ctx context.Context, client *http.Client, options downloadOptions, resourceURL, target string, expected *int64, progress *downloadProgress) (int64, error) {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", resourceURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if options.token != "" {
+		req.Header.Set("Authorization", options.token)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, &transientError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		// The server sends the whole file, the partial download is started over
+		if offset > 0 {
+			if err := file.Truncate(0); err != nil {
+				return 0, err
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return 0, err
+			}
+			progress.add(-offset, 0)
+			options.workspace.Release(offset)
+			offset = 0
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 && offset == *expected:
+		// The previous attempt got the whole file
+		return offset, nil
+	default:
+		err := fmt.Errorf("request failed with status code %d", resp.StatusCode)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return 0, &transientError{err}
+		}
+		return 0, err
+	}
+
+	if resp.ContentLength >= 0 {
+		progress.add(0, offset+resp.ContentLength-*expected)
+		*expected = offset + resp.ContentLength
+	}
+	n, err := io.Copy(io.MultiWriter(options.workspace.Writer(file), progressWriter{progress}), resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if errors.Is(err, workspace.ErrDiskLimit) {
+			return 0, err
+		}
+		return 0, &transientError{err}
+	}
+	return offset + n, nil
+}
+
+// hashAlgorithms are the hashes CKAN metadata may hold, also recognized by the length of the
+// hex digest if the algorithm is not given
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// parseHash reads a hash like "sha256:ab12..." or a bare hex digest, nil if it is not recognized
+func parseHash(text string) (func() hash.Hash, string) {
+	text = strings.TrimSpace(text)
+	if algorithm, digest, ok := strings.Cut(text, ":"); ok {
+		return hashAlgorithms[strings.ToLower(strings.ReplaceAll(algorithm, "-", ""))], digest
+	}
+	if _, err := hex.DecodeString(text); err != nil {
+		return nil, ""
+	}
+	for _, newHash := range hashAlgorithms {
+		if newHash().Size()*2 == len(text) {
+			return newHash, text
+		}
+	}
+	return nil, ""
+}
+
+// verifyHash checks the file at path against the hash of the CKAN metadata, if it has a known one
+func verifyHash(path, expected string) error {
+	newHash, digest := parseHash(expected)
+	if newHash == nil {
+		return nil
+	}
+	matches, err := hashMatches(path, newHash, digest)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return fmt.Errorf("the downloaded file does not match the hash '%s' in the CKAN metadata", expected)
+	}
+	return nil
+}
+
+// hashMatches reports whether the digest of the file at path is digest
+func hashMatches(path string, newHash func() hash.Hash, digest string) (bool, error) {
+	actual, err := fileDigest(path, newHash)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(actual, digest), nil
+}
+
+// fileDigest returns the hex digest of the file at path
+func fileDigest(path string, newHash func() hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stagedCopy returns the local copy of the resource of file in stagingPath, the 'staging_path'
+// attr, if it has the hash of the CKAN metadata, so it is checked in place instead of downloaded.
+// The copy is looked up by the file name of the resource URL and by the resource name. Resources
+// without a known hash or with a copy of another size or content are downloaded ("" is returned).
+func stagedCopy(stagingPath string, file structs.File, expected string) string {
+	if stagingPath == "" {
+		return ""
+	}
+	newHash, digest := parseHash(expected)
+	if newHash == nil {
+		return ""
+	}
+	for _, name := range []string{resourceFileName(file), file.Name} {
+		if name == "" || name != filepath.Base(name) {
+			continue
+		}
+		candidate := filepath.Join(stagingPath, name)
+		info, err := os.Stat(candidate)
+		if err != nil || !info.Mode().IsRegular() || (file.Size > 0 && info.Size() != file.Size) {
+			continue
+		}
+		if matches, err := hashMatches(candidate, newHash, digest); err == nil && matches {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,342 @@
+package collectors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/workspace"
+)
+
+// newDownloadCKAN serves a package with resources at /download/<name>. The first request for
+// "flaky.txt" is cut off halfway and its second one fails with 503; the Range headers of its
+// requests are recorded.
+func newDownloadCKAN(t *testing.T, resources map[string]string, hashes map[string]string, ranges *[]string) *httptest.Server {
+	var mutex sync.Mutex
+	flakyRequests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/api/3/action/package_show" {
+			var list []interface{}
+			for name, content := range resources {
+				list = append(list, map[string]interface{}{
+					"name":     name,
+					"url":      server.URL + "/download/" + name,
+					"url_type": "upload",
+					"size":     len(content),
+					"hash":     hashes[name],
+				})
+			}
+			list = append(list, map[string]interface{}{"name": "missing.txt", "url": server.URL + "/download/missing.txt", "url_type": "upload", "size": 10})
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": map[string]interface{}{"resources": list}})
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/download/")
+		content, ok := resources[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if name == "flaky.txt" {
+			mutex.Lock()
+			flakyRequests++
+			attempt := flakyRequests
+			*ranges = append(*ranges, r.Header.Get("Range"))
+			mutex.Unlock()
+			switch attempt {
+			case 1:
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.Write([]byte(content[:len(content)/2]))
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			case 2:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		http.ServeContent(w, r, name, time.Time{}, strings.NewReader(content))
+	}))
+	return server
+}
+
+// downloadConfig loads the attrs of the CkanCollector from a pc.toml, as the scan does
+func downloadConfig(t *testing.T, url string, dir string) config.Config {
+	t.Helper()
+	attrs := fmt.Sprintf("url = %q\ntoken = \"secret-token\"\nverify = true\nckan_storage_path = \"\"\ndownload = true\ndownload_dir = %q\ndownload_parallelism = 2\ndownload_retries = 5\n", url, dir)
+	path := filepath.Join(t.TempDir(), "pc.toml")
+	if err := os.WriteFile(path, []byte("[collector.CkanCollector.attrs]\n"+attrs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return *cfg
+}
+
+func TestReadDownloadOptions(t *testing.T) {
+	cfg := downloadConfig(t, "https://ckan.example", "")
+	options := readDownloadOptions(cfg.Collectors["CkanCollector"].Attrs)
+	if options.parallelism != 2 || options.retries != 5 || options.token != "secret-token" || !options.verifyTLS {
+		t.Errorf("expected the options of the pc.toml, got %+v", options)
+	}
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCkanCollectorDownloads(t *testing.T) {
+	defer func(delay time.Duration) { retryDelay = delay }(retryDelay)
+	retryDelay = time.Millisecond
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	resources := map[string]string{
+		"data.csv":  "id,value\n1,2\n",
+		"flaky.txt": strings.Repeat("0123456789", 1000),
+		"wrong.txt": "tampered",
+	}
+	hashes := map[string]string{
+		"data.csv":  "sha256:" + sha256Hex(resources["data.csv"]),
+		"flaky.txt": sha256Hex(resources["flaky.txt"]),
+		"wrong.txt": sha256Hex("original"),
+	}
+	var ranges []string
+	server := newDownloadCKAN(t, resources, hashes, &ranges)
+	defer server.Close()
+
+	cfg, removeDownloads, err := PrepareDownloads(downloadConfig(t, server.URL, t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mutex sync.Mutex
+	var done, total int64
+	cfg = cfg.WithSkippedFiles(output.NewSkippedFiles()).WithDownloadProgress(func(d, t int64) {
+		mutex.Lock()
+		done, total = d, t
+		mutex.Unlock()
+	})
+
+	files, err := CkanCollector("package", cfg)
+	if err != nil {
+		t.Fatalf("CkanCollector failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected the 2 resources downloaded intact, got %+v", files)
+	}
+	for _, file := range files {
+		if !strings.HasPrefix(file.Path, cfg.DownloadDir()) {
+			t.Errorf("Expected %s in the download directory", file.Path)
+		}
+		content, err := os.ReadFile(file.Path)
+		if err != nil || string(content) != resources[file.Name] || file.Size != int64(len(content)) {
+			t.Errorf("Unexpected download of %s: %d bytes (%v)", file.Name, len(content), err)
+		}
+	}
+	if len(ranges) != 3 || ranges[0] != "" || ranges[2] != "bytes=5000-" {
+		t.Errorf("Expected the retry to resume the partial download, got Range headers %q", ranges)
+	}
+	if done != total {
+		t.Errorf("Expected the progress to reach the total, got %d of %d", done, total)
+	}
+
+	var failed []string
+	for _, message := range output.GlobalLogger.GetMessages() {
+		if message.Level == "warning" && strings.Contains(message.Message, "the download failed") {
+			failed = append(failed, message.Message)
+		}
+	}
+	if len(failed) != 2 || !strings.Contains(strings.Join(failed, "\n"), "'missing.txt'") || !strings.Contains(strings.Join(failed, "\n"), "does not match the hash") {
+		t.Errorf("Expected warnings for the missing and the tampered resource, got %q", failed)
+	}
+	skipped := cfg.SkippedFiles().Files()
+	if len(skipped) != 2 || skipped[0].Code != output.SkipDownloadFailed || skipped[1].Code != output.SkipDownloadFailed {
+		t.Errorf("Expected the missing and the tampered resource to be recorded as skipped, got %+v", skipped)
+	}
+
+	removeDownloads()
+	if _, err := os.Stat(cfg.DownloadDir()); !os.IsNotExist(err) {
+		t.Errorf("Expected the downloads to be removed, got %v", err)
+	}
+}
+
+func TestCkanCollectorDownloadsFail(t *testing.T) {
+	defer func(delay time.Duration) { retryDelay = delay }(retryDelay)
+	retryDelay = time.Millisecond
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	server := newDownloadCKAN(t, map[string]string{}, nil, nil)
+	defer server.Close()
+	if _, err := CkanCollector("package", downloadConfig(t, server.URL, t.TempDir())); err == nil || !strings.Contains(err.Error(), "none of the 1 resources") {
+		t.Errorf("Expected an error if no resource can be downloaded, got %v", err)
+	}
+}
+
+func TestPrepareDownloads(t *testing.T) {
+	cfg := downloadConfig(t, "https://ckan.example", t.TempDir())
+	cfg.Collectors["CkanCollector"].Attrs["download"] = false
+	same, removeDownloads, err := PrepareDownloads(cfg)
+	removeDownloads()
+	if err != nil || same.DownloadDir() != "" {
+		t.Errorf("Expected no download directory without the download attr, got %q (%v)", same.DownloadDir(), err)
+	}
+
+	cfg.Collectors["CkanCollector"].Attrs["download_dir"] = filepath.Join(t.TempDir(), "missing", "dir")
+	cfg.Collectors["CkanCollector"].Attrs["download"] = true
+	if _, _, err := PrepareDownloads(cfg); err == nil {
+		t.Error("Expected an error for a download directory that cannot be created")
+	}
+}
+
+func TestCkanCollectorDownloadsWorkspace(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	resources := map[string]string{"data.csv": "id,value\n1,2\n", "large.txt": strings.Repeat("0123456789", 100)}
+	server := newDownloadCKAN(t, resources, nil, nil)
+	defer server.Close()
+
+	// Without download_dir the resources are downloaded into the workspace of the scan
+	parent := t.TempDir()
+	cfg := downloadConfig(t, server.URL, "")
+	cfg.General = &config.GeneralConfig{WorkspaceDir: parent, MaxWorkspaceSize: 2000}
+	ws := cfg.NewWorkspace()
+	cfg, removeDownloads, err := PrepareDownloads(cfg.WithWorkspace(ws))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := CkanCollector("package", cfg)
+	if err != nil || len(files) != 2 {
+		t.Fatalf("Expected both resources to be downloaded, got %+v (%v)", files, err)
+	}
+	if !strings.HasPrefix(cfg.DownloadDir(), parent) || ws.Used() != int64(len(resources["data.csv"])+len(resources["large.txt"])) {
+		t.Errorf("Expected the downloads in the workspace, got %s with %d bytes", cfg.DownloadDir(), ws.Used())
+	}
+	removeDownloads()
+	if ws.Used() != 0 {
+		t.Errorf("Expected the removed downloads to be given back, %d bytes are used", ws.Used())
+	}
+	ws.Close()
+	if entries, _ := os.ReadDir(parent); len(entries) != 0 {
+		t.Errorf("Expected the workspace to be removed, got %v", entries)
+	}
+
+	// A package larger than the limit fails the scan instead of checking part of it
+	cfg = downloadConfig(t, server.URL, "")
+	cfg.General = &config.GeneralConfig{WorkspaceDir: parent, MaxWorkspaceSize: 500}
+	cfg, removeDownloads, err = PrepareDownloads(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeDownloads()
+	if _, err := CkanCollector("package", cfg); !errors.Is(err, workspace.ErrDiskLimit) || !strings.Contains(err.Error(), "maxWorkspaceSize") {
+		t.Errorf("Expected the workspace limit to fail the downloads, got %v", err)
+	}
+}
+
+func TestCkanCollectorDownloadsStaged(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	resources := map[string]string{
+		"data.csv":    "id,value\n1,2\n",
+		"changed.csv": "id,value\n3,4\n",
+		"nohash.txt":  "no hash in CKAN",
+	}
+	hashes := map[string]string{
+		"data.csv":    "sha256:" + sha256Hex(resources["data.csv"]),
+		"changed.csv": sha256Hex(resources["changed.csv"]),
+	}
+	server := newDownloadCKAN(t, resources, hashes, nil)
+	defer server.Close()
+
+	// The staged copy of changed.csv has the size but not the content of the resource
+	staging := t.TempDir()
+	staged := map[string]string{"data.csv": resources["data.csv"], "changed.csv": "id,value\n5,6\n", "nohash.txt": resources["nohash.txt"]}
+	for name, content := range staged {
+		if err := os.WriteFile(filepath.Join(staging, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := downloadConfig(t, server.URL, t.TempDir())
+	cfg.Collectors["CkanCollector"].Attrs["staging_path"] = staging
+	cfg, removeDownloads, err := PrepareDownloads(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeDownloads()
+
+	files, err := CkanCollector("package", cfg)
+	if err != nil || len(files) != 3 {
+		t.Fatalf("Expected the 3 resources, got %+v (%v)", files, err)
+	}
+	for _, file := range files {
+		local := file.Name == "data.csv"
+		if strings.HasPrefix(file.Path, staging) != local {
+			t.Errorf("Expected %s to be checked in the staging directory: %v, got %s", file.Name, local, file.Path)
+		}
+		content, err := os.ReadFile(file.Path)
+		if err != nil || string(content) != resources[file.Name] {
+			t.Errorf("Unexpected content of %s: %q (%v)", file.Name, content, err)
+		}
+	}
+	removeDownloads()
+	if _, err := os.Stat(filepath.Join(staging, "data.csv")); err != nil {
+		t.Errorf("Expected the staged copy to be kept when the downloads are removed, got %v", err)
+	}
+}
+
+func TestVerifyHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		hash  string
+		valid bool
+	}{
+		{"", true},
+		{"not a hash", true},
+		{"b1946ac92492d2347c6235b4d2611184", true},
+		{"MD5:B1946AC92492D2347C6235B4D2611184", true},
+		{"f572d396fae9206628714fb2ce00f72e94f2258f", true},
+		{"sha256:" + sha256Hex("hello\n"), true},
+		{"sha-256:" + sha256Hex("hello\n"), true},
+		{sha256Hex("other"), false},
+		{"md5:" + strings.Repeat("0", 32), false},
+	}
+	for _, tt := range tests {
+		if err := verifyHash(path, tt.hash); (err == nil) != tt.valid {
+			t.Errorf("verifyHash(%q): expected valid=%v, got %v", tt.hash, tt.valid, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package collectors
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+const (
+	// PublishModeResource uploads the report as a resource of the CKAN package
+	PublishModeResource = "resource"
+	// PublishModeExtra stores the report in a package extra field
+	PublishModeExtra = "extra"
+
+	// ReportExtraKey is the package extra key holding the JSON report
+	ReportExtraKey = "pc_report"
+)
+
+// ReportUpload is a single report document to be posted back to CKAN
+type ReportUpload struct {
+	Name    string // resource name and upload filename, e.g. "pc_report.json"
+	Format  string // CKAN resource format, e.g. "JSON" or "HTML"
+	Content []byte
+}
+
+// ckanAPIResponse is the common envelope of CKAN action API responses
+type ckanAPIResponse struct {
+	Success bool                   `json:"success"`
+	Result  map[string]interface{} `json:"result"`
+	Error   map[string]interface{} `json:"error"`
+}
+
+// PublishMode returns the publish mode configured for the CkanCollector ("" if publishing is disabled)
+func PublishMode(config config.Config) string {
+	if collector, ok := config.Collectors["CkanCollector"]; ok {
+		if mode, ok := collector.Attrs["publish"].(string); ok {
+			return mode
+		}
+	}
+	return ""
+}
+
+// PublishReport posts scan reports back to the CKAN package they were generated for.
+// In resource mode every upload becomes (or replaces) a resource with the same name,
+// in extra mode the first upload is stored in the package extra ReportExtraKey.
+func PublishReport(packageID string, config config.Config, mode string, uploads []ReportUpload) error {
+	collectorName := "CkanCollector"
+
+	collector, ok := config.Collectors[collectorName]
+	if !ok {
+		return fmt.Errorf("collector %s is not configured", collectorName)
+	}
+	baseURL, ok := collector.Attrs["url"].(string)
+	if !ok || baseURL == "" {
+		return fmt.Errorf("url attribute not found or not a string")
+	}
+	token, _ := collector.Attrs["token"].(string)
+	if token == "" {
+		return fmt.Errorf("a CKAN token with write access is required to publish reports")
+	}
+	verify := true
+	if v, ok := collector.Attrs["verify"].(bool); ok {
+		verify = v
+	}
+	if len(uploads) == 0 {
+		return fmt.Errorf("no report to publish")
+	}
+
+	// The current package state is needed in both modes: to merge extras and to find existing resources
+	jsonStr, err := Request(fmt.Sprintf("%s/api/3/action/package_show?id=%s", baseURL, packageID), token, verify)
+	if err != nil {
+		return err
+	}
+	jsonMap, err := JSONToMap(jsonStr)
+	if err != nil {
+		return err
+	}
+	pkg, ok := jsonMap["result"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected package_show response for package '%s'", packageID)
+	}
+
+	switch mode {
+	case PublishModeExtra:
+		return publishAsExtra(baseURL, token, verify, pkg, uploads[0])
+	case PublishModeResource:
+		for _, upload := range uploads {
+			if err := publishAsResource(baseURL, token, verify, pkg, upload); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown publish mode '%s' (expected '%s' or '%s')", mode, PublishModeResource, PublishModeExtra)
+	}
+}
+
+// publishAsExtra sets the report extra while keeping all other extras of the package
+func publishAsExtra(baseURL, token string, verify bool, pkg map[string]interface{}, upload ReportUpload) error {
+	extras := []map[string]interface{}{}
+	if existing, ok := pkg["extras"].([]interface{}); ok {
+		for _, item := range existing {
+			if extra, ok := item.(map[string]interface{}); ok && extra["key"] != ReportExtraKey {
+				extras = append(extras, extra)
+			}
+		}
+	}
+	extras = append(extras, map[string]interface{}{"key": ReportExtraKey, "value": string(upload.Content)})
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":     pkg["id"],
+		"extras": extras,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = postCKANAction(baseURL+"/api/3/action/package_patch", token, verify, "application/json", body)
+	return err
+}
+
+// publishAsResource uploads the report, replacing an existing resource with the same name
+func publishAsResource(baseURL, token string, verify bool, pkg map[string]interface{}, upload ReportUpload) error {
+	action := "resource_create"
+	existingID := ""
+	if resources, ok := pkg["resources"].([]interface{}); ok {
+		for _, item := range resources {
+			if res, ok := item.(map[string]interface{}); ok && res["name"] == upload.Name {
+				existingID, _ = res["id"].(string)
+				action = "resource_update"
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	fields := map[string]string{
+		"name":   upload.Name,
+		"format": upload.Format,
+	}
+	if existingID != "" {
+		fields["id"] = existingID
+	} else {
+		fields["package_id"], _ = pkg["id"].(string)
+	}
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+	part, err := writer.CreateFormFile("upload", upload.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(upload.Content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	_, err = postCKANAction(baseURL+"/api/3/action/"+action, token, verify, writer.FormDataContentType(), buf.Bytes())
+	return err
+}
+
+// postCKANAction posts to a CKAN action endpoint and returns the action result
+func postCKANAction(url, token string, verifyTLS bool, contentType string, body []byte) (map[string]interface{}, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: !verifyTLS},
+		},
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp ckanAPIResponse
+	if err := json.Unmarshal(respBytes, &apiResp); err != nil {
+		return nil, fmt.Errorf("CKAN request to %s failed with status code %d", url, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || !apiResp.Success {
+		return nil, fmt.Errorf("CKAN request to %s failed with status code %d: %v", url, resp.StatusCode, apiResp.Error)
+	}
+	return apiResp.Result, nil
+}
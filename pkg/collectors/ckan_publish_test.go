@@ -0,0 +1,131 @@
+package collectors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+// newFakeCKAN serves package_show with the given package and records posted actions
+func newFakeCKAN(t *testing.T, pkg map[string]interface{}, posted map[string]*http.Request, bodies map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/3/action/package_show":
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": pkg})
+		case "/api/3/action/package_patch", "/api/3/action/resource_create", "/api/3/action/resource_update":
+			if r.Header.Get("Content-Type") == "application/json" {
+				body, _ := io.ReadAll(r.Body)
+				bodies[r.URL.Path] = body
+			} else if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("expected multipart upload: %v", err)
+			}
+			posted[r.URL.Path] = r
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": map[string]interface{}{}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func publishConfig(url string) config.Config {
+	return config.Config{Collectors: map[string]*config.CollectorConfig{
+		"CkanCollector": {Attrs: map[string]interface{}{"url": url, "token": "secret-token", "verify": true}},
+	}}
+}
+
+func TestPublishReportAsExtra(t *testing.T) {
+	pkg := map[string]interface{}{
+		"id": "pkg-id",
+		"extras": []interface{}{
+			map[string]interface{}{"key": "other", "value": "keep"},
+			map[string]interface{}{"key": ReportExtraKey, "value": "old report"},
+		},
+	}
+	posted := map[string]*http.Request{}
+	bodies := map[string][]byte{}
+	server := newFakeCKAN(t, pkg, posted, bodies)
+	defer server.Close()
+
+	err := PublishReport("my-package", publishConfig(server.URL), PublishModeExtra, []ReportUpload{{Name: "pc_report.json", Format: "JSON", Content: []byte(`{"a":1}`)}})
+	if err != nil {
+		t.Fatalf("PublishReport returned an error: %v", err)
+	}
+
+	var patch struct {
+		ID     string              `json:"id"`
+		Extras []map[string]string `json:"extras"`
+	}
+	if err := json.Unmarshal(bodies["/api/3/action/package_patch"], &patch); err != nil {
+		t.Fatalf("invalid package_patch body: %v", err)
+	}
+	if patch.ID != "pkg-id" {
+		t.Errorf("expected package id 'pkg-id', got '%s'", patch.ID)
+	}
+	if len(patch.Extras) != 2 {
+		t.Fatalf("expected 2 extras, got %d: %v", len(patch.Extras), patch.Extras)
+	}
+	if patch.Extras[0]["key"] != "other" || patch.Extras[1]["value"] != `{"a":1}` {
+		t.Errorf("unexpected extras: %v", patch.Extras)
+	}
+}
+
+func TestPublishReportAsResource(t *testing.T) {
+	pkg := map[string]interface{}{
+		"id": "pkg-id",
+		"resources": []interface{}{
+			map[string]interface{}{"id": "res-html", "name": "pc_report.html"},
+		},
+	}
+	posted := map[string]*http.Request{}
+	server := newFakeCKAN(t, pkg, posted, map[string][]byte{})
+	defer server.Close()
+
+	uploads := []ReportUpload{
+		{Name: "pc_report.json", Format: "JSON", Content: []byte(`{}`)},
+		{Name: "pc_report.html", Format: "HTML", Content: []byte(`<html></html>`)},
+	}
+	if err := PublishReport("my-package", publishConfig(server.URL), PublishModeResource, uploads); err != nil {
+		t.Fatalf("PublishReport returned an error: %v", err)
+	}
+
+	created, ok := posted["/api/3/action/resource_create"]
+	if !ok {
+		t.Fatal("expected a resource_create call for the new JSON report")
+	}
+	if created.FormValue("package_id") != "pkg-id" || created.FormValue("name") != "pc_report.json" {
+		t.Errorf("unexpected resource_create fields: %v", created.MultipartForm.Value)
+	}
+
+	updated, ok := posted["/api/3/action/resource_update"]
+	if !ok {
+		t.Fatal("expected a resource_update call for the existing HTML report")
+	}
+	if updated.FormValue("id") != "res-html" {
+		t.Errorf("expected resource id 'res-html', got '%s'", updated.FormValue("id"))
+	}
+}
+
+func TestPublishReportErrors(t *testing.T) {
+	uploads := []ReportUpload{{Name: "pc_report.json", Content: []byte(`{}`)}}
+
+	noToken := publishConfig("http://localhost")
+	noToken.Collectors["CkanCollector"].Attrs["token"] = ""
+	if err := PublishReport("pkg", noToken, PublishModeExtra, uploads); err == nil {
+		t.Error("expected an error without token")
+	}
+
+	server := newFakeCKAN(t, map[string]interface{}{"id": "pkg-id"}, map[string]*http.Request{}, map[string][]byte{})
+	defer server.Close()
+	if err := PublishReport("pkg", publishConfig(server.URL), "somewhere", uploads); err == nil {
+		t.Error("expected an error for an unknown publish mode")
+	}
+}
@@ -0,0 +1,218 @@
+package collectors
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// ComparedFile is a file of the local folder, a resource of the CKAN package or both
+type ComparedFile struct {
+	Name      string `json:"name"`               // Path of the local file relative to the folder, else the resource name
+	Resource  string `json:"resource,omitempty"` // Name of the CKAN resource
+	LocalSize int64  `json:"local_size,omitempty"`
+	CkanSize  int64  `json:"ckan_size,omitempty"`
+	Hash      string `json:"hash,omitempty"` // Hash of the resource in the CKAN metadata
+	// Verified is true if the contents were compared, by the hash in the CKAN metadata or with the
+	// file in the CKAN storage, and false if only the sizes were
+	Verified bool   `json:"verified"`
+	Problem  string `json:"problem,omitempty"` // How the local file and the resource differ
+}
+
+// Comparison lists which files of a local folder match the resources of a CKAN package, e.g.
+// to verify an upload
+type Comparison struct {
+	Folder    string         `json:"folder"`
+	Package   string         `json:"package"`
+	Matching  []ComparedFile `json:"matching"`
+	Different []ComparedFile `json:"different"`
+	OnlyLocal []ComparedFile `json:"only_local"`
+	OnlyCkan  []ComparedFile `json:"only_ckan"`
+}
+
+// Identical reports whether the folder and the package have the same files
+func (c *Comparison) Identical() bool {
+	return len(c.Different) == 0 && len(c.OnlyLocal) == 0 && len(c.OnlyCkan) == 0
+}
+
+// localFile is a file collected from the folder with its path relative to the folder
+type localFile struct {
+	file     structs.File
+	relative string
+	matched  bool
+}
+
+// ComparePackage collects the files of folder with the LocalCollector and compares them with
+// the resources of the CKAN package. Resources are matched to the local files by their name or
+// the file name of their URL, first as path relative to folder, then as file name at any depth.
+// Contents are compared with the hash of the resource in the CKAN metadata, or with the file
+// in the CKAN storage if the resources are not downloaded; nothing is downloaded.
+func ComparePackage(folder, packageID string, cfg config.Config) (*Comparison, error) {
+	if _, ok := cfg.Collectors["CkanCollector"]; !ok {
+		return nil, fmt.Errorf("the config has no [collector.CkanCollector] section")
+	}
+	collected, err := LocalCollector(folder, cfg)
+	if err != nil {
+		return nil, err
+	}
+	jsonMap, err := packageShow(packageID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := GetCKANResources(jsonMap)
+	if err != nil {
+		return nil, err
+	}
+	hashes := resourceHashes(jsonMap)
+	storagePath := ""
+	if !downloadsEnabled(cfg) {
+		storagePath, _ = cfg.Collectors["CkanCollector"].Attrs["ckan_storage_path"].(string)
+	}
+
+	var locals []*localFile
+	byPath := map[string]*localFile{}
+	byName := map[string][]*localFile{}
+	for _, file := range collected {
+		// The LocalCollector also collects the folders with includeFolders
+		if info, err := os.Stat(file.Path); err != nil || info.IsDir() {
+			continue
+		}
+		relative, _ := filepath.Rel(filepath.Clean(folder), file.Path)
+		local := &localFile{file: file, relative: filepath.ToSlash(relative)}
+		locals = append(locals, local)
+		byPath[local.relative] = local
+		byName[file.Name] = append(byName[file.Name], local)
+	}
+	find := func(names ...string) *localFile {
+		for _, name := range names {
+			if local := byPath[name]; local != nil && !local.matched {
+				return local
+			}
+		}
+		for _, name := range names {
+			for _, local := range byName[name] {
+				if !local.matched {
+					return local
+				}
+			}
+		}
+		return nil
+	}
+
+	comparison := &Comparison{
+		Folder:    folder,
+		Package:   packageID,
+		Matching:  []ComparedFile{},
+		Different: []ComparedFile{},
+		OnlyLocal: []ComparedFile{},
+		OnlyCkan:  []ComparedFile{},
+	}
+	for _, resource := range resources {
+		if err := cfg.Context().Err(); err != nil {
+			return nil, err
+		}
+		compared := ComparedFile{Name: resource.Name, Resource: resource.Name, CkanSize: resource.Size, Hash: hashes[resource.Path]}
+		local := find(resource.Name, resourceFileName(resource))
+		if local == nil {
+			comparison.OnlyCkan = append(comparison.OnlyCkan, compared)
+			continue
+		}
+		local.matched = true
+		compared.Name = local.relative
+		compared.LocalSize = local.file.Size
+		storageCopy := ""
+		if storagePath != "" {
+			storageCopy = getLocalResourcePath(resource.Path, storagePath)
+		}
+		compared.Verified, compared.Problem = compareResource(local.file, compared.CkanSize, compared.Hash, storageCopy)
+		if compared.Problem != "" {
+			comparison.Different = append(comparison.Different, compared)
+		} else {
+			comparison.Matching = append(comparison.Matching, compared)
+		}
+	}
+	for _, local := range locals {
+		if !local.matched {
+			comparison.OnlyLocal = append(comparison.OnlyLocal, ComparedFile{Name: local.relative, LocalSize: local.file.Size})
+		}
+	}
+	return comparison, nil
+}
+
+// compareResource compares the local file with a resource of size and hash, whose file in the
+// CKAN storage is storageCopy ("" if it cannot be read). It returns whether the contents were
+// compared and how they differ, "" if they do not.
+func compareResource(local structs.File, size int64, hash, storageCopy string) (bool, string) {
+	// CKAN leaves the size of some resources empty
+	if size > 0 && local.Size != size {
+		return false, fmt.Sprintf("%d bytes locally, %d bytes in CKAN", local.Size, size)
+	}
+	if newHash, digest := parseHash(hash); newHash != nil {
+		matches, err := hashMatches(local.Path, newHash, digest)
+		if err != nil {
+			return false, fmt.Sprintf("the local file cannot be read: %v", err)
+		}
+		if !matches {
+			return true, fmt.Sprintf("the contents do not match the hash '%s' in CKAN", hash)
+		}
+		return true, ""
+	}
+	if storageCopy == "" {
+		return false, ""
+	}
+	stored, err := fileDigest(storageCopy, sha256.New)
+	if err != nil {
+		// The package checker runs away from the CKAN storage, only the sizes can be compared
+		return false, ""
+	}
+	matches, err := hashMatches(local.Path, sha256.New, stored)
+	if err != nil {
+		return false, fmt.Sprintf("the local file cannot be read: %v", err)
+	}
+	if !matches {
+		return true, "the contents differ from the file in the CKAN storage"
+	}
+	return true, ""
+}
+
+// FormatText renders the comparison as plain text for the console
+func (c *Comparison) FormatText() string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Comparing '%s' with the CKAN package '%s':\n", c.Folder, c.Package)
+	sizeOnly := 0
+	for _, file := range c.Matching {
+		if !file.Verified {
+			sizeOnly++
+		}
+	}
+	fmt.Fprintf(&output, "  %d matching, %d different, %d only local, %d only in CKAN\n", len(c.Matching), len(c.Different), len(c.OnlyLocal), len(c.OnlyCkan))
+	if sizeOnly > 0 {
+		fmt.Fprintf(&output, "  %d matching by size only, CKAN has no hash of them\n", sizeOnly)
+	}
+
+	writeSection := func(title, marker string, files []ComparedFile) {
+		if len(files) == 0 {
+			return
+		}
+		fmt.Fprintf(&output, "\n%s:\n", title)
+		for _, file := range files {
+			line := file.Name
+			if file.Resource != "" && file.Resource != file.Name {
+				line += " (resource '" + file.Resource + "')"
+			}
+			if file.Problem != "" {
+				line += ": " + file.Problem
+			}
+			fmt.Fprintf(&output, "  %s %s\n", marker, line)
+		}
+	}
+	writeSection("Different", "!", c.Different)
+	writeSection("Only in the local folder", "+", c.OnlyLocal)
+	writeSection("Only in CKAN", "-", c.OnlyCkan)
+	return output.String()
+}
@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestComparePackage(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"a.csv":       "a,b\n1,2\n",
+		"raw/b.csv":   "b,c\n3,4\n",
+		"raw/d.csv":   "d\n",
+		"changed.txt": "new text",
+		"notes.txt":   "not uploaded",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	digest := sha256.Sum256([]byte("a,b\n1,2\n"))
+	resource := func(name string, size int, hash string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "url": "https://ckan.example/dataset/p/resource/f46e74be-1c61/download/" + name, "url_type": "upload", "size": size, "hash": hash}
+	}
+	pkg := map[string]interface{}{
+		"name": "lake-ice",
+		"resources": []interface{}{
+			resource("a.csv", 8, "sha256:"+hex.EncodeToString(digest[:])),
+			resource("b.csv", 99, ""),
+			resource("d.csv", 2, ""),
+			resource("changed.txt", 8, strings.Repeat("0", 64)),
+			resource("missing.csv", 10, ""),
+		},
+	}
+	server := newFakeCKAN(t, pkg, map[string]*http.Request{}, map[string][]byte{})
+	defer server.Close()
+	cfg := publishConfig(server.URL)
+	cfg.Collectors["LocalCollector"] = &config.CollectorConfig{Attrs: map[string]interface{}{"includeFolders": true}}
+
+	comparison, err := ComparePackage(dir, "lake-ice", cfg)
+	if err != nil {
+		t.Fatalf("ComparePackage returned an error: %v", err)
+	}
+	names := func(files []ComparedFile) string {
+		var names []string
+		for _, file := range files {
+			names = append(names, file.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	if names(comparison.Matching) != "a.csv,raw/d.csv" || !comparison.Matching[0].Verified || comparison.Matching[1].Verified {
+		t.Errorf("Expected a.csv verified and raw/d.csv matching by size, got %+v", comparison.Matching)
+	}
+	if names(comparison.Different) != "raw/b.csv,changed.txt" {
+		t.Fatalf("Expected raw/b.csv and changed.txt to differ, got %+v", comparison.Different)
+	}
+	if problem := comparison.Different[0].Problem; problem != "8 bytes locally, 99 bytes in CKAN" {
+		t.Errorf("Expected the sizes of raw/b.csv, got %q", problem)
+	}
+	if problem := comparison.Different[1].Problem; !strings.HasPrefix(problem, "the contents do not match the hash") {
+		t.Errorf("Expected the hash of changed.txt not to match, got %q", problem)
+	}
+	if names(comparison.OnlyLocal) != "notes.txt" || names(comparison.OnlyCkan) != "missing.csv" {
+		t.Errorf("Expected notes.txt only local and missing.csv only in CKAN, got %+v, %+v", comparison.OnlyLocal, comparison.OnlyCkan)
+	}
+	if comparison.Identical() {
+		t.Error("Expected the folder and the package to differ")
+	}
+
+	text := comparison.FormatText()
+	for _, line := range []string{"2 matching, 2 different, 1 only local, 1 only in CKAN", "1 matching by size only", "! raw/b.csv (resource 'b.csv'): 8 bytes locally", "+ notes.txt", "- missing.csv"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected %q in the text, got:\n%s", line, text)
+		}
+	}
+}
+
+func TestCompareResourceWithStorage(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.csv")
+	stored := filepath.Join(dir, "stored")
+	os.WriteFile(local, []byte("a,b\n"), 0644)
+	os.WriteFile(stored, []byte("a,c\n"), 0644)
+	file := structs.ToFile(local, "local.csv", -1, "")
+
+	if verified, problem := compareResource(file, 4, "", stored); !verified || problem != "the contents differ from the file in the CKAN storage" {
+		t.Errorf("Expected the contents to differ from the storage, got %v, %q", verified, problem)
+	}
+	os.WriteFile(stored, []byte("a,b\n"), 0644)
+	if verified, problem := compareResource(file, 4, "", stored); !verified || problem != "" {
+		t.Errorf("Expected the contents to match the storage, got %v, %q", verified, problem)
+	}
+	// Away from the CKAN storage only the sizes are compared
+	if verified, problem := compareResource(file, 4, "", filepath.Join(dir, "missing")); verified || problem != "" {
+		t.Errorf("Expected a match by size only, got %v, %q", verified, problem)
+	}
+}
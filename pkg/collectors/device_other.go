@@ -0,0 +1,10 @@
+//go:build !unix
+
+package collectors
+
+import "os"
+
+// deviceOf is not supported on this platform, all files count as on the same file system
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
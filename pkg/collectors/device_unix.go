@@ -0,0 +1,17 @@
+//go:build unix
+
+package collectors
+
+import (
+	"os"
+	"syscall"
+)
+
+// deviceOf returns the device of the file system holding the file of info
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
@@ -0,0 +1,145 @@
+package collectors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the file in the scanned directory listing the files the LocalCollector leaves
+// out, in the syntax of .gitignore
+const IgnoreFileName = ".pcignore"
+
+// ignoreRule is a pattern of an ignore file
+type ignoreRule struct {
+	pattern string         // The line of the ignore file, for the reasons of the exclusions
+	re      *regexp.Regexp // Matches the slash-separated paths relative to the scanned directory
+	negate  bool           // The pattern started with !, matching paths are collected again
+	dirOnly bool           // The pattern ended with /, it matches directories only
+}
+
+// ignoreRules are the patterns of an ignore file, later patterns take precedence
+type ignoreRules []ignoreRule
+
+// readIgnoreFile reads the ignore file at path, nil if there is none
+func readIgnoreFile(path string) (ignoreRules, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules ignoreRules
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, scanner.Err()
+}
+
+// parseIgnoreLine parses a line of an ignore file, false for blank lines and comments
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	// Trailing spaces are ignored unless escaped with a backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	rule := ignoreRule{pattern: line}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	// Patterns with a slash before their end are relative to the scanned directory, others match
+	// names at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	expression := globToRegexp(line)
+	if !anchored {
+		expression = "(?:.*/)?" + expression
+	}
+	re, err := regexp.Compile("^" + expression + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates a pattern of .gitignore into a regular expression: * and ? match
+// within a name, ** across folders, [...] a character of the class
+func globToRegexp(glob string) string {
+	var expression strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			// Any folders, also none
+			expression.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			// Everything inside the folder
+			expression.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case c == '*':
+			expression.WriteString("[^/]*")
+		case c == '?':
+			expression.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expression.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expression.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expression.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expression.String()
+}
+
+// match returns the pattern excluding the path relative to the scanned directory, false if the
+// path is collected. The last matching pattern decides, as in .gitignore.
+func (rules ignoreRules) match(relative string, isDir bool) (string, bool) {
+	relative = filepath.ToSlash(relative)
+	pattern, ignored := "", false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(relative) {
+			pattern, ignored = rule.pattern, !rule.negate
+		}
+	}
+	return pattern, ignored
+}
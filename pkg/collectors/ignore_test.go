@@ -0,0 +1,74 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"*.tmp", "a.tmp", false, true},
+		{"*.tmp", "raw/2021/a.tmp", false, true},
+		{"*.tmp", "a.tmp.csv", false, false},
+		{"/*.tmp", "raw/a.tmp", false, false},
+		{"/*.tmp", "a.tmp", false, true},
+		{"raw/*.csv", "raw/a.csv", false, true},
+		{"raw/*.csv", "raw/2021/a.csv", false, false},
+		{"raw/*.csv", "data/raw/a.csv", false, false},
+		{"build/", "build", true, true},
+		{"build/", "src/build", true, true},
+		{"build/", "build", false, false},
+		{"**/cache", "a/b/cache", true, true},
+		{"**/cache", "cache", false, true},
+		{"logs/**", "logs/2021/run.log", false, true},
+		{"logs/**", "logs", true, false},
+		{"a/**/z", "a/z", false, true},
+		{"a/**/z", "a/b/c/z", false, true},
+		{"run?.log", "run1.log", false, true},
+		{"run?.log", "run10.log", false, false},
+		{"run[0-9].log", "run7.log", false, true},
+		{"run[!0-9].log", "run7.log", false, false},
+		{"run[!0-9].log", "runx.log", false, true},
+		{`\#notes`, "#notes", false, true},
+		{"notes.txt   ", "notes.txt", false, true},
+		{"# comment", "# comment", false, false},
+	}
+	for _, tt := range tests {
+		var rules ignoreRules
+		if rule, ok := parseIgnoreLine(tt.pattern); ok {
+			rules = append(rules, rule)
+		}
+		if _, ignored := rules.match(tt.path, tt.isDir); ignored != tt.ignored {
+			t.Errorf("%q on %q (directory %v): expected ignored %v", tt.pattern, tt.path, tt.isDir, tt.ignored)
+		}
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	if rules, err := readIgnoreFile(filepath.Join(dir, IgnoreFileName)); err != nil || rules != nil {
+		t.Errorf("Expected no rules without an ignore file, got %v, %v", rules, err)
+	}
+
+	content := "# Scratch files\n*.log\n\n!keep.log\nkeep.log.bak\n"
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := readIgnoreFile(filepath.Join(dir, IgnoreFileName))
+	if err != nil || len(rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %v, %v", rules, err)
+	}
+	if pattern, ignored := rules.match("run/debug.log", false); !ignored || pattern != "*.log" {
+		t.Errorf("Expected debug.log to be ignored by '*.log', got %q, %v", pattern, ignored)
+	}
+	// The last matching pattern decides
+	if pattern, ignored := rules.match("keep.log", false); ignored || pattern != "!keep.log" {
+		t.Errorf("Expected keep.log to be collected again by '!keep.log', got %q, %v", pattern, ignored)
+	}
+}
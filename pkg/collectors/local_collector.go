@@ -11,6 +11,9 @@ import (
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
+// logger logs the problems collecting files, local or from CKAN
+var logger = output.GlobalLogger.Component("collectors")
+
 // validatePath ensures the path is safe and doesn't contain directory traversal patterns
 func validatePath(path string) error {
 	// Clean the path to resolve any ".." or "." components
@@ -25,12 +28,152 @@ func validatePath(path string) error {
 	if filepath.IsAbs(cleanPath) {
 		// Allow absolute paths but warn about potential risks
 		// In a production environment, you might want to restrict this further
-		output.GlobalLogger.Warning("Warning: Using absolute path: %s", cleanPath)
+		logger.Warning("Warning: Using absolute path: %s", cleanPath)
 	}
 	
 	return nil
 }
 
+// localOptions are the attrs of the LocalCollector
+type localOptions struct {
+	includeFolders   bool
+	followSymlinks   bool
+	crossFilesystems bool
+	includeHidden    bool
+	useIgnoreFile    bool
+	maxDepth         int // 0 for no limit
+}
+
+// readLocalOptions reads the attrs of the LocalCollector, booleans may also be written as strings
+func readLocalOptions(attrs map[string]interface{}) localOptions {
+	flag := func(key string, fallback bool) bool {
+		switch v := attrs[key].(type) {
+		case bool:
+			return v
+		case string:
+			return v == "true"
+		}
+		return fallback
+	}
+	options := localOptions{
+		includeFolders:   flag("includeFolders", false),
+		followSymlinks:   flag("followSymlinks", false),
+		crossFilesystems: flag("crossFilesystems", true),
+		includeHidden:    flag("includeHidden", true),
+		useIgnoreFile:    flag("useIgnoreFile", true),
+	}
+	switch v := attrs["maxDepth"].(type) {
+	case int64:
+		options.maxDepth = int(v)
+	case float64:
+		options.maxDepth = int(v)
+	}
+	return options
+}
+
+// excluded records a path the LocalCollector leaves out of the scan and why
+func (w *localWalker) excluded(path string, code output.SkipCode, reason string) {
+	logger.Debug("Excluded '%s': %s", path, reason)
+	w.config.SkippedFiles().Add(output.SkippedFile{Filename: filepath.Base(path), Path: path, Code: code, Reason: reason})
+}
+
+// localWalker collects the files below a directory following the attrs of the LocalCollector
+type localWalker struct {
+	options localOptions
+	config  config.Config
+	device  uint64
+	root    string
+	ignore  ignoreRules // Patterns of the .pcignore file of the root, nil without one
+	// visited maps the real paths of the directories walked to their paths, so symbolic links
+	// neither loop nor collect a directory twice
+	visited map[string]string
+	files   []structs.File
+}
+
+// walk collects the entries of dir, whose own entries are at depth + 1 below the root
+func (w *localWalker) walk(dir string, depth int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Warning("Warning: error accessing %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		// Stop collecting when the scan is cancelled
+		if err := w.config.Context().Err(); err != nil {
+			return err
+		}
+		if err := w.visit(filepath.Join(dir, entry.Name()), entry, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visit collects the entry at path and, for a directory, its entries
+func (w *localWalker) visit(path string, entry os.DirEntry, depth int) error {
+	if !w.options.includeHidden && strings.HasPrefix(entry.Name(), ".") {
+		w.excluded(path, output.SkipHidden, "Hidden files are excluded (includeHidden is false).")
+		return nil
+	}
+	info, err := entry.Info()
+	if err != nil {
+		logger.Warning("Warning: could not get info for file %s: %v", path, err)
+		return nil
+	}
+	if w.ignore != nil {
+		relative, _ := filepath.Rel(w.root, path)
+		// Patterns for directories also match links to directories, like in git
+		isDir := info.IsDir()
+		if info.Mode()&os.ModeSymlink != 0 {
+			if target, err := os.Stat(path); err == nil {
+				isDir = target.IsDir()
+			}
+		}
+		if pattern, ignored := w.ignore.match(relative, isDir); ignored {
+			w.excluded(path, output.SkipIgnored, fmt.Sprintf("Matched by '%s' in %s.", pattern, IgnoreFileName))
+			return nil
+		}
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if !w.options.followSymlinks {
+			w.excluded(path, output.SkipSymlink, "Symbolic links are not followed (followSymlinks is false).")
+			return nil
+		}
+		if info, err = os.Stat(path); err != nil {
+			w.excluded(path, output.SkipSymlink, fmt.Sprintf("The symbolic link is broken: %v", err))
+			return nil
+		}
+	}
+
+	if !info.IsDir() {
+		w.files = append(w.files, structs.ToFile(path, entry.Name(), info.Size(), ""))
+		return nil
+	}
+	// If includeFolders is false, skip traversing into subdirectories
+	if !w.options.includeFolders {
+		return nil
+	}
+	if device, ok := deviceOf(info); ok && !w.options.crossFilesystems && device != w.device {
+		w.excluded(path, output.SkipOtherFilesystem, "The directory is on another file system (crossFilesystems is false).")
+		return nil
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		logger.Warning("Warning: error accessing %s: %v", path, err)
+		return nil
+	}
+	if collected, ok := w.visited[realPath]; ok {
+		w.excluded(path, output.SkipDuplicate, fmt.Sprintf("The directory is already collected as '%s'.", collected))
+		return nil
+	}
+	w.visited[realPath] = path
+	w.files = append(w.files, structs.ToFile(path, entry.Name(), -1, ""))
+	if w.options.maxDepth > 0 && depth >= w.options.maxDepth {
+		w.excluded(path, output.SkipMaxDepth, fmt.Sprintf("The contents of the directory are deeper than maxDepth (%d).", w.options.maxDepth))
+		return nil
+	}
+	return w.walk(path, depth)
+}
+
 // read all files from a local directory
 func LocalCollector(path string, config config.Config) ([]structs.File, error) {
 	collectorName := "LocalCollector"
@@ -44,61 +187,46 @@ func LocalCollector(path string, config config.Config) ([]structs.File, error) {
 	cleanPath := filepath.Clean(path)
 	
 	// Check if the path exists before attempting to walk it
-	if _, err := os.Stat(cleanPath); err != nil {
+	rootInfo, err := os.Stat(cleanPath)
+	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("path does not exist: %s", cleanPath)
 		}
 		return nil, fmt.Errorf("cannot access path %s: %w", cleanPath, err)
 	}
-	
-	foundFiles := []structs.File{}
-	
-	// Check if folders should be included recursively
-	includeFolders := false
-	if attrs, ok := config.Collectors[collectorName].Attrs["includeFolders"]; ok {
-		switch v := attrs.(type) {
-		case bool:
-			includeFolders = v
-		case string:
-			includeFolders = v == "true"
-		}
+	if !rootInfo.IsDir() {
+		return []structs.File{}, nil
 	}
-	
-	// Use filepath.WalkDir for recursive traversal
-	err := filepath.WalkDir(cleanPath, func(currentPath string, d os.DirEntry, err error) error {
-		if err != nil {
-			output.GlobalLogger.Warning("Warning: error accessing %s: %v", currentPath, err)
-			return nil // Continue walking despite errors
-		}
-		
-		// Skip the root directory itself
-		if currentPath == cleanPath {
-			return nil
-		}
-		
-		if d.IsDir() {
-			// If includeFolders is false, skip traversing into subdirectories
-			if !includeFolders {
-				return filepath.SkipDir
-			}
-			// Include directory only if includeFolders is true
-			foundFiles = append(foundFiles, structs.ToFile(currentPath, d.Name(), -1, ""))
-		} else {
-			// Add regular files
-			info, err := d.Info()
-			if err != nil {
-				output.GlobalLogger.Warning("Warning: could not get info for file %s: %v", currentPath, err)
-				return nil
-			}
-			foundFiles = append(foundFiles, structs.ToFile(currentPath, d.Name(), info.Size(), ""))
+
+	var attrs map[string]interface{}
+	if collector, ok := config.Collectors[collectorName]; ok {
+		attrs = collector.Attrs
+	}
+	walker := &localWalker{
+		options: readLocalOptions(attrs),
+		config:  config,
+		root:    cleanPath,
+		visited: map[string]string{},
+		files:   []structs.File{},
+	}
+	walker.device, _ = deviceOf(rootInfo)
+	if realPath, err := filepath.EvalSymlinks(cleanPath); err == nil {
+		walker.visited[realPath] = cleanPath
+	}
+	if walker.options.useIgnoreFile {
+		// An unreadable ignore file would collect what the depositor meant to leave out unnoticed
+		if walker.ignore, err = readIgnoreFile(filepath.Join(cleanPath, IgnoreFileName)); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
 		}
-		
-		return nil
-	})
-	
-	if err != nil {
+	}
+
+	if err := config.Context().Err(); err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", cleanPath, err)
+	}
+	if err := walker.walk(cleanPath, 0); err != nil {
 		return nil, fmt.Errorf("failed to walk directory %s: %w", cleanPath, err)
 	}
 
-	return foundFiles, nil
+	logger.Debug("Collected %d files from %s", len(walker.files), cleanPath)
+	return walker.files, nil
 }
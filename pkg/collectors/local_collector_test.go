@@ -1,11 +1,15 @@
 package collectors
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
 )
 
 func TestLocalCollector(t *testing.T) {
@@ -54,3 +58,215 @@ func TestLocalCollector(t *testing.T) {
 		}
 	}
 }
+
+func TestLocalCollectorCancelled(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "data.csv"), []byte("a,b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := config.Config{Collectors: map[string]*config.CollectorConfig{"LocalCollector": {Attrs: map[string]interface{}{}}}}
+	_, err := LocalCollector(tempDir, cfg.WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation as error, got %v", err)
+	}
+}
+
+// collectLocal runs the LocalCollector on dir with attrs and returns the relative paths of the
+// files and the excluded paths with their codes and reasons
+// loadAttrs loads the attrs of the LocalCollector from a pc.toml, as the scan does
+func loadAttrs(t *testing.T, attrs string) map[string]interface{} {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pc.toml")
+	if err := os.WriteFile(path, []byte("[collector.LocalCollector]\nattrs = "+attrs+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg.Collectors["LocalCollector"].Attrs
+}
+
+func collectLocal(t *testing.T, dir string, attrs map[string]interface{}) ([]string, map[string]string) {
+	t.Helper()
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	skippedFiles := output.NewSkippedFiles()
+	cfg := config.Config{Collectors: map[string]*config.CollectorConfig{"LocalCollector": {Attrs: attrs}}}
+	files, err := LocalCollector(dir, cfg.WithSkippedFiles(skippedFiles))
+	if err != nil {
+		t.Fatalf("LocalCollector returned an error: %v", err)
+	}
+	var paths []string
+	for _, file := range files {
+		rel, _ := filepath.Rel(dir, file.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	excluded := map[string]string{}
+	for _, skipped := range skippedFiles.Files() {
+		rel, _ := filepath.Rel(dir, skipped.Path)
+		excluded[filepath.ToSlash(rel)] = string(skipped.Code) + ": " + skipped.Reason
+	}
+	return paths, excluded
+}
+
+func TestLocalCollectorOptions(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	for _, path := range []string{"a.txt", ".hidden.txt", "sub/b.txt", "sub/deep/c.txt", ".git/config"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "target.txt"), []byte("linked content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{
+		"file-link.txt": filepath.Join(outside, "target.txt"),
+		"sub/loop":      dir,
+		"broken":        filepath.Join(outside, "missing"),
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skipf("Symbolic links are not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		attrs    map[string]interface{}
+		files    []string
+		excluded map[string]string
+	}{
+		{
+			name:  "defaults",
+			attrs: map[string]interface{}{},
+			files: []string{".hidden.txt", "a.txt"},
+			excluded: map[string]string{
+				"broken":        "symlink: Symbolic links are not followed (followSymlinks is false).",
+				"file-link.txt": "followSymlinks is false",
+			},
+		},
+		{
+			name:  "recursive without hidden files",
+			attrs: map[string]interface{}{"includeFolders": true, "includeHidden": false},
+			files: []string{"a.txt", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt"},
+			excluded: map[string]string{
+				".git":          "hidden: Hidden files are excluded (includeHidden is false).",
+				".hidden.txt":   "includeHidden is false",
+				"broken":        "followSymlinks is false",
+				"file-link.txt": "followSymlinks is false",
+				"sub/loop":      "followSymlinks is false",
+			},
+		},
+		{
+			name:  "following symbolic links",
+			attrs: map[string]interface{}{"includeFolders": true, "includeHidden": false, "followSymlinks": true},
+			files: []string{"a.txt", "file-link.txt", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt"},
+			excluded: map[string]string{
+				".git":        "includeHidden is false",
+				".hidden.txt": "includeHidden is false",
+				"broken":      "symlink: The symbolic link is broken",
+				"sub/loop":    "duplicate: The directory is already collected as '" + filepath.Clean(dir) + "'",
+			},
+		},
+		{
+			name:  "limited depth",
+			attrs: loadAttrs(t, `{includeFolders = "true", includeHidden = false, maxDepth = 2}`),
+			files: []string{"a.txt", "sub", "sub/b.txt", "sub/deep"},
+			excluded: map[string]string{
+				".git":          "includeHidden is false",
+				".hidden.txt":   "includeHidden is false",
+				"broken":        "followSymlinks is false",
+				"file-link.txt": "followSymlinks is false",
+				"sub/deep":      "max_depth: The contents of the directory are deeper than maxDepth (2)",
+				"sub/loop":      "followSymlinks is false",
+			},
+		},
+		{
+			name:  "same file system",
+			attrs: loadAttrs(t, `{includeFolders = true, crossFilesystems = false, maxDepth = 1}`),
+			files: []string{".git", ".hidden.txt", "a.txt", "sub"},
+			excluded: map[string]string{
+				".git":          "deeper than maxDepth (1)",
+				"broken":        "followSymlinks is false",
+				"file-link.txt": "followSymlinks is false",
+				"sub":           "deeper than maxDepth (1)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, excluded := collectLocal(t, dir, tt.attrs)
+			if strings.Join(files, ",") != strings.Join(tt.files, ",") {
+				t.Errorf("Expected files %q, got %q", tt.files, files)
+			}
+			if len(excluded) != len(tt.excluded) {
+				t.Errorf("Expected %d excluded paths, got %q", len(tt.excluded), excluded)
+			}
+			for path, reason := range tt.excluded {
+				if !strings.Contains(excluded[path], reason) {
+					t.Errorf("Expected %s to be excluded with %q, got %q", path, reason, excluded[path])
+				}
+			}
+		})
+	}
+
+	// The file behind a symbolic link is collected with its own size
+	files, _ := collectLocal(t, dir, map[string]interface{}{"followSymlinks": true})
+	for _, file := range files {
+		if file == "file-link.txt" {
+			return
+		}
+	}
+	t.Errorf("Expected the linked file to be collected, got %q", files)
+}
+
+func TestLocalCollectorIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"a.csv", "scratch.tmp", "raw/b.csv", "raw/c.tmp", "build/out.bin"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := "*.tmp\n!raw/c.tmp\nbuild/\n" + IgnoreFileName + "\n"
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, excluded := collectLocal(t, dir, map[string]interface{}{"includeFolders": true})
+	expected := []string{"a.csv", "raw", "raw/b.csv", "raw/c.tmp"}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected files %q, got %q", expected, files)
+	}
+	for path, reason := range map[string]string{
+		"scratch.tmp":  "ignored: Matched by '*.tmp' in .pcignore.",
+		"build":        "ignored: Matched by 'build/' in .pcignore.",
+		IgnoreFileName: "ignored: Matched by '.pcignore' in .pcignore.",
+	} {
+		if excluded[path] != reason {
+			t.Errorf("Expected %s to be excluded with %q, got %q", path, reason, excluded[path])
+		}
+	}
+	// The ignored directory is not walked
+	if _, ok := excluded["build/out.bin"]; ok || len(excluded) != 3 {
+		t.Errorf("Expected 3 excluded paths, got %q", excluded)
+	}
+
+	// Curators can collect what the depositor ignored
+	files, _ = collectLocal(t, dir, map[string]interface{}{"includeFolders": true, "useIgnoreFile": false})
+	if len(files) != 8 {
+		t.Errorf("Expected all 8 files and folders without the ignore file, got %q", files)
+	}
+}
@@ -1,31 +1,101 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/eawag-rdm/pc/pkg/helpers"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/eawag-rdm/pc/pkg/trace"
+	"github.com/eawag-rdm/pc/pkg/workspace"
 )
 
 // Structures for final parsed configuration
 type TestConfig struct {
-	Blacklist        []string
-	Whitelist        []string
-	KeywordArguments []map[string]interface{}
+	Blacklist         []string
+	Whitelist         []string
+	IncludeExtensions []string // Limit the check to these extensions or MIME globs, see MatchesFileType
+	ExcludeExtensions []string // Skip files with these extensions or MIME globs
+	KeywordArguments  []map[string]interface{}
+	Severity          string // Overrides the severity of the check: "error", "warning" or "info"
+	ReportOnly        bool   // Reports the issues of the check without counting them towards thresholds
 }
 
 type CollectorConfig struct {
 	Attrs map[string]interface{}
 }
 
+// NotifierConfig holds the settings of a notifier section, e.g. [notify.webhook]
+type NotifierConfig struct {
+	Attrs map[string]interface{}
+}
+
+// PluginConfig describes an external check executable, e.g. [plugin.NetCDFMetadata]
+type PluginConfig struct {
+	Command []string      // Executable followed by its arguments
+	Scope   string        // "file" (default) or "repository"
+	Timeout time.Duration // Time before the executable is killed, 0 uses the default
+}
+
+// RuleConfig is a check written as an expression, e.g. [rule.LargeCSV]
+type RuleConfig struct {
+	Rule    string // Expression evaluated per file, an issue is reported when it is true
+	Message string // Template for the issue message
+}
+
+// OperationConfig is an [operation.<profile>] section. [operation.main] is used unless
+// another profile is selected, which then overrides the values it sets (see ApplyProfile).
 type OperationConfig struct {
-	Collector string
+	Collector              string
+	Checks                 []string               // Names of the checks to run, nil runs all checks
+	MaxArchiveFileSize     int64                  // Overrides [general] if > 0
+	MaxTotalArchiveMemory  int64                  // Overrides [general] if > 0
+	MaxContentScanFileSize int64                  // Overrides [general] if > 0
+	Tests                  map[string]*TestConfig // Replace the [test.*] sections of the same name
 }
 
+// Scan strategies, set with the scanStrategy of [general]
+const (
+	// ScanStrategyFull checks the whole content of files up to the maxContentScanFileSize
+	ScanStrategyFull = "full"
+	// ScanStrategySample checks text files above the sampleThreshold in samples only: windows at
+	// the start and end and blocks at random positions in between
+	ScanStrategySample = "sample"
+)
+
 type GeneralConfig struct {
-	MaxArchiveFileSize     int64 // Maximum size for individual files in archives (bytes)
-	MaxTotalArchiveMemory  int64 // Maximum total memory for archive processing (bytes)
-	MaxContentScanFileSize int64 // Maximum size for files that read content (like IsFreeOfKeywords) (bytes)
+	MaxArchiveFileSize     int64         // Maximum size for individual files in archives (bytes)
+	MaxTotalArchiveMemory  int64         // Maximum total memory for archive processing (bytes)
+	MaxContentScanFileSize int64         // Maximum size for files that read content (like IsFreeOfKeywords) (bytes)
+	MaxTotalMemory         int64         // Memory the checks of a scan may hold at once, archives included (bytes), 0 for no limit
+	ScanStrategy           string        // ScanStrategyFull or ScanStrategySample, empty for ScanStrategyFull
+	SampleThreshold        int64         // Size from which text files are checked in samples with ScanStrategySample (bytes)
+	SampleWindow           int64         // Bytes checked at the start and end of a sampled file and in each block in between
+	SampleBlocks           int           // Blocks checked between the start and end of a sampled file
+	HistoryDir             string        // Directory where scan results are stored for diffing, empty disables the history
+	OutputDir              string        // Directory relative report paths are written to, empty for the working directory
+	WorkspaceDir           string        // Directory the temporary files of the scans are kept in, empty for the system default
+	MaxWorkspaceSize       int64         // Temporary files a scan may keep at once, such as CKAN downloads (bytes), 0 for no limit
+	Baseline               string        // File of triaged findings hidden by scans, empty for pc-baseline.json
+	MaxFindingsPerCheck    int           // Findings of a check in one file reported before the rest is summarized, 0 for no limit
+	FileTimeout            time.Duration // Time the checks of a file may take before it is skipped, 0 for no limit
+	ScanTimeout            time.Duration // Time a scan may take before the remaining files are skipped, 0 for no limit
+	Language               string        // Language of the reports, empty for English
+	Timezone               string        // Time zone of the times shown to people, empty for the local one
+	LetterTemplate         string        // Go text/template of the copy-paste summary for depositors, empty for the built-in text
+	SummaryMaxIssues       int           // Issues of a group of similar issues listed in the summaries before the rest is counted, 0 for all
+	SummaryMinGroupSize    int           // Groups of similar issues with fewer issues are listed completely in the summaries
+	Redact                 bool          // Redact secrets, user names and absolute paths in all output, for shared reports
+	ListArchives           bool          // Add the members of the archives to the reports, see -list-archives
+	FileMetadata           bool          // Add the size and modification time of the files to the scanned files of the reports
+	HashFiles              bool          // Also add the SHA-256 of the files, which reads every file once more
 }
 
 type Config struct {
@@ -33,6 +103,28 @@ type Config struct {
 	Tests      map[string]*TestConfig
 	Operation  map[string]*OperationConfig
 	Collectors map[string]*CollectorConfig
+	Notifiers  map[string]*NotifierConfig
+	Plugins    map[string]*PluginConfig
+	Rules      map[string]*RuleConfig
+
+	ctx     context.Context           // Cancels the scan run with this config, see WithContext
+	memory  *performance.MemoryBudget // Memory shared by the checks of the scan, see WithMemoryBudget
+	content *performance.FileContent  // Content of the file being checked, see WithFileContent
+
+	patterns *performance.PatternCache // Patterns compiled during the scan, see WithPatternCache
+	pdfs     *helpers.FileTracker      // PDF files found by the scan, see WithPDFTracker
+	skipped  *output.SkippedFiles      // Files the scan did not check, see WithSkippedFiles
+	redactor *output.Redactor          // Redacts the reports of the scan, see WithRedactor
+
+	workspace        *workspace.Workspace    // Temporary files of the scan, see WithWorkspace
+	downloadDir      string                  // Directory collectors download to, see WithDownloadDir
+	downloadProgress func(done, total int64) // Reports the bytes downloaded, see WithDownloadProgress
+	archiveProgress  ArchiveProgress         // Reports the members of archives checked, see WithArchiveProgress
+	metadata         *structs.Metadata       // Package metadata checked with the files, see WithMetadata
+	packageRoot      string                  // Folder of the package on disk, see WithPackageRoot
+	tracer           *trace.Tracer           // Records the decisions of the scan for -trace, see WithTracer
+
+	skipChecks []string // Checks not run whatever the profile enables, see SelectChecks
 }
 
 // ParseConfigNew parses the TOML file into a ConfigNew structure
@@ -41,16 +133,30 @@ func ParseConfig(filename string) (*Config, error) {
 	if _, err := toml.DecodeFile(filename, &raw); err != nil {
 		return nil, err
 	}
+	return parseRaw(raw)
+}
 
+// parseRaw builds the configuration from the decoded TOML values
+func parseRaw(raw map[string]interface{}) (*Config, error) {
 	c := &Config{
 		General: &GeneralConfig{
 			MaxArchiveFileSize:     10 * 1024 * 1024,       // 10MB default
 			MaxTotalArchiveMemory:  100 * 1024 * 1024,      // 100MB default
 			MaxContentScanFileSize: 1024 * 1024 * 1024,     // 1GB default for content scanning
+			MaxTotalMemory:         1024 * 1024 * 1024,     // 1GB default for the whole scan
+			SampleThreshold:        1024 * 1024 * 1024,     // 1GB default, as the maxContentScanFileSize
+			SampleWindow:           16 * 1024 * 1024,       // 16MB at the start, the end and in each block
+			SampleBlocks:           16,
+			MaxFindingsPerCheck:    100,
+			SummaryMaxIssues:       5,
+			SummaryMinGroupSize:    3,
 		},
 		Tests:      map[string]*TestConfig{},
 		Operation:  map[string]*OperationConfig{},
 		Collectors: map[string]*CollectorConfig{},
+		Notifiers:  map[string]*NotifierConfig{},
+		Plugins:    map[string]*PluginConfig{},
+		Rules:      map[string]*RuleConfig{},
 	}
 
 	parseStringSlice := func(data []interface{}) []string {
@@ -72,6 +178,10 @@ func ParseConfig(filename string) (*Config, error) {
 					switch val := v.(type) {
 					case string:
 						kwSet[k] = val
+					case bool:
+						kwSet[k] = val
+					case int64:
+						kwSet[k] = val
 					case []interface{}:
 						kwSet[k] = parseStringSlice(val)
 					}
@@ -82,21 +192,9 @@ func ParseConfig(filename string) (*Config, error) {
 		return result
 	}
 
-	// Parse general section
-	if generalData, ok := raw["general"].(map[string]interface{}); ok {
-		if maxArchiveFileSize, ok := generalData["maxArchiveFileSize"].(int64); ok {
-			c.General.MaxArchiveFileSize = maxArchiveFileSize
-		}
-		if maxTotalArchiveMemory, ok := generalData["maxTotalArchiveMemory"].(int64); ok {
-			c.General.MaxTotalArchiveMemory = maxTotalArchiveMemory
-		}
-		if maxContentScanFileSize, ok := generalData["maxContentScanFileSize"].(int64); ok {
-			c.General.MaxContentScanFileSize = maxContentScanFileSize
-		}
-	}
-
-	if testData, ok := raw["test"].(map[string]interface{}); ok {
-		for name, section := range testData {
+	parseTests := func(data map[string]interface{}) map[string]*TestConfig {
+		tests := map[string]*TestConfig{}
+		for name, section := range data {
 			tc := &TestConfig{}
 			if sectionMap, ok := section.(map[string]interface{}); ok {
 				if bl, ok := sectionMap["blacklist"].([]interface{}); ok {
@@ -105,14 +203,139 @@ func ParseConfig(filename string) (*Config, error) {
 				if wl, ok := sectionMap["whitelist"].([]interface{}); ok {
 					tc.Whitelist = parseStringSlice(wl)
 				}
+				if include, ok := sectionMap["include_extensions"].([]interface{}); ok {
+					tc.IncludeExtensions = parseStringSlice(include)
+				}
+				if exclude, ok := sectionMap["exclude_extensions"].([]interface{}); ok {
+					tc.ExcludeExtensions = parseStringSlice(exclude)
+				}
 				if kwArgs, ok := sectionMap["keywordArguments"].([]interface{}); ok {
 					tc.KeywordArguments = parseKeywordArguments(kwArgs)
 				}
+				if severity, ok := sectionMap["severity"].(string); ok {
+					tc.Severity = severity
+				}
+				if reportOnly, ok := sectionMap["report_only"].(bool); ok {
+					tc.ReportOnly = reportOnly
+				}
+			}
+			tests[name] = tc
+		}
+		return tests
+	}
+
+	// Sizes are given in bytes or with a unit, e.g. "100MB"
+	parseSizes := func(section string, data map[string]interface{}, targets map[string]*int64) error {
+		for key, target := range targets {
+			value, ok := data[key]
+			if !ok {
+				continue
+			}
+			size, err := ParseSize(value)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", section, key, err)
+			}
+			*target = size
+		}
+		return nil
+	}
+
+	// Parse general section
+	if generalData, ok := raw["general"].(map[string]interface{}); ok {
+		err := parseSizes("general", generalData, map[string]*int64{
+			"maxArchiveFileSize":     &c.General.MaxArchiveFileSize,
+			"maxTotalArchiveMemory":  &c.General.MaxTotalArchiveMemory,
+			"maxContentScanFileSize": &c.General.MaxContentScanFileSize,
+			"maxTotalMemory":         &c.General.MaxTotalMemory,
+			"maxWorkspaceSize":       &c.General.MaxWorkspaceSize,
+			"sampleThreshold":        &c.General.SampleThreshold,
+			"sampleWindow":           &c.General.SampleWindow,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if historyDir, ok := generalData["historyDir"].(string); ok {
+			c.General.HistoryDir = historyDir
+		}
+		if outputDir, ok := generalData["outputDir"].(string); ok {
+			c.General.OutputDir = outputDir
+		}
+		if workspaceDir, ok := generalData["workspaceDir"].(string); ok {
+			c.General.WorkspaceDir = workspaceDir
+		}
+		if scanStrategy, ok := generalData["scanStrategy"].(string); ok {
+			c.General.ScanStrategy = scanStrategy
+		}
+		if value, ok := generalData["sampleBlocks"]; ok {
+			blocks, isInt := value.(int64)
+			if !isInt || blocks < 0 {
+				return nil, fmt.Errorf("general.sampleBlocks: expected a number of blocks, got %v", value)
+			}
+			c.General.SampleBlocks = int(blocks)
+		}
+		if baseline, ok := generalData["baseline"].(string); ok {
+			c.General.Baseline = baseline
+		}
+		if language, ok := generalData["language"].(string); ok {
+			c.General.Language = language
+		}
+		if timezone, ok := generalData["timezone"].(string); ok {
+			c.General.Timezone = timezone
+		}
+		if letterTemplate, ok := generalData["letterTemplate"].(string); ok {
+			c.General.LetterTemplate = letterTemplate
+		}
+		if redact, ok := generalData["redact"].(bool); ok {
+			c.General.Redact = redact
+		}
+		if listArchives, ok := generalData["listArchives"].(bool); ok {
+			c.General.ListArchives = listArchives
+		}
+		if fileMetadata, ok := generalData["fileMetadata"].(bool); ok {
+			c.General.FileMetadata = fileMetadata
+		}
+		if hashFiles, ok := generalData["hashFiles"].(bool); ok {
+			c.General.HashFiles = hashFiles
+		}
+		if value, ok := generalData["maxFindingsPerCheck"]; ok {
+			limit, isInt := value.(int64)
+			if !isInt || limit < 0 {
+				return nil, fmt.Errorf("general.maxFindingsPerCheck: expected a number of findings, 0 for no limit, got %v", value)
+			}
+			c.General.MaxFindingsPerCheck = int(limit)
+		}
+		summaryLimits := map[string]*int{
+			"summaryMaxIssues":    &c.General.SummaryMaxIssues,
+			"summaryMinGroupSize": &c.General.SummaryMinGroupSize,
+		}
+		for key, target := range summaryLimits {
+			if value, ok := generalData[key]; ok {
+				limit, isInt := value.(int64)
+				if !isInt || limit < 0 {
+					return nil, fmt.Errorf("general.%s: expected a number of issues, got %v", key, value)
+				}
+				*target = int(limit)
+			}
+		}
+		timeouts := map[string]*time.Duration{
+			"fileTimeout": &c.General.FileTimeout,
+			"scanTimeout": &c.General.ScanTimeout,
+		}
+		for key, target := range timeouts {
+			if value, ok := generalData[key]; ok {
+				timeout, err := ParseDuration(value)
+				if err != nil {
+					return nil, fmt.Errorf("general.%s: %w", key, err)
+				}
+				*target = timeout
 			}
-			c.Tests[name] = tc
 		}
 	}
 
+	if testData, ok := raw["test"].(map[string]interface{}); ok {
+		c.Tests = parseTests(testData)
+	}
+
 	if collectorData, ok := raw["collector"].(map[string]interface{}); ok {
 		for name, section := range collectorData {
 			cc := &CollectorConfig{Attrs: make(map[string]interface{})}
@@ -124,6 +347,8 @@ func ParseConfig(filename string) (*Config, error) {
 							cc.Attrs[k] = val
 						case bool:
 							cc.Attrs[k] = val
+						case int64, float64:
+							cc.Attrs[k] = val
 						case []interface{}:
 							cc.Attrs[k] = parseStringSlice(val)
 						}
@@ -134,6 +359,63 @@ func ParseConfig(filename string) (*Config, error) {
 		}
 	}
 
+	if notifyData, ok := raw["notify"].(map[string]interface{}); ok {
+		for name, section := range notifyData {
+			nc := &NotifierConfig{Attrs: make(map[string]interface{})}
+			if sectionMap, ok := section.(map[string]interface{}); ok {
+				for k, v := range sectionMap {
+					switch val := v.(type) {
+					case string, bool, int64:
+						nc.Attrs[k] = val
+					case []interface{}:
+						nc.Attrs[k] = parseStringSlice(val)
+					}
+				}
+			}
+			c.Notifiers[name] = nc
+		}
+	}
+
+	if pluginData, ok := raw["plugin"].(map[string]interface{}); ok {
+		for name, section := range pluginData {
+			pc := &PluginConfig{Scope: "file"}
+			if sectionMap, ok := section.(map[string]interface{}); ok {
+				switch command := sectionMap["command"].(type) {
+				case string:
+					pc.Command = []string{command}
+				case []interface{}:
+					pc.Command = parseStringSlice(command)
+				}
+				if scope, ok := sectionMap["scope"].(string); ok {
+					pc.Scope = scope
+				}
+				if value, ok := sectionMap["timeout"]; ok {
+					timeout, err := ParseDuration(value)
+					if err != nil {
+						return nil, fmt.Errorf("plugin.%s.timeout: %w", name, err)
+					}
+					pc.Timeout = timeout
+				}
+			}
+			c.Plugins[name] = pc
+		}
+	}
+
+	if ruleData, ok := raw["rule"].(map[string]interface{}); ok {
+		for name, section := range ruleData {
+			rc := &RuleConfig{}
+			if sectionMap, ok := section.(map[string]interface{}); ok {
+				if rule, ok := sectionMap["rule"].(string); ok {
+					rc.Rule = rule
+				}
+				if message, ok := sectionMap["message"].(string); ok {
+					rc.Message = message
+				}
+			}
+			c.Rules[name] = rc
+		}
+	}
+
 	if operationData, ok := raw["operation"].(map[string]interface{}); ok {
 		for name, section := range operationData {
 			oc := &OperationConfig{}
@@ -141,6 +423,23 @@ func ParseConfig(filename string) (*Config, error) {
 				if collector, ok := sectionMap["collector"].(string); ok {
 					oc.Collector = collector
 				}
+				if checks, ok := sectionMap["checks"].([]interface{}); ok {
+					oc.Checks = parseStringSlice(checks)
+					if oc.Checks == nil {
+						oc.Checks = []string{}
+					}
+				}
+				err := parseSizes("operation."+name, sectionMap, map[string]*int64{
+					"maxArchiveFileSize":     &oc.MaxArchiveFileSize,
+					"maxTotalArchiveMemory":  &oc.MaxTotalArchiveMemory,
+					"maxContentScanFileSize": &oc.MaxContentScanFileSize,
+				})
+				if err != nil {
+					return nil, err
+				}
+				if testData, ok := sectionMap["test"].(map[string]interface{}); ok {
+					oc.Tests = parseTests(testData)
+				}
 			}
 			c.Operation[name] = oc
 		}
@@ -159,13 +458,53 @@ func assesLists(blacklist []string, whitelist []string) error {
 	return nil
 }
 
-// LoadConfig loads the configuration from a TOML file and performs the necessary checks
+// LoadConfig loads the configuration from a TOML file or https:// URL (see ReadConfig),
+// applies the PC_* environment variables and performs the necessary checks
 func LoadConfig(file string) (*Config, error) {
-	var config *Config
-	config, err := ParseConfig(file)
+	return LoadConfigWithOverrides(file, nil)
+}
+
+// LoadConfigWithOverrides is LoadConfig with additional key=value overrides (the -set flag),
+// which take precedence over the environment variables
+func LoadConfigWithOverrides(file string, overrides []string) (*Config, error) {
+	data, err := ReadConfig(file, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file '%s': %w", file, err)
 	}
+	config, err := LoadConfigData(data, file, overrides, false)
+	if err != nil {
+		return nil, err
+	}
+	CacheConfig(file, data)
+	return config, nil
+}
+
+// LoadConfigData is LoadConfigWithOverrides for a config held in memory, e.g. the built-in
+// default. source names the config in error messages; with offline set the remote keyword files
+// it references are taken from the local cache only, see ReadConfig.
+func LoadConfigData(data []byte, source string, overrides []string, offline bool) (*Config, error) {
+	var raw map[string]interface{}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", source, err)
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	if err := applyEnvOverrides(raw, os.LookupEnv); err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		if err := applyOverride(raw, override); err != nil {
+			return nil, err
+		}
+	}
+	config, err := parseRaw(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error in config file '%s': %w", source, err)
+	}
+	if err := prepareKeywordArguments(config, source, offline); err != nil {
+		return nil, fmt.Errorf("error in config file '%s': %w", source, err)
+	}
 
 	for testName, test := range config.Tests {
 		if err := assesLists(test.Blacklist, test.Whitelist); err != nil {
@@ -173,25 +512,354 @@ func LoadConfig(file string) (*Config, error) {
 		}
 	}
 
+	for profileName, profile := range config.Operation {
+		for testName, test := range profile.Tests {
+			if err := assesLists(test.Blacklist, test.Whitelist); err != nil {
+				return nil, fmt.Errorf("error in test %s of profile %s: %v", testName, profileName, err)
+			}
+		}
+	}
+
+	for ruleName, rule := range config.Rules {
+		if rule.Rule == "" {
+			return nil, fmt.Errorf("error in rule %s: rule is required", ruleName)
+		}
+	}
+
+	for pluginName, plugin := range config.Plugins {
+		if len(plugin.Command) == 0 || plugin.Command[0] == "" {
+			return nil, fmt.Errorf("error in plugin %s: command is required", pluginName)
+		}
+		if plugin.Scope != "file" && plugin.Scope != "repository" {
+			return nil, fmt.Errorf("error in plugin %s: scope must be 'file' or 'repository', got '%s'", pluginName, plugin.Scope)
+		}
+	}
+
 	return config, nil
 }
 
-// check fore the default configurtion file 1. ~/.config/pc/config.toml 2. ./config.toml if exists return the path
+// DefaultProfile is the [operation.*] section used when no profile is selected
+const DefaultProfile = "main"
+
+// ApplyProfile merges the [operation.<name>] profile into [operation.main] and the [general]
+// and [test.*] sections, so the rest of the program only has to look at the merged values.
+// Values the profile does not set are inherited from [operation.main].
+func (c *Config) ApplyProfile(name string) error {
+	if name == "" {
+		name = DefaultProfile
+	}
+	profile, ok := c.Operation[name]
+	if !ok {
+		if name == DefaultProfile {
+			return nil
+		}
+		return fmt.Errorf("unknown profile '%s', add an [operation.%s] section", name, name)
+	}
+
+	if name != DefaultProfile {
+		merged := OperationConfig{}
+		if main, ok := c.Operation[DefaultProfile]; ok {
+			merged = *main
+		}
+		if profile.Collector != "" {
+			merged.Collector = profile.Collector
+		}
+		if profile.Checks != nil {
+			merged.Checks = profile.Checks
+		}
+		c.Operation[DefaultProfile] = &merged
+	}
+
+	if c.General == nil {
+		c.General = &GeneralConfig{}
+	}
+	if profile.MaxArchiveFileSize > 0 {
+		c.General.MaxArchiveFileSize = profile.MaxArchiveFileSize
+	}
+	if profile.MaxTotalArchiveMemory > 0 {
+		c.General.MaxTotalArchiveMemory = profile.MaxTotalArchiveMemory
+	}
+	if profile.MaxContentScanFileSize > 0 {
+		c.General.MaxContentScanFileSize = profile.MaxContentScanFileSize
+	}
+
+	if c.Tests == nil {
+		c.Tests = map[string]*TestConfig{}
+	}
+	for testName, test := range profile.Tests {
+		c.Tests[testName] = test
+	}
+	return nil
+}
+
+// WithContext returns a copy of the config whose scan stops once ctx is done. The config is
+// passed to the collectors and every check, which check Context between files and archive members.
+func (c Config) WithContext(ctx context.Context) Config {
+	c.ctx = ctx
+	return c
+}
+
+// Context returns the context of the scan, context.Background() unless set with WithContext
+func (c Config) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// WithMemoryBudget returns a copy of the config whose checks share budget, see MemoryBudget
+func (c Config) WithMemoryBudget(budget *performance.MemoryBudget) Config {
+	c.memory = budget
+	return c
+}
+
+// MemoryBudget returns the memory budget of the scan, nil (no limit) unless set with WithMemoryBudget
+func (c Config) MemoryBudget() *performance.MemoryBudget {
+	return c.memory
+}
+
+// WithPatternCache returns a copy of the config whose checks compile their patterns once in cache
+func (c Config) WithPatternCache(cache *performance.PatternCache) Config {
+	c.patterns = cache
+	return c
+}
+
+// PatternCache returns the pattern cache of the scan, nil (no caching) unless set with
+// WithPatternCache
+func (c Config) PatternCache() *performance.PatternCache {
+	return c.patterns
+}
+
+// WithPDFTracker returns a copy of the config whose scan lists the PDF files it finds in tracker,
+// for the reports
+func (c Config) WithPDFTracker(tracker *helpers.FileTracker) Config {
+	c.pdfs = tracker
+	return c
+}
+
+// PDFTracker returns the PDF files found by the scan, nil (not tracked) unless set with
+// WithPDFTracker
+func (c Config) PDFTracker() *helpers.FileTracker {
+	return c.pdfs
+}
+
+// WithSkippedFiles returns a copy of the config whose scan records the files it does not check
+// in skipped, for the reports
+func (c Config) WithSkippedFiles(skipped *output.SkippedFiles) Config {
+	c.skipped = skipped
+	return c
+}
+
+// SkippedFiles returns the files the scan did not check, nil (not recorded) unless set with
+// WithSkippedFiles
+func (c Config) SkippedFiles() *output.SkippedFiles {
+	return c.skipped
+}
+
+// WithRedactor returns a copy of the config whose checks tell redactor what else to hide in the
+// reports, such as the user names they find
+func (c Config) WithRedactor(redactor *output.Redactor) Config {
+	c.redactor = redactor
+	return c
+}
+
+// Redactor returns the redactor of the reports of the scan, nil (not redacted) unless set with
+// WithRedactor
+func (c Config) Redactor() *output.Redactor {
+	return c.redactor
+}
+
+// WithFileContent returns a copy of the config for the checks of one file, which share its content
+func (c Config) WithFileContent(content *performance.FileContent) Config {
+	c.content = content
+	return c
+}
+
+// FileContent returns the content shared by the checks of the file being checked, nil unless set
+// with WithFileContent
+func (c Config) FileContent() *performance.FileContent {
+	return c.content
+}
+
+// WithWorkspace returns a copy of the config whose scan keeps its temporary files in ws. The
+// caller closes the workspace once the scan is done.
+func (c Config) WithWorkspace(ws *workspace.Workspace) Config {
+	c.workspace = ws
+	return c
+}
+
+// Workspace returns the workspace of the scan, nil (no limit, not removed) unless set with
+// WithWorkspace
+func (c Config) Workspace() *workspace.Workspace {
+	return c.workspace
+}
+
+// NewWorkspace returns a workspace in the workspaceDir of the [general] section limited to its
+// maxWorkspaceSize, for the caller to set with WithWorkspace and close after the scan
+func (c Config) NewWorkspace() *workspace.Workspace {
+	if c.General == nil {
+		return workspace.New("", 0)
+	}
+	return workspace.New(c.General.WorkspaceDir, c.General.MaxWorkspaceSize)
+}
+
+// WithDownloadDir returns a copy of the config whose collectors download into dir. The caller
+// removes dir once the downloaded files are no longer needed.
+func (c Config) WithDownloadDir(dir string) Config {
+	c.downloadDir = dir
+	return c
+}
+
+// DownloadDir returns the directory collectors download to, "" unless set with WithDownloadDir
+func (c Config) DownloadDir() string {
+	return c.downloadDir
+}
+
+// WithDownloadProgress returns a copy of the config whose collectors report the bytes they
+// downloaded so far and the bytes they expect in total to progress
+func (c Config) WithDownloadProgress(progress func(done, total int64)) Config {
+	c.downloadProgress = progress
+	return c
+}
+
+// DownloadProgress returns the function downloads are reported to, one doing nothing unless set
+// with WithDownloadProgress
+func (c Config) DownloadProgress() func(done, total int64) {
+	if c.downloadProgress == nil {
+		return func(done, total int64) {}
+	}
+	return c.downloadProgress
+}
+
+// ArchiveProgress is told about each member of an archive the content checks have scanned.
+// total is the number of entries of the archive, 0 if it is not known in advance (tar archives).
+type ArchiveProgress func(archive string, member, total int)
+
+// WithArchiveProgress returns a copy of the config whose content checks report the members of
+// archives they scanned to progress. Archives are scanned in parallel, so progress must be safe
+// for concurrent use.
+func (c Config) WithArchiveProgress(progress ArchiveProgress) Config {
+	c.archiveProgress = progress
+	return c
+}
+
+// ArchiveProgress returns the function the members of archives are reported to, one doing
+// nothing unless set with WithArchiveProgress
+func (c Config) ArchiveProgress() ArchiveProgress {
+	if c.archiveProgress == nil {
+		return func(archive string, member, total int) {}
+	}
+	return c.archiveProgress
+}
+
+// WithPackageRoot returns a copy of the config whose checks take the paths of the files relative
+// to root, the folder the package was collected from
+func (c Config) WithPackageRoot(root string) Config {
+	c.packageRoot = root
+	return c
+}
+
+// PackageRoot returns the folder the package was collected from, "" unless set with WithPackageRoot
+func (c Config) PackageRoot() string {
+	return c.packageRoot
+}
+
+// WithMetadata returns a copy of the config whose scan also runs the metadata checks on metadata
+func (c Config) WithMetadata(metadata structs.Metadata) Config {
+	c.metadata = &metadata
+	return c
+}
+
+// Metadata returns the package metadata the scan checks, nil unless set with WithMetadata
+func (c Config) Metadata() *structs.Metadata {
+	return c.metadata
+}
+
+// WithTracer returns a copy of the config whose scan records per file which checks ran or were
+// skipped and why in tracer
+func (c Config) WithTracer(tracer *trace.Tracer) Config {
+	c.tracer = tracer
+	return c
+}
+
+// Tracer returns the tracer of the scan, nil unless set with WithTracer. A nil tracer records
+// nothing.
+func (c Config) Tracer() *trace.Tracer {
+	return c.tracer
+}
+
+// SelectChecks overrides the checks of the active profile at runtime, e.g. from the command line.
+// A non-empty only replaces the checks the profile runs, the checks in skip do not run either way.
+func (c *Config) SelectChecks(only, skip []string) {
+	if len(only) > 0 {
+		if c.Operation == nil {
+			c.Operation = map[string]*OperationConfig{}
+		}
+		merged := OperationConfig{}
+		if main, ok := c.Operation[DefaultProfile]; ok {
+			merged = *main
+		}
+		merged.Checks = only
+		c.Operation[DefaultProfile] = &merged
+	}
+	c.skipChecks = skip
+}
+
+// IsCheckEnabled reports whether the check (built-in, rule or plugin) runs with the active profile
+func (c Config) IsCheckEnabled(name string) bool {
+	for _, check := range c.skipChecks {
+		if check == name {
+			return false
+		}
+	}
+	main, ok := c.Operation[DefaultProfile]
+	if !ok || main.Checks == nil {
+		return true
+	}
+	for _, check := range main.Checks {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
+
+// XDGConfigPath returns the per-user config file, $XDG_CONFIG_HOME/pc/pc.toml (~/.config/pc/pc.toml by default)
+func XDGConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "pc", "pc.toml")
+}
+
+// ConfigEnvVar holds the path or https:// URL of the config, for containers without a config file
+// in the working directory
+const ConfigEnvVar = "PC_CONFIG"
+
+// FindConfigFile returns $PC_CONFIG if it is set, else the first existing config file of
+// 1. ./pc.toml 2. $XDG_CONFIG_HOME/pc/pc.toml 3. ~/.config/pc.toml 4. ~/pc.toml
+// or "" if there is none
 func FindConfigFile() string {
-	// check for the default configuration file
-	// 1. ~/.config/pc/config.toml
-	// 2. ./config.toml
-	paths := []string{
-		"~/pc.toml",
-		"./pc.toml",
-		"~/.config/pc.toml",
+	if location := os.Getenv(ConfigEnvVar); location != "" {
+		return location
+	}
+	paths := []string{"./pc.toml", XDGConfigPath()}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "pc.toml"), filepath.Join(home, "pc.toml"))
 	}
 
 	for _, path := range paths {
+		if path == "" {
+			continue
+		}
 		if _, err := os.Stat(path); err == nil {
 			return path
 		}
 	}
 	return ""
-
 }
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/eawag-rdm/pc/pkg/performance"
 )
 
 func createTempConfigFile(t *testing.T, content string) string {
@@ -107,10 +113,10 @@ func TestParseConfig(t *testing.T) {
 	keywordArguments = [{ "arg1" = "value1" }, {"arg1" = "value1", "arg2" = ["value2", "value3"] }]
 
 	[test.test2]
-	keywordArguments = [{"arg1" = "value1", "arg2" = ["/path/", "C:/path/"] }]
+	keywordArguments = [{"arg1" = "value1", "arg2" = ["/path/", "C:/path/"], "arg3" = 64 }]
 
 	[collector.collector1]
-	attrs = { "key1" = "value1", "key2" = ["value2", "value3"] }
+	attrs = { "key1" = "value1", "key2" = ["value2", "value3"], "key3" = 2, "key4" = 0.5 }
 	`
 	tmpFile, err := os.CreateTemp("", "test_config_*.toml")
 	assert.NoError(t, err)
@@ -144,11 +150,14 @@ func TestParseConfig(t *testing.T) {
 	assert.Equal(t, "value1", testConfig2.KeywordArguments[0]["arg1"])
 	assert.ElementsMatch(t, []string{"/path/", "C:/path/"}, testConfig2.KeywordArguments[0]["arg2"])
 	assert.Equal(t, 2, len(testConfig2.KeywordArguments[0]["arg2"].([]string)))
+	assert.Equal(t, int64(64), testConfig2.KeywordArguments[0]["arg3"])
 
 	collectorConfig, ok := config.Collectors["collector1"]
 	assert.True(t, ok)
 	assert.Equal(t, "value1", collectorConfig.Attrs["key1"])
 	assert.ElementsMatch(t, []string{"value2", "value3"}, collectorConfig.Attrs["key2"])
+	assert.Equal(t, int64(2), collectorConfig.Attrs["key3"])
+	assert.Equal(t, 0.5, collectorConfig.Attrs["key4"])
 
 	operationConfig, ok := config.Operation["main"]
 	assert.True(t, ok)
@@ -156,6 +165,161 @@ func TestParseConfig(t *testing.T) {
 
 }
 
+func TestParseConfigNotifiers(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[notify.webhook]
+	url = "https://chat.example.org/hooks/abc"
+	threshold = 3
+	enabled = true
+	`)
+	defer os.Remove(configFile)
+
+	config, err := ParseConfig(configFile)
+	assert.NoError(t, err)
+
+	notifier, ok := config.Notifiers["webhook"]
+	assert.True(t, ok)
+	assert.Equal(t, "https://chat.example.org/hooks/abc", notifier.Attrs["url"])
+	assert.Equal(t, int64(3), notifier.Attrs["threshold"])
+	assert.Equal(t, true, notifier.Attrs["enabled"])
+}
+
+func TestParseConfigPlugins(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[plugin.NetCDFMetadata]
+	command = ["/usr/local/bin/check-netcdf", "--strict"]
+	timeout = 10
+
+	[plugin.Licence]
+	command = "check-licence"
+	scope = "repository"
+	`)
+	defer os.Remove(configFile)
+
+	config, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+
+	netcdf := config.Plugins["NetCDFMetadata"]
+	assert.Equal(t, []string{"/usr/local/bin/check-netcdf", "--strict"}, netcdf.Command)
+	assert.Equal(t, "file", netcdf.Scope)
+	assert.Equal(t, 10*time.Second, netcdf.Timeout)
+
+	licence := config.Plugins["Licence"]
+	assert.Equal(t, []string{"check-licence"}, licence.Command)
+	assert.Equal(t, "repository", licence.Scope)
+}
+
+func TestLoadConfigInvalidPlugins(t *testing.T) {
+	for _, content := range []string{
+		"[plugin.NoCommand]\nscope = \"file\"",
+		"[plugin.BadScope]\ncommand = \"x\"\nscope = \"archive\"",
+	} {
+		configFile := createTempConfigFile(t, content)
+		defer os.Remove(configFile)
+
+		_, err := LoadConfig(configFile)
+		assert.Error(t, err)
+	}
+}
+
+func TestParseConfigRules(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[rule.LargeCSV]
+	rule = "file.size > 1e9 && file.ext == '.csv'"
+	message = "Large CSV file"
+
+	[rule.Empty]
+	message = "no rule"
+	`)
+	defer os.Remove(configFile)
+
+	config, err := ParseConfig(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "file.size > 1e9 && file.ext == '.csv'", config.Rules["LargeCSV"].Rule)
+	assert.Equal(t, "Large CSV file", config.Rules["LargeCSV"].Message)
+
+	// A rule without expression is rejected when loading
+	_, err = LoadConfig(configFile)
+	assert.Error(t, err)
+}
+
+func TestParseConfigFileTypes(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[test.IsFreeOfKeywords]
+	include_extensions = ["py", ".R", "text/*"]
+	exclude_extensions = ["csv"]
+	`)
+	defer os.Remove(configFile)
+
+	config, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"py", ".R", "text/*"}, config.Tests["IsFreeOfKeywords"].IncludeExtensions)
+	assert.Equal(t, []string{"csv"}, config.Tests["IsFreeOfKeywords"].ExcludeExtensions)
+}
+
+func TestApplyProfile(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[general]
+	maxArchiveFileSize = 100
+	maxTotalArchiveMemory = 200
+
+	[operation.main]
+	collector = "LocalCollector"
+
+	[operation.quick]
+	checks = ["HasOnlyASCII", "IsValidName"]
+	maxArchiveFileSize = 10
+
+	[operation.quick.test.IsValidName]
+	keywordArguments = [{ disallowed_names = [".DS_Store"] }]
+
+	[test.IsValidName]
+	keywordArguments = [{ disallowed_names = ["venv", "__pycache__"] }]
+	severity = "error"
+	report_only = true
+	`)
+	defer os.Remove(configFile)
+
+	config, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.True(t, config.IsCheckEnabled("HasReadme"))
+	assert.Equal(t, "error", config.Tests["IsValidName"].Severity)
+	assert.True(t, config.Tests["IsValidName"].ReportOnly)
+
+	assert.NoError(t, config.ApplyProfile("quick"))
+	assert.Equal(t, "LocalCollector", config.Operation["main"].Collector)
+	assert.Equal(t, int64(10), config.General.MaxArchiveFileSize)
+	assert.Equal(t, int64(200), config.General.MaxTotalArchiveMemory)
+	assert.Equal(t, []string{".DS_Store"}, config.Tests["IsValidName"].KeywordArguments[0]["disallowed_names"])
+	assert.True(t, config.IsCheckEnabled("IsValidName"))
+	assert.False(t, config.IsCheckEnabled("HasReadme"))
+
+	assert.Error(t, config.ApplyProfile("strict"))
+	assert.NoError(t, (&Config{}).ApplyProfile(""))
+}
+
+func TestSelectChecks(t *testing.T) {
+	config := &Config{Operation: map[string]*OperationConfig{
+		"main": {Collector: "LocalCollector", Checks: []string{"HasOnlyASCII", "IsValidName"}},
+	}}
+	config.SelectChecks(nil, []string{"IsValidName"})
+	assert.True(t, config.IsCheckEnabled("HasOnlyASCII"))
+	assert.False(t, config.IsCheckEnabled("IsValidName"))
+	assert.False(t, config.IsCheckEnabled("HasReadme"))
+
+	config.SelectChecks([]string{"HasReadme", "IsValidName"}, []string{"IsValidName"})
+	assert.Equal(t, "LocalCollector", config.Operation["main"].Collector)
+	assert.True(t, config.IsCheckEnabled("HasReadme"))
+	assert.False(t, config.IsCheckEnabled("HasOnlyASCII"))
+	assert.False(t, config.IsCheckEnabled("IsValidName"))
+
+	// Without a profile all checks run but the skipped ones
+	empty := &Config{}
+	empty.SelectChecks(nil, []string{"HasReadme"})
+	assert.True(t, empty.IsCheckEnabled("IsValidName"))
+	assert.False(t, empty.IsCheckEnabled("HasReadme"))
+}
+
 func TestAssesLists(t *testing.T) {
 	tests := []struct {
 		blacklist []string
@@ -178,3 +342,187 @@ func TestAssesLists(t *testing.T) {
 		}
 	}
 }
+
+func TestFindConfigFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Setenv(ConfigEnvVar, "")
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(t.TempDir())
+
+	assert.Equal(t, "", FindConfigFile())
+
+	os.WriteFile(filepath.Join(home, "pc.toml"), []byte(""), 0644)
+	assert.Equal(t, filepath.Join(home, "pc.toml"), FindConfigFile())
+
+	xdg := XDGConfigPath()
+	assert.Equal(t, filepath.Join(home, "xdg", "pc", "pc.toml"), xdg)
+	os.MkdirAll(filepath.Dir(xdg), 0755)
+	os.WriteFile(xdg, []byte(""), 0644)
+	assert.Equal(t, xdg, FindConfigFile())
+
+	os.WriteFile("pc.toml", []byte(""), 0644)
+	assert.Equal(t, "./pc.toml", FindConfigFile())
+
+	t.Setenv(ConfigEnvVar, "https://policies.example.org/pc.toml")
+	assert.Equal(t, "https://policies.example.org/pc.toml", FindConfigFile())
+}
+
+func TestLoadConfigData(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "LocalCollector", config.Operation["main"].Collector)
+
+	_, err = LoadConfigData([]byte("[general"), "inline", nil, false)
+	assert.ErrorContains(t, err, "'inline'")
+}
+
+func TestParseConfigMaxFindingsPerCheck(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, config.General.MaxFindingsPerCheck)
+
+	config, err = LoadConfigData([]byte("[general]\nmaxFindingsPerCheck = 0\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, config.General.MaxFindingsPerCheck)
+
+	_, err = LoadConfigData([]byte("[general]\nmaxFindingsPerCheck = -1\n"), "inline", nil, false)
+	assert.ErrorContains(t, err, "general.maxFindingsPerCheck")
+}
+
+func TestParseConfigMaxTotalMemory(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024*1024*1024), config.General.MaxTotalMemory)
+	assert.Nil(t, config.MemoryBudget())
+
+	config, err = LoadConfigData([]byte("[general]\nmaxTotalMemory = \"2GiB\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2<<30), config.General.MaxTotalMemory)
+
+	config, err = LoadConfigData([]byte("[general]\nmaxTotalMemory = 0\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), config.General.MaxTotalMemory)
+
+	budget := performance.NewMemoryBudget(100)
+	assert.Same(t, budget, config.WithMemoryBudget(budget).MemoryBudget())
+	assert.Nil(t, config.MemoryBudget())
+
+	content := performance.NewFileContent("file.txt", budget)
+	assert.Same(t, content, config.WithFileContent(content).FileContent())
+	assert.Nil(t, config.FileContent())
+}
+
+func TestParseConfigScanStrategy(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.General.ScanStrategy)
+	assert.Equal(t, int64(1<<30), config.General.SampleThreshold)
+	assert.Equal(t, int64(16<<20), config.General.SampleWindow)
+	assert.Equal(t, 16, config.General.SampleBlocks)
+
+	config, err = LoadConfigData([]byte("[general]\nscanStrategy = \"sample\"\nsampleThreshold = \"10GiB\"\nsampleWindow = \"1MiB\"\nsampleBlocks = 4\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ScanStrategySample, config.General.ScanStrategy)
+	assert.Equal(t, int64(10<<30), config.General.SampleThreshold)
+	assert.Equal(t, int64(1<<20), config.General.SampleWindow)
+	assert.Equal(t, 4, config.General.SampleBlocks)
+
+	_, err = LoadConfigData([]byte("[general]\nsampleBlocks = \"many\"\n"), "inline", nil, false)
+	assert.ErrorContains(t, err, "general.sampleBlocks")
+}
+
+func TestParseConfigFileMetadata(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.False(t, config.General.FileMetadata)
+	assert.False(t, config.General.HashFiles)
+
+	config, err = LoadConfigData([]byte("[general]\nfileMetadata = true\nhashFiles = true\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.True(t, config.General.FileMetadata)
+	assert.True(t, config.General.HashFiles)
+}
+
+func TestParseConfigTimezone(t *testing.T) {
+	config, err := LoadConfigData([]byte("[general]\ntimezone = \"Europe/Zurich\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Zurich", config.General.Timezone)
+
+	config, err = LoadConfigData([]byte("[general]\n"), "inline", []string{"general.timezone=UTC"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "UTC", config.General.Timezone)
+}
+
+func TestParseConfigTimeouts(t *testing.T) {
+	config, err := LoadConfigData([]byte("[operation.main]\ncollector = \"LocalCollector\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), config.General.FileTimeout)
+	assert.Equal(t, time.Duration(0), config.General.ScanTimeout)
+
+	config, err = LoadConfigData([]byte("[general]\nfileTimeout = \"30s\"\nscanTimeout = 3600\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, config.General.FileTimeout)
+	assert.Equal(t, time.Hour, config.General.ScanTimeout)
+
+	_, err = LoadConfigData([]byte("[general]\nfileTimeout = \"soon\"\n"), "inline", nil, false)
+	assert.ErrorContains(t, err, "general.fileTimeout")
+}
+
+func TestConfigContext(t *testing.T) {
+	cfg := Config{Tests: map[string]*TestConfig{}}
+	assert.Equal(t, context.Background(), cfg.Context())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scanConfig := cfg.WithContext(ctx)
+	cancel()
+	assert.Error(t, scanConfig.Context().Err())
+	assert.NoError(t, cfg.Context().Err(), "WithContext must not change the original config")
+}
+
+func TestConfigDownloads(t *testing.T) {
+	cfg := Config{Tests: map[string]*TestConfig{}}
+	assert.Equal(t, "", cfg.DownloadDir())
+	cfg.DownloadProgress()(1, 2) // Does nothing unless set
+
+	var done, total int64
+	downloadConfig := cfg.WithDownloadDir("/tmp/downloads").WithDownloadProgress(func(d, t int64) { done, total = d, t })
+	downloadConfig.DownloadProgress()(1, 2)
+	assert.Equal(t, "/tmp/downloads", downloadConfig.DownloadDir())
+	assert.Equal(t, []int64{1, 2}, []int64{done, total})
+	assert.Equal(t, "", cfg.DownloadDir(), "WithDownloadDir must not change the original config")
+}
+
+func TestConfigArchiveProgress(t *testing.T) {
+	cfg := Config{Tests: map[string]*TestConfig{}}
+	cfg.ArchiveProgress()("data.zip", 1, 2) // Does nothing unless set
+
+	var reported []string
+	progressConfig := cfg.WithArchiveProgress(func(archive string, member, total int) {
+		reported = append(reported, fmt.Sprintf("%s %d/%d", archive, member, total))
+	})
+	progressConfig.ArchiveProgress()("data.zip", 1, 2)
+	cfg.ArchiveProgress()("data.zip", 2, 2)
+	assert.Equal(t, []string{"data.zip 1/2"}, reported, "WithArchiveProgress must not change the original config")
+}
+
+func TestConfigWorkspace(t *testing.T) {
+	config, err := LoadConfigData([]byte("[general]\nworkspaceDir = \"/scratch\"\nmaxWorkspaceSize = \"10GiB\"\n"), "inline", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "/scratch", config.General.WorkspaceDir)
+	assert.Equal(t, int64(10<<30), config.General.MaxWorkspaceSize)
+
+	assert.Nil(t, config.Workspace())
+	ws := config.NewWorkspace()
+	assert.Equal(t, ws, config.WithWorkspace(ws).Workspace())
+	assert.Nil(t, config.Workspace(), "WithWorkspace must not change the original config")
+}
+
+func TestConfigPackageRoot(t *testing.T) {
+	cfg := Config{}
+	assert.Equal(t, "", cfg.PackageRoot())
+	assert.Equal(t, "data/package", cfg.WithPackageRoot("data/package").PackageRoot())
+	assert.Equal(t, "", cfg.PackageRoot(), "WithPackageRoot must not change the original config")
+}
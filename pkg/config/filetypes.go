@@ -0,0 +1,37 @@
+package config
+
+import (
+	"mime"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchesFileType reports whether a check configured by this section applies to the file name,
+// according to its include_extensions and exclude_extensions. Entries are extensions ("csv",
+// ".tar.gz") or MIME globs containing a slash ("text/*"), the MIME type is derived from the
+// extension. Exclusions take precedence, an empty include list includes every file.
+func (t *TestConfig) MatchesFileType(name string) bool {
+	if matchesAnyFileType(t.ExcludeExtensions, name) {
+		return false
+	}
+	return len(t.IncludeExtensions) == 0 || matchesAnyFileType(t.IncludeExtensions, name)
+}
+
+func matchesAnyFileType(patterns []string, name string) bool {
+	lower := strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if strings.Contains(pattern, "/") {
+			mimeType, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(lower)), ";")
+			if matched, _ := path.Match(pattern, mimeType); matched && mimeType != "" {
+				return true
+			}
+			continue
+		}
+		if pattern != "" && strings.HasSuffix(lower, "."+strings.TrimPrefix(pattern, ".")) {
+			return true
+		}
+	}
+	return false
+}
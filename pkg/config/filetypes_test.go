@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesFileType(t *testing.T) {
+	tests := []struct {
+		name     string
+		test     TestConfig
+		file     string
+		expected bool
+	}{
+		{"no lists", TestConfig{}, "data.bin", true},
+		{"included extension", TestConfig{IncludeExtensions: []string{"csv", ".tsv"}}, "Data.CSV", true},
+		{"not included", TestConfig{IncludeExtensions: []string{"csv", ".tsv"}}, "data.csv.bak", false},
+		{"compound extension", TestConfig{IncludeExtensions: []string{"tar.gz"}}, "archive.tar.gz", true},
+		{"excluded extension", TestConfig{ExcludeExtensions: []string{"png"}}, "plot.png", false},
+		{"exclusion wins", TestConfig{IncludeExtensions: []string{"image/*"}, ExcludeExtensions: []string{"png"}}, "plot.png", false},
+		{"mime glob", TestConfig{IncludeExtensions: []string{"image/*"}}, "photo.jpg", true},
+		{"mime glob mismatch", TestConfig{IncludeExtensions: []string{"image/*"}}, "notes.txt", false},
+		{"unknown mime type", TestConfig{IncludeExtensions: []string{"*/*"}}, "README", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.test.MatchesFileType(tt.file))
+		})
+	}
+}
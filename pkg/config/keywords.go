@@ -0,0 +1,132 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// wellKnownLists are the keyword lists shipped with pc, referenced by name with keyword_lists
+//
+//go:embed keywordlists/*.txt
+var wellKnownLists embed.FS
+
+// WellKnownKeywordLists returns the names of the built-in keyword lists, sorted
+func WellKnownKeywordLists() []string {
+	entries, _ := wellKnownLists.ReadDir("keywordlists")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseKeywords reads a newline-delimited keyword list. Empty lines and lines starting with # are skipped.
+func ParseKeywords(data []byte) []string {
+	var keywords []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keywords = append(keywords, line)
+	}
+	return keywords
+}
+
+// KeywordFilePath resolves a keywords_file relative to the config it is given in.
+// source is the path or URL of the config.
+func KeywordFilePath(file, source string) string {
+	if IsRemote(file) || filepath.IsAbs(file) {
+		return file
+	}
+	if IsRemote(source) {
+		if base, err := url.Parse(source); err == nil {
+			if ref, err := url.Parse(file); err == nil {
+				return base.ResolveReference(ref).String()
+			}
+		}
+		return file
+	}
+	return filepath.Join(filepath.Dir(source), file)
+}
+
+// prepareKeywordArguments adds the keywords of keywords_file and keyword_lists to the keywords of
+// every keywordArguments set, so the checks only read keywords, and checks the regular expressions of patterns
+func prepareKeywordArguments(c *Config, source string, offline bool) error {
+	tests := map[string]*TestConfig{}
+	for name, test := range c.Tests {
+		tests["test."+name] = test
+	}
+	for profileName, profile := range c.Operation {
+		for name, test := range profile.Tests {
+			tests["operation."+profileName+".test."+name] = test
+		}
+	}
+
+	for field, test := range tests {
+		for i, set := range test.KeywordArguments {
+			if err := resolveKeywords(set, source, offline); err != nil {
+				return fmt.Errorf("%s.keywordArguments[%d]: %w", field, i, err)
+			}
+			patterns, _ := set["patterns"].([]string)
+			for _, pattern := range patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("%s.keywordArguments[%d].patterns: invalid regular expression %q: %w", field, i, pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveKeywords merges the referenced keyword lists into set["keywords"], skipping duplicates.
+// With offline set a remote keywords_file is read from the local cache only.
+func resolveKeywords(set map[string]interface{}, source string, offline bool) error {
+	file, hasFile := set["keywords_file"].(string)
+	lists, hasLists := set["keyword_lists"].([]string)
+	if !hasFile && !hasLists {
+		return nil
+	}
+
+	keywords, _ := set["keywords"].([]string)
+	seen := map[string]bool{}
+	for _, keyword := range keywords {
+		seen[keyword] = true
+	}
+	add := func(data []byte) {
+		for _, keyword := range ParseKeywords(data) {
+			if !seen[keyword] {
+				seen[keyword] = true
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+
+	if hasFile {
+		path := KeywordFilePath(file, source)
+		data, err := ReadConfig(path, offline)
+		if err != nil {
+			return fmt.Errorf("failed to read keywords_file '%s': %w", path, err)
+		}
+		if !offline {
+			CacheConfig(path, data)
+		}
+		add(data)
+	}
+	for _, name := range lists {
+		data, err := wellKnownLists.ReadFile("keywordlists/" + name + ".txt")
+		if err != nil {
+			return fmt.Errorf("unknown keyword list '%s', expected one of %s", name, strings.Join(WellKnownKeywordLists(), ", "))
+		}
+		add(data)
+	}
+
+	set["keywords"] = keywords
+	return nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const keywordFileConfig = `
+[test.IsFreeOfKeywords]
+keywordArguments = [
+    { keywords = ["password"], keywords_file = "secrets.txt", info = "Credentials detected" },
+    { keyword_lists = ["private-keys"], info = "Private key detected" }
+]
+
+[operation.main]
+collector = "LocalCollector"
+
+[operation.ci.test.IsFreeOfKeywords]
+keywordArguments = [{ keywords_file = "lists/paths.txt", info = "Paths detected" }]
+`
+
+func TestKeywordFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secrets.txt"), []byte("# shared secrets\nsecret\n\n  token  \npassword\n"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "lists"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "lists", "paths.txt"), []byte("/home/\r\nC:\\Users\\\r\n"), 0644))
+	configFile := filepath.Join(dir, "pc.toml")
+	assert.NoError(t, os.WriteFile(configFile, []byte(keywordFileConfig), 0644))
+
+	config, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	sets := config.Tests["IsFreeOfKeywords"].KeywordArguments
+	assert.Equal(t, []string{"password", "secret", "token"}, sets[0]["keywords"])
+	assert.Contains(t, sets[1]["keywords"], "BEGIN OPENSSH PRIVATE KEY")
+	assert.Equal(t, []string{"/home/", "C:\\Users\\"}, config.Operation["ci"].Tests["IsFreeOfKeywords"].KeywordArguments[0]["keywords"])
+}
+
+func TestRemoteKeywordFileOffline(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("secret\n"))
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { remoteClient = client }(remoteClient)
+	remoteClient = server.Client()
+
+	// The keywords_file is relative to the https config
+	source := server.URL + "/policies/pc.toml"
+	data := []byte(`[test.IsFreeOfKeywords]
+keywordArguments = [{ keywords_file = "secrets.txt", info = "Credentials detected" }]`)
+
+	_, err := LoadConfigData(data, source, nil, true)
+	assert.ErrorContains(t, err, "no cached copy", "nothing cached yet")
+	assert.Equal(t, 0, requests, "offline does not fetch the keywords_file")
+
+	_, err = LoadConfigData(data, source, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	config, err := LoadConfigData(data, source, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests, "offline reads the cached keywords_file")
+	assert.Equal(t, []string{"secret"}, config.Tests["IsFreeOfKeywords"].KeywordArguments[0]["keywords"])
+}
+
+func TestKeywordFileErrors(t *testing.T) {
+	configFile := createTempConfigFile(t, `[test.IsFreeOfKeywords]
+keywordArguments = [{ keywords_file = "missing.txt", info = "x" }]`)
+	defer os.Remove(configFile)
+	_, err := LoadConfig(configFile)
+	assert.ErrorContains(t, err, "test.IsFreeOfKeywords.keywordArguments[0]: failed to read keywords_file")
+
+	configFile = createTempConfigFile(t, `[test.IsFreeOfKeywords]
+keywordArguments = [{ keyword_lists = ["passwords"], info = "x" }]`)
+	defer os.Remove(configFile)
+	_, err = LoadConfig(configFile)
+	assert.ErrorContains(t, err, "unknown keyword list 'passwords'")
+}
+
+func TestKeywordFilePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/etc/pc", "secrets.txt"), KeywordFilePath("secrets.txt", "/etc/pc/pc.toml"))
+	assert.Equal(t, "/srv/secrets.txt", KeywordFilePath("/srv/secrets.txt", "/etc/pc/pc.toml"))
+	assert.Equal(t, "https://example.org/policies/secrets.txt", KeywordFilePath("secrets.txt", "https://example.org/policies/pc.toml"))
+	assert.Equal(t, "https://lists.example.org/a.txt", KeywordFilePath("https://lists.example.org/a.txt", "pc.toml"))
+}
+
+func TestWellKnownKeywordLists(t *testing.T) {
+	assert.Equal(t, []string{"cloud-tokens", "credentials", "private-keys"}, WellKnownKeywordLists())
+	assert.Equal(t, []string{"a", "b"}, ParseKeywords([]byte("# comment\na\n\n b \n")))
+}
+
+func TestKeywordPatterns(t *testing.T) {
+	configFile := createTempConfigFile(t, `[test.IsFreeOfKeywords]
+keywordArguments = [{ name = "aws", patterns = ["AKIA[0-9A-Z]{16}"], redact = true, info = "AWS key" }]`)
+	defer os.Remove(configFile)
+	config, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	set := config.Tests["IsFreeOfKeywords"].KeywordArguments[0]
+	assert.Equal(t, true, set["redact"])
+	assert.Equal(t, []string{"AKIA[0-9A-Z]{16}"}, set["patterns"])
+
+	configFile = createTempConfigFile(t, `[test.IsFreeOfKeywords]
+keywordArguments = [{ patterns = ["token=("], info = "x" }]`)
+	defer os.Remove(configFile)
+	_, err = LoadConfig(configFile)
+	assert.ErrorContains(t, err, "test.IsFreeOfKeywords.keywordArguments[0].patterns: invalid regular expression")
+}
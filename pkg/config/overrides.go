@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// EnvOverride maps a PC_* environment variable to the config value it sets
+type EnvOverride struct {
+	Key  string // Dotted path, e.g. collector.CkanCollector.attrs.token
+	Type string // "string", "size" or "bool"
+}
+
+// EnvOverrides are the environment variables read by LoadConfig. They allow containerized
+// deployments to keep secrets such as the CKAN token out of pc.toml.
+var EnvOverrides = map[string]EnvOverride{
+	"PC_COLLECTOR":                  {"operation.main.collector", "string"},
+	"PC_HISTORY_DIR":                {"general.historyDir", "string"},
+	"PC_OUTPUT_DIR":                 {"general.outputDir", "string"},
+	"PC_WORKSPACE_DIR":              {"general.workspaceDir", "string"},
+	"PC_BASELINE":                   {"general.baseline", "string"},
+	"PC_LANGUAGE":                   {"general.language", "string"},
+	"PC_TIMEZONE":                   {"general.timezone", "string"},
+	"PC_LETTER_TEMPLATE":            {"general.letterTemplate", "string"},
+	"PC_REDACT":                     {"general.redact", "bool"},
+	"PC_LIST_ARCHIVES":              {"general.listArchives", "bool"},
+	"PC_FILE_METADATA":              {"general.fileMetadata", "bool"},
+	"PC_HASH_FILES":                 {"general.hashFiles", "bool"},
+	"PC_FILE_TIMEOUT":               {"general.fileTimeout", "string"},
+	"PC_SCAN_TIMEOUT":               {"general.scanTimeout", "string"},
+	"PC_MAX_ARCHIVE_FILE_SIZE":      {"general.maxArchiveFileSize", "size"},
+	"PC_MAX_TOTAL_ARCHIVE_MEMORY":   {"general.maxTotalArchiveMemory", "size"},
+	"PC_MAX_CONTENT_SCAN_FILE_SIZE": {"general.maxContentScanFileSize", "size"},
+	"PC_MAX_TOTAL_MEMORY":           {"general.maxTotalMemory", "size"},
+	"PC_MAX_WORKSPACE_SIZE":         {"general.maxWorkspaceSize", "size"},
+	"PC_SCAN_STRATEGY":              {"general.scanStrategy", "string"},
+	"PC_SAMPLE_THRESHOLD":           {"general.sampleThreshold", "size"},
+	"PC_CKAN_URL":                   {"collector.CkanCollector.attrs.url", "string"},
+	"PC_CKAN_TOKEN":                 {"collector.CkanCollector.attrs.token", "string"},
+	"PC_CKAN_VERIFY":                {"collector.CkanCollector.attrs.verify", "bool"},
+	"PC_CKAN_STORAGE_PATH":          {"collector.CkanCollector.attrs.ckan_storage_path", "string"},
+	"PC_CKAN_PUBLISH":               {"collector.CkanCollector.attrs.publish", "string"},
+	"PC_CKAN_DOWNLOAD":              {"collector.CkanCollector.attrs.download", "bool"},
+	"PC_CKAN_DOWNLOAD_DIR":          {"collector.CkanCollector.attrs.download_dir", "string"},
+	"PC_CKAN_STAGING_PATH":          {"collector.CkanCollector.attrs.staging_path", "string"},
+	"PC_CKAN_METADATA":              {"collector.CkanCollector.attrs.metadata", "string"},
+	"PC_CKAN_WEBHOOK_SECRET":        {"collector.CkanCollector.attrs.webhook_secret", "string"},
+	"PC_WEBHOOK_URL":                {"notify.webhook.url", "string"},
+	"PC_SMTP_HOST":                  {"notify.email.host", "string"},
+	"PC_SMTP_USERNAME":              {"notify.email.username", "string"},
+	"PC_SMTP_PASSWORD":              {"notify.email.password", "string"},
+}
+
+// applyEnvOverrides sets the values of the PC_* environment variables that are set
+func applyEnvOverrides(raw map[string]interface{}, lookupEnv func(string) (string, bool)) error {
+	names := make([]string, 0, len(EnvOverrides))
+	for name := range EnvOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		text, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		override := EnvOverrides[name]
+		var value interface{} = text
+		switch override.Type {
+		case "size":
+			n, err := ParseSize(text)
+			if err != nil {
+				return fmt.Errorf("environment variable %s: %w", name, err)
+			}
+			value = n
+		case "bool":
+			b, err := strconv.ParseBool(strings.TrimSpace(text))
+			if err != nil {
+				return fmt.Errorf("environment variable %s: expected true or false, got '%s'", name, text)
+			}
+			value = b
+		}
+		if err := setValue(raw, override.Key, value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// applyOverride applies a key=value override such as general.maxArchiveFileSize=52428800.
+// The value is read as a TOML value (numbers, booleans, arrays, quoted strings); anything else,
+// and any value replacing a string, is taken as a plain string.
+func applyOverride(raw map[string]interface{}, override string) error {
+	key, text, found := strings.Cut(override, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return fmt.Errorf("invalid override '%s', expected key=value", override)
+	}
+
+	var value interface{} = text
+	if current, ok := getValue(raw, key); !ok || !isString(current) {
+		var decoded map[string]interface{}
+		if _, err := toml.Decode("value = "+text, &decoded); err == nil {
+			value = decoded["value"]
+		}
+	} else if unquoted, err := strconv.Unquote(text); err == nil {
+		value = unquoted
+	}
+
+	if err := setValue(raw, key, value); err != nil {
+		return fmt.Errorf("invalid override '%s': %w", override, err)
+	}
+	return nil
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+// getValue returns the value at a dotted key path
+func getValue(raw map[string]interface{}, key string) (interface{}, bool) {
+	var current interface{} = raw
+	for _, part := range strings.Split(key, ".") {
+		table, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = table[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setValue sets the value at a dotted key path, creating the tables on the way
+func setValue(raw map[string]interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	table := raw
+	for i, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return fmt.Errorf("empty key in '%s'", key)
+		}
+		next, exists := table[part]
+		if !exists {
+			next = map[string]interface{}{}
+			table[part] = next
+		}
+		nextTable, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("'%s' is not a table", strings.Join(parts[:i+1], "."))
+		}
+		table = nextTable
+	}
+	last := parts[len(parts)-1]
+	if last == "" {
+		return fmt.Errorf("empty key in '%s'", key)
+	}
+	table[last] = value
+	return nil
+}
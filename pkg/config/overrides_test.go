@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const overrideConfig = `
+[general]
+maxArchiveFileSize = 100
+
+[operation.main]
+collector = "LocalCollector"
+
+[collector.CkanCollector]
+attrs = {url = "https://ckan.example.com", token = "", verify = true, ckan_storage_path = "/data"}
+`
+
+func TestLoadConfigWithOverrides(t *testing.T) {
+	configFile := createTempConfigFile(t, overrideConfig)
+	defer os.Remove(configFile)
+
+	t.Setenv("PC_CKAN_TOKEN", "12345")
+	t.Setenv("PC_MAX_ARCHIVE_FILE_SIZE", "200")
+	t.Setenv("PC_CKAN_VERIFY", "false")
+
+	config, err := LoadConfigWithOverrides(configFile, []string{
+		"general.maxArchiveFileSize=300",
+		"collector.CkanCollector.attrs.url=https://other.example.com",
+		"test.IsValidName.keywordArguments=[{disallowed_names = ['venv']}]",
+	})
+	assert.NoError(t, err)
+
+	attrs := config.Collectors["CkanCollector"].Attrs
+	assert.Equal(t, "12345", attrs["token"], "a numeric token stays a string")
+	assert.Equal(t, false, attrs["verify"])
+	assert.Equal(t, "https://other.example.com", attrs["url"])
+	assert.Equal(t, int64(300), config.General.MaxArchiveFileSize, "-set takes precedence over the environment")
+	assert.Equal(t, []string{"venv"}, config.Tests["IsValidName"].KeywordArguments[0]["disallowed_names"])
+
+	config, err = LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200), config.General.MaxArchiveFileSize)
+}
+
+func TestInvalidOverrides(t *testing.T) {
+	configFile := createTempConfigFile(t, overrideConfig)
+	defer os.Remove(configFile)
+
+	for _, override := range []string{"general", "=1", "general.maxArchiveFileSize.x=1", "general..x=1"} {
+		_, err := LoadConfigWithOverrides(configFile, []string{override})
+		assert.Error(t, err, override)
+	}
+
+	t.Setenv("PC_MAX_ARCHIVE_FILE_SIZE", "a lot")
+	_, err := LoadConfig(configFile)
+	assert.Error(t, err)
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	raw := map[string]interface{}{}
+	env := map[string]string{"PC_SMTP_PASSWORD": "secret", "PC_COLLECTOR": "CkanCollector", "OTHER": "x"}
+	err := applyEnvOverrides(raw, func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+	assert.NoError(t, err)
+
+	config, err := parseRaw(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", config.Notifiers["email"].Attrs["password"])
+	assert.Equal(t, "CkanCollector", config.Operation["main"].Collector)
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+)
+
+// logger logs the problems reading remote configs
+var logger = output.GlobalLogger.Component("config")
+
+// AuthEnvVar holds the Authorization header sent when fetching a remote config, e.g. "Bearer <token>"
+const AuthEnvVar = "PC_CONFIG_AUTHORIZATION"
+
+// maxRemoteConfigSize limits the size of a downloaded config
+const maxRemoteConfigSize = 10 << 20
+
+// remoteClient fetches remote configs
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+// IsRemote reports whether location is a URL rather than a file path
+func IsRemote(location string) bool {
+	return strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://")
+}
+
+// ReadConfig returns the content of the config at location, a file path or an https:// URL.
+// The cached copy of a remote config, see CacheConfig, is used if the download fails, and
+// without any network access if offline is set.
+func ReadConfig(location string, offline bool) ([]byte, error) {
+	if !IsRemote(location) {
+		return os.ReadFile(location)
+	}
+	if !strings.HasPrefix(location, "https://") {
+		return nil, fmt.Errorf("remote config '%s' must be fetched over https", location)
+	}
+
+	cachePath, err := remoteCachePath(location)
+	if err != nil {
+		return nil, err
+	}
+	if offline {
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			return nil, fmt.Errorf("no cached copy of '%s' for offline use, run once without -offline: %w", location, err)
+		}
+		return data, nil
+	}
+
+	data, fetchErr := fetchRemoteConfig(location, os.Getenv(AuthEnvVar))
+	if fetchErr != nil {
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			return nil, fetchErr
+		}
+		logger.Warning("%v, using the cached copy from %s", fetchErr, cachePath)
+		return data, nil
+	}
+	return data, nil
+}
+
+// CacheConfig keeps data as the cached copy of the remote config at location. It is called once
+// the config was loaded, so a broken download never replaces a working copy. Local files are
+// not cached.
+func CacheConfig(location string, data []byte) {
+	if !IsRemote(location) {
+		return
+	}
+	cachePath, err := remoteCachePath(location)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(cachePath), 0700); err == nil {
+			err = os.WriteFile(cachePath, data, 0600)
+		}
+	}
+	if err != nil {
+		logger.Warning("Failed to cache config '%s': %v", location, err)
+	}
+}
+
+// fetchRemoteConfig downloads the config at url, sending authorization as the Authorization header if set
+func fetchRemoteConfig(url, authorization string) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL '%s': %w", url, err)
+	}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	response, err := remoteClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config '%s': %w", url, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch config '%s': %s", url, response.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config '%s': %w", url, err)
+	}
+	if len(data) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("config '%s' is larger than %d bytes", url, maxRemoteConfigSize)
+	}
+	return data, nil
+}
+
+// remoteCachePath returns the cache file of a remote config, in the user cache directory
+func remoteCachePath(url string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no cache directory for remote config: %w", err)
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "pc", "config", hex.EncodeToString(sum[:])+".toml"), nil
+}
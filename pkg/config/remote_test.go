@@ -0,0 +1,108 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRemoteConfig(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(AuthEnvVar, "Bearer secret")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(overrideConfig))
+	}))
+	defer func(client *http.Client) { remoteClient = client }(remoteClient)
+	remoteClient = server.Client()
+	url := server.URL + "/pc.toml"
+
+	_, err := ReadConfig(url, true)
+	assert.Error(t, err, "nothing cached yet")
+
+	data, err := ReadConfig(url, false)
+	assert.NoError(t, err)
+	assert.Equal(t, overrideConfig, string(data))
+
+	config, err := LoadConfig(url)
+	assert.NoError(t, err)
+	assert.Equal(t, "LocalCollector", config.Operation["main"].Collector)
+
+	output.GlobalLogger.ClearMessages()
+	output.GlobalLogger.SetJSONMode(true)
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+	server.Close()
+	data, err = ReadConfig(url, false)
+	assert.NoError(t, err, "the cached copy is used when the server is unreachable")
+	assert.Equal(t, overrideConfig, string(data))
+	messages := output.GlobalLogger.GetMessages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "config", messages[0].Component)
+		assert.Contains(t, messages[0].Message, "using the cached copy")
+	}
+
+	data, err = ReadConfig(url, true)
+	assert.NoError(t, err)
+	assert.Equal(t, overrideConfig, string(data))
+
+	_, err = ReadConfig(server.URL+"/other.toml", false)
+	assert.Error(t, err)
+}
+
+func TestRemoteConfigCachedWhenLoaded(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	content := overrideConfig
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer func(client *http.Client) { remoteClient = client }(remoteClient)
+	remoteClient = server.Client()
+	url := server.URL + "/pc.toml"
+
+	_, err := ReadConfig(url, false)
+	assert.NoError(t, err)
+	_, err = ReadConfig(url, true)
+	assert.Error(t, err, "reading alone does not cache the config")
+
+	_, err = LoadConfig(url)
+	assert.NoError(t, err)
+
+	// A broken config on the server does not replace the cached copy
+	content = "[general"
+	_, err = LoadConfig(url)
+	assert.Error(t, err)
+	server.Close()
+
+	data, err := ReadConfig(url, true)
+	assert.NoError(t, err)
+	assert.Equal(t, overrideConfig, string(data))
+}
+
+func TestRemoteConfigErrors(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { remoteClient = client }(remoteClient)
+	remoteClient = server.Client()
+
+	_, err := ReadConfig(server.URL+"/pc.toml", false)
+	assert.ErrorContains(t, err, "401")
+
+	_, err = ReadConfig("http://example.com/pc.toml", false)
+	assert.ErrorContains(t, err, "https")
+
+	assert.True(t, IsRemote("https://example.com/pc.toml"))
+	assert.False(t, IsRemote("./pc.toml"))
+}
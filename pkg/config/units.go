@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sizeUnits are the multipliers of the size suffixes, matched case-insensitively
+var sizeUnits = map[string]float64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseSize reads a size in bytes given as an integer or as a string such as "100MB" or "2GiB".
+// Decimal units (kB, MB, GB, TB) are powers of 1000, binary units (KiB, MiB, GiB, TiB) powers of 1024.
+func ParseSize(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return 0, fmt.Errorf("size must not be negative, got %d", v)
+		}
+		return v, nil
+	case string:
+		text := strings.TrimSpace(v)
+		i := strings.IndexFunc(text, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		number, unit := text, "b"
+		if i >= 0 {
+			number, unit = text[:i], strings.ToLower(strings.TrimSpace(text[i:]))
+		}
+		multiplier, ok := sizeUnits[unit]
+		if !ok {
+			return 0, fmt.Errorf("invalid size '%s', expected e.g. 512, \"100MB\" or \"2GiB\"", v)
+		}
+		n, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid size '%s', expected e.g. 512, \"100MB\" or \"2GiB\"", v)
+		}
+		bytes := n * multiplier
+		if bytes >= math.MaxInt64 {
+			return 0, fmt.Errorf("size '%s' is too large", v)
+		}
+		return int64(bytes), nil
+	default:
+		return 0, fmt.Errorf("expected a size such as 512 or \"100MB\", got %T", value)
+	}
+}
+
+// FormatSize writes a size in bytes with a binary unit, e.g. "512 B" or "1.5 MiB", as ParseSize reads it
+func FormatSize(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes)
+	unit := "B"
+	for _, next := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		if value < 1024 {
+			break
+		}
+		value /= 1024
+		unit = next
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
+
+// ParseDuration reads a duration given as an integer number of seconds or as a string such as "30s" or "2m"
+func ParseDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return 0, fmt.Errorf("duration must not be negative, got %d", v)
+		}
+		return time.Duration(v) * time.Second, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s', expected e.g. 30 (seconds), \"30s\" or \"2m\"", v)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("duration must not be negative, got '%s'", v)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("expected a duration such as 30 or \"30s\", got %T", value)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected int64
+	}{
+		{int64(1024), 1024},
+		{"1024", 1024},
+		{"100MB", 100000000},
+		{"100 MB", 100000000},
+		{"10MiB", 10 * 1024 * 1024},
+		{"2GiB", 2 * 1024 * 1024 * 1024},
+		{"1.5kb", 1500},
+		{"512B", 512},
+	}
+	for _, tt := range tests {
+		size, err := ParseSize(tt.value)
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, size, tt.value)
+	}
+
+	for _, value := range []interface{}{"", "MB", "10 apples", "1.2.3MB", "10M", int64(-1), "9999999TiB", true} {
+		_, err := ParseSize(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:                                "0 B",
+		512:                              "512 B",
+		1536:                             "1.5 KiB",
+		10 * 1024 * 1024:                 "10.0 MiB",
+		3 * 1024 * 1024 * 1024:           "3.0 GiB",
+		2048 * 1024 * 1024 * 1024 * 1024: "2048.0 TiB",
+	}
+	for bytes, expected := range tests {
+		assert.Equal(t, expected, FormatSize(bytes), bytes)
+		size, err := ParseSize(strings.ReplaceAll(FormatSize(bytes), " ", ""))
+		assert.NoError(t, err)
+		assert.InDelta(t, bytes, size, float64(bytes)/100+1)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := ParseDuration(int64(30))
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, err = ParseDuration("2m30s")
+	assert.NoError(t, err)
+	assert.Equal(t, 150*time.Second, d)
+
+	for _, value := range []interface{}{"30", "soon", "-5s", int64(-1), 1.5} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadConfigWithUnits(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+	[general]
+	maxArchiveFileSize = "50MB"
+	maxTotalArchiveMemory = "1GiB"
+
+	[operation.quick]
+	maxContentScanFileSize = "1MiB"
+
+	[plugin.Slow]
+	command = "slow-check"
+	timeout = "2m"
+	`)
+	defer os.Remove(configFile)
+
+	config, err := LoadConfigWithOverrides(configFile, []string{"general.maxContentScanFileSize=20MB"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50000000), config.General.MaxArchiveFileSize)
+	assert.Equal(t, int64(1<<30), config.General.MaxTotalArchiveMemory)
+	assert.Equal(t, int64(20000000), config.General.MaxContentScanFileSize)
+	assert.Equal(t, int64(1<<20), config.Operation["quick"].MaxContentScanFileSize)
+	assert.Equal(t, 2*time.Minute, config.Plugins["Slow"].Timeout)
+
+	_, err = LoadConfigWithOverrides(configFile, []string{"general.maxArchiveFileSize=lots"})
+	assert.ErrorContains(t, err, "general.maxArchiveFileSize")
+}
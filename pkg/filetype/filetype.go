@@ -0,0 +1,186 @@
+// Package filetype tells text from binary files, for the checks that read the content of files
+// and for the members of archives. The type is detected from the first bytes of a file: the
+// signatures of binary formats, byte order marks, whether the bytes are UTF-8 or UTF-16 text,
+// and the extension of the file for text in legacy encodings.
+package filetype
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// SampleSize is the number of bytes at the start of a file the type is detected from
+const SampleSize = 8192
+
+// Type is the detected type of a file
+type Type struct {
+	Text bool
+	MIME string // e.g. "application/zip" or "text/plain; charset=utf-16le"
+}
+
+// Common text file extensions, for text that is neither UTF-8 nor UTF-16 such as Latin-1
+var textExtensions = map[string]bool{
+	".txt": true, ".log": true, ".md": true, ".csv": true, ".tsv": true, ".json": true,
+	".xml": true, ".html": true, ".css": true, ".js": true, ".py": true,
+	".go": true, ".java": true, ".cpp": true, ".c": true, ".h": true,
+	".sql": true, ".yml": true, ".yaml": true, ".toml": true, ".ini": true,
+	".conf": true, ".config": true, ".properties": true, ".sh": true,
+	".bat": true, ".ps1": true, ".rb": true, ".php": true, ".pl": true,
+	".r": true, ".m": true, ".tex": true, ".bib": true, ".svg": true, ".gpx": true, ".kml": true,
+}
+
+// signatures are the first bytes of binary formats. Files starting with them are binary whatever
+// their extension, e.g. a zip named data.csv.
+var signatures = []struct {
+	magic string
+	mime  string
+}{
+	{"%PDF-", "application/pdf"},
+	{"PK\x03\x04", "application/zip"},
+	{"PK\x05\x06", "application/zip"},
+	{"\x1f\x8b", "application/gzip"},
+	{"\xfd7zXZ\x00", "application/x-xz"},
+	{"\x28\xb5\x2f\xfd", "application/zstd"},
+	{"7z\xbc\xaf\x27\x1c", "application/x-7z-compressed"},
+	{"Rar!\x1a\x07", "application/vnd.rar"},
+	{"\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1", "application/x-ole-storage"},
+	{"\x89PNG\r\n\x1a\n", "image/png"},
+	{"\xff\xd8\xff", "image/jpeg"},
+	{"GIF87a", "image/gif"},
+	{"GIF89a", "image/gif"},
+	{"II*\x00", "image/tiff"},
+	{"MM\x00*", "image/tiff"},
+	{"\x89HDF\r\n\x1a\n", "application/x-hdf5"},
+	{"CDF\x01", "application/x-netcdf"},
+	{"CDF\x02", "application/x-netcdf"},
+	{"PAR1", "application/vnd.apache.parquet"},
+	{"ARROW1", "application/vnd.apache.arrow.file"},
+	{"SQLite format 3\x00", "application/vnd.sqlite3"},
+	{"\x7fELF", "application/x-executable"},
+	{"\xfe\xed\xfa\xce", "application/x-mach-binary"},
+	{"\xfe\xed\xfa\xcf", "application/x-mach-binary"},
+	{"\xce\xfa\xed\xfe", "application/x-mach-binary"},
+	{"\xcf\xfa\xed\xfe", "application/x-mach-binary"},
+	{"OggS", "audio/ogg"},
+	{"fLaC", "audio/flac"},
+}
+
+// byteOrderMarks start text in UTF-8, UTF-16 and UTF-32. UTF-32 comes first, its little-endian
+// mark starts like the one of UTF-16.
+var byteOrderMarks = []struct {
+	mark    string
+	charset string
+}{
+	{"\xff\xfe\x00\x00", "utf-32le"},
+	{"\x00\x00\xfe\xff", "utf-32be"},
+	{"\xef\xbb\xbf", "utf-8"},
+	{"\xff\xfe", "utf-16le"},
+	{"\xfe\xff", "utf-16be"},
+}
+
+// Detect returns the type of the file named name whose first bytes are sample, up to SampleSize
+// bytes. Empty files are text.
+func Detect(name string, sample []byte) Type {
+	if len(sample) > SampleSize {
+		sample = sample[:SampleSize]
+	}
+	if len(sample) == 0 {
+		return text("utf-8")
+	}
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(sample, []byte(bom.mark)) {
+			return text(bom.charset)
+		}
+	}
+	for _, signature := range signatures {
+		if bytes.HasPrefix(sample, []byte(signature.magic)) {
+			return Type{MIME: signature.mime}
+		}
+	}
+	if charset := utf16Charset(sample); charset != "" {
+		return text(charset)
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return binary()
+	}
+	if validUTF8(sample) && controlRatio(sample) < 0.05 {
+		return text("utf-8")
+	}
+	// Text in legacy encodings such as Latin-1 is recognized by its extension or its bytes
+	if textExtensions[strings.ToLower(filepath.Ext(name))] || printableRatio(sample) >= 0.95 {
+		return text("unknown-8bit")
+	}
+	return binary()
+}
+
+// IsText reports whether the file named name whose first bytes are sample is text
+func IsText(name string, sample []byte) bool {
+	return Detect(name, sample).Text
+}
+
+func text(charset string) Type {
+	return Type{Text: true, MIME: "text/plain; charset=" + charset}
+}
+
+func binary() Type {
+	return Type{MIME: "application/octet-stream"}
+}
+
+// validUTF8 reports whether sample is UTF-8, but for a character cut off at its end
+func validUTF8(sample []byte) bool {
+	for cut := 0; cut < utf8.UTFMax && cut < len(sample); cut++ {
+		if utf8.Valid(sample[:len(sample)-cut]) {
+			return true
+		}
+	}
+	return false
+}
+
+// utf16Charset recognizes UTF-16 text without byte order mark, such as XML exported on Windows,
+// by its ASCII characters: every other byte is zero and the others are printable, unlike arrays
+// of small numbers
+func utf16Charset(sample []byte) string {
+	pairs := len(sample) / 2
+	if pairs < 2 {
+		return ""
+	}
+	var zeros, printable [2]int
+	for i := 0; i < pairs*2; i++ {
+		switch b := sample[i]; {
+		case b == 0:
+			zeros[i%2]++
+		case (b >= 32 && b <= 126) || b == '\t' || b == '\n' || b == '\r':
+			printable[i%2]++
+		}
+	}
+	for low, high := range []int{1, 0} {
+		if zeros[high] >= pairs*9/10 && printable[low] >= pairs*9/10 {
+			return []string{"utf-16le", "utf-16be"}[low]
+		}
+	}
+	return ""
+}
+
+// controlRatio is the share of control characters that do not occur in text
+func controlRatio(sample []byte) float64 {
+	controls := 0
+	for _, b := range sample {
+		if b < 32 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1b {
+			controls++
+		}
+	}
+	return float64(controls) / float64(len(sample))
+}
+
+// printableRatio is the share of printable ASCII, whitespace and bytes of 8-bit encodings
+func printableRatio(sample []byte) float64 {
+	printable := 0
+	for _, b := range sample {
+		if (b >= 32 && b <= 126) || b == '\t' || b == '\n' || b == '\r' || b >= 128 {
+			printable++
+		}
+	}
+	return float64(printable) / float64(len(sample))
+}
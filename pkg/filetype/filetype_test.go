@@ -0,0 +1,62 @@
+package filetype
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16le encodes text as UTF-16 little-endian without byte order mark
+func utf16le(text string) []byte {
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune(text)) {
+		encoded = append(encoded, byte(unit), byte(unit>>8))
+	}
+	return encoded
+}
+
+func TestDetect(t *testing.T) {
+	csv := strings.Repeat("station;date;temperature\nRhine-07;2024-01-01;12.5\n", 20)
+	tests := []struct {
+		name   string
+		file   string
+		sample []byte
+		text   bool
+		mime   string
+	}{
+		{"empty", "empty.dat", nil, true, "text/plain; charset=utf-8"},
+		{"plain text", "notes", []byte("Sampling notes of the field campaign.\n"), true, "text/plain; charset=utf-8"},
+		{"CSV with BOM", "data.csv", append([]byte("\xef\xbb\xbf"), csv...), true, "text/plain; charset=utf-8"},
+		{"UTF-16 with BOM", "data.csv", append([]byte("\xff\xfe"), utf16le(csv)...), true, "text/plain; charset=utf-16le"},
+		{"UTF-16 XML without BOM", "export.xml", utf16le(`<?xml version="1.0" encoding="UTF-16"?><station id="7"/>`), true, "text/plain; charset=utf-16le"},
+		{"XML without declaration", "track.gpx", []byte(`<gpx version="1.1"><trk><name>Lake</name></trk></gpx>`), true, "text/plain; charset=utf-8"},
+		{"CSV ending in DOS end of file", "old.csv", []byte(csv + "\x1a"), true, "text/plain; charset=utf-8"},
+		{"UTF-8 cut in a character", "notes.txt", []byte("Messung bei 5 °C")[:len("Messung bei 5 °")], true, "text/plain; charset=utf-8"},
+		{"Latin-1 CSV", "data.csv", []byte("Ort;Temperatur\nZ\xfcrich;12.5\nGen\xe8ve;13.1\n"), true, "text/plain; charset=unknown-8bit"},
+		{"zip named like a CSV", "data.csv", []byte("PK\x03\x04\x14\x00\x00\x00\x08\x00"), false, "application/zip"},
+		{"PDF", "paper.pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), false, "application/pdf"},
+		{"gzip", "data.csv.gz", []byte{0x1f, 0x8b, 0x08, 0x00}, false, "application/gzip"},
+		{"numbers", "values.bin", []byte{0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04, 0x00}, false, "application/octet-stream"},
+		{"control bytes", "values.bin", bytes.Repeat([]byte{0x01, 0x02, 'a', 0x03}, 64), false, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected := Detect(tt.file, tt.sample)
+			if detected.Text != tt.text || detected.MIME != tt.mime {
+				t.Errorf("Expected text=%v %s, got %+v", tt.text, tt.mime, detected)
+			}
+			if IsText(tt.file, tt.sample) != tt.text {
+				t.Errorf("Expected IsText to agree with Detect")
+			}
+		})
+	}
+}
+
+func TestDetectSampleSize(t *testing.T) {
+	// Only the sample is looked at, binary data later in the file does not count
+	data := append(bytes.Repeat([]byte("text "), SampleSize/5+1), 0x00, 0x01)
+	if !IsText("data.txt", data) {
+		t.Error("Expected the bytes beyond SampleSize to be ignored")
+	}
+}
@@ -20,7 +20,11 @@ func NewFileTracker(header string) *FileTracker {
 	}
 }
 
+// AddFileIfPDF lists the file if it is a PDF, prefixed with note. A nil tracker lists nothing.
 func (ft *FileTracker) AddFileIfPDF(note string, file structs.File) {
+	if ft == nil {
+		return
+	}
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
 	if file.Suffix == ".pdf" {
@@ -30,6 +34,13 @@ func (ft *FileTracker) AddFileIfPDF(note string, file structs.File) {
 	}
 }
 
+// Reset forgets the tracked files, e.g. before the files are scanned again
+func (ft *FileTracker) Reset() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.Files = make([]string, 0)
+}
+
 func (ft *FileTracker) FormatFiles() string {
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
@@ -46,4 +57,7 @@ func (ft *FileTracker) FormatFiles() string {
 	return sb.String()
 }
 
-var PDFTracker = NewFileTracker("=== PDF Files ===")
+// NewPDFTracker returns a tracker for the PDF files of one scan, see config.WithPDFTracker
+func NewPDFTracker() *FileTracker {
+	return NewFileTracker("=== PDF Files ===")
+}
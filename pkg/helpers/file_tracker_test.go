@@ -136,6 +136,19 @@ func TestFileTracker_AddFileIfPDF_MultipleFiles(t *testing.T) {
 	}
 }
 
+func TestFileTracker_Reset(t *testing.T) {
+	tracker := NewFileTracker("Test")
+	tracker.AddFileIfPDF("", structs.File{Name: "doc1.pdf", Suffix: ".pdf"})
+
+	tracker.Reset()
+	if len(tracker.Files) != 0 {
+		t.Errorf("Expected no files after Reset, got %v", tracker.Files)
+	}
+	if !strings.Contains(tracker.FormatFiles(), "No files found.") {
+		t.Error("Expected the reset tracker to report no files")
+	}
+}
+
 func TestFileTracker_FormatFiles_WithFiles(t *testing.T) {
 	tracker := NewFileTracker("=== PDF Files ===")
 
@@ -197,21 +210,28 @@ func TestFileTracker_FormatFiles_MultipleFiles(t *testing.T) {
 	}
 }
 
-func TestPDFTracker_GlobalInstance(t *testing.T) {
-	// Test that the global PDFTracker is properly initialized
-	if PDFTracker == nil {
-		t.Fatal("PDFTracker global instance is nil")
+func TestNewPDFTracker(t *testing.T) {
+	// Each scan gets its own tracker
+	tracker := NewPDFTracker()
+	if tracker == NewPDFTracker() {
+		t.Fatal("Expected a new tracker for every scan")
 	}
 
-	if PDFTracker.Header != "=== PDF Files ===" {
-		t.Errorf("Expected header '=== PDF Files ===', got '%s'", PDFTracker.Header)
+	if tracker.Header != "=== PDF Files ===" {
+		t.Errorf("Expected header '=== PDF Files ===', got '%s'", tracker.Header)
 	}
 
-	if PDFTracker.Files == nil {
+	if tracker.Files == nil {
 		t.Error("PDFTracker Files slice not initialized")
 	}
 }
 
+func TestFileTracker_Nil(t *testing.T) {
+	// Scans without a tracker do not list their PDF files
+	var tracker *FileTracker
+	tracker.AddFileIfPDF("", structs.File{Name: "report.pdf", Suffix: ".pdf"})
+}
+
 func TestFileTracker_EdgeCases(t *testing.T) {
 	tracker := NewFileTracker("Test")
 
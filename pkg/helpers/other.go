@@ -7,6 +7,9 @@ import (
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
+// logger logs the warnings about files found while checking them
+var logger = output.GlobalLogger.Component("checks")
+
 // check file size and warn if it is too big
 func WarnForLargeFile(file structs.File, limitSize int64, message string) {
 	// if the excel file is greater than 2MB warn the user, as it may cause performance issues
@@ -15,6 +18,6 @@ func WarnForLargeFile(file structs.File, limitSize int64, message string) {
 		panic(err)
 	}
 	if fileInfo.Size() > limitSize {
-		output.GlobalLogger.Warning("Warning for file '%s': %s", file.Name, message)
+		logger.Warning("Warning for file '%s': %s", file.Name, message)
 	}
 }
@@ -0,0 +1,156 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// Issue identifies a single finding independently of the scan it belongs to
+type Issue struct {
+	ID          string `json:"id"` // Stable ID of the finding, see structs.IssueID
+	Checkname   string `json:"checkname"`
+	Subject     string `json:"subject"`
+	ArchiveName string `json:"archive_name,omitempty"`
+	Message     string `json:"message"`
+}
+
+// Diff lists which issues appeared, disappeared or persisted between two scans
+type Diff struct {
+	Location  string  `json:"location"`
+	Previous  string  `json:"previous"`
+	Current   string  `json:"current"`
+	New       []Issue `json:"new"`
+	Fixed     []Issue `json:"fixed"`
+	Unchanged []Issue `json:"unchanged"`
+}
+
+// issuesOf extracts the issues and the timestamp of a JSON report
+func issuesOf(report []byte) ([]Issue, string, error) {
+	var result jsonformatter.ScanResult
+	if err := json.Unmarshal(report, &result); err != nil {
+		return nil, "", err
+	}
+
+	var issues []Issue
+	for _, check := range result.DetailsCheckFocused {
+		for _, issue := range check.Issues {
+			issues = append(issues, Issue{
+				ID:          issue.IssueID(check.Checkname),
+				Checkname:   check.Checkname,
+				Subject:     issue.Subject,
+				ArchiveName: issue.ArchiveName,
+				Message:     issue.Message,
+			})
+		}
+	}
+	return issues, result.Timestamp, nil
+}
+
+// Compare diffs two stored scans
+func Compare(previous, current *Entry) (*Diff, error) {
+	before, _, err := issuesOf(previous.Report)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report from %s: %w", previous.Timestamp.Format(time.RFC3339), err)
+	}
+	after, _, err := issuesOf(current.Report)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report from %s: %w", current.Timestamp.Format(time.RFC3339), err)
+	}
+	return compareIssues(current.Location, previous.Timestamp.Format(time.RFC3339), current.Timestamp.Format(time.RFC3339), before, after), nil
+}
+
+// CompareReports diffs two JSON reports, e.g. files written with -json. The timestamps of the diff
+// are those of the reports.
+func CompareReports(previous, current []byte) (*Diff, error) {
+	before, previousTime, err := issuesOf(previous)
+	if err != nil {
+		return nil, fmt.Errorf("invalid previous report: %w", err)
+	}
+	after, currentTime, err := issuesOf(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid current report: %w", err)
+	}
+	return compareIssues("", previousTime, currentTime, before, after), nil
+}
+
+func compareIssues(location, previous, current string, before, after []Issue) *Diff {
+	diff := &Diff{
+		Location:  location,
+		Previous:  previous,
+		Current:   current,
+		New:       []Issue{},
+		Fixed:     []Issue{},
+		Unchanged: []Issue{},
+	}
+
+	// Issues are matched by their IDs, so a diff of scans in different languages or of a moved
+	// finding shows no changes. Occurrences are counted so repeated identical issues are matched
+	// one to one.
+	seen := make(map[string]int)
+	for _, issue := range before {
+		seen[issue.ID]++
+	}
+	for _, issue := range after {
+		if seen[issue.ID] > 0 {
+			seen[issue.ID]--
+			diff.Unchanged = append(diff.Unchanged, issue)
+		} else {
+			diff.New = append(diff.New, issue)
+		}
+	}
+	for _, issue := range before {
+		if seen[issue.ID] > 0 {
+			seen[issue.ID]--
+			diff.Fixed = append(diff.Fixed, issue)
+		}
+	}
+
+	sortIssues(diff.New)
+	sortIssues(diff.Fixed)
+	sortIssues(diff.Unchanged)
+	return diff
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Checkname != issues[j].Checkname {
+			return issues[i].Checkname < issues[j].Checkname
+		}
+		if issues[i].ArchiveName != issues[j].ArchiveName {
+			return issues[i].ArchiveName < issues[j].ArchiveName
+		}
+		if issues[i].Subject != issues[j].Subject {
+			return issues[i].Subject < issues[j].Subject
+		}
+		return issues[i].Message < issues[j].Message
+	})
+}
+
+// FormatText renders the diff as plain text for the console
+func (d *Diff) FormatText() string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Changes in '%s' since %s:\n", d.Location, d.Previous)
+	fmt.Fprintf(&output, "  %d new, %d fixed, %d unchanged\n", len(d.New), len(d.Fixed), len(d.Unchanged))
+
+	writeSection := func(title, marker string, issues []Issue) {
+		if len(issues) == 0 {
+			return
+		}
+		fmt.Fprintf(&output, "\n%s:\n", title)
+		for _, issue := range issues {
+			subject := issue.Subject
+			if issue.ArchiveName != "" {
+				subject = issue.ArchiveName + " > " + subject
+			}
+			fmt.Fprintf(&output, "  %s [%s] %s: %s\n", marker, issue.Checkname, subject, issue.Message)
+		}
+	}
+	writeSection("New issues", "+", d.New)
+	writeSection("Fixed issues", "-", d.Fixed)
+	return output.String()
+}
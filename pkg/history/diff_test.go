@@ -0,0 +1,104 @@
+package history
+
+import (
+	"strings"
+	"testing"
+)
+
+const previousReport = `{"details_check_focused": [
+	{"checkname": "IsFreeOfKeywords", "issues": [
+		{"subject": "a.txt", "path": "a.txt", "message": "found password"},
+		{"subject": "b.txt", "path": "b.txt", "message": "found password"}
+	]},
+	{"checkname": "HasNoWhiteSpace", "issues": [
+		{"subject": "my file.txt", "path": "my file.txt", "message": "contains whitespace"}
+	]}
+]}`
+
+const currentReport = `{"details_check_focused": [
+	{"checkname": "IsFreeOfKeywords", "issues": [
+		{"subject": "a.txt", "path": "a.txt", "message": "found password"},
+		{"subject": "c.txt", "path": "c.txt", "archive_name": "data.zip", "message": "found token"}
+	]}
+]}`
+
+func TestDiffLatest(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	if _, err := store.Save("pkg", previousReport); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Save("pkg", currentReport); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := store.DiffLatest("pkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.New) != 1 || diff.New[0].Subject != "c.txt" || diff.New[0].ArchiveName != "data.zip" {
+		t.Errorf("unexpected new issues: %+v", diff.New)
+	}
+	if len(diff.Fixed) != 2 || diff.Fixed[0].Checkname != "HasNoWhiteSpace" || diff.Fixed[1].Subject != "b.txt" {
+		t.Errorf("unexpected fixed issues: %+v", diff.Fixed)
+	}
+	if len(diff.Unchanged) != 1 || diff.Unchanged[0].Subject != "a.txt" {
+		t.Errorf("unexpected unchanged issues: %+v", diff.Unchanged)
+	}
+
+	text := diff.FormatText()
+	if !strings.Contains(text, "1 new, 2 fixed, 1 unchanged") || !strings.Contains(text, "+ [IsFreeOfKeywords] data.zip > c.txt: found token") {
+		t.Errorf("unexpected text output:\n%s", text)
+	}
+}
+
+func TestCompareMatchesDuplicatesOneToOne(t *testing.T) {
+	report := `{"details_check_focused": [{"checkname": "X", "issues": [{"subject": "a", "message": "m"}%s]}]}`
+	previous := &Entry{Report: []byte(strings.Replace(report, "%s", "", 1))}
+	current := &Entry{Report: []byte(strings.Replace(report, "%s", `, {"subject": "a", "message": "m"}`, 1))}
+
+	diff, err := Compare(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.New) != 1 || len(diff.Unchanged) != 1 || len(diff.Fixed) != 0 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestCompareMatchesByID(t *testing.T) {
+	// The same finding reported in German and in English, the second line having moved
+	previous := `{"details_check_focused": [{"checkname": "HasLicense", "issues": [{"subject": "repository", "message": "Das Paket hat keine Lizenz."}]},
+		{"checkname": "IsFreeOfKeywords", "issues": [{"subject": "a.txt", "path": "a.txt", "message": "found password", "line": 3}]}]}`
+	current := `{"details_check_focused": [{"checkname": "HasLicense", "issues": [{"subject": "repository", "message": "The package has no license."}]},
+		{"checkname": "IsFreeOfKeywords", "issues": [{"subject": "a.txt", "path": "a.txt", "message": "found password", "line": 12}]}]}`
+
+	diff, err := CompareReports([]byte(previous), []byte(current))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.New) != 0 || len(diff.Fixed) != 0 || len(diff.Unchanged) != 2 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if diff.Unchanged[0].ID == "" || diff.Unchanged[0].ID == diff.Unchanged[1].ID {
+		t.Errorf("expected the IDs of the issues, got %+v", diff.Unchanged)
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	previous := strings.Replace(previousReport, "{", `{"timestamp": "2026-01-01T10:00:00Z",`, 1)
+	diff, err := CompareReports([]byte(previous), []byte(currentReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.New) != 1 || len(diff.Fixed) != 2 || len(diff.Unchanged) != 1 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if diff.Previous != "2026-01-01T10:00:00Z" || diff.Current != "" {
+		t.Errorf("unexpected timestamps %q, %q", diff.Previous, diff.Current)
+	}
+
+	if _, err := CompareReports([]byte(previousReport), []byte("[")); err == nil || !strings.Contains(err.Error(), "invalid current report") {
+		t.Errorf("expected an error for an invalid report, got %v", err)
+	}
+}
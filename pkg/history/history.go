@@ -0,0 +1,157 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoPrevious is returned when a location has fewer than two stored scans
+var ErrNoPrevious = errors.New("no previous scan to compare with")
+
+// timestampLayout is used for the file names, it sorts lexically in chronological order
+const timestampLayout = "20060102T150405.000000000Z"
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Store keeps scan results per location as JSON files below a directory, one directory per
+// location and one file per scan. Curators can browse, copy and prune the files without tools,
+// and the reports are JSON already; the scans are only ever read per location or all at once,
+// which needs no queries.
+type Store struct {
+	Dir string
+}
+
+// Entry is a single stored scan
+type Entry struct {
+	Location  string          `json:"location"`
+	Timestamp time.Time       `json:"timestamp"`
+	Report    json.RawMessage `json:"report"`
+}
+
+// NewStore creates the history directory if needed
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("history directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &Store{Dir: dir}, nil
+}
+
+// locationDir returns the directory holding the scans of a location.
+// The readable prefix helps browsing, the hash keeps distinct locations apart.
+func (s *Store) locationDir(location string) string {
+	sum := sha256.Sum256([]byte(location))
+	name := strings.Trim(unsafeChars.ReplaceAllString(location, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return filepath.Join(s.Dir, name+"-"+hex.EncodeToString(sum[:])[:12])
+}
+
+// Save stores the JSON report of a scan of location
+func (s *Store) Save(location string, report string) (*Entry, error) {
+	if !json.Valid([]byte(report)) {
+		return nil, fmt.Errorf("report is not valid JSON")
+	}
+	entry := &Entry{
+		Location:  location,
+		Timestamp: time.Now().UTC(),
+		Report:    json.RawMessage(report),
+	}
+
+	dir := s.locationDir(location)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, entry.Timestamp.Format(timestampLayout)+".json")
+	if err := writeFileAtomic(path, data); err != nil {
+		return nil, fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return entry, nil
+}
+
+// writeFileAtomic writes data to a temporary file renamed to path, so the server never lists a
+// scan that is only partly written
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// List returns all stored scans of location, oldest first
+func (s *Store) List(location string) ([]*Entry, error) {
+	return readEntries(s.locationDir(location))
+}
+
+// readEntries returns the scans stored in the directory of a location, oldest first
+func readEntries(dir string) ([]*Entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	entries := make([]*Entry, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid history entry %s: %w", name, err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// DiffLatest compares the two most recent scans of location
+func (s *Store) DiffLatest(location string) (*Diff, error) {
+	entries, err := s.List(location)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) < 2 {
+		return nil, ErrNoPrevious
+	}
+	return Compare(entries[len(entries)-2], entries[len(entries)-1])
+}
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreSaveAndList(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List("my/package")
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty history, got %v (%v)", entries, err)
+	}
+
+	if _, err := store.Save("my/package", `{"a": 1}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Save("my/package", `{"a": 2}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Save("other", `{}`); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = store.List("my/package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if string(entries[1].Report) != `{"a":2}` || entries[0].Location != "my/package" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestStoreSaveLeavesNoTemporaryFiles(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Save("my/package", `{"a": 1}`); err != nil {
+		t.Fatal(err)
+	}
+	files, err := os.ReadDir(store.locationDir("my/package"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Ext(files[0].Name()) != ".json" {
+		t.Errorf("expected only the stored scan, got %v", files)
+	}
+	if info, err := files[0].Info(); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("expected a readable entry, got %v (%v)", info.Mode(), err)
+	}
+}
+
+func TestStoreRejectsInvalidReport(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	if _, err := store.Save("pkg", "not json"); err == nil {
+		t.Error("expected an error for an invalid report")
+	}
+	if _, err := NewStore(""); err == nil {
+		t.Error("expected an error without directory")
+	}
+}
+
+func TestDiffLatestNeedsTwoScans(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	store.Save("pkg", `{}`)
+	if _, err := store.DiffLatest("pkg"); !errors.Is(err, ErrNoPrevious) {
+		t.Errorf("expected ErrNoPrevious, got %v", err)
+	}
+}
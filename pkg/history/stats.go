@@ -0,0 +1,281 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// Periods the trend of the statistics can be grouped by
+var Periods = []string{"day", "week", "month"}
+
+// Stats summarizes the scans of many locations, e.g. of all packages of an organization. The
+// issues of a location are those of its latest scan; the statistics name no location.
+type Stats struct {
+	From           string       `json:"from"`            // Time of the first scan
+	To             string       `json:"to"`              // Time of the latest scan
+	Scans          int          `json:"scans"`           // Scans read
+	Locations      int          `json:"locations"`       // Locations scanned
+	CleanLocations int          `json:"clean_locations"` // Locations whose latest scan found no issues
+	Issues         int          `json:"issues"`          // Issues of the latest scans
+	AverageIssues  float64      `json:"average_issues"`  // Issues per location
+	Checks         []CheckStats `json:"checks"`          // Checks that reported issues, most frequent first
+	Trend          []Period     `json:"trend"`           // Scans per period, oldest first
+}
+
+// CheckStats counts the issues of a check in the latest scans
+type CheckStats struct {
+	Checkname string `json:"checkname"`
+	Severity  string `json:"severity"`
+	Issues    int    `json:"issues"`
+	Locations int    `json:"locations"` // Locations with issues of the check
+}
+
+// Period summarizes the scans of a day, week or month. Locations scanned more than once in the
+// period count with their latest scan.
+type Period struct {
+	Period        string  `json:"period"` // 2024-03-01, 2024-W09 or 2024-03
+	Scans         int     `json:"scans"`
+	Locations     int     `json:"locations"`
+	Issues        int     `json:"issues"`
+	AverageIssues float64 `json:"average_issues"`
+}
+
+// All returns the stored scans of every location, oldest first per location
+func (s *Store) All() ([]*Entry, error) {
+	dirs, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		located, err := readEntries(filepath.Join(s.Dir, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, located...)
+	}
+	return entries, nil
+}
+
+// ReadScans reads the scans below the given paths: directories and files of a history directory
+// or JSON reports written with -json. Reports are located by their location, or by their path if
+// they were written before reports named it.
+func ReadScans(paths []string) ([]*Entry, error) {
+	var entries []*Entry
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || (path != root && !strings.HasSuffix(path, ".json")) {
+				return nil
+			}
+			entry, err := readScan(path)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// readScan reads a history entry or a JSON report
+func readScan(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe struct {
+		Report json.RawMessage `json:"report"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid report: %w", path, err)
+	}
+	if probe.Report != nil {
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid history entry %s: %w", path, err)
+		}
+		return &entry, nil
+	}
+
+	var report jsonformatter.ScanResult
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid report: %w", path, err)
+	}
+	entry := Entry{Location: report.Location, Report: data}
+	if entry.Location == "" {
+		entry.Location = path
+	}
+	if entry.Timestamp, err = time.Parse(time.RFC3339, report.Timestamp); err != nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		entry.Timestamp = info.ModTime().UTC()
+	}
+	return &entry, nil
+}
+
+// periodOf returns the name of the period of t
+func periodOf(t time.Time, period string) string {
+	switch period {
+	case "day":
+		return t.Format("2006-01-02")
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01")
+}
+
+// ComputeStats summarizes the scans with a trend grouped by period: day, week or month
+func ComputeStats(entries []*Entry, period string) (*Stats, error) {
+	if period == "" {
+		period = "month"
+	}
+	known := false
+	for _, p := range Periods {
+		known = known || p == period
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown period '%s', expected one of %s", period, strings.Join(Periods, ", "))
+	}
+
+	type scan struct {
+		entry    *Entry
+		issues   int
+		byCheck  map[string]int
+		severity map[string]string
+	}
+	scans := make([]scan, 0, len(entries))
+	for _, entry := range entries {
+		var report jsonformatter.ScanResult
+		if err := json.Unmarshal(entry.Report, &report); err != nil {
+			return nil, fmt.Errorf("invalid report of '%s' from %s: %w", entry.Location, entry.Timestamp.Format(time.RFC3339), err)
+		}
+		s := scan{entry: entry, byCheck: map[string]int{}, severity: map[string]string{}}
+		for _, check := range report.DetailsCheckFocused {
+			s.issues += len(check.Issues)
+			s.byCheck[check.Checkname] += len(check.Issues)
+			s.severity[check.Checkname] = check.Severity
+		}
+		scans = append(scans, s)
+	}
+	sort.SliceStable(scans, func(i, j int) bool { return scans[i].entry.Timestamp.Before(scans[j].entry.Timestamp) })
+
+	stats := &Stats{Scans: len(scans), Checks: []CheckStats{}, Trend: []Period{}}
+	if len(scans) == 0 {
+		return stats, nil
+	}
+	stats.From = scans[0].entry.Timestamp.UTC().Format(time.RFC3339)
+	stats.To = scans[len(scans)-1].entry.Timestamp.UTC().Format(time.RFC3339)
+
+	// The scans are in chronological order, so later scans of a location replace earlier ones
+	latest := map[string]scan{}
+	periods := map[string]map[string]scan{}
+	var order []string
+	counts := map[string]int{}
+	for _, s := range scans {
+		latest[s.entry.Location] = s
+		name := periodOf(s.entry.Timestamp.UTC(), period)
+		if periods[name] == nil {
+			periods[name] = map[string]scan{}
+			order = append(order, name)
+		}
+		periods[name][s.entry.Location] = s
+		counts[name]++
+	}
+
+	checkIndex := map[string]int{}
+	for _, s := range latest {
+		stats.Issues += s.issues
+		if s.issues == 0 {
+			stats.CleanLocations++
+		}
+		for checkname, issues := range s.byCheck {
+			if issues == 0 {
+				continue
+			}
+			i, ok := checkIndex[checkname]
+			if !ok {
+				i = len(stats.Checks)
+				checkIndex[checkname] = i
+				stats.Checks = append(stats.Checks, CheckStats{Checkname: checkname, Severity: s.severity[checkname]})
+			}
+			stats.Checks[i].Issues += issues
+			stats.Checks[i].Locations++
+			if stats.Checks[i].Severity == "" {
+				// Reports written before severities were recorded
+				stats.Checks[i].Severity = string(checks.SeverityOf(checkname))
+			}
+		}
+	}
+	stats.Locations = len(latest)
+	stats.AverageIssues = average(stats.Issues, stats.Locations)
+	sort.SliceStable(stats.Checks, func(i, j int) bool {
+		if stats.Checks[i].Issues != stats.Checks[j].Issues {
+			return stats.Checks[i].Issues > stats.Checks[j].Issues
+		}
+		return stats.Checks[i].Checkname < stats.Checks[j].Checkname
+	})
+
+	for _, name := range order {
+		p := Period{Period: name, Scans: counts[name], Locations: len(periods[name])}
+		for _, s := range periods[name] {
+			p.Issues += s.issues
+		}
+		p.AverageIssues = average(p.Issues, p.Locations)
+		stats.Trend = append(stats.Trend, p)
+	}
+	return stats, nil
+}
+
+// average returns issues per location, rounded to two decimals
+func average(issues, locations int) float64 {
+	if locations == 0 {
+		return 0
+	}
+	return float64(issues*100/locations) / 100
+}
+
+// FormatText renders the statistics as plain text for the console
+func (s *Stats) FormatText() string {
+	var output strings.Builder
+	if s.Scans == 0 {
+		output.WriteString("No scans found.\n")
+		return output.String()
+	}
+	fmt.Fprintf(&output, "%d scans of %d locations from %s to %s\n", s.Scans, s.Locations, s.From, s.To)
+	fmt.Fprintf(&output, "  %d issues in the latest scans, %.2f per location, %d locations without issues\n", s.Issues, s.AverageIssues, s.CleanLocations)
+
+	if len(s.Checks) > 0 {
+		output.WriteString("\nMost frequent checks:\n")
+		for _, check := range s.Checks {
+			fmt.Fprintf(&output, "  %6d  %-30s %-8s in %d locations\n", check.Issues, check.Checkname, check.Severity, check.Locations)
+		}
+	}
+
+	output.WriteString("\nTrend:\n")
+	for _, p := range s.Trend {
+		fmt.Fprintf(&output, "  %-10s %4d scans of %4d locations, %6d issues, %.2f per location\n", p.Period, p.Scans, p.Locations, p.Issues, p.AverageIssues)
+	}
+	return output.String()
+}
@@ -0,0 +1,109 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scanOf builds a stored scan with the given number of issues per check
+func scanOf(location, timestamp string, issues map[string]int) *Entry {
+	var checks []string
+	for checkname, n := range issues {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = `{"subject": "f.txt", "path": "/f.txt", "message": "m"}`
+		}
+		checks = append(checks, `{"checkname": "`+checkname+`", "issues": [`+strings.Join(items, ",")+`]}`)
+	}
+	at, _ := time.Parse(time.RFC3339, timestamp)
+	return &Entry{Location: location, Timestamp: at, Report: []byte(`{"details_check_focused": [` + strings.Join(checks, ",") + `]}`)}
+}
+
+func TestComputeStats(t *testing.T) {
+	entries := []*Entry{
+		scanOf("lake", "2024-01-10T10:00:00Z", map[string]int{"HasNoWhiteSpace": 4, "IsFreeOfKeywords": 1}),
+		scanOf("lake", "2024-02-03T10:00:00Z", map[string]int{"HasNoWhiteSpace": 2}),
+		scanOf("river", "2024-01-20T10:00:00Z", map[string]int{"HasNoWhiteSpace": 1, "IsFreeOfKeywords": 3}),
+		scanOf("pond", "2024-02-05T10:00:00Z", nil),
+	}
+
+	stats, err := ComputeStats(entries, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scans != 4 || stats.Locations != 3 || stats.CleanLocations != 1 || stats.Issues != 6 || stats.AverageIssues != 2 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.From != "2024-01-10T10:00:00Z" || stats.To != "2024-02-05T10:00:00Z" {
+		t.Errorf("unexpected range: %s to %s", stats.From, stats.To)
+	}
+	expectedChecks := []CheckStats{
+		{Checkname: "HasNoWhiteSpace", Severity: "warning", Issues: 3, Locations: 2},
+		{Checkname: "IsFreeOfKeywords", Severity: "error", Issues: 3, Locations: 1},
+	}
+	if len(stats.Checks) != 2 || stats.Checks[0] != expectedChecks[0] || stats.Checks[1] != expectedChecks[1] {
+		t.Errorf("unexpected checks: %+v", stats.Checks)
+	}
+	expectedTrend := []Period{
+		{Period: "2024-01", Scans: 2, Locations: 2, Issues: 9, AverageIssues: 4.5},
+		{Period: "2024-02", Scans: 2, Locations: 2, Issues: 2, AverageIssues: 1},
+	}
+	if len(stats.Trend) != 2 || stats.Trend[0] != expectedTrend[0] || stats.Trend[1] != expectedTrend[1] {
+		t.Errorf("unexpected trend: %+v", stats.Trend)
+	}
+
+	text := stats.FormatText()
+	for _, expected := range []string{"4 scans of 3 locations", "6 issues in the latest scans, 2.00 per location, 1 locations without issues", "HasNoWhiteSpace", "2024-02"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("text is missing %q:\n%s", expected, text)
+		}
+	}
+
+	weekly, err := ComputeStats(entries, "week")
+	if err != nil || len(weekly.Trend) != 4 || weekly.Trend[0].Period != "2024-W02" {
+		t.Errorf("unexpected weekly trend: %+v (%v)", weekly, err)
+	}
+	if _, err := ComputeStats(entries, "year"); err == nil || !strings.Contains(err.Error(), "unknown period") {
+		t.Errorf("expected an unknown period error, got %v", err)
+	}
+	if empty, err := ComputeStats(nil, "day"); err != nil || empty.Scans != 0 || empty.FormatText() != "No scans found.\n" {
+		t.Errorf("unexpected statistics without scans: %+v (%v)", empty, err)
+	}
+}
+
+func TestReadScans(t *testing.T) {
+	historyDir := t.TempDir()
+	store, _ := NewStore(historyDir)
+	store.Save("lake", `{"details_check_focused": []}`)
+	store.Save("river", `{"details_check_focused": []}`)
+
+	all, err := store.All()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected the scans of both locations, got %d (%v)", len(all), err)
+	}
+
+	reportsDir := t.TempDir()
+	os.WriteFile(filepath.Join(reportsDir, "pond.json"), []byte(`{"location": "pond", "timestamp": "2024-03-01T08:00:00Z"}`), 0644)
+	os.WriteFile(filepath.Join(reportsDir, "old.json"), []byte(`{"timestamp": ""}`), 0644)
+	os.WriteFile(filepath.Join(reportsDir, "notes.txt"), []byte(`not json`), 0644)
+
+	entries, err := ReadScans([]string{historyDir, reportsDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations := map[string]bool{}
+	for _, entry := range entries {
+		locations[entry.Location] = true
+	}
+	oldPath := filepath.Join(reportsDir, "old.json")
+	if len(entries) != 4 || !locations["lake"] || !locations["river"] || !locations["pond"] || !locations[oldPath] {
+		t.Errorf("unexpected scans: %v", locations)
+	}
+
+	if _, err := ReadScans([]string{filepath.Join(reportsDir, "notes.txt")}); err == nil {
+		t.Error("expected an error for a file that is not a report")
+	}
+}
@@ -0,0 +1,236 @@
+package i18n
+
+// german translates the output into German
+var german = map[string]string{
+	// Messages of the checks by file
+	"File name contains invalid character: %s":   "Der Dateiname enthält ein ungültiges Zeichen: %s",
+	"File name contains non-ASCII character: %s": "Der Dateiname enthält Zeichen außerhalb von ASCII: %s",
+	"File name contains spaces.":                 "Der Dateiname enthält Leerzeichen.",
+	"File name is too long.":                     "Der Dateiname ist zu lang.",
+	"Path '%s' is %d characters long, more than %d, unpacking the package on Windows may fail.": "Der Pfad '%s' ist %d Zeichen lang, mehr als %d, das Entpacken des Pakets unter Windows kann fehlschlagen.",
+	"File or Folder has an invalid name: %s":                                                    "Die Datei oder der Ordner hat einen ungültigen Namen: %s",
+	"File has an invalid suffix: %s":                                                            "Die Datei hat eine ungültige Endung: %s",
+	"'%s' is a %s, please delete it before publishing the package.":                             "'%s' (%s) bitte vor der Veröffentlichung des Pakets löschen.",
+	"'%s' is a %s, please remove it from the archive.":                                          "'%s' (%s) bitte aus dem Archiv entfernen.",
+	"Windows thumbnail cache":                                                                   "Windows-Miniaturansichtscache",
+	"Windows folder settings file":                                                              "Windows-Ordnereinstellungsdatei",
+	"macOS Finder settings file":                                                                "macOS-Finder-Einstellungsdatei",
+	"macOS resource fork":                                                                       "macOS-Ressourcenzweig",
+	"macOS archive metadata folder":                                                             "macOS-Archivmetadatenordner",
+	"macOS Spotlight index":                                                                     "macOS-Spotlight-Index",
+	"macOS trash folder":                                                                        "macOS-Papierkorbordner",
+	"macOS file system event log":                                                               "macOS-Dateisystem-Ereignisprotokoll",
+	"Microsoft Office lock file":                                                                "Microsoft-Office-Sperrdatei",
+	"LibreOffice lock file":                                                                     "LibreOffice-Sperrdatei",
+	"Vim swap file":                                                                             "Vim-Auslagerungsdatei",
+	"Jupyter checkpoint folder":                                                                 "Jupyter-Checkpoint-Ordner",
+	"Python bytecode cache":                                                                     "Python-Bytecode-Cache",
+	"%s repository with the full history of the project":                                        "%s-Repository mit der gesamten Geschichte des Projekts",
+	"'%s' is a %s, executables should not be part of a data package.":                           "'%s' (%s): ausführbare Dateien gehören nicht in ein Datenpaket.",
+	"Windows program":                                                                           "Windows-Programm",
+	"Windows library":                                                                           "Windows-Bibliothek",
+	"Windows installer":                                                                         "Windows-Installationsprogramm",
+	"Windows batch script":                                                                      "Windows-Batch-Skript",
+	"PowerShell script":                                                                         "PowerShell-Skript",
+	"VBScript":                                                                                  "VBScript",
+	"Linux executable (ELF)":                                                                    "ausführbare Linux-Datei (ELF)",
+	"macOS executable (Mach-O)":                                                                 "ausführbare macOS-Datei (Mach-O)",
+	"Windows program (PE)":                                                                      "Windows-Programm (PE)",
+	"shell script":                                                                              "Shell-Skript",
+	"Keywords found: %s":                                                                        "Gefundene Schlüsselwörter: %s",
+	"%s '%s'":                                                                                   "%s '%s'",
+	"%s in sheet/paragraph/table %d":                                                            "%s in Tabelle/Absatz/Tabellenblatt %d",
+	"%s on slide %d":                                                                            "%s auf Folie %d",
+	"%s in cell %d":                                                                             "%s in Zelle %d",
+	"Security credentials detected":                                                             "Zugangsdaten gefunden",
+	"Private key detected":                                                                      "Privater Schlüssel gefunden",
+	"Authentication token detected":                                                             "Authentifizierungstoken gefunden",
+	"Database credentials detected":                                                             "Datenbank-Zugangsdaten gefunden",
+	"Administrative accounts detected":                                                          "Administratorkonten gefunden",
+	"Credentials detected":                                                                      "Zugangsdaten gefunden",
+	"Possible credentials in file":                                                              "Mögliche Zugangsdaten in der Datei",
+	"Possible internal information in file":                                                     "Mögliche interne Informationen in der Datei",
+	"Absolute path '%s' discloses the user name '%s'":                                           "Der absolute Pfad '%s' verrät den Benutzernamen '%s'",
+	"Absolute path '%s'":                                                                        "Absoluter Pfad '%s'",
+	"%s in the output of cell %d.":                                                              "%s in der Ausgabe von Zelle %d.",
+	"%s in the traceback of cell %d.":                                                           "%s im Traceback von Zelle %d.",
+	"The output of cell %d embeds %d bytes of %s, clear the outputs or publish the data as a separate file.":    "Die Ausgabe von Zelle %d bettet %d Bytes %s ein, bitte die Ausgaben löschen oder die Daten als eigene Datei veröffentlichen.",
+	"An attachment of cell %d embeds %d bytes of %s, clear the outputs or publish the data as a separate file.": "Ein Anhang von Zelle %d bettet %d Bytes %s ein, bitte die Ausgaben löschen oder die Daten als eigene Datei veröffentlichen.",
+	"The notebook cannot be read, the file may be corrupt: %s.":                                                 "Das Notebook kann nicht gelesen werden, die Datei ist möglicherweise beschädigt: %s.",
+	"The date '%s' in the file name '%s' is written as %s, write dates as %s.":                                  "Das Datum '%s' im Dateinamen '%s' ist als %s geschrieben, bitte Daten als %s schreiben.",
+	"File names write dates in %d formats: %s, use one format in the package.":                                  "Die Dateinamen schreiben Daten in %d Formaten: %s, bitte im Paket ein Format verwenden.",
+	"%s (1 file)":   "%s (1 Datei)",
+	"%s (%d files)": "%s (%d Dateien)",
+	"The archive is corrupt or truncated, please upload it again: %s.":                                                                            "Das Archiv ist beschädigt oder unvollständig, bitte erneut hochladen: %s.",
+	"The archive contains only '%s', consider publishing the file itself.":                                                                        "Das Archiv enthält nur '%s', bitte stattdessen die Datei selbst veröffentlichen.",
+	"The archive contains the already compressed file '%s', which archiving does not make smaller.":                                               "Das Archiv enthält die bereits komprimierte Datei '%s', die durch die Archivierung nicht kleiner wird.",
+	"The archive contains %d already compressed files (%s), which archiving does not make smaller, consider publishing them without the archive.": "Das Archiv enthält %d bereits komprimierte Dateien (%s), die durch die Archivierung nicht kleiner werden, bitte sie ohne das Archiv veröffentlichen.",
+	"The %s file '%s' lacks the global attribute '%s'.":                                                                                           "Der %s-Datei '%s' fehlt das globale Attribut '%s'.",
+	"The %s file '%s' lacks the global attributes '%s'.":                                                                                          "Der %s-Datei '%s' fehlen die globalen Attribute '%s'.",
+	"The %s file '%s' is corrupt: %s.":                                                                                                            "Die %s-Datei '%s' ist beschädigt: %s.",
+	"'%s' is neither a NetCDF nor an HDF5 file, the file may be corrupt.":                                                                         "'%s' ist weder eine NetCDF- noch eine HDF5-Datei, die Datei ist möglicherweise beschädigt.",
+	"the header is truncated":                                   "der Header ist abgeschnitten",
+	"the superblock is truncated":                               "der Superblock ist abgeschnitten",
+	"the superblock is corrupt":                                 "der Superblock ist beschädigt",
+	"the object header is corrupt":                              "der Objekt-Header ist beschädigt",
+	"the header has an implausible size of %d bytes":            "der Header hat eine unplausible Größe von %d Bytes",
+	"the header has an implausible count of %d":                 "der Header hat eine unplausible Anzahl von %d",
+	"the NetCDF version %d is unknown":                          "die NetCDF-Version %d ist unbekannt",
+	"the superblock version %d is unknown":                      "die Superblock-Version %d ist unbekannt",
+	"the file is truncated, it should have %d bytes but has %d": "die Datei ist abgeschnitten, sie sollte %d Bytes haben, hat aber %d",
+	"the file is truncated, the data of the variable '%s' ends at byte %d but the file has %d bytes":                                                                                       "die Datei ist abgeschnitten, die Daten der Variable '%s' enden bei Byte %d, die Datei hat aber %d Bytes",
+	"'%s' has %d of %d positions outside the range of longitude (-180 to 180) and latitude (-90 to 90), e.g. %s, the coordinates may be projected or have longitude and latitude swapped.": "'%s' hat %d von %d Positionen außerhalb des Bereichs von Länge (-180 bis 180) und Breite (-90 bis 90), z. B. %s, die Koordinaten sind möglicherweise projiziert oder Länge und Breite vertauscht.",
+	"'%s' has %d of %d positions outside the bounding box %s, e.g. %s.":                                                                                                                    "'%s' hat %d von %d Positionen außerhalb des Begrenzungsrahmens %s, z. B. %s.",
+	"The shapefile '%s' is corrupt: %s.":                                               "Das Shapefile '%s' ist beschädigt: %s.",
+	"The shapefile '%s' has no .prj file, its coordinate reference system is unknown.": "Das Shapefile '%s' hat keine .prj-Datei, sein Koordinatenreferenzsystem ist unbekannt.",
+	"The extent %s of the shapefile '%s' is outside the range of longitude (-180 to 180) and latitude (-90 to 90), although its .prj file declares a geographic coordinate system.": "Die Ausdehnung %s des Shapefiles '%s' liegt außerhalb des Bereichs von Länge (-180 bis 180) und Breite (-90 bis 90), obwohl seine .prj-Datei ein geographisches Koordinatensystem angibt.",
+	"The extent %s of the shapefile '%s' reaches outside the bounding box %s.":                                                                                                      "Die Ausdehnung %s des Shapefiles '%s' reicht über den Begrenzungsrahmen %s hinaus.",
+	"not a shapefile":                       "kein Shapefile",
+	"the shapefile version %d is unknown":   "die Shapefile-Version %d ist unbekannt",
+	"coordinates must be arrays of numbers": "Koordinaten müssen Arrays von Zahlen sein",
+	"a position has a single number":        "eine Position hat nur eine Zahl",
+	"The image metadata contains %s, remove it before publishing if it is private.": "Die Bildmetadaten enthalten %s, bitte vor der Veröffentlichung entfernen, falls sie privat sind.",
+	"the GPS position %s":    "die GPS-Position %s",
+	"the serial number '%s'": "die Seriennummer '%s'",
+	"the name '%s'":          "den Namen '%s'",
+	"The URL '%s' contains credentials and may disclose an internal package index, remove them before publishing.":                "Die URL '%s' enthält Zugangsdaten und kann einen internen Paketindex verraten, bitte sie vor der Veröffentlichung entfernen.",
+	"Local path '%s' does not exist on other computers, install the dependency from a public index or include it in the package.": "Der lokale Pfad '%s' existiert auf anderen Computern nicht, bitte die Abhängigkeit aus einem öffentlichen Index installieren oder ins Paket aufnehmen.",
+	"The dependency '%s' has no pinned version, pin it so the environment can be recreated.":                                      "Die Abhängigkeit '%s' hat keine feste Version, bitte sie festlegen, damit die Umgebung nachgebildet werden kann.",
+	"The dependencies '%s' have no pinned version, pin them so the environment can be recreated.":                                 "Die Abhängigkeiten '%s' haben keine feste Version, bitte sie festlegen, damit die Umgebung nachgebildet werden kann.",
+	"The R package '%s' is installed from a local source, which does not exist on other computers.":                               "Das R-Paket '%s' wird aus einer lokalen Quelle installiert, die auf anderen Computern nicht existiert.",
+	"The renv lock file cannot be read, the file may be corrupt: %s.":                                                             "Die renv-Lock-Datei kann nicht gelesen werden, die Datei ist möglicherweise beschädigt: %s.",
+	"File name '%s' does not match the pattern '%s'.":                                                                             "Der Dateiname '%s' entspricht nicht dem Muster '%s'.",
+	"File name '%s' is %d characters long, more than %d.":                                                                         "Der Dateiname '%s' ist %d Zeichen lang, mehr als %d.",
+	"File name '%s' contains characters that are not allowed: '%s'.":                                                              "Der Dateiname '%s' enthält nicht erlaubte Zeichen: '%s'.",
+	"File name '%s' is reserved for the device %s on Windows.":                                                                    "Der Dateiname '%s' ist unter Windows für das Gerät %s reserviert.",
+	"Name '%s' contains characters Windows does not allow: %s, the package cannot be extracted on Windows.":                       "Der Name '%s' enthält Zeichen, die Windows nicht erlaubt: %s, das Paket kann unter Windows nicht entpackt werden.",
+	"Name '%s' ends with a dot or space, which Windows does not allow, the package cannot be extracted on Windows.":               "Der Name '%s' endet mit einem Punkt oder Leerzeichen, was Windows nicht erlaubt, das Paket kann unter Windows nicht entpackt werden.",
+	"Mojibake '%s' where '%s' was meant, UTF-8 text was read as %s and encoded again":                                             "Zeichensalat '%s' statt '%s', UTF-8-Text wurde als %s gelesen und erneut kodiert",
+	"Mojibake '%s' where '%s' was meant, UTF-8 text was read as %s and encoded again %d times":                                    "Zeichensalat '%s' statt '%s', UTF-8-Text wurde als %s gelesen und %d-mal erneut kodiert",
+	"%s: File name '%s' does not match the pattern '%s'.":                                                                         "%s: Der Dateiname '%s' entspricht nicht dem Muster '%s'.",
+	"%s: File name '%s' is %d characters long, more than %d.":                                                                     "%s: Der Dateiname '%s' ist %d Zeichen lang, mehr als %d.",
+	"%s: File name '%s' contains characters that are not allowed: '%s'.":                                                          "%s: Der Dateiname '%s' enthält nicht erlaubte Zeichen: '%s'.",
+	"%s: File name '%s' is reserved for the device %s on Windows.":                                                                "%s: Der Dateiname '%s' ist unter Windows für das Gerät %s reserviert.",
+	"... and %d more findings (maxFindingsPerCheck = %d)":                                                                         "... und %d weitere Funde (maxFindingsPerCheck = %d)",
+	"... and %d more similar messages (truncated)":                                                                                "... und %d weitere ähnliche Meldungen (gekürzt)",
+
+	// Messages of the checks by repository
+	"No ReadMe file in repository.": "Das Repository enthält keine ReadMe-Datei.",
+	"ReadMe file is missing a complete table of contents for this repository. Missing files are: %s": "Der ReadMe-Datei fehlt ein vollständiges Inhaltsverzeichnis des Repositorys. Es fehlen die Dateien: %s",
+	"The package has no '%s/' folder.":                        "Das Paket hat keinen Ordner '%s/'.",
+	"The folder '%s/' is not allowed in the package.":         "Der Ordner '%s/' ist im Paket nicht erlaubt.",
+	"The readme refers to '%s', which is not in the package.": "Die Readme verweist auf '%s', das nicht im Paket enthalten ist.",
+	"The readme does not mention the file '%s'.":              "Die Readme erwähnt die Datei '%s' nicht.",
+	"The readme does not mention the files '%s'.":             "Die Readme erwähnt die Dateien '%s' nicht.",
+
+	// Messages of the checks by metadata
+	"The package has no description.":                     "Das Paket hat keine Beschreibung.",
+	"The package has no license.":                         "Das Paket hat keine Lizenz.",
+	"The author email '%s' looks like a placeholder.":     "Die E-Mail-Adresse des Autors '%s' sieht wie ein Platzhalter aus.",
+	"The maintainer email '%s' looks like a placeholder.": "Die E-Mail-Adresse des Betreuers '%s' sieht wie ein Platzhalter aus.",
+	"%s in the title":                                     "%s im Titel",
+	"%s in the description":                               "%s in der Beschreibung",
+
+	// Summaries
+	"We have analyzed your data package and found a few issues. Please address them and get back to us once you're done. Then, we can continue with the publication process. Feel free to get back to us, if something is unclear.": "Wir haben Ihr Datenpaket geprüft und einige Probleme gefunden. Bitte beheben Sie diese und melden Sie sich bei uns, sobald Sie fertig sind. Dann können wir mit der Veröffentlichung fortfahren. Melden Sie sich gerne, wenn etwas unklar ist.",
+	"No scan data available.":                "Keine Scan-Daten vorhanden.",
+	"Package Checker Scan Summary":           "Zusammenfassung des Package-Checker-Scans",
+	"Location:":                              "Ort:",
+	"Timestamp:":                             "Zeitpunkt:",
+	"Total:":                                 "Gesamt:",
+	"No issues found.":                       "Keine Probleme gefunden.",
+	"Issues by Type":                         "Probleme nach Typ",
+	"%s in %s":                               "%s in %s",
+	"%d issue":                               "%d Problem",
+	"%d issues":                              "%d Probleme",
+	"%d file":                                "%d Datei",
+	"%d files":                               "%d Dateien",
+	"%d false positive":                      "%d Fehlalarm",
+	"%d false positives":                     "%d Fehlalarme",
+	"Not listed: %d accepted, %s (baseline)": "Nicht aufgeführt: %d akzeptiert, %s (Baseline)",
+	"and %d more in %s":                      "und %d weitere in %s",
+	"with":                                   "mit",
+	"similar issues":                         "ähnliche Probleme",
+	"Repository":                             "Repository",
+	"File":                                   "Datei",
+	"Line":                                   "Zeile",
+	"Issue":                                  "Problem",
+	"Possible sensitive content detected":    "Möglicherweise sensible Inhalte gefunden",
+	"File name issues":                       "Probleme mit Dateinamen",
+	"File name too long":                     "Dateiname zu lang",
+	"Special characters in file name":        "Sonderzeichen im Dateinamen",
+	"Leading or trailing spaces in file name": "Leerzeichen am Anfang oder Ende des Dateinamens",
+	"Non-ASCII characters in file name":       "Zeichen außerhalb von ASCII im Dateinamen",
+	"Missing README file":                     "Fehlende README-Datei",
+	"Table of contents issues":                "Probleme mit dem Inhaltsverzeichnis",
+	"Invalid file names in archive":           "Ungültige Dateinamen im Archiv",
+	"Empty folders in archive":                "Leere Ordner im Archiv",
+	"Hidden files in archive":                 "Versteckte Dateien im Archiv",
+
+	// Plain output
+	"PC Scan Results":              "PC-Scan-Ergebnisse",
+	"Files scanned: %d":            "Geprüfte Dateien: %d",
+	"No issues found!":             "Keine Probleme gefunden!",
+	"Found %d issues in %d files:": "%d Probleme in %d Dateien gefunden:",
+	"Repository Issues:":           "Probleme des Repositorys:",
+	"Metadata Issues:":             "Probleme der Metadaten:",
+	"%s (%d issues):":              "%s (%d Probleme):",
+	"%d occurrences":               "%d Vorkommen",
+	"line %d":                      "Zeile %d",
+	"lines %s":                     "Zeilen %s",
+	"%s (%d occurrences):":         "%s (%d Vorkommen):",
+	"Summary":                      "Zusammenfassung",
+	"Total issues: %d":             "Probleme insgesamt: %d",
+	"Files with issues: %d/%d":     "Dateien mit Problemen: %d/%d",
+	"Issue types:":                 "Arten von Problemen:",
+	"Skipped %d files:":            "%d Dateien übersprungen:",
+	"Archive Listing":              "Archivinhalt",
+	"No archives found.":           "Keine Archive gefunden.",
+	"Archives: %d":                 "Archive: %d",
+	"%s could not be listed: %s":   "Der Inhalt von %s konnte nicht gelesen werden: %s",
+	"%s (%s, %s, %s unpacked):":    "%s (%s, %s, %s entpackt):",
+
+	// Terminal user interface
+	"Issues":                          "Probleme",
+	"Focused on":                      "Fokussiert auf",
+	"Details":                         "Details",
+	"Controls":                        "Steuerung",
+	"Scan Progress":                   "Scan-Fortschritt",
+	"Copy-Paste Summary":              "Zusammenfassung zum Kopieren",
+	"Summary (copied to clipboard)":   "Zusammenfassung (in die Zwischenablage kopiert)",
+	"Preparing to scan...":            "Scan wird vorbereitet...",
+	"Initializing scan...":            "Scan wird gestartet...",
+	"Subjects":                        "Objekte",
+	"Checks":                          "Prüfungen",
+	"PDFs":                            "PDFs",
+	"Skipped":                         "Übersprungen",
+	"Warnings":                        "Warnungen",
+	"Errors":                          "Fehler",
+	"Subject:":                        "Objekt:",
+	"Check:":                          "Prüfung:",
+	"Archive:":                        "Archiv:",
+	"Path:":                           "Pfad:",
+	"Issues (%d):":                    "Probleme (%d):",
+	"No subject selected":             "Kein Objekt ausgewählt",
+	"No check selected":               "Keine Prüfung ausgewählt",
+	"Press ESC or X to close":         "ESC oder X zum Schließen drücken",
+	"Scroll":                          "Blättern",
+	"Categories":                      "Kategorien",
+	"Navigate":                        "Navigieren",
+	"Search":                          "Suchen",
+	"Level":                           "Stufe",
+	"Triage":                          "Einstufen",
+	"Export":                          "Exportieren",
+	"Copy":                            "Kopieren",
+	"Open":                            "Öffnen",
+	"Rescan":                          "Neu prüfen",
+	"Resize":                          "Größe",
+	"Reports":                         "Berichte",
+	"PDF Files":                       "PDF-Dateien",
+	"Skipped Files":                   "Übersprungene Dateien",
+	"No details found":                "Keine Details gefunden",
+	"to scroll":                       "zum Blättern",
+	"Summary (clipboard unavailable)": "Zusammenfassung (Zwischenablage nicht verfügbar)",
+	"Summary (OSC 52 clipboard)":      "Zusammenfassung (OSC-52-Zwischenablage)",
+	"Quit":                            "Beenden",
+}
@@ -0,0 +1,238 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Languages of the output. The texts of pc are written in English, the catalogs translate them.
+const (
+	English = "en"
+	German  = "de"
+)
+
+// translations are the catalogs by language. Their keys are the English texts; texts with verbs
+// such as %s or %d are formats, whose translation keeps the verbs and may reorder them with
+// explicit indexes (%[2]s). Texts missing from a catalog stay in English.
+var translations = map[string]map[string]string{
+	German: german,
+}
+
+// Languages returns the languages that can be selected
+func Languages() []string {
+	return []string{English, German}
+}
+
+// IsLanguage reports whether lang is a language that can be selected, "" for English
+func IsLanguage(lang string) bool {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	_, ok := translations[lang]
+	return ok || lang == English || lang == ""
+}
+
+var language atomic.Value
+
+// SetLanguage selects the language of the output, "" for English
+func SetLanguage(lang string) error {
+	if !IsLanguage(lang) {
+		return fmt.Errorf("unknown language '%s', expected one of %s", lang, strings.Join(Languages(), ", "))
+	}
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		lang = English
+	}
+	language.Store(lang)
+	return nil
+}
+
+// Language returns the selected language of the output
+func Language() string {
+	if lang, ok := language.Load().(string); ok {
+		return lang
+	}
+	return English
+}
+
+// T translates a fixed text into the selected language
+func T(text string) string {
+	if translated, ok := translations[Language()][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Tf formats the arguments with the translation of format
+func Tf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Plural formats n with the translation of one if it is 1, of many otherwise
+func Plural(n int, one, many string) string {
+	if n == 1 {
+		return Tf(one, n)
+	}
+	return Tf(many, n)
+}
+
+// Translate translates a message of a check into the selected language. Messages are matched
+// against the formats of the catalog, the values they were built with are translated as well if
+// the catalog knows them, e.g. the kind of a junk file.
+func Translate(message string) string {
+	if Language() == English {
+		return message
+	}
+	return catalogOf(Language(), false).translate(message, false)
+}
+
+// ToEnglish translates a message of a check written in any language back into English, so
+// findings are recognized whatever language they were shown in
+func ToEnglish(message string) string {
+	for _, lang := range Languages() {
+		if lang == English {
+			continue
+		}
+		if english := catalogOf(lang, true).translate(message, false); english != message {
+			return english
+		}
+	}
+	return message
+}
+
+// template translates the messages built with one format
+type template struct {
+	pattern *regexp.Regexp // Matches the messages, a group per argument
+	args    []int          // Index of the argument of each group, starting at 1
+	output  string         // Format of the translation taking every argument as a string
+	literal int            // Characters of the format besides the verbs
+}
+
+// catalog translates messages from one language into another
+type catalog struct {
+	texts     map[string]string
+	templates []template // Sorted by literal, so the most specific format is tried first
+}
+
+var (
+	catalogsMu sync.Mutex
+	catalogs   = map[string]*catalog{}
+)
+
+// verb matches the verbs of a format, with an optional explicit argument index
+var verb = regexp.MustCompile(`%(?:\[(\d+)\])?([sdvq%])`)
+
+// catalogOf returns the catalog from English into lang, or from lang into English if reverse
+func catalogOf(lang string, reverse bool) *catalog {
+	key := lang
+	if reverse {
+		key = "-" + lang
+	}
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	if c, ok := catalogs[key]; ok {
+		return c
+	}
+
+	c := &catalog{texts: map[string]string{}}
+	for from, to := range translations[lang] {
+		if reverse {
+			from, to = to, from
+		}
+		if !verb.MatchString(from) {
+			c.texts[from] = to
+			continue
+		}
+		c.templates = append(c.templates, compile(from, to))
+	}
+	sort.SliceStable(c.templates, func(i, j int) bool {
+		if c.templates[i].literal != c.templates[j].literal {
+			return c.templates[i].literal > c.templates[j].literal
+		}
+		return c.templates[i].pattern.String() < c.templates[j].pattern.String()
+	})
+	catalogs[key] = c
+	return c
+}
+
+// compile builds the template translating messages written with the format from into to
+func compile(from, to string) template {
+	t := template{}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last, next := 0, 1
+	for _, m := range verb.FindAllStringSubmatchIndex(from, -1) {
+		pattern.WriteString(regexp.QuoteMeta(from[last:m[0]]))
+		t.literal += m[0] - last
+		last = m[1]
+		if from[m[4]:m[5]] == "%" {
+			pattern.WriteString("%")
+			t.literal++
+			continue
+		}
+		index := next
+		if m[2] >= 0 {
+			index, _ = strconv.Atoi(from[m[2]:m[3]])
+		}
+		next = index + 1
+		t.args = append(t.args, index)
+		if from[m[4]:m[5]] == "d" {
+			pattern.WriteString(`(-?\d+)`)
+		} else {
+			pattern.WriteString(`(.*?)`)
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(from[last:]))
+	t.literal += len(from) - last
+	pattern.WriteString("$")
+	t.pattern = regexp.MustCompile(pattern.String())
+
+	// The arguments are the matched texts, so every verb of the translation becomes %[n]s
+	next = 1
+	t.output = verb.ReplaceAllStringFunc(to, func(v string) string {
+		m := verb.FindStringSubmatch(v)
+		if m[2] == "%" {
+			return "%%"
+		}
+		index := next
+		if m[1] != "" {
+			index, _ = strconv.Atoi(m[1])
+		}
+		next = index + 1
+		return "%[" + strconv.Itoa(index) + "]s"
+	})
+	return t
+}
+
+// translate translates text with the texts and templates of the catalog. The arguments of a
+// template are translated in turn; as they may be lists, their items are translated on their own.
+func (c *catalog) translate(text string, isArgument bool) string {
+	if translated, ok := c.texts[text]; ok {
+		return translated
+	}
+	for _, t := range c.templates {
+		m := t.pattern.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		args := make([]interface{}, 0, len(t.args))
+		for i, index := range t.args {
+			for len(args) < index {
+				args = append(args, "")
+			}
+			args[index-1] = c.translate(m[i+1], true)
+		}
+		return fmt.Sprintf(t.output, args...)
+	}
+	if isArgument && strings.Contains(text, ", ") {
+		items := strings.Split(text, ", ")
+		for i, item := range items {
+			items[i] = c.translate(item, false)
+		}
+		return strings.Join(items, ", ")
+	}
+	return text
+}
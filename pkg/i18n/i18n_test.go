@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// useLanguage selects lang for the test and English again afterwards
+func useLanguage(t *testing.T, lang string) {
+	t.Helper()
+	if err := SetLanguage(lang); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetLanguage(English) })
+}
+
+func TestSetLanguage(t *testing.T) {
+	useLanguage(t, " DE ")
+	if Language() != German {
+		t.Errorf("Expected %s, got %s", German, Language())
+	}
+	if err := SetLanguage("fr"); err == nil || err.Error() != "unknown language 'fr', expected one of en, de" {
+		t.Errorf("Expected an unknown language to be rejected, got %v", err)
+	}
+	if Language() != German {
+		t.Errorf("Expected the language to stay %s, got %s", German, Language())
+	}
+	if err := SetLanguage(""); err != nil || Language() != English {
+		t.Errorf("Expected an empty language to select English, got %s (%v)", Language(), err)
+	}
+}
+
+func TestTexts(t *testing.T) {
+	if T("No issues found.") != "No issues found." || Plural(2, "%d file", "%d files") != "2 files" {
+		t.Errorf("Expected English texts to stay as they are")
+	}
+	useLanguage(t, German)
+	tests := []struct{ got, expected string }{
+		{T("No issues found."), "Keine Probleme gefunden."},
+		{T("Not in the catalog"), "Not in the catalog"},
+		{Tf("Files scanned: %d", 3), "Geprüfte Dateien: 3"},
+		{Plural(1, "%d file", "%d files"), "1 Datei"},
+		{Plural(4, "%d file", "%d files"), "4 Dateien"},
+	}
+	for _, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, test.got)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	useLanguage(t, German)
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"Fixed text", "No ReadMe file in repository.", "Das Repository enthält keine ReadMe-Datei."},
+		{"Format", "Path 'a/b.csv' is 300 characters long, more than 260, unpacking the package on Windows may fail.",
+			"Der Pfad 'a/b.csv' ist 300 Zeichen lang, mehr als 260, das Entpacken des Pakets unter Windows kann fehlschlagen."},
+		{"Translated argument", "'.DS_Store' is a macOS Finder settings file, please delete it before publishing the package.",
+			"'.DS_Store' (macOS-Finder-Einstellungsdatei) bitte vor der Veröffentlichung des Pakets löschen."},
+		{"Nested format", "Absolute path '/home/jdoe/lake.py' discloses the user name 'jdoe' in the traceback of cell 3.",
+			"Der absolute Pfad '/home/jdoe/lake.py' verrät den Benutzernamen 'jdoe' im Traceback von Zelle 3."},
+		{"List argument", "File names write dates in 2 formats: YYYY-MM-DD (2 files), YYYYMMDD (1 file), use one format in the package.",
+			"Die Dateinamen schreiben Daten in 2 Formaten: YYYY-MM-DD (2 Dateien), YYYYMMDD (1 Datei), bitte im Paket ein Format verwenden."},
+		{"Count at the end", "Mojibake 'ÃƒÂ¤' where 'ä' was meant, UTF-8 text was read as Windows-1252 and encoded again 2 times",
+			"Zeichensalat 'ÃƒÂ¤' statt 'ä', UTF-8-Text wurde als Windows-1252 gelesen und 2-mal erneut kodiert"},
+		{"Slide", "Private key detected 'BEGIN RSA PRIVATE KEY' on slide 3", "Privater Schlüssel gefunden 'BEGIN RSA PRIVATE KEY' auf Folie 3"},
+		{"Unknown message", "Something the catalog does not know.", "Something the catalog does not know."},
+	}
+	for _, test := range tests {
+		if got := Translate(test.message); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+		if english := ToEnglish(test.expected); english != test.message {
+			t.Errorf("%s: expected %q back in English, got %q", test.name, test.message, english)
+		}
+	}
+}
+
+// arguments returns the verbs of a format by the index of their argument
+func arguments(format string) map[int]string {
+	args := map[int]string{}
+	next := 1
+	for _, m := range verb.FindAllStringSubmatch(format, -1) {
+		if m[2] == "%" {
+			continue
+		}
+		index := next
+		if m[1] != "" {
+			index, _ = strconv.Atoi(m[1])
+		}
+		args[index] = m[2]
+		next = index + 1
+	}
+	return args
+}
+
+// Every translation takes the arguments of its English format
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range translations {
+		for from, to := range catalog {
+			if !reflect.DeepEqual(arguments(from), arguments(to)) {
+				t.Errorf("%s: the translation of %q takes other arguments: %q", lang, from, to)
+			}
+		}
+	}
+}
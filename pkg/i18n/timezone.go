@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	// The zone database is embedded, so time zones also work in containers without one
+	_ "time/tzdata"
+)
+
+// timeLayouts are the formats of the times shown to people, by language. The times of the JSON
+// reports, logs and APIs stay in UTC as RFC 3339.
+var timeLayouts = map[string]string{
+	English: "2006-01-02 15:04:05 MST",
+	German:  "02.01.2006 15:04:05 MST",
+}
+
+var timezone atomic.Pointer[time.Location]
+
+// LoadTimezone returns the time zone of an IANA name such as "Europe/Zurich", "UTC", or the
+// local time zone of the machine for "" and "Local"
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone '%s', expected a name such as Europe/Zurich or UTC", name)
+	}
+	return location, nil
+}
+
+// SetTimezone selects the time zone of the times shown to people, "" for the local one
+func SetTimezone(name string) error {
+	location, err := LoadTimezone(name)
+	if err != nil {
+		return err
+	}
+	timezone.Store(location)
+	return nil
+}
+
+// Timezone returns the selected time zone of the times shown to people
+func Timezone() *time.Location {
+	if location := timezone.Load(); location != nil {
+		return location
+	}
+	return time.Local
+}
+
+// FormatTime formats t in the selected time zone, in the format of the selected language
+func FormatTime(t time.Time) string {
+	layout, ok := timeLayouts[Language()]
+	if !ok {
+		layout = timeLayouts[English]
+	}
+	return t.In(Timezone()).Format(layout)
+}
+
+// FormatTimestamp formats an RFC 3339 timestamp of a report with FormatTime. Other texts, such
+// as the placeholder of a running scan, are returned as they are.
+func FormatTimestamp(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return FormatTime(t)
+}
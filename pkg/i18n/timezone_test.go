@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+// useTimezone selects the time zone name for the test and the local one again afterwards
+func useTimezone(t *testing.T, name string) {
+	t.Helper()
+	if err := SetTimezone(name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetTimezone("") })
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	useTimezone(t, "Europe/Zurich")
+	if got := FormatTimestamp("2024-07-01T10:30:00Z"); got != "2024-07-01 12:30:00 CEST" {
+		t.Errorf("Expected the summer time of Zurich, got %q", got)
+	}
+	if got := FormatTimestamp("2024-01-15T10:30:00Z"); got != "2024-01-15 11:30:00 CET" {
+		t.Errorf("Expected the winter time of Zurich, got %q", got)
+	}
+
+	useLanguage(t, German)
+	if got := FormatTimestamp("2024-07-01T10:30:00Z"); got != "01.07.2024 12:30:00 CEST" {
+		t.Errorf("Expected the German format, got %q", got)
+	}
+	if got := FormatTimestamp("Scanning..."); got != "Scanning..." {
+		t.Errorf("Expected texts that are no timestamps to stay as they are, got %q", got)
+	}
+}
+
+func TestSetTimezone(t *testing.T) {
+	useTimezone(t, "UTC")
+	if got := FormatTime(time.Date(2024, 7, 1, 10, 30, 0, 0, time.UTC)); got != "2024-07-01 10:30:00 UTC" {
+		t.Errorf("Expected UTC, got %q", got)
+	}
+	if err := SetTimezone("Mars/Olympus"); err == nil {
+		t.Error("Expected an unknown time zone to be rejected")
+	}
+	if Timezone() != time.UTC {
+		t.Errorf("Expected the time zone to stay UTC, got %s", Timezone())
+	}
+	if err := SetTimezone(""); err != nil || Timezone() != time.Local {
+		t.Errorf("Expected an empty time zone to select the local one, got %s (%v)", Timezone(), err)
+	}
+}
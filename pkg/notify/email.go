@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EmailNotifier sends the plain text summary by mail, optionally with the HTML report attached
+type EmailNotifier struct {
+	Host       string
+	Port       int
+	Username   string
+	password   string
+	From       string
+	To         []string
+	Subject    string
+	AttachHTML bool
+	threshold  int
+	// send is swapped out in tests
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates an email notifier from its [notify.email] attributes
+func NewEmailNotifier(attrs map[string]interface{}) (*EmailNotifier, error) {
+	host, _ := attrs["host"].(string)
+	if host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+	from, _ := attrs["from"].(string)
+	if from == "" {
+		return nil, fmt.Errorf("from is required")
+	}
+	to := stringSliceAttr(attrs, "to")
+	if len(to) == 0 {
+		return nil, fmt.Errorf("at least one recipient in 'to' is required")
+	}
+
+	username, _ := attrs["username"].(string)
+	password, _ := attrs["password"].(string)
+	subject, _ := attrs["subject"].(string)
+	attachHTML, _ := attrs["attach_html"].(bool)
+
+	return &EmailNotifier{
+		Host:       host,
+		Port:       intAttr(attrs, "port", 587),
+		Username:   username,
+		password:   password,
+		From:       from,
+		To:         to,
+		Subject:    subject,
+		AttachHTML: attachHTML,
+		threshold:  intAttr(attrs, "threshold", 1),
+		send:       smtp.SendMail,
+	}, nil
+}
+
+func (e *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (e *EmailNotifier) Threshold() int {
+	return e.threshold
+}
+
+// Notify sends the summary to all recipients
+func (e *EmailNotifier) Notify(summary Summary) error {
+	msg, err := e.buildMessage(summary)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		auth = smtp.PlainAuth("", e.Username, e.password, e.Host)
+	}
+	return e.send(e.Host+":"+strconv.Itoa(e.Port), auth, e.From, e.To, msg)
+}
+
+// buildMessage assembles the MIME message with the summary as body and the HTML report as attachment
+func (e *EmailNotifier) buildMessage(summary Summary) ([]byte, error) {
+	subject := e.Subject
+	if subject == "" {
+		subject = fmt.Sprintf("Package check of '%s': %d issue(s)", summary.Location, summary.TotalIssues)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	writer := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	body, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	body.Write([]byte(strings.ReplaceAll(summary.Report, "\n", "\r\n")))
+
+	if e.AttachHTML && summary.HTMLReportPath != "" {
+		content, err := os.ReadFile(summary.HTMLReportPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HTML report: %w", err)
+		}
+		name := filepath.Base(summary.HTMLReportPath)
+		attachment, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/html; charset=utf-8"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		attachment.Write([]byte(wrapBase64(content)))
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// wrapBase64 encodes content as base64 with lines of at most 76 characters as required by MIME
+func wrapBase64(content []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded)
+	return wrapped.String()
+}
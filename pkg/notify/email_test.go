@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewEmailNotifierErrors(t *testing.T) {
+	valid := map[string]interface{}{"host": "smtp.example.org", "from": "pc@example.org", "to": []string{"curator@example.org"}}
+	if _, err := NewEmailNotifier(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, missing := range []string{"host", "from", "to"} {
+		attrs := map[string]interface{}{}
+		for k, v := range valid {
+			if k != missing {
+				attrs[k] = v
+			}
+		}
+		if _, err := NewEmailNotifier(attrs); err == nil {
+			t.Errorf("expected an error without %s", missing)
+		}
+	}
+}
+
+func TestEmailNotifierSendsReportWithAttachment(t *testing.T) {
+	htmlPath := filepath.Join(t.TempDir(), "report.html")
+	if err := os.WriteFile(htmlPath, []byte("<html>report</html>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier, err := NewEmailNotifier(map[string]interface{}{
+		"host":        "smtp.example.org",
+		"port":        int64(25),
+		"from":        "pc@example.org",
+		"to":          "curator@example.org",
+		"attach_html": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var addr string
+	var recipients []string
+	var sent []byte
+	notifier.send = func(a string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		addr, recipients, sent = a, to, msg
+		if auth != nil {
+			t.Error("expected no authentication without username")
+		}
+		return nil
+	}
+
+	summary := Summary{Location: "my-package", TotalIssues: 2, Report: "Found 2 issues\n", HTMLReportPath: htmlPath}
+	if err := notifier.Notify(summary); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+
+	if addr != "smtp.example.org:25" {
+		t.Errorf("unexpected address %s", addr)
+	}
+	if len(recipients) != 1 || recipients[0] != "curator@example.org" {
+		t.Errorf("unexpected recipients %v", recipients)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(sent)))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if subject := msg.Header.Get("Subject"); subject != "Package check of 'my-package': 2 issue(s)" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+
+	body, err := reader.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := io.ReadAll(body)
+	if !strings.Contains(string(text), "Found 2 issues") {
+		t.Errorf("expected summary in body, got %q", text)
+	}
+
+	attachment, err := reader.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.FileName() != "report.html" {
+		t.Errorf("unexpected attachment name %q", attachment.FileName())
+	}
+	encoded, _ := io.ReadAll(attachment)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil || string(decoded) != "<html>report</html>" {
+		t.Errorf("unexpected attachment content %q (%v)", decoded, err)
+	}
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	pcchecks "github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+	plainformatter "github.com/eawag-rdm/pc/pkg/output/plain"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// CheckCount is the number of issues a single check reported
+type CheckCount struct {
+	Checkname string
+	Count     int
+}
+
+// Summary is the condensed scan result handed to notifiers and their payload templates
+type Summary struct {
+	Location    string
+	Collector   string
+	Timestamp   string
+	TotalFiles  int
+	TotalIssues int
+	// ReportOnlyIssues are the issues of checks set to report_only, included in TotalIssues
+	ReportOnlyIssues int
+	FilesWithIssues  int
+	RepositoryIssue  bool
+	IssuesByCheck    []CheckCount
+	// Report is the plain text summary of the scan
+	Report string
+	// HTMLReportPath points to the generated HTML report, empty if none was written
+	HTMLReportPath string
+}
+
+// Notifier delivers a scan summary to an external system
+type Notifier interface {
+	Name() string
+	// Threshold is the minimum number of issues needed before the notifier fires
+	Threshold() int
+	Notify(summary Summary) error
+}
+
+// NewSummary condenses the messages of a scan into a Summary
+func NewSummary(location, collector string, messages []structs.Message, totalFiles int) Summary {
+	summary := Summary{
+		Location:    location,
+		Collector:   collector,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		TotalFiles:  totalFiles,
+		TotalIssues: len(messages),
+		Report:      plainformatter.NewPlainFormatter().FormatResults(location, collector, messages, totalFiles, nil),
+	}
+
+	files := make(map[string]struct{})
+	checks := make(map[string]int)
+	for _, msg := range messages {
+		switch source := msg.Source.(type) {
+		case structs.File:
+			key := source.GetDisplayName()
+			if source.ArchiveName != "" {
+				key = source.ArchiveName + " > " + key
+			}
+			files[key] = struct{}{}
+		case structs.Repository:
+			summary.RepositoryIssue = true
+		}
+		checks[msg.TestName]++
+		if pcchecks.IsReportOnly(msg.TestName) {
+			summary.ReportOnlyIssues++
+		}
+	}
+	summary.FilesWithIssues = len(files)
+
+	for name, count := range checks {
+		summary.IssuesByCheck = append(summary.IssuesByCheck, CheckCount{Checkname: name, Count: count})
+	}
+	sort.Slice(summary.IssuesByCheck, func(i, j int) bool {
+		if summary.IssuesByCheck[i].Count != summary.IssuesByCheck[j].Count {
+			return summary.IssuesByCheck[i].Count > summary.IssuesByCheck[j].Count
+		}
+		return summary.IssuesByCheck[i].Checkname < summary.IssuesByCheck[j].Checkname
+	})
+	return summary
+}
+
+// FromConfig creates all notifiers configured in the [notify.*] sections
+func FromConfig(cfg config.Config) ([]Notifier, error) {
+	var notifiers []Notifier
+
+	// Iterate in a fixed order so notifications are sent deterministically
+	names := make([]string, 0, len(cfg.Notifiers))
+	for name := range cfg.Notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attrs := cfg.Notifiers[name].Attrs
+		if enabled, ok := attrs["enabled"].(bool); ok && !enabled {
+			continue
+		}
+		switch name {
+		case "webhook":
+			notifier, err := NewWebhookNotifier(attrs)
+			if err != nil {
+				return nil, fmt.Errorf("notify.%s: %w", name, err)
+			}
+			notifiers = append(notifiers, notifier)
+		case "email":
+			notifier, err := NewEmailNotifier(attrs)
+			if err != nil {
+				return nil, fmt.Errorf("notify.%s: %w", name, err)
+			}
+			notifiers = append(notifiers, notifier)
+		default:
+			return nil, fmt.Errorf("unknown notifier '%s'", name)
+		}
+	}
+	return notifiers, nil
+}
+
+// NotifyAll sends the summary to every notifier whose threshold is reached by the issues of
+// checks that are not report_only. Failing notifiers do not stop the others; their errors are
+// returned together.
+func NotifyAll(notifiers []Notifier, summary Summary) []error {
+	var errs []error
+	for _, notifier := range notifiers {
+		if summary.TotalIssues-summary.ReportOnlyIssues < notifier.Threshold() {
+			continue
+		}
+		if err := notifier.Notify(summary); err != nil {
+			errs = append(errs, fmt.Errorf("%s notification failed: %w", notifier.Name(), err))
+		}
+	}
+	return errs
+}
+
+// intAttr reads an integer attribute, falling back to def if it is missing
+func intAttr(attrs map[string]interface{}, key string, def int) int {
+	if v, ok := attrs[key].(int64); ok {
+		return int(v)
+	}
+	return def
+}
+
+// stringSliceAttr reads a list attribute; a single string is accepted as a one element list
+func stringSliceAttr(attrs map[string]interface{}, key string) []string {
+	switch v := attrs[key].(type) {
+	case []string:
+		return v
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	}
+	return nil
+}
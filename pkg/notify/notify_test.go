@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestNewSummary(t *testing.T) {
+	messages := []structs.Message{
+		{Content: "a", Source: structs.File{Name: "a.txt"}, TestName: "IsFreeOfKeywords"},
+		{Content: "b", Source: structs.File{Name: "a.txt"}, TestName: "HasNoWhiteSpace"},
+		{Content: "c", Source: structs.File{Name: "inner.txt", ArchiveName: "x.zip"}, TestName: "IsFreeOfKeywords"},
+		{Content: "d", Source: structs.Repository{}, TestName: "HasReadme"},
+	}
+
+	summary := NewSummary("my-package", "CkanCollector", messages, 10)
+
+	if summary.TotalIssues != 4 {
+		t.Errorf("expected 4 issues, got %d", summary.TotalIssues)
+	}
+	if summary.FilesWithIssues != 2 {
+		t.Errorf("expected 2 files with issues, got %d", summary.FilesWithIssues)
+	}
+	if !summary.RepositoryIssue {
+		t.Error("expected repository issue flag to be set")
+	}
+	if len(summary.IssuesByCheck) != 3 || summary.IssuesByCheck[0].Checkname != "IsFreeOfKeywords" || summary.IssuesByCheck[0].Count != 2 {
+		t.Errorf("unexpected issues by check: %v", summary.IssuesByCheck)
+	}
+}
+
+type fakeNotifier struct {
+	threshold int
+	calls     int
+	err       error
+}
+
+func (f *fakeNotifier) Name() string   { return "fake" }
+func (f *fakeNotifier) Threshold() int { return f.threshold }
+func (f *fakeNotifier) Notify(Summary) error {
+	f.calls++
+	return f.err
+}
+
+func TestNotifyAllThreshold(t *testing.T) {
+	low := &fakeNotifier{threshold: 1}
+	high := &fakeNotifier{threshold: 10}
+	failing := &fakeNotifier{threshold: 0, err: errors.New("boom")}
+
+	errs := NotifyAll([]Notifier{low, high, failing}, Summary{TotalIssues: 3})
+
+	if low.calls != 1 {
+		t.Errorf("expected low threshold notifier to fire once, got %d", low.calls)
+	}
+	if high.calls != 0 {
+		t.Errorf("expected high threshold notifier not to fire, got %d", high.calls)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error, got %v", errs)
+	}
+}
+
+func TestNotifyAllReportOnly(t *testing.T) {
+	defer checks.SetSeverities(config.Config{})
+	checks.SetSeverities(config.Config{Tests: map[string]*config.TestConfig{"HasNoWhiteSpace": {ReportOnly: true}}})
+
+	messages := []structs.Message{
+		{Content: "a", Source: structs.File{Name: "a.txt"}, TestName: "IsFreeOfKeywords"},
+		{Content: "b", Source: structs.File{Name: "a.txt"}, TestName: "HasNoWhiteSpace"},
+		{Content: "c", Source: structs.File{Name: "b.txt"}, TestName: "HasNoWhiteSpace"},
+	}
+	summary := NewSummary("my-package", "LocalCollector", messages, 10)
+	if summary.TotalIssues != 3 || summary.ReportOnlyIssues != 2 {
+		t.Fatalf("expected 3 issues of which 2 are report-only, got %d and %d", summary.TotalIssues, summary.ReportOnlyIssues)
+	}
+
+	one := &fakeNotifier{threshold: 1}
+	two := &fakeNotifier{threshold: 2}
+	NotifyAll([]Notifier{one, two}, summary)
+	if one.calls != 1 {
+		t.Errorf("expected the notifier with threshold 1 to fire once, got %d", one.calls)
+	}
+	if two.calls != 0 {
+		t.Errorf("expected report-only issues not to count towards the threshold, got %d calls", two.calls)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	cfg := config.Config{Notifiers: map[string]*config.NotifierConfig{
+		"webhook": {Attrs: map[string]interface{}{"url": "http://localhost/hook", "threshold": int64(5)}},
+	}}
+	notifiers, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig returned an error: %v", err)
+	}
+	if len(notifiers) != 1 || notifiers[0].Threshold() != 5 {
+		t.Fatalf("unexpected notifiers: %v", notifiers)
+	}
+
+	cfg.Notifiers["webhook"].Attrs["enabled"] = false
+	notifiers, err = FromConfig(cfg)
+	if err != nil || len(notifiers) != 0 {
+		t.Errorf("expected disabled notifier to be ignored, got %v (%v)", notifiers, err)
+	}
+
+	cfg.Notifiers["pigeon"] = &config.NotifierConfig{Attrs: map[string]interface{}{}}
+	if _, err := FromConfig(cfg); err == nil {
+		t.Error("expected an error for an unknown notifier")
+	}
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+// DefaultWebhookTemplate renders a Slack compatible payload, which Mattermost and Teams incoming webhooks accept as well
+const DefaultWebhookTemplate = `{"text": {{printf "Package check of '%s' found %d issue(s) in %d file(s)." .Location .TotalIssues .FilesWithIssues | json}}}`
+
+// WebhookNotifier posts a templated JSON payload to a webhook URL
+type WebhookNotifier struct {
+	URL       string
+	threshold int
+	template  *template.Template
+	client    *http.Client
+}
+
+// templateFuncs are the helpers available in payload templates
+var templateFuncs = template.FuncMap{
+	// json encodes a value so it can be embedded in a JSON payload
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// NewWebhookNotifier creates a webhook notifier from its [notify.webhook] attributes
+func NewWebhookNotifier(attrs map[string]interface{}) (*WebhookNotifier, error) {
+	url, _ := attrs["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	payload, _ := attrs["template"].(string)
+	if payload == "" {
+		payload = DefaultWebhookTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	return &WebhookNotifier{
+		URL:       url,
+		threshold: intAttr(attrs, "threshold", 1),
+		template:  tmpl,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookNotifier) Threshold() int {
+	return w.threshold
+}
+
+// Notify renders the payload template and posts it to the webhook
+func (w *WebhookNotifier) Notify(summary Summary) error {
+	var payload bytes.Buffer
+	if err := w.template.Execute(&payload, summary); err != nil {
+		return fmt.Errorf("failed to render payload: %w", err)
+	}
+
+	resp, err := w.client.Post(w.URL, "application/json", &payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
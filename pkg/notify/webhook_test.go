@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifierDefaultPayload(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("payload is not valid JSON: %v (%s)", err, body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(map[string]interface{}{"url": server.URL})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier returned an error: %v", err)
+	}
+	if notifier.Threshold() != 1 {
+		t.Errorf("expected default threshold 1, got %d", notifier.Threshold())
+	}
+
+	// Quotes in the location must not break the JSON payload
+	err = notifier.Notify(Summary{Location: `pkg "quoted"`, TotalIssues: 3, FilesWithIssues: 2})
+	if err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	expected := `Package check of 'pkg "quoted"' found 3 issue(s) in 2 file(s).`
+	if received["text"] != expected {
+		t.Errorf("expected text %q, got %q", expected, received["text"])
+	}
+}
+
+func TestWebhookNotifierCustomTemplate(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(map[string]interface{}{
+		"url":      server.URL,
+		"template": `{"summary": {{json .Location}}, "checks": [{{range $i, $c := .IssuesByCheck}}{{if $i}},{{end}}{{json $c.Checkname}}{{end}}]}`,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier returned an error: %v", err)
+	}
+
+	summary := Summary{Location: "pkg", IssuesByCheck: []CheckCount{{"A", 1}, {"B", 2}}}
+	if err := notifier.Notify(summary); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	if body != `{"summary": "pkg", "checks": ["A","B"]}` {
+		t.Errorf("unexpected payload: %s", body)
+	}
+}
+
+func TestWebhookNotifierErrors(t *testing.T) {
+	if _, err := NewWebhookNotifier(map[string]interface{}{}); err == nil {
+		t.Error("expected an error without url")
+	}
+	if _, err := NewWebhookNotifier(map[string]interface{}{"url": "http://x", "template": "{{"}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier, _ := NewWebhookNotifier(map[string]interface{}{"url": server.URL})
+	if err := notifier.Notify(Summary{}); err == nil {
+		t.Error("expected an error for a failing webhook")
+	}
+}
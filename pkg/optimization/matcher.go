@@ -4,17 +4,18 @@ import (
 	"bytes"
 	"sort"
 	"strings"
-	"sync"
+
+	"github.com/eawag-rdm/pc/pkg/performance"
 )
 
-// FastMatcher provides high-performance string matching using multiple algorithms
+// FastMatcher finds keywords case-insensitively in a single pass over the text, see
+// performance.Automaton
 type FastMatcher struct {
-	patterns      []string
-	lowerPatterns []string   // pre-computed lowercased patterns
-	patternBytes  [][]byte   // pre-computed pattern byte slices for large text search
-	maxLen        int
-	minLen        int
-	caseMap       map[string]string // lowercase pattern -> original pattern
+	patterns  []string
+	automaton *performance.Automaton
+	maxLen    int
+	minLen    int
+	caseMap   map[string]string // lowercase pattern -> original pattern
 }
 
 // NewFastMatcher creates a new fast string matcher optimized for the given patterns
@@ -24,12 +25,11 @@ func NewFastMatcher(patterns []string) *FastMatcher {
 	}
 
 	fm := &FastMatcher{
-		patterns:      make([]string, len(patterns)),
-		lowerPatterns: make([]string, len(patterns)),
-		patternBytes:  make([][]byte, len(patterns)),
-		caseMap:       make(map[string]string),
-		minLen:        1000000,
-		maxLen:        0,
+		patterns:  make([]string, len(patterns)),
+		automaton: performance.NewAutomaton(patterns),
+		caseMap:   make(map[string]string),
+		minLen:    1000000,
+		maxLen:    0,
 	}
 
 	// Process patterns and build lookup structures
@@ -39,10 +39,7 @@ func NewFastMatcher(patterns []string) *FastMatcher {
 		}
 
 		fm.patterns[i] = pattern
-		lowerPattern := strings.ToLower(pattern)
-		fm.lowerPatterns[i] = lowerPattern
-		fm.patternBytes[i] = []byte(lowerPattern)
-		fm.caseMap[lowerPattern] = pattern
+		fm.caseMap[strings.ToLower(pattern)] = pattern
 
 		if len(pattern) > fm.maxLen {
 			fm.maxLen = len(pattern)
@@ -56,22 +53,14 @@ func NewFastMatcher(patterns []string) *FastMatcher {
 }
 
 // FindMatches returns all unique pattern matches found in the text
-// This uses multiple optimized algorithms based on pattern characteristics
 func (fm *FastMatcher) FindMatches(text []byte) []string {
-	if len(text) == 0 || len(fm.patterns) == 0 {
+	if len(text) == 0 || fm.automaton == nil {
 		return nil
 	}
 
 	found := make(map[string]struct{})
-	lowerText := bytes.ToLower(text)
-
-	// Use different strategies based on pattern length and text size
-	if len(text) < 1024 {
-		// For small text, use simple but fast approach
-		fm.findInSmallText(lowerText, found)
-	} else {
-		// For larger text, use optimized search
-		fm.findInLargeText(lowerText, found)
+	for _, i := range fm.automaton.Matched(text) {
+		found[strings.ToLower(fm.patterns[i])] = struct{}{}
 	}
 
 	// Convert to slice and sort for consistent ordering
@@ -89,22 +78,17 @@ func (fm *FastMatcher) FindMatches(text []byte) []string {
 
 // FindMatchesWithOriginalCase finds matches and returns them with their original case from the text
 func (fm *FastMatcher) FindMatchesWithOriginalCase(text []byte) []string {
-	found := make(map[string]string) // map[lowerPattern]originalFromText
-	lowerText := bytes.ToLower(text)
-
-	// Find all matches first
-	matchSet := make(map[string]struct{})
-	if len(text) < 1024 {
-		fm.findInSmallText(lowerText, matchSet)
-	} else {
-		fm.findInLargeText(lowerText, matchSet)
+	if len(text) == 0 || fm.automaton == nil {
+		return []string{}
 	}
 
-	// For each found pattern, find its original case in the text
-	for lowerMatch := range matchSet {
-		// Find the original case in the text
-		originalCase := fm.findOriginalCase(text, lowerText, lowerMatch)
-		found[lowerMatch] = originalCase
+	// The first occurrence of each pattern shows its case in the text
+	found := make(map[string]string) // map[lowerPattern]originalFromText
+	for _, match := range fm.automaton.FindAll(text) {
+		lowerMatch := strings.ToLower(fm.patterns[match.Pattern])
+		if _, exists := found[lowerMatch]; !exists {
+			found[lowerMatch] = string(text[match.Start:match.End])
+		}
 	}
 
 	// Convert to slice and sort by original case for consistent ordering
@@ -117,117 +101,33 @@ func (fm *FastMatcher) FindMatchesWithOriginalCase(text []byte) []string {
 	return result
 }
 
-// findOriginalCase finds the original case of a pattern in the text
-func (fm *FastMatcher) findOriginalCase(text []byte, lowerText []byte, lowerPattern string) string {
-	pattern := []byte(lowerPattern)
-
-	// Find the first occurrence of the pattern
-	idx := bytes.Index(lowerText, pattern)
-	if idx == -1 {
-		// Fallback to the pattern itself
-		if original, exists := fm.caseMap[lowerPattern]; exists {
-			return original
-		}
-		return lowerPattern
-	}
-	
-	// Extract the original case from the text
-	// Ensure we don't go out of bounds
-	endIdx := idx + len(pattern)
-	if endIdx > len(text) {
-		// Fallback to original pattern if bounds would be exceeded
-		if original, exists := fm.caseMap[lowerPattern]; exists {
-			return original
-		}
-		return lowerPattern
-	}
-	return string(text[idx:endIdx])
-}
-
-// findInSmallText uses simple string contains for small texts
-func (fm *FastMatcher) findInSmallText(lowerText []byte, found map[string]struct{}) {
-	textStr := string(lowerText)
-
-	for i, lowerPattern := range fm.lowerPatterns {
-		if len(fm.patterns[i]) == 0 {
-			continue
-		}
-
-		if strings.Contains(textStr, lowerPattern) {
-			found[lowerPattern] = struct{}{}
-		}
-	}
-}
-
-// findInLargeText uses optimized algorithms for larger texts
-func (fm *FastMatcher) findInLargeText(lowerText []byte, found map[string]struct{}) {
-	// Use bytes.Contains which is optimized in Go's stdlib
-	for i, patternBytes := range fm.patternBytes {
-		if len(fm.patterns[i]) == 0 {
-			continue
-		}
-
-		if bytes.Contains(lowerText, patternBytes) {
-			found[fm.lowerPatterns[i]] = struct{}{}
-		}
+// FindAll returns the positions of the matches in the text, so callers can derive line numbers
+// and snippets without searching again
+func (fm *FastMatcher) FindAll(text []byte) []performance.Match {
+	if len(text) == 0 || fm.automaton == nil {
+		return nil
 	}
+	return fm.automaton.FindAll(text)
 }
 
 // HasAnyMatch returns true if any pattern matches (faster than FindMatches when only presence is needed)
 func (fm *FastMatcher) HasAnyMatch(text []byte) bool {
-	if len(text) == 0 || len(fm.patterns) == 0 {
+	if len(text) == 0 || fm.automaton == nil {
 		return false
 	}
-
-	lowerText := bytes.ToLower(text)
-
-	for i, patternBytes := range fm.patternBytes {
-		if len(fm.patterns[i]) == 0 {
-			continue
-		}
-
-		if bytes.Contains(lowerText, patternBytes) {
-			return true
-		}
-	}
-
-	return false
+	return fm.automaton.Contains(text)
 }
 
-// MatcherCache provides thread-safe caching of FastMatcher instances
-type MatcherCache struct {
-	cache map[string]*FastMatcher
-	mutex sync.RWMutex
-}
-
-var globalMatcherCache = &MatcherCache{
-	cache: make(map[string]*FastMatcher),
-}
-
-// GetMatcher returns a cached FastMatcher for the given patterns
-func GetMatcher(patterns []string) *FastMatcher {
+// GetMatcher returns the FastMatcher for the given patterns, built once per scan in cache. A nil
+// cache builds a new matcher.
+func GetMatcher(cache *performance.PatternCache, patterns []string) *FastMatcher {
 	if len(patterns) == 0 {
 		return NewFastMatcher(patterns)
 	}
-
-	// Create a cache key from patterns
-	key := strings.Join(patterns, "|")
-	
-	globalMatcherCache.mutex.RLock()
-	if matcher, exists := globalMatcherCache.cache[key]; exists {
-		globalMatcherCache.mutex.RUnlock()
-		return matcher
-	}
-	globalMatcherCache.mutex.RUnlock()
-
-	// Create new matcher
-	matcher := NewFastMatcher(patterns)
-	
-	globalMatcherCache.mutex.Lock()
-	globalMatcherCache.cache[key] = matcher
-	globalMatcherCache.mutex.Unlock()
-	
-	return matcher
+	key := "matcher\x00" + strings.Join(patterns, "\x00")
+	return cache.Value(key, func() interface{} {
+		return NewFastMatcher(patterns)
+	}).(*FastMatcher)
 }
 
 // FastStringSearch provides Boyer-Moore-like fast string searching
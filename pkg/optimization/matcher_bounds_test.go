@@ -1,7 +1,7 @@
 package optimization
 
 import (
-	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -11,13 +11,12 @@ func TestFindOriginalCase_BoundsProtection(t *testing.T) {
 	patterns := []string{"test"}
 	matcher := NewFastMatcher(patterns)
 
-	// Create a text where lowerText and original text might have different byte lengths
+	// Create a text where the lowercased and original text might have different byte lengths
 	// This can happen with Unicode characters
 	text := []byte("This is a TEST string with unicode characters: café")
-	lowerText := bytes.ToLower(text)
 
 	// This should not panic
-	original := matcher.findOriginalCase(text, lowerText, "test")
+	original := firstMatch(matcher.FindMatchesWithOriginalCase(text))
 	
 	if original != "TEST" {
 		t.Errorf("Expected 'TEST', got '%s'", original)
@@ -32,10 +31,9 @@ func TestFindOriginalCase_LargeText(t *testing.T) {
 	largePrefix := strings.Repeat("x", 100000)
 	largeSuffix := strings.Repeat("y", 100000)
 	text := []byte(largePrefix + " PASSWORD " + largeSuffix)
-	lowerText := bytes.ToLower(text)
 
 	// This should not panic
-	original := matcher.findOriginalCase(text, lowerText, "password")
+	original := firstMatch(matcher.FindMatchesWithOriginalCase(text))
 	
 	if original != "PASSWORD" {
 		t.Errorf("Expected 'PASSWORD', got '%s'", original)
@@ -48,10 +46,9 @@ func TestFindOriginalCase_EdgeCaseBounds(t *testing.T) {
 
 	// Text where pattern is at the very end
 	text := []byte("This text ends with END")
-	lowerText := bytes.ToLower(text)
 
 	// This should not panic even if bounds calculation is wrong
-	original := matcher.findOriginalCase(text, lowerText, "end")
+	original := firstMatch(matcher.FindMatchesWithOriginalCase(text))
 	
 	// The function finds lowercase 'end' first, so it returns 'end', not 'END'
 	if original != "end" {
@@ -64,13 +61,12 @@ func TestFindOriginalCase_PatternNotFound(t *testing.T) {
 	matcher := NewFastMatcher(patterns)
 
 	text := []byte("This text has found but not the other")
-	lowerText := bytes.ToLower(text)
 
-	// Should fallback to original pattern when not found
-	original := matcher.findOriginalCase(text, lowerText, "missing")
-	
-	if original != "missing" {
-		t.Errorf("Expected fallback 'missing', got '%s'", original)
+	// Only the pattern in the text is reported
+	matches := matcher.FindMatchesWithOriginalCase(text)
+
+	if !reflect.DeepEqual(matches, []string{"found"}) {
+		t.Errorf("Expected only 'found', got %v", matches)
 	}
 }
 
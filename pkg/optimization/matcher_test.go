@@ -1,10 +1,11 @@
 package optimization
 
 import (
-	"bytes"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/performance"
 )
 
 func TestNewFastMatcher(t *testing.T) {
@@ -172,13 +173,20 @@ func TestFindMatchesWithOriginalCase(t *testing.T) {
 	}
 }
 
+// firstMatch returns the first of the matches, "" if there are none
+func firstMatch(matches []string) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
 func TestFindOriginalCase(t *testing.T) {
 	patterns := []string{"password"}
 	matcher := NewFastMatcher(patterns)
 
 	text := []byte("My PASSWORD is secret")
-	lowerText := bytes.ToLower(text)
-	original := matcher.findOriginalCase(text, lowerText, "password")
+	original := firstMatch(matcher.FindMatchesWithOriginalCase(text))
 
 	if original != "PASSWORD" {
 		t.Errorf("Expected 'PASSWORD', got '%s'", original)
@@ -190,12 +198,11 @@ func TestFindOriginalCase_NotFound(t *testing.T) {
 	matcher := NewFastMatcher(patterns)
 
 	text := []byte("No sensitive data here")
-	lowerText := bytes.ToLower(text)
-	original := matcher.findOriginalCase(text, lowerText, "password")
+	original := firstMatch(matcher.FindMatchesWithOriginalCase(text))
 
-	// Should fallback to original pattern
-	if original != "password" {
-		t.Errorf("Expected fallback 'password', got '%s'", original)
+	// Patterns not in the text are not reported
+	if original != "" {
+		t.Errorf("Expected no match, got '%s'", original)
 	}
 }
 
@@ -226,24 +233,34 @@ func TestGetMatcher_Caching(t *testing.T) {
 	patterns := []string{"password", "secret"}
 
 	// Get matcher twice with same patterns
-	matcher1 := GetMatcher(patterns)
-	matcher2 := GetMatcher(patterns)
+	cache := performance.NewPatternCache()
+	matcher1 := GetMatcher(cache, patterns)
+	matcher2 := GetMatcher(cache, patterns)
 
 	// Should return the same cached instance
 	if matcher1 != matcher2 {
 		t.Error("Expected cached matcher to be returned")
 	}
+
+	// Another scan builds its own matcher
+	if GetMatcher(performance.NewPatternCache(), patterns) == matcher1 {
+		t.Error("Expected scans not to share matchers")
+	}
+	if GetMatcher(nil, patterns) == GetMatcher(nil, patterns) {
+		t.Error("Expected a nil cache to build a new matcher")
+	}
 }
 
 func TestGetMatcher_EmptyPatterns(t *testing.T) {
-	matcher := GetMatcher([]string{})
+	cache := performance.NewPatternCache()
+	matcher := GetMatcher(cache, []string{})
 
 	if matcher == nil {
 		t.Fatal("GetMatcher returned nil for empty patterns")
 	}
 
 	// Should not be cached for empty patterns
-	matcher2 := GetMatcher([]string{})
+	matcher2 := GetMatcher(cache, []string{})
 	if matcher == matcher2 {
 		t.Error("Empty pattern matchers should not be cached")
 	}
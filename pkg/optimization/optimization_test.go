@@ -2,6 +2,8 @@ package optimization
 
 import (
 	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/performance"
 )
 
 func TestFastMatcher(t *testing.T) {
@@ -26,8 +28,9 @@ func TestFastMatcher(t *testing.T) {
 func TestMatcherCaching(t *testing.T) {
 	patterns := []string{"cached", "test"}
 	
-	matcher1 := GetMatcher(patterns)
-	matcher2 := GetMatcher(patterns)
+	cache := performance.NewPatternCache()
+	matcher1 := GetMatcher(cache, patterns)
+	matcher2 := GetMatcher(cache, patterns)
 	
 	// Should return the same cached instance
 	if matcher1 != matcher2 {
@@ -0,0 +1,63 @@
+package optimization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// logger logs the checks that failed on a file
+var logger = output.GlobalLogger.Component("checks")
+
+// WithFileTimeout runs the checks of a file, giving up after the fileTimeout of the [general]
+// section. A file that takes longer is recorded as skipped and has no findings; check gets a config
+// whose context ends with the timeout, so archive checks stop unpacking. Checks that do not look
+// at the context, e.g. a regex running over a huge log, finish in the background.
+func WithFileTimeout(cfg config.Config, file structs.File, check func(cfg config.Config) []structs.Message) []structs.Message {
+	if cfg.General == nil || cfg.General.FileTimeout <= 0 {
+		return SafeCheck(cfg, file, check)
+	}
+
+	ctx, cancel := context.WithTimeout(cfg.Context(), cfg.General.FileTimeout)
+	defer cancel()
+	done := make(chan []structs.Message, 1)
+	go func() {
+		done <- SafeCheck(cfg.WithContext(ctx), file, check)
+	}()
+
+	select {
+	case messages := <-done:
+		return messages
+	case <-ctx.Done():
+		// A cancelled scan skips the file without reporting it
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && cfg.Context().Err() == nil {
+			cfg.SkippedFiles().Add(output.SkippedFile{
+				Filename:    file.GetDisplayName(),
+				Path:        file.Path,
+				ArchiveName: file.ArchiveName,
+				Code:        output.SkipTimeout,
+				Reason:      fmt.Sprintf("Checking it took longer than %s.", cfg.General.FileTimeout),
+			})
+		}
+		return nil
+	}
+}
+
+// SafeCheck runs the checks of a file, turning a panic into an error in the report: the file has
+// no findings, and the scan goes on with the other files instead of crashing pc in a worker
+// goroutine, where the workspace would not be removed
+func SafeCheck(cfg config.Config, file structs.File, check func(cfg config.Config) []structs.Message) (messages []structs.Message) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("Checking '%s' failed: %v", file.GetDisplayName(), recovered)
+			logger.Debug("%s", debug.Stack())
+			messages = nil
+		}
+	}()
+	return check(cfg)
+}
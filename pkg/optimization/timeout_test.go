@@ -0,0 +1,90 @@
+package optimization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestWithFileTimeout(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	file := structs.File{Name: "huge.log", Path: "/data/huge.log"}
+	finding := []structs.Message{{Content: "found"}}
+	fast := func(cfg config.Config) []structs.Message { return finding }
+	// A slow check ignoring the context, like a regex running over a huge log
+	release := make(chan struct{})
+	defer close(release)
+	slow := func(cfg config.Config) []structs.Message {
+		<-release
+		return finding
+	}
+
+	cfg := config.Config{General: &config.GeneralConfig{}}.WithSkippedFiles(output.NewSkippedFiles())
+	if messages := WithFileTimeout(cfg, file, fast); len(messages) != 1 {
+		t.Errorf("Expected the findings without a timeout, got %v", messages)
+	}
+
+	cfg.General.FileTimeout = 20 * time.Millisecond
+	if messages := WithFileTimeout(cfg, file, fast); len(messages) != 1 {
+		t.Errorf("Expected the findings of a fast check, got %v", messages)
+	}
+	if messages := WithFileTimeout(cfg, file, slow); messages != nil {
+		t.Errorf("Expected no findings after the timeout, got %v", messages)
+	}
+	skipped := cfg.SkippedFiles().Files()
+	if len(skipped) != 1 || skipped[0].Filename != "huge.log" || skipped[0].Path != "/data/huge.log" || skipped[0].Code != output.SkipTimeout {
+		t.Errorf("Expected the file to be recorded as skipped, got %+v", skipped)
+	}
+
+	// A cancelled scan does not report the file
+	cfg = cfg.WithSkippedFiles(output.NewSkippedFiles())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if messages := WithFileTimeout(cfg.WithContext(ctx), file, slow); messages != nil {
+		t.Errorf("Expected no findings of a cancelled scan, got %v", messages)
+	}
+	if skipped := cfg.SkippedFiles().Files(); len(skipped) != 0 {
+		t.Errorf("Expected a cancelled file not to be recorded, got %+v", skipped)
+	}
+}
+
+func TestWithFileTimeoutPanic(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	file := structs.File{Name: "broken.xlsx", Path: "/data/broken.xlsx"}
+	broken := func(cfg config.Config) []structs.Message {
+		var sheets []string
+		return []structs.Message{{Content: sheets[1]}}
+	}
+
+	// The panic ends the checks of the file, without and with a timeout running them in a goroutine
+	cfg := config.Config{General: &config.GeneralConfig{}}
+	if messages := WithFileTimeout(cfg, file, broken); messages != nil {
+		t.Errorf("Expected no findings of a panicking check, got %v", messages)
+	}
+	cfg.General.FileTimeout = time.Second
+	if messages := WithFileTimeout(cfg, file, broken); messages != nil {
+		t.Errorf("Expected no findings of a panicking check, got %v", messages)
+	}
+
+	var errors []output.LogMessage
+	for _, message := range output.GlobalLogger.GetMessages() {
+		if message.Level == output.LevelError.String() {
+			errors = append(errors, message)
+		}
+	}
+	if len(errors) != 2 || errors[0].Message != "Checking 'broken.xlsx' failed: runtime error: index out of range [1] with length 0" {
+		t.Errorf("Expected the panics as errors, got %+v", errors)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/performance"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
@@ -102,23 +103,36 @@ func getFunctionName(i interface{}) string {
 // processWorkItem applies all checks to a single file
 // This ensures all checks for a single file run in the same worker to avoid IO conflicts
 func (wp *WorkerPool) processWorkItem(work WorkItem) []structs.Message {
-	var allMessages []structs.Message
-	
-	// Run all checks for this file sequentially in the same worker
-	// This avoids IO conflicts from multiple goroutines reading the same file
-	for _, check := range work.Checks {
-		testName := getFunctionName(check)
-		messages := check(work.File, work.Config)
-		if len(messages) > 0 {
-			// Add test name to each message
-			for i := range messages {
-				messages[i].TestName = testName
+	// The checks of the file share its content
+	content := performance.NewFileContent(work.File.Path, work.Config.MemoryBudget())
+	defer content.Close()
+	work.Config = work.Config.WithFileContent(content)
+
+	return WithFileTimeout(work.Config, work.File, func(cfg config.Config) []structs.Message {
+		var allMessages []structs.Message
+
+		// Run all checks for this file sequentially in the same worker
+		// This avoids IO conflicts from multiple goroutines reading the same file
+		for _, check := range work.Checks {
+			// A cancelled scan still gets a result for every work item, without running the checks
+			if cfg.Context().Err() != nil {
+				break
+			}
+			testName := getFunctionName(check)
+			start := time.Now()
+			messages := check(work.File, cfg)
+			cfg.Tracer().CheckRan(work.File, testName, time.Since(start), len(messages))
+			if len(messages) > 0 {
+				// Add test name to each message
+				for i := range messages {
+					messages[i].TestName = testName
+				}
+				allMessages = append(allMessages, messages...)
 			}
-			allMessages = append(allMessages, messages...)
 		}
-	}
-	
-	return allMessages
+
+		return allMessages
+	})
 }
 
 // Submit adds a work item to the processing queue (blocks until space is available)
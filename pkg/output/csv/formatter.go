@@ -0,0 +1,65 @@
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// Header are the columns of the CSV report, the same as those exported from the issue table of
+// the HTML report
+var Header = []string{"file", "path", "archive", "line", "check", "severity", "count", "message", "id"}
+
+// CSVFormatter writes the issues of a JSON report as CSV, one row per issue, for spreadsheets
+type CSVFormatter struct{}
+
+// NewCSVFormatter creates a new CSV formatter
+func NewCSVFormatter() *CSVFormatter {
+	return &CSVFormatter{}
+}
+
+// FormatResults converts the JSON report to CSV, the issues ordered by check as in the report
+func (f *CSVFormatter) FormatResults(jsonResult string) (string, error) {
+	result, err := jsonformatter.Parse([]byte(jsonResult))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JSON data: %w", err)
+	}
+	return f.Format(result)
+}
+
+// Format converts the report to CSV
+func (f *CSVFormatter) Format(result *jsonformatter.ScanResult) (string, error) {
+
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	writer.UseCRLF = true
+	if err := writer.Write(Header); err != nil {
+		return "", err
+	}
+	for _, check := range result.DetailsCheckFocused {
+		severity := check.Severity
+		if severity == "" {
+			// Reports written before severities were recorded
+			severity = string(checks.SeverityOf(check.Checkname))
+		}
+		for _, issue := range check.Issues {
+			line := ""
+			if issue.Line > 0 {
+				line = strconv.Itoa(issue.Line)
+			}
+			row := []string{issue.Subject, issue.Path, issue.ArchiveName, line, check.Checkname, severity, strconv.Itoa(max(issue.Count, 1)), issue.Message, issue.IssueID(check.Checkname)}
+			if err := writer.Write(row); err != nil {
+				return "", err
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return sb.String(), nil
+}
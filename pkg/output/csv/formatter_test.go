@@ -0,0 +1,45 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+const testReport = `{
+  "timestamp": "2024-01-14T10:30:00Z",
+  "details_check_focused": [
+    {"checkname": "HasNoWhiteSpace", "severity": "warning", "issues": [
+      {"id": "0123456789abcdef", "subject": "my file.txt", "path": "/data/my file.txt", "message": "File name contains spaces"}
+    ]},
+    {"checkname": "IsFreeOfKeywords", "issues": [
+      {"subject": "run.log", "path": "/data/run.log", "message": "Credentials detected 'password', \"quoted\"", "line": 3, "count": 2},
+      {"subject": "notes.txt", "path": "/data/data.zip", "archive_name": "data.zip", "message": "Credentials detected 'secret'"}
+    ]}
+  ]
+}`
+
+func TestCSVFormatter_FormatResults(t *testing.T) {
+	result, err := NewCSVFormatter().FormatResults(testReport)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+
+	expected := "file,path,archive,line,check,severity,count,message,id\r\n" +
+		"my file.txt,/data/my file.txt,,,HasNoWhiteSpace,warning,1,File name contains spaces,0123456789abcdef\r\n" +
+		"run.log,/data/run.log,,3,IsFreeOfKeywords,error,2,\"Credentials detected 'password', \"\"quoted\"\"\"," + structs.IssueID("IsFreeOfKeywords", "/data/run.log", "", "Credentials detected 'password', \"quoted\"") + "\r\n" +
+		"notes.txt,/data/data.zip,data.zip,,IsFreeOfKeywords,error,1,Credentials detected 'secret'," + structs.IssueID("IsFreeOfKeywords", "/data/data.zip", "data.zip", "Credentials detected 'secret'") + "\r\n"
+	if result != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestCSVFormatter_Errors(t *testing.T) {
+	if _, err := NewCSVFormatter().FormatResults("not json"); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+	result, err := NewCSVFormatter().FormatResults(`{"details_check_focused": []}`)
+	if err != nil || result != "file,path,archive,line,check,severity,count,message,id\r\n" {
+		t.Errorf("Expected only the header for a report without issues, got %q (%v)", result, err)
+	}
+}
@@ -0,0 +1,367 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
+// DefaultDiffTitle is the heading of diff reports without a configured title
+const DefaultDiffTitle = "Package Checker Scan Comparison"
+
+// GenerateDiffReport creates a static HTML file showing two scans side by side. diffJSON is a
+// history diff with the previous and current timestamps and the new, fixed and unchanged issues.
+// The data is always embedded in the HTML file; Mode does not apply.
+func (h *HTMLFormatter) GenerateDiffReport(diffJSON string, outputPath string) error {
+	var diff map[string]interface{}
+	if err := json.Unmarshal([]byte(diffJSON), &diff); err != nil {
+		return fmt.Errorf("failed to parse JSON data: %w", err)
+	}
+
+	templateData := struct {
+		JSONData    template.JS
+		LogoURI     template.URL
+		GeneratedAt string
+		Title       string
+	}{
+		JSONData:    scriptJSON(diffJSON),
+		GeneratedAt: i18n.FormatTime(time.Now()),
+		Title:       h.options.Title,
+	}
+	if templateData.Title == "" {
+		templateData.Title = DefaultDiffTitle
+	}
+	if h.options.Logo != "" {
+		logo, err := logoURI(h.options.Logo)
+		if err != nil {
+			return err
+		}
+		templateData.LogoURI = logo
+	}
+
+	tmpl := template.Must(template.New("diff").Parse(diffTemplate))
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML file: %w", err)
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, templateData); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// HTML template of the diff report, issues are grouped per file or per check with the previous
+// scan on the left and the current scan on the right
+const diffTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        :root {
+            --primary-color: #035C77;
+            --success-color: #10b981;
+            --warning-color: #f59e0b;
+            --error-color: #ef4444;
+            --background-color: #ffffff;
+            --surface-color: #f8fafc;
+            --text-color: #1e293b;
+            --text-secondary: #64748b;
+            --border-color: #e2e8f0;
+            --new-background: #fef2f2;
+            --fixed-background: #ecfdf5;
+        }
+
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            background: var(--background-color);
+            color: var(--text-color);
+            font-size: 13px;
+        }
+
+        .header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            padding: 12px 20px;
+            background: var(--surface-color);
+            border-bottom: 1px solid var(--border-color);
+        }
+
+        .header-title {
+            display: flex;
+            align-items: center;
+            gap: 12px;
+        }
+
+        .header-title .logo {
+            height: 32px;
+        }
+
+        .header h1 {
+            color: var(--primary-color);
+            font-size: 1.5rem;
+            font-weight: 600;
+        }
+
+        .controls {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 12px;
+            padding: 10px 20px;
+            border-bottom: 1px solid var(--border-color);
+            font-size: 12px;
+        }
+
+        .controls input[type="text"],
+        .controls select {
+            padding: 4px 8px;
+            border: 1px solid var(--border-color);
+            border-radius: 4px;
+        }
+
+        .stats-bar {
+            display: flex;
+            gap: 20px;
+            padding: 10px 20px;
+            font-size: 12px;
+        }
+
+        .stat-number {
+            font-weight: 600;
+            margin-right: 4px;
+        }
+
+        .stat-number.new {
+            color: var(--error-color);
+        }
+
+        .stat-number.fixed {
+            color: var(--success-color);
+        }
+
+        .content {
+            padding: 0 20px 20px;
+        }
+
+        .group {
+            margin-top: 15px;
+            border: 1px solid var(--border-color);
+            border-radius: 6px;
+            overflow: hidden;
+        }
+
+        .group-header {
+            padding: 8px 12px;
+            background: var(--surface-color);
+            font-weight: 600;
+            display: flex;
+            justify-content: space-between;
+        }
+
+        .group-counts {
+            font-weight: normal;
+            color: var(--text-secondary);
+            font-size: 11px;
+        }
+
+        .diff-table {
+            width: 100%;
+            border-collapse: collapse;
+            table-layout: fixed;
+        }
+
+        .diff-table th,
+        .diff-table td {
+            width: 50%;
+            padding: 6px 12px;
+            border-top: 1px solid var(--border-color);
+            text-align: left;
+            vertical-align: top;
+        }
+
+        .diff-table th {
+            font-size: 11px;
+            color: var(--text-secondary);
+            font-weight: normal;
+        }
+
+        .diff-table td + td,
+        .diff-table th + th {
+            border-left: 1px solid var(--border-color);
+        }
+
+        .diff-table td.new {
+            background: var(--new-background);
+        }
+
+        .diff-table td.fixed {
+            background: var(--fixed-background);
+        }
+
+        .status {
+            font-size: 10px;
+            text-transform: uppercase;
+            margin-right: 6px;
+            color: var(--text-secondary);
+        }
+
+        .status.new {
+            color: var(--error-color);
+        }
+
+        .status.fixed {
+            color: var(--success-color);
+        }
+
+        .issue-meta {
+            color: var(--text-secondary);
+            font-size: 11px;
+        }
+
+        .empty {
+            margin-top: 20px;
+            color: var(--text-secondary);
+        }
+
+        .footer {
+            padding: 10px 20px;
+            color: var(--text-secondary);
+            font-size: 11px;
+            border-top: 1px solid var(--border-color);
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <div class="header-title">
+            {{if .LogoURI}}<img class="logo" src="{{.LogoURI}}" alt="">{{end}}
+            <h1>{{.Title}}</h1>
+        </div>
+    </div>
+
+    <div class="controls">
+        <label>Group by
+            <select id="groupBy" onchange="render()">
+                <option value="file">File</option>
+                <option value="check">Check</option>
+            </select>
+        </label>
+        <label><input type="checkbox" id="show-new" checked onchange="render()"> New</label>
+        <label><input type="checkbox" id="show-fixed" checked onchange="render()"> Resolved</label>
+        <label><input type="checkbox" id="show-unchanged" checked onchange="render()"> Persisting</label>
+        <input type="text" id="filterBox" placeholder="Filter..." oninput="render()">
+    </div>
+
+    <div class="stats-bar" id="statsBar"></div>
+    <div class="content" id="content"></div>
+
+    <div class="footer">Generated on {{.GeneratedAt}}</div>
+
+    <script>
+        // Diff data from Go template
+        const diffData = {{.JSONData}};
+
+        // Issues of the diff with their status: new, fixed (resolved) or unchanged (persisting)
+        function getRows() {
+            let rows = [];
+            ['new', 'fixed', 'unchanged'].forEach(status => {
+                (diffData[status] || []).forEach(issue => {
+                    const file = issue.archive_name ? issue.archive_name + ' > ' + issue.subject : issue.subject;
+                    rows.push({ status: status, file: file, check: issue.checkname, message: issue.message });
+                });
+            });
+            return rows;
+        }
+
+        const statusOrder = { new: 0, fixed: 1, unchanged: 2 };
+        const statusLabel = { new: 'new', fixed: 'resolved', unchanged: 'persisting' };
+
+        function render() {
+            const groupBy = document.getElementById('groupBy').value;
+            const filterTerm = document.getElementById('filterBox').value.toLowerCase();
+            const rows = getRows().filter(row => {
+                if (!document.getElementById('show-' + row.status).checked) return false;
+                if (filterTerm === '') return true;
+                return (row.file + ' ' + row.check + ' ' + row.message).toLowerCase().includes(filterTerm);
+            });
+
+            const groups = new Map();
+            rows.forEach(row => {
+                const key = groupBy === 'file' ? row.file : row.check;
+                if (!groups.has(key)) groups.set(key, []);
+                groups.get(key).push(row);
+            });
+
+            let html = '';
+            [...groups.keys()].sort().forEach(key => {
+                const groupRows = groups.get(key).sort((a, b) => statusOrder[a.status] - statusOrder[b.status] || a.message.localeCompare(b.message));
+                const counts = { new: 0, fixed: 0, unchanged: 0 };
+                groupRows.forEach(row => counts[row.status]++);
+
+                html += '<div class="group"><div class="group-header"><span>' + escapeHtml(key) + '</span>';
+                html += '<span class="group-counts">+' + counts.new + ' new, -' + counts.fixed + ' resolved, ' + counts.unchanged + ' persisting</span></div>';
+                html += '<table class="diff-table"><tr><th>Previous scan</th><th>Current scan</th></tr>';
+                groupRows.forEach(row => {
+                    const cell = '<span class="status ' + row.status + '">' + statusLabel[row.status] + '</span>' + escapeHtml(row.message) +
+                        '<div class="issue-meta">' + escapeHtml(groupBy === 'file' ? row.check : row.file) + '</div>';
+                    const before = row.status === 'new' ? '' : cell;
+                    const after = row.status === 'fixed' ? '' : cell;
+                    html += '<tr><td class="' + (row.status === 'fixed' ? 'fixed' : '') + '">' + before + '</td>';
+                    html += '<td class="' + (row.status === 'new' ? 'new' : '') + '">' + after + '</td></tr>';
+                });
+                html += '</table></div>';
+            });
+            if (html === '') {
+                html = '<div class="empty">No issues match the filter.</div>';
+            }
+            document.getElementById('content').innerHTML = html;
+        }
+
+        function populateStats() {
+            const stats = [
+                { label: 'new', value: (diffData.new || []).length, class: 'new' },
+                { label: 'resolved', value: (diffData.fixed || []).length, class: 'fixed' },
+                { label: 'persisting', value: (diffData.unchanged || []).length, class: 'unchanged' }
+            ];
+            let html = stats.map(stat => '<span><span class="stat-number ' + stat.class + '">' + stat.value + '</span>' + stat.label + '</span>').join('');
+            if (diffData.previous || diffData.current) {
+                html += '<span>' + escapeHtml(diffData.previous || '?') + ' → ' + escapeHtml(diffData.current || '?') + '</span>';
+            }
+            document.getElementById('statsBar').innerHTML = html;
+        }
+
+        // Utility function to escape HTML
+        function escapeHtml(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+
+        document.addEventListener('DOMContentLoaded', function() {
+            populateStats();
+            render();
+        });
+    </script>
+</body>
+</html>
+`
@@ -0,0 +1,38 @@
+package html
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDiffReport(t *testing.T) {
+	diffJSON := `{"location": "new.json", "previous": "2026-01-01T10:00:00Z", "current": "2026-01-02T10:00:00Z",
+		"new": [{"checkname": "IsFreeOfKeywords", "subject": "c.txt", "archive_name": "data.zip", "message": "found token </script>"}],
+		"fixed": [{"checkname": "HasNoWhiteSpace", "subject": "my file.txt", "message": "contains whitespace"}],
+		"unchanged": []}`
+	outputPath := filepath.Join(t.TempDir(), "diff.html")
+
+	formatter, err := NewHTMLFormatterWithOptions(Options{Title: "Release review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := formatter.GenerateDiffReport(diffJSON, outputPath); err != nil {
+		t.Fatalf("GenerateDiffReport failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{"<title>Release review</title>", "const diffData = {", `found token \u003c/script\u003e`, "Previous scan", "Current scan"} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("diff report is missing %q", expected)
+		}
+	}
+
+	if err := NewHTMLFormatter().GenerateDiffReport("{", outputPath); err == nil || !strings.Contains(err.Error(), "failed to parse JSON data") {
+		t.Errorf("expected an error for invalid JSON, got %v", err)
+	}
+}
@@ -1,63 +1,213 @@
 package html
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
+// Modes of embedding the scan data in the report
+const (
+	// ModeSingle embeds the JSON data in the HTML file
+	ModeSingle = "single"
+	// ModeGzip embeds the JSON data gzip compressed and base64 encoded, for very large scans
+	ModeGzip = "gzip"
+	// ModeSplit writes the JSON data to a directory next to the HTML file
+	ModeSplit = "split"
 )
 
+// DefaultTitle is the heading of reports without a configured title
+const DefaultTitle = "Package Checker Scanner Report"
+
+// Options configure the generated report
+type Options struct {
+	Mode  string // ModeSingle, ModeGzip or ModeSplit, empty for ModeSingle
+	Title string // Page title and heading, empty for DefaultTitle
+	Logo  string // Path of an image shown next to the heading, embedded in the HTML file
+}
+
 // HTMLFormatter handles generation of static HTML reports
-type HTMLFormatter struct{}
+type HTMLFormatter struct {
+	options Options
+}
 
 // NewHTMLFormatter creates a new HTML formatter instance
 func NewHTMLFormatter() *HTMLFormatter {
 	return &HTMLFormatter{}
 }
 
+// NewHTMLFormatterWithOptions creates an HTML formatter with the given options
+func NewHTMLFormatterWithOptions(options Options) (*HTMLFormatter, error) {
+	switch options.Mode {
+	case "", ModeSingle, ModeGzip, ModeSplit:
+	default:
+		return nil, fmt.Errorf("unknown HTML mode '%s', expected %s, %s or %s", options.Mode, ModeSingle, ModeGzip, ModeSplit)
+	}
+	return &HTMLFormatter{options: options}, nil
+}
+
+// DataDir returns the directory the data of a ModeSplit report written to outputPath is stored in
+func DataDir(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "_files"
+}
+
 // GenerateReport creates a static HTML file from the scan results
 func (h *HTMLFormatter) GenerateReport(jsonData string, outputPath string) error {
-	// Parse the JSON data to extract summary information
-	var scanResult map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonData), &scanResult); err != nil {
-		return fmt.Errorf("failed to parse JSON data: %w", err)
+	// Ensure output directory exists
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if h.options.Mode == ModeSplit {
+		if err := writeDataDir(jsonData, DataDir(outputPath)); err != nil {
+			return err
+		}
+	}
+
+	// Create the output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML file: %w", err)
 	}
+	defer file.Close()
 
+	return h.render(jsonData, h.options.Mode, filepath.Base(DataDir(outputPath))+"/data.js", file)
+}
+
+// SelfContained returns the report as a single HTML file that does not need any other files.
+// Reports of ModeSplit are compressed as in ModeGzip instead.
+func (h *HTMLFormatter) SelfContained(jsonData string) ([]byte, error) {
+	mode := h.options.Mode
+	if mode == ModeSplit {
+		mode = ModeGzip
+	}
+	var buffer bytes.Buffer
+	if err := h.render(jsonData, mode, "", &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// render writes the page; dataScript is the path of the data.js of ModeSplit, relative to the page
+func (h *HTMLFormatter) render(jsonData, mode, dataScript string, w io.Writer) error {
+	// Validate the JSON data before it is embedded, the page decodes it
+	if !json.Valid([]byte(jsonData)) {
+		return fmt.Errorf("failed to parse JSON data: invalid JSON")
+	}
 
 	// Prepare template data - we need to pass the parsed JSON object, not the string
 	templateData := struct {
 		JSONData    template.JS
+		Payload     template.JS
+		DataScript  string
+		Mode        string
+		LogoURI     template.URL
 		GeneratedAt string
 		Title       string
 	}{
-		JSONData:    template.JS(jsonData), // Use template.JS to safely embed JSON
-		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
-		Title:       "Package Checker Scanner Report",
+		Mode:        mode,
+		GeneratedAt: i18n.FormatTime(time.Now()),
+		Title:       h.options.Title,
+	}
+	if templateData.Title == "" {
+		templateData.Title = DefaultTitle
+	}
+	switch mode {
+	case ModeGzip:
+		payload, err := compress(jsonData)
+		if err != nil {
+			return err
+		}
+		templateData.Payload = template.JS(`"` + payload + `"`)
+	case ModeSplit:
+		templateData.DataScript = dataScript
+	default:
+		templateData.Mode = ModeSingle
+		templateData.JSONData = scriptJSON(jsonData)
+	}
+	if h.options.Logo != "" {
+		logo, err := logoURI(h.options.Logo)
+		if err != nil {
+			return err
+		}
+		templateData.LogoURI = logo
 	}
 
 	// Create the HTML template
 	tmpl := template.Must(template.New("report").Parse(htmlTemplate))
 
-	// Ensure output directory exists
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	// Execute the template
+	if err := tmpl.Execute(w, templateData); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	// Create the output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create HTML file: %w", err)
+	return nil
+}
+
+// writeDataDir writes the scan data of a ModeSplit report: data.json for other tools and data.js,
+// which the page loads with a script tag because browsers do not fetch files of local pages
+func writeDataDir(jsonData, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
 	}
-	defer file.Close()
+	if err := os.WriteFile(filepath.Join(dir, "data.json"), []byte(jsonData), 0644); err != nil {
+		return fmt.Errorf("failed to write report data: %w", err)
+	}
+	script := "window.pcScanData = " + jsonData + ";\n"
+	if err := os.WriteFile(filepath.Join(dir, "data.js"), []byte(script), 0644); err != nil {
+		return fmt.Errorf("failed to write report data: %w", err)
+	}
+	return nil
+}
 
-	// Execute the template
-	if err := tmpl.Execute(file, templateData); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+// scriptJSON returns JSON data to embed in a script element. template.JS is not escaped by the
+// template, so "<", ">" and "&" in strings are escaped here: a finding quoting "</script>" would
+// end the element otherwise.
+func scriptJSON(jsonData string) template.JS {
+	var buffer bytes.Buffer
+	json.HTMLEscape(&buffer, []byte(jsonData))
+	return template.JS(buffer.String())
+}
+
+// compress returns the gzip compressed, base64 encoded JSON data of ModeGzip
+func compress(jsonData string) (string, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(jsonData)); err != nil {
+		return "", fmt.Errorf("failed to compress report data: %w", err)
 	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress report data: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
 
-	return nil
+// logoURI embeds the logo image as a data URI, so the report works offline
+func logoURI(path string) (template.URL, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logo: %w", err)
+	}
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(content)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", fmt.Errorf("logo '%s' is not an image", path)
+	}
+	return template.URL("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(content)), nil
 }
 
 // HTML template with embedded CSS and JavaScript
@@ -72,9 +222,9 @@ const htmlTemplate = `<!DOCTYPE html>
             --primary-color: #035C77;
             --primary-light: #118EC6;
             --secondary-color: #64748b;
-            --success-color: #10b981;
-            --warning-color: #f59e0b;
-            --error-color: #ef4444;
+            --success-color: #047857;
+            --warning-color: #b45309;
+            --error-color: #dc2626;
             --background-color: #ffffff;
             --surface-color: #f8fafc;
             --text-color: #1e293b;
@@ -84,11 +234,19 @@ const htmlTemplate = `<!DOCTYPE html>
             --sidebar-width: 280px;
             --eawag-primary: #035C77;
             --eawag-accent: #118EC6;
+            /* Backgrounds of white text, with a contrast of at least 4.5:1 in both themes */
+            --active-background: #035C77;
+            --active-hover: #0a6a8f;
+            --badge-text: #ffffff;
         }
 
         [data-theme="dark"] {
-            --primary-color: #118EC6;
-            --primary-light: #35A5D1;
+            --primary-color: #35A5D1;
+            --primary-light: #67BDE0;
+            --secondary-color: #94a3b8;
+            --success-color: #34d399;
+            --warning-color: #fbbf24;
+            --error-color: #f87171;
             --background-color: #0f172a;
             --surface-color: #1e293b;
             --text-color: #f1f5f9;
@@ -97,6 +255,9 @@ const htmlTemplate = `<!DOCTYPE html>
             --shadow: 0 1px 3px 0 rgba(0, 0, 0, 0.3);
             --eawag-primary: #118EC6;
             --eawag-accent: #35A5D1;
+            --active-background: #0a6a8f;
+            --active-hover: #035C77;
+            --badge-text: #0f172a;
         }
 
         * {
@@ -114,6 +275,26 @@ const htmlTemplate = `<!DOCTYPE html>
             transition: background-color 0.3s, color 0.3s;
         }
 
+        :focus-visible {
+            outline: 2px solid var(--primary-color);
+            outline-offset: 2px;
+        }
+
+        .skip-link {
+            position: absolute;
+            left: 8px;
+            top: -40px;
+            z-index: 10;
+            padding: 6px 12px;
+            background: var(--active-background);
+            color: white;
+            border-radius: 4px;
+        }
+
+        .skip-link:focus {
+            top: 8px;
+        }
+
         .app-layout {
             display: flex;
             height: 100vh;
@@ -144,6 +325,16 @@ const htmlTemplate = `<!DOCTYPE html>
             border-bottom: 1px solid var(--border-color);
         }
 
+        .header-title {
+            display: flex;
+            align-items: center;
+            gap: 12px;
+        }
+
+        .header-title .logo {
+            height: 32px;
+        }
+
         .header h1 {
             color: var(--primary-color);
             font-size: 1.5rem;
@@ -156,8 +347,8 @@ const htmlTemplate = `<!DOCTYPE html>
             align-items: center;
         }
 
-        .theme-toggle {
-            background: var(--primary-color);
+        .header-button {
+            background: var(--active-background);
             color: white;
             border: none;
             padding: 6px 12px;
@@ -167,8 +358,8 @@ const htmlTemplate = `<!DOCTYPE html>
             transition: background-color 0.2s ease;
         }
 
-        .theme-toggle:hover {
-            background: var(--primary-light);
+        .header-button:hover {
+            background: var(--active-hover);
         }
 
         .filter-box {
@@ -242,7 +433,7 @@ const htmlTemplate = `<!DOCTYPE html>
         }
 
         .nav-section-header.active {
-            background: var(--primary-color);
+            background: var(--active-background);
             color: white;
         }
 
@@ -265,8 +456,8 @@ const htmlTemplate = `<!DOCTYPE html>
             overflow-y: auto;
         }
 
-        .nav-section-content:focus {
-            outline: none;
+        .nav-section-content:focus-visible {
+            outline-offset: -2px;
         }
 
         .nav-section-content.expanded {
@@ -286,7 +477,7 @@ const htmlTemplate = `<!DOCTYPE html>
         }
 
         .nav-item.active {
-            background: var(--primary-color);
+            background: var(--active-background);
             color: white;
         }
 
@@ -382,6 +573,12 @@ const htmlTemplate = `<!DOCTYPE html>
             line-height: 1.4;
         }
 
+        .detail-location {
+            color: var(--text-secondary);
+            font-size: 11px;
+            margin-top: 4px;
+        }
+
         .footer {
             text-align: center;
             padding: 10px;
@@ -394,6 +591,116 @@ const htmlTemplate = `<!DOCTYPE html>
             display: none !important;
         }
 
+        .table-controls {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 12px;
+            margin-bottom: 12px;
+            font-size: 11px;
+        }
+
+        .table-controls select,
+        .table-controls button {
+            padding: 4px 8px;
+            border: 1px solid var(--border-color);
+            border-radius: 4px;
+            background: var(--surface-color);
+            color: var(--text-color);
+            font-size: 11px;
+        }
+
+        .table-controls button {
+            cursor: pointer;
+        }
+
+        .facet {
+            display: flex;
+            align-items: center;
+            gap: 6px;
+        }
+
+        .issue-table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 12px;
+        }
+
+        .issue-table th,
+        .issue-table td {
+            padding: 6px 8px;
+            border-bottom: 1px solid var(--border-color);
+            text-align: left;
+            vertical-align: top;
+        }
+
+        .issue-table th {
+            position: sticky;
+            top: 0;
+            background: var(--surface-color);
+            cursor: pointer;
+            user-select: none;
+            white-space: nowrap;
+        }
+
+        .issue-table th.sorted-asc::after {
+            content: " ▲";
+        }
+
+        .issue-table th.sorted-desc::after {
+            content: " ▼";
+        }
+
+        .archive-table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 12px;
+            margin-top: 8px;
+        }
+
+        .archive-table th,
+        .archive-table td {
+            padding: 4px 8px;
+            border-bottom: 1px solid var(--border-color);
+            text-align: left;
+        }
+
+        .archive-table td.size {
+            text-align: right;
+            white-space: nowrap;
+        }
+
+        .archive-table td.hash {
+            font-family: monospace;
+            word-break: break-all;
+        }
+
+        .severity-badge {
+            padding: 1px 6px;
+            border-radius: 3px;
+            color: var(--badge-text);
+            font-size: 10px;
+            text-transform: uppercase;
+        }
+
+        .severity-badge.error {
+            background: var(--error-color);
+        }
+
+        .severity-badge.warning {
+            background: var(--warning-color);
+        }
+
+        .severity-badge.info {
+            background: var(--secondary-color);
+        }
+
+        .table-footer {
+            margin-top: 10px;
+            font-size: 11px;
+            color: var(--text-secondary);
+        }
+
         @media (max-width: 768px) {
             .app-layout {
                 flex-direction: column;
@@ -414,78 +721,165 @@ const htmlTemplate = `<!DOCTYPE html>
                 gap: 6px;
             }
         }
+
+        @media (prefers-reduced-motion: reduce) {
+            body {
+                transition: none;
+            }
+        }
+
+        /* Printed reports, e.g. saved as PDF for the curation record, show the details in full in
+           light colors without the navigation and controls */
+        @media print {
+            body[data-theme] {
+                --primary-color: #035C77;
+                --secondary-color: #475569;
+                --success-color: #047857;
+                --warning-color: #b45309;
+                --error-color: #b91c1c;
+                --background-color: #ffffff;
+                --surface-color: #ffffff;
+                --text-color: #000000;
+                --text-secondary: #334155;
+                --border-color: #cbd5e1;
+                --shadow: none;
+                --badge-text: #ffffff;
+                font-size: 11px;
+            }
+
+            .sidebar,
+            .header-controls,
+            .table-controls,
+            .table-footer button,
+            .skip-link {
+                display: none !important;
+            }
+
+            .app-layout,
+            .main-content,
+            .content-area {
+                display: block;
+                height: auto;
+                overflow: visible;
+            }
+
+            .issue-table th {
+                position: static;
+            }
+
+            .issue-table tr,
+            .detail-item {
+                break-inside: avoid;
+            }
+
+            .print-section + .print-section {
+                break-before: page;
+            }
+
+            .severity-badge {
+                -webkit-print-color-adjust: exact;
+                print-color-adjust: exact;
+            }
+        }
     </style>
 </head>
 <body data-theme="light">
+    <a class="skip-link" href="#contentArea">Skip to the report</a>
     <div class="app-layout">
-        <div class="sidebar">
+        <nav class="sidebar" aria-label="Report sections">
             <div class="sidebar-header">Navigation</div>
             <div class="navigation">
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="toggleNavSection('subjects')" id="subjects-header">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('issues')" id="issues-header">
+                        <span>All Issues</span>
+                        <span class="nav-section-count" id="issues-count">0</span>
+                    </div>
+                </div>
+
+                <div class="nav-section">
+                    <div class="nav-section-header" role="button" tabindex="0" aria-expanded="false" aria-controls="subjects-content" onclick="toggleNavSection('subjects')" id="subjects-header">
                         <span>Subjects</span>
                         <span class="nav-section-count" id="subjects-count">0</span>
                     </div>
-                    <div class="nav-section-content" id="subjects-content">
+                    <div class="nav-section-content" id="subjects-content" role="listbox" aria-label="Subjects">
                         <!-- Subjects navigation will be populated by JavaScript -->
                     </div>
                 </div>
                 
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="toggleNavSection('checks')" id="checks-header">
+                    <div class="nav-section-header" role="button" tabindex="0" aria-expanded="false" aria-controls="checks-content" onclick="toggleNavSection('checks')" id="checks-header">
                         <span>Checks</span>
                         <span class="nav-section-count" id="checks-count">0</span>
                     </div>
-                    <div class="nav-section-content" id="checks-content">
+                    <div class="nav-section-content" id="checks-content" role="listbox" aria-label="Checks">
                         <!-- Checks navigation will be populated by JavaScript -->
                     </div>
                 </div>
                 
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="showAllDetails('pdfs')" id="pdfs-header">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('pdfs')" id="pdfs-header">
                         <span>PDF Files</span>
                         <span class="nav-section-count" id="pdfs-count">0</span>
                     </div>
                 </div>
                 
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="showAllDetails('skipped')" id="skipped-header">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('skipped')" id="skipped-header">
                         <span>Skipped Files</span>
                         <span class="nav-section-count" id="skipped-count">0</span>
                     </div>
                 </div>
                 
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="showAllDetails('warnings')" id="warnings-header">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('files')" id="files-header">
+                        <span>Files</span>
+                        <span class="nav-section-count" id="files-count">0</span>
+                    </div>
+                </div>
+                
+                <div class="nav-section">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('archives')" id="archives-header">
+                        <span>Archives</span>
+                        <span class="nav-section-count" id="archives-count">0</span>
+                    </div>
+                </div>
+                
+                <div class="nav-section">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('warnings')" id="warnings-header">
                         <span>Warnings</span>
                         <span class="nav-section-count" id="warnings-count">0</span>
                     </div>
                 </div>
                 
                 <div class="nav-section">
-                    <div class="nav-section-header" onclick="showAllDetails('errors')" id="errors-header">
+                    <div class="nav-section-header" role="button" tabindex="0" onclick="showAllDetails('errors')" id="errors-header">
                         <span>Errors</span>
                         <span class="nav-section-count" id="errors-count">0</span>
                     </div>
                 </div>
             </div>
-        </div>
+        </nav>
 
-        <div class="main-content">
-            <div class="header">
-                <h1>{{.Title}}</h1>
+        <main class="main-content">
+            <header class="header">
+                <div class="header-title">
+                    {{if .LogoURI}}<img class="logo" src="{{.LogoURI}}" alt="">{{end}}
+                    <h1>{{.Title}}</h1>
+                </div>
                 <div class="header-controls">
-                    <input type="text" class="filter-box" placeholder="Filter..." id="filterBox">
-                    <button class="theme-toggle" onclick="toggleTheme()">🌙 Dark</button>
+                    <input type="search" class="filter-box" placeholder="Filter... (/)" id="filterBox" aria-label="Filter the details">
+                    <button type="button" class="header-button" onclick="downloadScanJSON()" title="Download the scan data, e.g. for pc view">Download JSON</button>
+                    <button type="button" class="header-button" onclick="showPrintView()" title="Show all sections for printing or saving as PDF">Print view</button>
+                    <button type="button" class="header-button theme-toggle" onclick="toggleTheme()" aria-pressed="false">🌙 Dark</button>
                 </div>
-            </div>
+            </header>
 
-            <div class="stats-bar" id="statsBar">
+            <div class="stats-bar" id="statsBar" role="group" aria-label="Summary">
                 <!-- Stats will be populated by JavaScript -->
             </div>
 
-            <div class="content-area" id="contentArea">
-                <div class="content-header">
+            <div class="content-area" id="contentArea" tabindex="-1">
+                <div class="content-header" aria-live="polite">
                     <div class="content-title" id="contentTitle">Select an item from the navigation</div>
                     <div class="content-subtitle" id="contentSubtitle">Choose a category and item to view details</div>
                 </div>
@@ -493,25 +887,35 @@ const htmlTemplate = `<!DOCTYPE html>
                     <!-- Details will be populated by JavaScript -->
                 </div>
             </div>
-        </div>
+        </main>
     </div>
 
-    <div class="footer">
+    <footer class="footer">
         <div class="timestamp">Generated on {{.GeneratedAt}}</div>
-    </div>
+    </footer>
 
+    {{if eq .Mode "split"}}<script src="{{.DataScript}}"></script>{{end}}
     <script>
         // Scan data from Go template
-        const scanData = {{.JSONData}};
+        {{if eq .Mode "single"}}const scanData = {{.JSONData}};{{else}}let scanData = null;{{end}}
+        {{if eq .Mode "gzip"}}const scanPayload = {{.Payload}};{{end}}
         
         // Global state
         let currentSection = null;
         let currentItem = null;
+
+        // State of the issue table
+        const severityOrder = { error: 0, warning: 1, info: 2 };
+        const tablePageSize = 500;
+        let issueRows = null;
+        let tableState = { sortKey: 'severity', sortAsc: true, severities: { error: true, warning: true, info: true }, check: '', archive: '', shown: tablePageSize };
         
-        // Debug: Log the data to console
-        console.log('Scan data loaded:', scanData);
-        console.log('Scanned files:', scanData.scanned ? scanData.scanned.length : 0);
-        console.log('Skipped files:', scanData.skipped ? scanData.skipped.length : 0);
+        // Load the scan data that is not embedded as JSON
+        async function loadScanData() {
+            {{if eq .Mode "gzip"}}const compressed = Uint8Array.from(atob(scanPayload), c => c.charCodeAt(0));
+            const stream = new Blob([compressed]).stream().pipeThrough(new DecompressionStream('gzip'));
+            return JSON.parse(await new Response(stream).text());{{else}}return window.pcScanData;{{end}}
+        }
         
         // Theme management
         function toggleTheme() {
@@ -522,6 +926,7 @@ const htmlTemplate = `<!DOCTYPE html>
             
             body.setAttribute('data-theme', newTheme);
             button.textContent = newTheme === 'light' ? '🌙 Dark' : '☀️ Light';
+            button.setAttribute('aria-pressed', newTheme === 'dark');
             
             localStorage.setItem('theme', newTheme);
         }
@@ -532,6 +937,7 @@ const htmlTemplate = `<!DOCTYPE html>
             document.body.setAttribute('data-theme', savedTheme);
             const button = document.querySelector('.theme-toggle');
             button.textContent = savedTheme === 'light' ? '🌙 Dark' : '☀️ Light';
+            button.setAttribute('aria-pressed', savedTheme === 'dark');
         });
 
         // Navigation section toggle
@@ -543,12 +949,14 @@ const htmlTemplate = `<!DOCTYPE html>
             // Close all sections first and clear all active states
             document.querySelectorAll('.nav-section-content').forEach(c => c.classList.remove('expanded'));
             document.querySelectorAll('.nav-section-header').forEach(h => h.classList.remove('active'));
-            document.querySelectorAll('.nav-item').forEach(item => item.classList.remove('active'));
+            document.querySelectorAll('[aria-expanded]').forEach(h => h.setAttribute('aria-expanded', 'false'));
+            clearSelectedNavItems();
             
             if (!isExpanded) {
                 // Open this section
                 content.classList.add('expanded');
                 header.classList.add('active');
+                header.setAttribute('aria-expanded', 'true');
                 currentSection = sectionName;
                 
                 // If first time opening, select first item and set focus
@@ -569,16 +977,26 @@ const htmlTemplate = `<!DOCTYPE html>
             }
         }
 
+        // Deselect the items of the subjects and checks lists
+        function clearSelectedNavItems() {
+            document.querySelectorAll('.nav-item').forEach(item => item.classList.remove('active'));
+            document.querySelectorAll('.nav-item[role="option"]').forEach(item => item.setAttribute('aria-selected', 'false'));
+            document.querySelectorAll('[role="listbox"]').forEach(list => list.removeAttribute('aria-activedescendant'));
+        }
+
         // Select navigation item
         function selectNavItem(sectionName, itemId) {
             // Remove active from all nav items and section headers
-            document.querySelectorAll('.nav-item').forEach(item => item.classList.remove('active'));
+            clearSelectedNavItems();
             document.querySelectorAll('.nav-section-header').forEach(h => h.classList.remove('active'));
             
             // Add active to selected item and its section header
             const selectedItem = document.querySelector('[data-section="' + sectionName + '"][data-id="' + itemId + '"]');
             if (selectedItem) {
                 selectedItem.classList.add('active');
+                // Screen readers announce the item of the focused list
+                selectedItem.setAttribute('aria-selected', 'true');
+                document.getElementById(sectionName + '-content').setAttribute('aria-activedescendant', selectedItem.id);
                 document.getElementById(sectionName + '-header').classList.add('active');
                 currentItem = itemId;
                 showItemDetails(sectionName, itemId);
@@ -629,6 +1047,11 @@ const htmlTemplate = `<!DOCTYPE html>
 
         // Filter functionality - now filters details instead of navigation
         function filterContent() {
+            if (currentSection === 'issues') {
+                tableState.shown = tablePageSize;
+                renderIssueTable();
+                return;
+            }
             const filterTerm = document.getElementById('filterBox').value.toLowerCase();
             const detailItems = document.querySelectorAll('.detail-item');
             
@@ -642,8 +1065,30 @@ const htmlTemplate = `<!DOCTYPE html>
             });
         }
 
-        // Keyboard navigation
+        // Keyboard navigation: Enter and Space activate the section headers and the columns of the
+        // issue table, / focuses the filter and Escape clears it, the arrow keys, Home and End move
+        // through the list of subjects or checks
         document.addEventListener('keydown', function(event) {
+            const target = event.target;
+            if ((event.key === 'Enter' || event.key === ' ') && target.matches('[role="button"], .issue-table th')) {
+                event.preventDefault();
+                target.click();
+                return;
+            }
+            if (target.id === 'filterBox' && event.key === 'Escape') {
+                target.value = '';
+                filterContent();
+                return;
+            }
+            if (target.matches('input, select, textarea')) {
+                return;
+            }
+            if (event.key === '/') {
+                event.preventDefault();
+                document.getElementById('filterBox').focus();
+                return;
+            }
+
             if (currentSection === 'subjects' || currentSection === 'checks') {
                 const content = document.getElementById(currentSection + '-content');
                 const items = content.querySelectorAll('.nav-item:not(.hidden)');
@@ -669,6 +1114,12 @@ const htmlTemplate = `<!DOCTYPE html>
                 } else if (event.key === 'ArrowUp') {
                     event.preventDefault();
                     newIndex = Math.max(currentIndex - 1, 0);
+                } else if (event.key === 'Home') {
+                    event.preventDefault();
+                    newIndex = 0;
+                } else if (event.key === 'End') {
+                    event.preventDefault();
+                    newIndex = items.length - 1;
                 }
                 
                 if (newIndex !== currentIndex && newIndex >= 0) {
@@ -685,11 +1136,11 @@ const htmlTemplate = `<!DOCTYPE html>
             }
         });
 
-        // Show all details for simple sections (pdfs, skipped, warnings, errors)
+        // Show all details for simple sections (pdfs, skipped, files, archives, warnings, errors)
         function showAllDetails(sectionName) {
             // Clear active states from section headers and navigation items
             document.querySelectorAll('.nav-section-header').forEach(h => h.classList.remove('active'));
-            document.querySelectorAll('.nav-item').forEach(item => item.classList.remove('active'));
+            clearSelectedNavItems();
             document.getElementById(sectionName + '-header').classList.add('active');
             
             currentSection = sectionName;
@@ -704,6 +1155,12 @@ const htmlTemplate = `<!DOCTYPE html>
             let subtitle = '';
             
             switch (sectionName) {
+                case 'issues':
+                    title = 'All Issues';
+                    subtitle = getIssueRows().length + ' issues';
+                    html = generateIssueTable();
+                    break;
+
                 case 'pdfs':
                     title = 'PDF Files';
                     subtitle = scanData.pdf_files ? scanData.pdf_files.length + ' files' : '0 files';
@@ -716,6 +1173,18 @@ const htmlTemplate = `<!DOCTYPE html>
                     html = generateAllSkippedDetails();
                     break;
                     
+                case 'files':
+                    title = 'Files';
+                    subtitle = getListedFiles().length + ' files';
+                    html = generateAllFileDetails();
+                    break;
+                    
+                case 'archives':
+                    title = 'Archives';
+                    subtitle = scanData.archives ? scanData.archives.length + ' archives' : '0 archives';
+                    html = generateAllArchiveDetails();
+                    break;
+                    
                 case 'warnings':
                     title = 'Warnings';
                     subtitle = scanData.warnings ? scanData.warnings.length + ' warnings' : '0 warnings';
@@ -732,15 +1201,253 @@ const htmlTemplate = `<!DOCTYPE html>
             contentTitle.textContent = title;
             contentSubtitle.textContent = subtitle;
             contentDetails.innerHTML = html;
+            if (sectionName === 'issues') {
+                renderIssueTable();
+            }
+        }
+
+        // Show all sections one after another, with every issue of the table, and open the print
+        // dialog, e.g. to save the report as PDF for the curation record
+        function showPrintView() {
+            const shown = tableState.shown;
+            tableState.shown = Infinity;
+            let html = '';
+            ['issues', 'pdfs', 'skipped', 'files', 'archives', 'warnings', 'errors'].forEach(sectionName => {
+                if (sectionName !== 'issues' && document.getElementById(sectionName + '-count').textContent === '0') {
+                    return;
+                }
+                showAllDetails(sectionName);
+                html += '<section class="print-section">';
+                html += '<h2 class="content-title">' + escapeHtml(document.getElementById('contentTitle').textContent) + '</h2>';
+                html += '<div class="content-subtitle">' + escapeHtml(document.getElementById('contentSubtitle').textContent) + '</div>';
+                html += document.getElementById('contentDetails').innerHTML;
+                html += '</section>';
+            });
+            tableState.shown = shown;
+
+            document.querySelectorAll('.nav-section-header').forEach(h => h.classList.remove('active'));
+            currentSection = 'print';
+            currentItem = null;
+            document.getElementById('contentTitle').textContent = 'Complete report';
+            document.getElementById('contentSubtitle').textContent = 'All sections of the report, for printing or saving as PDF';
+            document.getElementById('contentDetails').innerHTML = html;
+            window.print();
+        }
+
+        // Issues of all checks as table rows
+        function getIssueRows() {
+            if (issueRows !== null) {
+                return issueRows;
+            }
+            issueRows = [];
+            (scanData.details_check_focused || []).forEach(check => {
+                (check.issues || []).forEach(issue => {
+                    issueRows.push({
+                        file: issue.subject,
+                        path: issue.path || '',
+                        archive: issue.archive_name || '',
+                        check: check.checkname,
+                        severity: check.severity || 'warning',
+                        line: issue.line || 0,
+                        count: issue.count || 1,
+                        message: issue.message,
+                        id: issue.id || '',
+                        issue: issue
+                    });
+                });
+            });
+            return issueRows;
+        }
+
+        // Controls and empty table of the issues, filled by renderIssueTable
+        function generateIssueTable() {
+            const rows = getIssueRows();
+            const checks = [...new Set(rows.map(r => r.check))].sort();
+            const archives = [...new Set(rows.map(r => r.archive).filter(a => a !== ''))].sort();
+
+            let html = '<div class="table-controls">';
+            html += '<span class="facet">Severity:';
+            ['error', 'warning', 'info'].forEach(severity => {
+                html += '<label><input type="checkbox" onchange="setSeverityFacet(\'' + severity + '\', this.checked)"' + (tableState.severities[severity] ? ' checked' : '') + '> ' + severity + '</label>';
+            });
+            html += '</span>';
+            html += '<label class="facet">Check: <select onchange="setFacet(\'check\', this.value)"><option value="">All</option>';
+            checks.forEach(check => {
+                html += '<option value="' + escapeHtml(check) + '"' + (tableState.check === check ? ' selected' : '') + '>' + escapeHtml(check) + '</option>';
+            });
+            html += '</select></label>';
+            if (archives.length > 0) {
+                html += '<label class="facet">Archive: <select onchange="setFacet(\'archive\', this.value)"><option value="">All</option><option value="-"' + (tableState.archive === '-' ? ' selected' : '') + '>Not in an archive</option>';
+                archives.forEach(archive => {
+                    html += '<option value="' + escapeHtml(archive) + '"' + (tableState.archive === archive ? ' selected' : '') + '>' + escapeHtml(archive) + '</option>';
+                });
+                html += '</select></label>';
+            }
+            html += '<button onclick="exportIssuesCSV()">Export CSV</button>';
+            html += '</div>';
+
+            html += '<table class="issue-table"><thead><tr>';
+            [['file', 'File'], ['line', 'Line'], ['check', 'Check'], ['severity', 'Severity'], ['archive', 'Archive'], ['message', 'Message']].forEach(([key, label]) => {
+                html += '<th scope="col" tabindex="0" aria-sort="none" data-key="' + key + '" onclick="sortIssueTable(\'' + key + '\')">' + label + '</th>';
+            });
+            html += '</tr></thead><tbody id="issueTableBody"></tbody></table>';
+            html += '<div class="table-footer" id="issueTableFooter"></div>';
+            return html;
+        }
+
+        // Rows that pass the facets and the filter box, in table order
+        function getVisibleIssueRows() {
+            const filterTerm = document.getElementById('filterBox').value.toLowerCase();
+            const rows = getIssueRows().filter(row => {
+                if (!tableState.severities[row.severity]) return false;
+                if (tableState.check !== '' && row.check !== tableState.check) return false;
+                if (tableState.archive === '-' && row.archive !== '') return false;
+                if (tableState.archive !== '' && tableState.archive !== '-' && row.archive !== tableState.archive) return false;
+                if (filterTerm !== '') {
+                    const text = (row.file + ' ' + row.path + ' ' + row.check + ' ' + row.archive + ' ' + row.message).toLowerCase();
+                    if (!text.includes(filterTerm)) return false;
+                }
+                return true;
+            });
+
+            const key = tableState.sortKey;
+            const direction = tableState.sortAsc ? 1 : -1;
+            rows.sort((a, b) => {
+                let result;
+                if (key === 'severity') {
+                    result = severityOrder[a.severity] - severityOrder[b.severity];
+                } else if (key === 'line') {
+                    result = a.line - b.line;
+                } else {
+                    result = a[key].localeCompare(b[key]);
+                }
+                if (result === 0 && key !== 'file') {
+                    result = a.file.localeCompare(b.file) || a.line - b.line;
+                }
+                return result * direction;
+            });
+            return rows;
+        }
+
+        // Fill the issue table, only the first tableState.shown rows are rendered
+        function renderIssueTable() {
+            const body = document.getElementById('issueTableBody');
+            if (!body) return;
+            const rows = getVisibleIssueRows();
+
+            let html = '';
+            rows.slice(0, tableState.shown).forEach(row => {
+                html += '<tr>';
+                html += '<td title="' + escapeHtml(row.path) + '">' + escapeHtml(row.file) + '</td>';
+                html += '<td>' + (row.line > 0 ? row.line : '') + '</td>';
+                html += '<td>' + escapeHtml(row.check) + '</td>';
+                html += '<td><span class="severity-badge ' + row.severity + '">' + row.severity + '</span></td>';
+                html += '<td>' + escapeHtml(row.archive) + '</td>';
+                html += '<td>' + escapeHtml(row.message) + generateIssueLocation(row.issue) + '</td>';
+                html += '</tr>';
+            });
+            body.innerHTML = html;
+
+            document.querySelectorAll('.issue-table th').forEach(th => {
+                th.classList.remove('sorted-asc', 'sorted-desc');
+                th.setAttribute('aria-sort', 'none');
+                if (th.dataset.key === tableState.sortKey) {
+                    th.classList.add(tableState.sortAsc ? 'sorted-asc' : 'sorted-desc');
+                    th.setAttribute('aria-sort', tableState.sortAsc ? 'ascending' : 'descending');
+                }
+            });
+
+            let footer = 'Showing ' + Math.min(rows.length, tableState.shown) + ' of ' + rows.length + ' matching issues';
+            if (rows.length > tableState.shown) {
+                footer += ' <button onclick="showMoreIssues()">Show more</button>';
+            }
+            document.getElementById('issueTableFooter').innerHTML = footer;
+        }
+
+        function sortIssueTable(key) {
+            if (tableState.sortKey === key) {
+                tableState.sortAsc = !tableState.sortAsc;
+            } else {
+                tableState.sortKey = key;
+                tableState.sortAsc = true;
+            }
+            renderIssueTable();
+        }
+
+        function setSeverityFacet(severity, enabled) {
+            tableState.severities[severity] = enabled;
+            tableState.shown = tablePageSize;
+            renderIssueTable();
+        }
+
+        function setFacet(facet, value) {
+            tableState[facet] = value;
+            tableState.shown = tablePageSize;
+            renderIssueTable();
+        }
+
+        function showMoreIssues() {
+            tableState.shown += tablePageSize;
+            renderIssueTable();
+        }
+
+        // Download the matching issues, in table order, as CSV
+        function exportIssuesCSV() {
+            const quote = value => '"' + String(value).replace(/"/g, '""') + '"';
+            const lines = [['file', 'path', 'archive', 'line', 'check', 'severity', 'count', 'message', 'id'].join(',')];
+            getVisibleIssueRows().forEach(row => {
+                lines.push([row.file, row.path, row.archive, row.line > 0 ? row.line : '', row.check, row.severity, row.count, row.message, row.id].map(quote).join(','));
+            });
+            downloadFile(lines.join('\r\n') + '\r\n', 'text/csv;charset=utf-8', 'pc_issues.csv');
+        }
+
+        // Download the scan data of the report as JSON report, which pc view and pc report read
+        function downloadScanJSON() {
+            if (!scanData) {
+                return;
+            }
+            downloadFile(JSON.stringify(scanData, null, 2) + '\n', 'application/json', 'pc_report.json');
+        }
+
+        function downloadFile(content, type, name) {
+            const blob = new Blob([content], { type: type });
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(blob);
+            link.download = name;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            URL.revokeObjectURL(link.href);
         }
 
         // Initialize page
-        document.addEventListener('DOMContentLoaded', function() {
+        document.addEventListener('DOMContentLoaded', async function() {
+            if (scanData === null) {
+                try {
+                    scanData = await loadScanData();
+                } catch (err) {
+                    scanData = undefined;
+                }
+                if (!scanData) {
+                    document.getElementById('contentTitle').textContent = 'Failed to load the scan data';
+                    document.getElementById('contentSubtitle').textContent = {{if eq .Mode "split"}}'Keep the {{.DataScript}} file next to the report.'{{else}}'Your browser cannot decompress the report, please use a current browser.'{{end}};
+                    return;
+                }
+            }
+
+            // Debug: Log the data to console
+            console.log('Scan data loaded:', scanData);
+            console.log('Scanned files:', scanData.scanned ? scanData.scanned.length : 0);
+            console.log('Skipped files:', scanData.skipped ? scanData.skipped.length : 0);
+
             populateStats();
             populateNavigation();
             
             // Setup filter
             document.getElementById('filterBox').addEventListener('input', filterContent);
+
+            // Start with the table of all issues
+            showAllDetails('issues');
         });
 
         // Populate statistics
@@ -774,10 +1481,13 @@ const htmlTemplate = `<!DOCTYPE html>
 
         // Populate navigation
         function populateNavigation() {
+            document.getElementById('issues-count').textContent = getIssueRows().length;
             populateSubjectsNav();
             populateChecksNav();
             populatePDFsCount();
             populateSkippedCount();
+            populateFilesCount();
+            populateArchivesCount();
             populateWarningsCount();
             populateErrorsCount();
         }
@@ -791,9 +1501,9 @@ const htmlTemplate = `<!DOCTYPE html>
             if (scanData.details_subject_focused && scanData.details_subject_focused.length > 0) {
                 countElement.textContent = scanData.details_subject_focused.length;
                 
-                scanData.details_subject_focused.forEach(subject => {
+                scanData.details_subject_focused.forEach((subject, index) => {
                     const issueCount = subject.issues ? subject.issues.length : 0;
-                    html += '<div class="nav-item" data-section="subjects" data-id="' + escapeHtml(subject.subject) + '" onclick="selectNavItem(\'subjects\', \'' + escapeHtml(subject.subject) + '\')">';
+                    html += '<div class="nav-item" role="option" aria-selected="false" id="subjects-item-' + index + '" data-section="subjects" data-id="' + escapeHtml(subject.subject) + '" onclick="selectNavItem(\'subjects\', \'' + escapeHtml(subject.subject) + '\')">';
                     html += '<div class="nav-item-title">' + escapeHtml(subject.subject) + '</div>';
                     html += '<div class="nav-item-subtitle">' + issueCount + ' issues</div>';
                     html += '</div>';
@@ -815,9 +1525,9 @@ const htmlTemplate = `<!DOCTYPE html>
             if (scanData.details_check_focused && scanData.details_check_focused.length > 0) {
                 countElement.textContent = scanData.details_check_focused.length;
                 
-                scanData.details_check_focused.forEach(check => {
+                scanData.details_check_focused.forEach((check, index) => {
                     const issueCount = check.issues ? check.issues.length : 0;
-                    html += '<div class="nav-item" data-section="checks" data-id="' + escapeHtml(check.checkname) + '" onclick="selectNavItem(\'checks\', \'' + escapeHtml(check.checkname) + '\')">';
+                    html += '<div class="nav-item" role="option" aria-selected="false" id="checks-item-' + index + '" data-section="checks" data-id="' + escapeHtml(check.checkname) + '" onclick="selectNavItem(\'checks\', \'' + escapeHtml(check.checkname) + '\')">';
                     html += '<div class="nav-item-title">' + escapeHtml(check.checkname) + '</div>';
                     html += '<div class="nav-item-subtitle">' + issueCount + ' issues</div>';
                     html += '</div>';
@@ -841,6 +1551,15 @@ const htmlTemplate = `<!DOCTYPE html>
             countElement.textContent = scanData.skipped ? scanData.skipped.length : '0';
         }
 
+        function populateFilesCount() {
+            document.getElementById('files-count').textContent = getListedFiles().length;
+        }
+
+        function populateArchivesCount() {
+            const countElement = document.getElementById('archives-count');
+            countElement.textContent = scanData.archives ? scanData.archives.length : '0';
+        }
+
         function populateWarningsCount() {
             const countElement = document.getElementById('warnings-count');
             countElement.textContent = scanData.warnings ? scanData.warnings.length : '0';
@@ -859,6 +1578,7 @@ const htmlTemplate = `<!DOCTYPE html>
                     html += '<div class="detail-item">';
                     html += '<div class="detail-header">' + escapeHtml(issue.checkname) + '</div>';
                     html += '<div class="detail-content">' + escapeHtml(issue.message) + '</div>';
+                    html += generateIssueLocation(issue);
                     html += '</div>';
                 });
             } else {
@@ -867,6 +1587,30 @@ const htmlTemplate = `<!DOCTYPE html>
             return html;
         }
 
+        // Occurrences, lines and snippet of an issue, with the finding marked »like this« highlighted
+        function generateIssueLocation(issue) {
+            if (!issue.line && !issue.snippet && !issue.count) {
+                return '';
+            }
+            let parts = [];
+            if (issue.count > 1) {
+                parts.push(issue.count + ' occurrences');
+            }
+            if (issue.lines && issue.lines.length > 1) {
+                parts.push('lines ' + issue.lines.join(', '));
+            } else if (issue.line) {
+                parts.push('line ' + issue.line);
+            }
+            let html = '<div class="detail-location">' + parts.join(', ');
+            if (parts.length > 0 && issue.snippet) {
+                html += ': ';
+            }
+            if (issue.snippet) {
+                html += '<code>' + escapeHtml(issue.snippet).replace(/»(.*?)«/g, '<mark>$1</mark>') + '</code>';
+            }
+            return html + '</div>';
+        }
+
         function generateCheckDetails(check) {
             let html = '';
             if (check.issues && check.issues.length > 0) {
@@ -877,6 +1621,7 @@ const htmlTemplate = `<!DOCTYPE html>
                         html += '<div class="detail-path">' + escapeHtml(issue.path) + '</div>';
                     }
                     html += '<div class="detail-content">' + escapeHtml(issue.message) + '</div>';
+                    html += generateIssueLocation(issue);
                     html += '</div>';
                 });
             } else {
@@ -891,7 +1636,7 @@ const htmlTemplate = `<!DOCTYPE html>
             if (skipped.path) {
                 html += '<div class="detail-path">' + escapeHtml(skipped.path) + '</div>';
             }
-            html += '<div class="detail-content"><strong>Reason:</strong> ' + escapeHtml(skipped.reason) + '</div>';
+            html += '<div class="detail-content"><strong>Reason:</strong> ' + escapeHtml(skipped.reason) + (skipped.code ? ' [' + escapeHtml(skipped.code) + ']' : '') + '</div>';
             html += '</div>';
             return html;
         }
@@ -933,11 +1678,12 @@ const htmlTemplate = `<!DOCTYPE html>
             if (scanData.skipped && scanData.skipped.length > 0) {
                 scanData.skipped.forEach(file => {
                     html += '<div class="detail-item">';
-                    html += '<div class="detail-header">' + escapeHtml(file.filename) + '</div>';
+                    const name = file.archive_name ? file.archive_name + ' > ' + file.filename : file.filename;
+                    html += '<div class="detail-header">' + escapeHtml(name) + '</div>';
                     if (file.path) {
                         html += '<div class="detail-path">' + escapeHtml(file.path) + '</div>';
                     }
-                    html += '<div class="detail-content"><strong>Reason:</strong> ' + escapeHtml(file.reason) + '</div>';
+                    html += '<div class="detail-content"><strong>Reason:</strong> ' + escapeHtml(file.reason) + (file.code ? ' [' + escapeHtml(file.code) + ']' : '') + '</div>';
                     html += '</div>';
                 });
             } else {
@@ -946,6 +1692,75 @@ const htmlTemplate = `<!DOCTYPE html>
             return html;
         }
 
+        function generateAllArchiveDetails() {
+            let html = '';
+            if (scanData.archives && scanData.archives.length > 0) {
+                scanData.archives.forEach(archive => {
+                    html += '<div class="detail-item">';
+                    const name = archive.location ? archive.location + ' > ' + archive.archive : archive.archive;
+                    html += '<div class="detail-header">' + escapeHtml(name) + '</div>';
+                    if (archive.path) {
+                        html += '<div class="detail-path">' + escapeHtml(archive.path) + '</div>';
+                    }
+                    html += '<div class="detail-content">' + archive.files + ' files, ' + formatSize(archive.size) + ', ' + formatSize(archive.unpacked_size) + ' unpacked</div>';
+                    if (archive.error) {
+                        html += '<div class="detail-content"><strong>Could not be listed:</strong> ' + escapeHtml(archive.error) + '</div>';
+                    }
+                    if (archive.members && archive.members.length > 0) {
+                        html += '<table class="archive-table"><thead><tr><th>Name</th><th>Size</th><th>Type</th></tr></thead><tbody>';
+                        archive.members.forEach(member => {
+                            const size = member.type === 'folder' ? '' : formatSize(member.size);
+                            html += '<tr><td>' + escapeHtml(member.name) + '</td><td class="size">' + size + '</td><td>' + escapeHtml(member.type || '') + '</td></tr>';
+                        });
+                        html += '</tbody></table>';
+                    }
+                    html += '</div>';
+                });
+            } else {
+                html = '<div class="detail-item"><div class="detail-content">No archives listed.</div></div>';
+            }
+            return html;
+        }
+
+        // Scanned files with their metadata, listed with fileMetadata = true
+        function getListedFiles() {
+            return (scanData.scanned || []).filter(file => file.metadata);
+        }
+
+        function generateAllFileDetails() {
+            const files = getListedFiles();
+            if (files.length === 0) {
+                return '<div class="detail-item"><div class="detail-content">No files listed. Set fileMetadata = true in [general] to list the files with their sizes and modification times.</div></div>';
+            }
+            let html = '<div class="detail-item"><table class="archive-table"><thead><tr><th>Name</th><th>Size</th><th>Modified</th><th>SHA-256</th><th>Issues</th></tr></thead><tbody>';
+            files.forEach(file => {
+                const metadata = file.metadata;
+                const name = file.location ? file.location + ' > ' + file.filename : file.filename;
+                const issues = (file.issues || []).reduce((sum, check) => sum + check.issue_count, 0);
+                html += '<tr><td title="' + escapeHtml(metadata.path) + '">' + escapeHtml(name) + '</td>';
+                html += '<td class="size">' + formatSize(metadata.size) + '</td>';
+                html += '<td>' + escapeHtml(metadata.error ? 'Could not be read: ' + metadata.error : (metadata.modified || '')) + '</td>';
+                html += '<td class="hash">' + escapeHtml(metadata.sha256 || '') + '</td>';
+                html += '<td class="size">' + issues + '</td></tr>';
+            });
+            return html + '</tbody></table></div>';
+        }
+
+        // Sizes in binary units, as in the text output
+        function formatSize(bytes) {
+            const units = ['KiB', 'MiB', 'GiB', 'TiB'];
+            if (bytes < 1024) {
+                return bytes + ' B';
+            }
+            let value = bytes / 1024;
+            let unit = 0;
+            while (value >= 1024 && unit < units.length - 1) {
+                value /= 1024;
+                unit++;
+            }
+            return value.toFixed(1) + ' ' + units[unit];
+        }
+
         function generateAllWarningDetails() {
             let html = '';
             if (scanData.warnings && scanData.warnings.length > 0) {
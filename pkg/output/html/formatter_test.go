@@ -1,6 +1,9 @@
 package html
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"os"
@@ -365,6 +368,27 @@ func TestGenerateReport_SpecialCharacters(t *testing.T) {
 	}
 }
 
+func TestGenerateReport_ScriptInMessage(t *testing.T) {
+	// Reports edited with jq or Python keep "<" unescaped, the page must escape it
+	jsonData := `{"timestamp": "2026-01-01T10:00:00Z", "details_subject_focused": [{"subject": "notes.txt",
+		"issues": [{"checkname": "IsFreeOfKeywords", "message": "</script><script>alert(1)</script>"}]}]}`
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	if err := NewHTMLFormatter().GenerateReport(jsonData, outputPath); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "<script>alert(1)") {
+		t.Error("expected the message not to end the script element")
+	}
+	if !strings.Contains(string(content), `\u003c/script\u003e\u003cscript\u003ealert(1)`) {
+		t.Error("expected the message to be embedded escaped")
+	}
+}
+
 func TestGenerateReport_LargeDataset(t *testing.T) {
 	// Create larger test dataset
 	var scannedFiles []TestScannedFile
@@ -543,4 +567,190 @@ func TestGenerateReport_FilePermissions(t *testing.T) {
 	if !fileInfo.Mode().IsRegular() {
 		t.Error("Generated file is not a regular file")
 	}
-}
\ No newline at end of file
+}
+func TestGenerateReport_GzipMode(t *testing.T) {
+	jsonData := `{"scanned":[{"filename":"big.log"}]}`
+	formatter, err := NewHTMLFormatterWithOptions(Options{Mode: ModeGzip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := formatter.SelfContained(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	if strings.Contains(htmlContent, "big.log") {
+		t.Error("gzip report contains the uncompressed data")
+	}
+
+	start := strings.Index(htmlContent, `const scanPayload = "`)
+	if start < 0 {
+		t.Fatal("gzip report has no payload")
+	}
+	payload := htmlContent[start+len(`const scanPayload = "`):]
+	payload = payload[:strings.Index(payload, `"`)]
+	compressed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decompressed) != jsonData {
+		t.Errorf("payload decompresses to %q", decompressed)
+	}
+}
+
+func TestGenerateReport_SplitMode(t *testing.T) {
+	jsonData := `{"scanned":[{"filename":"big.log"}]}`
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	formatter, err := NewHTMLFormatterWithOptions(Options{Mode: ModeSplit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := formatter.GenerateReport(jsonData, outputPath); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `<script src="report_files/data.js"></script>`) {
+		t.Error("split report does not load its data")
+	}
+	if strings.Contains(string(content), "big.log") {
+		t.Error("split report contains the data")
+	}
+	if data, err := os.ReadFile(filepath.Join(DataDir(outputPath), "data.json")); err != nil || string(data) != jsonData {
+		t.Errorf("unexpected data.json: %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(DataDir(outputPath), "data.js")); err != nil || !strings.HasPrefix(string(data), "window.pcScanData = {") {
+		t.Errorf("unexpected data.js: %q, %v", data, err)
+	}
+
+	// The uploaded copy does not depend on the data directory
+	selfContained, err := formatter.SelfContained(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(selfContained), "data.js") || !strings.Contains(string(selfContained), "const scanPayload") {
+		t.Error("self-contained copy of a split report is not compressed")
+	}
+}
+
+func TestGenerateReport_TitleAndLogo(t *testing.T) {
+	logo := filepath.Join(t.TempDir(), "logo.svg")
+	if err := os.WriteFile(logo, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	formatter, err := NewHTMLFormatterWithOptions(Options{Title: "Eawag <Data> Review", Logo: logo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := formatter.SelfContained(`{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	if !strings.Contains(htmlContent, "<title>Eawag &lt;Data&gt; Review</title>") {
+		t.Error("title is missing or not escaped")
+	}
+	if !strings.Contains(htmlContent, `src="data:image/svg`) {
+		t.Error("logo is not embedded")
+	}
+	if !strings.Contains(htmlContent, "const scanData = {}") {
+		t.Error("single report does not embed the data")
+	}
+
+	notAnImage := filepath.Join(t.TempDir(), "logo.txt")
+	if err := os.WriteFile(notAnImage, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	formatter, _ = NewHTMLFormatterWithOptions(Options{Logo: notAnImage})
+	if _, err := formatter.SelfContained(`{}`); err == nil || !strings.Contains(err.Error(), "not an image") {
+		t.Errorf("expected an error for a logo that is not an image, got %v", err)
+	}
+}
+
+func TestNewHTMLFormatterWithOptions_InvalidMode(t *testing.T) {
+	if _, err := NewHTMLFormatterWithOptions(Options{Mode: "zip"}); err == nil || !strings.Contains(err.Error(), "unknown HTML mode 'zip'") {
+		t.Errorf("expected an error for an unknown mode, got %v", err)
+	}
+}
+
+func TestGenerateReport_IssueTable(t *testing.T) {
+	content, err := NewHTMLFormatter().SelfContained(`{"details_check_focused":[{"checkname":"IsFreeOfKeywords","severity":"error","issues":[]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{`id="issues-header"`, "function renderIssueTable()", "function exportIssuesCSV()", "showAllDetails('issues');"} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("HTML report is missing %q", expected)
+		}
+	}
+}
+
+func TestGenerateReport_Files(t *testing.T) {
+	content, err := NewHTMLFormatter().SelfContained(`{"scanned":[{"filename":"data.csv","issues":[],"metadata":{"path":"data.csv","size":5,"modified":"2024-05-01T12:00:00Z"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{`id="files-header"`, "function generateAllFileDetails()", "populateFilesCount();", `"modified":"2024-05-01T12:00:00Z"`} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("HTML report is missing %q", expected)
+		}
+	}
+}
+
+func TestGenerateReport_Archives(t *testing.T) {
+	content, err := NewHTMLFormatter().SelfContained(`{"archives":[{"archive":"data.zip","path":"data.zip","size":10,"files":1,"unpacked_size":20,"members":[{"name":"a.csv","size":20,"type":"text/csv"}]}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{`id="archives-header"`, "function generateAllArchiveDetails()", "populateArchivesCount();", `"unpacked_size":20`} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("HTML report is missing %q", expected)
+		}
+	}
+}
+
+func TestGenerateReport_AccessibleAndPrintable(t *testing.T) {
+	content, err := NewHTMLFormatter().SelfContained(`{"details_check_focused":[]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{`class="skip-link"`, `aria-label="Report sections"`, `role="listbox"`, `aria-live="polite"`, "@media print", "@media (prefers-reduced-motion: reduce)", "function showPrintView()", `onclick="showPrintView()"`} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("HTML report is missing %q", expected)
+		}
+	}
+}
+
+func TestGenerateReport_DownloadJSON(t *testing.T) {
+	for _, mode := range []string{ModeSingle, ModeGzip} {
+		formatter, err := NewHTMLFormatterWithOptions(Options{Mode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := formatter.SelfContained(`{"details_check_focused":[]}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		htmlContent := string(content)
+		for _, expected := range []string{`onclick="downloadScanJSON()"`, "function downloadScanJSON()", "'pc_report.json'"} {
+			if !strings.Contains(htmlContent, expected) {
+				t.Errorf("%s HTML report is missing %q", mode, expected)
+			}
+		}
+	}
+}
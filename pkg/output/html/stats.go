@@ -0,0 +1,320 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
+// DefaultStatsTitle is the heading of statistics dashboards without a configured title
+const DefaultStatsTitle = "Package Checker Statistics"
+
+// GenerateStatsReport creates a static HTML dashboard of the statistics of many scans. statsJSON
+// are history statistics with the totals, the checks and the trend. The data is always embedded
+// in the HTML file; Mode does not apply.
+func (h *HTMLFormatter) GenerateStatsReport(statsJSON string, outputPath string) error {
+	var stats map[string]interface{}
+	if err := json.Unmarshal([]byte(statsJSON), &stats); err != nil {
+		return fmt.Errorf("failed to parse JSON data: %w", err)
+	}
+
+	templateData := struct {
+		JSONData    template.JS
+		LogoURI     template.URL
+		GeneratedAt string
+		Title       string
+	}{
+		JSONData:    scriptJSON(statsJSON),
+		GeneratedAt: i18n.FormatTime(time.Now()),
+		Title:       h.options.Title,
+	}
+	if templateData.Title == "" {
+		templateData.Title = DefaultStatsTitle
+	}
+	if h.options.Logo != "" {
+		logo, err := logoURI(h.options.Logo)
+		if err != nil {
+			return err
+		}
+		templateData.LogoURI = logo
+	}
+
+	tmpl := template.Must(template.New("stats").Parse(statsTemplate))
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML file: %w", err)
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, templateData); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// HTML template of the statistics dashboard: totals, the checks reporting the most issues and
+// the trend of the issues per location, drawn as bars without external libraries
+const statsTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        :root {
+            --primary-color: #035C77;
+            --success-color: #10b981;
+            --warning-color: #f59e0b;
+            --error-color: #ef4444;
+            --info-color: #3b82f6;
+            --background-color: #ffffff;
+            --surface-color: #f8fafc;
+            --text-color: #1e293b;
+            --text-secondary: #64748b;
+            --border-color: #e2e8f0;
+        }
+
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            background: var(--background-color);
+            color: var(--text-color);
+            font-size: 13px;
+        }
+
+        .header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            padding: 12px 20px;
+            background: var(--surface-color);
+            border-bottom: 1px solid var(--border-color);
+        }
+
+        .header-title {
+            display: flex;
+            align-items: center;
+            gap: 12px;
+        }
+
+        .header-title .logo {
+            height: 32px;
+        }
+
+        .header h1 {
+            color: var(--primary-color);
+            font-size: 1.5rem;
+            font-weight: 600;
+        }
+
+        .range {
+            color: var(--text-secondary);
+            font-size: 12px;
+        }
+
+        .cards {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 12px;
+            padding: 15px 20px 0;
+        }
+
+        .card {
+            flex: 1 1 150px;
+            padding: 12px;
+            border: 1px solid var(--border-color);
+            border-radius: 6px;
+            background: var(--surface-color);
+        }
+
+        .card-value {
+            font-size: 1.6rem;
+            font-weight: 600;
+            color: var(--primary-color);
+        }
+
+        .card-label {
+            color: var(--text-secondary);
+            font-size: 11px;
+        }
+
+        .content {
+            padding: 0 20px 20px;
+        }
+
+        h2 {
+            margin-top: 20px;
+            font-size: 1rem;
+            color: var(--primary-color);
+        }
+
+        table {
+            width: 100%;
+            margin-top: 8px;
+            border-collapse: collapse;
+        }
+
+        th,
+        td {
+            padding: 6px 12px;
+            border-top: 1px solid var(--border-color);
+            text-align: left;
+            vertical-align: middle;
+        }
+
+        th {
+            font-size: 11px;
+            color: var(--text-secondary);
+            font-weight: normal;
+        }
+
+        td.number {
+            text-align: right;
+            white-space: nowrap;
+        }
+
+        .bar-cell {
+            width: 40%;
+        }
+
+        .bar {
+            height: 10px;
+            border-radius: 2px;
+            background: var(--primary-color);
+        }
+
+        .bar.error {
+            background: var(--error-color);
+        }
+
+        .bar.warning {
+            background: var(--warning-color);
+        }
+
+        .bar.info {
+            background: var(--info-color);
+        }
+
+        .severity {
+            font-size: 10px;
+            text-transform: uppercase;
+            color: var(--text-secondary);
+        }
+
+        .empty {
+            margin-top: 20px;
+            color: var(--text-secondary);
+        }
+
+        .footer {
+            padding: 10px 20px;
+            color: var(--text-secondary);
+            font-size: 11px;
+            border-top: 1px solid var(--border-color);
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <div class="header-title">
+            {{if .LogoURI}}<img class="logo" src="{{.LogoURI}}" alt="">{{end}}
+            <h1>{{.Title}}</h1>
+        </div>
+        <div class="range" id="range"></div>
+    </div>
+
+    <div class="cards" id="cards"></div>
+    <div class="content" id="content"></div>
+
+    <div class="footer">Generated on {{.GeneratedAt}}</div>
+
+    <script>
+        // Statistics data from Go template
+        const statsData = {{.JSONData}};
+
+        function populateCards() {
+            const cards = [
+                { label: 'scans', value: statsData.scans || 0 },
+                { label: 'locations', value: statsData.locations || 0 },
+                { label: 'issues in the latest scans', value: statsData.issues || 0 },
+                { label: 'issues per location', value: (statsData.average_issues || 0).toFixed(2) },
+                { label: 'locations without issues', value: statsData.clean_locations || 0 }
+            ];
+            document.getElementById('cards').innerHTML = cards.map(card =>
+                '<div class="card"><div class="card-value">' + escapeHtml(String(card.value)) + '</div><div class="card-label">' + card.label + '</div></div>').join('');
+            if (statsData.from || statsData.to) {
+                document.getElementById('range').textContent = (statsData.from || '?') + ' → ' + (statsData.to || '?');
+            }
+        }
+
+        // A bar whose width is value relative to the largest value
+        function bar(value, largest, severity) {
+            const width = largest > 0 ? Math.max(1, Math.round(100 * value / largest)) : 0;
+            return '<div class="bar ' + (severity || '') + '" style="width: ' + width + '%"></div>';
+        }
+
+        function renderChecks() {
+            const checks = statsData.checks || [];
+            if (checks.length === 0) {
+                return '<h2>Most frequent checks</h2><div class="empty">No issues in the latest scans.</div>';
+            }
+            const largest = Math.max(...checks.map(check => check.issues));
+            let html = '<h2>Most frequent checks</h2><table><tr><th>Check</th><th>Severity</th><th>Issues</th><th>Locations</th><th></th></tr>';
+            checks.forEach(check => {
+                html += '<tr><td>' + escapeHtml(check.checkname) + '</td>';
+                html += '<td><span class="severity">' + escapeHtml(check.severity || '') + '</span></td>';
+                html += '<td class="number">' + check.issues + '</td><td class="number">' + check.locations + '</td>';
+                html += '<td class="bar-cell">' + bar(check.issues, largest, check.severity) + '</td></tr>';
+            });
+            return html + '</table>';
+        }
+
+        function renderTrend() {
+            const trend = statsData.trend || [];
+            if (trend.length === 0) {
+                return '';
+            }
+            const largest = Math.max(...trend.map(period => period.average_issues));
+            let html = '<h2>Trend</h2><table><tr><th>Period</th><th>Scans</th><th>Locations</th><th>Issues</th><th>Per location</th><th></th></tr>';
+            trend.forEach(period => {
+                html += '<tr><td>' + escapeHtml(period.period) + '</td>';
+                html += '<td class="number">' + period.scans + '</td><td class="number">' + period.locations + '</td>';
+                html += '<td class="number">' + period.issues + '</td><td class="number">' + period.average_issues.toFixed(2) + '</td>';
+                html += '<td class="bar-cell">' + bar(period.average_issues, largest) + '</td></tr>';
+            });
+            return html + '</table>';
+        }
+
+        // Utility function to escape HTML
+        function escapeHtml(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+
+        document.addEventListener('DOMContentLoaded', function() {
+            populateCards();
+            if (!statsData.scans) {
+                document.getElementById('content').innerHTML = '<div class="empty">No scans found.</div>';
+                return;
+            }
+            document.getElementById('content').innerHTML = renderChecks() + renderTrend();
+        });
+    </script>
+</body>
+</html>
+`
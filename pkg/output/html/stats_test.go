@@ -0,0 +1,33 @@
+package html
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStatsReport(t *testing.T) {
+	statsJSON := `{"from": "2024-01-10T10:00:00Z", "to": "2024-02-05T10:00:00Z", "scans": 4, "locations": 3, "clean_locations": 1,
+		"issues": 6, "average_issues": 2, "checks": [{"checkname": "HasNoWhiteSpace", "severity": "warning", "issues": 3, "locations": 2}],
+		"trend": [{"period": "2024-01", "scans": 2, "locations": 2, "issues": 9, "average_issues": 4.5}]}`
+	outputPath := filepath.Join(t.TempDir(), "stats.html")
+
+	if err := NewHTMLFormatter().GenerateStatsReport(statsJSON, outputPath); err != nil {
+		t.Fatalf("GenerateStatsReport failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlContent := string(content)
+	for _, expected := range []string{"<title>" + DefaultStatsTitle + "</title>", "const statsData = {", "HasNoWhiteSpace", "function renderTrend()"} {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("statistics dashboard is missing %q", expected)
+		}
+	}
+
+	if err := NewHTMLFormatter().GenerateStatsReport("{", outputPath); err == nil || !strings.Contains(err.Error(), "failed to parse JSON data") {
+		t.Errorf("expected an error for invalid JSON, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package json
+
+import (
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// ArchiveListing is the inventory of an archive: its members with their sizes and types, listed
+// from the headers of the archive without checking their contents
+type ArchiveListing struct {
+	Archive      string          `json:"archive"`            // Display name of the archive
+	Path         string          `json:"path"`               // Path of the archive file
+	Size         int64           `json:"size"`               // Size of the archive file in bytes
+	Files        int             `json:"files"`              // Members that are not folders
+	UnpackedSize int64           `json:"unpacked_size"`      // Sum of the sizes of the members in bytes
+	Members      []ArchiveMember `json:"members"`            // Members in the order of the archive
+	Error        string          `json:"error,omitempty"`    // Why the archive could not be listed
+	Location     string          `json:"location,omitempty"` // Location the archive was found in, in merged reports
+}
+
+// ArchiveMember is a file or folder in an archive
+type ArchiveMember struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type,omitempty"` // "folder" or the MIME type derived from the extension, empty if unknown
+}
+
+// FolderType is the type of the folders of an archive listing
+const FolderType = "folder"
+
+// ListArchives lists the members of the supported archives among files, without unpacking them.
+// Archives that cannot be read are listed with the error. The paths are redacted with redactor,
+// which may be nil.
+func ListArchives(cfg config.Config, files []structs.File, redactor *output.Redactor) []ArchiveListing {
+	listings := []ArchiveListing{}
+	for _, file := range files {
+		if cfg.Context().Err() != nil {
+			break
+		}
+		if !readers.IsSupportedArchive(file.Name) {
+			continue
+		}
+		listing := ArchiveListing{
+			Archive: file.GetDisplayName(),
+			Path:    redactor.Path(file.Path),
+			Size:    file.Size,
+			Members: []ArchiveMember{},
+		}
+		entries, err := readers.ListArchive(file)
+		if err != nil {
+			listing.Error = redactor.Text(err.Error())
+		}
+		for _, entry := range entries {
+			member := ArchiveMember{Name: entry.Name, Size: entry.Size, Type: FolderType}
+			if !entry.IsDir {
+				member.Type = memberType(entry.Name)
+				listing.Files++
+				listing.UnpackedSize += entry.Size
+			}
+			listing.Members = append(listing.Members, member)
+		}
+		listings = append(listings, listing)
+	}
+	return listings
+}
+
+// memberType returns the MIME type of a member derived from its extension, without parameters
+func memberType(name string) string {
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(path.Ext(name))), ";")
+	return mimeType
+}
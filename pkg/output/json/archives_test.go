@@ -0,0 +1,73 @@
+package json
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestListArchives(t *testing.T) {
+	corrupt := filepath.Join(t.TempDir(), "corrupt.zip")
+	if err := os.WriteFile(corrupt, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []structs.File{
+		structs.ToFile("../../../testdata/archives/test.tar", "test.tar", -1, ""),
+		structs.ToFile("../../../testdata/readme.txt", "readme.txt", -1, ""),
+		structs.ToFile(corrupt, "corrupt.zip", -1, ""),
+	}
+
+	listings := ListArchives(config.Config{}, files, nil)
+	if len(listings) != 2 {
+		t.Fatalf("Expected the two archives to be listed, got %+v", listings)
+	}
+
+	tar := listings[0]
+	if tar.Archive != "test.tar" || tar.Size != 10240 || tar.Files != 2 || tar.UnpackedSize != 6 || tar.Error != "" {
+		t.Errorf("Unexpected listing %+v", tar)
+	}
+	expected := []ArchiveMember{
+		{Name: "test/", Size: 0, Type: FolderType},
+		{Name: "test/file2", Size: 0},
+		{Name: "test/file1.txt", Size: 6, Type: memberType("file1.txt")},
+	}
+	if len(tar.Members) != len(expected) {
+		t.Fatalf("Expected members %+v, got %+v", expected, tar.Members)
+	}
+	for i, member := range expected {
+		if tar.Members[i] != member {
+			t.Errorf("Expected member %+v, got %+v", member, tar.Members[i])
+		}
+	}
+	if memberType("data/report.PDF") != "application/pdf" {
+		t.Errorf("Expected the type to be derived from the extension, got %q", memberType("data/report.PDF"))
+	}
+
+	if listings[1].Archive != "corrupt.zip" || listings[1].Error == "" || len(listings[1].Members) != 0 {
+		t.Errorf("Expected the corrupt archive to be listed with its error, got %+v", listings[1])
+	}
+}
+
+func TestFormatResults_Archives(t *testing.T) {
+	formatter := NewJSONFormatter()
+	result, err := formatter.FormatResults("test", "LocalCollector", []structs.Message{}, 0, nil)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	if strings.Contains(result, `"archives"`) {
+		t.Errorf("Expected no archives without a listing, got %s", result)
+	}
+
+	formatter.Archives = []ArchiveListing{
+		{Archive: "b.zip", Path: "/data/b.zip", Members: []ArchiveMember{}},
+		{Archive: "a.zip", Path: "/data/a.zip", Members: []ArchiveMember{{Name: "x.csv", Size: 3}}},
+	}
+	scan := formatter.Result("test", "LocalCollector", []structs.Message{}, 2, nil)
+	if len(scan.Archives) != 2 || scan.Archives[0].Archive != "a.zip" {
+		t.Errorf("Expected the archives sorted by path, got %+v", scan.Archives)
+	}
+}
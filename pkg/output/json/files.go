@@ -0,0 +1,84 @@
+package json
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// FileMetadata is the size, modification time and SHA-256 of a scanned file, for tracking the
+// integrity of a package between scans
+type FileMetadata struct {
+	Path     string `json:"path"`               // Path of the file
+	Size     int64  `json:"size"`               // Size in bytes
+	Modified string `json:"modified,omitempty"` // Modification time in RFC 3339 (UTC)
+	SHA256   string `json:"sha256,omitempty"`   // Hex digest of the content, with hashFiles
+	Error    string `json:"error,omitempty"`    // Why the file could not be read
+}
+
+// ListFiles returns the files with their metadata, as scanned files without issues for the
+// report to fill in. The SHA-256 of the contents is computed if hash is set, which reads every
+// file. Paths and errors are redacted with redactor, which may be nil.
+func ListFiles(cfg config.Config, files []structs.File, hash bool, redactor *output.Redactor) []ScannedFile {
+	listed := []ScannedFile{}
+	for _, file := range files {
+		if cfg.Context().Err() != nil {
+			break
+		}
+		metadata := FileMetadata{Path: redactor.Path(file.Path), Size: file.Size}
+		info, err := os.Stat(file.Path)
+		if err == nil {
+			metadata.Size = info.Size()
+			metadata.Modified = info.ModTime().UTC().Format(time.RFC3339)
+			if hash {
+				metadata.SHA256, err = hashFile(file.Path)
+			}
+		}
+		if err != nil {
+			metadata.Error = redactor.Text(err.Error())
+		}
+		listed = append(listed, ScannedFile{
+			Filename: file.GetDisplayName(),
+			Issues:   []CheckSummary{},
+			Metadata: &metadata,
+		})
+	}
+	return listed
+}
+
+// hashFile returns the hex SHA-256 of the content of the file at path
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// addFiles adds the metadata of the listed files to the scanned files of the same name, and the
+// files without issues to the scanned files
+func (result *ScanResult) addFiles(listed []ScannedFile) {
+	byName := make(map[string]int)
+	for i, scanned := range result.Scanned {
+		byName[scanned.Filename] = i
+	}
+	for _, file := range listed {
+		if i, ok := byName[file.Filename]; ok && result.Scanned[i].Metadata == nil {
+			result.Scanned[i].Metadata = file.Metadata
+			continue
+		}
+		// Files of the same name in different folders are listed each with their metadata
+		result.Scanned = append(result.Scanned, file)
+	}
+}
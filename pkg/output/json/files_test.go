@@ -0,0 +1,75 @@
+package json
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(data, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(data, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	files := []structs.File{
+		structs.ToFile(data, "data.csv", -1, ""),
+		structs.ToFile(filepath.Join(dir, "gone.txt"), "gone.txt", 3, ""),
+	}
+
+	listed := ListFiles(config.Config{}, files, false, nil)
+	if len(listed) != 2 {
+		t.Fatalf("Expected both files to be listed, got %+v", listed)
+	}
+	expected := FileMetadata{Path: data, Size: 5, Modified: "2024-05-01T12:00:00Z"}
+	if listed[0].Filename != "data.csv" || *listed[0].Metadata != expected {
+		t.Errorf("Expected %+v, got %+v", expected, listed[0].Metadata)
+	}
+	if gone := listed[1].Metadata; gone.Size != 3 || gone.Modified != "" || gone.Error == "" {
+		t.Errorf("Expected the missing file to be listed with its error, got %+v", gone)
+	}
+
+	hashed := ListFiles(config.Config{}, files[:1], true, nil)
+	if sum := hashed[0].Metadata.SHA256; sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Expected the SHA-256 of the content, got %q", sum)
+	}
+}
+
+func TestFormatResults_Files(t *testing.T) {
+	formatter := NewJSONFormatter()
+	formatter.Files = []ScannedFile{
+		{Filename: "data.csv", Issues: []CheckSummary{}, Metadata: &FileMetadata{Path: "a/data.csv", Size: 5}},
+		{Filename: "clean.txt", Issues: []CheckSummary{}, Metadata: &FileMetadata{Path: "clean.txt", Size: 0}},
+		{Filename: "data.csv", Issues: []CheckSummary{}, Metadata: &FileMetadata{Path: "b/data.csv", Size: 7}},
+	}
+	messages := []structs.Message{
+		{Content: "Keyword found", Source: structs.File{Path: "a/data.csv", Name: "data.csv"}, TestName: "IsFreeOfKeywords"},
+	}
+	result := formatter.Result("test", "LocalCollector", messages, 3, nil)
+
+	if len(result.Scanned) != 3 {
+		t.Fatalf("Expected the files without issues to be added, got %+v", result.Scanned)
+	}
+	if clean := result.Scanned[0]; clean.Filename != "clean.txt" || len(clean.Issues) != 0 || clean.Metadata.Path != "clean.txt" {
+		t.Errorf("Unexpected scanned file %+v", clean)
+	}
+	if data := result.Scanned[1]; len(data.Issues) != 1 || data.Metadata.Path != "a/data.csv" {
+		t.Errorf("Expected the metadata to be added to the file with issues, got %+v", data)
+	}
+	if other := result.Scanned[2]; len(other.Issues) != 0 || other.Metadata.Path != "b/data.csv" {
+		t.Errorf("Expected the other file of the same name to be listed on its own, got %+v", other)
+	}
+
+	plain := NewJSONFormatter().Result("test", "LocalCollector", messages, 1, nil)
+	if len(plain.Scanned) != 1 || plain.Scanned[0].Metadata != nil {
+		t.Errorf("Expected no metadata without files, got %+v", plain.Scanned)
+	}
+}
@@ -3,16 +3,20 @@ package json
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
+	"sort"
 	"time"
 
+	"github.com/eawag-rdm/pc/pkg/checks"
 	"github.com/eawag-rdm/pc/pkg/structs"
 	"github.com/eawag-rdm/pc/pkg/output"
 )
 
 // ScanResult represents the complete output of a package check scan
 type ScanResult struct {
+	SchemaVersion          string           `json:"schema_version"`
 	Timestamp              string           `json:"timestamp"`
+	Location               string           `json:"location,omitempty"`  // Folder or CKAN package scanned, empty for merged reports
+	Locations              []MergedLocation `json:"locations,omitempty"` // Scans a merged report was combined from
 	Scanned                []ScannedFile    `json:"scanned"`
 	Skipped                []SkippedFile    `json:"skipped"`
 	DetailsSubjectFocused  []SubjectDetails `json:"details_subject_focused"`
@@ -20,20 +24,26 @@ type ScanResult struct {
 	PDFFiles               []string         `json:"pdf_files"`
 	Errors                 []output.LogMessage     `json:"errors"`
 	Warnings               []output.LogMessage     `json:"warnings"`
+	Suppressed             *Suppressed             `json:"suppressed,omitempty"` // Findings hidden by the baseline
+	Archives               []ArchiveListing        `json:"archives,omitempty"`   // Inventory of the archives, see ListArchives
+}
+
+// Suppressed counts the findings hidden by the baseline
+type Suppressed struct {
+	Accepted       int `json:"accepted"`
+	FalsePositives int `json:"false_positives"`
 }
 
 // ScannedFile represents a file that was scanned with summary of issues
 type ScannedFile struct {
 	Filename string              `json:"filename"`
 	Issues   []CheckSummary      `json:"issues"`
+	Location string              `json:"location,omitempty"` // Location the file was scanned in, in merged reports
+	Metadata *FileMetadata       `json:"metadata,omitempty"` // Size, modification time and hash, with fileMetadata, see ListFiles
 }
 
-// SkippedFile represents a file that was skipped during scanning
-type SkippedFile struct {
-	Filename string `json:"filename"`
-	Path     string `json:"path"`
-	Reason   string `json:"reason"`
-}
+// SkippedFile represents a file that was skipped during scanning, recorded by output.SkippedFiles
+type SkippedFile = output.SkippedFile
 
 // SubjectDetails represents detailed issues for a specific subject
 type SubjectDetails struct {
@@ -41,12 +51,15 @@ type SubjectDetails struct {
 	Path        string       `json:"path"`
 	ArchiveName string       `json:"archive_name,omitempty"` // Parent archive if file is inside archive
 	Issues      []CheckIssue `json:"issues"`
+	Location    string       `json:"location,omitempty"` // Location the subject was scanned in, in merged reports
 }
 
 // CheckDetails represents detailed issues for a specific check
 type CheckDetails struct {
-	Checkname string         `json:"checkname"`
-	Issues    []SubjectIssue `json:"issues"`
+	Checkname  string         `json:"checkname"`
+	Severity   string         `json:"severity,omitempty"`    // Severity of the check: error, warning or info
+	ReportOnly bool           `json:"report_only,omitempty"` // Issues are informational only, see checks.IsReportOnly
+	Issues     []SubjectIssue `json:"issues"`
 }
 
 // CheckSummary represents a summary of issues for a check within a file
@@ -57,22 +70,39 @@ type CheckSummary struct {
 
 // CheckIssue represents an issue from a specific check within a file
 type CheckIssue struct {
+	ID        string `json:"id,omitempty"` // Stable ID of the finding, see structs.IssueID
 	Checkname string `json:"checkname"`
 	Message   string `json:"message"`
+	Line      int    `json:"line,omitempty"`    // Line of the file the issue was found on
+	Snippet   string `json:"snippet,omitempty"` // Text around the finding, marked »like this«
+	Count     int    `json:"count,omitempty"`   // Number of identical findings merged into this one
+	Lines     []int  `json:"lines,omitempty"`   // Lines of the merged findings
 }
 
 // SubjectIssue represents an issue in a specific subject for a check
 type SubjectIssue struct {
+	ID          string `json:"id,omitempty"` // Stable ID of the finding, see structs.IssueID
 	Subject     string `json:"subject"`
 	Path        string `json:"path"`
 	ArchiveName string `json:"archive_name,omitempty"` // Parent archive if file is inside archive
 	Message     string `json:"message"`
+	Line        int    `json:"line,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+	Count       int    `json:"count,omitempty"`
+	Lines       []int  `json:"lines,omitempty"`
+	Location    string `json:"location,omitempty"` // Location the issue was found in, in merged reports
 }
 
 // Using LogMessage from output package
 
 // JSONFormatter handles conversion of results to JSON
-type JSONFormatter struct {}
+type JSONFormatter struct {
+	Suppressed Suppressed       // Findings hidden by the baseline, reported if there are any
+	Archives   []ArchiveListing // Inventory of the archives, reported if set
+	Files      []ScannedFile    // Collected files with their metadata, added to the scanned files if set
+	Skipped    []SkippedFile    // Files the scan did not check, see config.SkippedFiles
+	Root       string           // Folder of the scanned package, the IDs of the issues hash the paths relative to it
+}
 
 // NewJSONFormatter creates a new JSON formatter
 func NewJSONFormatter() *JSONFormatter {
@@ -82,8 +112,16 @@ func NewJSONFormatter() *JSONFormatter {
 
 // FormatResults converts messages to structured JSON output
 func (jf *JSONFormatter) FormatResults(location, collector string, messages []structs.Message, totalFiles int, pdfFiles []string) (string, error) {
-	result := ScanResult{
+	return jf.Result(location, collector, messages, totalFiles, pdfFiles).JSON()
+}
+
+// Result converts messages to the report FormatResults writes as JSON, for the formatters and the
+// TUI to use without decoding the JSON again
+func (jf *JSONFormatter) Result(location, collector string, messages []structs.Message, totalFiles int, pdfFiles []string) *ScanResult {
+	result := &ScanResult{
+		SchemaVersion:         SchemaVersion,
 		Timestamp:             time.Now().UTC().Format(time.RFC3339),
+		Location:              location,
 		Scanned:               make([]ScannedFile, 0),
 		Skipped:               make([]SkippedFile, 0),
 		DetailsSubjectFocused: make([]SubjectDetails, 0),
@@ -94,93 +132,39 @@ func (jf *JSONFormatter) FormatResults(location, collector string, messages []st
 	}
 
 	// Process messages into the new structured format
-	result.processMessages(messages)
+	result.processMessages(messages, jf.Root)
+	result.addFiles(jf.Files)
 
-	// Separate logger messages by level and extract skipped files
-	logMessages := output.GlobalLogger.GetMessages()
-	for _, msg := range logMessages {
+	// Separate logger messages by level
+	for _, msg := range output.GlobalLogger.GetMessages() {
 		switch msg.Level {
 		case "error":
 			result.Errors = append(result.Errors, msg)
 		case "warning":
 			result.Warnings = append(result.Warnings, msg)
-		case "info":
-			// Check if this is a binary file skip message
-			if strings.Contains(msg.Message, "Not checking contents of file") && strings.Contains(msg.Message, "binary") {
-				// Extract filename and path from message like "Not checking contents of file: 'filename' (path: 'filepath'). The file seems to be binary."
-				
-				// Extract filename (first quoted string)
-				start := strings.Index(msg.Message, "'")
-				if start != -1 {
-					end := strings.Index(msg.Message[start+1:], "'")
-					if end != -1 {
-						filename := msg.Message[start+1 : start+1+end]
-						
-						// Extract path (second quoted string after "path: '")
-						pathStart := strings.Index(msg.Message, "(path: '")
-						var path string
-						if pathStart != -1 {
-							pathStart += len("(path: '")
-							pathEnd := strings.Index(msg.Message[pathStart:], "'")
-							if pathEnd != -1 {
-								path = msg.Message[pathStart : pathStart+pathEnd]
-							}
-						}
-						
-						// Fallback to filename if path not found
-						if path == "" {
-							path = filename
-						}
-						
-						result.Skipped = append(result.Skipped, SkippedFile{
-							Filename: filename,
-							Path:     path,
-							Reason:   "Binary file detected",
-						})
-					}
-				}
-			} else if strings.Contains(msg.Message, "Skipping content scan of file") && strings.Contains(msg.Message, "exceeds maximum") {
-				// Check if this is a file size limit skip message
-				// Extract filename and path from message like "Skipping content scan of file: 'filename' (path: 'filepath'). File size (X bytes) exceeds maximum (Y bytes)."
-				
-				// Extract filename (first quoted string)
-				start := strings.Index(msg.Message, "'")
-				if start != -1 {
-					end := strings.Index(msg.Message[start+1:], "'")
-					if end != -1 {
-						filename := msg.Message[start+1 : start+1+end]
-						
-						// Extract path (second quoted string after "path: '")
-						pathStart := strings.Index(msg.Message, "(path: '")
-						var path string
-						if pathStart != -1 {
-							pathStart += len("(path: '")
-							pathEnd := strings.Index(msg.Message[pathStart:], "'")
-							if pathEnd != -1 {
-								path = msg.Message[pathStart : pathStart+pathEnd]
-							}
-						}
-						
-						// Fallback to filename if path not found
-						if path == "" {
-							path = filename
-						}
-						
-						result.Skipped = append(result.Skipped, SkippedFile{
-							Filename: filename,
-							Path:     path,
-							Reason:   "File too large for content scanning",
-						})
-					}
-				}
-			}
 		}
 	}
+	result.Skipped = append(result.Skipped, jf.Skipped...)
 
 	// Add PDF files passed from caller
-	result.PDFFiles = pdfFiles
+	result.PDFFiles = append(result.PDFFiles, pdfFiles...)
 
-	// Generate JSON
+	if jf.Archives != nil {
+		result.Archives = append([]ArchiveListing{}, jf.Archives...)
+	}
+
+	result.sort()
+
+	if jf.Suppressed != (Suppressed{}) {
+		suppressed := jf.Suppressed
+		result.Suppressed = &suppressed
+	}
+
+	return result
+}
+
+// JSON encodes the report as written by -json
+func (result *ScanResult) JSON() (string, error) {
 	jsonBytes, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal JSON: %w", err)
@@ -189,6 +173,32 @@ func (jf *JSONFormatter) FormatResults(location, collector string, messages []st
 	return string(jsonBytes), nil
 }
 
+// Parse decodes a JSON report written by FormatResults, also of earlier schema versions
+func Parse(data []byte) (*ScanResult, error) {
+	var result ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// issueID returns the stable ID of an issue of the package scanned at root; issues of the whole
+// package are identified by their subject
+func issueID(root, checkname, subject, path, archiveName, message string) string {
+	if path == "" {
+		return structs.IssueID(checkname, subject, archiveName, message)
+	}
+	return structs.IssueID(checkname, structs.RelativePath(root, path), archiveName, message)
+}
+
+// IssueID returns the stable ID of the issue of the check, also for reports written before issues had IDs
+func (issue SubjectIssue) IssueID(checkname string) string {
+	if issue.ID != "" {
+		return issue.ID
+	}
+	return issueID("", checkname, issue.Subject, issue.Path, issue.ArchiveName, issue.Message)
+}
+
 // subjectKey creates a unique key for a subject considering archive context
 func subjectKey(displayName, archiveName string) string {
 	if archiveName != "" {
@@ -197,8 +207,22 @@ func subjectKey(displayName, archiveName string) string {
 	return displayName
 }
 
-// processMessages analyzes messages and creates the new structured output
-func (result *ScanResult) processMessages(messages []structs.Message) {
+// describeSource returns the display name, path and archive of the source of a message;
+// issues of the whole package have the subject "repository", those of its CKAN metadata the
+// subject "metadata"
+func describeSource(source structs.Source) (displayName, filePath, archiveName string) {
+	switch source := source.(type) {
+	case structs.File:
+		return source.GetDisplayName(), source.Path, source.ArchiveName
+	case structs.Metadata:
+		return "metadata", "", ""
+	}
+	return "repository", "", ""
+}
+
+// processMessages analyzes messages of the package scanned at root and creates the new structured
+// output
+func (result *ScanResult) processMessages(messages []structs.Message, root string) {
 	// Maps to organize data
 	fileIssueMap := make(map[string]map[string]int)         // subject_key -> checkname -> count (only for files)
 	subjectDetailMap := make(map[string][]CheckIssue)       // subject_key -> []CheckIssue
@@ -214,41 +238,44 @@ func (result *ScanResult) processMessages(messages []structs.Message) {
 		}
 
 		// Determine subject and path
-		var subject, displayName, filePath, archiveName string
-		if file, isFile := msg.Source.(structs.File); isFile {
-			displayName = file.GetDisplayName()
-			filePath = file.Path
-			archiveName = file.ArchiveName
-			subject = subjectKey(displayName, archiveName)
-
+		displayName, filePath, archiveName := describeSource(msg.Source)
+		subject := subjectKey(displayName, archiveName)
+		if _, isFile := msg.Source.(structs.File); isFile {
 			// Only track scanned files for actual files, not repository
 			if fileIssueMap[subject] == nil {
 				fileIssueMap[subject] = make(map[string]int)
 			}
 			fileIssueMap[subject][testName]++
-		} else {
-			subject = "repository"
-			displayName = "repository"
-			filePath = ""
-			archiveName = ""
 		}
 
 		subjectPathMap[subject] = filePath
 		subjectArchiveMap[subject] = archiveName
 		subjectDisplayMap[subject] = displayName
 
+		id := issueID(root, testName, displayName, filePath, archiveName, msg.Content)
+
 		// Add to subject-focused details
 		subjectDetailMap[subject] = append(subjectDetailMap[subject], CheckIssue{
+			ID:        id,
 			Checkname: testName,
 			Message:   msg.Content,
+			Line:      msg.Line,
+			Snippet:   msg.Snippet,
+			Count:     msg.Count,
+			Lines:     msg.Lines,
 		})
 
 		// Add to check-focused details
 		checkDetailMap[testName] = append(checkDetailMap[testName], SubjectIssue{
+			ID:          id,
 			Subject:     displayName,
 			Path:        filePath,
 			ArchiveName: archiveName,
 			Message:     msg.Content,
+			Line:        msg.Line,
+			Snippet:     msg.Snippet,
+			Count:       msg.Count,
+			Lines:       msg.Lines,
 		})
 	}
 
@@ -290,9 +317,121 @@ func (result *ScanResult) processMessages(messages []structs.Message) {
 	// Build check-focused details
 	for checkname, issues := range checkDetailMap {
 		result.DetailsCheckFocused = append(result.DetailsCheckFocused, CheckDetails{
-			Checkname: checkname,
-			Issues:    issues,
+			Checkname:  checkname,
+			Severity:   string(checks.SeverityOf(checkname)),
+			ReportOnly: checks.IsReportOnly(checkname),
+			Issues:     issues,
 		})
 	}
 }
 
+// sort orders the files, subjects, checks and issues of the result, so that scans finding the
+// same issues write the same report regardless of the order the checks ran in. Entries of merged
+// reports stay in the order of their locations.
+func (result *ScanResult) sort() {
+	rank := make(map[string]int)
+	for i, location := range result.Locations {
+		if _, ok := rank[location.Location]; !ok {
+			rank[location.Location] = i
+		}
+	}
+	locationLess := func(a, b string) bool {
+		ra, aok := rank[a]
+		rb, bok := rank[b]
+		if aok && bok {
+			return ra < rb
+		}
+		return a < b
+	}
+
+	sort.SliceStable(result.Scanned, func(i, j int) bool {
+		a, b := result.Scanned[i], result.Scanned[j]
+		if a.Location != b.Location {
+			return locationLess(a.Location, b.Location)
+		}
+		return a.Filename < b.Filename
+	})
+	for _, scanned := range result.Scanned {
+		sort.SliceStable(scanned.Issues, func(i, j int) bool { return scanned.Issues[i].Checkname < scanned.Issues[j].Checkname })
+	}
+
+	sort.SliceStable(result.Skipped, func(i, j int) bool {
+		a, b := result.Skipped[i], result.Skipped[j]
+		if a.Location != b.Location {
+			return locationLess(a.Location, b.Location)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Filename < b.Filename
+	})
+
+	sort.SliceStable(result.DetailsSubjectFocused, func(i, j int) bool {
+		a, b := result.DetailsSubjectFocused[i], result.DetailsSubjectFocused[j]
+		if a.Location != b.Location {
+			return locationLess(a.Location, b.Location)
+		}
+		if (a.Path == "") != (b.Path == "") {
+			// The repository and the metadata follow the files
+			return b.Path == ""
+		}
+		if a.ArchiveName != b.ArchiveName {
+			return a.ArchiveName < b.ArchiveName
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Subject < b.Subject
+	})
+	for _, subject := range result.DetailsSubjectFocused {
+		sort.SliceStable(subject.Issues, func(i, j int) bool {
+			a, b := subject.Issues[i], subject.Issues[j]
+			if a.Checkname != b.Checkname {
+				return a.Checkname < b.Checkname
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Message < b.Message
+		})
+	}
+
+	sort.SliceStable(result.DetailsCheckFocused, func(i, j int) bool {
+		return result.DetailsCheckFocused[i].Checkname < result.DetailsCheckFocused[j].Checkname
+	})
+	for _, check := range result.DetailsCheckFocused {
+		sort.SliceStable(check.Issues, func(i, j int) bool {
+			a, b := check.Issues[i], check.Issues[j]
+			if a.Location != b.Location {
+				return locationLess(a.Location, b.Location)
+			}
+			if (a.Path == "") != (b.Path == "") {
+				// The repository and the metadata follow the files
+				return b.Path == ""
+			}
+			if a.ArchiveName != b.ArchiveName {
+				return a.ArchiveName < b.ArchiveName
+			}
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			if a.Subject != b.Subject {
+				return a.Subject < b.Subject
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Message < b.Message
+		})
+	}
+
+	sort.Strings(result.PDFFiles)
+
+	sort.SliceStable(result.Archives, func(i, j int) bool {
+		a, b := result.Archives[i], result.Archives[j]
+		if a.Location != b.Location {
+			return locationLess(a.Location, b.Location)
+		}
+		return a.Path < b.Path
+	})
+}
@@ -2,9 +2,13 @@ package json
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
@@ -158,6 +162,29 @@ func TestFormatResults_RepositoryMessage(t *testing.T) {
 	}
 }
 
+func TestFormatResults_MetadataMessage(t *testing.T) {
+	messages := []structs.Message{
+		{Content: "The package has no license.", Source: structs.Metadata{Package: "lake-ice"}, TestName: "HasLicense"},
+	}
+	result, err := NewJSONFormatter().FormatResults("lake-ice", "CkanCollector", messages, 0, []string{})
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var scanResult ScanResult
+	if err := json.Unmarshal([]byte(result), &scanResult); err != nil {
+		t.Fatalf("Result is not valid JSON: %v", err)
+	}
+	if len(scanResult.Scanned) != 0 {
+		t.Errorf("Expected metadata issues not to create scanned files, got %+v", scanResult.Scanned)
+	}
+	if len(scanResult.DetailsSubjectFocused) != 1 || scanResult.DetailsSubjectFocused[0].Subject != "metadata" {
+		t.Errorf("Expected the subject 'metadata', got %+v", scanResult.DetailsSubjectFocused)
+	}
+	if len(scanResult.DetailsCheckFocused) != 1 || scanResult.DetailsCheckFocused[0].Severity != "error" {
+		t.Errorf("Expected HasLicense with its severity, got %+v", scanResult.DetailsCheckFocused)
+	}
+}
+
 func TestProcessMessages(t *testing.T) {
 	result := &ScanResult{}
 	
@@ -179,7 +206,7 @@ func TestProcessMessages(t *testing.T) {
 		},
 	}
 
-	result.processMessages(messages)
+	result.processMessages(messages, "")
 
 	// Verify scanned files
 	if len(result.Scanned) != 1 {
@@ -258,4 +285,232 @@ func TestJSONStructureIntegrity(t *testing.T) {
 	if !strings.Contains(result, "warnings") {
 		t.Error("JSON missing warnings field")
 	}
-}
\ No newline at end of file
+}
+func TestFormatResults_Location(t *testing.T) {
+	formatter := NewJSONFormatter()
+	messages := []structs.Message{
+		{
+			Content:  "Security credentials detected 'password'",
+			Source:   structs.File{Name: "settings.py", Path: "/repo/settings.py"},
+			TestName: "IsFreeOfKeywords",
+			Line:     3,
+			Snippet:  "»password« = 'x'",
+		},
+	}
+
+	result, err := formatter.FormatResults("/repo", "LocalCollector", messages, 1, []string{})
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var scanResult ScanResult
+	if err := json.Unmarshal([]byte(result), &scanResult); err != nil {
+		t.Fatalf("Result is not valid JSON: %v", err)
+	}
+
+	issue := scanResult.DetailsSubjectFocused[0].Issues[0]
+	if issue.Line != 3 || issue.Snippet != "»password« = 'x'" {
+		t.Errorf("Expected line and snippet in subject details, got %+v", issue)
+	}
+	checkIssue := scanResult.DetailsCheckFocused[0].Issues[0]
+	if checkIssue.Line != 3 || checkIssue.Snippet != "»password« = 'x'" {
+		t.Errorf("Expected line and snippet in check details, got %+v", checkIssue)
+	}
+}
+
+func TestFormatResults_Severity(t *testing.T) {
+	formatter := NewJSONFormatter()
+	file := structs.File{Name: "settings.py", Path: "/repo/settings.py"}
+	messages := []structs.Message{
+		{Content: "Security credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords"},
+		{Content: "Large file", Source: file, TestName: "MyRule"},
+	}
+
+	result, err := formatter.FormatResults("/repo", "LocalCollector", messages, 1, []string{})
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var scanResult ScanResult
+	if err := json.Unmarshal([]byte(result), &scanResult); err != nil {
+		t.Fatalf("Result is not valid JSON: %v", err)
+	}
+
+	severities := map[string]string{}
+	for _, check := range scanResult.DetailsCheckFocused {
+		severities[check.Checkname] = check.Severity
+	}
+	if severities["IsFreeOfKeywords"] != "error" || severities["MyRule"] != "warning" {
+		t.Errorf("Unexpected severities %v", severities)
+	}
+}
+
+func TestFormatResults_ReportOnly(t *testing.T) {
+	defer checks.SetSeverities(config.Config{})
+	checks.SetSeverities(config.Config{Tests: map[string]*config.TestConfig{"MyRule": {ReportOnly: true}}})
+
+	formatter := NewJSONFormatter()
+	file := structs.File{Name: "settings.py", Path: "/repo/settings.py"}
+	messages := []structs.Message{
+		{Content: "Security credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords"},
+		{Content: "Large file", Source: file, TestName: "MyRule"},
+	}
+
+	result, err := formatter.FormatResults("/repo", "LocalCollector", messages, 1, []string{})
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var scanResult ScanResult
+	if err := json.Unmarshal([]byte(result), &scanResult); err != nil {
+		t.Fatalf("Result is not valid JSON: %v", err)
+	}
+
+	reportOnly := map[string]bool{}
+	for _, check := range scanResult.DetailsCheckFocused {
+		reportOnly[check.Checkname] = check.ReportOnly
+	}
+	if reportOnly["IsFreeOfKeywords"] || !reportOnly["MyRule"] {
+		t.Errorf("Unexpected report_only flags %v", reportOnly)
+	}
+}
+
+func TestResult_JSON(t *testing.T) {
+	formatter := NewJSONFormatter()
+	file := structs.File{Name: "settings.py", Path: "/repo/settings.py"}
+	messages := []structs.Message{
+		{Content: "Security credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords"},
+		{Content: "No README", Source: structs.Repository{}, TestName: "HasReadme"},
+	}
+
+	result := formatter.Result("/repo", "LocalCollector", messages, 1, []string{"/repo/report.pdf"})
+	if len(result.DetailsCheckFocused) != 2 || len(result.DetailsSubjectFocused) != 2 {
+		t.Fatalf("Unexpected report %+v", result)
+	}
+
+	encoded, err := result.JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	parsed, err := Parse([]byte(encoded))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, result) {
+		t.Errorf("Expected the parsed report to equal the formatted one\ngot:  %+v\nwant: %+v", parsed, result)
+	}
+
+	if _, err := Parse([]byte("{not json")); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestFormatResults_Suppressed(t *testing.T) {
+	formatter := NewJSONFormatter()
+	result, err := formatter.FormatResults("test", "LocalCollector", []structs.Message{}, 0, nil)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	if strings.Contains(result, `"suppressed"`) {
+		t.Errorf("Expected no suppressed findings without a baseline, got:\n%s", result)
+	}
+
+	formatter.Suppressed = Suppressed{Accepted: 1, FalsePositives: 2}
+	result, err = formatter.FormatResults("test", "LocalCollector", []structs.Message{}, 0, nil)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var parsed ScanResult
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Suppressed == nil || *parsed.Suppressed != formatter.Suppressed {
+		t.Errorf("Expected %+v suppressed, got %+v", formatter.Suppressed, parsed.Suppressed)
+	}
+}
+
+func TestFormatResults_Skipped(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	timeout := output.SkippedFile{Filename: "huge.log", Path: "/data/huge.log", Code: output.SkipTimeout, Reason: "Checking it took longer than 1s."}
+	member := output.SkippedFile{Filename: "image.png", Path: "/data/data.zip", ArchiveName: "data.zip", Code: output.SkipBinary, Reason: "The archive member seems to be binary."}
+	// A file timing out in the file checks and in the archive content checks is listed once
+	skipped := output.NewSkippedFiles()
+	skipped.Add(timeout)
+	skipped.Add(member)
+	skipped.Add(timeout)
+	output.GlobalLogger.Info("Archive memory usage: 10/100 bytes (10 files processed)")
+
+	formatter := NewJSONFormatter()
+	formatter.Skipped = skipped.Files()
+	result, err := formatter.FormatResults("test", "LocalCollector", []structs.Message{}, 0, nil)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var parsed ScanResult
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	// Skipped files are sorted by path
+	expected := []SkippedFile{member, timeout}
+	if !reflect.DeepEqual(parsed.Skipped, expected) {
+		t.Errorf("Expected %+v skipped, got %+v", expected, parsed.Skipped)
+	}
+	if !strings.Contains(result, `"code": "binary"`) || !strings.Contains(result, `"archive_name": "data.zip"`) {
+		t.Errorf("Expected the code and archive of skipped files in the report, got %s", result)
+	}
+}
+
+func TestFormatResults_Order(t *testing.T) {
+	data := structs.File{Name: "data.csv", Path: "/data/data.csv"}
+	log := structs.File{Name: "run.log", Path: "/data/run.log"}
+	messages := []structs.Message{
+		{Content: "Credentials detected 'password'", Source: log, TestName: "IsFreeOfKeywords", Line: 9},
+		{Content: "No ReadMe file in repository.", Source: structs.Repository{}, TestName: "HasReadme"},
+		{Content: "Credentials detected 'token'", Source: log, TestName: "IsFreeOfKeywords", Line: 2},
+		{Content: "File name contains spaces", Source: data, TestName: "HasNoWhiteSpace"},
+		{Content: "Credentials detected 'secret'", Source: data, TestName: "IsFreeOfKeywords", Line: 4},
+	}
+	reversed := make([]structs.Message, len(messages))
+	for i, msg := range messages {
+		reversed[len(messages)-1-i] = msg
+	}
+
+	format := func(messages []structs.Message) ScanResult {
+		report, err := NewJSONFormatter().FormatResults("test", "LocalCollector", messages, 2, []string{"/data/b.pdf", "/data/a.pdf"})
+		if err != nil {
+			t.Fatalf("FormatResults failed: %v", err)
+		}
+		var result ScanResult
+		if err := json.Unmarshal([]byte(report), &result); err != nil {
+			t.Fatal(err)
+		}
+		result.Timestamp = ""
+		return result
+	}
+	result := format(messages)
+	if !reflect.DeepEqual(result, format(reversed)) {
+		t.Error("Expected the same report regardless of the order of the messages")
+	}
+
+	var checknames []string
+	for _, check := range result.DetailsCheckFocused {
+		checknames = append(checknames, check.Checkname)
+	}
+	if !reflect.DeepEqual(checknames, []string{"HasNoWhiteSpace", "HasReadme", "IsFreeOfKeywords"}) {
+		t.Errorf("Expected the checks sorted by name, got %v", checknames)
+	}
+	keywords := result.DetailsCheckFocused[2].Issues
+	if keywords[0].Path != "/data/data.csv" || keywords[1].Line != 2 || keywords[2].Line != 9 {
+		t.Errorf("Expected the issues sorted by path and line, got %+v", keywords)
+	}
+	if keywords[2].ID != messages[0].ID("") || result.DetailsSubjectFocused[1].Issues[0].ID != messages[2].ID("") {
+		t.Errorf("Expected the stable IDs of the findings, got %+v", result)
+	}
+	if result.DetailsSubjectFocused[0].Subject != "data.csv" || result.DetailsSubjectFocused[2].Subject != "repository" {
+		t.Errorf("Expected the subjects sorted by path and the repository last, got %+v", result.DetailsSubjectFocused)
+	}
+	if !reflect.DeepEqual(result.PDFFiles, []string{"/data/a.pdf", "/data/b.pdf"}) {
+		t.Errorf("Expected the PDF files sorted, got %v", result.PDFFiles)
+	}
+}
@@ -0,0 +1,124 @@
+package json
+
+import (
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+)
+
+// MergedLocation is a scan combined into a merged report
+type MergedLocation struct {
+	Location  string `json:"location"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Source is a report to merge with the location it was made of
+type Source struct {
+	Location string
+	Result   ScanResult
+}
+
+// Merge combines the reports of several locations, e.g. one per CKAN package, into one. Every
+// file, issue, skipped file and log message keeps the location it was found in, and the merged
+// report lists the scans it was combined from. Merged reports can be merged again. Issues of
+// reports written before issues had IDs are given theirs.
+func Merge(sources []Source) ScanResult {
+	merged := ScanResult{
+		SchemaVersion:         SchemaVersion,
+		Scanned:               make([]ScannedFile, 0),
+		Skipped:               make([]SkippedFile, 0),
+		DetailsSubjectFocused: make([]SubjectDetails, 0),
+		DetailsCheckFocused:   make([]CheckDetails, 0),
+		PDFFiles:              make([]string, 0),
+		Errors:                make([]output.LogMessage, 0),
+		Warnings:              make([]output.LogMessage, 0),
+		Locations:             make([]MergedLocation, 0),
+	}
+	checkIndex := make(map[string]int)
+	var suppressed Suppressed
+	var latest time.Time
+
+	for _, source := range sources {
+		result := source.Result
+		location := source.Location
+		if result.Location != "" {
+			location = result.Location
+		}
+		// Entries of merged reports already name their location
+		locate := func(entry string) string {
+			if entry != "" {
+				return entry
+			}
+			return location
+		}
+
+		if len(result.Locations) > 0 {
+			merged.Locations = append(merged.Locations, result.Locations...)
+		} else {
+			merged.Locations = append(merged.Locations, MergedLocation{Location: location, Timestamp: result.Timestamp})
+		}
+		if timestamp, err := time.Parse(time.RFC3339, result.Timestamp); err == nil && timestamp.After(latest) {
+			latest = timestamp
+			merged.Timestamp = result.Timestamp
+		}
+
+		for _, scanned := range result.Scanned {
+			scanned.Location = locate(scanned.Location)
+			merged.Scanned = append(merged.Scanned, scanned)
+		}
+		for _, skipped := range result.Skipped {
+			skipped.Location = locate(skipped.Location)
+			merged.Skipped = append(merged.Skipped, skipped)
+		}
+		for _, subject := range result.DetailsSubjectFocused {
+			subject.Location = locate(subject.Location)
+			subject.Issues = append([]CheckIssue(nil), subject.Issues...)
+			for j, issue := range subject.Issues {
+				if issue.ID == "" {
+					subject.Issues[j].ID = issueID("", issue.Checkname, subject.Subject, subject.Path, subject.ArchiveName, issue.Message)
+				}
+			}
+			merged.DetailsSubjectFocused = append(merged.DetailsSubjectFocused, subject)
+		}
+		for _, check := range result.DetailsCheckFocused {
+			i, ok := checkIndex[check.Checkname]
+			if !ok {
+				i = len(merged.DetailsCheckFocused)
+				checkIndex[check.Checkname] = i
+				merged.DetailsCheckFocused = append(merged.DetailsCheckFocused, CheckDetails{Checkname: check.Checkname, Issues: []SubjectIssue{}})
+			}
+			if merged.DetailsCheckFocused[i].Severity == "" {
+				merged.DetailsCheckFocused[i].Severity = check.Severity
+			}
+			merged.DetailsCheckFocused[i].ReportOnly = merged.DetailsCheckFocused[i].ReportOnly || check.ReportOnly
+			for _, issue := range check.Issues {
+				issue.Location = locate(issue.Location)
+				issue.ID = issue.IssueID(check.Checkname)
+				merged.DetailsCheckFocused[i].Issues = append(merged.DetailsCheckFocused[i].Issues, issue)
+			}
+		}
+		merged.PDFFiles = append(merged.PDFFiles, result.PDFFiles...)
+		for _, message := range result.Errors {
+			message.Location = locate(message.Location)
+			merged.Errors = append(merged.Errors, message)
+		}
+		for _, message := range result.Warnings {
+			message.Location = locate(message.Location)
+			merged.Warnings = append(merged.Warnings, message)
+		}
+		for _, listing := range result.Archives {
+			listing.Location = locate(listing.Location)
+			merged.Archives = append(merged.Archives, listing)
+		}
+		if result.Suppressed != nil {
+			suppressed.Accepted += result.Suppressed.Accepted
+			suppressed.FalsePositives += result.Suppressed.FalsePositives
+		}
+	}
+
+	if suppressed != (Suppressed{}) {
+		merged.Suppressed = &suppressed
+	}
+	merged.sort()
+	return merged
+}
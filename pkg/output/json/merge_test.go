@@ -0,0 +1,77 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+)
+
+func TestMerge(t *testing.T) {
+	lake := ScanResult{
+		Timestamp: "2024-01-14T10:30:00Z",
+		Location:  "lake-ice",
+		Scanned:   []ScannedFile{{Filename: "my file.txt", Issues: []CheckSummary{{Checkname: "HasNoWhiteSpace", IssueCount: 1}}}},
+		DetailsSubjectFocused: []SubjectDetails{{Subject: "my file.txt", Path: "/lake/my file.txt", Issues: []CheckIssue{
+			{Checkname: "HasNoWhiteSpace", Message: "File name contains spaces"},
+		}}},
+		DetailsCheckFocused: []CheckDetails{{Checkname: "HasNoWhiteSpace", Severity: "warning", Issues: []SubjectIssue{
+			{Subject: "my file.txt", Path: "/lake/my file.txt", Message: "File name contains spaces"},
+		}}},
+		Suppressed: &Suppressed{Accepted: 1},
+		Archives:   []ArchiveListing{{Archive: "ice.zip", Path: "/lake/ice.zip", Files: 1, Members: []ArchiveMember{{Name: "ice.csv", Size: 12}}}},
+	}
+	// Reports written before the location was recorded take the one they are merged with
+	river := ScanResult{
+		Timestamp: "2024-02-01T08:00:00Z",
+		Skipped:   []SkippedFile{{Filename: "big.nc", Path: "/river/big.nc", Code: output.SkipTooLarge, Reason: "too large"}},
+		DetailsCheckFocused: []CheckDetails{
+			{Checkname: "HasNoWhiteSpace", Severity: "warning", Issues: []SubjectIssue{{Subject: "a b.csv", Path: "/river/a b.csv", Message: "File name contains spaces"}}},
+			{Checkname: "HasReadme", Severity: "info", ReportOnly: true, Issues: []SubjectIssue{{Subject: "repository", Message: "No readme file found"}}},
+		},
+		Warnings:   []output.LogMessage{{Level: "warning", Message: "slow download"}},
+		Suppressed: &Suppressed{FalsePositives: 2},
+	}
+
+	merged := Merge([]Source{{Location: "lake.json", Result: lake}, {Location: "river", Result: river}})
+
+	if merged.SchemaVersion != SchemaVersion || merged.Location != "" || merged.Timestamp != "2024-02-01T08:00:00Z" {
+		t.Errorf("Unexpected header: %q %q %q", merged.SchemaVersion, merged.Location, merged.Timestamp)
+	}
+	if len(merged.Locations) != 2 || merged.Locations[0] != (MergedLocation{"lake-ice", "2024-01-14T10:30:00Z"}) || merged.Locations[1].Location != "river" {
+		t.Errorf("Unexpected locations: %+v", merged.Locations)
+	}
+	if len(merged.DetailsCheckFocused) != 2 {
+		t.Fatalf("Expected the issues of a check to be combined, got %+v", merged.DetailsCheckFocused)
+	}
+	if merged.DetailsCheckFocused[0].ReportOnly || !merged.DetailsCheckFocused[1].ReportOnly {
+		t.Errorf("Expected checks to keep report_only, got %+v", merged.DetailsCheckFocused)
+	}
+	spaces := merged.DetailsCheckFocused[0].Issues
+	if len(spaces) != 2 || spaces[0].Location != "lake-ice" || spaces[1].Location != "river" {
+		t.Errorf("Expected each issue to keep its location, got %+v", spaces)
+	}
+	if spaces[1].ID != issueID("", "HasNoWhiteSpace", "a b.csv", "/river/a b.csv", "", "File name contains spaces") || merged.DetailsSubjectFocused[0].Issues[0].ID == "" {
+		t.Errorf("Expected issues of reports without IDs to be given theirs, got %+v", spaces)
+	}
+	if merged.Scanned[0].Location != "lake-ice" || merged.DetailsSubjectFocused[0].Location != "lake-ice" {
+		t.Errorf("Expected the files to keep their location, got %+v %+v", merged.Scanned, merged.DetailsSubjectFocused)
+	}
+	if merged.Skipped[0].Location != "river" || merged.Warnings[0].Location != "river" {
+		t.Errorf("Expected skipped files and warnings to keep their location, got %+v %+v", merged.Skipped, merged.Warnings)
+	}
+	if len(merged.Archives) != 1 || merged.Archives[0].Location != "lake-ice" {
+		t.Errorf("Expected the archives to keep their location, got %+v", merged.Archives)
+	}
+	if merged.Suppressed == nil || *merged.Suppressed != (Suppressed{Accepted: 1, FalsePositives: 2}) {
+		t.Errorf("Expected the suppressed findings to be summed, got %+v", merged.Suppressed)
+	}
+
+	// Merging a merged report keeps the locations it was combined from
+	again := Merge([]Source{{Location: "all.json", Result: merged}, {Location: "pond", Result: ScanResult{Timestamp: "2023-12-01T00:00:00Z"}}})
+	if len(again.Locations) != 3 || again.Locations[2].Location != "pond" || again.DetailsCheckFocused[0].Issues[1].Location != "river" {
+		t.Errorf("Unexpected merge of a merged report: %+v", again.Locations)
+	}
+	if again.Timestamp != "2024-02-01T08:00:00Z" || again.Suppressed == nil {
+		t.Errorf("Expected the latest timestamp and the suppressed findings to be kept, got %q %+v", again.Timestamp, again.Suppressed)
+	}
+}
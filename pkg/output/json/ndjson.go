@@ -0,0 +1,114 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// Finding is one line of the NDJSON output
+type Finding struct {
+	Type        string `json:"type"` // Always "finding"
+	ID          string `json:"id"`   // Stable ID of the finding, see structs.IssueID
+	Checkname   string `json:"checkname"`
+	Severity    string `json:"severity"`
+	Subject     string `json:"subject"`
+	Path        string `json:"path"`
+	ArchiveName string `json:"archive_name,omitempty"`
+	Message     string `json:"message"`
+	Line        int    `json:"line,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+	Count       int    `json:"count,omitempty"`
+	Lines       []int  `json:"lines,omitempty"`
+}
+
+// StreamSummary is the last line of the NDJSON output
+type StreamSummary struct {
+	Type          string              `json:"type"` // Always "summary"
+	SchemaVersion string              `json:"schema_version"`
+	Timestamp     string              `json:"timestamp"`
+	Location      string              `json:"location"`
+	FilesScanned  int                 `json:"files_scanned"`
+	Findings      int                 `json:"findings"`
+	Errors        []output.LogMessage `json:"errors"`
+	Warnings      []output.LogMessage `json:"warnings"`
+	Skipped       []SkippedFile       `json:"skipped"`
+	Suppressed    *Suppressed         `json:"suppressed,omitempty"`
+}
+
+// NDJSONWriter writes findings as newline-delimited JSON, one object per finding, as they are found
+type NDJSONWriter struct {
+	Suppressed Suppressed    // Findings hidden by the baseline, reported in the summary if there are any
+	Skipped    []SkippedFile // Files the scan did not check, reported in the summary
+	Root       string        // Folder of the scanned package, the IDs of the findings hash the paths relative to it
+	encoder    *json.Encoder
+	findings   int
+}
+
+// NewNDJSONWriter creates a writer of NDJSON to w
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{encoder: json.NewEncoder(w)}
+}
+
+// WriteMessages writes one line per message
+func (nw *NDJSONWriter) WriteMessages(messages []structs.Message) error {
+	for _, msg := range messages {
+		testName := msg.TestName
+		if testName == "" {
+			testName = "Unknown"
+		}
+		displayName, filePath, archiveName := describeSource(msg.Source)
+		finding := Finding{
+			Type:        "finding",
+			ID:          issueID(nw.Root, testName, displayName, filePath, archiveName, msg.Content),
+			Checkname:   testName,
+			Severity:    string(checks.SeverityOf(testName)),
+			Subject:     displayName,
+			Path:        filePath,
+			ArchiveName: archiveName,
+			Message:     msg.Content,
+			Line:        msg.Line,
+			Snippet:     msg.Snippet,
+			Count:       msg.Count,
+			Lines:       msg.Lines,
+		}
+		if err := nw.encoder.Encode(finding); err != nil {
+			return err
+		}
+		nw.findings++
+	}
+	return nil
+}
+
+// WriteSummary ends the output with the number of findings, the errors and warnings and the
+// skipped files of the scan
+func (nw *NDJSONWriter) WriteSummary(location string, totalFiles int) error {
+	summary := StreamSummary{
+		Type:          "summary",
+		SchemaVersion: SchemaVersion,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Location:      location,
+		FilesScanned:  totalFiles,
+		Findings:      nw.findings,
+		Errors:        make([]output.LogMessage, 0),
+		Warnings:      make([]output.LogMessage, 0),
+		Skipped:       append([]SkippedFile{}, nw.Skipped...),
+	}
+	if nw.Suppressed != (Suppressed{}) {
+		suppressed := nw.Suppressed
+		summary.Suppressed = &suppressed
+	}
+	for _, msg := range output.GlobalLogger.GetMessages() {
+		switch msg.Level {
+		case "error":
+			summary.Errors = append(summary.Errors, msg)
+		case "warning":
+			summary.Warnings = append(summary.Warnings, msg)
+		}
+	}
+	return nw.encoder.Encode(summary)
+}
@@ -0,0 +1,64 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewNDJSONWriter(&buf)
+
+	file := structs.File{Name: "config.txt", Path: "/data/config.txt", ArchiveName: "data.zip"}
+	if err := writer.WriteMessages([]structs.Message{
+		{Content: "Credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords", Line: 3, Count: 2, Lines: []int{3, 9}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteMessages([]structs.Message{
+		{Content: "ReadMe is missing", Source: structs.Repository{}, TestName: "HasReadme"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	writer.Suppressed = Suppressed{Accepted: 2}
+	if err := writer.WriteSummary("my-package", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected one line per finding and a summary, got:\n%s", buf.String())
+	}
+
+	var finding Finding
+	if err := json.Unmarshal([]byte(lines[0]), &finding); err != nil {
+		t.Fatalf("Line is not valid JSON: %v", err)
+	}
+	expected := Finding{Type: "finding", Checkname: "IsFreeOfKeywords", Severity: "error", Subject: "config.txt", Path: "/data/config.txt",
+		ArchiveName: "data.zip", Message: "Credentials detected 'password'", Line: 3, Count: 2, Lines: []int{3, 9}}
+	if finding.Type != expected.Type || finding.Severity != expected.Severity || finding.ArchiveName != expected.ArchiveName ||
+		finding.Line != expected.Line || finding.Count != expected.Count || len(finding.Lines) != 2 {
+		t.Errorf("Expected %+v, got %+v", expected, finding)
+	}
+	if finding.ID != (structs.Message{Content: "Credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords"}).ID("") {
+		t.Errorf("Expected the stable ID of the finding, got %q", finding.ID)
+	}
+	if !strings.Contains(lines[1], `"subject":"repository"`) {
+		t.Errorf("Expected the repository as subject, got %s", lines[1])
+	}
+
+	var summary StreamSummary
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+	if summary.Type != "summary" || summary.Findings != 2 || summary.FilesScanned != 7 || summary.Location != "my-package" || summary.SchemaVersion != SchemaVersion {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if summary.Suppressed == nil || summary.Suppressed.Accepted != 2 {
+		t.Errorf("Expected 2 accepted findings in the summary, got %+v", summary.Suppressed)
+	}
+}
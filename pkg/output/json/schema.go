@@ -0,0 +1,12 @@
+package json
+
+import _ "embed"
+
+// SchemaVersion is the version of Schema written to reports as schema_version. The minor part
+// is increased when fields are added, the major part when fields change or are removed.
+const SchemaVersion = "1.11"
+
+// Schema is the JSON Schema of ScanResult, printed by `pc schema` and served by pc-server
+//
+//go:embed schema.json
+var Schema []byte
@@ -0,0 +1,103 @@
+package json
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Defs       map[string]schemaObject    `json:"$defs"`
+}
+
+// checkSchemaFields compares the properties of an object schema with the JSON fields of typ.
+// Fields without omitempty must be required.
+func checkSchemaFields(t *testing.T, name string, schema schemaObject, typ reflect.Type) {
+	required := make(map[string]bool)
+	for _, field := range schema.Required {
+		required[field] = true
+	}
+	fields := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")
+		fields[tag[0]] = true
+		if _, ok := schema.Properties[tag[0]]; !ok {
+			t.Errorf("%s: field %q is missing in the schema", name, tag[0])
+		}
+		if omitempty := len(tag) > 1 && tag[1] == "omitempty"; omitempty == required[tag[0]] {
+			t.Errorf("%s: field %q required = %v, expected %v", name, tag[0], required[tag[0]], !omitempty)
+		}
+	}
+	for property := range schema.Properties {
+		if !fields[property] {
+			t.Errorf("%s: schema property %q is not in the output", name, property)
+		}
+	}
+}
+
+func TestSchemaMatchesScanResult(t *testing.T) {
+	var schema schemaObject
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	checkSchemaFields(t, "ScanResult", schema, reflect.TypeOf(ScanResult{}))
+	for name, typ := range map[string]reflect.Type{
+		"scannedFile":    reflect.TypeOf(ScannedFile{}),
+		"skippedFile":    reflect.TypeOf(SkippedFile{}),
+		"subjectDetails": reflect.TypeOf(SubjectDetails{}),
+		"checkDetails":   reflect.TypeOf(CheckDetails{}),
+		"checkSummary":   reflect.TypeOf(CheckSummary{}),
+		"checkIssue":     reflect.TypeOf(CheckIssue{}),
+		"subjectIssue":   reflect.TypeOf(SubjectIssue{}),
+		"logMessage":     reflect.TypeOf(output.LogMessage{}),
+		"suppressed":     reflect.TypeOf(Suppressed{}),
+		"mergedLocation": reflect.TypeOf(MergedLocation{}),
+		"archiveListing": reflect.TypeOf(ArchiveListing{}),
+		"archiveMember":  reflect.TypeOf(ArchiveMember{}),
+		"fileMetadata":   reflect.TypeOf(FileMetadata{}),
+	} {
+		definition, ok := schema.Defs[name]
+		if !ok {
+			t.Errorf("Schema definition %q is missing", name)
+			continue
+		}
+		checkSchemaFields(t, name, definition, typ)
+	}
+}
+
+func TestFormatResults_SchemaVersion(t *testing.T) {
+	result, err := NewJSONFormatter().FormatResults("test", "LocalCollector", []structs.Message{}, 0, nil)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed["schema_version"] != SchemaVersion {
+		t.Errorf("Expected schema_version %q, got %v", SchemaVersion, parsed["schema_version"])
+	}
+}
+
+func TestSchemaSkipCodes(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Enum []output.SkipCode `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	if codes := schema.Defs["skippedFile"].Properties["code"].Enum; !reflect.DeepEqual(codes, output.SkipCodes) {
+		t.Errorf("Expected the skip codes %v in the schema, got %v", output.SkipCodes, codes)
+	}
+}
@@ -1,22 +1,78 @@
 package output
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
 	"time"
 )
 
+// Level is the severity of a log message. Messages below the level of the logger are dropped.
+type Level int
+
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo        // The default level
+	LevelWarning
+	LevelError
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+// ParseLevel returns the level named "debug", "info", "warning" (or "warn") or "error"
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warning", "warn":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level '%s', expected debug, info, warning or error", name)
+}
+
+// Log formats of the messages the logger writes
+const (
+	FormatConsole = "console" // Lines of timestamp, level, [component] and message
+	FormatJSON    = "json"    // One JSON object per message
+)
+
 // LogMessage represents a log entry with level, message and timestamp
 type LogMessage struct {
 	Level     string `json:"level"`
 	Message   string `json:"message"`
 	Timestamp string `json:"timestamp"`
+	Component string `json:"component,omitempty"` // Subsystem that logged the message, e.g. "collectors"
+	Location  string `json:"location,omitempty"`  // Location the message was logged for, in merged reports
 }
 
-// Logger provides configurable output destinations
+// Logger records the messages of a scan for the reports (JSON mode) or writes them as they are
+// logged. Messages below its level are dropped.
 type Logger struct {
 	jsonMode bool
+	level    Level
+	echo     io.Writer // Also receives the recorded messages in JSON mode, e.g. stderr for -verbose
+	writer   io.Writer // Receives the messages outside JSON mode, stdout if nil
+	format   string    // FormatConsole or FormatJSON, console if empty
 	messages []LogMessage
+	redactor *Redactor // Redacts the recorded messages when they are read
 	mu       sync.Mutex
 }
 
@@ -27,59 +83,178 @@ func (l *Logger) SetJSONMode(enabled bool) {
 	l.jsonMode = enabled
 }
 
-// Warning prints warning messages to appropriate stream
-func (l *Logger) Warning(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	if l.jsonMode {
-		l.mu.Lock()
-		l.messages = append(l.messages, LogMessage{
-			Level:     "warning",
-			Message:   message,
-			Timestamp: time.Now().Format(time.RFC3339),
-		})
-		l.mu.Unlock()
-	} else {
-		fmt.Printf(message + "\n")
+// SetLevel drops the messages below level from now on
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// Enabled reports whether messages of level are logged, to skip preparing debug messages
+func (l *Logger) Enabled(level Level) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return level >= l.level
+}
+
+// SetFormat selects how messages are written: FormatConsole or FormatJSON
+func (l *Logger) SetFormat(format string) error {
+	if format != FormatConsole && format != FormatJSON {
+		return fmt.Errorf("unknown log format '%s', expected %s or %s", format, FormatConsole, FormatJSON)
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+	return nil
 }
 
-// Error prints error messages to appropriate stream
-func (l *Logger) Error(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	if l.jsonMode {
-		l.mu.Lock()
-		l.messages = append(l.messages, LogMessage{
-			Level:     "error",
-			Message:   message,
-			Timestamp: time.Now().Format(time.RFC3339),
-		})
-		l.mu.Unlock()
-	} else {
-		fmt.Printf(message + "\n")
+// SetWriter writes the messages outside JSON mode to w instead of stdout
+func (l *Logger) SetWriter(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writer = w
+}
+
+// SetEcho also writes the messages recorded in JSON mode to w as they are logged, nil for none
+func (l *Logger) SetEcho(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.echo = w
+}
+
+// Configure applies the -verbose, -quiet and -log-format flags. -verbose logs debug messages and
+// echoes the messages recorded in JSON mode to stderr, -quiet logs errors only.
+func (l *Logger) Configure(verbose, quiet bool, format string) error {
+	if verbose && quiet {
+		return errors.New("-verbose and -quiet cannot be used together")
+	}
+	if err := l.SetFormat(format); err != nil {
+		return err
+	}
+	switch {
+	case verbose:
+		l.SetLevel(LevelDebug)
+		l.SetEcho(os.Stderr)
+	case quiet:
+		l.SetLevel(LevelError)
 	}
+	return nil
 }
 
-// Info prints info messages to appropriate stream
+// Debug logs details for tracing a scan, dropped unless the level is LevelDebug
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.log(LevelDebug, "", format, args...)
+}
+
+// Info logs informational messages
 func (l *Logger) Info(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	if l.jsonMode {
-		l.mu.Lock()
-		l.messages = append(l.messages, LogMessage{
-			Level:     "info",
-			Message:   message,
-			Timestamp: time.Now().Format(time.RFC3339),
-		})
-		l.mu.Unlock()
-	} else {
-		fmt.Printf(message + "\n")
+	l.log(LevelInfo, "", format, args...)
+}
+
+// Warning logs problems that do not stop the scan
+func (l *Logger) Warning(format string, args ...interface{}) {
+	l.log(LevelWarning, "", format, args...)
+}
+
+// Error logs failures
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.log(LevelError, "", format, args...)
+}
+
+func (l *Logger) log(level Level, component string, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level {
+		return
+	}
+	message := LogMessage{
+		Level:     level.String(),
+		Message:   fmt.Sprintf(format, args...),
+		Timestamp: time.Now().Format(time.RFC3339),
+		Component: component,
 	}
+	if !l.jsonMode {
+		writer := l.writer
+		if writer == nil {
+			writer = os.Stdout
+		}
+		l.write(writer, message)
+		return
+	}
+	// Debug messages are not shown by the reports and only echoed
+	if level > LevelDebug {
+		l.messages = append(l.messages, message)
+	}
+	if l.echo != nil {
+		l.write(l.echo, message)
+	}
+}
+
+// write writes message in the format of the logger, redacted if the logger redacts
+func (l *Logger) write(w io.Writer, message LogMessage) {
+	if l.redactor != nil {
+		message.Message = l.redactor.Text(message.Message)
+	}
+	if l.format == FormatJSON {
+		if line, err := json.Marshal(message); err == nil {
+			fmt.Fprintln(w, string(line))
+		}
+		return
+	}
+	component := ""
+	if message.Component != "" {
+		component = "[" + message.Component + "] "
+	}
+	fmt.Fprintf(w, "%s %-7s %s%s\n", message.Timestamp, message.Level, component, message.Message)
+}
+
+// ComponentLogger logs through a Logger with the name of a subsystem
+type ComponentLogger struct {
+	logger    *Logger
+	component string
+}
+
+// Component returns a logger tagging its messages with component, e.g. "collectors"
+func (l *Logger) Component(component string) ComponentLogger {
+	return ComponentLogger{logger: l, component: component}
+}
+
+func (c ComponentLogger) Debug(format string, args ...interface{}) {
+	c.logger.log(LevelDebug, c.component, format, args...)
+}
+
+func (c ComponentLogger) Info(format string, args ...interface{}) {
+	c.logger.log(LevelInfo, c.component, format, args...)
+}
+
+func (c ComponentLogger) Warning(format string, args ...interface{}) {
+	c.logger.log(LevelWarning, c.component, format, args...)
+}
+
+func (c ComponentLogger) Error(format string, args ...interface{}) {
+	c.logger.log(LevelError, c.component, format, args...)
+}
+
+// SetRedactor redacts the messages returned from now on, nil for none
+func (l *Logger) SetRedactor(r *Redactor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.redactor = r
 }
 
 // GetMessages returns captured messages for JSON output
 func (l *Logger) GetMessages() []LogMessage {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return l.messages
+	if l.redactor == nil {
+		return l.messages
+	}
+	redacted := make([]LogMessage, len(l.messages))
+	for i, message := range l.messages {
+		message.Message = l.redactor.Text(message.Message)
+		redacted[i] = message
+	}
+	return redacted
 }
 
 // ClearMessages clears the captured messages
@@ -87,4 +262,4 @@ func (l *Logger) ClearMessages() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.messages = []LogMessage{}
-}
\ No newline at end of file
+}
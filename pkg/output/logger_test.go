@@ -1,6 +1,9 @@
 package output
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -249,4 +252,102 @@ func TestLogger_ConcurrentAccess(t *testing.T) {
 	if len(messages) != numGoroutines*3 {
 		t.Errorf("Expected %d messages, got %d", numGoroutines*3, len(messages))
 	}
-}
\ No newline at end of file
+}
+
+func TestLogger_Level(t *testing.T) {
+	logger := &Logger{jsonMode: true, messages: []LogMessage{}}
+
+	// Debug messages are dropped at the default level
+	logger.Debug("Debug message")
+	logger.Info("Info message")
+	if len(logger.messages) != 1 || logger.messages[0].Level != "info" {
+		t.Errorf("Expected the info message only, got %+v", logger.messages)
+	}
+
+	logger.SetLevel(LevelError)
+	logger.Warning("Dropped warning")
+	logger.Error("Kept error")
+	if len(logger.messages) != 2 || logger.messages[1].Message != "Kept error" {
+		t.Errorf("Expected the error to be kept and the warning to be dropped, got %+v", logger.messages)
+	}
+	if logger.Enabled(LevelWarning) || !logger.Enabled(LevelError) {
+		t.Error("Expected only errors to be enabled")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warn": LevelWarning, "warning": LevelWarning, "error": LevelError} {
+		if level, err := ParseLevel(name); err != nil || level != expected {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, level, err, expected)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestLogger_Echo(t *testing.T) {
+	logger := &Logger{jsonMode: true, messages: []LogMessage{}}
+	var echo bytes.Buffer
+	logger.SetEcho(&echo)
+	logger.SetLevel(LevelDebug)
+
+	collectors := logger.Component("collectors")
+	collectors.Debug("Collected %d files", 3)
+	collectors.Warning("Cannot read %s", "a.txt")
+
+	// Debug messages are echoed but not recorded for the reports
+	if len(logger.messages) != 1 || logger.messages[0].Component != "collectors" {
+		t.Errorf("Expected the warning of the collectors to be recorded, got %+v", logger.messages)
+	}
+	lines := strings.Split(strings.TrimSpace(echo.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "debug   [collectors] Collected 3 files") || !strings.HasSuffix(lines[1], "warning [collectors] Cannot read a.txt") {
+		t.Errorf("Unexpected echo:\n%s", echo.String())
+	}
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	logger := &Logger{jsonMode: false}
+	var out bytes.Buffer
+	logger.SetWriter(&out)
+	if err := logger.SetFormat("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if err := logger.SetFormat(FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Component("server").Info("Listening on %s", ":8080")
+
+	var message LogMessage
+	if err := json.Unmarshal(out.Bytes(), &message); err != nil {
+		t.Fatalf("Expected a JSON line, got %q: %v", out.String(), err)
+	}
+	if message.Level != "info" || message.Component != "server" || message.Message != "Listening on :8080" || message.Timestamp == "" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+}
+
+func TestLogger_Configure(t *testing.T) {
+	logger := &Logger{jsonMode: true, messages: []LogMessage{}}
+	if err := logger.Configure(true, true, FormatConsole); err == nil {
+		t.Error("Expected an error for -verbose with -quiet")
+	}
+	if err := logger.Configure(false, false, "yaml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	if err := logger.Configure(false, true, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if logger.Enabled(LevelWarning) || logger.format != FormatJSON || logger.echo != nil {
+		t.Errorf("Expected -quiet to log errors only without echo, got %+v", logger)
+	}
+
+	if err := logger.Configure(true, false, FormatConsole); err != nil {
+		t.Fatal(err)
+	}
+	if !logger.Enabled(LevelDebug) || logger.echo == nil {
+		t.Error("Expected -verbose to log debug messages to stderr")
+	}
+}
@@ -0,0 +1,42 @@
+package plain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// FormatArchives lists the members of the archives with their sizes and types, as printed by
+// -list-archives
+func (f *PlainFormatter) FormatArchives(location string, listings []jsonformatter.ArchiveListing) string {
+	var output strings.Builder
+	output.WriteString("=== " + i18n.T("Archive Listing") + " ===\n")
+	output.WriteString(fmt.Sprintf("%s %s\n", i18n.T("Location:"), location))
+
+	if len(listings) == 0 {
+		output.WriteString("\n" + i18n.T("No archives found.") + "\n")
+		return output.String()
+	}
+	output.WriteString(i18n.Tf("Archives: %d", len(listings)) + "\n")
+
+	for _, listing := range listings {
+		output.WriteString("\n📦 ")
+		if listing.Error != "" {
+			output.WriteString(i18n.Tf("%s could not be listed: %s", listing.Archive, listing.Error) + "\n")
+			continue
+		}
+		output.WriteString(i18n.Tf("%s (%s, %s, %s unpacked):", listing.Archive, config.FormatSize(listing.Size),
+			i18n.Plural(listing.Files, "%d file", "%d files"), config.FormatSize(listing.UnpackedSize)) + "\n")
+		for _, member := range listing.Members {
+			size := ""
+			if member.Type != jsonformatter.FolderType {
+				size = config.FormatSize(member.Size)
+			}
+			output.WriteString(strings.TrimRight(fmt.Sprintf("  %10s  %-24s %s", size, member.Type, member.Name), " ") + "\n")
+		}
+	}
+	return output.String()
+}
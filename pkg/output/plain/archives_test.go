@@ -0,0 +1,36 @@
+package plain
+
+import (
+	"strings"
+	"testing"
+
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+func TestPlainFormatter_FormatArchives(t *testing.T) {
+	listings := []jsonformatter.ArchiveListing{
+		{Archive: "data.zip", Size: 2048, Files: 1, UnpackedSize: 3 * 1024 * 1024, Members: []jsonformatter.ArchiveMember{
+			{Name: "raw/", Type: jsonformatter.FolderType},
+			{Name: "raw/ice.csv", Size: 3 * 1024 * 1024, Type: "text/csv"},
+		}},
+		{Archive: "broken.7z", Error: "not a valid 7-zip file", Members: []jsonformatter.ArchiveMember{}},
+	}
+
+	result := NewPlainFormatter().FormatArchives("lake", listings)
+
+	for _, expected := range []string{
+		"Location: lake\nArchives: 2\n",
+		"📦 data.zip (2.0 KiB, 1 file, 3.0 MiB unpacked):\n",
+		"              folder                   raw/\n",
+		"     3.0 MiB  text/csv                 raw/ice.csv\n",
+		"📦 broken.7z could not be listed: not a valid 7-zip file\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in the listing, got:\n%s", expected, result)
+		}
+	}
+
+	if result := NewPlainFormatter().FormatArchives("lake", nil); !strings.Contains(result, "No archives found.") {
+		t.Errorf("Expected a note without archives, got:\n%s", result)
+	}
+}
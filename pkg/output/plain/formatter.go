@@ -2,13 +2,18 @@ package plain
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
 // PlainFormatter provides plain text formatting for scan results
-type PlainFormatter struct{}
+type PlainFormatter struct {
+	Skipped []output.SkippedFile // Files the scan did not check, see config.SkippedFiles
+}
 
 // NewPlainFormatter creates a new plain text formatter
 func NewPlainFormatter() *PlainFormatter {
@@ -20,18 +25,20 @@ func (f *PlainFormatter) FormatResults(location string, collectorName string, me
 	var output strings.Builder
 	
 	// Header
-	output.WriteString("=== PC Scan Results ===\n")
-	output.WriteString(fmt.Sprintf("Location: %s\n", location))
-	output.WriteString(fmt.Sprintf("Files scanned: %d\n", totalFiles))
+	output.WriteString("=== " + i18n.T("PC Scan Results") + " ===\n")
+	output.WriteString(fmt.Sprintf("%s %s\n", i18n.T("Location:"), location))
+	output.WriteString(i18n.Tf("Files scanned: %d", totalFiles) + "\n")
 	
 	if len(messages) == 0 {
-		output.WriteString("\n✅ No issues found!\n")
+		output.WriteString("\n✅ " + i18n.T("No issues found!") + "\n")
+		writeSkipped(&output, f.Skipped)
 		return output.String()
 	}
 	
 	// Group messages by source file (using display name with archive context)
 	fileIssues := make(map[string][]structs.Message)
 	repoIssues := []structs.Message{}
+	metadataIssues := []structs.Message{}
 
 	for _, msg := range messages {
 		switch source := msg.Source.(type) {
@@ -45,6 +52,8 @@ func (f *PlainFormatter) FormatResults(location string, collectorName string, me
 			fileIssues[key] = append(fileIssues[key], msg)
 		case structs.Repository:
 			repoIssues = append(repoIssues, msg)
+		case structs.Metadata:
+			metadataIssues = append(metadataIssues, msg)
 		}
 	}
 	
@@ -54,21 +63,34 @@ func (f *PlainFormatter) FormatResults(location string, collectorName string, me
 	if len(repoIssues) > 0 {
 		filesWithIssues++ // Count repository as one more "file" with issues
 	}
+	if len(metadataIssues) > 0 {
+		filesWithIssues++ // Count the metadata as one more "file" with issues
+	}
 	
-	output.WriteString(fmt.Sprintf("\n❌ Found %d issues in %d files:\n\n", totalIssues, filesWithIssues))
+	output.WriteString("\n❌ " + i18n.Tf("Found %d issues in %d files:", totalIssues, filesWithIssues) + "\n\n")
 	
 	// Repository issues first
 	if len(repoIssues) > 0 {
-		output.WriteString("📁 Repository Issues:\n")
+		output.WriteString("📁 " + i18n.T("Repository Issues:") + "\n")
 		for _, msg := range repoIssues {
 			output.WriteString(fmt.Sprintf("  • %s\n", msg.Content))
 		}
 		output.WriteString("\n")
 	}
+
+	// Metadata issues
+	if len(metadataIssues) > 0 {
+		output.WriteString("🏷️ " + i18n.T("Metadata Issues:") + "\n")
+		for _, msg := range metadataIssues {
+			output.WriteString(fmt.Sprintf("  • %s\n", withLocation(msg.Content, msg)))
+		}
+		output.WriteString("\n")
+	}
 	
-	// File issues grouped by file
-	for filename, msgs := range fileIssues {
-		output.WriteString(fmt.Sprintf("📄 %s (%d issues):\n", filename, len(msgs)))
+	// File issues grouped by file, in order of the file names
+	for _, filename := range sortedKeys(fileIssues) {
+		msgs := fileIssues[filename]
+		output.WriteString("📄 " + i18n.Tf("%s (%d issues):", filename, len(msgs)) + "\n")
 		
 		// Group by check type for better readability
 		checkGroups := make(map[string][]structs.Message)
@@ -76,28 +98,31 @@ func (f *PlainFormatter) FormatResults(location string, collectorName string, me
 			checkGroups[msg.TestName] = append(checkGroups[msg.TestName], msg)
 		}
 		
-		for checkName, checkMsgs := range checkGroups {
+		for _, checkName := range sortedKeys(checkGroups) {
+			checkMsgs := checkGroups[checkName]
 			if len(checkMsgs) == 1 {
-				output.WriteString(fmt.Sprintf("  • %s\n", checkMsgs[0].Content))
+				output.WriteString(fmt.Sprintf("  • %s\n", withLocation(checkMsgs[0].Content, checkMsgs[0])))
 			} else {
-				output.WriteString(fmt.Sprintf("  • %s (%d occurrences):\n", checkName, len(checkMsgs)))
+				output.WriteString("  • " + i18n.Tf("%s (%d occurrences):", checkName, len(checkMsgs)) + "\n")
 				for _, msg := range checkMsgs {
 					// Truncate long messages for readability
 					content := msg.Content
 					if len(content) > 80 {
 						content = content[:77] + "..."
 					}
-					output.WriteString(fmt.Sprintf("    - %s\n", content))
+					output.WriteString(fmt.Sprintf("    - %s\n", withLocation(content, msg)))
 				}
 			}
 		}
 		output.WriteString("\n")
 	}
 	
+	writeSkipped(&output, f.Skipped)
+
 	// Summary footer
-	output.WriteString("=== Summary ===\n")
-	output.WriteString(fmt.Sprintf("Total issues: %d\n", totalIssues))
-	output.WriteString(fmt.Sprintf("Files with issues: %d/%d\n", filesWithIssues, totalFiles))
+	output.WriteString("=== " + i18n.T("Summary") + " ===\n")
+	output.WriteString(i18n.Tf("Total issues: %d", totalIssues) + "\n")
+	output.WriteString(i18n.Tf("Files with issues: %d/%d", filesWithIssues, totalFiles) + "\n")
 	
 	// Issue type breakdown
 	checkCounts := make(map[string]int)
@@ -106,11 +131,49 @@ func (f *PlainFormatter) FormatResults(location string, collectorName string, me
 	}
 	
 	if len(checkCounts) > 0 {
-		output.WriteString("\nIssue types:\n")
-		for checkName, count := range checkCounts {
-			output.WriteString(fmt.Sprintf("  • %s: %d\n", checkName, count))
+		output.WriteString("\n" + i18n.T("Issue types:") + "\n")
+		checkNames := make([]string, 0, len(checkCounts))
+		for checkName := range checkCounts {
+			checkNames = append(checkNames, checkName)
+		}
+		sort.Strings(checkNames)
+		for _, checkName := range checkNames {
+			output.WriteString(fmt.Sprintf("  • %s: %d\n", checkName, checkCounts[checkName]))
 		}
 	}
 	
 	return output.String()
-}
\ No newline at end of file
+}
+
+// sortedKeys returns the keys of messages grouped by file or check, sorted
+func sortedKeys(groups map[string][]structs.Message) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withLocation appends the line and snippet of the message to content, if known
+func withLocation(content string, msg structs.Message) string {
+	if location := msg.Location(); location != "" {
+		return content + " (" + location + ")"
+	}
+	return content
+}
+// writeSkipped lists the files recorded as skipped during the scan with their reason codes
+func writeSkipped(sb *strings.Builder, skipped []output.SkippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+	sb.WriteString("\n⏭️ " + i18n.Tf("Skipped %d files:", len(skipped)) + "\n")
+	for _, file := range skipped {
+		name := file.Filename
+		if file.ArchiveName != "" {
+			name = file.ArchiveName + " > " + name
+		}
+		sb.WriteString(fmt.Sprintf("  • %s [%s]: %s\n", name, file.Code, file.Reason))
+	}
+	sb.WriteString("\n")
+}
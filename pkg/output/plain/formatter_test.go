@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
@@ -117,4 +118,27 @@ func TestPlainFormatter_FormatResults_RepositoryIssues(t *testing.T) {
 	if !strings.Contains(result, "Repository issue") {
 		t.Errorf("Expected repository issue content, got: %s", result)
 	}
-}
\ No newline at end of file
+}
+func TestPlainFormatter_FormatResults_MetadataIssues(t *testing.T) {
+	messages := []structs.Message{
+		{Content: "The package has no license.", Source: structs.Metadata{Package: "lake-ice"}, TestName: "HasLicense"},
+	}
+
+	result := NewPlainFormatter().FormatResults("lake-ice", "CkanCollector", messages, 1, []string{})
+	if !strings.Contains(result, "Metadata Issues:\n  • The package has no license.") {
+		t.Errorf("Expected metadata section, got: %s", result)
+	}
+	if !strings.Contains(result, "Found 1 issues in 1 files") {
+		t.Errorf("Expected the metadata to count as a subject with issues, got: %s", result)
+	}
+}
+
+func TestPlainFormatter_FormatResults_Skipped(t *testing.T) {
+	formatter := NewPlainFormatter()
+	formatter.Skipped = []output.SkippedFile{{Filename: "random.bin", Path: "/data/data.zip", ArchiveName: "data.zip", Code: output.SkipBinary, Reason: "The archive member seems to be binary."}}
+
+	result := formatter.FormatResults("test/path", "LocalCollector", []structs.Message{}, 1, []string{})
+	if !strings.Contains(result, "Skipped 1 files:") || !strings.Contains(result, "data.zip > random.bin [binary]: The archive member seems to be binary.") {
+		t.Errorf("Expected the skipped archive member with its code, got: %s", result)
+	}
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RedactedUser replaces the name of the user running pc in redacted output
+const RedactedUser = "<user>"
+
+// RedactedFolder replaces the folders of absolute paths outside the scanned folder
+const RedactedFolder = "<redacted>"
+
+// absolutePath matches absolute Unix and Windows paths in a text, after the start of the text,
+// white space, quotes, brackets or the marker of a finding
+var absolutePath = regexp.MustCompile(`(?:^|[\s'"(\[=»])((?:/|[A-Za-z]:\\)[^\s'"()\[\]<>,;«»]+)`)
+
+// Redactor removes what a shared report should not spread besides the secrets the checks redact:
+// the folders and user names in absolute paths. Paths in the scanned folder become relative to
+// it, other absolute paths keep only their last element. A nil Redactor changes nothing.
+type Redactor struct {
+	root      string         // Scanned folder, "" if the package was not collected from a folder
+	users     []string       // Names of the user running pc and of the users the checks found
+	userNames *regexp.Regexp // The users as words, nil if none are known
+	mu        sync.RWMutex
+}
+
+// NewRedactor creates a redactor for a scan of root
+func NewRedactor(root string) *Redactor {
+	r := &Redactor{}
+	if root != "" {
+		if absolute, err := filepath.Abs(root); err == nil {
+			r.root = filepath.Clean(absolute)
+		}
+	}
+
+	if current, err := user.Current(); err == nil {
+		r.AddUsers(current.Username)
+	}
+	r.AddUsers(os.Getenv("USER"), os.Getenv("USERNAME"))
+	return r
+}
+
+// AddUsers hides the user names from now on, e.g. those found in the home folders of absolute
+// paths by the checks
+func (r *Redactor) AddUsers(names ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	added := false
+	for _, name := range names {
+		// Windows user names include the domain
+		if i := strings.LastIndex(name, `\`); i >= 0 {
+			name = name[i+1:]
+		}
+		// Short names such as "a" would redact ordinary words
+		if len(name) < 3 || slices.Contains(r.users, name) {
+			continue
+		}
+		r.users = append(r.users, name)
+		added = true
+	}
+	if !added {
+		return
+	}
+	quoted := make([]string, len(r.users))
+	for i, name := range r.users {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	r.userNames = regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// Path redacts a path: relative to the scanned folder if it is in it, the scanned folder itself
+// by its name and other absolute paths by their last element. Other relative paths are kept.
+func (r *Redactor) Path(path string) string {
+	if r == nil || path == "" {
+		return path
+	}
+	if !filepath.IsAbs(path) && !isWindowsPath(path) {
+		// Relative to the working directory, as the scanned folder was given
+		absolute, err := filepath.Abs(path)
+		if r.root == "" || err != nil || (absolute != r.root && !strings.HasPrefix(absolute, r.root+string(filepath.Separator))) {
+			return r.hideUsers(path)
+		}
+		path = absolute
+	}
+	clean := filepath.Clean(path)
+	if r.root != "" {
+		if clean == r.root {
+			return r.hideUsers(filepath.Base(r.root))
+		}
+		if relative, err := filepath.Rel(r.root, clean); err == nil && !strings.HasPrefix(relative, "..") {
+			return r.hideUsers(filepath.ToSlash(relative))
+		}
+	}
+	base := path[strings.LastIndexAny(strings.TrimRight(path, `/\`), `/\`)+1:]
+	return RedactedFolder + "/" + r.hideUsers(strings.TrimRight(base, `/\`))
+}
+
+// Paths redacts each of the paths
+func (r *Redactor) Paths(paths []string) []string {
+	if r == nil {
+		return paths
+	}
+	redacted := make([]string, len(paths))
+	for i, path := range paths {
+		redacted[i] = r.Path(path)
+	}
+	return redacted
+}
+
+// SkippedFiles redacts the paths and reasons of the skipped files
+func (r *Redactor) SkippedFiles(files []SkippedFile) []SkippedFile {
+	if r == nil {
+		return files
+	}
+	redacted := make([]SkippedFile, len(files))
+	for i, file := range files {
+		file.Path = r.Path(file.Path)
+		file.Reason = r.Text(file.Reason)
+		redacted[i] = file
+	}
+	return redacted
+}
+
+// Text redacts the absolute paths and the user names in a text, e.g. a message or a snippet
+func (r *Redactor) Text(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	text = absolutePath.ReplaceAllStringFunc(text, func(match string) string {
+		m := absolutePath.FindStringSubmatchIndex(match)
+		return match[:m[2]] + r.Path(match[m[2]:m[3]])
+	})
+	return r.hideUsers(text)
+}
+
+// hideUsers replaces the names of the user running pc and of the users the checks found
+func (r *Redactor) hideUsers(text string) string {
+	r.mu.RLock()
+	userNames := r.userNames
+	r.mu.RUnlock()
+	if userNames == nil {
+		return text
+	}
+	return userNames.ReplaceAllString(text, RedactedUser)
+}
+
+// isWindowsPath reports whether path is an absolute Windows path, also on other systems
+func isWindowsPath(path string) bool {
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') &&
+		(path[0] >= 'A' && path[0] <= 'Z' || path[0] >= 'a' && path[0] <= 'z')
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestRedactor_Path(t *testing.T) {
+	r := &Redactor{root: "/data/lake-ice", userNames: regexp.MustCompile(`\b(?:jdoe)\b`)}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/data/lake-ice", "lake-ice"},
+		{"/data/lake-ice/raw/ice.csv", "raw/ice.csv"},
+		{"/data/lake-ice-old/ice.csv", "<redacted>/ice.csv"},
+		{"/home/jdoe/.ssh/", "<redacted>/.ssh"},
+		{"/home/jdoe/jdoe.txt", "<redacted>/<user>.txt"},
+		{`C:\Users\jdoe\notes.txt`, "<redacted>/notes.txt"},
+		{"raw/ice.csv", "raw/ice.csv"},
+		{"jdoe/ice.csv", "<user>/ice.csv"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := r.Path(tt.path); got != tt.expected {
+			t.Errorf("Path(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestRedactor_Path_RelativeLocation(t *testing.T) {
+	// The scanned folder was given relative to the working directory, e.g. "-location data"
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Redactor{root: filepath.Join(wd, "data")}
+
+	if got := r.Path(filepath.Join("data", "raw", "ice.csv")); got != "raw/ice.csv" {
+		t.Errorf("Expected the path relative to the scanned folder, got %q", got)
+	}
+	if got := r.Path(filepath.Join("other", "ice.csv")); got != "other/ice.csv" {
+		t.Errorf("Expected a relative path outside the scanned folder to be kept, got %q", got)
+	}
+}
+
+func TestRedactor_Text(t *testing.T) {
+	r := &Redactor{root: "/data/lake-ice", userNames: regexp.MustCompile(`\b(?:jdoe)\b`)}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Found '/home/jdoe/key.pem' in config", "Found '<redacted>/key.pem' in config"},
+		{"Could not read /data/lake-ice/raw/ice.csv: permission denied", "Could not read raw/ice.csv: permission denied"},
+		{`path=C:\Users\jdoe\secret.txt`, "path=<redacted>/secret.txt"},
+		{"Written by jdoe and jdoes", "Written by <user> and jdoes"},
+		{"10/20 files, ratio 1/2", "10/20 files, ratio 1/2"},
+	}
+	for _, tt := range tests {
+		if got := r.Text(tt.text); got != tt.expected {
+			t.Errorf("Text(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestRedactor_Nil(t *testing.T) {
+	var r *Redactor
+	if got := r.Path("/home/jdoe/key.pem"); got != "/home/jdoe/key.pem" {
+		t.Errorf("Expected a nil redactor to keep paths, got %q", got)
+	}
+	if got := r.Text("Found /home/jdoe/key.pem"); got != "Found /home/jdoe/key.pem" {
+		t.Errorf("Expected a nil redactor to keep texts, got %q", got)
+	}
+	paths := []string{"/home/jdoe/key.pem"}
+	if got := r.Paths(paths); got[0] != paths[0] {
+		t.Errorf("Expected a nil redactor to keep paths, got %v", got)
+	}
+}
+
+func TestRedactor_Paths(t *testing.T) {
+	r := &Redactor{root: "/data/lake-ice"}
+	paths := []string{"/data/lake-ice/report.pdf", "/tmp/other.pdf"}
+	got := r.Paths(paths)
+	if got[0] != "report.pdf" || got[1] != "<redacted>/other.pdf" {
+		t.Errorf("Unexpected redacted paths %v", got)
+	}
+	if paths[0] != "/data/lake-ice/report.pdf" {
+		t.Errorf("Expected the paths to be left unchanged, got %v", paths)
+	}
+}
+
+func TestRedactor_AddUsers(t *testing.T) {
+	r := &Redactor{root: "/data/lake-ice"}
+	r.AddUsers("alice", `EAWAG\bob`, "jd")
+	if got := r.Text("Files of alice and bob, checked by jd"); got != "Files of <user> and <user>, checked by jd" {
+		t.Errorf("Expected the added users to be redacted, got %q", got)
+	}
+	if got := r.Path("/home/alice/notes.txt"); got != "<redacted>/notes.txt" {
+		t.Errorf("Unexpected redacted path %q", got)
+	}
+	if got := r.Path("alice-notes.txt"); got != "<user>-notes.txt" {
+		t.Errorf("Expected the user to be redacted in relative paths, got %q", got)
+	}
+
+	var none *Redactor
+	none.AddUsers("alice")
+}
+
+func TestRedactor_SkippedFiles(t *testing.T) {
+	r := &Redactor{root: "/data/lake-ice"}
+	files := []SkippedFile{{Filename: "ice.csv", Path: "/data/lake-ice/raw/ice.csv", Code: SkipReadError, Reason: "open /data/lake-ice/raw/ice.csv: permission denied"}}
+	got := r.SkippedFiles(files)
+	if got[0].Path != "raw/ice.csv" || got[0].Reason != "open raw/ice.csv: permission denied" || got[0].Filename != "ice.csv" {
+		t.Errorf("Unexpected redacted skipped file %+v", got[0])
+	}
+	if files[0].Path != "/data/lake-ice/raw/ice.csv" {
+		t.Errorf("Expected the skipped files to be left unchanged, got %+v", files)
+	}
+}
+
+func TestLogger_GetMessages_Redacted(t *testing.T) {
+	logger := &Logger{jsonMode: true, messages: []LogMessage{}}
+	logger.Warning("Could not open /data/lake-ice/raw/ice.csv")
+	logger.SetRedactor(&Redactor{root: "/data/lake-ice"})
+
+	messages := logger.GetMessages()
+	if len(messages) != 1 || messages[0].Message != "Could not open raw/ice.csv" {
+		t.Errorf("Expected a redacted message, got %v", messages)
+	}
+	if logger.messages[0].Message != "Could not open /data/lake-ice/raw/ice.csv" {
+		t.Errorf("Expected the stored message to be kept, got %v", logger.messages[0])
+	}
+}
@@ -0,0 +1,215 @@
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// Version and Schema of the SARIF format written
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Log is the SARIF document, restricted to what pc reports
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a scan with the tool that made it
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes pc and its checks
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is pc with a rule per check that reported issues
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule describes a check
+type Rule struct {
+	ID                   string        `json:"id"`
+	ShortDescription     *Text         `json:"shortDescription,omitempty"`
+	DefaultConfiguration Configuration `json:"defaultConfiguration"`
+}
+
+// Configuration holds the level of the issues of a check
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// Text is a plain text message
+type Text struct {
+	Text string `json:"text"`
+}
+
+// Result is an issue of the report
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             Text              `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"` // stable ID of the issue
+	Properties          map[string]int    `json:"properties,omitempty"`          // count of merged findings, if more than one
+}
+
+// FingerprintKey names the stable ID of pc issues in the partial fingerprints of results
+const FingerprintKey = "pcIssueId/v1"
+
+// Location is the file of an issue, or for issues of archive members and of the whole package,
+// a logical location naming them
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// PhysicalLocation is a file with the line of the issue, if known
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation is the URI of a file
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is the line of an issue with the text around the finding
+type Region struct {
+	StartLine int   `json:"startLine"`
+	Snippet   *Text `json:"snippet,omitempty"`
+}
+
+// LogicalLocation names a member of an archive or the package
+type LogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFFormatter writes a JSON report as SARIF, so code scanning tools and CI platforms can show
+// the issues
+type SARIFFormatter struct{}
+
+// NewSARIFFormatter creates a new SARIF formatter
+func NewSARIFFormatter() *SARIFFormatter {
+	return &SARIFFormatter{}
+}
+
+// FormatResults converts the JSON report to SARIF
+func (f *SARIFFormatter) FormatResults(jsonResult string) (string, error) {
+	result, err := jsonformatter.Parse([]byte(jsonResult))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JSON data: %w", err)
+	}
+	return f.Format(result)
+}
+
+// Format converts the report to SARIF
+func (f *SARIFFormatter) Format(result *jsonformatter.ScanResult) (string, error) {
+
+	run := Run{
+		Tool:    Tool{Driver: Driver{Name: "pc", InformationURI: "https://github.com/eawag-rdm/pc", Rules: []Rule{}}},
+		Results: []Result{},
+	}
+	for _, check := range result.DetailsCheckFocused {
+		severity := check.Severity
+		if severity == "" {
+			// Reports written before severities were recorded
+			severity = string(checks.SeverityOf(check.Checkname))
+		}
+		if check.ReportOnly {
+			// Informational checks must not fail code scanning
+			severity = string(checks.SeverityInfo)
+		}
+		rule := Rule{ID: check.Checkname, DefaultConfiguration: Configuration{Level: level(severity)}}
+		if info, ok := checks.Lookup(check.Checkname); ok {
+			rule.ShortDescription = &Text{Text: info.Description}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		for _, issue := range check.Issues {
+			r := Result{RuleID: check.Checkname, Level: rule.DefaultConfiguration.Level, Message: Text{Text: issue.Message}}
+			r.PartialFingerprints = map[string]string{FingerprintKey: issue.IssueID(check.Checkname)}
+			if location := locate(issue); location != nil {
+				r.Locations = []Location{*location}
+			}
+			if issue.Count > 1 {
+				r.Properties = map[string]int{"count": issue.Count}
+			}
+			run.Results = append(run.Results, r)
+		}
+	}
+	sort.SliceStable(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	log := Log{Schema: Schema, Version: Version, Runs: []Run{run}}
+	jsonBytes, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// level returns the SARIF level of a severity
+func level(severity string) string {
+	switch severity {
+	case string(checks.SeverityError):
+		return "error"
+	case string(checks.SeverityInfo):
+		return "note"
+	}
+	return "warning"
+}
+
+// locate returns the location of an issue, nil for issues without a subject
+func locate(issue jsonformatter.SubjectIssue) *Location {
+	location := &Location{}
+	if issue.Path != "" {
+		physical := &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: fileURI(issue.Path)}}
+		// Lines of archive members are not lines of the archive
+		if issue.Line > 0 && issue.ArchiveName == "" {
+			physical.Region = &Region{StartLine: issue.Line}
+			if issue.Snippet != "" {
+				physical.Region.Snippet = &Text{Text: issue.Snippet}
+			}
+		}
+		location.PhysicalLocation = physical
+	}
+	switch {
+	case issue.ArchiveName != "":
+		location.LogicalLocations = []LogicalLocation{{FullyQualifiedName: issue.ArchiveName + " > " + issue.Subject, Kind: "member"}}
+	case issue.Path == "" && issue.Subject != "":
+		// Issues of the whole package ("repository") or of its CKAN metadata ("metadata")
+		location.LogicalLocations = []LogicalLocation{{FullyQualifiedName: issue.Subject, Kind: "package"}}
+	}
+	if location.PhysicalLocation == nil && location.LogicalLocations == nil {
+		return nil
+	}
+	return location
+}
+
+// fileURI returns the URI of a path, file:// for absolute paths
+func fileURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		return (&url.URL{Scheme: "file", Path: slashed}).String()
+	}
+	return (&url.URL{Path: slashed}).String()
+}
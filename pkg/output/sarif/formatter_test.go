@@ -0,0 +1,94 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+const testReport = `{
+  "timestamp": "2024-01-14T10:30:00Z",
+  "details_check_focused": [
+    {"checkname": "IsFreeOfKeywords", "issues": [
+      {"subject": "run.log", "path": "/data/my run.log", "message": "Credentials detected 'password'", "line": 3, "snippet": "»password« = x", "count": 2},
+      {"subject": "notes.txt", "path": "/data/data.zip", "archive_name": "data.zip", "message": "Credentials detected 'secret'", "line": 7}
+    ]},
+    {"checkname": "HasReadme", "severity": "info", "issues": [
+      {"subject": "repository", "path": "", "message": "No readme file found"}
+    ]}
+  ]
+}`
+
+func TestSARIFFormatter_FormatResults(t *testing.T) {
+	output, err := NewSARIFFormatter().FormatResults(testReport)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var log Log
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %s", output)
+	}
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "HasReadme" || run.Tool.Driver.Rules[1].ID != "IsFreeOfKeywords" {
+		t.Fatalf("Expected a rule per check ordered by name, got %+v", run.Tool.Driver.Rules)
+	}
+	if rule := run.Tool.Driver.Rules[1]; rule.DefaultConfiguration.Level != "error" || rule.ShortDescription == nil || rule.ShortDescription.Text == "" {
+		t.Errorf("Expected the level and description of the check, got %+v", rule)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(run.Results))
+	}
+
+	file := run.Results[0]
+	physical := file.Locations[0].PhysicalLocation
+	if file.RuleID != "IsFreeOfKeywords" || file.Level != "error" || file.Message.Text != "Credentials detected 'password'" || file.Properties["count"] != 2 {
+		t.Errorf("Unexpected result: %+v", file)
+	}
+	if physical.ArtifactLocation.URI != "file:///data/my%20run.log" || physical.Region.StartLine != 3 || physical.Region.Snippet.Text != "»password« = x" {
+		t.Errorf("Unexpected location: %+v", physical)
+	}
+
+	member := run.Results[1].Locations[0]
+	if member.PhysicalLocation.ArtifactLocation.URI != "file:///data/data.zip" || member.PhysicalLocation.Region != nil {
+		t.Errorf("Expected the archive without the line of its member, got %+v", member.PhysicalLocation)
+	}
+	if len(member.LogicalLocations) != 1 || member.LogicalLocations[0].FullyQualifiedName != "data.zip > notes.txt" {
+		t.Errorf("Expected the member as logical location, got %+v", member.LogicalLocations)
+	}
+
+	repository := run.Results[2]
+	if repository.Level != "note" || repository.Locations[0].PhysicalLocation != nil || repository.Locations[0].LogicalLocations[0].FullyQualifiedName != "repository" {
+		t.Errorf("Unexpected result for the package: %+v", repository)
+	}
+	if id := repository.PartialFingerprints[FingerprintKey]; id != structs.IssueID("HasReadme", "repository", "", "No readme file found") {
+		t.Errorf("Expected the stable ID of the issue as fingerprint, got %q", id)
+	}
+}
+
+func TestSARIFFormatter_Errors(t *testing.T) {
+	if _, err := NewSARIFFormatter().FormatResults("not json"); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestSARIFFormatter_ReportOnly(t *testing.T) {
+	report := `{"details_check_focused": [{"checkname": "IsFreeOfKeywords", "severity": "error", "report_only": true, "issues": [
+		{"subject": "run.log", "path": "/data/run.log", "message": "Credentials detected 'password'"}]}]}`
+	output, err := NewSARIFFormatter().FormatResults(report)
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+	var log Log
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Rules[0].DefaultConfiguration.Level != "note" || run.Results[0].Level != "note" {
+		t.Errorf("Expected the issues of a report-only check to be notes, got %+v", run)
+	}
+}
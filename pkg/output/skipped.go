@@ -0,0 +1,127 @@
+package output
+
+import "sync"
+
+// SkipCode is the machine-readable reason why a file was not checked, for filtering reports
+type SkipCode string
+
+const (
+	SkipBinary             SkipCode = "binary"              // Contents are binary
+	SkipTooLarge           SkipCode = "too_large"           // Larger than maxContentScanFileSize or maxArchiveFileSize
+	SkipMemoryLimit        SkipCode = "memory_limit"        // The memory budget of the scan is exhausted
+	SkipUnsupportedArchive SkipCode = "unsupported_archive" // Archive format that cannot be unpacked
+	SkipReadError          SkipCode = "read_error"          // The file or archive member could not be read
+	SkipEncrypted          SkipCode = "encrypted"           // Password-protected archive or archive members
+	SkipBlacklisted        SkipCode = "blacklisted"         // Matched by the blacklist of a test
+	SkipTimeout            SkipCode = "timeout"             // Checking it took longer than the fileTimeout
+	SkipDownloadFailed     SkipCode = "download_failed"     // The CkanCollector could not download it
+	SkipSymlink            SkipCode = "symlink"             // Symbolic link not followed, or broken
+	SkipHidden             SkipCode = "hidden"              // Hidden file or directory
+	SkipMaxDepth           SkipCode = "max_depth"           // Deeper than the maxDepth of the collector
+	SkipOtherFilesystem    SkipCode = "other_filesystem"    // On another file system than the scanned directory
+	SkipDuplicate          SkipCode = "duplicate"           // Already collected through another path
+	SkipSampled            SkipCode = "sampled"             // Larger than the sampleThreshold, only samples were checked
+	SkipIgnored            SkipCode = "ignored"             // Matched by a pattern of the .pcignore file
+)
+
+// SkipCodes lists all codes, in the order of the documentation
+var SkipCodes = []SkipCode{
+	SkipBinary, SkipTooLarge, SkipMemoryLimit, SkipUnsupportedArchive, SkipReadError, SkipEncrypted,
+	SkipBlacklisted,
+	SkipTimeout, SkipDownloadFailed, SkipSymlink, SkipHidden, SkipMaxDepth, SkipOtherFilesystem, SkipDuplicate,
+	SkipSampled, SkipIgnored,
+}
+
+// SkippedFile represents a file that was skipped during scanning
+type SkippedFile struct {
+	Filename    string   `json:"filename"`
+	Path        string   `json:"path"`
+	ArchiveName string   `json:"archive_name,omitempty"` // Parent archive if the file is inside an archive
+	Code        SkipCode `json:"code"`
+	Reason      string   `json:"reason"`
+	Location    string   `json:"location,omitempty"` // Location the file was skipped in, in merged reports
+}
+
+// SkippedFiles records the files one scan did not check, see config.WithSkippedFiles. A file
+// skipped twice for the same reason, such as by the file and the archive content checks, is
+// recorded once; a file that cannot be read is recorded once with the first error. A nil
+// SkippedFiles records nothing.
+type SkippedFiles struct {
+	mu       sync.Mutex
+	files    []SkippedFile
+	seen     map[SkippedFile]bool
+	reported map[string]bool // Paths whose read errors are reported by a check, see Reported
+}
+
+// NewSkippedFiles returns an empty record of skipped files for one scan
+func NewSkippedFiles() *SkippedFiles {
+	return &SkippedFiles{seen: map[SkippedFile]bool{}, reported: map[string]bool{}}
+}
+
+// key identifies the entries recorded once, the checks word their read errors differently
+func (file SkippedFile) key() SkippedFile {
+	if file.Code == SkipReadError {
+		file.Reason = ""
+	}
+	return file
+}
+
+// explained reports whether a check reported why the file, or the archive holding the member,
+// cannot be read; members are recorded with the path of their archive
+func (s *SkippedFiles) explained(file SkippedFile) bool {
+	return file.Code == SkipReadError && s.reported[file.Path]
+}
+
+// Add records that file was not checked
+func (s *SkippedFiles) Add(file SkippedFile) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[file.key()] || s.explained(file) {
+		return
+	}
+	s.seen[file.key()] = true
+	s.files = append(s.files, file)
+}
+
+// Reported leaves out the read errors of the file at path, e.g. of an archive IsArchiveIntact
+// reported as corrupt and of its members, as the report explains them already
+func (s *SkippedFiles) Reported(path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reported[path] = true
+	files := s.files[:0]
+	for _, file := range s.files {
+		if !s.explained(file) {
+			files = append(files, file)
+		}
+	}
+	s.files = files
+}
+
+// Files returns the recorded files in the order they were skipped, nil for a nil SkippedFiles
+func (s *SkippedFiles) Files() []SkippedFile {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SkippedFile(nil), s.files...)
+}
+
+// Reset forgets the recorded files, e.g. before the files are scanned again
+func (s *SkippedFiles) Reset() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = nil
+	s.seen = map[SkippedFile]bool{}
+	s.reported = map[string]bool{}
+}
@@ -0,0 +1,64 @@
+package output
+
+import "testing"
+
+func TestSkippedFiles(t *testing.T) {
+	skipped := NewSkippedFiles()
+	binary := SkippedFile{Filename: "image.png", Path: "/data/image.png", Code: SkipBinary, Reason: "The file seems to be binary."}
+
+	// Skipped files are recorded once, in the order they were skipped
+	skipped.Add(binary)
+	skipped.Add(binary)
+	skipped.Add(SkippedFile{Filename: "image.png", Path: "/data/image.png", Code: SkipTimeout, Reason: "Checking it took longer than 1s."})
+	if files := skipped.Files(); len(files) != 2 || files[0] != binary || files[1].Code != SkipTimeout {
+		t.Errorf("Expected the binary and the timed out file, got %+v", files)
+	}
+
+	skipped.Reset()
+	if files := skipped.Files(); len(files) != 0 {
+		t.Errorf("Expected Reset to forget the skipped files, got %+v", files)
+	}
+	skipped.Add(binary)
+	if files := skipped.Files(); len(files) != 1 {
+		t.Errorf("Expected a file skipped again after Reset to be recorded, got %+v", files)
+	}
+
+	// Without a record nothing is recorded
+	var none *SkippedFiles
+	none.Add(binary)
+	if files := none.Files(); files != nil {
+		t.Errorf("Expected no skipped files, got %+v", files)
+	}
+}
+
+func TestSkippedFilesReadErrors(t *testing.T) {
+	skipped := NewSkippedFiles()
+	listing := SkippedFile{Filename: "data.zip", Path: "/data/data.zip", Code: SkipReadError, Reason: "Error reading the file list of the archive: zip: not a valid zip file"}
+	content := SkippedFile{Filename: "data.zip", Path: "/data/data.zip", Code: SkipReadError, Reason: "Error opening zip file: zip: not a valid zip file"}
+	member := SkippedFile{Filename: "a.csv", Path: "/data/data.zip", ArchiveName: "data.zip", Code: SkipReadError, Reason: "Error reading archive member: unexpected EOF"}
+
+	// A file that cannot be read is recorded once, with the first error
+	skipped.Add(listing)
+	skipped.Add(content)
+	skipped.Add(member)
+	if files := skipped.Files(); len(files) != 2 || files[0] != listing || files[1] != member {
+		t.Errorf("Expected the first read error of the archive and the member, got %+v", files)
+	}
+
+	// Read errors of a reported archive and its members are left out, also when they come later
+	skipped.Reported("/data/data.zip")
+	skipped.Add(content)
+	skipped.Add(SkippedFile{Filename: "b.csv", Path: "/data/data.zip", ArchiveName: "data.zip", Code: SkipReadError, Reason: "Error reading archive member: unexpected EOF"})
+	if files := skipped.Files(); len(files) != 0 {
+		t.Errorf("Expected no read errors after the archive was reported, got %+v", files)
+	}
+
+	skipped.Reset()
+	skipped.Add(content)
+	if files := skipped.Files(); len(files) != 1 {
+		t.Errorf("Expected Reset to forget the reported archives, got %+v", files)
+	}
+
+	var none *SkippedFiles
+	none.Reported("/data/data.zip")
+}
@@ -0,0 +1,236 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// TemplateFormatter renders scan results through a user-supplied Go text/template, e.g. to write
+// emails or letters to depositors
+type TemplateFormatter struct {
+	tmpl *texttemplate.Template
+}
+
+// Data is what templates are executed with: the JSON report with the scanned location
+type Data struct {
+	Location string
+	jsonformatter.ScanResult
+}
+
+// Issue is one issue of the report, as returned by the issues template function
+type Issue struct {
+	ID          string // Stable ID of the finding
+	Check       string
+	Severity    string
+	Subject     string
+	Path        string
+	ArchiveName string
+	Message     string
+	Line        int
+	Count       int // Number of identical findings merged into this issue, at least 1
+}
+
+// File is the subject of the issue with the archive it is in, if any
+func (i Issue) File() string {
+	if i.ArchiveName != "" {
+		return i.ArchiveName + " > " + i.Subject
+	}
+	return i.Subject
+}
+
+// Group is a set of issues sharing a check, file or severity, as returned by groupBy
+type Group struct {
+	Key    string
+	Issues []Issue
+}
+
+// NewTemplateFormatter parses the template file at path
+func NewTemplateFormatter(path string) (*TemplateFormatter, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := texttemplate.New(filepath.Base(path)).Funcs(Funcs()).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// FormatResults renders the JSON report of a scan of location
+func (f *TemplateFormatter) FormatResults(jsonResult string, location string) (string, error) {
+	result, err := jsonformatter.Parse([]byte(jsonResult))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JSON data: %w", err)
+	}
+	return f.Format(result, location)
+}
+
+// Format renders the report of a scan of location
+func (f *TemplateFormatter) Format(result *jsonformatter.ScanResult, location string) (string, error) {
+	data := Data{Location: location, ScanResult: *result}
+
+	var sb strings.Builder
+	if err := f.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// Funcs returns the helper functions available in templates:
+//
+//	issues .            all issues of the report, ordered by check and file
+//	severity "error" l  the issues of l with one of the given severities
+//	check "Name" l      the issues of l reported by one of the given checks
+//	groupBy "check" l   l grouped by "check", "file" or "severity", ordered by key
+//	count l             the number of findings in l, counting merged findings
+//	files l             the distinct files of l
+//	plural n "issue"    "1 issue", "2 issues"
+//
+// as well as join, lower, upper and trim from the strings package.
+func Funcs() texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"issues":   issues,
+		"severity": filterSeverity,
+		"check":    filterCheck,
+		"groupBy":  groupBy,
+		"count":    count,
+		"files":    files,
+		"plural":   plural,
+		"join":     strings.Join,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"trim":     strings.TrimSpace,
+	}
+}
+
+func issues(data Data) []Issue {
+	var result []Issue
+	for _, check := range data.DetailsCheckFocused {
+		severity := check.Severity
+		if severity == "" {
+			// Reports written before severities were recorded
+			severity = string(checks.SeverityOf(check.Checkname))
+		}
+		for _, issue := range check.Issues {
+			result = append(result, Issue{
+				ID:          issue.IssueID(check.Checkname),
+				Check:       check.Checkname,
+				Severity:    severity,
+				Subject:     issue.Subject,
+				Path:        issue.Path,
+				ArchiveName: issue.ArchiveName,
+				Message:     issue.Message,
+				Line:        issue.Line,
+				Count:       max(issue.Count, 1),
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Check != result[j].Check {
+			return result[i].Check < result[j].Check
+		}
+		return result[i].File() < result[j].File()
+	})
+	return result
+}
+
+func filterSeverity(args ...interface{}) ([]Issue, error) {
+	return filter("severity", args, func(issue Issue) string { return issue.Severity })
+}
+
+func filterCheck(args ...interface{}) ([]Issue, error) {
+	return filter("check", args, func(issue Issue) string { return issue.Check })
+}
+
+// filter keeps the issues whose key is one of the values; args are the values followed by the
+// issues, so filters can be used in pipelines
+func filter(name string, args []interface{}, key func(Issue) string) ([]Issue, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s: expected values and a list of issues", name)
+	}
+	list, ok := args[len(args)-1].([]Issue)
+	if !ok {
+		return nil, fmt.Errorf("%s: last argument must be a list of issues", name)
+	}
+	values := make(map[string]bool, len(args)-1)
+	for _, arg := range args[:len(args)-1] {
+		value, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: values must be strings", name)
+		}
+		values[strings.ToLower(value)] = true
+	}
+
+	var result []Issue
+	for _, issue := range list {
+		if values[strings.ToLower(key(issue))] {
+			result = append(result, issue)
+		}
+	}
+	return result, nil
+}
+
+func groupBy(field string, list []Issue) ([]Group, error) {
+	var key func(Issue) string
+	switch field {
+	case "check":
+		key = func(issue Issue) string { return issue.Check }
+	case "file":
+		key = Issue.File
+	case "severity":
+		key = func(issue Issue) string { return issue.Severity }
+	default:
+		return nil, fmt.Errorf("groupBy: unknown field %q (use check, file or severity)", field)
+	}
+
+	index := make(map[string]int)
+	var groups []Group
+	for _, issue := range list {
+		k := key(issue)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Key: k})
+		}
+		groups[i].Issues = append(groups[i].Issues, issue)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
+
+func count(list []Issue) int {
+	total := 0
+	for _, issue := range list {
+		total += issue.Count
+	}
+	return total
+}
+
+func files(list []Issue) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, issue := range list {
+		if file := issue.File(); !seen[file] {
+			seen[file] = true
+			result = append(result, file)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,81 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testReport = `{
+  "timestamp": "2024-01-14T10:30:00Z",
+  "details_check_focused": [
+    {"checkname": "HasNoWhiteSpace", "severity": "warning", "issues": [
+      {"subject": "my file.txt", "path": "/data/my file.txt", "message": "File name contains spaces"}
+    ]},
+    {"checkname": "IsFreeOfKeywords", "issues": [
+      {"subject": "run.log", "path": "/data/run.log", "message": "Credentials detected 'password'", "line": 3, "count": 2},
+      {"subject": "notes.txt", "path": "/data/notes.txt", "archive_name": "data.zip", "message": "Credentials detected 'secret'"}
+    ]}
+  ]
+}`
+
+func writeTemplate(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "letter.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTemplateFormatter_FormatResults(t *testing.T) {
+	path := writeTemplate(t, `Dear depositor of {{.Location}},
+{{- $all := issues .}}
+we found {{plural (count $all) "issue"}} in {{plural (len (files $all)) "file"}} on {{.Timestamp}}.
+{{- range groupBy "check" $all}}
+{{.Key}}:{{range .Issues}} {{.File}}{{if .Line}}:{{.Line}}{{end}}{{end}}
+{{- end}}
+Errors: {{range $i, $issue := issues . | severity "error"}}{{if $i}}; {{end}}{{$issue.Message}}{{end}}
+Spaces: {{len (issues . | check "HasNoWhiteSpace")}}`)
+
+	formatter, err := NewTemplateFormatter(path)
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+	result, err := formatter.FormatResults(testReport, "my-package")
+	if err != nil {
+		t.Fatalf("FormatResults failed: %v", err)
+	}
+
+	expected := `Dear depositor of my-package,
+we found 4 issues in 3 files on 2024-01-14T10:30:00Z.
+HasNoWhiteSpace: my file.txt
+IsFreeOfKeywords: data.zip > notes.txt run.log:3
+Errors: Credentials detected 'secret'; Credentials detected 'password'
+Spaces: 1`
+	if result != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestNewTemplateFormatter_Errors(t *testing.T) {
+	if _, err := NewTemplateFormatter(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("Expected an error for a missing template")
+	}
+	if _, err := NewTemplateFormatter(writeTemplate(t, "{{range}}")); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestTemplateFormatter_ExecutionErrors(t *testing.T) {
+	formatter, err := NewTemplateFormatter(writeTemplate(t, `{{groupBy "size" (issues .)}}`))
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+	if _, err := formatter.FormatResults(testReport, ""); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+	if _, err := formatter.FormatResults("not json", ""); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
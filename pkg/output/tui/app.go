@@ -4,20 +4,24 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/eawag-rdm/pc/pkg/baseline"
+	"github.com/eawag-rdm/pc/pkg/i18n"
 	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
 // copyToClipboardOSC52 uses OSC 52 escape sequence to copy to clipboard.
 // This works over SSH/tmux when the terminal supports it.
-// Writes directly to /dev/tty to bypass tview's terminal capture.
+// Writes directly to the terminal device (ttyPath) to bypass tview's terminal capture.
 func copyToClipboardOSC52(text string) error {
-	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	tty, err := os.OpenFile(ttyPath, os.O_WRONLY, 0)
 	if err != nil {
 		return err
 	}
@@ -29,6 +33,19 @@ func copyToClipboardOSC52(text string) error {
 	return err
 }
 
+// copyToClipboard copies text to the system clipboard and falls back to OSC 52 for remote/tmux
+// environments. It reports whether OSC 52 was used; the error is the one of the system clipboard.
+func copyToClipboard(text string) (osc52 bool, err error) {
+	err = clipboard.WriteAll(text)
+	if err == nil {
+		return false, nil
+	}
+	if osc52Err := copyToClipboardOSC52(text); osc52Err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 type App struct {
 	app               *tview.Application
 	data              *ScanResult
@@ -49,23 +66,37 @@ type App struct {
 	selectedLeftPanel int    // Currently selected left panel (0=subjects, 1=checks)
 	isScanning        bool   // Whether we're currently scanning
 	startupCallback   func() // Called when TUI starts running
+	rescanFunc        func() // Scans the location again, nil if there is nothing to rescan
 	location          string // Location/path being scanned (for summary)
+	letter            *Letter // Template of the copy-paste summary, nil for the built-in text
+	truncation        Truncation // Issues listed of groups of similar issues in the summaries
 	summaryModal      *tview.Flex     // Modal overlay for summary
 	summaryTextView   *tview.TextView // Scrollable summary content
 	summaryVisible    bool            // Track modal visibility
+	header            *tview.Flex     // Top bar showing the controls or an input
+	prompting         bool            // An input is shown in place of the controls
+	subjectNames      []string        // Subjects in the subjects list, filtered by the search
+	checkNames        []string        // Checks in the checks list, filtered by the search
+	search            searchState
+	exportInput       *tview.InputField
+	triage            triageState
+	severity          severityFilter // Severities of the issues shown
+	reports           reportsState   // Reports of the viewer to switch between
+	index             reportIndex    // Lookup maps of data
+	mainContent       *tview.Flex    // Left and right panel side by side
+	leftPaneWidth     int            // Width of the left panel in tenths of the window
 }
 
 func NewApp(data *ScanResult) *App {
-	if data != nil {
-		data.BuildCache()
-	}
 	app := &App{
 		app:               tview.NewApplication(),
 		data:              data,
+		index:             newReportIndex(data),
 		currentView:       "subjects",
 		selectedSection:   0,
 		selectedLeftPanel: 0, // Start with subjects selected
 		isScanning:        false, // Not scanning for regular TUI
+		truncation:        DefaultTruncation,
 	}
 	app.setupUI()
 	return app
@@ -88,16 +119,18 @@ func NewScanningApp() *App {
 	app := &App{
 		app:               tview.NewApplication(),
 		data:              emptyData,
+		index:             newReportIndex(emptyData),
 		currentView:       "subjects",
 		selectedSection:   0,
 		selectedLeftPanel: 0, // Start with subjects selected
 		isScanning:        true, // Start in scanning mode
+		truncation:        DefaultTruncation,
 	}
 	app.setupUI()
 	
 	// Set initial scanning message
 	app.updateInfo()
-	app.progressBar.SetText("Preparing to scan...")
+	app.progressBar.SetText(i18n.T("Preparing to scan..."))
 	
 	return app
 }
@@ -108,7 +141,7 @@ func (a *App) setupUI() {
 	a.checksList = tview.NewList().ShowSecondaryText(false)
 	a.leftSections = tview.NewTextView().SetDynamicColors(true).SetWrap(true)
 	a.leftContent = tview.NewFlex().SetDirection(tview.FlexRow)
-	a.detailsContent = tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true)
+	a.detailsContent = tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetScrollable(true).SetWrap(true)
 	
 	// Set up faster scrolling for details content
 	a.detailsContent.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -131,13 +164,13 @@ func (a *App) setupUI() {
 	a.progressBar = tview.NewTextView().SetDynamicColors(true)
 
 	// Set up borders and titles
-	a.subjectsList.SetBorder(true).SetTitle(" Issues ")
-	a.checksList.SetBorder(true).SetTitle(" Issues ")
-	a.leftSections.SetBorder(true).SetTitle(" Focused on ")
-	a.detailsContent.SetBorder(true).SetTitle(" Details ")
-	a.info.SetBorder(true).SetTitle(" Summary ")
-	a.controls.SetBorder(true).SetTitle(" Controls ")
-	a.progressBar.SetBorder(true).SetTitle(" Scan Progress ")
+	a.subjectsList.SetBorder(true).SetTitle(" " + i18n.T("Issues") + " ")
+	a.checksList.SetBorder(true).SetTitle(" " + i18n.T("Issues") + " ")
+	a.leftSections.SetBorder(true).SetTitle(" " + i18n.T("Focused on") + " ")
+	a.detailsContent.SetBorder(true).SetTitle(" " + i18n.T("Details") + " ")
+	a.info.SetBorder(true).SetTitle(" " + i18n.T("Summary") + " ")
+	a.controls.SetBorder(true).SetTitle(" " + i18n.T("Controls") + " ")
+	a.progressBar.SetBorder(true).SetTitle(" " + i18n.T("Scan Progress") + " ")
 
 	// Create left panel with all categories (subjects, checks, skipped, warnings, errors)
 	a.leftPanel = tview.NewFlex().SetDirection(tview.FlexRow).
@@ -145,17 +178,21 @@ func (a *App) setupUI() {
 		AddItem(a.leftContent, 0, 1, true)
 
 	a.rightPanel = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(a.info, 6, 0, false).
+		AddItem(a.info, 7, 0, false).
 		AddItem(a.detailsContent, 0, 1, false)
 
-	mainContent := tview.NewFlex().
-		AddItem(a.leftPanel, 0, 1, true).
-		AddItem(a.rightPanel, 0, 1, false)  // Changed ratio to give more space to left panel
+	a.leftPaneWidth = defaultLeftPaneWidth
+	a.mainContent = tview.NewFlex().
+		AddItem(a.leftPanel, 0, a.leftPaneWidth, true).
+		AddItem(a.rightPanel, 0, 10-a.leftPaneWidth, false)
+
+	// The controls are replaced by the search input while searching
+	a.header = tview.NewFlex().AddItem(a.controls, 0, 1, false)
 
 	// Main layout - always include progress bar (hidden when not scanning)
 	a.flex = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(a.controls, 3, 0, false).
-		AddItem(mainContent, 0, 1, false).
+		AddItem(a.header, 3, 0, false).
+		AddItem(a.mainContent, 0, 1, false).
 		AddItem(a.progressBar, 3, 0, false)
 	
 	// Hide progress bar initially unless scanning
@@ -171,14 +208,21 @@ func (a *App) setupUI() {
 	a.updateInfo()
 	a.updateControls()
 
-	// Set up key bindings
+	// Set up key bindings and the mouse
 	a.setupKeyBindings()
+	a.setupMouse()
 
 	// Set up resize handler for responsive sections
 	a.setupResizeHandler()
 
-	// Set up summary modal
+	// Set up summary, triage and report modals
 	a.setupSummaryModal()
+	a.setupTriageModal()
+	a.setupReportPicker()
+
+	// Set up the search and export inputs
+	a.setupSearch()
+	a.setupExport()
 
 	// Set root
 	a.app.SetRoot(a.flex, true)
@@ -189,38 +233,65 @@ func (a *App) populateSubjectsList() {
 
 	// Pre-allocate with known capacity
 	capacity := len(a.data.Scanned)
-	if a.data.cachedHasRepository {
+	if a.index.hasRepository {
 		capacity++
 	}
 	subjectNames := make([]string, 0, capacity)
 
 	// Add scanned files
 	for _, file := range a.data.Scanned {
+		if !a.search.matches(a.subjectDetailsText(file.Filename)) {
+			continue
+		}
 		issueCount := 0
+		worst := ""
 		for _, issue := range file.Issues {
+			if !a.showsCheck(issue.Checkname) {
+				continue
+			}
 			issueCount += issue.IssueCount
+			if severity := a.index.severityOf(issue.Checkname); severityRank[severity] > severityRank[worst] {
+				worst = severity
+			}
+		}
+		if issueCount == 0 {
+			continue
 		}
 
-		mainText := fmt.Sprintf("%s (%d)", file.Filename, issueCount)
+		mainText := fmt.Sprintf("[%s]%s (%d)", severityColor(worst), file.Filename, issueCount)
 		a.subjectsList.AddItem(mainText, "", 0, nil)
 		subjectNames = append(subjectNames, file.Filename)
 	}
 
 	// Add repository if cached flag indicates it exists
-	if a.data.cachedHasRepository {
-		if repo, ok := a.data.subjectIndex["repository"]; ok {
-			issueCount := len(repo.Issues)
-			mainText := fmt.Sprintf("repository (%d)", issueCount)
-			a.subjectsList.AddItem(mainText, "", 0, nil)
-			subjectNames = append(subjectNames, "repository")
+	if a.index.hasRepository && a.search.matches(a.subjectDetailsText("repository")) {
+		if repo, ok := a.index.subjects["repository"]; ok {
+			issueCount := 0
+			worst := ""
+			for _, issue := range repo.Issues {
+				if !a.showsCheck(issue.Checkname) {
+					continue
+				}
+				issueCount++
+				if severity := a.index.severityOf(issue.Checkname); severityRank[severity] > severityRank[worst] {
+					worst = severity
+				}
+			}
+			if issueCount > 0 {
+				mainText := fmt.Sprintf("[%s]repository (%d)", severityColor(worst), issueCount)
+				a.subjectsList.AddItem(mainText, "", 0, nil)
+				subjectNames = append(subjectNames, "repository")
+			}
 		}
 	}
 
+	a.subjectNames = subjectNames
+
 	// Set up selection change handler for automatic details update
 	a.subjectsList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
-		if index >= 0 && index < len(subjectNames) {
+		if index >= 0 && index < len(a.subjectNames) {
 			// Update current subject and refresh details
-			a.currentSubject = subjectNames[index]
+			a.currentSubject = a.subjectNames[index]
 			if a.currentView == "subjects" {
 				a.showSubjectDetails()
 			}
@@ -235,19 +306,24 @@ func (a *App) populateChecksList() {
 	var checkNames []string
 	
 	for _, check := range a.data.DetailsCheckFocused {
+		if !a.showsCheck(check.Checkname) || !a.search.matches(a.checkDetailsText(check.Checkname)) {
+			continue
+		}
 		issueCount := len(check.Issues)
 		
-		mainText := fmt.Sprintf("%s (%d)", check.Checkname, issueCount)
+		mainText := fmt.Sprintf("[%s]%s (%d)", severityColor(a.index.severityOf(check.Checkname)), check.Checkname, issueCount)
 		
 		a.checksList.AddItem(mainText, "", 0, nil)
 		checkNames = append(checkNames, check.Checkname)
 	}
 	
+	a.checkNames = checkNames
+
 	// Set up selection change handler for automatic details update
 	a.checksList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
-		if index >= 0 && index < len(checkNames) {
+		if index >= 0 && index < len(a.checkNames) {
 			// Update current check and refresh details
-			a.currentSubject = checkNames[index]
+			a.currentSubject = a.checkNames[index]
 			if a.currentView == "checks" {
 				a.showCheckDetails()
 			}
@@ -263,19 +339,24 @@ func (a *App) updateInfo() {
 	}
 
 	// Use cached total instead of iterating
-	totalIssues := a.data.cachedTotalIssues
+	totalIssues := fmt.Sprintf("%d", a.index.totalIssues)
+	if suppressed := a.data.Suppressed; suppressed != nil {
+		totalIssues += fmt.Sprintf(" (+%d in baseline)", suppressed.Accepted+suppressed.FalsePositives)
+	}
 
 	info := fmt.Sprintf(
 		"[yellow]PC Scanner Results[white]\n"+
 			"Timestamp: %s\n"+
 			"Scanned: %d  |  Skipped: %d\n"+
-			"Issues: %d  |  Errors: %d  |  Warnings: %d",
-		a.data.Timestamp,
+			"Issues: %s  |  Errors: %d  |  Warnings: %d\n"+
+			"Severity: %s",
+		i18n.FormatTimestamp(a.data.Timestamp),
 		totalScanned,
 		totalSkipped,
 		totalIssues,
 		len(a.data.Errors),
 		len(a.data.Warnings),
+		a.severityBreakdown(),
 	)
 
 	a.info.SetText(info)
@@ -303,10 +384,29 @@ func (a *App) updateControls() {
 		}
 	}
 
+	controls = strings.Replace(controls, "  [yellow]X[white]=Summary", "  [yellow]/[white]=Search  [yellow]L[white]=Level  [yellow]T[white]=Triage  [yellow]X[white]=Summary  [yellow]E[white]=Export  [yellow]Y[white]=Copy  [yellow]O[white]=Open  [yellow]R[white]=Rescan  [yellow][ ][white]=Resize", 1)
+	if len(a.reports.list) > 1 {
+		controls = strings.Replace(controls, "  [yellow]Q[white]=Quit", "  [yellow]P[white]=Reports  [yellow]Q[white]=Quit", 1)
+	}
+	controls = translateControls(controls)
+	if status := a.search.status(); status != "" {
+		controls += "  " + status
+	}
+
 	a.controls.SetText(controls)
 }
 
 
+// controlLabel matches the labels of the keys in the controls
+var controlLabel = regexp.MustCompile(`\[white\]=([A-Za-z]+)`)
+
+// translateControls translates the labels of the keys in the controls
+func translateControls(controls string) string {
+	return controlLabel.ReplaceAllStringFunc(controls, func(label string) string {
+		return "[white]=" + i18n.T(label[len("[white]="):])
+	})
+}
+
 func (a *App) setupResizeHandler() {
 	// Set up a periodic refresh to check for size changes
 	// This will handle terminal resize events
@@ -331,11 +431,60 @@ func (a *App) setupKeyBindings() {
 			return event
 		}
 
+		// Handle triage modal input separately
+		if a.triage.visible {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				a.closeTriage()
+				return nil
+			}
+			switch event.Rune() {
+			case 'a', 'A':
+				a.markTriage(baseline.StatusAccepted)
+				return nil
+			case 'f', 'F':
+				a.markTriage(baseline.StatusFalsePositive)
+				return nil
+			case 't', 'T', 'q', 'Q':
+				a.closeTriage()
+				return nil
+			}
+			return event
+		}
+
+		// Handle report picker input separately
+		if a.reports.visible {
+			switch event.Key() {
+			case tcell.KeyEsc:
+				a.closeReportPicker()
+				return nil
+			}
+			switch event.Rune() {
+			case 'p', 'P', 'q', 'Q':
+				a.closeReportPicker()
+				return nil
+			}
+			return event
+		}
+
+		// The search and export inputs handle their own keys
+		if a.prompting {
+			return event
+		}
+
 		switch event.Key() {
 		case tcell.KeyTab:
 			a.switchFocus()
 			return nil
-		case tcell.KeyEsc, tcell.KeyCtrlC:
+		case tcell.KeyEsc:
+			// Esc clears an active search before it quits
+			if a.search.query != "" {
+				a.applySearch("")
+				return nil
+			}
+			a.app.Stop()
+			return nil
+		case tcell.KeyCtrlC:
 			a.app.Stop()
 			return nil
 		}
@@ -358,6 +507,42 @@ func (a *App) setupKeyBindings() {
 		case 'x', 'X':
 			a.showSummaryModal()
 			return nil
+		case '/':
+			a.openSearch()
+			return nil
+		case 'e', 'E':
+			a.openExport()
+			return nil
+		case 'y', 'Y':
+			a.copyCurrentView()
+			return nil
+		case 't', 'T':
+			a.openTriage()
+			return nil
+		case 'l', 'L':
+			a.cycleSeverityFilter()
+			return nil
+		case 'r', 'R':
+			a.rescan()
+			return nil
+		case 'o', 'O':
+			a.openSelectedFile()
+			return nil
+		case 'p', 'P':
+			a.openReportPicker()
+			return nil
+		case '[':
+			a.resizePanes(-1)
+			return nil
+		case ']':
+			a.resizePanes(1)
+			return nil
+		case 'n':
+			a.nextSearchHit(1)
+			return nil
+		case 'N':
+			a.nextSearchHit(-1)
+			return nil
 		}
 
 		// Handle arrow keys for navigation
@@ -407,6 +592,7 @@ func (a *App) switchFocus() {
 func (a *App) focusSubjects() {
 	a.currentView = "subjects"
 	a.selectedLeftPanel = 0
+	a.collectSearchHits()
 	a.populateLeftSections()
 	a.showSubjectsPanel()
 	a.app.SetFocus(a.subjectsList)
@@ -421,6 +607,7 @@ func (a *App) focusSubjects() {
 func (a *App) focusChecks() {
 	a.currentView = "checks"
 	a.selectedLeftPanel = 1
+	a.collectSearchHits()
 	a.populateLeftSections()
 	a.showChecksPanel()
 	a.app.SetFocus(a.checksList)
@@ -518,14 +705,10 @@ func (a *App) populateLeftSections() {
 	for i, section := range sections {
 		var count int
 		switch i {
-		case 0: // Subjects
-			count = len(a.data.Scanned)
-			// Use cached flag instead of loop
-			if a.data.cachedHasRepository {
-				count++
-			}
+		case 0: // Subjects, as listed with the search and severity filter
+			count = len(a.subjectNames)
 		case 1: // Checks
-			count = len(a.data.DetailsCheckFocused)
+			count = len(a.checkNames)
 		case 2: // PDFs
 			count = len(a.data.PDFFiles)
 		case 3: // Skipped
@@ -538,10 +721,11 @@ func (a *App) populateLeftSections() {
 
 		var sectionText string
 		if i == a.selectedLeftPanel {
-			sectionText = fmt.Sprintf("[black:white]%s (%d)[-:-]", section, count)
+			sectionText = fmt.Sprintf("[black:white]%s (%d)[-:-]", i18n.T(section), count)
 		} else {
-			sectionText = fmt.Sprintf("[white]%s (%d)", section, count)
+			sectionText = fmt.Sprintf("[white]%s (%d)", i18n.T(section), count)
 		}
+		sectionText = sectionRegion(i, sectionText)
 		sectionTexts = append(sectionTexts, sectionText)
 	}
 
@@ -566,94 +750,121 @@ func (a *App) showEmptyLeftPanel(title string) {
 	a.leftContent.Clear()
 	emptyView := tview.NewTextView().SetDynamicColors(true)
 	emptyView.SetText(fmt.Sprintf("[dim]%s[white]\n\n[dim]Details shown in right panel[white]", title))
-	emptyView.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", title))
+	emptyView.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", i18n.T(title)))
 	a.leftContent.SetDirection(tview.FlexRow).
 		AddItem(emptyView, 0, 1, true)
 }
 
 func (a *App) showSubjectDetails() {
 	if a.currentSubject == "" {
-		a.detailsContent.SetText("[dim]No subject selected[white]")
+		a.detailsContent.SetText("[dim]" + i18n.T("No subject selected") + "[white]")
 		return
 	}
+	a.detailsContent.SetText(a.search.highlight(a.subjectDetailsText(a.currentSubject)))
+}
 
+// subjectDetailsText formats the issues of a subject for the details panel
+func (a *App) subjectDetailsText(name string) string {
 	// O(1) lookup instead of O(n) loop
-	subject, ok := a.data.subjectIndex[a.currentSubject]
+	subject, ok := a.index.subjects[name]
 	if !ok {
-		a.detailsContent.SetText("[dim]No details found[white]")
-		return
+		return "[dim]" + i18n.T("No details found") + "[white]"
 	}
 
 	// Use strings.Builder instead of += concatenation
 	var sb strings.Builder
 	sb.Grow(256 + len(subject.Issues)*100) // Pre-allocate estimated size
 
-	sb.WriteString("[yellow]Subject: ")
+	sb.WriteString("[yellow]" + i18n.T("Subject:") + " ")
 	sb.WriteString(subject.Subject)
 	sb.WriteString("[white]\n")
 
 	if subject.ArchiveName != "" {
-		sb.WriteString("Archive: ")
+		sb.WriteString(i18n.T("Archive:") + " ")
 		sb.WriteString(subject.ArchiveName)
 		sb.WriteString("\n")
 	}
 	if subject.Path != "" {
-		sb.WriteString("Path: ")
+		sb.WriteString(i18n.T("Path:") + " ")
 		sb.WriteString(subject.Path)
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(fmt.Sprintf("\n[green]Issues (%d):[white]\n", len(subject.Issues)))
+	issues := make([]CheckIssue, 0, len(subject.Issues))
+	for _, issue := range subject.Issues {
+		if a.showsCheck(issue.Checkname) {
+			issues = append(issues, issue)
+		}
+	}
+	sb.WriteString("\n[green]" + i18n.Tf("Issues (%d):", len(issues)) + "[white]\n")
 
-	for i, issue := range subject.Issues {
-		sb.WriteString(fmt.Sprintf("\n[cyan]%d. %s[white]\n", i+1, issue.Checkname))
+	for i, issue := range issues {
+		sb.WriteString(fmt.Sprintf("\n[%s]%d. %s[white]%s\n", severityColor(a.index.severityOf(issue.Checkname)), i+1, issue.Checkname,
+			triageLabel(a.triageStatus(scannedFolder(a.data, subject.Location), issue.Checkname, subject.Path, subject.ArchiveName, issue.Message))))
 		sb.WriteString("   ")
 		sb.WriteString(issue.Message)
 		sb.WriteString("\n")
+		sb.WriteString(formatIssueLocation(structs.Message{Line: issue.Line, Lines: issue.Lines, Count: issue.Count, Snippet: issue.Snippet}))
 	}
 
-	a.detailsContent.SetText(sb.String())
+	return sb.String()
 }
 
 func (a *App) showCheckDetails() {
 	if a.currentSubject == "" {
-		a.detailsContent.SetText("[dim]No check selected[white]")
+		a.detailsContent.SetText("[dim]" + i18n.T("No check selected") + "[white]")
 		return
 	}
+	a.detailsContent.SetText(a.search.highlight(a.checkDetailsText(a.currentSubject)))
+}
 
+// checkDetailsText formats the issues of a check for the details panel
+func (a *App) checkDetailsText(name string) string {
 	// O(1) lookup instead of O(n) loop
-	check, ok := a.data.checkIndex[a.currentSubject]
+	check, ok := a.index.checks[name]
 	if !ok {
-		a.detailsContent.SetText("[dim]No details found[white]")
-		return
+		return "[dim]" + i18n.T("No details found") + "[white]"
 	}
 
 	// Use strings.Builder with pre-allocation
 	var sb strings.Builder
 	sb.Grow(128 + len(check.Issues)*150)
 
-	sb.WriteString("[yellow]Check: ")
-	sb.WriteString(a.currentSubject)
-	sb.WriteString("[white]\n")
-	sb.WriteString(fmt.Sprintf("\n[green]Issues (%d):[white]\n", len(check.Issues)))
+	severity := a.index.severityOf(name)
+	sb.WriteString("[yellow]" + i18n.T("Check:") + " ")
+	sb.WriteString(name)
+	sb.WriteString(fmt.Sprintf(" [%s](%s)[white]\n", severityColor(severity), severity))
+	sb.WriteString("\n[green]" + i18n.Tf("Issues (%d):", len(check.Issues)) + "[white]\n")
 
 	for i, issue := range check.Issues {
+		label := triageLabel(a.triageStatus(scannedFolder(a.data, issue.Location), name, issue.Path, issue.ArchiveName, issue.Message))
 		if issue.ArchiveName != "" {
-			sb.WriteString(fmt.Sprintf("\n[cyan]%d. %s > %s[white]\n", i+1, issue.ArchiveName, issue.Subject))
+			sb.WriteString(fmt.Sprintf("\n[cyan]%d. %s > %s[white]%s\n", i+1, issue.ArchiveName, issue.Subject, label))
 		} else {
-			sb.WriteString(fmt.Sprintf("\n[cyan]%d. %s[white]\n", i+1, issue.Subject))
+			sb.WriteString(fmt.Sprintf("\n[cyan]%d. %s[white]%s\n", i+1, issue.Subject, label))
 		}
 		if issue.Path != "" {
-			sb.WriteString("   Path: ")
+			sb.WriteString("   " + i18n.T("Path:") + " ")
 			sb.WriteString(issue.Path)
 			sb.WriteString("\n")
 		}
 		sb.WriteString("   ")
 		sb.WriteString(issue.Message)
 		sb.WriteString("\n")
+		sb.WriteString(formatIssueLocation(structs.Message{Line: issue.Line, Lines: issue.Lines, Count: issue.Count, Snippet: issue.Snippet}))
 	}
 
-	a.detailsContent.SetText(sb.String())
+	return sb.String()
+}
+
+// formatIssueLocation formats the occurrences, lines and snippet of an issue as an indented detail line,
+// empty if none are known
+func formatIssueLocation(location structs.Message) string {
+	text := location.Location()
+	if text == "" {
+		return ""
+	}
+	return "   [dim]" + tview.Escape(text) + "[white]\n"
 }
 
 func (a *App) showSkippedDetails() {
@@ -684,7 +895,7 @@ func (a *App) getPDFsContent() string {
 	var sb strings.Builder
 	sb.Grow(64 + len(a.data.PDFFiles)*80)
 
-	sb.WriteString(fmt.Sprintf("[yellow]PDF Files (%d):[white]\n\n", len(a.data.PDFFiles)))
+	sb.WriteString(fmt.Sprintf("[yellow]%s (%d):[white]\n\n", i18n.T("PDF Files"), len(a.data.PDFFiles)))
 	for i, file := range a.data.PDFFiles {
 		sb.WriteString(fmt.Sprintf("[cyan]%d.[white] %s\n", i+1, file))
 	}
@@ -717,6 +928,7 @@ func (a *App) switchToSelectedLeftPanel() {
 	
 	// Set navigation header to yellow
 	a.leftSections.SetBorderColor(tcell.ColorYellow)
+	a.collectSearchHits()
 	
 	switch a.selectedLeftPanel {
 	case 0: // Subjects
@@ -767,23 +979,23 @@ func (a *App) updateDetailsForCurrentSelection() {
 	// Get the currently selected item from the active list
 	if a.currentView == "subjects" {
 		currentIndex := a.subjectsList.GetCurrentItem()
-		if currentIndex >= 0 {
-			// Get subject name based on index
-			if currentIndex < len(a.data.Scanned) {
-				a.currentSubject = a.data.Scanned[currentIndex].Filename
-			} else {
-				// Must be repository
-				a.currentSubject = "repository"
-			}
+		if currentIndex >= 0 && currentIndex < len(a.subjectNames) {
+			a.currentSubject = a.subjectNames[currentIndex]
 			// Update details panel with selected subject
 			a.showSubjectDetails()
+		} else if a.filtering() {
+			a.currentSubject = ""
+			a.detailsContent.SetText("[dim]No matches[white]")
 		}
 	} else if a.currentView == "checks" {
 		currentIndex := a.checksList.GetCurrentItem()
-		if currentIndex >= 0 && currentIndex < len(a.data.DetailsCheckFocused) {
-			a.currentSubject = a.data.DetailsCheckFocused[currentIndex].Checkname
+		if currentIndex >= 0 && currentIndex < len(a.checkNames) {
+			a.currentSubject = a.checkNames[currentIndex]
 			// Update details panel with selected check
 			a.showCheckDetails()
+		} else if a.filtering() {
+			a.currentSubject = ""
+			a.detailsContent.SetText("[dim]No matches[white]")
 		}
 	}
 }
@@ -796,9 +1008,13 @@ func (a *App) getSkippedContent() string {
 	var sb strings.Builder
 	sb.Grow(64 + len(a.data.Skipped)*100)
 
-	sb.WriteString(fmt.Sprintf("[yellow]Skipped Files (%d):[white]\n\n", len(a.data.Skipped)))
+	sb.WriteString(fmt.Sprintf("[yellow]%s (%d):[white]\n\n", i18n.T("Skipped Files"), len(a.data.Skipped)))
 	for i, file := range a.data.Skipped {
-		sb.WriteString(fmt.Sprintf("[cyan]%d.[white] %s\n", i+1, file.Filename))
+		name := file.Filename
+		if file.ArchiveName != "" {
+			name = file.ArchiveName + " > " + name
+		}
+		sb.WriteString(fmt.Sprintf("[cyan]%d.[white] %s\n", i+1, name))
 		if file.Path != "" {
 			sb.WriteString("   [dim]Path: ")
 			sb.WriteString(file.Path)
@@ -806,6 +1022,9 @@ func (a *App) getSkippedContent() string {
 		}
 		sb.WriteString("   [dim]Reason: ")
 		sb.WriteString(file.Reason)
+		if file.Code != "" {
+			sb.WriteString(" [" + string(file.Code) + "[]")
+		}
 		sb.WriteString("[white]\n\n")
 	}
 	return sb.String()
@@ -819,9 +1038,9 @@ func (a *App) getWarningsContent() string {
 	var sb strings.Builder
 	sb.Grow(64 + len(a.data.Warnings)*100)
 
-	sb.WriteString(fmt.Sprintf("[yellow]Warnings (%d):[white]\n\n", len(a.data.Warnings)))
+	sb.WriteString(fmt.Sprintf("[yellow]%s (%d):[white]\n\n", i18n.T("Warnings"), len(a.data.Warnings)))
 	for i, warning := range a.data.Warnings {
-		sb.WriteString(fmt.Sprintf("[yellow]%d.[white] [%s] %s\n", i+1, warning.Timestamp, warning.Message))
+		sb.WriteString(fmt.Sprintf("[yellow]%d.[white] [%s] %s\n", i+1, i18n.FormatTimestamp(warning.Timestamp), warning.Message))
 	}
 	return sb.String()
 }
@@ -834,9 +1053,9 @@ func (a *App) getErrorsContent() string {
 	var sb strings.Builder
 	sb.Grow(64 + len(a.data.Errors)*100)
 
-	sb.WriteString(fmt.Sprintf("[red]Errors (%d):[white]\n\n", len(a.data.Errors)))
+	sb.WriteString(fmt.Sprintf("[red]%s (%d):[white]\n\n", i18n.T("Errors"), len(a.data.Errors)))
 	for i, err := range a.data.Errors {
-		sb.WriteString(fmt.Sprintf("[red]%d.[white] [%s] %s\n", i+1, err.Timestamp, err.Message))
+		sb.WriteString(fmt.Sprintf("[red]%d.[white] [%s] %s\n", i+1, i18n.FormatTimestamp(err.Timestamp), err.Message))
 	}
 	return sb.String()
 }
@@ -846,7 +1065,7 @@ func (a *App) getErrorsContent() string {
 func (a *App) ShowProgressBar() {
 	if !a.isScanning {
 		a.isScanning = true
-		a.progressBar.SetText("Initializing scan...")
+		a.progressBar.SetText(i18n.T("Initializing scan..."))
 		// Progress bar is always part of layout, just show it
 		a.app.QueueUpdateDraw(func() {})
 	}
@@ -862,7 +1081,7 @@ func (a *App) HideProgressBar() {
 
 func (a *App) UpdateProgress(current, total int, message string) {
 	if total == 0 {
-		a.progressBar.SetText("Initializing scan...")
+		a.progressBar.SetText(i18n.T("Initializing scan..."))
 		a.app.QueueUpdateDraw(func() {})
 		return
 	}
@@ -903,35 +1122,38 @@ func (a *App) UpdateProgress(current, total int, message string) {
 }
 
 func (a *App) UpdateData(newData *ScanResult) {
+	a.isScanning = false
+	a.setData(newData)
+	a.app.QueueUpdateDraw(func() {})
+}
+
+// setData shows newData in place of the shown results, without drawing
+func (a *App) setData(newData *ScanResult) {
+	selected := a.currentSubject
 	a.data = newData
-	a.data.BuildCache() // Build lookup maps once
+	a.index = newReportIndex(newData) // Build lookup maps once
 
 	a.populateSubjectsList()
 	a.populateChecksList()
 	a.populateLeftSections() // Update navigation counts
 	a.updateInfo()
 
-	// Auto-select first subject if available
-	a.autoSelectFirstSubject()
+	// Keep the selected subject of a rescan if it still has issues, otherwise select the first one
+	if index := indexOf(a.subjectNames, selected); a.selectedLeftPanel == 0 && index >= 0 {
+		a.subjectsList.SetCurrentItem(index)
+		a.showSubjectDetails()
+	} else {
+		a.autoSelectFirstSubject()
+	}
 
 	// Focus the navigation panel so user can immediately start navigating
 	a.focusSubjects()
-
-	a.app.QueueUpdateDraw(func() {})
 }
 
 func (a *App) autoSelectFirstSubject() {
-	// Auto-select first subject if any subjects are available
-	if len(a.data.Scanned) > 0 {
-		// Select first scanned file
-		firstFile := a.data.Scanned[0]
-		a.currentSubject = firstFile.Filename
-		a.subjectsList.SetCurrentItem(0)
-		// Explicitly update details for the selected subject
-		a.showSubjectDetails()
-	} else if a.data.cachedHasRepository {
-		// Use cached flag instead of loop
-		a.currentSubject = "repository"
+	// Auto-select first subject if any subjects are available (the repository comes last)
+	if len(a.subjectNames) > 0 {
+		a.currentSubject = a.subjectNames[0]
 		a.subjectsList.SetCurrentItem(0)
 		// Explicitly update details for the selected subject
 		a.showSubjectDetails()
@@ -942,6 +1164,42 @@ func (a *App) SetStartupCallback(callback func()) {
 	a.startupCallback = callback
 }
 
+// SetRescanFunc enables "R", which calls rescan in its own goroutine to scan the location again.
+// rescan reports like the first scan, through UpdateProgress and UpdateData or ScanFailed.
+func (a *App) SetRescanFunc(rescan func()) {
+	a.rescanFunc = rescan
+}
+
+// ScanFailed shows why the scan failed and ends scanning
+func (a *App) ScanFailed(err error) {
+	a.UpdateProgress(0, 1, fmt.Sprintf("Scan failed: %v", err))
+	a.isScanning = false
+}
+
+// rescan scans the location again; the results stay until the new ones arrive
+func (a *App) rescan() {
+	if a.rescanFunc == nil {
+		a.showStatus("[yellow]Rescanning is only available when scanning a location[white]")
+		return
+	}
+	if a.isScanning {
+		return
+	}
+	a.isScanning = true
+	a.progressBar.SetText(i18n.T("Preparing to scan..."))
+	go a.rescanFunc()
+}
+
+// indexOf returns the index of name in names, -1 if it is not there
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (a *App) Run() error {
 	// Start the startup callback after a brief delay to ensure TUI is ready
 	if a.startupCallback != nil {
@@ -959,6 +1217,16 @@ func (a *App) SetLocation(location string) {
 	a.location = location
 }
 
+// SetTruncation sets how many issues of groups of similar issues the summaries list
+func (a *App) SetTruncation(truncation Truncation) {
+	a.truncation = truncation
+}
+
+// SetLetter writes the copy-paste summary with the letter template
+func (a *App) SetLetter(letter *Letter) {
+	a.letter = letter
+}
+
 // setupSummaryModal creates the modal overlay for the copy-paste summary
 func (a *App) setupSummaryModal() {
 	// Create the text view for summary content
@@ -966,19 +1234,19 @@ func (a *App) setupSummaryModal() {
 		SetDynamicColors(true).
 		SetScrollable(true).
 		SetWrap(true)
-	a.summaryTextView.SetBorder(true).SetTitle(" Summary (copied to clipboard) ")
+	a.summaryTextView.SetBorder(true).SetTitle(" " + i18n.T("Summary (copied to clipboard)") + " ")
 
 	// Create instructions text
 	instructions := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText("[yellow]Press ESC or X to close[white]  |  [yellow]↑↓[white] to scroll")
+		SetText("[yellow]" + i18n.T("Press ESC or X to close") + "[white]  |  [yellow]↑↓[white] " + i18n.T("to scroll"))
 	instructions.SetTextAlign(tview.AlignCenter)
 
 	// Create the modal container
 	innerFlex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(a.summaryTextView, 0, 1, true).
 		AddItem(instructions, 1, 0, false)
-	innerFlex.SetBorder(true).SetTitle(" Copy-Paste Summary ")
+	innerFlex.SetBorder(true).SetTitle(" " + i18n.T("Copy-Paste Summary") + " ")
 	innerFlex.SetBorderColor(tcell.ColorYellow)
 
 	// Create centered modal with padding
@@ -1000,21 +1268,21 @@ func (a *App) showSummaryModal() {
 
 	// Generate the summary
 	generator := NewSummaryGenerator(a.data, a.location)
+	generator.SetBaseline(a.triage.baseline)
+	generator.SetLetter(a.letter)
+	generator.SetTruncation(a.truncation)
 	summary := generator.Generate()
 
 	// Try to copy to clipboard
 	clipboardStatus := ""
-	if err := clipboard.WriteAll(summary); err != nil {
-		// Fallback to OSC 52 for remote/tmux environments
-		if osc52Err := copyToClipboardOSC52(summary); osc52Err != nil {
-			clipboardStatus = "\n\n[red]Note: Could not copy to clipboard: " + err.Error() + "[white]"
-			a.summaryTextView.SetTitle(" Summary (clipboard unavailable) ")
-		} else {
-			clipboardStatus = "\n\n[yellow]Note: Used OSC 52 for clipboard (works if terminal supports it)[white]"
-			a.summaryTextView.SetTitle(" Summary (OSC 52 clipboard) ")
-		}
+	if osc52, err := copyToClipboard(summary); err != nil {
+		clipboardStatus = "\n\n[red]Note: Could not copy to clipboard: " + err.Error() + "[white]"
+		a.summaryTextView.SetTitle(" " + i18n.T("Summary (clipboard unavailable)") + " ")
+	} else if osc52 {
+		clipboardStatus = "\n\n[yellow]Note: Used OSC 52 for clipboard (works if terminal supports it)[white]"
+		a.summaryTextView.SetTitle(" " + i18n.T("Summary (OSC 52 clipboard)") + " ")
 	} else {
-		a.summaryTextView.SetTitle(" Summary (copied to clipboard) ")
+		a.summaryTextView.SetTitle(" " + i18n.T("Summary (copied to clipboard)") + " ")
 	}
 
 	// Set the summary text
@@ -1035,8 +1303,27 @@ func (a *App) hideSummaryModal() {
 
 	a.summaryVisible = false
 	a.app.SetRoot(a.flex, true)
+	a.restoreFocus()
+}
+
+// showPrompt shows input in place of the controls and focuses it
+func (a *App) showPrompt(input *tview.InputField) {
+	a.prompting = true
+	a.header.Clear()
+	a.header.AddItem(input, 0, 1, true)
+	a.app.SetFocus(input)
+}
+
+// closePrompt shows the controls again
+func (a *App) closePrompt() {
+	a.prompting = false
+	a.header.Clear()
+	a.header.AddItem(a.controls, 0, 1, false)
+	a.restoreFocus()
+}
 
-	// Restore focus to previous view
+// restoreFocus focuses the panel of the current view again
+func (a *App) restoreFocus() {
 	switch a.currentView {
 	case "subjects":
 		a.app.SetFocus(a.subjectsList)
@@ -1,9 +1,13 @@
 package tui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
 	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/gdamore/tcell/v2"
 )
 
 func TestNewApp(t *testing.T) {
@@ -178,4 +182,47 @@ func TestValidateTestData(t *testing.T) {
 	if len(data.DetailsSubjectFocused) == 0 {
 		t.Error("Should have subject details for test")
 	}
-}
\ No newline at end of file
+}
+
+func TestAppRescan(t *testing.T) {
+	app := NewScanningApp()
+	// Results and progress are drawn by the event loop, which runs on a simulated screen
+	app.app.SetScreen(tcell.NewSimulationScreen(""))
+	go app.app.Run()
+	defer app.app.Stop()
+
+	app.UpdateData(searchTestData())
+	if app.isScanning {
+		t.Fatal("Expected scanning to end when the results arrive")
+	}
+
+	app.rescan()
+	if !strings.Contains(app.progressBar.GetText(true), "only available when scanning") {
+		t.Errorf("Expected rescanning to need a scan, got %q", app.progressBar.GetText(true))
+	}
+
+	rescans := make(chan struct{}, 2)
+	app.SetRescanFunc(func() { rescans <- struct{}{} })
+	app.currentSubject = "my file.txt"
+	app.rescan()
+	app.rescan() // Ignored while the rescan is running
+	<-rescans
+	if !app.isScanning {
+		t.Error("Expected a rescan to start scanning")
+	}
+
+	app.UpdateData(searchTestData())
+	if app.isScanning || len(rescans) != 0 {
+		t.Errorf("Expected one rescan that ended with the new results, scanning = %v", app.isScanning)
+	}
+	if app.currentSubject != "my file.txt" || app.subjectsList.GetCurrentItem() != 1 {
+		t.Errorf("Expected the selected subject to be kept, got %q", app.currentSubject)
+	}
+
+	app.rescan()
+	<-rescans
+	app.ScanFailed(errors.New("location is gone"))
+	if app.isScanning || !strings.Contains(app.progressBar.GetText(true), "Scan failed: location is gone") {
+		t.Errorf("Expected the failure to end scanning, got %q", app.progressBar.GetText(true))
+	}
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+	htmlformatter "github.com/eawag-rdm/pc/pkg/output/html"
+)
+
+// defaultExportPath is the path suggested when exporting the report
+const defaultExportPath = "pc_report.html"
+
+// exportReport writes the full report to path, as JSON, HTML or Markdown depending on its extension.
+// The Markdown summary leaves out the issues triaged in b and truncates groups of similar issues.
+func exportReport(data *ScanResult, location, path string, b *baseline.Baseline, truncation Truncation) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	var content []byte
+	switch ext {
+	case ".json", ".html", ".htm":
+		jsonData, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		content = append(jsonData, '\n')
+		if ext != ".json" {
+			// The exported report must not depend on a data directory next to it
+			if content, err = htmlformatter.NewHTMLFormatter().SelfContained(string(jsonData)); err != nil {
+				return err
+			}
+		}
+	case ".md", ".markdown":
+		generator := NewSummaryGenerator(data, location)
+		generator.SetBaseline(b)
+		generator.SetTruncation(truncation)
+		content = []byte(generator.GenerateMarkdown())
+	default:
+		return fmt.Errorf("unknown format '%s', use a path ending in .json, .html or .md", ext)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// setupExport creates the input shown in place of the controls by "E"
+func (a *App) setupExport() {
+	a.exportInput = tview.NewInputField().
+		SetLabel("Export to: ").
+		SetText(defaultExportPath).
+		SetPlaceholder("Path ending in .json, .html or .md, Enter to export, Esc to cancel")
+	a.exportInput.SetBorder(true).SetTitle(" Export Report ")
+	a.exportInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			a.exportTo(strings.TrimSpace(a.exportInput.GetText()))
+		}
+		a.closePrompt()
+	})
+}
+
+// openExport asks for the path to export the report to
+func (a *App) openExport() {
+	if a.isScanning {
+		a.showStatus("[yellow]The report can be exported when the scan has finished[white]")
+		return
+	}
+	a.showPrompt(a.exportInput)
+}
+
+// exportTo exports the report to path and shows the outcome
+func (a *App) exportTo(path string) {
+	if path == "" {
+		return
+	}
+	if err := exportReport(a.data, a.location, path, a.triage.baseline, a.truncation); err != nil {
+		a.showStatus("[red]Export failed: " + tview.Escape(err.Error()) + "[white]")
+		return
+	}
+	a.showStatus("[green]Report exported to " + tview.Escape(path) + "[white]")
+}
+
+// copyCurrentView copies the text of the details panel, e.g. the issues of the selected subject
+// or check, to the clipboard
+func (a *App) copyCurrentView() {
+	text := strings.TrimSpace(a.detailsContent.GetText(true))
+	if text == "" {
+		a.showStatus("[yellow]Nothing to copy[white]")
+		return
+	}
+	if osc52, err := copyToClipboard(text); err != nil {
+		a.showStatus("[red]Could not copy to clipboard: " + tview.Escape(err.Error()) + "[white]")
+	} else if osc52 {
+		a.showStatus("[yellow]Copied with OSC 52 (works if terminal supports it)[white]")
+	} else {
+		a.showStatus("[green]Copied to clipboard[white]")
+	}
+}
+
+// showStatus shows the outcome of an export or copy in the progress bar, which is unused after
+// the scan
+func (a *App) showStatus(text string) {
+	a.progressBar.SetText(text)
+}
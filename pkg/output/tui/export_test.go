@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportReport(t *testing.T) {
+	dir := t.TempDir()
+	data := searchTestData()
+
+	jsonPath := filepath.Join(dir, "report.json")
+	if err := exportReport(data, "/data/package", jsonPath, nil, DefaultTruncation); err != nil {
+		t.Fatalf("Failed to export JSON: %v", err)
+	}
+	content, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScanResult
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("Exported JSON is invalid: %v", err)
+	}
+	if len(decoded.DetailsSubjectFocused) != 2 {
+		t.Errorf("Expected 2 subjects in the exported JSON, got %d", len(decoded.DetailsSubjectFocused))
+	}
+
+	htmlPath := filepath.Join(dir, "nested", "report.HTML")
+	if err := exportReport(data, "/data/package", htmlPath, nil, DefaultTruncation); err != nil {
+		t.Fatalf("Failed to export HTML: %v", err)
+	}
+	content, err = os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<html") || !strings.Contains(string(content), "config.txt") {
+		t.Error("Expected a self-contained HTML report with the scan data")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "nested", "report_files")); !os.IsNotExist(err) {
+		t.Error("Expected no data directory next to the exported HTML report")
+	}
+
+	mdPath := filepath.Join(dir, "report.md")
+	if err := exportReport(data, "/data/package", mdPath, nil, DefaultTruncation); err != nil {
+		t.Fatalf("Failed to export Markdown: %v", err)
+	}
+	content, err = os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "/data/package") || !strings.Contains(string(content), "config.txt") {
+		t.Errorf("Expected the Markdown summary, got:\n%s", content)
+	}
+}
+
+func TestExportReportUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	err := exportReport(searchTestData(), "", path, nil, DefaultTruncation)
+	if err == nil || !strings.Contains(err.Error(), "unknown format '.pdf'") {
+		t.Errorf("Expected an unknown format error, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no file to be written")
+	}
+}
+
+func TestAppExportTo(t *testing.T) {
+	app := NewApp(searchTestData())
+
+	app.exportTo(filepath.Join(t.TempDir(), "report.txt"))
+	if status := app.progressBar.GetText(true); !strings.Contains(status, "Export failed") {
+		t.Errorf("Expected the failure in the status, got %q", status)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	app.exportTo(path)
+	if status := app.progressBar.GetText(true); !strings.Contains(status, "Report exported to "+path) {
+		t.Errorf("Expected the export in the status, got %q", status)
+	}
+}
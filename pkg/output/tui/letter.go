@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Letter is a Go text/template the copy-paste summary for depositors is written with, so an
+// institution can word and sign it without recompiling pc
+type Letter struct {
+	tmpl *texttemplate.Template
+	now  func() time.Time
+}
+
+// LetterData are the variables of a letter template
+type LetterData struct {
+	Location       string // Folder or CKAN package scanned
+	Timestamp      string // Time of the scan
+	Issues         int    // Open issues, triaged ones are not counted
+	Files          int    // Files with open issues
+	Accepted       int    // Issues triaged as accepted
+	FalsePositives int    // Issues triaged as false positives
+	Summary        string // Header and issues grouped by check, as in the built-in summary
+}
+
+// Date is a day of a letter, printed as 2006-01-02 and formatted otherwise with .Format
+type Date struct {
+	time.Time
+}
+
+func (d Date) String() string {
+	return d.Format("2006-01-02")
+}
+
+// LoadLetter parses the letter template at path. Besides the variables of LetterData it can use
+//
+//	today               the date of today
+//	deadline 14         the date 14 days from today
+//	plural n "issue"    "1 issue", "2 issues"
+//
+// as well as lower, upper and trim from the strings package.
+func LoadLetter(path string) (*Letter, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read letter template: %w", err)
+	}
+	letter := &Letter{now: time.Now}
+	letter.tmpl, err = texttemplate.New(filepath.Base(path)).Funcs(letter.funcs()).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse letter template: %w", err)
+	}
+	// Mistakes such as unknown variables show before a letter is written
+	if _, err := letter.render(LetterData{}); err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+func (l *Letter) funcs() texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"today": func() Date {
+			return Date{l.now()}
+		},
+		"deadline": func(days int) Date {
+			return Date{l.now().AddDate(0, 0, days)}
+		},
+		"plural": plural,
+		"lower":  strings.ToLower,
+		"upper":  strings.ToUpper,
+		"trim":   strings.TrimSpace,
+	}
+}
+
+// render writes the letter for data
+func (l *Letter) render(data LetterData) (string, error) {
+	var sb strings.Builder
+	if err := l.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute letter template: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeLetter writes a letter template to a temporary file and returns its path
+func writeLetter(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "letter.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write letter template: %v", err)
+	}
+	return path
+}
+
+func TestLetter(t *testing.T) {
+	letter, err := LoadLetter(writeLetter(t, `Dear depositor,
+
+we checked {{.Location}} and found {{plural .Issues "issue"}} in {{plural .Files "file"}}. Please fix them by {{(deadline 14).Format "02.01.2006"}} ({{deadline 14}}).
+
+{{.Summary}}
+Lake Data Team, {{today}}
+`))
+	if err != nil {
+		t.Fatalf("LoadLetter failed: %v", err)
+	}
+	letter.now = func() time.Time { return time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC) }
+
+	data := &ScanResult{
+		DetailsCheckFocused: []CheckDetails{{
+			Checkname: "HasOnlyASCII",
+			Issues: []SubjectIssue{
+				{Subject: "Zürich.csv", Path: "/data/Zürich.csv", Message: "File name contains non-ASCII character: ü"},
+				{Subject: "Genève.csv", Path: "/data/Genève.csv", Message: "File name contains non-ASCII character: è"},
+			},
+		}},
+	}
+	sg := NewSummaryGenerator(data, "lake-ice")
+	sg.SetLetter(letter)
+	result := sg.Generate()
+
+	if !strings.HasPrefix(result, "Dear depositor,\n\nwe checked lake-ice and found 2 issues in 2 files. Please fix them by 15.03.2024 (2024-03-15).\n\n=== Package Checker Scan Summary ===\n") {
+		t.Errorf("Unexpected letter:\n%s", result)
+	}
+	if !strings.Contains(result, "  - Zürich.csv: File name contains non-ASCII character: ü\n") {
+		t.Errorf("Expected the issues in the letter, got:\n%s", result)
+	}
+	if !strings.HasSuffix(result, "Total: 2 issues in 2 files\n\nLake Data Team, 2024-03-01\n") {
+		t.Errorf("Unexpected end of the letter:\n%s", result)
+	}
+	if strings.Contains(result, "We have analyzed your data package") {
+		t.Errorf("Expected the letter to replace the built-in text, got:\n%s", result)
+	}
+}
+
+func TestLoadLetterErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Syntax error", "{{.Location", "failed to parse letter template"},
+		{"Unknown function", "{{signature}}", "failed to parse letter template"},
+		{"Unknown variable", "{{.Depositor}}", "failed to execute letter template"},
+	}
+	for _, test := range tests {
+		if _, err := LoadLetter(writeLetter(t, test.content)); err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+	if _, err := LoadLetter(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil || !strings.Contains(err.Error(), "failed to read letter template") {
+		t.Errorf("Expected a missing template to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
+// markdownStyle renders the issues of a check as rows of a Markdown table
+var markdownStyle = summaryStyle{
+	item: formatMarkdownRow,
+	truncated: func(remaining int, displayName string) string {
+		return fmt.Sprintf("| … | | _%s_ |\n", i18n.Tf("and %d more in %s", remaining, escapeMarkdownCell(displayName)))
+	},
+}
+
+// GenerateMarkdown creates a GitHub/GitLab-flavored Markdown summary for issue trackers and CI bots.
+// Every check is a collapsible section with a table of the affected files, grouped and truncated
+// as in Generate.
+func (sg *SummaryGenerator) GenerateMarkdown() string {
+	if sg.data == nil {
+		return i18n.T("No scan data available.") + "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## " + i18n.T("Package Checker Scan Summary") + "\n\n")
+	if sg.location != "" {
+		sb.WriteString(fmt.Sprintf("**%s** `%s`  \n", i18n.T("Location:"), strings.ReplaceAll(sg.location, "`", "'")))
+	}
+	if sg.data.Timestamp != "" {
+		sb.WriteString(fmt.Sprintf("**%s** %s  \n", i18n.T("Timestamp:"), i18n.FormatTimestamp(sg.data.Timestamp)))
+	}
+
+	checks := make([]CheckDetails, 0, len(sg.data.DetailsCheckFocused))
+	totalIssues := 0
+	filesWithIssues := make(map[string]struct{})
+	triaged := sg.suppressed()
+	for _, check := range sg.data.DetailsCheckFocused {
+		check.Issues = sg.openIssues(check.Checkname, check.Issues, &triaged)
+		if len(check.Issues) == 0 {
+			continue
+		}
+		checks = append(checks, check)
+		totalIssues += len(check.Issues)
+		for _, issue := range check.Issues {
+			filesWithIssues[issue.Subject] = struct{}{}
+		}
+	}
+	if len(checks) == 0 {
+		sb.WriteString("\n" + i18n.T("No issues found.") + " :white_check_mark:\n")
+		if line := formatTriaged(triaged); line != "" {
+			sb.WriteString("\n_" + strings.TrimSuffix(line, "\n") + "_\n")
+		}
+		return sb.String()
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Checkname < checks[j].Checkname })
+
+	sb.WriteString(fmt.Sprintf("**%s** %s\n\n", i18n.T("Total:"), i18n.Tf("%s in %s", plural(totalIssues, "issue"), plural(len(filesWithIssues), "file"))))
+	if line := formatTriaged(triaged); line != "" {
+		sb.WriteString("_" + strings.TrimSuffix(line, "\n") + "_\n\n")
+	}
+
+	for _, check := range checks {
+		sb.WriteString("<details>\n")
+		sb.WriteString(fmt.Sprintf("<summary><b>%s</b> (%s)</summary>\n\n", escapeMarkdownHTML(humanizeCheckName(check.Checkname)), plural(len(check.Issues), "issue")))
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", i18n.T("File"), i18n.T("Line"), i18n.T("Issue")))
+		sb.WriteString("|------|------|-------|\n")
+		sb.WriteString(formatIssuesWithTruncation(check.Issues, markdownStyle, sg.truncation))
+		sb.WriteString("\n</details>\n\n")
+	}
+	return sb.String()
+}
+
+// formatMarkdownRow formats a single issue as a table row
+func formatMarkdownRow(item IssueItem) string {
+	subject := item.Subject
+	switch {
+	case item.ArchivePath != "":
+		subject = item.Subject + " -> " + item.ArchivePath
+	case subject == "" || strings.EqualFold(subject, "repository"):
+		subject = i18n.T("Repository")
+	}
+
+	line := ""
+	if item.Line > 0 {
+		line = fmt.Sprintf("%d", item.Line)
+	}
+	message := escapeMarkdownCell(item.Message)
+	if item.Count > 1 {
+		message += fmt.Sprintf(" (%d×)", item.Count)
+	}
+	return fmt.Sprintf("| `%s` | %s | %s |\n", strings.ReplaceAll(subject, "`", "'"), line, message)
+}
+
+// escapeMarkdownCell keeps text from breaking the table or being rendered as Markdown or HTML
+func escapeMarkdownCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = escapeMarkdownHTML(text)
+	return strings.NewReplacer("|", "\\|", "`", "\\`", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]").Replace(text)
+}
+
+func escapeMarkdownHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func plural(count int, noun string) string {
+	return i18n.Plural(count, "%d "+noun, "%d "+noun+"s")
+}
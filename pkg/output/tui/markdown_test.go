@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSummaryGenerator_GenerateMarkdown(t *testing.T) {
+	var issues []SubjectIssue
+	for i := 0; i < 8; i++ {
+		issues = append(issues, SubjectIssue{Subject: fmt.Sprintf("logs/run%d.log", i), Message: "Found 'PASSWORD'", Line: i + 1})
+	}
+	data := &ScanResult{
+		Timestamp: "2024-01-14T10:30:00Z",
+		DetailsCheckFocused: []CheckDetails{
+			{Checkname: "IsFreeOfKeywords", Issues: issues},
+			{Checkname: "HasNoWhiteSpace", Issues: []SubjectIssue{
+				{Subject: "a|b c.txt", ArchiveName: "data.zip", Message: "File name contains spaces <b>", Count: 2},
+			}},
+		},
+	}
+
+	result := NewSummaryGenerator(data, "my-package").GenerateMarkdown()
+	for _, expected := range []string{
+		"## Package Checker Scan Summary",
+		"**Location:** `my-package`",
+		"**Total:** 9 issues in 9 files",
+		"<summary><b>Possible sensitive content detected</b> (8 issues)</summary>",
+		"| `logs/run0.log` | 1 | Found 'PASSWORD' |",
+		`| … | | _and 3 more in "logs" with "Found"_ |`,
+		"| `data.zip -> a|b c.txt` |  | File name contains spaces &lt;b&gt; (2×) |",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in Markdown summary, got:\n%s", expected, result)
+		}
+	}
+	if strings.Index(result, "HasNoWhiteSpace") > strings.Index(result, "Possible sensitive content") {
+		t.Error("Expected checks sorted by name")
+	}
+	if strings.Count(result, "<details>") != 2 || strings.Count(result, "</details>") != 2 {
+		t.Error("Expected one collapsible section per check")
+	}
+}
+
+func TestSummaryGenerator_GenerateMarkdown_NoIssues(t *testing.T) {
+	result := NewSummaryGenerator(&ScanResult{}, "").GenerateMarkdown()
+	if !strings.Contains(result, "No issues found.") || strings.Contains(result, "Location") {
+		t.Errorf("Unexpected Markdown summary:\n%s", result)
+	}
+	if NewSummaryGenerator(nil, "").GenerateMarkdown() != "No scan data available.\n" {
+		t.Error("Expected a note without scan data")
+	}
+}
+
+func TestEscapeMarkdownCell(t *testing.T) {
+	if got := escapeMarkdownCell("a | b\n*c* [x]"); got != `a \| b \*c\* \[x\]` {
+		t.Errorf("Unexpected escaping %q", got)
+	}
+}
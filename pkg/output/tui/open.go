@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// openCommand returns the command opening path in $EDITOR, or in $PAGER if no editor is set
+func openCommand(path string) (*exec.Cmd, error) {
+	for _, variable := range []string{"EDITOR", "PAGER"} {
+		// The variables may hold arguments, e.g. "code --wait"
+		if args := strings.Fields(os.Getenv(variable)); len(args) > 0 {
+			return exec.Command(args[0], append(args[1:], path)...), nil
+		}
+	}
+	return nil, errors.New("set $EDITOR or $PAGER to open files")
+}
+
+// selectedFile returns the path of the selected subject if it is a file on disk
+func (a *App) selectedFile() (string, error) {
+	subject, ok := a.index.subjects[a.currentSubject]
+	if a.selectedLeftPanel != 0 || !ok {
+		return "", errors.New("select a subject to open its file")
+	}
+	if subject.ArchiveName != "" {
+		return "", fmt.Errorf("%s is inside the archive %s and cannot be opened", subject.Subject, subject.ArchiveName)
+	}
+	if subject.Path == "" {
+		return "", fmt.Errorf("%s is not a file", subject.Subject)
+	}
+	if _, err := os.Stat(subject.Path); err != nil {
+		return "", fmt.Errorf("cannot open %s: %w", subject.Path, err)
+	}
+	return subject.Path, nil
+}
+
+// openSelectedFile suspends the TUI while the selected subject is open in the editor or pager
+func (a *App) openSelectedFile() {
+	path, err := a.selectedFile()
+	if err != nil {
+		a.showStatus("[yellow]" + tview.Escape(err.Error()) + "[white]")
+		return
+	}
+	cmd, err := openCommand(path)
+	if err != nil {
+		a.showStatus("[yellow]" + tview.Escape(err.Error()) + "[white]")
+		return
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if !a.app.Suspend(func() { err = cmd.Run() }) {
+		a.showStatus("[red]Could not suspend the TUI to open " + tview.Escape(path) + "[white]")
+		return
+	}
+	if err != nil {
+		a.showStatus("[red]" + tview.Escape(cmd.Args[0]) + " failed: " + tview.Escape(err.Error()) + "[white]")
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenCommand(t *testing.T) {
+	t.Setenv("EDITOR", "")
+	t.Setenv("PAGER", "")
+	if _, err := openCommand("data.csv"); err == nil {
+		t.Error("Expected an error without $EDITOR and $PAGER")
+	}
+
+	t.Setenv("PAGER", "less")
+	if cmd, _ := openCommand("data.csv"); !reflect.DeepEqual(cmd.Args, []string{"less", "data.csv"}) {
+		t.Errorf("Expected the pager, got %v", cmd.Args)
+	}
+
+	t.Setenv("EDITOR", "code --wait")
+	if cmd, _ := openCommand("data.csv"); !reflect.DeepEqual(cmd.Args, []string{"code", "--wait", "data.csv"}) {
+		t.Errorf("Expected the editor with its arguments, got %v", cmd.Args)
+	}
+}
+
+func TestAppSelectedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.txt")
+	if err := os.WriteFile(path, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data := searchTestData()
+	data.DetailsSubjectFocused[0].Path = path
+	data.DetailsSubjectFocused[1].Path = filepath.Join(t.TempDir(), "missing.txt")
+	data.DetailsSubjectFocused = append(data.DetailsSubjectFocused,
+		SubjectDetails{Subject: "inner.txt", Path: "inner.txt", ArchiveName: "data.zip", Issues: []CheckIssue{{Checkname: "HasNoWhiteSpace"}}})
+	app := NewApp(data)
+
+	app.currentSubject = "config.txt"
+	if selected, err := app.selectedFile(); err != nil || selected != path {
+		t.Errorf("Expected %s, got %q (%v)", path, selected, err)
+	}
+
+	tests := []struct {
+		subject string
+		err     string
+	}{
+		{"my file.txt", "cannot open"},
+		{"data.zip > inner.txt", "inside the archive data.zip"},
+	}
+	for _, test := range tests {
+		app.currentSubject = test.subject
+		if _, err := app.selectedFile(); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected an error containing %q, got %v", test.subject, test.err, err)
+		}
+	}
+
+	app.selectedLeftPanel = 1
+	app.currentSubject = "IsFreeOfKeywords"
+	if _, err := app.selectedFile(); err == nil {
+		t.Error("Expected checks not to be opened")
+	}
+}
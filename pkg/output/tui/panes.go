@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Width of the left pane in tenths of the window, changed with "[" and "]"
+const (
+	defaultLeftPaneWidth = 5
+	minLeftPaneWidth     = 2
+	maxLeftPaneWidth     = 8
+)
+
+// sectionRegionPrefix prefixes the regions of the section headers, which are clicked to select them
+const sectionRegionPrefix = "section-"
+
+// setupMouse enables the mouse: lists select and scroll, the details scroll and clicking a
+// section header selects it
+func (a *App) setupMouse() {
+	a.app.EnableMouse(true)
+
+	a.leftSections.SetRegions(true)
+	a.leftSections.SetHighlightedFunc(func(added, removed, remaining []string) {
+		if len(added) == 0 {
+			return
+		}
+		// The header is only highlighted while the click is handled
+		a.leftSections.Highlight()
+		if index, err := strconv.Atoi(strings.TrimPrefix(added[0], sectionRegionPrefix)); err == nil {
+			a.selectLeftPanel(index)
+		}
+	})
+
+	// Clicking a panel focuses it; keep the current view in step as the keys do
+	a.subjectsList.SetFocusFunc(func() {
+		if a.currentView != "subjects" && a.selectedLeftPanel == 0 && !a.prompting {
+			a.focusSubjects()
+		}
+	})
+	a.checksList.SetFocusFunc(func() {
+		if a.currentView != "checks" && a.selectedLeftPanel == 1 && !a.prompting {
+			a.focusChecks()
+		}
+	})
+	a.detailsContent.SetFocusFunc(func() {
+		if (a.currentView == "subjects" || a.currentView == "checks") && a.currentSubject != "" && !a.prompting {
+			a.focusDetails()
+		}
+	})
+}
+
+// sectionRegion makes a section header clickable
+func sectionRegion(index int, text string) string {
+	return fmt.Sprintf(`["%s%d"]%s[""]`, sectionRegionPrefix, index, text)
+}
+
+// selectLeftPanel shows the section of the left panel with the given index
+func (a *App) selectLeftPanel(index int) {
+	if index < 0 || index > 5 || index == a.selectedLeftPanel && a.currentView != "details" {
+		return
+	}
+	a.selectedLeftPanel = index
+	a.populateLeftSections()
+	a.switchToSelectedLeftPanel()
+	a.updateControls()
+}
+
+// resizePanes grows (delta > 0) or shrinks the left pane by delta tenths of the window
+func (a *App) resizePanes(delta int) {
+	width := min(max(a.leftPaneWidth+delta, minLeftPaneWidth), maxLeftPaneWidth)
+	if width == a.leftPaneWidth {
+		return
+	}
+	a.leftPaneWidth = width
+	a.mainContent.ResizeItem(a.leftPanel, 0, width)
+	a.mainContent.ResizeItem(a.rightPanel, 0, 10-width)
+}
@@ -0,0 +1,41 @@
+package tui
+
+import "testing"
+
+func TestAppClickSection(t *testing.T) {
+	app := NewApp(searchTestData())
+
+	// A click on a header highlights its region
+	app.leftSections.Highlight(sectionRegionPrefix + "1")
+	if app.selectedLeftPanel != 1 || app.currentView != "checks" {
+		t.Errorf("Expected the checks to be selected, got panel %d in view %q", app.selectedLeftPanel, app.currentView)
+	}
+	if highlights := app.leftSections.GetHighlights(); len(highlights) != 0 {
+		t.Errorf("Expected the header highlight to be cleared, got %v", highlights)
+	}
+
+	app.leftSections.Highlight(sectionRegionPrefix + "4")
+	if app.selectedLeftPanel != 4 || app.currentView != "warnings" {
+		t.Errorf("Expected the warnings to be selected, got panel %d in view %q", app.selectedLeftPanel, app.currentView)
+	}
+}
+
+func TestAppResizePanes(t *testing.T) {
+	app := NewApp(searchTestData())
+	if app.leftPaneWidth != defaultLeftPaneWidth {
+		t.Fatalf("Expected the default width %d, got %d", defaultLeftPaneWidth, app.leftPaneWidth)
+	}
+
+	for i := 0; i < 10; i++ {
+		app.resizePanes(1)
+	}
+	if app.leftPaneWidth != maxLeftPaneWidth {
+		t.Errorf("Expected the left pane to grow to %d, got %d", maxLeftPaneWidth, app.leftPaneWidth)
+	}
+	for i := 0; i < 10; i++ {
+		app.resizePanes(-1)
+	}
+	if app.leftPaneWidth != minLeftPaneWidth {
+		t.Errorf("Expected the left pane to shrink to %d, got %d", minLeftPaneWidth, app.leftPaneWidth)
+	}
+}
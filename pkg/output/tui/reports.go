@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/eawag-rdm/pc/pkg/history"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
+// Report is a saved JSON report the viewer can switch to
+type Report struct {
+	Name string      // File the report was read from
+	Raw  []byte      // JSON of the report, compared with the shown report in the picker
+	Data *ScanResult // Decoded report
+}
+
+// reportsState holds the reports of the viewer and the picker switching between them
+type reportsState struct {
+	list    []Report
+	current int // Index of the shown report
+	modal   *tview.Flex
+	picker  *tview.List
+	visible bool
+}
+
+// SetReports shows the first of several reports; "P" switches between them
+func (a *App) SetReports(reports []Report) {
+	a.reports.list = reports
+	a.reports.current = 0
+	if len(reports) > 0 {
+		a.showReport(0)
+	}
+}
+
+// setupReportPicker creates the modal listing the reports
+func (a *App) setupReportPicker() {
+	a.reports.picker = tview.NewList().ShowSecondaryText(true)
+	a.reports.picker.SetBorder(true).SetTitle(" Reports ")
+	a.reports.picker.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		a.closeReportPicker()
+		a.showReport(index)
+	})
+
+	instructions := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[yellow]Enter[white]=Show report  |  [yellow]ESC or P[white] to close")
+	instructions.SetTextAlign(tview.AlignCenter)
+
+	innerFlex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.reports.picker, 0, 1, true).
+		AddItem(instructions, 1, 0, false)
+	innerFlex.SetBorder(true).SetTitle(" Switch Report ")
+	innerFlex.SetBorderColor(tcell.ColorYellow)
+
+	a.reports.modal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 2, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 4, 0, false).
+			AddItem(innerFlex, 0, 1, true).
+			AddItem(nil, 4, 0, false),
+			0, 1, true).
+		AddItem(nil, 2, 0, false)
+}
+
+// openReportPicker lists the reports, each compared with the shown one
+func (a *App) openReportPicker() {
+	if len(a.reports.list) < 2 {
+		a.showStatus("[yellow]Pass several reports or a directory of reports to pc view to switch between them[white]")
+		return
+	}
+
+	a.reports.picker.Clear()
+	for i, report := range a.reports.list {
+		main := tview.Escape(report.Name)
+		if i == a.reports.current {
+			main = "[green]" + main + " (shown)[white]"
+		}
+		a.reports.picker.AddItem(main, "   "+a.compareReport(i), 0, nil)
+	}
+	a.reports.picker.SetCurrentItem(a.reports.current)
+
+	a.reports.visible = true
+	a.app.SetRoot(a.reports.modal, true)
+	a.app.SetFocus(a.reports.picker)
+}
+
+// closeReportPicker returns to the main view
+func (a *App) closeReportPicker() {
+	if !a.reports.visible {
+		return
+	}
+	a.reports.visible = false
+	a.app.SetRoot(a.flex, true)
+	a.restoreFocus()
+}
+
+// compareReport describes a report by its timestamp and issues, and how its issues differ from
+// those of the shown report
+func (a *App) compareReport(index int) string {
+	report := a.reports.list[index]
+	issues := 0
+	for _, check := range report.Data.DetailsCheckFocused {
+		issues += len(check.Issues)
+	}
+	description := fmt.Sprintf("%s  |  %s", tview.Escape(i18n.FormatTimestamp(report.Data.Timestamp)), plural(issues, "issue"))
+	if index == a.reports.current {
+		return description
+	}
+
+	diff, err := history.CompareReports(a.reports.list[a.reports.current].Raw, report.Raw)
+	if err != nil {
+		return description
+	}
+	return fmt.Sprintf("%s  |  [red]+%d new[white], [green]%d fixed[white] compared to the shown report",
+		description, len(diff.New), len(diff.Fixed))
+}
+
+// showReport replaces the shown results with the report with the given index
+func (a *App) showReport(index int) {
+	if index < 0 || index >= len(a.reports.list) {
+		return
+	}
+	report := a.reports.list[index]
+	a.reports.current = index
+	a.location = report.Name
+	a.setData(report.Data)
+	if len(a.reports.list) > 1 {
+		a.showStatus(fmt.Sprintf("Showing report %d of %d: %s", index+1, len(a.reports.list), tview.Escape(report.Name)))
+	}
+}
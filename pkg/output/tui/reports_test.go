@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAppSwitchReports(t *testing.T) {
+	first := searchTestData()
+	second := searchTestData()
+	second.Timestamp = "2024-02-01"
+	second.Scanned = second.Scanned[1:]
+	second.DetailsSubjectFocused = second.DetailsSubjectFocused[1:]
+	second.DetailsCheckFocused = second.DetailsCheckFocused[1:]
+	second.DetailsCheckFocused = append(second.DetailsCheckFocused,
+		CheckDetails{Checkname: "IsValidName", Issues: []SubjectIssue{{Subject: "my file.txt", Message: "Invalid name"}}})
+
+	var reports []Report
+	for i, data := range []*ScanResult{first, second} {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, Report{Name: []string{"v1.json", "v2.json"}[i], Raw: raw, Data: data})
+	}
+
+	app := NewApp(first)
+	app.SetReports(reports)
+	if len(app.subjectNames) != 2 || app.location != "v1.json" {
+		t.Fatalf("Expected the first report, got %v from %q", app.subjectNames, app.location)
+	}
+	if !strings.Contains(app.controls.GetText(true), "P=Reports") {
+		t.Errorf("Expected the report picker in the controls, got %q", app.controls.GetText(true))
+	}
+
+	app.openReportPicker()
+	if !app.reports.visible || app.reports.picker.GetItemCount() != 2 {
+		t.Fatal("Expected the picker to list both reports")
+	}
+	if _, secondary := app.reports.picker.GetItemText(1); !strings.Contains(secondary, "+1 new[white], [green]2 fixed") {
+		t.Errorf("Expected the second report compared with the first, got %q", secondary)
+	}
+
+	app.closeReportPicker()
+	app.showReport(1)
+	if len(app.subjectNames) != 1 || app.subjectNames[0] != "my file.txt" || app.location != "v2.json" {
+		t.Errorf("Expected the second report, got %v from %q", app.subjectNames, app.location)
+	}
+	if !strings.Contains(app.info.GetText(true), "2024-02-01") {
+		t.Errorf("Expected the summary of the second report, got:\n%s", app.info.GetText(true))
+	}
+}
+
+func TestAppReportPickerNeedsReports(t *testing.T) {
+	app := NewApp(searchTestData())
+	app.openReportPicker()
+	if app.reports.visible || !strings.Contains(app.progressBar.GetText(true), "several reports") {
+		t.Errorf("Expected the picker to need several reports, got %q", app.progressBar.GetText(true))
+	}
+}
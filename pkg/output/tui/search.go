@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tviewTag matches the color and region tags in text formatted for tview, which are not searched
+var tviewTag = regexp.MustCompile(`\[[a-zA-Z0-9#:\-_,."]*\]`)
+
+// searchHit is one match of the search query: the entry of the subjects or checks list it is
+// shown for and its region in the details of that entry
+type searchHit struct {
+	entry  int
+	region string
+}
+
+// searchState is the full-text search over subjects, checks and the details of their issues
+type searchState struct {
+	input   *tview.InputField
+	query   string         // Active query, empty if not searching
+	pattern *regexp.Regexp // Case-insensitive pattern of the query
+	hits    []searchHit    // Matches in the current list
+	current int            // Index of the selected hit, -1 before n/N is pressed
+}
+
+// matches reports whether text contains the query outside of tags; everything matches without a query
+func (s *searchState) matches(text string) bool {
+	if s.pattern == nil {
+		return true
+	}
+	return s.count(text) > 0
+}
+
+// count returns the number of matches in text outside of tags
+func (s *searchState) count(text string) int {
+	if s.pattern == nil {
+		return 0
+	}
+	count := 0
+	for _, segment := range tviewTag.Split(text, -1) {
+		count += len(s.pattern.FindAllStringIndex(segment, -1))
+	}
+	return count
+}
+
+// highlight marks the matches in text; the n-th match is in region "n" so it can be selected
+// and scrolled to
+func (s *searchState) highlight(text string) string {
+	if s.pattern == nil {
+		return text
+	}
+	var sb strings.Builder
+	region := 0
+	last := 0
+	tags := append(tviewTag.FindAllStringIndex(text, -1), []int{len(text), len(text)})
+	for _, tag := range tags {
+		segment := text[last:tag[0]]
+		offset := 0
+		for _, match := range s.pattern.FindAllStringIndex(segment, -1) {
+			sb.WriteString(segment[offset:match[0]])
+			sb.WriteString(fmt.Sprintf(`["%d"][:blue]%s[:-][""]`, region, segment[match[0]:match[1]]))
+			offset = match[1]
+			region++
+		}
+		sb.WriteString(segment[offset:])
+		sb.WriteString(text[tag[0]:tag[1]])
+		last = tag[1]
+	}
+	return sb.String()
+}
+
+// status describes the active search for the controls bar
+func (s *searchState) status() string {
+	if s.query == "" {
+		return ""
+	}
+	position := "-"
+	if s.current >= 0 {
+		position = strconv.Itoa(s.current + 1)
+	}
+	return fmt.Sprintf("[yellow]n/N[white]=Next/Prev  [yellow]Esc[white]=Clear  [green]\"%s\" %s/%d[white]", tview.Escape(s.query), position, len(s.hits))
+}
+
+// setupSearch creates the input shown in place of the controls by "/"
+func (a *App) setupSearch() {
+	a.search.current = -1
+	a.search.input = tview.NewInputField().
+		SetLabel("/").
+		SetPlaceholder("Search subjects, checks and issues, Enter to apply, Esc to cancel")
+	a.search.input.SetBorder(true).SetTitle(" Search ")
+	a.search.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			a.applySearch(a.search.input.GetText())
+		}
+		a.closePrompt()
+	})
+}
+
+// openSearch shows the search input with the active query
+func (a *App) openSearch() {
+	a.search.input.SetText(a.search.query)
+	a.showPrompt(a.search.input)
+}
+
+// applySearch filters the subjects and checks lists to those matching query and selects the
+// first hit; an empty query shows everything again
+func (a *App) applySearch(query string) {
+	query = strings.TrimSpace(query)
+	a.search.query = query
+	a.search.pattern = nil
+	if query != "" {
+		a.search.pattern = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+
+	a.populateSubjectsList()
+	a.populateChecksList()
+	title := " Issues "
+	if query != "" {
+		title = fmt.Sprintf(" Issues matching \"%s\" ", tview.Escape(query))
+	}
+	a.subjectsList.SetTitle(title)
+	a.checksList.SetTitle(title)
+
+	// Hits are shown in the subjects or checks list
+	if a.selectedLeftPanel == 1 {
+		a.focusChecks()
+	} else {
+		a.focusSubjects()
+	}
+	a.detailsContent.Highlight()
+	a.updateDetailsForCurrentSelection()
+	a.nextSearchHit(1)
+}
+
+// collectSearchHits finds the matches in the details of every entry of the current list
+func (a *App) collectSearchHits() {
+	a.search.hits = nil
+	a.search.current = -1
+	if a.search.pattern != nil {
+		names, details := a.subjectNames, a.subjectDetailsText
+		if a.selectedLeftPanel == 1 {
+			names, details = a.checkNames, a.checkDetailsText
+		}
+		for entry, name := range names {
+			count := a.search.count(details(name))
+			for region := 0; region < count; region++ {
+				a.search.hits = append(a.search.hits, searchHit{entry: entry, region: strconv.Itoa(region)})
+			}
+		}
+	}
+	a.updateControls()
+}
+
+// nextSearchHit selects the next (step 1) or previous (step -1) hit and scrolls the details to it
+func (a *App) nextSearchHit(step int) {
+	if len(a.search.hits) == 0 {
+		return
+	}
+	switch {
+	case a.search.current < 0 && step < 0:
+		a.search.current = len(a.search.hits) - 1
+	case a.search.current < 0:
+		a.search.current = 0
+	default:
+		a.search.current = (a.search.current + step + len(a.search.hits)) % len(a.search.hits)
+	}
+	hit := a.search.hits[a.search.current]
+
+	if a.selectedLeftPanel == 1 {
+		a.checksList.SetCurrentItem(hit.entry)
+		a.currentSubject = a.checkNames[hit.entry]
+		a.showCheckDetails()
+	} else {
+		a.subjectsList.SetCurrentItem(hit.entry)
+		a.currentSubject = a.subjectNames[hit.entry]
+		a.showSubjectDetails()
+	}
+	a.detailsContent.Highlight(hit.region)
+	a.detailsContent.ScrollToHighlight()
+	a.updateControls()
+}
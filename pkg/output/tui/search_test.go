@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func searchTestData() *ScanResult {
+	return &ScanResult{
+		Scanned: []ScannedFile{
+			{Filename: "config.txt", Issues: []CheckSummary{{Checkname: "IsFreeOfKeywords", IssueCount: 2}}},
+			{Filename: "my file.txt", Issues: []CheckSummary{{Checkname: "HasNoWhiteSpace", IssueCount: 1}}},
+		},
+		DetailsSubjectFocused: []SubjectDetails{
+			{Subject: "config.txt", Issues: []CheckIssue{
+				{Checkname: "IsFreeOfKeywords", Message: "Credentials detected 'secret'"},
+				{Checkname: "IsFreeOfKeywords", Message: "Credentials detected 'SECRET_KEY'"},
+			}},
+			{Subject: "my file.txt", Issues: []CheckIssue{{Checkname: "HasNoWhiteSpace", Message: "File name contains spaces"}}},
+		},
+		DetailsCheckFocused: []CheckDetails{
+			{Checkname: "IsFreeOfKeywords", Issues: []SubjectIssue{
+				{Subject: "config.txt", Message: "Credentials detected 'secret'"},
+				{Subject: "config.txt", Message: "Credentials detected 'SECRET_KEY'"},
+			}},
+			{Checkname: "HasNoWhiteSpace", Issues: []SubjectIssue{{Subject: "my file.txt", Message: "File name contains spaces"}}},
+		},
+	}
+}
+
+func TestAppSearch(t *testing.T) {
+	app := NewApp(searchTestData())
+
+	app.applySearch("secret")
+	if len(app.subjectNames) != 1 || app.subjectNames[0] != "config.txt" {
+		t.Errorf("Expected only config.txt to match, got %v", app.subjectNames)
+	}
+	if len(app.checkNames) != 1 || app.checkNames[0] != "IsFreeOfKeywords" {
+		t.Errorf("Expected only IsFreeOfKeywords to match, got %v", app.checkNames)
+	}
+	if len(app.search.hits) != 2 || app.search.current != 0 {
+		t.Fatalf("Expected the first of 2 hits to be selected, got %+v", app.search)
+	}
+	if details := app.detailsContent.GetText(false); !strings.Contains(details, `["1"][:blue]SECRET[:-][""]`) {
+		t.Errorf("Expected the matches to be highlighted, got:\n%s", details)
+	}
+	if !strings.Contains(app.controls.GetText(false), `"secret" 1/2`) {
+		t.Errorf("Expected the search status in the controls, got %q", app.controls.GetText(false))
+	}
+
+	app.nextSearchHit(1)
+	app.nextSearchHit(1)
+	if app.search.current != 0 {
+		t.Errorf("Expected n to wrap around, got hit %d", app.search.current)
+	}
+	app.nextSearchHit(-1)
+	if app.search.current != 1 {
+		t.Errorf("Expected N to go back to the last hit, got hit %d", app.search.current)
+	}
+
+	// Checks are searched in the checks list
+	app.focusChecks()
+	if len(app.search.hits) != 2 || app.search.current != -1 {
+		t.Errorf("Expected the hits of the checks list, got %+v", app.search.hits)
+	}
+
+	app.applySearch("")
+	if len(app.subjectNames) != 2 || len(app.checkNames) != 2 || len(app.search.hits) != 0 {
+		t.Errorf("Expected an empty query to show everything, got %v and %v", app.subjectNames, app.checkNames)
+	}
+}
+
+func TestAppSearchNoMatches(t *testing.T) {
+	app := NewApp(searchTestData())
+
+	app.applySearch("nothing like this")
+	if len(app.subjectNames) != 0 || app.currentSubject != "" {
+		t.Errorf("Expected no subjects, got %v", app.subjectNames)
+	}
+	if details := app.detailsContent.GetText(false); !strings.Contains(details, "No matches") {
+		t.Errorf("Expected a note without matches, got %q", details)
+	}
+	app.nextSearchHit(1)
+}
+
+func TestSearchStateHighlightSkipsTags(t *testing.T) {
+	var search searchState
+	if search.highlight("[yellow]text[white]") != "[yellow]text[white]" || !search.matches("anything") {
+		t.Error("Expected no changes without a query")
+	}
+
+	app := NewApp(searchTestData())
+	app.applySearch("yellow")
+	if app.search.count("[yellow]Subject: yellow.txt[white]") != 1 {
+		t.Error("Expected tags not to be searched")
+	}
+	if got := app.search.highlight("[yellow]Yellow[white]"); got != `[yellow]["0"][:blue]Yellow[:-][""][white]` {
+		t.Errorf("Unexpected highlight %q", got)
+	}
+}
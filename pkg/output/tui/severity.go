@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/eawag-rdm/pc/pkg/checks"
+)
+
+// severityFilter selects the issues shown by their severity, cycled with "L"
+type severityFilter int
+
+const (
+	showAllSeverities severityFilter = iota
+	showWarningsAndErrors
+	showErrorsOnly
+)
+
+// severityRank orders the severities, unknown severities rank lowest
+var severityRank = map[string]int{
+	string(checks.SeverityInfo):    1,
+	string(checks.SeverityWarning): 2,
+	string(checks.SeverityError):   3,
+}
+
+// allows reports whether issues of severity are shown
+func (f severityFilter) allows(severity string) bool {
+	switch f {
+	case showWarningsAndErrors:
+		return severityRank[severity] >= severityRank[string(checks.SeverityWarning)]
+	case showErrorsOnly:
+		return severity == string(checks.SeverityError)
+	}
+	return true
+}
+
+// String describes the filter for the summary panel
+func (f severityFilter) String() string {
+	switch f {
+	case showWarningsAndErrors:
+		return "warnings and errors"
+	case showErrorsOnly:
+		return "errors only"
+	}
+	return "all"
+}
+
+// severityColor returns the tview color issues of severity are shown in
+func severityColor(severity string) string {
+	switch severity {
+	case string(checks.SeverityError):
+		return "red"
+	case string(checks.SeverityWarning):
+		return "yellow"
+	case string(checks.SeverityInfo):
+		return "blue"
+	}
+	return "white"
+}
+
+// severityOf returns the severity of a check as stored in the report. Reports written before
+// severities were added fall back to the severity of the registered check.
+func (index reportIndex) severityOf(checkname string) string {
+	if check, ok := index.checks[checkname]; ok && check.Severity != "" {
+		return check.Severity
+	}
+	return string(checks.SeverityOf(checkname))
+}
+
+// showsCheck reports whether the issues of a check pass the severity filter
+func (a *App) showsCheck(checkname string) bool {
+	return a.severity.allows(a.index.severityOf(checkname))
+}
+
+// filtering reports whether the lists leave out issues because of the search or severity filter
+func (a *App) filtering() bool {
+	return a.search.query != "" || a.severity != showAllSeverities
+}
+
+// severityBreakdown formats the number of issues per severity for the summary panel
+func (a *App) severityBreakdown() string {
+	counts := make(map[string]int)
+	for _, check := range a.data.DetailsCheckFocused {
+		counts[a.index.severityOf(check.Checkname)] += len(check.Issues)
+	}
+	return fmt.Sprintf("[red]%s[white] · [yellow]%s[white] · [blue]%d info[white]  |  Showing: %s",
+		plural(counts[string(checks.SeverityError)], "error"),
+		plural(counts[string(checks.SeverityWarning)], "warning"),
+		counts[string(checks.SeverityInfo)],
+		a.severity)
+}
+
+// cycleSeverityFilter switches between showing all issues, warnings and errors, and errors only
+func (a *App) cycleSeverityFilter() {
+	a.severity = (a.severity + 1) % 3
+	a.populateSubjectsList()
+	a.populateChecksList()
+	a.updateInfo()
+	switch a.selectedLeftPanel {
+	case 0:
+		a.focusSubjects()
+	case 1:
+		a.focusChecks()
+	}
+	a.updateDetailsForCurrentSelection()
+}
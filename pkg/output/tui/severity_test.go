@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSeverityFilterAllows(t *testing.T) {
+	tests := []struct {
+		filter   severityFilter
+		severity string
+		expected bool
+	}{
+		{showAllSeverities, "info", true},
+		{showAllSeverities, "", true},
+		{showWarningsAndErrors, "info", false},
+		{showWarningsAndErrors, "warning", true},
+		{showWarningsAndErrors, "error", true},
+		{showErrorsOnly, "warning", false},
+		{showErrorsOnly, "error", true},
+	}
+	for _, test := range tests {
+		if allowed := test.filter.allows(test.severity); allowed != test.expected {
+			t.Errorf("%s allows %q = %v, expected %v", test.filter, test.severity, allowed, test.expected)
+		}
+	}
+}
+
+func TestAppSeverityFilter(t *testing.T) {
+	data := searchTestData()
+	data.DetailsCheckFocused[0].Severity = "error"
+	app := NewApp(data)
+
+	if !strings.Contains(app.info.GetText(true), "Severity: 2 errors · 1 warning · 0 info  |  Showing: all") {
+		t.Errorf("Expected the severity breakdown in the summary panel, got:\n%s", app.info.GetText(true))
+	}
+	if item, _ := app.checksList.GetItemText(0); !strings.HasPrefix(item, "[red]IsFreeOfKeywords") {
+		t.Errorf("Expected the error check in red, got %q", item)
+	}
+	// HasNoWhiteSpace has no severity in the report and falls back to the registered one
+	if item, _ := app.checksList.GetItemText(1); !strings.HasPrefix(item, "[yellow]HasNoWhiteSpace") {
+		t.Errorf("Expected the warning check in yellow, got %q", item)
+	}
+
+	app.cycleSeverityFilter()
+	if len(app.subjectNames) != 2 {
+		t.Errorf("Expected warnings and errors to show both subjects, got %v", app.subjectNames)
+	}
+
+	app.cycleSeverityFilter()
+	if len(app.subjectNames) != 1 || app.subjectNames[0] != "config.txt" {
+		t.Errorf("Expected only config.txt with errors, got %v", app.subjectNames)
+	}
+	if len(app.checkNames) != 1 || app.checkNames[0] != "IsFreeOfKeywords" {
+		t.Errorf("Expected only the error check, got %v", app.checkNames)
+	}
+	if !strings.Contains(app.info.GetText(true), "Showing: errors only") {
+		t.Errorf("Expected the filter in the summary panel, got:\n%s", app.info.GetText(true))
+	}
+
+	app.cycleSeverityFilter()
+	if len(app.checkNames) != 2 {
+		t.Errorf("Expected all checks again, got %v", app.checkNames)
+	}
+}
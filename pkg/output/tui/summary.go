@@ -4,19 +4,36 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+	"github.com/eawag-rdm/pc/pkg/i18n"
 )
 
-const (
-	// maxIssuesBeforeTruncation is the number of issues to show before truncating
-	maxIssuesBeforeTruncation = 5
-	// minGroupSizeForTruncation is the minimum group size to trigger truncation
-	minGroupSizeForTruncation = 3
+// Truncation limits the issues listed of a group of similar issues in the summaries
+type Truncation struct {
+	MaxIssues    int // Issues of a group listed before the rest is counted, 0 lists all issues
+	MinGroupSize int // Groups with fewer issues are listed completely
+}
+
+var (
+	// DefaultTruncation lists five issues of groups of at least three
+	DefaultTruncation = Truncation{MaxIssues: 5, MinGroupSize: 3}
+	// NoTruncation lists every issue, e.g. when curators need the complete list
+	NoTruncation = Truncation{}
 )
 
+// truncates reports whether a group of n issues is cut short
+func (t Truncation) truncates(n int) bool {
+	return t.MaxIssues > 0 && n > t.MaxIssues && n >= t.MinGroupSize
+}
+
 // SummaryGenerator creates plain-text summaries grouped by check type
 type SummaryGenerator struct {
-	data     *ScanResult
-	location string
+	data       *ScanResult
+	location   string
+	baseline   *baseline.Baseline // Triaged issues, nil to list all issues
+	letter     *Letter            // Text around the issues, nil for the built-in one
+	truncation Truncation         // Issues listed of groups of similar issues
 }
 
 // IssueItem represents a single issue for the summary
@@ -24,6 +41,23 @@ type IssueItem struct {
 	Subject     string // filename or "Repository"
 	ArchivePath string // inner path if from archive (empty if not from archive)
 	Message     string // the issue content (without archive suffix)
+	Line        int    // line of the file, 0 if unknown
+	Count       int    // number of identical findings merged into the issue, 0 for one
+}
+
+// summaryStyle renders the issues of a check, so the plain text and Markdown summaries share
+// the grouping and truncation
+type summaryStyle struct {
+	item      func(item IssueItem) string                    // One issue
+	truncated func(remaining int, displayName string) string // The issues left out of a group
+}
+
+// plainStyle is the style of the plain-text summary
+var plainStyle = summaryStyle{
+	item: formatIssueItem,
+	truncated: func(remaining int, displayName string) string {
+		return "  ... " + i18n.Tf("and %d more in %s", remaining, displayName) + "\n"
+	},
 }
 
 // groupedIssue wraps an issue with computed grouping keys for pattern detection
@@ -47,29 +81,41 @@ type issueGroup struct {
 // NewSummaryGenerator creates a generator from scan results
 func NewSummaryGenerator(data *ScanResult, location string) *SummaryGenerator {
 	return &SummaryGenerator{
-		data:     data,
-		location: location,
+		data:       data,
+		location:   location,
+		truncation: DefaultTruncation,
 	}
 }
 
 // Generate creates the plain-text summary grouped by check type
 func (sg *SummaryGenerator) Generate() string {
 	if sg.data == nil {
-		return "No scan data available."
+		return i18n.T("No scan data available.")
 	}
 
-	var sb strings.Builder
+	summary, data := sg.issueSummary()
+	// A letter template replaces the introductory text, the built-in one is used if it fails
+	if sg.letter != nil {
+		if letter, err := sg.letter.render(data); err == nil {
+			return letter
+		}
+	}
+	return i18n.T("We have analyzed your data package and found a few issues. Please address them and get back to us once you're done. Then, we can continue with the publication process. Feel free to get back to us, if something is unclear.") + "\n\n" + summary
+}
 
-	// Introductory text
-	sb.WriteString("We have analyzed your data package and found a few issues. Please address them and get back to us once you're done. Then, we can continue with the publication process. Feel free to get back to us, if something is unclear.\n\n")
+// issueSummary formats the header and the open issues grouped by check, and returns them with
+// the variables of a letter
+func (sg *SummaryGenerator) issueSummary() (string, LetterData) {
+	var sb strings.Builder
+	data := LetterData{Location: sg.location, Timestamp: i18n.FormatTimestamp(sg.data.Timestamp)}
 
 	// Header
-	sb.WriteString("=== Package Checker Scan Summary ===\n")
+	sb.WriteString("=== " + i18n.T("Package Checker Scan Summary") + " ===\n")
 	if sg.location != "" {
-		sb.WriteString(fmt.Sprintf("Location: %s\n", sg.location))
+		sb.WriteString(fmt.Sprintf("%s %s\n", i18n.T("Location:"), sg.location))
 	}
 	if sg.data.Timestamp != "" {
-		sb.WriteString(fmt.Sprintf("Timestamp: %s\n", sg.data.Timestamp))
+		sb.WriteString(fmt.Sprintf("%s %s\n", i18n.T("Timestamp:"), data.Timestamp))
 	}
 	sb.WriteString("\n")
 
@@ -77,21 +123,28 @@ func (sg *SummaryGenerator) Generate() string {
 	totalIssues := 0
 	filesWithIssues := make(map[string]struct{})
 
-	// Group issues by check type (already available in DetailsCheckFocused)
-	if len(sg.data.DetailsCheckFocused) == 0 {
-		sb.WriteString("No issues found.\n")
-		return sb.String()
-	}
-
-	sb.WriteString("## Issues by Type\n\n")
-
-	// Sort check names for consistent output
+	// Group issues by check type (already available in DetailsCheckFocused), triaged issues
+	// are only counted
+	triaged := sg.suppressed()
 	checkNames := make([]string, 0, len(sg.data.DetailsCheckFocused))
 	checkMap := make(map[string][]SubjectIssue)
+	openIssues := 0
 	for _, check := range sg.data.DetailsCheckFocused {
 		checkNames = append(checkNames, check.Checkname)
-		checkMap[check.Checkname] = check.Issues
+		checkMap[check.Checkname] = sg.openIssues(check.Checkname, check.Issues, &triaged)
+		openIssues += len(checkMap[check.Checkname])
+	}
+	data.Accepted, data.FalsePositives = triaged.Accepted, triaged.FalsePositives
+	if openIssues == 0 {
+		sb.WriteString(i18n.T("No issues found.") + "\n")
+		sb.WriteString(formatTriaged(triaged))
+		data.Summary = sb.String()
+		return data.Summary, data
 	}
+
+	sb.WriteString("## " + i18n.T("Issues by Type") + "\n\n")
+
+	// Sort check names for consistent output
 	sort.Strings(checkNames)
 
 	for _, checkName := range checkNames {
@@ -108,40 +161,82 @@ func (sg *SummaryGenerator) Generate() string {
 
 		// Human-readable check name
 		displayName := humanizeCheckName(checkName)
-		sb.WriteString(fmt.Sprintf("### %s (%d issue", displayName, len(issues)))
-		if len(issues) != 1 {
-			sb.WriteString("s")
-		}
-		sb.WriteString(")\n")
+		sb.WriteString(fmt.Sprintf("### %s (%s)\n", displayName, plural(len(issues), "issue")))
 
 		// Format issues with smart truncation
-		sb.WriteString(formatIssuesWithTruncation(issues))
+		sb.WriteString(formatIssuesWithTruncation(issues, plainStyle, sg.truncation))
 		sb.WriteString("\n")
 	}
 
 	// Summary footer
 	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("Total: %d issue", totalIssues))
-	if totalIssues != 1 {
-		sb.WriteString("s")
+	sb.WriteString(i18n.T("Total:") + " " + i18n.Tf("%s in %s", plural(totalIssues, "issue"), plural(len(filesWithIssues), "file")) + "\n")
+	sb.WriteString(formatTriaged(triaged))
+
+	data.Issues, data.Files, data.Summary = totalIssues, len(filesWithIssues), sb.String()
+	return data.Summary, data
+}
+
+// SetTruncation sets how many issues of groups of similar issues are listed
+func (sg *SummaryGenerator) SetTruncation(truncation Truncation) {
+	sg.truncation = truncation
+}
+
+// SetLetter writes the summary with the letter template, nil for the built-in text
+func (sg *SummaryGenerator) SetLetter(letter *Letter) {
+	sg.letter = letter
+}
+
+// SetBaseline hides the issues triaged in b from the summary; they are counted separately
+func (sg *SummaryGenerator) SetBaseline(b *baseline.Baseline) {
+	sg.baseline = b
+}
+
+// suppressed returns the number of findings hidden by the baseline during the scan
+func (sg *SummaryGenerator) suppressed() Suppressed {
+	if sg.data.Suppressed == nil {
+		return Suppressed{}
 	}
-	sb.WriteString(fmt.Sprintf(" in %d file", len(filesWithIssues)))
-	if len(filesWithIssues) != 1 {
-		sb.WriteString("s")
+	return *sg.data.Suppressed
+}
+
+// openIssues returns the issues of a check that are not triaged in the baseline and adds the
+// triaged ones to counts
+func (sg *SummaryGenerator) openIssues(checkname string, issues []SubjectIssue, counts *Suppressed) []SubjectIssue {
+	if sg.baseline == nil {
+		return issues
 	}
-	sb.WriteString("\n")
+	open := make([]SubjectIssue, 0, len(issues))
+	for _, issue := range issues {
+		switch sg.baseline.Status(scannedFolder(sg.data, issue.Location), checkname, issue.Path, issue.ArchiveName, issue.Message) {
+		case baseline.StatusAccepted:
+			counts.Accepted++
+		case baseline.StatusFalsePositive:
+			counts.FalsePositives++
+		default:
+			open = append(open, issue)
+		}
+	}
+	return open
+}
 
-	return sb.String()
+// formatTriaged formats the numbers of triaged findings left out of the summary, empty if there
+// are none
+func formatTriaged(counts Suppressed) string {
+	if counts == (Suppressed{}) {
+		return ""
+	}
+	return i18n.Tf("Not listed: %d accepted, %s (baseline)", counts.Accepted, plural(counts.FalsePositives, "false positive")) + "\n"
 }
 
 // formatIssuesWithTruncation formats issues with automatic pattern-based truncation
-func formatIssuesWithTruncation(issues []SubjectIssue) string {
-	if len(issues) <= maxIssuesBeforeTruncation {
+func formatIssuesWithTruncation(issues []SubjectIssue, style summaryStyle, truncation Truncation) string {
+	if !truncation.truncates(len(issues)) {
 		// No truncation needed, output all
 		var sb strings.Builder
 		for _, issue := range issues {
 			item := parseIssueItem(issue)
-			sb.WriteString(formatIssueItem(item))
+			sb.WriteString(style.item(item))
 		}
 		return sb.String()
 	}
@@ -156,7 +251,7 @@ func formatIssuesWithTruncation(issues []SubjectIssue) string {
 	groups := detectAndGroupIssues(grouped)
 
 	// Format output with truncation
-	return formatGroupedOutput(groups)
+	return formatGroupedOutput(groups, style, truncation)
 }
 
 // computeGroupingKeys extracts grouping keys from an issue
@@ -185,8 +280,9 @@ func computeGroupingKeys(issue SubjectIssue, index int) groupedIssue {
 // extractParentPath returns the parent directory portion of a path
 // "archive.zip -> folder/subfolder/file.txt" -> "archive.zip -> folder/subfolder"
 // "folder/file.txt" -> "folder"
+// "archive.zip -> folder\file.txt" -> "archive.zip -> folder" (archives written on Windows)
 func extractParentPath(path string) string {
-	lastSlash := strings.LastIndex(path, "/")
+	lastSlash := strings.LastIndexAny(path, "/\\")
 	if lastSlash == -1 {
 		return ""
 	}
@@ -251,39 +347,38 @@ func buildGroupDisplayName(group *issueGroup) string {
 	}
 
 	if group.messageKey != "" && group.parentPath != "" {
-		parts = append(parts, fmt.Sprintf("with \"%s\"", group.messageKey))
+		parts = append(parts, fmt.Sprintf("%s \"%s\"", i18n.T("with"), group.messageKey))
 	} else if group.messageKey != "" {
 		parts = append(parts, fmt.Sprintf("\"%s\"", group.messageKey))
 	}
 
 	if len(parts) == 0 {
-		return "similar issues"
+		return i18n.T("similar issues")
 	}
 
 	return strings.Join(parts, " ")
 }
 
 // formatGroupedOutput formats groups with truncation where applicable
-func formatGroupedOutput(groups []issueGroup) string {
+func formatGroupedOutput(groups []issueGroup, style summaryStyle, truncation Truncation) string {
 	var sb strings.Builder
 
 	for _, group := range groups {
-		if len(group.issues) <= maxIssuesBeforeTruncation ||
-			len(group.issues) < minGroupSizeForTruncation {
+		if !truncation.truncates(len(group.issues)) {
 			// Small group - show all issues
 			for _, gi := range group.issues {
 				item := parseIssueItem(gi.issue)
-				sb.WriteString(formatIssueItem(item))
+				sb.WriteString(style.item(item))
 			}
 		} else {
 			// Large group - show first N and truncate
-			for i := 0; i < maxIssuesBeforeTruncation; i++ {
+			for i := 0; i < truncation.MaxIssues; i++ {
 				item := parseIssueItem(group.issues[i].issue)
-				sb.WriteString(formatIssueItem(item))
+				sb.WriteString(style.item(item))
 			}
 
-			remaining := len(group.issues) - maxIssuesBeforeTruncation
-			sb.WriteString(fmt.Sprintf("  ... and %d more in %s\n", remaining, group.displayName))
+			remaining := len(group.issues) - truncation.MaxIssues
+			sb.WriteString(style.truncated(remaining, group.displayName))
 		}
 	}
 
@@ -295,6 +390,8 @@ func parseIssueItem(issue SubjectIssue) IssueItem {
 	item := IssueItem{
 		Subject: issue.Subject,
 		Message: issue.Message,
+		Line:    issue.Line,
+		Count:   issue.Count,
 	}
 
 	// Use structured ArchiveName field if present
@@ -318,17 +415,23 @@ func formatIssueItem(item IssueItem) string {
 		sb.WriteString(item.ArchivePath)
 	} else if item.Subject == "Repository" || item.Subject == "" {
 		// Repository-level issue
-		sb.WriteString("Repository")
+		sb.WriteString(i18n.T("Repository"))
 	} else {
 		// Regular file issue
 		sb.WriteString(item.Subject)
 	}
+	if item.Line > 0 {
+		sb.WriteString(fmt.Sprintf(":%d", item.Line))
+	}
 
 	// Add message if present and different from subject
 	if item.Message != "" && item.Message != item.Subject {
 		sb.WriteString(": ")
 		sb.WriteString(item.Message)
 	}
+	if item.Count > 1 {
+		sb.WriteString(fmt.Sprintf(" (%d×)", item.Count))
+	}
 
 	sb.WriteString("\n")
 	return sb.String()
@@ -352,7 +455,7 @@ func humanizeCheckName(checkName string) string {
 	}
 
 	if humanName, ok := nameMap[checkName]; ok {
-		return humanName
+		return i18n.T(humanName)
 	}
 
 	// Fallback: convert CamelCase to spaces
@@ -3,6 +3,8 @@ package tui
 import (
 	"strings"
 	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
 )
 
 func TestSummaryGenerator_Generate_EmptyData(t *testing.T) {
@@ -297,6 +299,22 @@ func TestFormatIssueItem_Regular(t *testing.T) {
 	}
 }
 
+func TestFormatIssueItem_LineAndCount(t *testing.T) {
+	item := IssueItem{
+		Subject: "run.log",
+		Message: "Security credentials detected 'password'",
+		Line:    7,
+		Count:   3,
+	}
+
+	result := formatIssueItem(item)
+	expected := "  - run.log:7: Security credentials detected 'password' (3×)\n"
+
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
 func TestFormatIssueItem_Archive(t *testing.T) {
 	item := IssueItem{
 		Subject:     "archive.zip",
@@ -336,6 +354,7 @@ func TestExtractParentPath(t *testing.T) {
 		{"file.txt", ""},
 		{"archive.zip -> Level0/2022-09-02T122044.xml", "archive.zip -> Level0"},
 		{"Lake Hallwil data.zip -> Level0/not used/file.xml", "Lake Hallwil data.zip -> Level0/not used"},
+		{"archive.zip -> folder\\subfolder\\file.txt", "archive.zip -> folder\\subfolder"},
 	}
 
 	for _, tt := range tests {
@@ -531,3 +550,57 @@ func TestTruncation_NoTruncationForSmallGroups(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncation_Configured(t *testing.T) {
+	issues := make([]SubjectIssue, 10)
+	for i := range issues {
+		issues[i] = SubjectIssue{
+			Subject:     "Level0/file" + string(rune('A'+i)) + ".xml",
+			ArchiveName: "data.zip",
+			Message:     "File name contains spaces",
+		}
+	}
+	data := &ScanResult{
+		DetailsCheckFocused: []CheckDetails{{Checkname: "HasValidFileName", Issues: issues}},
+	}
+
+	sg := NewSummaryGenerator(data, "test")
+	sg.SetTruncation(Truncation{MaxIssues: 2, MinGroupSize: 3})
+	if result := sg.Generate(); !strings.Contains(result, "... and 8 more in") {
+		t.Errorf("Expected 2 issues listed before truncating, got:\n%s", result)
+	}
+
+	sg.SetTruncation(Truncation{MaxIssues: 2, MinGroupSize: 11})
+	if result := sg.Generate(); strings.Contains(result, "more in") {
+		t.Errorf("Expected groups smaller than MinGroupSize to be listed completely, got:\n%s", result)
+	}
+
+	sg.SetTruncation(NoTruncation)
+	result := sg.Generate()
+	if strings.Contains(result, "more in") || !strings.Contains(result, "fileJ.xml") {
+		t.Errorf("Expected every issue without truncation, got:\n%s", result)
+	}
+	if markdown := sg.GenerateMarkdown(); strings.Contains(markdown, "more in") {
+		t.Errorf("Expected every issue in the Markdown summary without truncation, got:\n%s", markdown)
+	}
+}
+
+func TestSummaryGenerator_Timezone(t *testing.T) {
+	if err := i18n.SetTimezone("Europe/Zurich"); err != nil {
+		t.Fatal(err)
+	}
+	defer i18n.SetTimezone("")
+	data := &ScanResult{Timestamp: "2024-01-14T10:30:00Z", DetailsCheckFocused: []CheckDetails{}}
+
+	sg := NewSummaryGenerator(data, "test-package")
+	if result := sg.Generate(); !strings.Contains(result, "Timestamp: 2024-01-14 11:30:00 CET") {
+		t.Errorf("Expected the time of the scan in the time zone, got '%s'", result)
+	}
+	if result := sg.GenerateMarkdown(); !strings.Contains(result, "2024-01-14 11:30:00 CET") {
+		t.Errorf("Expected the time of the scan in the time zone, got '%s'", result)
+	}
+	// The report keeps the time in UTC
+	if data.Timestamp != "2024-01-14T10:30:00Z" {
+		t.Errorf("Expected the timestamp of the report to stay in UTC, got %s", data.Timestamp)
+	}
+}
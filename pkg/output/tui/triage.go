@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+)
+
+// triageItem is an issue of the triage list
+type triageItem struct {
+	label string         // Check of a subject's issue or subject of a check's issue
+	root  string         // Folder the issue was scanned in, see scannedFolder
+	entry baseline.Entry // The issue, Status is its current triage status
+}
+
+// triageState holds the baseline issues are triaged into and the modal listing the issues of
+// the selected subject or check
+type triageState struct {
+	baseline *baseline.Baseline // nil disables triage
+	path     string             // File the baseline is saved to
+	changed  bool               // Issues were triaged since the baseline was saved
+	modal    *tview.Flex
+	list     *tview.List
+	visible  bool
+	items    []triageItem
+}
+
+// SetBaseline enables triage; decisions are added to b and written to path by SaveBaseline
+func (a *App) SetBaseline(path string, b *baseline.Baseline) {
+	a.triage.path = path
+	a.triage.baseline = b
+}
+
+// SaveBaseline writes the baseline if issues were triaged and reports whether it was written
+func (a *App) SaveBaseline() (bool, error) {
+	if a.triage.baseline == nil || !a.triage.changed {
+		return false, nil
+	}
+	if err := a.triage.baseline.Save(a.triage.path); err != nil {
+		return false, err
+	}
+	a.triage.changed = false
+	return true, nil
+}
+
+// triageStatus returns the triage status of an issue, empty if it is not triaged
+func (a *App) triageStatus(root, checkname, path, archiveName, message string) string {
+	if a.triage.baseline == nil {
+		return ""
+	}
+	return a.triage.baseline.Status(root, checkname, path, archiveName, message)
+}
+
+// scannedFolder returns the folder the paths of an issue found in location are relative to in
+// the baseline; location is only set in merged reports
+func scannedFolder(data *ScanResult, location string) string {
+	if location != "" {
+		return location
+	}
+	return data.Location
+}
+
+// triageLabel marks triaged issues in the details panel and the triage list
+func triageLabel(status string) string {
+	switch status {
+	case baseline.StatusAccepted:
+		return " [green](accepted)[white]"
+	case baseline.StatusFalsePositive:
+		return " [gray](false positive)[white]"
+	}
+	return ""
+}
+
+// setupTriageModal creates the modal listing the issues to triage
+func (a *App) setupTriageModal() {
+	a.triage.list = tview.NewList().ShowSecondaryText(true)
+	a.triage.list.SetBorder(true).SetTitle(" Issues ")
+
+	instructions := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[yellow]A[white]=Accept  [yellow]F[white]=False positive (press again to undo)  |  [yellow]ESC or T[white] to close")
+	instructions.SetTextAlign(tview.AlignCenter)
+
+	innerFlex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.triage.list, 0, 1, true).
+		AddItem(instructions, 1, 0, false)
+	innerFlex.SetBorder(true).SetTitle(" Triage ")
+	innerFlex.SetBorderColor(tcell.ColorYellow)
+
+	a.triage.modal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 2, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 4, 0, false).
+			AddItem(innerFlex, 0, 1, true).
+			AddItem(nil, 4, 0, false),
+			0, 1, true).
+		AddItem(nil, 2, 0, false)
+}
+
+// openTriage shows the issues of the selected subject or check for triage
+func (a *App) openTriage() {
+	if a.triage.baseline == nil {
+		a.showStatus("[yellow]Triage is disabled because no baseline is used[white]")
+		return
+	}
+	if a.isScanning {
+		a.showStatus("[yellow]Issues can be triaged when the scan has finished[white]")
+		return
+	}
+	if a.selectedLeftPanel > 1 || a.currentSubject == "" {
+		a.showStatus("[yellow]Select a subject or check to triage its issues[white]")
+		return
+	}
+	a.triage.items = a.triageItems()
+	if len(a.triage.items) == 0 {
+		return
+	}
+	a.triage.list.SetTitle(fmt.Sprintf(" Issues of %s ", tview.Escape(a.currentSubject)))
+	a.populateTriageList()
+	a.triage.list.SetCurrentItem(0)
+
+	a.triage.visible = true
+	a.app.SetRoot(a.triage.modal, true)
+	a.app.SetFocus(a.triage.list)
+}
+
+// closeTriage returns to the main view with the triage shown in the details
+func (a *App) closeTriage() {
+	if !a.triage.visible {
+		return
+	}
+	a.triage.visible = false
+	a.app.SetRoot(a.flex, true)
+	a.updateDetailsForCurrentSelection()
+	a.restoreFocus()
+	if a.triage.changed {
+		a.showStatus("[green]Triage is saved to " + tview.Escape(a.triage.path) + " on exit[white]")
+	}
+}
+
+// triageItems returns the issues of the selected subject or check
+func (a *App) triageItems() []triageItem {
+	var items []triageItem
+	if a.selectedLeftPanel == 1 {
+		check, ok := a.index.checks[a.currentSubject]
+		if !ok {
+			return nil
+		}
+		for _, issue := range check.Issues {
+			label := issue.Subject
+			if issue.ArchiveName != "" {
+				label = issue.ArchiveName + " > " + issue.Subject
+			}
+			items = append(items, triageItem{label: label, root: scannedFolder(a.data, issue.Location), entry: baseline.Entry{
+				ID: issue.ID, Checkname: check.Checkname, Path: issue.Path, ArchiveName: issue.ArchiveName, Message: issue.Message,
+			}})
+		}
+	} else {
+		subject, ok := a.index.subjects[a.currentSubject]
+		if !ok {
+			return nil
+		}
+		for _, issue := range subject.Issues {
+			if !a.showsCheck(issue.Checkname) {
+				continue
+			}
+			items = append(items, triageItem{label: issue.Checkname, root: scannedFolder(a.data, subject.Location), entry: baseline.Entry{
+				ID: issue.ID, Checkname: issue.Checkname, Path: subject.Path, ArchiveName: subject.ArchiveName, Message: issue.Message,
+			}})
+		}
+	}
+
+	for i := range items {
+		entry := &items[i].entry
+		entry.Status = a.triageStatus(items[i].root, entry.Checkname, entry.Path, entry.ArchiveName, entry.Message)
+	}
+	return items
+}
+
+// populateTriageList shows the triage items, keeping the selection
+func (a *App) populateTriageList() {
+	current := a.triage.list.GetCurrentItem()
+	a.triage.list.Clear()
+	for _, item := range a.triage.items {
+		a.triage.list.AddItem(tview.Escape(item.label)+triageLabel(item.entry.Status), "   "+tview.Escape(item.entry.Message), 0, nil)
+	}
+	a.triage.list.SetCurrentItem(current)
+}
+
+// markTriage sets the status of the selected issue; marking it with its current status removes
+// it from the baseline again
+func (a *App) markTriage(status string) {
+	index := a.triage.list.GetCurrentItem()
+	if index < 0 || index >= len(a.triage.items) {
+		return
+	}
+	item := &a.triage.items[index]
+	if item.entry.Status == status {
+		status = ""
+	}
+	item.entry.Status = status
+	a.triage.baseline.Mark(item.root, item.entry)
+	a.triage.changed = true
+	a.populateTriageList()
+}
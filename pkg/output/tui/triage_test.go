@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+)
+
+func TestAppTriage(t *testing.T) {
+	app := NewApp(searchTestData())
+
+	app.openTriage()
+	if app.triage.visible {
+		t.Fatal("Expected triage to be disabled without a baseline")
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	app.SetBaseline(path, baseline.New())
+	app.currentSubject = "config.txt"
+	app.openTriage()
+	if !app.triage.visible || len(app.triage.items) != 2 {
+		t.Fatalf("Expected the 2 issues of config.txt, got %+v", app.triage.items)
+	}
+
+	app.markTriage(baseline.StatusAccepted)
+	app.triage.list.SetCurrentItem(1)
+	app.markTriage(baseline.StatusFalsePositive)
+	app.markTriage(baseline.StatusFalsePositive)
+	app.closeTriage()
+	if details := app.detailsContent.GetText(true); strings.Count(details, "(accepted)") != 1 || strings.Contains(details, "(false positive)") {
+		t.Errorf("Expected one accepted issue in the details, got:\n%s", details)
+	}
+
+	summary := NewSummaryGenerator(app.data, "")
+	summary.SetBaseline(app.triage.baseline)
+	text := summary.Generate()
+	if strings.Contains(text, "'secret'") || !strings.Contains(text, "SECRET_KEY") {
+		t.Errorf("Expected the accepted issue to be left out of the summary, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Not listed: 1 accepted, 0 false positives (baseline)") {
+		t.Errorf("Expected the accepted count in the summary, got:\n%s", text)
+	}
+
+	saved, err := app.SaveBaseline()
+	if err != nil || !saved {
+		t.Fatalf("Expected the baseline to be saved, got %v, %v", saved, err)
+	}
+	loaded, err := baseline.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := loaded.Entries(); len(entries) != 1 || entries[0].Message != "Credentials detected 'secret'" || entries[0].Status != baseline.StatusAccepted {
+		t.Errorf("Expected the accepted issue in the baseline, got %+v", entries)
+	}
+	if saved, _ := app.SaveBaseline(); saved {
+		t.Error("Expected an unchanged baseline not to be saved again")
+	}
+}
+
+func TestAppTriageRelativePath(t *testing.T) {
+	data := &ScanResult{
+		Location: "/data/lake-ice",
+		DetailsSubjectFocused: []SubjectDetails{
+			{Subject: "config.txt", Path: "/data/lake-ice/raw/config.txt", Issues: []CheckIssue{{Checkname: "IsFreeOfKeywords", Message: "Credentials detected 'secret'"}}},
+		},
+	}
+	app := NewApp(data)
+	b := baseline.New()
+	app.SetBaseline(filepath.Join(t.TempDir(), "baseline.json"), b)
+	app.currentSubject = "config.txt"
+	app.openTriage()
+	app.markTriage(baseline.StatusAccepted)
+
+	if entries := b.Entries(); len(entries) != 1 || entries[0].Path != "raw/config.txt" {
+		t.Errorf("Expected the path relative to the scanned folder in the baseline, got %+v", entries)
+	}
+	if status := b.Status("/srv/lake-ice", "IsFreeOfKeywords", "/srv/lake-ice/raw/config.txt", "", "Credentials detected 'secret'"); status != baseline.StatusAccepted {
+		t.Errorf("Expected the issue to stay triaged when the folder is scanned elsewhere, got %q", status)
+	}
+}
+
+func TestSummarySuppressedCounts(t *testing.T) {
+	data := searchTestData()
+	data.Suppressed = &Suppressed{Accepted: 2, FalsePositives: 1}
+
+	text := NewSummaryGenerator(data, "").Generate()
+	if !strings.Contains(text, "Not listed: 2 accepted, 1 false positive (baseline)") {
+		t.Errorf("Expected the findings hidden by the scan in the summary, got:\n%s", text)
+	}
+	markdown := NewSummaryGenerator(data, "").GenerateMarkdown()
+	if !strings.Contains(markdown, "_Not listed: 2 accepted, 1 false positive (baseline)_") {
+		t.Errorf("Expected the findings hidden by the scan in the Markdown summary, got:\n%s", markdown)
+	}
+}
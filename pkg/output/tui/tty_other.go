@@ -0,0 +1,6 @@
+//go:build !windows
+
+package tui
+
+// ttyPath is the controlling terminal, written to directly for OSC 52
+const ttyPath = "/dev/tty"
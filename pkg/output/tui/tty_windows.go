@@ -0,0 +1,7 @@
+//go:build windows
+
+package tui
+
+// ttyPath is the console output buffer, written to directly for OSC 52. Windows Terminal and
+// recent consoles interpret virtual terminal sequences, the system clipboard is tried first.
+const ttyPath = "CONOUT$"
@@ -1,109 +1,64 @@
 package tui
 
-import "github.com/eawag-rdm/pc/pkg/output"
-
-// ScanResult represents the JSON structure from PC scanner
-type ScanResult struct {
-	Timestamp             string           `json:"timestamp"`
-	Scanned               []ScannedFile    `json:"scanned"`
-	Skipped               []SkippedFile    `json:"skipped"`
-	DetailsSubjectFocused []SubjectDetails `json:"details_subject_focused"`
-	DetailsCheckFocused   []CheckDetails   `json:"details_check_focused"`
-	PDFFiles              []string         `json:"pdf_files"`
-	Errors                []output.LogMessage `json:"errors"`
-	Warnings              []output.LogMessage `json:"warnings"`
-
-	// Lookup maps (built once, used for O(1) access)
-	subjectIndex map[string]*SubjectDetails // key: subject or "archive > subject"
-	checkIndex   map[string]*CheckDetails   // key: checkname
-
-	// Cached counts (computed once)
-	cachedTotalIssues   int
-	cachedHasRepository bool
-	cacheBuilt          bool
+import jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+
+// The TUI shows the report of the JSON formatter as it is, so scans are passed to it without
+// encoding them to JSON and decoding them again, and reports read from files decode the same way
+type (
+	ScanResult     = jsonformatter.ScanResult
+	Suppressed     = jsonformatter.Suppressed
+	ScannedFile    = jsonformatter.ScannedFile
+	SkippedFile    = jsonformatter.SkippedFile
+	SubjectDetails = jsonformatter.SubjectDetails
+	CheckDetails   = jsonformatter.CheckDetails
+	CheckSummary   = jsonformatter.CheckSummary
+	CheckIssue     = jsonformatter.CheckIssue
+	SubjectIssue   = jsonformatter.SubjectIssue
+)
+
+// reportIndex holds lookup maps and counts of the shown report, built once when it is loaded
+type reportIndex struct {
+	subjects      map[string]*SubjectDetails // key: subject or "archive > subject"
+	checks        map[string]*CheckDetails   // key: checkname
+	totalIssues   int
+	hasRepository bool
 }
 
-// BuildCache builds lookup maps and cached values for O(1) access.
-// This should be called once when data is loaded or updated.
-func (sr *ScanResult) BuildCache() {
-	if sr.cacheBuilt {
-		return
+// newReportIndex builds the lookup maps and counts of sr, which may be nil
+func newReportIndex(sr *ScanResult) reportIndex {
+	if sr == nil {
+		return reportIndex{}
+	}
+	index := reportIndex{
+		subjects: make(map[string]*SubjectDetails, len(sr.DetailsSubjectFocused)),
+		checks:   make(map[string]*CheckDetails, len(sr.DetailsCheckFocused)),
 	}
 
-	// Build subject index
-	sr.subjectIndex = make(map[string]*SubjectDetails, len(sr.DetailsSubjectFocused))
 	for i := range sr.DetailsSubjectFocused {
 		subject := &sr.DetailsSubjectFocused[i]
 		key := subject.Subject
 		if subject.ArchiveName != "" {
 			key = subject.ArchiveName + " > " + subject.Subject
 		}
-		sr.subjectIndex[key] = subject
+		index.subjects[key] = subject
 
 		if subject.Subject == "repository" {
-			sr.cachedHasRepository = true
+			index.hasRepository = true
 		}
 	}
 
-	// Build check index
-	sr.checkIndex = make(map[string]*CheckDetails, len(sr.DetailsCheckFocused))
 	for i := range sr.DetailsCheckFocused {
 		check := &sr.DetailsCheckFocused[i]
-		sr.checkIndex[check.Checkname] = check
+		index.checks[check.Checkname] = check
 	}
 
-	// Calculate total issues once
-	sr.cachedTotalIssues = 0
 	for _, file := range sr.Scanned {
 		for _, issue := range file.Issues {
-			sr.cachedTotalIssues += issue.IssueCount
+			index.totalIssues += issue.IssueCount
 		}
 	}
-	if repo, ok := sr.subjectIndex["repository"]; ok {
-		sr.cachedTotalIssues += len(repo.Issues)
+	if repo, ok := index.subjects["repository"]; ok {
+		index.totalIssues += len(repo.Issues)
 	}
-
-	sr.cacheBuilt = true
-}
-
-type ScannedFile struct {
-	Filename string         `json:"filename"`
-	Issues   []CheckSummary `json:"issues"`
-}
-
-type SkippedFile struct {
-	Filename string `json:"filename"`
-	Path     string `json:"path"`
-	Reason   string `json:"reason"`
-}
-
-type SubjectDetails struct {
-	Subject     string       `json:"subject"`
-	Path        string       `json:"path"`
-	ArchiveName string       `json:"archive_name,omitempty"` // Parent archive if file is inside archive
-	Issues      []CheckIssue `json:"issues"`
-}
-
-type CheckDetails struct {
-	Checkname string         `json:"checkname"`
-	Issues    []SubjectIssue `json:"issues"`
-}
-
-type CheckSummary struct {
-	Checkname  string `json:"checkname"`
-	IssueCount int    `json:"issue_count"`
+	return index
 }
-
-type CheckIssue struct {
-	Checkname string `json:"checkname"`
-	Message   string `json:"message"`
-}
-
-type SubjectIssue struct {
-	Subject     string `json:"subject"`
-	Path        string `json:"path"`
-	ArchiveName string `json:"archive_name,omitempty"` // Parent archive if file is inside archive
-	Message     string `json:"message"`
-}
-
-// Using LogMessage from output package
\ No newline at end of file
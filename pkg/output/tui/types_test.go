@@ -163,4 +163,35 @@ func TestCheckDetails_Structure(t *testing.T) {
 	if len(check.Issues) != 2 {
 		t.Errorf("Expected 2 issues, got %d", len(check.Issues))
 	}
-}
\ No newline at end of file
+}
+func TestNewReportIndex(t *testing.T) {
+	data := &ScanResult{
+		Scanned: []ScannedFile{
+			{Filename: "a.txt", Issues: []CheckSummary{{Checkname: "HasNoWhiteSpace", IssueCount: 2}}},
+		},
+		DetailsSubjectFocused: []SubjectDetails{
+			{Subject: "a.txt", Issues: []CheckIssue{{Checkname: "HasNoWhiteSpace"}, {Checkname: "HasNoWhiteSpace"}}},
+			{Subject: "b.txt", ArchiveName: "data.zip", Issues: []CheckIssue{{Checkname: "IsFreeOfKeywords"}}},
+			{Subject: "repository", Issues: []CheckIssue{{Checkname: "HasReadme"}}},
+		},
+		DetailsCheckFocused: []CheckDetails{
+			{Checkname: "HasNoWhiteSpace", Severity: "warning"},
+			{Checkname: "HasReadme"},
+		},
+	}
+
+	index := newReportIndex(data)
+	if index.totalIssues != 3 || !index.hasRepository {
+		t.Errorf("Expected 3 issues and a repository issue, got %d and %v", index.totalIssues, index.hasRepository)
+	}
+	if index.subjects["data.zip > b.txt"] != &data.DetailsSubjectFocused[1] {
+		t.Error("Expected subjects in archives to be indexed with their archive")
+	}
+	if index.severityOf("HasNoWhiteSpace") != "warning" || index.severityOf("HasReadme") != "error" {
+		t.Errorf("Unexpected severities %s and %s", index.severityOf("HasNoWhiteSpace"), index.severityOf("HasReadme"))
+	}
+
+	if empty := newReportIndex(nil); empty.totalIssues != 0 || empty.hasRepository {
+		t.Errorf("Expected an empty index without a report, got %+v", empty)
+	}
+}
@@ -0,0 +1,191 @@
+package performance
+
+import "strings"
+
+// Match is an occurrence of a pattern: text[Start:End] matches patterns[Pattern]
+type Match struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// Automaton finds a set of patterns in a single pass over a text with the Aho-Corasick
+// algorithm, however many patterns there are. ASCII letters match case-insensitively; other
+// letters match as written in the pattern or in its lower or upper case. Build it once per
+// set of patterns, it is safe for concurrent use.
+type Automaton struct {
+	patterns []string
+	next     [][256]int32 // Transitions of each state on a folded byte, failures resolved
+	outputs  [][]output   // Patterns ending in each state, those of its failure states included
+	shortest int          // Length of the shortest pattern, shorter texts cannot match
+}
+
+// output is a pattern ending in a state, as one of its variants of the given length
+type output struct {
+	pattern int
+	length  int
+}
+
+// fold maps the bytes of patterns and texts to lower case ASCII
+var fold = func() (table [256]byte) {
+	for c := range table {
+		table[c] = byte(c)
+		if 'A' <= c && c <= 'Z' {
+			table[c] = byte(c) + 'a' - 'A'
+		}
+	}
+	return table
+}()
+
+// NewAutomaton builds the automaton of the patterns, empty patterns never match
+func NewAutomaton(patterns []string) *Automaton {
+	a := &Automaton{
+		patterns: patterns,
+		next:     make([][256]int32, 1),
+		outputs:  make([][]output, 1),
+	}
+	for i, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if a.shortest == 0 || len(pattern) < a.shortest {
+			a.shortest = len(pattern)
+		}
+		for _, variant := range variants(pattern) {
+			a.insert(variant, i)
+		}
+	}
+	a.link()
+	return a
+}
+
+// variants returns the spellings of pattern to look for, unique after folding
+func variants(pattern string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, variant := range []string{pattern, strings.ToLower(pattern), strings.ToUpper(pattern)} {
+		folded := string(foldBytes([]byte(variant)))
+		if !seen[folded] {
+			seen[folded] = true
+			unique = append(unique, folded)
+		}
+	}
+	return unique
+}
+
+// foldBytes folds text in place
+func foldBytes(text []byte) []byte {
+	for i, c := range text {
+		text[i] = fold[c]
+	}
+	return text
+}
+
+// insert adds the path of a folded pattern variant to the trie
+func (a *Automaton) insert(variant string, pattern int) {
+	state := int32(0)
+	for i := 0; i < len(variant); i++ {
+		c := variant[i]
+		if a.next[state][c] == 0 {
+			a.next = append(a.next, [256]int32{})
+			a.outputs = append(a.outputs, nil)
+			a.next[state][c] = int32(len(a.next) - 1)
+		}
+		state = a.next[state][c]
+	}
+	for _, out := range a.outputs[state] {
+		if out.pattern == pattern {
+			return
+		}
+	}
+	a.outputs[state] = append(a.outputs[state], output{pattern: pattern, length: len(variant)})
+}
+
+// link turns the trie into the automaton: states are visited breadth first, so the failure
+// state of each state, the longest proper suffix in the trie, is complete before it is used
+func (a *Automaton) link() {
+	failure := make([]int32, len(a.next))
+	var queue []int32
+	for c := 0; c < 256; c++ {
+		if child := a.next[0][c]; child != 0 {
+			queue = append(queue, child)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		a.outputs[state] = append(a.outputs[state], a.outputs[failure[state]]...)
+		for c := 0; c < 256; c++ {
+			if child := a.next[state][c]; child != 0 {
+				failure[child] = a.next[failure[state]][c]
+				queue = append(queue, child)
+			} else {
+				a.next[state][c] = a.next[failure[state]][c]
+			}
+		}
+	}
+}
+
+// scan calls found for each pattern ending at text[:end] until found returns false
+func (a *Automaton) scan(text []byte, found func(out output, end int) bool) {
+	if a.shortest == 0 || len(text) < a.shortest {
+		return
+	}
+	state := int32(0)
+	for i, c := range text {
+		state = a.next[state][fold[c]]
+		for _, out := range a.outputs[state] {
+			if !found(out, i+1) {
+				return
+			}
+		}
+	}
+}
+
+// FindAll returns the occurrences of the patterns ordered by their end. As with repeated
+// searches for each pattern, occurrences overlapping an earlier one of the same pattern are left
+// out, e.g. "aa" is found once in "aaa".
+func (a *Automaton) FindAll(text []byte) []Match {
+	var matches []Match
+	var ends []int // End of the last occurrence of each pattern
+	a.scan(text, func(out output, end int) bool {
+		if ends == nil {
+			ends = make([]int, len(a.patterns))
+		}
+		start := end - out.length
+		if ends[out.pattern] > start {
+			return true
+		}
+		ends[out.pattern] = end
+		matches = append(matches, Match{Pattern: out.pattern, Start: start, End: end})
+		return true
+	})
+	return matches
+}
+
+// Matched returns the indices of the patterns occurring in text, in the order they first occur
+func (a *Automaton) Matched(text []byte) []int {
+	var matched []int
+	var seen []bool
+	a.scan(text, func(out output, end int) bool {
+		if seen == nil {
+			seen = make([]bool, len(a.patterns))
+		}
+		if !seen[out.pattern] {
+			seen[out.pattern] = true
+			matched = append(matched, out.pattern)
+		}
+		return len(matched) < len(a.patterns)
+	})
+	return matched
+}
+
+// Contains reports whether any pattern occurs in text
+func (a *Automaton) Contains(text []byte) bool {
+	found := false
+	a.scan(text, func(output, int) bool {
+		found = true
+		return false
+	})
+	return found
+}
@@ -0,0 +1,158 @@
+package performance
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAutomatonFindAll(t *testing.T) {
+	automaton := NewAutomaton([]string{"he", "she", "hers", "", "aa"})
+	matches := automaton.FindAll([]byte("uSHErs aaa"))
+	expected := []Match{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 2, Start: 2, End: 6},
+		{Pattern: 4, Start: 7, End: 9},
+	}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %v, got %v", expected, matches)
+	}
+
+	if matched := automaton.Matched([]byte("hers and she")); !reflect.DeepEqual(matched, []int{0, 2, 1}) {
+		t.Errorf("Expected the patterns in the order they occur, got %v", matched)
+	}
+	if !automaton.Contains([]byte("xxhexx")) || automaton.Contains([]byte("h e")) || automaton.Contains(nil) {
+		t.Error("Expected Contains to report whether a pattern occurs")
+	}
+}
+
+func TestAutomatonNonASCII(t *testing.T) {
+	automaton := NewAutomaton([]string{"Kennwört"})
+	for _, text := range []string{"kennwört", "KENNWÖRT", "Kennwört", "KennWört"} {
+		if !automaton.Contains([]byte(text)) {
+			t.Errorf("Expected %q to match", text)
+		}
+	}
+	if automaton.Contains([]byte("kennwort")) {
+		t.Error("Expected other letters not to match")
+	}
+}
+
+func TestAutomatonNoPatterns(t *testing.T) {
+	for _, automaton := range []*Automaton{NewAutomaton(nil), NewAutomaton([]string{""})} {
+		if automaton.FindAll([]byte("text")) != nil || automaton.Matched([]byte("text")) != nil || automaton.Contains([]byte("text")) {
+			t.Error("Expected no matches without patterns")
+		}
+	}
+}
+
+// findEach finds each pattern on its own, as repeated searches of the lowercased text
+func findEach(patterns []string, text []byte) []Match {
+	var matches []Match
+	lower := bytes.ToLower(text)
+	for i, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		needle := []byte(strings.ToLower(pattern))
+		for offset := 0; ; {
+			index := bytes.Index(lower[offset:], needle)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			matches = append(matches, Match{Pattern: i, Start: start, End: start + len(needle)})
+			offset = start + len(needle)
+		}
+	}
+	return matches
+}
+
+func TestAutomatonMatchesSearches(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	letters := "abAB c"
+	word := func(n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteByte(letters[random.Intn(len(letters))])
+		}
+		return b.String()
+	}
+	for round := 0; round < 200; round++ {
+		patterns := []string{word(1 + random.Intn(3)), word(1 + random.Intn(4)), word(2 + random.Intn(4))}
+		text := []byte(word(200))
+
+		found := map[Match]bool{}
+		for _, match := range NewAutomaton(patterns).FindAll(text) {
+			found[match] = true
+		}
+		expected := map[Match]bool{}
+		for _, match := range findEach(patterns, text) {
+			expected[match] = true
+		}
+		if !reflect.DeepEqual(found, expected) {
+			t.Fatalf("Patterns %q in %q: expected %v, got %v", patterns, text, expected, found)
+		}
+	}
+}
+
+// builtinKeywords reads the keyword lists shipped with pc
+func builtinKeywords(b *testing.B) []string {
+	var keywords []string
+	for _, list := range []string{"credentials", "cloud-tokens", "private-keys"} {
+		content, err := os.ReadFile("../config/keywordlists/" + list + ".txt")
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				keywords = append(keywords, line)
+			}
+		}
+	}
+	return keywords
+}
+
+// Compares the automaton with the previous matcher, which searched the lowercased text once per
+// keyword
+func BenchmarkKeywordMatching(b *testing.B) {
+	keywords := builtinKeywords(b)
+	line := []byte("2024-01-01 12:00:00 INFO request handled in 12ms by worker 7\n")
+	for _, size := range []int{1024, 1024 * 1024} {
+		text := bytes.Repeat(line, size/len(line))
+		automaton := NewAutomaton(keywords)
+
+		b.Run(fmt.Sprintf("Automaton/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				automaton.Matched(text)
+			}
+		})
+		b.Run(fmt.Sprintf("Contains/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				lower := bytes.ToLower(text)
+				for _, keyword := range keywords {
+					bytes.Contains(lower, []byte(keyword))
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("AutomatonPositions/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				automaton.FindAll(text)
+			}
+		})
+		b.Run(fmt.Sprintf("SearchPositions/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				findEach(keywords, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package performance_test
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/collectors"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/utils"
+)
+
+// BenchmarkScan runs all checks of the example config on each corpus, at a tenth of the size
+// `pc bench` uses by default and, like it, with files of any size checked:
+//
+//	go test ./pkg/performance -bench Scan -run '^$'
+func BenchmarkScan(b *testing.B) {
+	cfg, err := config.LoadConfig("../../pc.toml.example")
+	if err != nil {
+		b.Fatal(err)
+	}
+	cfg.General.MaxContentScanFileSize = 1 << 40
+	output.GlobalLogger.SetJSONMode(true)
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	for _, corpus := range performance.Corpora {
+		dir := b.TempDir()
+		if err := corpus.Write(dir, 0.1); err != nil {
+			b.Fatal(err)
+		}
+		files, err := collectors.LocalCollector(dir, *cfg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var bytes int64
+		for _, file := range files {
+			bytes += file.Size
+		}
+
+		b.Run(corpus.Name, func(b *testing.B) {
+			b.SetBytes(bytes)
+			for i := 0; i < b.N; i++ {
+				output.GlobalLogger.ClearMessages()
+				utils.ApplyAllChecks(cfg.WithPackageRoot(dir), files, true)
+			}
+		})
+	}
+}
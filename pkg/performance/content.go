@@ -0,0 +1,131 @@
+package performance
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/eawag-rdm/pc/pkg/filetype"
+)
+
+// ErrMemoryBudget is returned when the content of a file does not fit into the memory budget
+var ErrMemoryBudget = errors.New("the memory budget of the scan is exhausted")
+
+// sampleSize is the number of bytes read from the start of a file to detect its type
+const sampleSize = filetype.SampleSize
+
+// FileContent reads a file once for all checks of the file: its info, the sample its type is
+// detected from and its whole content are kept until Close. The content is taken from the memory
+// budget of the scan, which bounds what all files hold together; checks stream files too large
+// to be held instead of calling Bytes.
+type FileContent struct {
+	path   string
+	memory *MemoryBudget
+
+	mutex     sync.Mutex
+	info      os.FileInfo
+	infoErr   error
+	statted   bool
+	sample    []byte
+	sampleErr error
+	sampled   bool
+	data      []byte
+	dataErr   error
+	read      bool
+	reserved  int64
+	closed    bool
+}
+
+// NewFileContent returns the content of the file at path, reserving its memory from budget
+func NewFileContent(path string, budget *MemoryBudget) *FileContent {
+	return &FileContent{path: path, memory: budget}
+}
+
+// Path returns the path of the file
+func (c *FileContent) Path() string {
+	return c.path
+}
+
+// Stat returns the info of the file
+func (c *FileContent) Stat() (os.FileInfo, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stat()
+}
+
+// stat is Stat with the mutex held
+func (c *FileContent) stat() (os.FileInfo, error) {
+	if !c.statted {
+		c.info, c.infoErr = os.Stat(c.path)
+		c.statted = true
+	}
+	return c.info, c.infoErr
+}
+
+// Sample returns up to the first 8KB of the file to detect its type from, empty for empty files
+func (c *FileContent) Sample() ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.sampled {
+		return c.sample, c.sampleErr
+	}
+	if c.read && c.dataErr == nil {
+		c.sample = c.data[:min(len(c.data), sampleSize)]
+	} else {
+		c.sample, c.sampleErr = readSample(c.path)
+	}
+	c.sampled = true
+	return c.sample, c.sampleErr
+}
+
+// readSample reads the start of the file at path
+func readSample(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	buffer := make([]byte, sampleSize)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buffer[:n], nil
+}
+
+// Bytes returns the whole content of the file, or ErrMemoryBudget if it does not fit into the
+// memory budget of the scan. After Close the content is read again but no longer kept.
+func (c *FileContent) Bytes() ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.read {
+		return c.data, c.dataErr
+	}
+	if c.closed {
+		return os.ReadFile(c.path)
+	}
+
+	info, err := c.stat()
+	if err != nil {
+		return nil, err
+	}
+	if !c.memory.Reserve(info.Size()) {
+		return nil, ErrMemoryBudget
+	}
+	c.reserved = info.Size()
+	c.data, c.dataErr = os.ReadFile(c.path)
+	c.read = true
+	return c.data, c.dataErr
+}
+
+// Close drops the content and gives its memory back to the budget of the scan
+func (c *FileContent) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.memory.Release(c.reserved)
+	c.reserved = 0
+	c.data, c.dataErr, c.read = nil, nil, false
+	c.sample, c.sampleErr, c.sampled = nil, nil, false
+	c.closed = true
+}
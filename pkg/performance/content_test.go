@@ -0,0 +1,86 @@
+package performance
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("password = secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	budget := NewMemoryBudget(100)
+	content := NewFileContent(path, budget)
+
+	data, err := content.Bytes()
+	if err != nil || string(data) != "password = secret\n" {
+		t.Fatalf("Expected the content, got %q (%v)", data, err)
+	}
+	if budget.Used() != 18 {
+		t.Errorf("Expected the content to take 18 bytes from the budget, got %d", budget.Used())
+	}
+
+	// The file is read once, later changes are not seen
+	if err := os.WriteFile(path, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := content.Bytes(); string(data) != "password = secret\n" {
+		t.Errorf("Expected the content read before, got %q", data)
+	}
+	if sample, _ := content.Sample(); string(sample) != "password = secret\n" {
+		t.Errorf("Expected the sample to be taken from the content, got %q", sample)
+	}
+	if info, err := content.Stat(); err != nil || info.Size() != 18 {
+		t.Errorf("Expected the info of the file read, got %v (%v)", info, err)
+	}
+
+	content.Close()
+	if budget.Used() != 0 {
+		t.Errorf("Expected Close to give the memory back, %d bytes are used", budget.Used())
+	}
+	if data, _ := content.Bytes(); string(data) != "changed" || budget.Used() != 0 {
+		t.Errorf("Expected the file to be read without being kept after Close, got %q using %d bytes", data, budget.Used())
+	}
+}
+
+func TestFileContentSample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(path, make([]byte, 3*sampleSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sample, err := NewFileContent(path, nil).Sample()
+	if err != nil || len(sample) != sampleSize {
+		t.Errorf("Expected a sample of %d bytes, got %d (%v)", sampleSize, len(sample), err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if sample, err := NewFileContent(empty, nil).Sample(); err != nil || len(sample) != 0 {
+		t.Errorf("Expected an empty sample, got %q (%v)", sample, err)
+	}
+
+	if _, err := NewFileContent(filepath.Join(t.TempDir(), "missing"), nil).Sample(); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestFileContentMemoryBudget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	budget := NewMemoryBudget(5)
+	content := NewFileContent(path, budget)
+	if _, err := content.Bytes(); !errors.Is(err, ErrMemoryBudget) {
+		t.Errorf("Expected the content not to fit into the budget, got %v", err)
+	}
+	content.Close()
+	if budget.Used() != 0 {
+		t.Errorf("Expected nothing to be taken from the budget, %d bytes are used", budget.Used())
+	}
+}
@@ -0,0 +1,183 @@
+package performance
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Corpus is a package generated for the benchmarks of `pc bench` and BenchmarkScan, representing
+// a kind of package the checks are slow on
+type Corpus struct {
+	Name        string
+	Description string
+	write       func(dir string, scale float64) error
+}
+
+// Corpora are the corpora of the benchmarks
+var Corpora = []Corpus{
+	{"small-files", "2000 small text and CSV files", writeSmallFiles},
+	{"huge-text", "one 256MiB log file", writeHugeText},
+	{"deep-archive", "a zip of 1000 files in folders 8 levels deep", writeDeepArchive},
+}
+
+// FindCorpus returns the corpus named name
+func FindCorpus(name string) (Corpus, bool) {
+	for _, corpus := range Corpora {
+		if corpus.Name == name {
+			return corpus, true
+		}
+	}
+	return Corpus{}, false
+}
+
+// Write generates the files of the corpus in dir. scale multiplies the number of files and the
+// size of the huge file, e.g. 0.01 for a quick run. The files are the same for every run.
+func (c Corpus) Write(dir string, scale float64) error {
+	if scale <= 0 {
+		return fmt.Errorf("invalid scale %v, expected a number greater than 0", scale)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return c.write(dir, scale)
+}
+
+// scaled returns n times scale, at least 1
+func scaled(n int, scale float64) int {
+	return max(1, int(float64(n)*scale))
+}
+
+// corpusLines are the lines the text of the corpora is made of, with a keyword now and then so
+// the keyword checks find something
+var corpusLines = []string{
+	"2024-01-01 12:00:00 INFO request handled in 12ms by worker 7\n",
+	"2024-01-01 12:00:01 DEBUG cache hit for station 42, temperature 12.5\n",
+	"2024-01-01 12:00:02 WARN retrying upload of sample_0815.csv\n",
+	"2024-01-01 12:00:03 INFO measured discharge 3.2 m3/s at gauge Rhine-07\n",
+	"2024-01-01 12:00:04 ERROR login failed for user admin with password hunter2\n",
+}
+
+// writeText writes about size bytes of log lines drawn from random to w
+func writeText(w io.Writer, size int64, random *rand.Rand) error {
+	buffered := bufio.NewWriter(w)
+	for written := int64(0); written < size; {
+		// Every 50th line on average holds the keywords
+		line := corpusLines[random.Intn(len(corpusLines)-1)]
+		if random.Intn(50) == 0 {
+			line = corpusLines[len(corpusLines)-1]
+		}
+		n, err := buffered.WriteString(line)
+		if err != nil {
+			return err
+		}
+		written += int64(n)
+	}
+	return buffered.Flush()
+}
+
+func writeSmallFiles(dir string, scale float64) error {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < scaled(2000, scale); i++ {
+		name := fmt.Sprintf("measurement_%04d.txt", i)
+		if i%2 == 1 {
+			name = fmt.Sprintf("station_%04d.csv", i)
+		}
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		err = writeText(file, 1024+random.Int63n(8*1024), random)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHugeText(dir string, scale float64) error {
+	file, err := os.Create(filepath.Join(dir, "instrument.log"))
+	if err != nil {
+		return err
+	}
+	err = writeText(file, int64(scaled(256*1024*1024, scale)), rand.New(rand.NewSource(2)))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeDeepArchive(dir string, scale float64) error {
+	file, err := os.Create(filepath.Join(dir, "results.zip"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	random := rand.New(rand.NewSource(3))
+	archive := zip.NewWriter(file)
+	for i := 0; i < scaled(1000, scale); i++ {
+		folder := ""
+		for level := 0; level <= i%8; level++ {
+			folder = path.Join(folder, fmt.Sprintf("level%d", level))
+		}
+		member, err := archive.Create(path.Join(folder, fmt.Sprintf("run_%04d.txt", i)))
+		if err != nil {
+			return err
+		}
+		if err := writeText(member, 512+random.Int63n(4*1024), random); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// BenchResult is the throughput of the checks on a corpus, the median of the runs of `pc bench`
+type BenchResult struct {
+	Corpus         string  `json:"corpus"`
+	Files          int     `json:"files"`
+	Bytes          int64   `json:"bytes"`
+	Seconds        float64 `json:"seconds"`
+	FilesPerSecond float64 `json:"files_per_second"`
+	MBPerSecond    float64 `json:"mb_per_second"`
+}
+
+// NewBenchResult returns the result of checking files of bytes bytes of a corpus in seconds
+func NewBenchResult(corpus string, files int, bytes int64, seconds float64) BenchResult {
+	result := BenchResult{Corpus: corpus, Files: files, Bytes: bytes, Seconds: seconds}
+	if seconds > 0 {
+		result.FilesPerSecond = float64(files) / seconds
+		result.MBPerSecond = float64(bytes) / (1 << 20) / seconds
+	}
+	return result
+}
+
+// Regressions compares results with the baseline results of an earlier run and describes each
+// corpus whose throughput dropped by more than maxDrop percent. Corpora missing from the baseline
+// or generated at another scale are not compared.
+func Regressions(baseline, results []BenchResult, maxDrop float64) []string {
+	var regressions []string
+	for _, result := range results {
+		for _, base := range baseline {
+			if base.Corpus != result.Corpus || base.Bytes != result.Bytes || base.MBPerSecond <= 0 {
+				continue
+			}
+			drop := (base.MBPerSecond - result.MBPerSecond) / base.MBPerSecond * 100
+			if drop > maxDrop {
+				regressions = append(regressions, fmt.Sprintf("%s: %.1f MB/s instead of %.1f MB/s (%.0f%% slower, at most %.0f%% allowed)",
+					result.Corpus, result.MBPerSecond, base.MBPerSecond, drop, maxDrop))
+			}
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,74 @@
+package performance
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCorpusWrite(t *testing.T) {
+	for _, corpus := range Corpora {
+		dir := filepath.Join(t.TempDir(), corpus.Name)
+		if err := corpus.Write(dir, 0.01); err != nil {
+			t.Fatalf("%s: %v", corpus.Name, err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) == 0 {
+			t.Errorf("%s: expected files, got %v (%v)", corpus.Name, entries, err)
+		}
+	}
+
+	small, _ := FindCorpus("small-files")
+	dir := t.TempDir()
+	small.Write(dir, 0.01)
+	if entries, _ := os.ReadDir(dir); len(entries) != 20 {
+		t.Errorf("Expected the number of files to be scaled, got %d", len(entries))
+	}
+	first, _ := os.ReadFile(filepath.Join(dir, "measurement_0000.txt"))
+	other := t.TempDir()
+	small.Write(other, 0.01)
+	if again, _ := os.ReadFile(filepath.Join(other, "measurement_0000.txt")); string(again) != string(first) {
+		t.Error("Expected the same files for every run")
+	}
+
+	deep, _ := FindCorpus("deep-archive")
+	dir = t.TempDir()
+	deep.Write(dir, 0.01)
+	archive, err := zip.OpenReader(filepath.Join(dir, "results.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	if len(archive.File) != 10 || strings.Count(archive.File[7].Name, "/") != 8 {
+		t.Errorf("Expected 10 members up to 8 folders deep, got %d members", len(archive.File))
+	}
+
+	if _, ok := FindCorpus("unknown"); ok {
+		t.Error("Expected an unknown corpus not to be found")
+	}
+	if err := small.Write(t.TempDir(), 0); err == nil {
+		t.Error("Expected an error for a scale of 0")
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	baseline := []BenchResult{
+		NewBenchResult("small-files", 100, 10<<20, 1),
+		NewBenchResult("huge-text", 1, 100<<20, 1),
+		NewBenchResult("deep-archive", 1, 5<<20, 1),
+	}
+	results := []BenchResult{
+		NewBenchResult("small-files", 100, 10<<20, 1.05),
+		NewBenchResult("huge-text", 1, 100<<20, 2),
+		NewBenchResult("deep-archive", 1, 1<<20, 10),
+	}
+	if baseline[1].MBPerSecond != 100 || baseline[0].FilesPerSecond != 100 {
+		t.Errorf("Unexpected throughput %+v", baseline[:2])
+	}
+	regressions := Regressions(baseline, results, 10)
+	if len(regressions) != 1 || !strings.HasPrefix(regressions[0], "huge-text: 50.0 MB/s instead of 100.0 MB/s (50% slower") {
+		t.Errorf("Expected only the huge file to regress, the archive was generated at another scale, got %q", regressions)
+	}
+}
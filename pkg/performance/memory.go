@@ -0,0 +1,86 @@
+package performance
+
+import "sync"
+
+// MemoryBudget limits the memory held by the checks of a scan at the same time: contents of
+// text and office files read at once and members unpacked from archives. A nil budget has no
+// limit, its methods may be called all the same.
+type MemoryBudget struct {
+	mutex sync.Mutex
+	limit int64
+	used  int64
+}
+
+// NewMemoryBudget returns a budget of limit bytes, nil for no limit if limit is not positive
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &MemoryBudget{limit: limit}
+}
+
+// Reserve takes size bytes from the budget if they fit, to be given back with Release
+func (b *MemoryBudget) Reserve(size int64) bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.used+size > b.limit {
+		return false
+	}
+	b.used += size
+	return true
+}
+
+// Fits reports whether size bytes are left in the budget without reserving them
+func (b *MemoryBudget) Fits(size int64) bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.used+size <= b.limit
+}
+
+// Use takes size bytes already in memory from the budget even if they do not fit, e.g. an
+// archive member unpacked while another check reserved the rest of the budget
+func (b *MemoryBudget) Use(size int64) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.used += size
+}
+
+// Release gives size bytes back to the budget
+func (b *MemoryBudget) Release(size int64) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.used -= size
+	if b.used < 0 {
+		b.used = 0
+	}
+}
+
+// Used returns the bytes currently taken from the budget
+func (b *MemoryBudget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.used
+}
+
+// Limit returns the size of the budget, 0 for no limit
+func (b *MemoryBudget) Limit() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.limit
+}
@@ -0,0 +1,59 @@
+package performance
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	budget := NewMemoryBudget(100)
+	if !budget.Reserve(60) || budget.Reserve(50) {
+		t.Error("Expected only the reservations fitting the budget to succeed")
+	}
+	if !budget.Fits(40) || budget.Fits(41) {
+		t.Error("Expected 40 bytes to be left")
+	}
+
+	budget.Use(50)
+	if budget.Used() != 110 || budget.Fits(0) {
+		t.Errorf("Expected memory already in use to overdraw the budget, got %d bytes used", budget.Used())
+	}
+	budget.Release(110)
+	if budget.Used() != 0 || !budget.Reserve(100) {
+		t.Errorf("Expected the released memory to be available again, got %d bytes used", budget.Used())
+	}
+}
+
+func TestMemoryBudgetUnlimited(t *testing.T) {
+	var budget *MemoryBudget
+	if NewMemoryBudget(0) != nil {
+		t.Error("Expected no budget for a limit of 0")
+	}
+	if !budget.Reserve(1<<40) || !budget.Fits(1<<40) || budget.Used() != 0 || budget.Limit() != 0 {
+		t.Error("Expected a nil budget to have no limit")
+	}
+	budget.Use(1)
+	budget.Release(1)
+}
+
+func TestMemoryBudgetConcurrent(t *testing.T) {
+	budget := NewMemoryBudget(1000)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if budget.Reserve(100) {
+				mutex.Lock()
+				reserved++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 10 || budget.Used() != 1000 {
+		t.Errorf("Expected 10 reservations of 100 bytes, got %d using %d bytes", reserved, budget.Used())
+	}
+}
@@ -0,0 +1,66 @@
+package performance
+
+import (
+	"regexp"
+	"sync"
+)
+
+// PatternCache keeps the regular expressions and keyword matchers compiled during a scan, so the
+// patterns of the config are compiled once per scan rather than once per file. Each scan has its
+// own cache, which is dropped with the scan; scans run at the same time, as in pc-server, do not
+// share one. A nil cache compiles every time, its methods may be called all the same.
+type PatternCache struct {
+	mutex   sync.Mutex
+	regexps map[string]*regexp.Regexp
+	values  map[string]interface{}
+}
+
+// NewPatternCache returns an empty cache
+func NewPatternCache() *PatternCache {
+	return &PatternCache{regexps: map[string]*regexp.Regexp{}, values: map[string]interface{}{}}
+}
+
+// Regexp returns the compiled pattern, nil if it is invalid
+func (c *PatternCache) Regexp(pattern string) *regexp.Regexp {
+	if c != nil {
+		c.mutex.Lock()
+		re, ok := c.regexps[pattern]
+		c.mutex.Unlock()
+		if ok {
+			return re
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	if c != nil {
+		c.mutex.Lock()
+		c.regexps[pattern] = re
+		c.mutex.Unlock()
+	}
+	return re
+}
+
+// Value returns the value cached for key, built with build the first time. Packages caching
+// their own compiled types, such as the keyword matchers, prefix their keys.
+func (c *PatternCache) Value(key string, build func() interface{}) interface{} {
+	if c == nil {
+		return build()
+	}
+	c.mutex.Lock()
+	value, ok := c.values[key]
+	c.mutex.Unlock()
+	if ok {
+		return value
+	}
+	// Two files may build the same value at once, either result is kept
+	value = build()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cached, ok := c.values[key]; ok {
+		return cached
+	}
+	c.values[key] = value
+	return value
+}
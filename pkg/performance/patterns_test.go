@@ -0,0 +1,68 @@
+package performance
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPatternCacheRegexp(t *testing.T) {
+	cache := NewPatternCache()
+	re := cache.Regexp(`\d+`)
+	if re == nil || !re.MatchString("42") {
+		t.Fatal("Expected the pattern to be compiled")
+	}
+	if cache.Regexp(`\d+`) != re {
+		t.Error("Expected the compiled pattern to be reused")
+	}
+	if cache.Regexp(`(`) != nil {
+		t.Error("Expected nil for an invalid pattern")
+	}
+}
+
+func TestPatternCacheValue(t *testing.T) {
+	cache := NewPatternCache()
+	builds := 0
+	build := func() interface{} {
+		builds++
+		return builds
+	}
+	if cache.Value("a", build) != 1 || cache.Value("a", build) != 1 || builds != 1 {
+		t.Errorf("Expected the value to be built once, built %d times", builds)
+	}
+	if cache.Value("b", build) != 2 {
+		t.Error("Expected another key to build its own value")
+	}
+}
+
+func TestPatternCacheNil(t *testing.T) {
+	var cache *PatternCache
+	if re := cache.Regexp(`a+`); re == nil || !re.MatchString("aa") {
+		t.Error("Expected a nil cache to compile the pattern")
+	}
+	builds := 0
+	cache.Value("a", func() interface{} { builds++; return nil })
+	cache.Value("a", func() interface{} { builds++; return nil })
+	if builds != 2 {
+		t.Errorf("Expected a nil cache to build every time, built %d times", builds)
+	}
+}
+
+func TestPatternCacheConcurrent(t *testing.T) {
+	cache := NewPatternCache()
+	var wg sync.WaitGroup
+	results := make([]interface{}, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache.Regexp(`[a-z]+`)
+			results[i] = cache.Value("key", func() interface{} { return new(int) })
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results {
+		if result != results[0] {
+			t.Fatal("Expected all goroutines to get the same cached value")
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// DefaultTimeout is used when a plugin does not configure its own timeout
+const DefaultTimeout = 60 * time.Second
+
+// FileInfo is the description of a file sent to a plugin
+type FileInfo struct {
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	Suffix      string `json:"suffix"`
+	IsArchive   bool   `json:"is_archive"`
+	ArchiveName string `json:"archive_name,omitempty"`
+}
+
+// Request is written as JSON to the plugin's stdin.
+// File is set for file scoped plugins, Files for repository scoped plugins.
+type Request struct {
+	Check     string                   `json:"check"`
+	Scope     string                   `json:"scope"`
+	File      *FileInfo                `json:"file,omitempty"`
+	Files     []FileInfo               `json:"files,omitempty"`
+	Arguments []map[string]interface{} `json:"arguments"`
+}
+
+// Response is read as JSON from the plugin's stdout
+type Response struct {
+	Messages []struct {
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+// ExternalCheck is a check implemented by an external executable
+type ExternalCheck struct {
+	Name    string
+	Command []string
+	Scope   string
+	Timeout time.Duration
+}
+
+// Load returns the plugins configured in the [plugin.*] sections, sorted by name
+func Load(cfg config.Config) []*ExternalCheck {
+	var checks []*ExternalCheck
+	for name, pc := range cfg.Plugins {
+		timeout := DefaultTimeout
+		if pc.Timeout > 0 {
+			timeout = pc.Timeout
+		}
+		checks = append(checks, &ExternalCheck{
+			Name:    name,
+			Command: pc.Command,
+			Scope:   pc.Scope,
+			Timeout: timeout,
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].Name < checks[j].Name
+	})
+	return checks
+}
+
+func toFileInfo(file structs.File) FileInfo {
+	return FileInfo{
+		Path:        file.Path,
+		Name:        file.Name,
+		Size:        file.Size,
+		Suffix:      file.Suffix,
+		IsArchive:   file.IsArchive,
+		ArchiveName: file.ArchiveName,
+	}
+}
+
+// arguments returns the keywordArguments of the [test.<name>] section, so plugins are configured like built-in checks
+func (c *ExternalCheck) arguments(cfg config.Config) []map[string]interface{} {
+	if test, ok := cfg.Tests[c.Name]; ok && test.KeywordArguments != nil {
+		return test.KeywordArguments
+	}
+	return []map[string]interface{}{}
+}
+
+// CheckFile runs a file scoped plugin on a single file
+func (c *ExternalCheck) CheckFile(file structs.File, cfg config.Config) ([]structs.Message, error) {
+	info := toFileInfo(file)
+	return c.run(Request{Check: c.Name, Scope: c.Scope, File: &info, Arguments: c.arguments(cfg)}, file)
+}
+
+// CheckRepository runs a repository scoped plugin on all files
+func (c *ExternalCheck) CheckRepository(repository structs.Repository, cfg config.Config) ([]structs.Message, error) {
+	files := make([]FileInfo, 0, len(repository.Files))
+	for _, file := range repository.Files {
+		files = append(files, toFileInfo(file))
+	}
+	return c.run(Request{Check: c.Name, Scope: c.Scope, Files: files, Arguments: c.arguments(cfg)}, repository)
+}
+
+// run executes the plugin and turns its response into messages attributed to source
+func (c *ExternalCheck) run(request Request, source structs.Source) ([]structs.Message, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin '%s' timed out after %s", c.Name, c.Timeout)
+		}
+		return nil, fmt.Errorf("plugin '%s' failed: %v %s", c.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("plugin '%s' returned invalid JSON: %v", c.Name, err)
+	}
+
+	var messages []structs.Message
+	for _, msg := range response.Messages {
+		if msg.Content == "" {
+			continue
+		}
+		messages = append(messages, structs.Message{Content: msg.Content, Source: source, TestName: c.Name})
+	}
+	return messages, nil
+}
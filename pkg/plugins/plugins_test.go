@@ -0,0 +1,123 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// shellPlugin stores the request it receives in requestFile and prints response
+func shellPlugin(requestFile, response string) []string {
+	return []string{"sh", "-c", `cat > "$0"; printf '%s' "$1"`, requestFile, response}
+}
+
+func TestLoad(t *testing.T) {
+	cfg := config.Config{Plugins: map[string]*config.PluginConfig{
+		"B": {Command: []string{"b"}, Scope: "file"},
+		"A": {Command: []string{"a"}, Scope: "repository", Timeout: 5 * time.Second},
+	}}
+
+	checks := Load(cfg)
+	if len(checks) != 2 || checks[0].Name != "A" || checks[1].Name != "B" {
+		t.Fatalf("unexpected plugins: %+v", checks)
+	}
+	if checks[0].Timeout != 5*time.Second || checks[1].Timeout != DefaultTimeout {
+		t.Errorf("unexpected timeouts: %s, %s", checks[0].Timeout, checks[1].Timeout)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	check := &ExternalCheck{
+		Name:    "NetCDFMetadata",
+		Command: shellPlugin(requestFile, `{"messages": [{"content": "missing units"}, {"content": ""}]}`),
+		Scope:   "file",
+		Timeout: DefaultTimeout,
+	}
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"NetCDFMetadata": {KeywordArguments: []map[string]interface{}{{"required": []string{"units"}}}},
+	}}
+	file := structs.File{Path: "/data/a.nc", Name: "a.nc", Size: 42, Suffix: ".nc"}
+
+	messages, err := check.CheckFile(file, cfg)
+	if err != nil {
+		t.Fatalf("CheckFile returned an error: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Content != "missing units" || messages[0].TestName != "NetCDFMetadata" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+	if source, ok := messages[0].Source.(structs.File); !ok || source.Name != "a.nc" {
+		t.Errorf("expected the file as source, got %+v", messages[0].Source)
+	}
+
+	data, err := os.ReadFile(requestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request Request
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatalf("plugin received invalid JSON: %v", err)
+	}
+	if request.Scope != "file" || request.File == nil || request.File.Path != "/data/a.nc" || request.File.Size != 42 {
+		t.Errorf("unexpected request: %s", data)
+	}
+	if len(request.Arguments) != 1 {
+		t.Errorf("expected the keywordArguments to be passed, got %s", data)
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	check := &ExternalCheck{
+		Name:    "Licence",
+		Command: shellPlugin(requestFile, `{"messages": [{"content": "no licence file"}]}`),
+		Scope:   "repository",
+		Timeout: DefaultTimeout,
+	}
+	repo := structs.Repository{Files: []structs.File{{Name: "a.txt"}, {Name: "b.txt"}}}
+
+	messages, err := check.CheckRepository(repo, config.Config{})
+	if err != nil {
+		t.Fatalf("CheckRepository returned an error: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	if _, ok := messages[0].Source.(structs.Repository); !ok {
+		t.Errorf("expected the repository as source, got %+v", messages[0].Source)
+	}
+
+	data, _ := os.ReadFile(requestFile)
+	var request Request
+	json.Unmarshal(data, &request)
+	if len(request.Files) != 2 || request.File != nil {
+		t.Errorf("unexpected request: %s", data)
+	}
+}
+
+func TestCheckFileErrors(t *testing.T) {
+	file := structs.File{Name: "a.txt"}
+	tests := []struct {
+		name    string
+		command []string
+		timeout time.Duration
+	}{
+		{"missing executable", []string{"/nonexistent/plugin"}, DefaultTimeout},
+		{"non-zero exit", []string{"sh", "-c", "echo broken >&2; exit 3"}, DefaultTimeout},
+		{"invalid JSON", []string{"sh", "-c", "echo not json"}, DefaultTimeout},
+		{"timeout", []string{"sleep", "5"}, 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &ExternalCheck{Name: "Broken", Command: tt.command, Scope: "file", Timeout: tt.timeout}
+			if _, err := check.CheckFile(file, config.Config{}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -3,19 +3,21 @@ package readers
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/bodgit/sevenzip"
+	"github.com/eawag-rdm/pc/pkg/filetype"
 	"github.com/eawag-rdm/pc/pkg/optimization"
 	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
 )
 
+// logger logs what the readers of archives and documents run into
+var logger = output.GlobalLogger.Component("readers")
+
 type UnpackedFileIterator struct {
 	ArchivePath string
 	ArchiveName string
@@ -39,9 +41,20 @@ type UnpackedFileIterator struct {
 	maxTotalMemory     int64
 	processedFileCount int
 
-	tarFile        *os.File
+	// Memory taken from the budget of the scan, given back once the archive is closed
+	memory          *performance.MemoryBudget
+	memoryExhausted bool
+	// Members left out because the archive reached maxTotalMemory
+	archiveMemoryExhausted bool
+	// Members left out because they are encrypted
+	encryptedMembers int
+	// Whitelist and blacklist matchers of the scan, see SetPatternCache
+	patterns *performance.PatternCache
+	// Members and archives not checked, see SetSkippedFiles
+	skipped *output.SkippedFiles
+
+	tarFile        io.Closer // The tar file and its decompressor
 	tarReader      *tar.Reader
-	gzipReader     *gzip.Reader
 	zipReader      *zip.ReadCloser
 	sevenZipReader *sevenzip.ReadCloser
 }
@@ -75,7 +88,6 @@ func InitArchiveIteratorWithMemoryLimit(archivePath string, archiveName string,
 
 		tarFile:        nil,
 		tarReader:      nil,
-		gzipReader:     nil,
 		zipReader:      nil,
 		sevenZipReader: nil,
 	}
@@ -85,124 +97,140 @@ func (u *UnpackedFileIterator) UnpackedFile() (string, []byte, int) {
 	return u.CurrentFilename, u.CurrentFileContent, u.CurrentFileSize
 }
 
+// MemberCount returns the number of entries of a zip or 7z archive once HasFilesToUnpack has
+// opened it. It is 0 for tar archives, which are read as a stream.
+func (u *UnpackedFileIterator) MemberCount() int {
+	if u.zipReader != nil {
+		return len(u.zipReader.File)
+	}
+	if u.sevenZipReader != nil {
+		return len(u.sevenZipReader.File)
+	}
+	return 0
+}
+
+// SetMemoryBudget shares the memory budget of the scan with the archive: members are only
+// unpacked while they fit, the memory of the unpacked members is given back once the archive
+// is closed. nil leaves the archive to its own maxTotalMemory.
+func (u *UnpackedFileIterator) SetMemoryBudget(budget *performance.MemoryBudget) {
+	u.memory = budget
+}
+
+// SetPatternCache matches the members against the whitelist and blacklist with the matchers of
+// the scan. nil builds them for every member.
+func (u *UnpackedFileIterator) SetPatternCache(cache *performance.PatternCache) {
+	u.patterns = cache
+}
+
+// SetSkippedFiles records the members and archives that are not checked in skipped, those of
+// the scan. nil records nothing.
+func (u *UnpackedFileIterator) SetSkippedFiles(skipped *output.SkippedFiles) {
+	u.skipped = skipped
+}
+
 // checkMemoryLimit verifies if processing another file would exceed memory limits
 func (u *UnpackedFileIterator) checkMemoryLimit(additionalBytes int64) bool {
-	return u.totalMemoryUsed+additionalBytes <= u.maxTotalMemory
+	if u.totalMemoryUsed+additionalBytes > u.maxTotalMemory {
+		u.archiveMemoryExhausted = true
+		return false
+	}
+	if !u.memory.Fits(additionalBytes) {
+		u.memoryExhausted = true
+		return false
+	}
+	return true
 }
 
 // updateMemoryUsage tracks memory usage and enforces limits
 func (u *UnpackedFileIterator) updateMemoryUsage(fileSize int) {
 	u.totalMemoryUsed += int64(fileSize)
+	u.memory.Use(int64(fileSize))
 	u.processedFileCount++
 	
 	// Log memory usage every 10 files
 	if u.processedFileCount%10 == 0 {
-		output.GlobalLogger.Info("Archive memory usage: %d/%d bytes (%d files processed)", 
+		logger.Debug("Archive memory usage: %d/%d bytes (%d files processed)", 
 			u.totalMemoryUsed, u.maxTotalMemory, u.processedFileCount)
 	}
 }
 
-func matchPatterns(list []string, str string) bool {
+func matchPatterns(cache *performance.PatternCache, list []string, str string) bool {
 	if len(list) == 0 || str == "" {
 		return true // Empty patterns match everything
 	}
 	
 	// Use fast matcher for pattern detection
-	matcher := optimization.GetMatcher(list)
+	matcher := optimization.GetMatcher(cache, list)
 	return matcher.HasAnyMatch([]byte(str))
 }
 
-func fileGoodToUnpack(whitelist []string, blacklist []string, filename string) bool {
-	if len(blacklist) > 0 {
-		return !matchPatterns(blacklist, filename)
-	}
-	if len(whitelist) > 0 {
-		return matchPatterns(whitelist, filename)
-	}
-	return true
+// skip records that the member of the archive was not checked and why
+func (u *UnpackedFileIterator) skip(member string, code output.SkipCode, format string, args ...interface{}) {
+	u.skipped.Add(output.SkippedFile{
+		Filename:    member,
+		Path:        u.ArchivePath,
+		ArchiveName: u.ArchiveName,
+		Code:        code,
+		Reason:      fmt.Sprintf(format, args...),
+	})
 }
 
+// skipArchive records that the archive itself was not (fully) checked and why
+func (u *UnpackedFileIterator) skipArchive(code output.SkipCode, format string, args ...interface{}) {
+	u.skipped.Add(output.SkippedFile{
+		Filename: u.ArchiveName,
+		Path:     u.ArchivePath,
+		Code:     code,
+		Reason:   fmt.Sprintf(format, args...),
+	})
+}
 
+// goodToUnpack applies the whitelist and blacklist to a member, recording blacklisted ones
+func (u *UnpackedFileIterator) goodToUnpack(member string) bool {
+	if len(u.Blacklist) > 0 && matchPatterns(u.patterns, u.Blacklist, member) {
+		u.skip(member, output.SkipBlacklisted, "Matched by the blacklist of IsFreeOfKeywords.")
+		return false
+	}
+	return fileGoodToUnpack(u.patterns, u.Whitelist, u.Blacklist, member)
+}
 
+// zipEncryptedFlag marks encrypted members in the general purpose flags of a zip entry
+const zipEncryptedFlag = 0x1
 
+// EncryptedArchiveReason is the reason password-protected archives are skipped with
+const EncryptedArchiveReason = "Password-protected archive cannot be checked."
 
-func (u *UnpackedFileIterator) findFirstTar() bool {
-	if u.tarReader == nil {
-		file, err := os.Open(u.ArchivePath)
-		if err != nil {
-			output.GlobalLogger.Warning("Error (archive content checks) opening tar file '%s' -> %v", u.ArchiveName, err)
-			u.iterationEnded = true
-			return false
-		}
-		u.tarFile = file
-		u.tarReader = tar.NewReader(file)
+// IsEncrypted reports whether err is a 7z read error caused by encryption, which is reported
+// instead of the error since the members cannot be read without the password
+func IsEncrypted(err error) bool {
+	var readErr *sevenzip.ReadError
+	return errors.As(err, &readErr) && readErr.Encrypted
+}
+
+func fileGoodToUnpack(cache *performance.PatternCache, whitelist []string, blacklist []string, filename string) bool {
+	if len(blacklist) > 0 {
+		return !matchPatterns(cache, blacklist, filename)
 	}
+	if len(whitelist) > 0 {
+		return matchPatterns(cache, whitelist, filename)
+	}
+	return true
+}
 
-	// Buffer the first valid file
-	for {
-		header, err := u.tarReader.Next()
-		if err != nil {
-			u.iterationEnded = true
-			return false
-		}
-		u.fileIndex++
 
-		isFile := !(header.Typeflag == tar.TypeDir)
-		isGreaterZero := header.Size > 0
-		isBelowMaxSize := header.Size <= int64(u.MaxSize)
 
-		// Check memory limits
-		if !u.checkMemoryLimit(header.Size) {
-			// Skip remaining bytes
-			_, _ = io.CopyN(io.Discard, u.tarReader, header.Size)
-			continue
-		}
 
-		var isGoodToUnpack bool
-		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, header.Name)
-		}
 
-		if isGoodToUnpack {
-			isText, content, err := u.isTarTextFileWithContent(header, u.tarReader)
-			if err != nil {
-				continue
-			}
-			if !isText {
-				continue
-			}
-
-			u.bufferedFilename = header.Name
-			u.bufferedFileContent = content
-			u.bufferedFileSize = len(content)
-			u.updateMemoryUsage(len(content))
-			return true
-		} else {
-			// Skip non-matching files
-			_, _ = io.CopyN(io.Discard, u.tarReader, header.Size)
-			continue
-		}
-	}
-}
-
-func (u *UnpackedFileIterator) findFirstTarGz() bool {
+func (u *UnpackedFileIterator) findFirstTar() bool {
 	if u.tarReader == nil {
-		file, err := os.Open(u.ArchivePath)
+		reader, closer, err := openTar(u.ArchivePath, u.ArchiveName)
 		if err != nil {
-			output.GlobalLogger.Warning("Error (archive content checks) opening tar.gz file '%s' -> %v", u.ArchiveName, err)
+			u.skipArchive(output.SkipReadError, "Error opening tar file: %v", err)
 			u.iterationEnded = true
 			return false
 		}
-		u.tarFile = file
-		
-		gzipReader, err := gzip.NewReader(file)
-		if err != nil {
-			output.GlobalLogger.Warning("Error (archive content checks) creating gzip reader for '%s' -> %v", u.ArchiveName, err)
-			u.iterationEnded = true
-			return false
-		}
-		u.gzipReader = gzipReader
-		u.tarReader = tar.NewReader(gzipReader)
+		u.tarFile = closer
+		u.tarReader = reader
 	}
 
 	// Buffer the first valid file
@@ -227,7 +255,9 @@ func (u *UnpackedFileIterator) findFirstTarGz() bool {
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, header.Name)
+			isGoodToUnpack = u.goodToUnpack(header.Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(header.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 
 		if isGoodToUnpack {
@@ -253,20 +283,23 @@ func (u *UnpackedFileIterator) findFirstTarGz() bool {
 }
 
 func (u *UnpackedFileIterator) isTarTextFileWithContent(header *tar.Header, reader io.Reader) (bool, []byte, error) {
-	const sampleSize = 512
-	buffer := make([]byte, sampleSize)
+	buffer := make([]byte, filetype.SampleSize)
 
-	n, err := reader.Read(buffer)
+	n, err := io.ReadFull(reader, buffer)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		u.skip(header.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, err
 	}
 
-	if n == 0 || !strings.HasPrefix(http.DetectContentType(buffer[:n]), "text/") {
+	if n == 0 || !filetype.IsText(header.Name, buffer[:n]) {
 		// Not a text file: skip remaining bytes
 		remaining := header.Size - int64(n)
 		if remaining > 0 {
 			_, _ = io.CopyN(io.Discard, reader, remaining)
 		}
+		if n > 0 {
+			u.skip(header.Name, output.SkipBinary, "The archive member seems to be binary.")
+		}
 		return false, nil, nil
 	}
 
@@ -274,6 +307,7 @@ func (u *UnpackedFileIterator) isTarTextFileWithContent(header *tar.Header, read
 	remaining := header.Size - int64(n)
 	rest, err := io.ReadAll(io.LimitReader(reader, remaining))
 	if err != nil {
+		u.skip(header.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, fmt.Errorf("error reading rest of text file: %w", err)
 	}
 
@@ -324,7 +358,9 @@ func unpackTar(u *UnpackedFileIterator) (bool, error) {
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, header.Name)
+			isGoodToUnpack = u.goodToUnpack(header.Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(header.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 
 		if isGoodToUnpack {
@@ -361,14 +397,24 @@ func (u *UnpackedFileIterator) is7zTextFileWithContent(index int) (bool, []byte,
 	f := u.sevenZipReader.File[index]
 
 	rc, err := f.Open()
+	if IsEncrypted(err) {
+		u.encryptedMembers++
+		return false, nil, nil
+	}
 	if err != nil {
+		u.skip(f.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, err
 	}
 	defer rc.Close()
 
 	// Read the entire file content once
 	content, err := io.ReadAll(rc)
+	if IsEncrypted(err) {
+		u.encryptedMembers++
+		return false, nil, nil
+	}
 	if err != nil {
+		u.skip(f.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, err
 	}
 
@@ -376,24 +422,25 @@ func (u *UnpackedFileIterator) is7zTextFileWithContent(index int) (bool, []byte,
 		return false, nil, nil
 	}
 
-	// Use the first 512 bytes for content type detection
-	sampleSize := 512
-	if len(content) < sampleSize {
-		sampleSize = len(content)
-	}
-
-	filetype := http.DetectContentType(content[:sampleSize])
-	isText := strings.HasPrefix(filetype, "text/") // Same logic as TAR and ZIP
+	isText := filetype.IsText(f.Name, content) // Same logic as TAR and ZIP
 
+	if !isText {
+		u.skip(f.Name, output.SkipBinary, "The archive member seems to be binary.")
+	}
 	return isText, content, nil
 }
 
 // Optimized ZIP file processing that eliminates double reading (same pattern as TAR)
 func (u *UnpackedFileIterator) isZippedTextWithContent(fileIndex int) (bool, []byte, error) {
 	file := u.zipReader.File[fileIndex]
+	if file.Flags&zipEncryptedFlag != 0 {
+		u.encryptedMembers++
+		return false, nil, nil
+	}
 
 	rc, err := file.Open()
 	if err != nil {
+		u.skip(file.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, err
 	}
 	defer rc.Close()
@@ -401,6 +448,7 @@ func (u *UnpackedFileIterator) isZippedTextWithContent(fileIndex int) (bool, []b
 	// Read the entire file content once
 	content, err := io.ReadAll(rc)
 	if err != nil {
+		u.skip(file.Name, output.SkipReadError, "Error reading archive member: %v", err)
 		return false, nil, err
 	}
 
@@ -408,15 +456,11 @@ func (u *UnpackedFileIterator) isZippedTextWithContent(fileIndex int) (bool, []b
 		return false, nil, nil
 	}
 
-	// Use the first 512 bytes for content type detection (same as original)
-	sampleSize := 512
-	if len(content) < sampleSize {
-		sampleSize = len(content)
-	}
-
-	filetype := http.DetectContentType(content[:sampleSize])
-	isText := strings.HasPrefix(filetype, "text/") // Same logic as TAR and 7Z
+	isText := filetype.IsText(file.Name, content) // Same logic as TAR and 7Z
 
+	if !isText {
+		u.skip(file.Name, output.SkipBinary, "The archive member seems to be binary.")
+	}
 	return isText, content, nil
 }
 
@@ -453,7 +497,9 @@ func unpackZip(u *UnpackedFileIterator) (bool, error) {
 
 			var isGoodToUnpack bool
 			if isFile && isGreaterZero && isBelowMaxSize {
-				isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, f.Name)
+				isGoodToUnpack = u.goodToUnpack(f.Name)
+			} else if isFile && !isBelowMaxSize {
+				u.skip(f.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 			}
 			
 			if isGoodToUnpack {
@@ -500,7 +546,9 @@ func unpackZip(u *UnpackedFileIterator) (bool, error) {
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, f.Name)
+			isGoodToUnpack = u.goodToUnpack(f.Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(f.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 		
 		if isGoodToUnpack {
@@ -530,8 +578,14 @@ func unpackZip(u *UnpackedFileIterator) (bool, error) {
 func (u *UnpackedFileIterator) findFirst7z() bool {
 	if u.sevenZipReader == nil {
 		reader, err := sevenzip.OpenReader(u.ArchivePath)
+		if IsEncrypted(err) {
+			// The names of the members are encrypted as well
+			u.skipArchive(output.SkipEncrypted, EncryptedArchiveReason)
+			u.iterationEnded = true
+			return false
+		}
 		if err != nil {
-			output.GlobalLogger.Warning("Error (archive content checks) opening 7z file '%s' -> %v", u.ArchiveName, err)
+			u.skipArchive(output.SkipReadError, "Error opening 7z file: %v", err)
 			u.iterationEnded = true
 			return false
 		}
@@ -554,13 +608,14 @@ func (u *UnpackedFileIterator) findFirst7z() bool {
 
 		// Check memory limits
 		if !u.checkMemoryLimit(int64(f.UncompressedSize)) {
-			output.GlobalLogger.Warning("Skipping file %s: would exceed memory limit", f.Name)
 			continue
 		}
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, files[i].Name)
+			isGoodToUnpack = u.goodToUnpack(files[i].Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(files[i].Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 		
 		if isGoodToUnpack {
@@ -616,7 +671,9 @@ func unpack7z(u *UnpackedFileIterator) (bool, error) {
 
 			var isGoodToUnpack bool
 			if isFile && isGreaterZero && isBelowMaxSize {
-				isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, f.Name)
+				isGoodToUnpack = u.goodToUnpack(f.Name)
+			} else if isFile && !isBelowMaxSize {
+				u.skip(f.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 			}
 			
 			if isGoodToUnpack {
@@ -663,7 +720,9 @@ func unpack7z(u *UnpackedFileIterator) (bool, error) {
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, f.Name)
+			isGoodToUnpack = u.goodToUnpack(f.Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(f.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 		
 		if isGoodToUnpack {
@@ -695,7 +754,7 @@ func (u *UnpackedFileIterator) findFirstZip() bool {
 	if u.zipReader == nil {
 		reader, err := zip.OpenReader(u.ArchivePath)
 		if err != nil {
-			output.GlobalLogger.Warning("Error (archive content checks) opening zip file '%s' -> %v", u.ArchiveName, err)
+			u.skipArchive(output.SkipReadError, "Error opening zip file: %v", err)
 			u.iterationEnded = true
 			return false
 		}
@@ -718,13 +777,14 @@ func (u *UnpackedFileIterator) findFirstZip() bool {
 
 		// Check memory limits
 		if !u.checkMemoryLimit(int64(f.UncompressedSize64)) {
-			output.GlobalLogger.Warning("Skipping file %s: would exceed memory limit", f.Name)
 			continue
 		}
 
 		var isGoodToUnpack bool
 		if isFile && isGreaterZero && isBelowMaxSize {
-			isGoodToUnpack = fileGoodToUnpack(u.Whitelist, u.Blacklist, f.Name)
+			isGoodToUnpack = u.goodToUnpack(f.Name)
+		} else if isFile && !isBelowMaxSize {
+			u.skip(f.Name, output.SkipTooLarge, "Larger than the maximum size of archive members (%d bytes).", u.MaxSize)
 		}
 		
 		if isGoodToUnpack {
@@ -749,11 +809,23 @@ func (u *UnpackedFileIterator) findFirstZip() bool {
 }
 
 func (u *UnpackedFileIterator) close() {
+	if u.encryptedMembers > 0 {
+		u.skipArchive(output.SkipEncrypted, "Password-protected archive cannot be checked, %d encrypted members were left out.", u.encryptedMembers)
+		u.encryptedMembers = 0
+	}
+	if u.archiveMemoryExhausted {
+		u.skipArchive(output.SkipMemoryLimit, "Not all members were checked, unpacking them would exceed maxTotalArchiveMemory (%d bytes).", u.maxTotalMemory)
+	}
+	if u.memory != nil {
+		u.memory.Release(u.totalMemoryUsed)
+		if u.memoryExhausted {
+			u.skipArchive(output.SkipMemoryLimit, "Not all members were checked, the memory budget of the scan (%d bytes) is exhausted.", u.memory.Limit())
+		}
+		u.memory = nil
+	}
 	if u.tarFile != nil {
 		u.tarFile.Close()
-	}
-	if u.gzipReader != nil {
-		u.gzipReader.Close()
+		u.tarFile = nil
 	}
 	if u.zipReader != nil {
 		u.zipReader.Close()
@@ -766,6 +838,15 @@ func (u *UnpackedFileIterator) close() {
 	}
 }
 
+// Close releases the archive and the unpacked contents before the iteration has ended, e.g.
+// when the scan is cancelled
+func (u *UnpackedFileIterator) Close() {
+	u.iterationEnded = true
+	u.CurrentFileContent = nil
+	u.bufferedFileContent = nil
+	u.close()
+}
+
 func (u *UnpackedFileIterator) HasNext() bool {
 	if u.iterationEnded {
 		u.close()
@@ -779,9 +860,9 @@ func (u *UnpackedFileIterator) HasFilesToUnpack() bool {
 		return !u.iterationEnded
 	}
 	u.hasCheckedFirstFile = true
-	// Handle .tar.gz separately since filepath.Ext only returns .gz
-	if strings.HasSuffix(u.ArchiveName, ".tar.gz") {
-		return u.findFirstTarGz()
+	// Compressed tar files are handled separately since filepath.Ext only returns .gz, .xz, ...
+	if IsCompressedTar(u.ArchiveName) {
+		return u.findFirstTar()
 	}
 	
 	switch filepath.Ext(u.ArchiveName) {
@@ -792,7 +873,7 @@ func (u *UnpackedFileIterator) HasFilesToUnpack() bool {
 	case ".7z":
 		return u.findFirst7z()
 	default:
-		output.GlobalLogger.Warning("Unsupported archive type '%s'", u.ArchiveName)
+		u.skipArchive(output.SkipUnsupportedArchive, "Unsupported archive type '%s'.", filepath.Ext(u.ArchiveName))
 		u.iterationEnded = true
 		u.close()
 		return false
@@ -807,9 +888,9 @@ func (u *UnpackedFileIterator) Next() bool {
 	var ok bool
 	var err error
 
-	// Handle .tar.gz separately since filepath.Ext only returns .gz
-	if strings.HasSuffix(u.ArchiveName, ".tar.gz") {
-		ok, err = unpackTar(u) // Reuse TAR unpacking logic for TAR.GZ
+	// Compressed tar files are handled separately since filepath.Ext only returns .gz, .xz, ...
+	if IsCompressedTar(u.ArchiveName) {
+		ok, err = unpackTar(u)
 	} else {
 		switch filepath.Ext(u.ArchiveName) {
 		case ".zip":
@@ -1,10 +1,14 @@
 package readers
 
 import (
+	"archive/zip"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +20,10 @@ func TestIterareUnpackedFiles(t *testing.T) {
 		{"Test with zip file", "../../testdata/archives/test.zip"},
 		{"Test with tar file", "../../testdata/archives/test.tar"},
 		{"Test with 7z file", "../../testdata/archives/test.7z"},
+		{"Test with tar.gz file", "../../testdata/archives/test.tar.gz"},
+		{"Test with tar.bz2 file", "../../testdata/archives/test.tar.bz2"},
+		{"Test with tar.xz file", "../../testdata/archives/test.tar.xz"},
+		{"Test with tar.zst file", "../../testdata/archives/test.tar.zst"},
 	}
 
 	for _, test := range tests {
@@ -301,3 +309,149 @@ func TestALotOfBinaryFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestMemberCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		filepath string
+		expected int
+	}{
+		{"Test with zip file", "../../testdata/archives/ten_valid_files.zip", 10},
+		{"Test with 7z file", "../../testdata/archives/ten_valid_files.7z", 10},
+		{"Test with tar file", "../../testdata/archives/ten_valid_files.tar", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts := strings.Split(test.filepath, "/")
+			nfi := InitArchiveIterator(test.filepath, parts[len(parts)-1], 1024*1024, []string{}, []string{})
+			assert.Equal(t, 0, nfi.MemberCount(), "The archive is opened by HasFilesToUnpack")
+			assert.True(t, nfi.HasFilesToUnpack(), "Expected archive to have valid files")
+			assert.Equal(t, test.expected, nfi.MemberCount())
+			for nfi.HasNext() {
+				nfi.Next()
+			}
+		})
+	}
+}
+
+func TestIteratorClose(t *testing.T) {
+	nfi := InitArchiveIterator("../../testdata/archives/ten_valid_files.zip", "ten_valid_files.zip", 1024*1024, []string{}, []string{})
+	assert.True(t, nfi.HasFilesToUnpack(), "Expected archive to have valid files")
+	nfi.Next()
+
+	nfi.Close()
+	assert.False(t, nfi.HasNext(), "A closed iterator has no more files")
+	_, content, _ := nfi.UnpackedFile()
+	assert.Nil(t, content, "The unpacked content is released")
+}
+
+func TestSkippedArchiveMembers(t *testing.T) {
+	for _, ext := range []string{".zip", ".7z", ".tar"} {
+		t.Run(ext, func(t *testing.T) {
+			path := "../../testdata/archives/one_of_each" + ext
+			nfi := InitArchiveIterator(path, "one_of_each"+ext, 2*1024*1024, []string{}, []string{".blst"})
+			skippedFiles := output.NewSkippedFiles()
+			nfi.SetSkippedFiles(skippedFiles)
+			assert.True(t, nfi.HasFilesToUnpack())
+			for nfi.HasNext() {
+				nfi.Next()
+			}
+			nfi.Close()
+
+			skipped := map[string]output.SkipCode{}
+			for _, file := range skippedFiles.Files() {
+				assert.Equal(t, path, file.Path)
+				assert.Equal(t, "one_of_each"+ext, file.ArchiveName)
+				skipped[file.Filename] = file.Code
+			}
+			assert.Equal(t, map[string]output.SkipCode{
+				"black/to_be_blacklisted.blst": output.SkipBlacklisted,
+				"random.bin":                   output.SkipBinary,
+				"too_large.txt":                output.SkipTooLarge,
+			}, skipped)
+		})
+	}
+}
+
+func TestArchiveMemberTypes(t *testing.T) {
+	// Members that http.DetectContentType took for binary are text, as for files on disk
+	path := filepath.Join(t.TempDir(), "members.zip")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	archive := zip.NewWriter(file)
+	members := map[string]string{
+		"old.csv":    "station;value\nRhine-07;12.5\n\x1a",
+		"data.csv":   "\xef\xbb\xbfstation;value\n",
+		"nested.zip": "PK\x03\x04\x14\x00\x00\x00\x08\x00",
+	}
+	for name, content := range members {
+		writer, err := archive.Create(name)
+		assert.NoError(t, err)
+		writer.Write([]byte(content))
+	}
+	assert.NoError(t, archive.Close())
+	file.Close()
+
+	nfi := InitArchiveIterator(path, "members.zip", 1024*1024, []string{}, []string{})
+	skippedFiles := output.NewSkippedFiles()
+	nfi.SetSkippedFiles(skippedFiles)
+	assert.True(t, nfi.HasFilesToUnpack())
+	var unpacked []string
+	for nfi.HasNext() {
+		nfi.Next()
+		name, _, _ := nfi.UnpackedFile()
+		unpacked = append(unpacked, name)
+	}
+	nfi.Close()
+	assert.ElementsMatch(t, []string{"old.csv", "data.csv"}, unpacked)
+	skipped := skippedFiles.Files()
+	if assert.Len(t, skipped, 1) {
+		assert.Equal(t, "nested.zip", skipped[0].Filename)
+		assert.Equal(t, output.SkipBinary, skipped[0].Code)
+	}
+}
+
+func TestUnsupportedArchive(t *testing.T) {
+	nfi := InitArchiveIterator("../../testdata/archives/data.rar", "data.rar", 1024, []string{}, []string{})
+	skippedFiles := output.NewSkippedFiles()
+	nfi.SetSkippedFiles(skippedFiles)
+	assert.False(t, nfi.HasFilesToUnpack())
+	skipped := skippedFiles.Files()
+	if assert.Len(t, skipped, 1) {
+		assert.Equal(t, output.SkipUnsupportedArchive, skipped[0].Code)
+		assert.Equal(t, "data.rar", skipped[0].Filename)
+	}
+}
+
+func TestEncryptedArchive(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason string
+	}{
+		{"encrypted.zip", "Password-protected archive cannot be checked, 2 encrypted members were left out."},
+		{"encrypted.7z", "Password-protected archive cannot be checked, 2 encrypted members were left out."},
+		{"encrypted_headers.7z", EncryptedArchiveReason},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output.GlobalLogger.ClearMessages()
+			defer output.GlobalLogger.ClearMessages()
+
+			nfi := InitArchiveIterator("../../testdata/archives/"+test.name, test.name, 1024*1024, []string{}, []string{})
+			skippedFiles := output.NewSkippedFiles()
+			nfi.SetSkippedFiles(skippedFiles)
+			assert.False(t, nfi.HasFilesToUnpack(), "Encrypted members cannot be unpacked")
+			nfi.Close()
+
+			skipped := skippedFiles.Files()
+			if assert.Len(t, skipped, 1) {
+				assert.Equal(t, output.SkipEncrypted, skipped[0].Code)
+				assert.Equal(t, test.name, skipped[0].Filename)
+				assert.Empty(t, skipped[0].ArchiveName)
+				assert.Equal(t, test.reason, skipped[0].Reason)
+			}
+			assert.Empty(t, output.GlobalLogger.GetMessages(), "Encrypted archives are not reported as errors")
+		})
+	}
+}
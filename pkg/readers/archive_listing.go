@@ -0,0 +1,86 @@
+package readers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+
+	"github.com/bodgit/sevenzip"
+)
+
+// ArchiveEntry is a member of an archive as listed by ListArchive
+type ArchiveEntry struct {
+	Name  string
+	Size  int64 // Uncompressed size in bytes
+	IsDir bool
+}
+
+// ListArchive lists the members of a supported archive from its headers, without unpacking
+// their contents. Other files have no members.
+func ListArchive(file structs.File) ([]ArchiveEntry, error) {
+	switch {
+	case strings.HasSuffix(file.Name, ".zip"):
+		return listZip(file.Path)
+	case strings.HasSuffix(file.Name, ".tar"), IsCompressedTar(file.Name):
+		return listTarFile(file.Path, file.Name)
+	case strings.HasSuffix(file.Name, ".7z"):
+		return list7Zip(file.Path)
+	}
+	return []ArchiveEntry{}, nil
+}
+
+func listZip(filePath string) ([]ArchiveEntry, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	entries := make([]ArchiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		info := file.FileInfo()
+		entries = append(entries, ArchiveEntry{Name: file.Name, Size: info.Size(), IsDir: info.IsDir()})
+	}
+	return entries, nil
+}
+
+// listTarFile lists a tar file, compressed if name is the name of a compressed tar file. The
+// compressed data has to be read to its end, but the members are skipped.
+func listTarFile(filePath string, name string) ([]ArchiveEntry, error) {
+	tarReader, closer, err := openTar(filePath, name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	entries := []ArchiveEntry{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, ArchiveEntry{Name: header.Name, Size: header.Size, IsDir: header.Typeflag == tar.TypeDir})
+	}
+	return entries, nil
+}
+
+func list7Zip(filePath string) ([]ArchiveEntry, error) {
+	reader, err := sevenzip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	entries := make([]ArchiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		info := file.FileInfo()
+		entries = append(entries, ArchiveEntry{Name: file.Name, Size: info.Size(), IsDir: info.IsDir()})
+	}
+	return entries, nil
+}
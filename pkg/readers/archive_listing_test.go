@@ -0,0 +1,36 @@
+package readers
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestListArchive(t *testing.T) {
+	expected := []ArchiveEntry{
+		{Name: "test/", Size: 0, IsDir: true},
+		{Name: "test/file1.txt", Size: 6},
+		{Name: "test/file2", Size: 0},
+	}
+	for _, name := range []string{"test.zip", "test.tar", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst", "test.7z"} {
+		entries, err := ListArchive(structs.File{Path: "../../testdata/archives/" + name, Name: name})
+		if err != nil {
+			t.Fatalf("ListArchive(%s): %v", name, err)
+		}
+		// 7z archives list the folder last
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		if !reflect.DeepEqual(entries, expected) {
+			t.Errorf("ListArchive(%s): expected %v, got %v", name, expected, entries)
+		}
+	}
+
+	entries, err := ListArchive(structs.File{Path: "../../testdata/readme.txt", Name: "readme.txt"})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no members of other files, got %v, %v", entries, err)
+	}
+	if _, err := ListArchive(structs.File{Path: "../../testdata/readme.txt", Name: "readme.zip"}); err == nil {
+		t.Error("Expected an error for a corrupt archive")
+	}
+}
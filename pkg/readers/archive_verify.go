@@ -0,0 +1,113 @@
+package readers
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+
+	"github.com/bodgit/sevenzip"
+)
+
+// VerifyArchive reads every member of a supported archive to its end, so the checksums of the
+// members are verified, and returns the first error: a corrupt or truncated archive. Members are
+// streamed, not kept in memory. Encrypted members cannot be verified without the password and
+// are left out. Other files and cancelled verifications return nil.
+func VerifyArchive(ctx context.Context, file structs.File) error {
+	switch {
+	case strings.HasSuffix(file.Name, ".zip"):
+		return verifyZip(ctx, file.Path)
+	case strings.HasSuffix(file.Name, ".tar"), IsCompressedTar(file.Name):
+		return verifyTar(ctx, file.Path, file.Name)
+	case strings.HasSuffix(file.Name, ".7z"):
+		return verify7Zip(ctx, file.Path)
+	}
+	return nil
+}
+
+func verifyZip(ctx context.Context, filePath string) error {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if file.FileInfo().IsDir() || file.Flags&zipEncryptedFlag != 0 {
+			continue
+		}
+		if err := discard(file.Open()); err != nil {
+			return fmt.Errorf("%s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// verifyTar reads a tar file, compressed if name is the name of a compressed tar file, to its end
+func verifyTar(ctx context.Context, filePath string, name string) error {
+	tarReader, closer, err := openTar(filePath, name)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, tarReader); err != nil {
+			return fmt.Errorf("%s: %w", header.Name, err)
+		}
+	}
+}
+
+func verify7Zip(ctx context.Context, filePath string) error {
+	reader, err := sevenzip.OpenReader(filePath)
+	if IsEncrypted(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		err := discard(file.Open())
+		if IsEncrypted(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// discard reads an opened archive member to its end and closes it
+func discard(member io.ReadCloser, err error) error {
+	if err != nil {
+		return err
+	}
+	defer member.Close()
+	_, err = io.Copy(io.Discard, member)
+	return err
+}
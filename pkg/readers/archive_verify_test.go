@@ -0,0 +1,64 @@
+package readers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestVerifyArchive(t *testing.T) {
+	for _, name := range []string{"ten_valid_files.zip", "ten_valid_files.tar", "ten_valid_files.7z", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst", "encrypted.zip", "encrypted.7z", "encrypted_headers.7z"} {
+		if err := VerifyArchive(context.Background(), structs.File{Path: "../../testdata/archives/" + name, Name: name}); err != nil {
+			t.Errorf("VerifyArchive(%s): expected an intact archive, got %v", name, err)
+		}
+	}
+	if err := VerifyArchive(context.Background(), structs.File{Path: "../../testdata/readme.txt", Name: "readme.txt"}); err != nil {
+		t.Errorf("Expected other files not to be verified, got %v", err)
+	}
+}
+
+func TestVerifyArchive_Truncated(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"ten_valid_files.zip", "one_of_each.tar", "ten_valid_files.7z", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst"} {
+		data, err := os.ReadFile("../../testdata/archives/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyArchive(context.Background(), structs.File{Path: path, Name: name}); err == nil {
+			t.Errorf("VerifyArchive(%s): expected an error for the truncated archive", name)
+		}
+	}
+}
+
+func TestVerifyArchive_CorruptMember(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	member, err := writer.CreateHeader(&zip.FileHeader{Name: "data/table.csv", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member.Write([]byte("lake,depth\nGreifensee,32\n"))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Replace(buffer.Bytes(), []byte("Greifensee"), []byte("Greifenseo"), 1)
+	path := filepath.Join(t.TempDir(), "corrupt.zip")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyArchive(context.Background(), structs.File{Path: path, Name: "corrupt.zip"})
+	if err == nil || !strings.HasPrefix(err.Error(), "data/table.csv: ") {
+		t.Errorf("Expected the corrupt member to be named in the error, got %v", err)
+	}
+}
@@ -1,12 +1,9 @@
 package readers
 
 import (
-	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
 	"io"
-	"os"
-	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/eawag-rdm/pc/pkg/structs"
@@ -32,7 +29,7 @@ func ReadZipFileListWithDisplayName(filePath string, archiveDisplayName string)
 
 	// Use archive filename if display name not provided
 	if archiveDisplayName == "" {
-		archiveDisplayName = path.Base(filePath)
+		archiveDisplayName = filepath.Base(filePath)
 	}
 
 	// Read the file list from the zip file
@@ -52,30 +49,7 @@ func ReadTarFileList(filePath string) ([]structs.File, error) {
 
 // ReadTarFileListWithDisplayName reads the file list with archive display name
 func ReadTarFileListWithDisplayName(filePath string, archiveDisplayName string) ([]structs.File, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	if archiveDisplayName == "" {
-		archiveDisplayName = path.Base(filePath)
-	}
-
-	tarReader := tar.NewReader(file)
-	var fileList []structs.File
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		f := structs.ToFileWithDisplay(filePath, header.Name, header.Name, header.Size, "", archiveDisplayName)
-		fileList = append(fileList, f)
-	}
-	return fileList, nil
+	return readTarFileList(filePath, "", archiveDisplayName)
 }
 
 // Read the filelist from a tar.gz file
@@ -85,23 +59,22 @@ func ReadTarGzFileList(filePath string) ([]structs.File, error) {
 
 // ReadTarGzFileListWithDisplayName reads the file list with archive display name
 func ReadTarGzFileListWithDisplayName(filePath string, archiveDisplayName string) ([]structs.File, error) {
-	file, err := os.Open(filePath)
+	return readTarFileList(filePath, ".tar.gz", archiveDisplayName)
+}
+
+// readTarFileList reads the file list of a tar file, decompressed if name is the name of a
+// compressed tar file
+func readTarFileList(filePath string, name string, archiveDisplayName string) ([]structs.File, error) {
+	tarReader, closer, err := openTar(filePath, name)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	defer closer.Close()
 
 	if archiveDisplayName == "" {
-		archiveDisplayName = path.Base(filePath)
-	}
-
-	gzipReader, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, err
+		archiveDisplayName = filepath.Base(filePath)
 	}
-	defer gzipReader.Close()
 
-	tarReader := tar.NewReader(gzipReader)
 	var fileList []structs.File
 	for {
 		header, err := tarReader.Next()
@@ -124,7 +97,7 @@ func Read7ZipFileList(filePath string) ([]structs.File, error) {
 // Read7ZipFileListWithDisplayName reads the file list with archive display name
 func Read7ZipFileListWithDisplayName(filePath string, archiveDisplayName string) ([]structs.File, error) {
 	if archiveDisplayName == "" {
-		archiveDisplayName = path.Base(filePath)
+		archiveDisplayName = filepath.Base(filePath)
 	}
 
 	var fileList []structs.File
@@ -149,7 +122,7 @@ func IsSupportedArchive(filePath string) bool {
 		return true
 	} else if strings.HasSuffix(filePath, ".7z") {
 		return true
-	} else if strings.HasSuffix(filePath, ".tar.gz") {
+	} else if IsCompressedTar(filePath) {
 		return true
 	}
 	return false
@@ -165,8 +138,8 @@ func ReadArchiveFileList(file structs.File) ([]structs.File, error) {
 		return ReadTarFileListWithDisplayName(file.Path, archiveDisplayName)
 	} else if strings.HasSuffix(file.Name, ".7z") {
 		return Read7ZipFileListWithDisplayName(file.Path, archiveDisplayName)
-	} else if strings.HasSuffix(file.Name, ".tar.gz") {
-		return ReadTarGzFileListWithDisplayName(file.Path, archiveDisplayName)
+	} else if IsCompressedTar(file.Name) {
+		return readTarFileList(file.Path, file.Name, archiveDisplayName)
 	} else {
 		return []structs.File{}, nil
 	}
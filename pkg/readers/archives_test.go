@@ -111,6 +111,30 @@ func TestReadArchiveFileList(t *testing.T) {
 				{Path: "../../testdata/archives/test.tar.gz", Name: "test/file1.txt", DisplayName: "test/file1.txt", Size: 6, Suffix: ".txt", ArchiveName: "test.tar.gz"},
 			},
 		},
+		{
+			file: structs.File{Path: "../../testdata/archives/test.tar.bz2", Name: "test.tar.bz2", DisplayName: "test.tar.bz2", Suffix: ".bz2"},
+			expected: []structs.File{
+				{Path: "../../testdata/archives/test.tar.bz2", Name: "test/", DisplayName: "test/", Size: 0, Suffix: "", ArchiveName: "test.tar.bz2"},
+				{Path: "../../testdata/archives/test.tar.bz2", Name: "test/file2", DisplayName: "test/file2", Size: 0, Suffix: "", ArchiveName: "test.tar.bz2"},
+				{Path: "../../testdata/archives/test.tar.bz2", Name: "test/file1.txt", DisplayName: "test/file1.txt", Size: 6, Suffix: ".txt", ArchiveName: "test.tar.bz2"},
+			},
+		},
+		{
+			file: structs.File{Path: "../../testdata/archives/test.tar.xz", Name: "test.tar.xz", DisplayName: "test.tar.xz", Suffix: ".xz"},
+			expected: []structs.File{
+				{Path: "../../testdata/archives/test.tar.xz", Name: "test/", DisplayName: "test/", Size: 0, Suffix: "", ArchiveName: "test.tar.xz"},
+				{Path: "../../testdata/archives/test.tar.xz", Name: "test/file2", DisplayName: "test/file2", Size: 0, Suffix: "", ArchiveName: "test.tar.xz"},
+				{Path: "../../testdata/archives/test.tar.xz", Name: "test/file1.txt", DisplayName: "test/file1.txt", Size: 6, Suffix: ".txt", ArchiveName: "test.tar.xz"},
+			},
+		},
+		{
+			file: structs.File{Path: "../../testdata/archives/test.tar.zst", Name: "test.tar.zst", DisplayName: "test.tar.zst", Suffix: ".zst"},
+			expected: []structs.File{
+				{Path: "../../testdata/archives/test.tar.zst", Name: "test/", DisplayName: "test/", Size: 0, Suffix: "", ArchiveName: "test.tar.zst"},
+				{Path: "../../testdata/archives/test.tar.zst", Name: "test/file2", DisplayName: "test/file2", Size: 0, Suffix: "", ArchiveName: "test.tar.zst"},
+				{Path: "../../testdata/archives/test.tar.zst", Name: "test/file1.txt", DisplayName: "test/file1.txt", Size: 6, Suffix: ".txt", ArchiveName: "test.tar.zst"},
+			},
+		},
 		{
 			file:     structs.File{Path: "../../testdata/config.toml.test", Name: "config.toml.test", DisplayName: "config.toml.test", Suffix: ".test"},
 			expected: []structs.File{},
@@ -0,0 +1,58 @@
+package readers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// ColumnarSchema is what ReadColumnarSchema learns from the footer of a Parquet or Feather file.
+// The data itself is not read.
+type ColumnarSchema struct {
+	Format   string          // "Parquet" or "Feather"
+	Columns  []string        // Names of the columns, those of nested columns joined with dots
+	Metadata []MetadataEntry // Key-value metadata of the file and its columns
+}
+
+// MetadataEntry is an entry of the key-value metadata of a columnar file
+type MetadataEntry struct {
+	Key   string
+	Value string
+}
+
+// ErrNotColumnar is returned by ReadColumnarSchema for files that are neither Parquet nor Feather
+var ErrNotColumnar = errors.New("neither a Parquet nor a Feather file")
+
+// maxFooterSize limits the footer read from a columnar file, larger sizes come from corrupt files
+const maxFooterSize = 1 << 26
+
+// ReadColumnarSchema reads the schema and key-value metadata of a Parquet or Feather (Arrow IPC)
+// file by its suffix. Errors other than ErrNotColumnar and those opening the file describe how
+// the file is corrupt or why it cannot be read.
+func ReadColumnarSchema(file structs.File) (ColumnarSchema, error) {
+	switch {
+	case strings.HasSuffix(file.Name, ".parquet"):
+		return ReadParquetSchema(file)
+	case strings.HasSuffix(file.Name, ".feather"):
+		return ReadFeatherSchema(file)
+	}
+	return ColumnarSchema{}, ErrNotColumnar
+}
+
+// Text returns the column names and metadata to search, one per line
+func (s ColumnarSchema) Text() []byte {
+	var text strings.Builder
+	for _, column := range s.Columns {
+		text.WriteString("column ")
+		text.WriteString(column)
+		text.WriteByte('\n')
+	}
+	for _, entry := range s.Metadata {
+		text.WriteString(entry.Key)
+		text.WriteString(": ")
+		text.WriteString(entry.Value)
+		text.WriteByte('\n')
+	}
+	return []byte(text.String())
+}
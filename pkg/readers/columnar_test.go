@@ -0,0 +1,27 @@
+package readers
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadColumnarSchema(t *testing.T) {
+	for _, name := range []string{"test.parquet", "test.feather"} {
+		schema, err := ReadColumnarSchema(structs.File{Path: "../../testdata/" + name, Name: name})
+		if assert.NoError(t, err, name) {
+			assert.Contains(t, string(schema.Text()), "column patient_name\n", name)
+		}
+	}
+	_, err := ReadColumnarSchema(structs.File{Path: "../../testdata/test.docx", Name: "test.docx"})
+	assert.ErrorIs(t, err, ErrNotColumnar)
+}
+
+func TestColumnarSchemaText(t *testing.T) {
+	schema := ColumnarSchema{
+		Columns:  []string{"sample_id", "location.lat"},
+		Metadata: []MetadataEntry{{Key: "source", Value: `Q:\lake\raw.csv`}},
+	}
+	assert.Equal(t, "column sample_id\ncolumn location.lat\nsource: Q:\\lake\\raw.csv\n", string(schema.Text()))
+}
@@ -0,0 +1,87 @@
+package readers
+
+import (
+	"archive/tar"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// decompressors open the compressed tar files by the suffix of their name. Tarballs of
+// sequencing data and other bioinformatics submissions often come as .tar.bz2, .tar.xz or .tar.zst.
+var decompressors = map[string]func(r io.Reader) (io.ReadCloser, error){
+	".tar.gz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	".tar.bz2": func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	},
+	".tar.xz": func(r io.Reader) (io.ReadCloser, error) {
+		reader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(reader), nil
+	},
+	".tar.zst": func(r io.Reader) (io.ReadCloser, error) {
+		// Members are read one after the other, more goroutines would only hold more memory
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	},
+}
+
+// compressedTarSuffix returns the suffix of a compressed tar file name, "" for other names
+func compressedTarSuffix(name string) string {
+	for suffix := range decompressors {
+		if strings.HasSuffix(name, suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// IsCompressedTar reports whether name is the name of a tar file compressed with gzip, bzip2,
+// xz or zstd
+func IsCompressedTar(name string) bool {
+	return compressedTarSuffix(name) != ""
+}
+
+// tarFile closes a tar file and its decompressor
+type tarFile struct {
+	file         *os.File
+	decompressor io.Closer
+}
+
+func (t *tarFile) Close() error {
+	if t.decompressor != nil {
+		t.decompressor.Close()
+	}
+	return t.file.Close()
+}
+
+// openTar opens the tar file at filePath, decompressed if name is the name of a compressed tar
+// file. The returned closer closes the decompressor and the file.
+func openTar(filePath string, name string) (*tar.Reader, io.Closer, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	decompress, ok := decompressors[compressedTarSuffix(name)]
+	if !ok {
+		return tar.NewReader(file), &tarFile{file: file}, nil
+	}
+	decompressor, err := decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return tar.NewReader(decompressor), &tarFile{file: file, decompressor: decompressor}, nil
+}
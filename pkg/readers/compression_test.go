@@ -0,0 +1,33 @@
+package readers
+
+import "testing"
+
+func TestIsCompressedTar(t *testing.T) {
+	tests := map[string]bool{
+		"reads.tar.gz":   true,
+		"reads.tar.bz2":  true,
+		"reads.tar.xz":   true,
+		"reads.tar.zst":  true,
+		"reads.tar":      false,
+		"reads.fastq.gz": false,
+		"reads.xz":       false,
+	}
+	for name, expected := range tests {
+		if got := IsCompressedTar(name); got != expected {
+			t.Errorf("IsCompressedTar(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
+
+func TestOpenTar_Corrupt(t *testing.T) {
+	for _, name := range []string{"readme.tar.gz", "readme.tar.bz2", "readme.tar.xz", "readme.tar.zst"} {
+		reader, closer, err := openTar("../../testdata/readme.txt", name)
+		if err == nil {
+			_, err = reader.Next()
+			closer.Close()
+		}
+		if err == nil {
+			t.Errorf("openTar(%s): expected an error for a file that is not compressed", name)
+		}
+	}
+}
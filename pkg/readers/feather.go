@@ -0,0 +1,208 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+var (
+	arrowMagic     = []byte("ARROW1")
+	featherV1Magic = []byte("FEA1")
+)
+
+// Slots of the fields read from the flatbuffers of the footer of an Arrow IPC file
+const (
+	footerSchema         = 1
+	footerCustomMetadata = 4
+	schemaFields         = 1
+	schemaCustomMetadata = 2
+	fieldName            = 0
+	fieldChildren        = 5
+	fieldCustomMetadata  = 6
+	keyValueKey          = 0
+	keyValueValue        = 1
+)
+
+// maxFieldDepth limits the nesting of the columns read from a schema
+const maxFieldDepth = 64
+
+// ReadFeatherSchema reads the column names and custom metadata from the footer of a Feather
+// version 2 file, which is an Arrow IPC file. The metadata of a column is reported with the
+// name of the column in front of its keys. Feather version 1 files cannot be read.
+func ReadFeatherSchema(file structs.File) (ColumnarSchema, error) {
+	footer, err := readFooter(file.Path, arrowMagic, nil)
+	if errors.Is(err, ErrNotColumnar) && isFeatherV1(file.Path) {
+		return ColumnarSchema{}, errors.New("files of Feather version 1 are not supported")
+	}
+	if err != nil {
+		return ColumnarSchema{}, err
+	}
+
+	r := &flatReader{buf: footer}
+	root := r.root()
+	schema := ColumnarSchema{Format: "Feather"}
+	if s, ok := r.table(root, footerSchema); ok {
+		r.readFields(&schema, s, "", 0)
+		schema.Metadata = r.appendMetadata(schema.Metadata, s, schemaCustomMetadata, "")
+	}
+	schema.Metadata = r.appendMetadata(schema.Metadata, root, footerCustomMetadata, "")
+	if r.err != nil {
+		return ColumnarSchema{}, fmt.Errorf("the footer is corrupt: %w", r.err)
+	}
+	return schema, nil
+}
+
+func isFeatherV1(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	magic := make([]byte, len(featherV1Magic))
+	_, err = f.ReadAt(magic, 0)
+	return err == nil && bytes.Equal(magic, featherV1Magic)
+}
+
+// readFields appends the names and metadata of the fields of a schema or field, and of their
+// children, to schema
+func (r *flatReader) readFields(schema *ColumnarSchema, parent int, prefix string, depth int) {
+	slot := schemaFields
+	if depth > 0 {
+		slot = fieldChildren
+	}
+	if depth > maxFieldDepth {
+		r.fail(errors.New("nested too deeply"))
+		return
+	}
+	if len(schema.Columns) > maxHeaderItems {
+		r.fail(fmt.Errorf("implausible number of %d columns", len(schema.Columns)))
+		return
+	}
+	for _, field := range r.tables(parent, slot) {
+		name := prefix + r.string(field, fieldName)
+		schema.Columns = append(schema.Columns, name)
+		schema.Metadata = r.appendMetadata(schema.Metadata, field, fieldCustomMetadata, name+".")
+		r.readFields(schema, field, name+".", depth+1)
+	}
+}
+
+// appendMetadata appends the KeyValue tables in slot of table to metadata, prefix in front of the keys
+func (r *flatReader) appendMetadata(metadata []MetadataEntry, table int, slot int, prefix string) []MetadataEntry {
+	for _, kv := range r.tables(table, slot) {
+		metadata = append(metadata, MetadataEntry{Key: prefix + r.string(kv, keyValueKey), Value: r.string(kv, keyValueValue)})
+	}
+	return metadata
+}
+
+// flatReader reads tables, strings and vectors of tables from a flatbuffer, keeping the first
+// error. Tables are passed around by their position in the buffer.
+type flatReader struct {
+	buf []byte
+	err error
+}
+
+func (r *flatReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *flatReader) uint32(pos int) int {
+	if r.err != nil {
+		return 0
+	}
+	if pos < 0 || pos+4 > len(r.buf) {
+		r.fail(fmt.Errorf("offset %d out of range", pos))
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(r.buf[pos:]))
+}
+
+func (r *flatReader) uint16(pos int) int {
+	if r.err != nil {
+		return 0
+	}
+	if pos < 0 || pos+2 > len(r.buf) {
+		r.fail(fmt.Errorf("offset %d out of range", pos))
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(r.buf[pos:]))
+}
+
+// root returns the position of the root table
+func (r *flatReader) root() int {
+	return r.uint32(0)
+}
+
+// field returns the position of the field in slot of table, 0 if the field is not set
+func (r *flatReader) field(table int, slot int) int {
+	vtable := table - int(int32(r.uint32(table)))
+	size := r.uint16(vtable)
+	entry := 4 + 2*slot
+	if r.err != nil || entry+2 > size {
+		return 0
+	}
+	if offset := r.uint16(vtable + entry); offset != 0 {
+		return table + offset
+	}
+	return 0
+}
+
+// indirect follows the offset stored at pos
+func (r *flatReader) indirect(pos int) int {
+	return pos + r.uint32(pos)
+}
+
+// table returns the position of the table in slot of table
+func (r *flatReader) table(table int, slot int) (int, bool) {
+	pos := r.field(table, slot)
+	if pos == 0 {
+		return 0, false
+	}
+	return r.indirect(pos), r.err == nil
+}
+
+// string returns the string in slot of table, "" if it is not set
+func (r *flatReader) string(table int, slot int) string {
+	pos := r.field(table, slot)
+	if pos == 0 {
+		return ""
+	}
+	start := r.indirect(pos)
+	n := r.uint32(start)
+	if r.err != nil {
+		return ""
+	}
+	if n > len(r.buf)-start-4 {
+		r.fail(fmt.Errorf("string of implausible length %d", n))
+		return ""
+	}
+	return string(r.buf[start+4 : start+4+n])
+}
+
+// tables returns the positions of the tables of the vector in slot of table
+func (r *flatReader) tables(table int, slot int) []int {
+	pos := r.field(table, slot)
+	if pos == 0 {
+		return nil
+	}
+	start := r.indirect(pos)
+	n := r.uint32(start)
+	if r.err != nil {
+		return nil
+	}
+	if n > (len(r.buf)-start-4)/4 {
+		r.fail(fmt.Errorf("vector of implausible length %d", n))
+		return nil
+	}
+	positions := make([]int, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		positions = append(positions, r.indirect(start+4+4*i))
+	}
+	return positions
+}
@@ -0,0 +1,46 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFeatherSchema(t *testing.T) {
+	schema, err := ReadFeatherSchema(structs.File{Path: "../../testdata/test.feather", Name: "test.feather", Suffix: ".feather"})
+	if err != nil {
+		t.Fatalf("Failed to read Feather file: %v", err)
+	}
+	assert.Equal(t, "Feather", schema.Format)
+	assert.Equal(t, []string{"sample_id", "patient_name", "location", "location.lat", "location.lon"}, schema.Columns)
+	assert.Equal(t, []MetadataEntry{
+		{Key: "patient_name.description", Value: "Full name of the participant"},
+		{Key: "pandas", Value: `{"index_columns": [], "creator": "jdoe"}`},
+	}, schema.Metadata)
+}
+
+func TestReadFeatherSchema_Unreadable(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/test.feather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"truncated.feather": data[:len(data)-20],
+		"v1.feather":        append([]byte("FEA1"), data[4:len(data)-6]...),
+		// Offsets point out of the footer
+		"corrupt.feather": append(append([]byte("ARROW1\x00\x00\xff\xff\xff\xff\x00\x00\x00\x00"), 0xf0, 0xff, 0, 0, 4, 0, 0, 0), "ARROW1"...),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ReadFeatherSchema(structs.File{Path: path, Name: name})
+		assert.Error(t, err, name)
+		assert.NotErrorIs(t, err, ErrNotColumnar, name)
+	}
+}
@@ -0,0 +1,192 @@
+package readers
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// maxGeoJSONDepth limits the nesting of objects and arrays read from a GeoJSON file
+const maxGeoJSONDepth = 256
+
+// ReadGeoJSONPositions streams the positions of the geometries of a GeoJSON file to position,
+// longitude (or easting) first, and returns the name of the coordinate reference system the file
+// declares in its crs member, "" if it declares none. The crs member was dropped by RFC 7946,
+// which allows only WGS 84 longitude and latitude, but older files use it for projected systems.
+func ReadGeoJSONPositions(file structs.File, position func(x, y float64)) (string, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	g := &geoJSONReader{decoder: json.NewDecoder(f), position: position}
+	if err := g.value(0, false); err != nil {
+		return "", err
+	}
+	if _, err := g.decoder.Token(); err != io.EOF {
+		return "", errors.New("unexpected data after the GeoJSON object")
+	}
+	return g.crs, nil
+}
+
+type geoJSONReader struct {
+	decoder  *json.Decoder
+	position func(x, y float64)
+	crs      string
+}
+
+// value reads the next value, coordinates tells whether it belongs to a coordinates member
+func (g *geoJSONReader) value(depth int, coordinates bool) error {
+	if depth > maxGeoJSONDepth {
+		return errors.New("the GeoJSON is nested too deeply")
+	}
+	token, err := g.decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		for g.decoder.More() {
+			key, err := g.decoder.Token()
+			if err != nil {
+				return err
+			}
+			if key == "crs" && depth == 0 {
+				if err := g.readCRS(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := g.value(depth+1, key == "coordinates"); err != nil {
+				return err
+			}
+		}
+		_, err = g.decoder.Token()
+		return err
+	case json.Delim('['):
+		if !coordinates {
+			for g.decoder.More() {
+				if err := g.value(depth+1, false); err != nil {
+					return err
+				}
+			}
+			_, err = g.decoder.Token()
+			return err
+		}
+		return g.coordinates(depth)
+	}
+	return nil
+}
+
+// errCoordinates is returned for coordinates other than positions and arrays of them
+var errCoordinates = errors.New("coordinates must be arrays of numbers")
+
+// coordinates reads the rest of an array of coordinates: a position or an array of them
+func (g *geoJSONReader) coordinates(depth int) error {
+	var numbers []float64
+	nested := false
+	for g.decoder.More() {
+		token, err := g.decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case float64:
+			if nested {
+				return errCoordinates
+			}
+			numbers = append(numbers, t)
+		case json.Delim:
+			if t != '[' || len(numbers) > 0 {
+				return errCoordinates
+			}
+			if depth >= maxGeoJSONDepth {
+				return errors.New("the GeoJSON is nested too deeply")
+			}
+			nested = true
+			if err := g.coordinates(depth + 1); err != nil {
+				return err
+			}
+		default:
+			return errCoordinates
+		}
+	}
+	if _, err := g.decoder.Token(); err != nil {
+		return err
+	}
+	if len(numbers) == 1 {
+		return errors.New("a position has a single number")
+	}
+	if len(numbers) >= 2 {
+		g.position(numbers[0], numbers[1])
+	}
+	return nil
+}
+
+// readCRS reads the name of a named crs member, linked ones are not followed
+func (g *geoJSONReader) readCRS() error {
+	var crs struct {
+		Properties struct {
+			Name string `json:"name"`
+		} `json:"properties"`
+	}
+	if err := g.decoder.Decode(&crs); err != nil {
+		return err
+	}
+	g.crs = crs.Properties.Name
+	if g.crs == "" {
+		g.crs = "unnamed"
+	}
+	return nil
+}
+
+// ShapefileHeader is what ReadShapefileHeader learns from the main file (.shp) of a shapefile
+type ShapefileHeader struct {
+	ShapeType              int32 // 0 for a shapefile without shapes
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// ErrNotShapefile is returned by ReadShapefileHeader for files that are no shapefiles
+var ErrNotShapefile = errors.New("not a shapefile")
+
+// shapefileCode starts the header of a shapefile
+const shapefileCode = 9994
+
+// ReadShapefileHeader reads the shape type and the bounding box of the shapes of a shapefile
+func ReadShapefileHeader(file structs.File) (ShapefileHeader, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return ShapefileHeader{}, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 100)
+	n, err := io.ReadFull(f, header)
+	if n < 4 || binary.BigEndian.Uint32(header) != shapefileCode {
+		return ShapefileHeader{}, ErrNotShapefile
+	}
+	if err != nil {
+		return ShapefileHeader{}, errors.New("the header is truncated")
+	}
+	if version := binary.LittleEndian.Uint32(header[28:]); version != 1000 {
+		return ShapefileHeader{}, fmt.Errorf("the shapefile version %d is unknown", version)
+	}
+
+	double := func(offset int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(header[offset:]))
+	}
+	return ShapefileHeader{
+		ShapeType: int32(binary.LittleEndian.Uint32(header[32:])),
+		MinX:      double(36),
+		MinY:      double(44),
+		MaxX:      double(52),
+		MaxY:      double(60),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package readers
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeGeoFile(t *testing.T, name string, content []byte) structs.File {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name}
+}
+
+func TestReadGeoJSONPositions(t *testing.T) {
+	file := writeGeoFile(t, "stations.geojson", []byte(`{
+		"type": "FeatureCollection",
+		"bbox": [8.5, 47.3, 8.6, 47.4],
+		"features": [
+			{"type": "Feature", "properties": {"name": "Zürich", "coordinates": "not a geometry"}, "geometry": {"type": "Point", "coordinates": [8.5417, 47.3769, 408]}},
+			{"type": "Feature", "properties": null, "geometry": {"type": "LineString", "coordinates": [[8.5, 47.3], [8.6, 47.4]]}},
+			{"type": "Feature", "properties": {}, "geometry": {"type": "GeometryCollection", "geometries": [
+				{"type": "Polygon", "coordinates": [[[8.5, 47.3], [8.6, 47.3], [8.5, 47.4], [8.5, 47.3]]]}
+			]}},
+			{"type": "Feature", "properties": {}, "geometry": null}
+		]
+	}`))
+	var positions [][2]float64
+	crs, err := ReadGeoJSONPositions(file, func(x, y float64) {
+		positions = append(positions, [2]float64{x, y})
+	})
+	if err != nil {
+		t.Fatalf("Failed to read GeoJSON file: %v", err)
+	}
+	assert.Equal(t, "", crs)
+	assert.Equal(t, [][2]float64{{8.5417, 47.3769}, {8.5, 47.3}, {8.6, 47.4}, {8.5, 47.3}, {8.6, 47.3}, {8.5, 47.4}, {8.5, 47.3}}, positions)
+}
+
+func TestReadGeoJSONPositions_CRS(t *testing.T) {
+	file := writeGeoFile(t, "lv95.geojson", []byte(`{"type": "FeatureCollection",
+		"crs": {"type": "name", "properties": {"name": "urn:ogc:def:crs:EPSG::2056"}},
+		"features": [{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2683000, 1247000]}}]}`))
+	crs, err := ReadGeoJSONPositions(file, func(x, y float64) {})
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ogc:def:crs:EPSG::2056", crs)
+}
+
+func TestReadGeoJSONPositions_Corrupt(t *testing.T) {
+	for name, content := range map[string]string{
+		"truncated.geojson": `{"type": "Point", "coordinates": [8.5`,
+		"single.geojson":    `{"type": "Point", "coordinates": [8.5]}`,
+		"mixed.geojson":     `{"type": "LineString", "coordinates": [[8.5, 47.3], 8.6]}`,
+		"strings.geojson":   `{"type": "Point", "coordinates": ["8.5", "47.3"]}`,
+		"trailing.geojson":  `{"type": "Point", "coordinates": [8.5, 47.3]} {}`,
+	} {
+		_, err := ReadGeoJSONPositions(writeGeoFile(t, name, []byte(content)), func(x, y float64) {})
+		assert.Error(t, err, name)
+	}
+}
+
+// shapefileHeader returns the header of a shapefile with the given shape type and extent
+func shapefileHeader(shapeType uint32, minX, minY, maxX, maxY float64) []byte {
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header, 9994)
+	binary.BigEndian.PutUint32(header[24:], 50)
+	binary.LittleEndian.PutUint32(header[28:], 1000)
+	binary.LittleEndian.PutUint32(header[32:], shapeType)
+	for i, value := range []float64{minX, minY, maxX, maxY} {
+		binary.LittleEndian.PutUint64(header[36+8*i:], math.Float64bits(value))
+	}
+	return header
+}
+
+func TestReadShapefileHeader(t *testing.T) {
+	file := writeGeoFile(t, "lakes.shp", shapefileHeader(5, 8.5, 47.2, 8.8, 47.4))
+	header, err := ReadShapefileHeader(file)
+	if err != nil {
+		t.Fatalf("Failed to read shapefile: %v", err)
+	}
+	assert.Equal(t, ShapefileHeader{ShapeType: 5, MinX: 8.5, MinY: 47.2, MaxX: 8.8, MaxY: 47.4}, header)
+
+	_, err = ReadShapefileHeader(writeGeoFile(t, "truncated.shp", shapefileHeader(5, 8.5, 47.2, 8.8, 47.4)[:50]))
+	assert.EqualError(t, err, "the header is truncated")
+
+	wrongVersion := shapefileHeader(5, 8.5, 47.2, 8.8, 47.4)
+	binary.LittleEndian.PutUint32(wrongVersion[28:], 999)
+	_, err = ReadShapefileHeader(writeGeoFile(t, "version.shp", wrongVersion))
+	assert.EqualError(t, err, "the shapefile version 999 is unknown")
+
+	_, err = ReadShapefileHeader(writeGeoFile(t, "text.shp", []byte("not a shapefile")))
+	assert.ErrorIs(t, err, ErrNotShapefile)
+}
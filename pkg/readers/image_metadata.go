@@ -0,0 +1,342 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// ImageMetadata is the personal information ReadImageMetadata finds in the EXIF and XMP metadata
+// of an image
+type ImageMetadata struct {
+	GPS           string   // Latitude and longitude in degrees, e.g. "47.40434, 8.60955", "" if there are none
+	SerialNumbers []string // Serial numbers of the camera body and lens
+	Authors       []string // Artist, author, creator and camera owner names
+}
+
+// ErrNotImage is returned by ReadImageMetadata for files that are no JPEG, TIFF or PNG images
+var ErrNotImage = errors.New("not a JPEG, TIFF or PNG image")
+
+// maxMetadataSize limits the size of a metadata block read from an image
+const maxMetadataSize = 1 << 20
+
+// ReadImageMetadata reads the EXIF and XMP metadata of a JPEG, TIFF or PNG image
+func ReadImageMetadata(file structs.File) (ImageMetadata, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return ImageMetadata{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return ImageMetadata{}, err
+	}
+
+	metadata := ImageMetadata{}
+	magic := make([]byte, 8)
+	n, _ := f.ReadAt(magic, 0)
+	switch {
+	case n >= 2 && magic[0] == 0xFF && magic[1] == 0xD8:
+		err = readJPEGMetadata(f, &metadata)
+	case n >= 4 && (string(magic[:4]) == "II*\x00" || string(magic[:4]) == "MM\x00*"):
+		err = readTIFFMetadata(f, info.Size(), &metadata)
+	case n == 8 && string(magic) == "\x89PNG\r\n\x1a\n":
+		err = readPNGMetadata(f, &metadata)
+	default:
+		return metadata, ErrNotImage
+	}
+	return metadata, err
+}
+
+// readJPEGMetadata reads the APP1 segments holding EXIF and XMP up to the image data
+func readJPEGMetadata(r io.ReaderAt, metadata *ImageMetadata) error {
+	header := make([]byte, 4)
+	for at := int64(2); ; {
+		if _, err := r.ReadAt(header, at); err != nil {
+			return nil
+		}
+		if header[0] != 0xFF {
+			return fmt.Errorf("the JPEG segment at byte %d is corrupt", at)
+		}
+		// Markers may be padded with any number of 0xFF bytes
+		if header[1] == 0xFF {
+			at++
+			continue
+		}
+		// Start of scan, the metadata comes before the image data
+		if header[1] == 0xDA || header[1] == 0xD9 {
+			return nil
+		}
+		length := int64(binary.BigEndian.Uint16(header[2:]))
+		if header[1] == 0xE1 && length > 2 {
+			segment := make([]byte, length-2)
+			if _, err := r.ReadAt(segment, at+4); err != nil {
+				return errors.New("the JPEG file is truncated")
+			}
+			if exif, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00")); ok {
+				readTIFFMetadata(bytes.NewReader(exif), int64(len(exif)), metadata)
+			} else if xmp, ok := bytes.CutPrefix(segment, []byte("http://ns.adobe.com/xap/1.0/\x00")); ok {
+				readXMPMetadata(xmp, metadata)
+			}
+		}
+		at += 2 + length
+	}
+}
+
+// readPNGMetadata reads the eXIf chunk and the XMP and author of the text chunks
+func readPNGMetadata(r io.ReaderAt, metadata *ImageMetadata) error {
+	header := make([]byte, 8)
+	for at := int64(8); ; {
+		if _, err := r.ReadAt(header, at); err != nil {
+			return nil
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		chunkType := string(header[4:])
+		if chunkType == "IEND" {
+			return nil
+		}
+		if (chunkType == "eXIf" || chunkType == "tEXt" || chunkType == "iTXt") && length <= maxMetadataSize {
+			data := make([]byte, length)
+			if _, err := r.ReadAt(data, at+8); err != nil {
+				return errors.New("the PNG file is truncated")
+			}
+			switch keyword, text, _ := bytes.Cut(data, []byte{0}); {
+			case chunkType == "eXIf":
+				readTIFFMetadata(bytes.NewReader(data), length, metadata)
+			case chunkType == "iTXt" && string(keyword) == "XML:com.adobe.xmp":
+				readXMPMetadata(text, metadata)
+			case chunkType == "tEXt" && string(keyword) == "Author":
+				metadata.addAuthor(string(text))
+			}
+		}
+		at += 12 + length
+	}
+}
+
+func (m *ImageMetadata) addAuthor(name string) {
+	if name = strings.TrimSpace(strings.TrimRight(name, "\x00")); name != "" {
+		for _, author := range m.Authors {
+			if author == name {
+				return
+			}
+		}
+		m.Authors = append(m.Authors, name)
+	}
+}
+
+func (m *ImageMetadata) addSerialNumber(serial string) {
+	if serial = strings.TrimSpace(strings.TrimRight(serial, "\x00")); serial != "" {
+		for _, existing := range m.SerialNumbers {
+			if existing == serial {
+				return
+			}
+		}
+		m.SerialNumbers = append(m.SerialNumbers, serial)
+	}
+}
+
+// tiffEntry is an entry of an image file directory
+type tiffEntry struct {
+	fieldType uint16
+	count     uint32
+	value     []byte
+}
+
+// tiffTypeSizes are the sizes of the TIFF field types by their number
+var tiffTypeSizes = map[uint16]int64{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// tiffReader reads the image file directories of TIFF data
+type tiffReader struct {
+	r     io.ReaderAt
+	size  int64
+	order binary.ByteOrder
+}
+
+// directory reads the entries of the image file directory at offset by their tag
+func (t tiffReader) directory(offset int64) map[uint16]tiffEntry {
+	entries := map[uint16]tiffEntry{}
+	b := make([]byte, 2)
+	if offset <= 0 || offset >= t.size {
+		return entries
+	}
+	if _, err := t.r.ReadAt(b, offset); err != nil {
+		return entries
+	}
+	count := int64(t.order.Uint16(b))
+	raw := make([]byte, 12*count)
+	if _, err := t.r.ReadAt(raw, offset+2); err != nil {
+		return entries
+	}
+	for i := int64(0); i < count; i++ {
+		entry := raw[12*i : 12*i+12]
+		e := tiffEntry{fieldType: t.order.Uint16(entry[2:4]), count: t.order.Uint32(entry[4:8])}
+		size := tiffTypeSizes[e.fieldType] * int64(e.count)
+		if size <= 4 {
+			e.value = entry[8 : 8+size]
+		} else if size <= maxMetadataSize {
+			e.value = make([]byte, size)
+			if _, err := t.r.ReadAt(e.value, int64(t.order.Uint32(entry[8:12]))); err != nil {
+				continue
+			}
+		}
+		entries[t.order.Uint16(entry[0:2])] = e
+	}
+	return entries
+}
+
+// offset returns the directory an entry such as the Exif or GPS pointer points to
+func (t tiffReader) offset(e tiffEntry) int64 {
+	if len(e.value) != 4 {
+		return 0
+	}
+	return int64(t.order.Uint32(e.value))
+}
+
+// degrees converts the three rationals of a GPS coordinate to degrees
+func (t tiffReader) degrees(e tiffEntry) (float64, bool) {
+	if e.fieldType != 5 || len(e.value) != 24 {
+		return 0, false
+	}
+	var degrees float64
+	for i, scale := range []float64{1, 60, 3600} {
+		numerator, denominator := t.order.Uint32(e.value[8*i:]), t.order.Uint32(e.value[8*i+4:])
+		if denominator != 0 {
+			degrees += float64(numerator) / float64(denominator) / scale
+		}
+	}
+	return degrees, true
+}
+
+// readTIFFMetadata reads the artist, the serial numbers, the camera owner and the GPS position
+// of TIFF data, as found in TIFF files and in the EXIF blocks of JPEG and PNG images
+func readTIFFMetadata(r io.ReaderAt, size int64, metadata *ImageMetadata) error {
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return errors.New("the TIFF header is truncated")
+	}
+	t := tiffReader{r: r, size: size, order: binary.LittleEndian}
+	if string(header[:2]) == "MM" {
+		t.order = binary.BigEndian
+	}
+	ifd0 := t.directory(int64(t.order.Uint32(header[4:])))
+	if artist, ok := ifd0[0x013B]; ok {
+		metadata.addAuthor(string(artist.value))
+	}
+	// XPAuthor of Windows, in UTF-16
+	if author, ok := ifd0[0x9C9D]; ok && len(author.value)%2 == 0 {
+		units := make([]uint16, len(author.value)/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(author.value[2*i:])
+		}
+		metadata.addAuthor(string(utf16.Decode(units)))
+	}
+	if xmp, ok := ifd0[0x02BC]; ok {
+		readXMPMetadata(xmp.value, metadata)
+	}
+
+	if pointer, ok := ifd0[0x8769]; ok {
+		exif := t.directory(t.offset(pointer))
+		if owner, ok := exif[0xA430]; ok {
+			metadata.addAuthor(string(owner.value))
+		}
+		for _, tag := range []uint16{0xA431, 0xA435} {
+			if serial, ok := exif[tag]; ok {
+				metadata.addSerialNumber(string(serial.value))
+			}
+		}
+	}
+
+	if pointer, ok := ifd0[0x8825]; ok {
+		gps := t.directory(t.offset(pointer))
+		latitude, hasLatitude := t.degrees(gps[0x0002])
+		longitude, hasLongitude := t.degrees(gps[0x0004])
+		if hasLatitude && hasLongitude && (latitude != 0 || longitude != 0) {
+			if strings.HasPrefix(string(gps[0x0001].value), "S") {
+				latitude = -latitude
+			}
+			if strings.HasPrefix(string(gps[0x0003].value), "W") {
+				longitude = -longitude
+			}
+			metadata.GPS = fmt.Sprintf("%.5f, %.5f", latitude, longitude)
+		}
+	}
+	return nil
+}
+
+// xmpProperty finds an XMP property written as attribute (name="value") or as element
+// (<name>value</name>, <name><rdf:Seq><rdf:li>value</rdf:li>...)
+func xmpProperty(name string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(name)
+	return regexp.MustCompile(quoted + `="([^"]+)"|<` + quoted + `>(?:\s*<rdf:(?:Seq|Bag|Alt)>\s*<rdf:li[^>]*>)?([^<]+)<`)
+}
+
+var (
+	xmpAuthors   = []*regexp.Regexp{xmpProperty("dc:creator"), xmpProperty("exifEX:CameraOwnerName"), xmpProperty("aux:OwnerName")}
+	xmpSerials   = []*regexp.Regexp{xmpProperty("exifEX:BodySerialNumber"), xmpProperty("exifEX:LensSerialNumber"), xmpProperty("aux:SerialNumber"), xmpProperty("aux:LensSerialNumber")}
+	xmpLatitude  = xmpProperty("exif:GPSLatitude")
+	xmpLongitude = xmpProperty("exif:GPSLongitude")
+)
+
+// xmpValue returns the first value of a property found by xmpProperty
+func xmpValue(re *regexp.Regexp, xmp []byte) (string, bool) {
+	match := re.FindSubmatch(xmp)
+	if match == nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(match[1]) + string(match[2])), true
+}
+
+// xmpDegrees converts an XMP GPS coordinate such as "47,24.26N" or "8,36,34W" to degrees
+func xmpDegrees(value string) (float64, bool) {
+	if len(value) < 2 {
+		return 0, false
+	}
+	parts := strings.Split(value[:len(value)-1], ",")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	var degrees float64
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return 0, false
+		}
+		degrees += number / []float64{1, 60, 3600}[i]
+	}
+	if direction := value[len(value)-1]; direction == 'S' || direction == 'W' {
+		degrees = -degrees
+	}
+	return degrees, true
+}
+
+// readXMPMetadata reads the creators, owners, serial numbers and the GPS position of an XMP packet
+func readXMPMetadata(xmp []byte, metadata *ImageMetadata) {
+	for _, re := range xmpAuthors {
+		if author, ok := xmpValue(re, xmp); ok {
+			metadata.addAuthor(author)
+		}
+	}
+	for _, re := range xmpSerials {
+		if serial, ok := xmpValue(re, xmp); ok {
+			metadata.addSerialNumber(serial)
+		}
+	}
+	if metadata.GPS == "" {
+		latitudeValue, _ := xmpValue(xmpLatitude, xmp)
+		longitudeValue, _ := xmpValue(xmpLongitude, xmp)
+		latitude, hasLatitude := xmpDegrees(latitudeValue)
+		longitude, hasLongitude := xmpDegrees(longitudeValue)
+		if hasLatitude && hasLongitude {
+			metadata.GPS = fmt.Sprintf("%.5f, %.5f", latitude, longitude)
+		}
+	}
+}
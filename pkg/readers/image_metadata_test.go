@@ -0,0 +1,122 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+)
+
+// exifBlock builds little-endian TIFF data with the artist in IFD0, a body serial number in the
+// Exif IFD and a GPS position in the GPS IFD
+func exifBlock() []byte {
+	var b bytes.Buffer
+	u16 := func(n int) { binary.Write(&b, binary.LittleEndian, uint16(n)) }
+	u32 := func(n int) { binary.Write(&b, binary.LittleEndian, uint32(n)) }
+	entry := func(tag, fieldType, count, value int) {
+		u16(tag)
+		u16(fieldType)
+		u32(count)
+		u32(value)
+	}
+	b.WriteString("II*\x00")
+	u32(8)
+
+	// IFD0 at 8, 42 bytes long, followed by the artist at 50
+	u16(3)
+	entry(0x013B, 2, 9, 50)
+	entry(0x8769, 4, 1, 60)
+	entry(0x8825, 4, 1, 86)
+	u32(0)
+	b.WriteString("Jane Doe\x00\x00")
+
+	// Exif IFD at 60, followed by the serial number at 78
+	u16(1)
+	entry(0xA431, 2, 8, 78)
+	u32(0)
+	b.WriteString("SN12345\x00")
+
+	// GPS IFD at 86, followed by the latitude at 140 and the longitude at 164
+	u16(4)
+	entry(0x0001, 2, 2, 'N')
+	entry(0x0002, 5, 3, 140)
+	entry(0x0003, 2, 2, 'W')
+	entry(0x0004, 5, 3, 164)
+	u32(0)
+	for _, rational := range [][2]int{{47, 1}, {24, 1}, {1563, 100}, {8, 1}, {36, 1}, {3438, 100}} {
+		u32(rational[0])
+		u32(rational[1])
+	}
+	return b.Bytes()
+}
+
+// jpegWithSegments builds a JPEG file with the given APP1 segments before the image data
+func jpegWithSegments(segments ...[]byte) []byte {
+	b := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00}
+	for _, segment := range segments {
+		b = append(b, 0xFF, 0xE1)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(segment)+2))
+		b = append(b, segment...)
+	}
+	return append(b, 0xFF, 0xDA, 0x00, 0x02, 0x12, 0x34, 0xFF, 0xD9)
+}
+
+// pngWithChunks builds a PNG file with the given chunks before its end
+func pngWithChunks(chunks ...[2]string) []byte {
+	b := []byte("\x89PNG\r\n\x1a\n")
+	for _, chunk := range append(chunks, [2]string{"IEND", ""}) {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(chunk[1])))
+		data := []byte(chunk[0] + chunk[1])
+		b = append(b, data...)
+		b = binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(data))
+	}
+	return b
+}
+
+const testXMP = `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF><rdf:Description exif:GPSLatitude="47,24.26N" exif:GPSLongitude="8,36,34.38E" aux:SerialNumber="0421">` +
+	`<dc:creator><rdf:Seq><rdf:li>Max Muster</rdf:li></rdf:Seq></dc:creator></rdf:Description></rdf:RDF></x:xmpmeta>`
+
+func TestReadImageMetadata(t *testing.T) {
+	exif := ImageMetadata{GPS: "47.40434, -8.60955", SerialNumbers: []string{"SN12345"}, Authors: []string{"Jane Doe"}}
+	tests := []struct {
+		name     string
+		content  []byte
+		expected ImageMetadata
+	}{
+		{"JPEG without metadata", jpegWithSegments(), ImageMetadata{}},
+		{"JPEG with EXIF", jpegWithSegments(append([]byte("Exif\x00\x00"), exifBlock()...)), exif},
+		{
+			"JPEG with XMP",
+			jpegWithSegments([]byte("http://ns.adobe.com/xap/1.0/\x00" + testXMP)),
+			ImageMetadata{GPS: "47.40433, 8.60955", SerialNumbers: []string{"0421"}, Authors: []string{"Max Muster"}},
+		},
+		{"TIFF", exifBlock(), exif},
+		{
+			"PNG",
+			pngWithChunks([2]string{"tEXt", "Author\x00Erika Muster"}, [2]string{"eXIf", string(exifBlock())}),
+			ImageMetadata{GPS: exif.GPS, SerialNumbers: exif.SerialNumbers, Authors: []string{"Erika Muster", "Jane Doe"}},
+		},
+		{
+			"PNG with XMP",
+			pngWithChunks([2]string{"iTXt", "XML:com.adobe.xmp\x00\x00\x00\x00\x00" + testXMP}),
+			ImageMetadata{GPS: "47.40433, 8.60955", SerialNumbers: []string{"0421"}, Authors: []string{"Max Muster"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := ReadImageMetadata(writeTestFile(t, "image", tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(metadata, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, metadata)
+			}
+		})
+	}
+
+	if _, err := ReadImageMetadata(writeTestFile(t, "notes.jpg", []byte("not an image"))); !errors.Is(err, ErrNotImage) {
+		t.Errorf("expected ErrNotImage, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package readers
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// oleSignature starts the compound files the binary Office formats before 2007 are stored in
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// ErrNotOLE is returned for .xls and .doc files that are no compound files
+var ErrNotOLE = errors.New("not a binary Office file")
+
+// minStringLength is the number of characters a run needs to count as a string, shorter runs
+// are mostly numbers and structures of the file read as text
+const minStringLength = 4
+
+// ReadLegacyOfficeFile extracts the strings of a binary .xls or .doc file, which stores its
+// text as 8-bit or UTF-16 characters. The sheets, cells and paragraphs are not parsed: the
+// strings are returned as one part, one per line, and a string crossing the boundary of a
+// sector of the compound file is split in two.
+func ReadLegacyOfficeFile(file structs.File) ([][]byte, error) {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, oleSignature) {
+		return nil, ErrNotOLE
+	}
+
+	var text strings.Builder
+	for _, s := range extract8BitStrings(data) {
+		text.WriteString(s)
+		text.WriteByte('\n')
+	}
+	// UTF-16 strings may start at odd offsets, e.g. after the flags of a string in a workbook
+	for offset := 0; offset < 2; offset++ {
+		for _, s := range extractUTF16Strings(data[offset:]) {
+			text.WriteString(s)
+			text.WriteByte('\n')
+		}
+	}
+	return [][]byte{[]byte(text.String())}, nil
+}
+
+// extract8BitStrings returns the runs of printable Windows-1252 characters without the
+// characters between 0x80 and 0x9f, which are rare in text and common in binary data
+func extract8BitStrings(data []byte) []string {
+	var strs []string
+	var run []rune
+	flush := func() {
+		if len(run) >= minStringLength {
+			strs = append(strs, string(run))
+		}
+		run = run[:0]
+	}
+	for _, b := range data {
+		if b == '\t' || b >= 0x20 && b < 0x7f || b >= 0xa0 {
+			run = append(run, rune(b))
+			continue
+		}
+		flush()
+	}
+	flush()
+	return strs
+}
+
+// extractUTF16Strings returns the runs of printable little-endian UTF-16 characters. Only the
+// characters below U+0800 (Latin, Greek, Cyrillic, ...) are taken: two ASCII characters read as
+// one UTF-16 character are above it, so 8-bit strings are not read again as garbage.
+func extractUTF16Strings(data []byte) []string {
+	var strs []string
+	var run []rune
+	flush := func() {
+		if len(run) >= minStringLength {
+			strs = append(strs, string(run))
+		}
+		run = run[:0]
+	}
+	for i := 0; i+1 < len(data); i += 2 {
+		r := rune(data[i]) | rune(data[i+1])<<8
+		if r == '\t' || r >= 0x20 && r < 0x800 && unicode.IsPrint(r) && utf8.ValidRune(r) {
+			run = append(run, r)
+			continue
+		}
+		flush()
+	}
+	flush()
+	return strs
+}
@@ -0,0 +1,63 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeLegacyOfficeFile writes a compound file holding text8 as 8-bit and text16 as UTF-16
+// characters between binary structures
+func writeLegacyOfficeFile(t *testing.T, name string, text8 string, text16 string) structs.File {
+	var data bytes.Buffer
+	data.Write(oleSignature)
+	data.Write([]byte{0x00, 0x01, 0x3e, 0x00, 0x03, 0x00, 0xfe, 0xff})
+	data.Write(bytes.Repeat([]byte{0x00}, 16))
+	data.Write([]byte(text8))
+	data.Write([]byte{0x00, 0x00, 0x02, 0x00, 0x09})
+	binary.Write(&data, binary.LittleEndian, utf16.Encode([]rune(text16)))
+	data.Write([]byte{0x00, 0x00, 0x01, 0x83, 0x12})
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name, Suffix: filepath.Ext(name)}
+}
+
+func TestReadLegacyOfficeFile(t *testing.T) {
+	file := writeLegacyOfficeFile(t, "report.doc", "Measured temperatures", "Zürich, password: geheim")
+	content, err := ReadLegacyOfficeFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read DOC file: %v", err)
+	}
+	assert.Len(t, content, 1)
+	lines := strings.Split(string(content[0]), "\n")
+	assert.Contains(t, lines, "Measured temperatures")
+	assert.Contains(t, lines, "Zürich, password: geheim")
+}
+
+func TestReadLegacyOfficeFile_Latin1(t *testing.T) {
+	file := writeLegacyOfficeFile(t, "lakes.xls", "Z\xfcrichsee", "depth")
+	content, err := ReadLegacyOfficeFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read XLS file: %v", err)
+	}
+	assert.Contains(t, strings.Split(string(content[0]), "\n"), "Zürichsee")
+}
+
+func TestReadLegacyOfficeFile_NotOLE(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.doc")
+	if err := os.WriteFile(path, []byte("{\\rtf1 saved as .doc}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadLegacyOfficeFile(structs.File{Path: path, Name: "report.doc", Suffix: ".doc"})
+	assert.ErrorIs(t, err, ErrNotOLE)
+}
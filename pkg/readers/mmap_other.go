@@ -0,0 +1,13 @@
+//go:build !unix
+
+package readers
+
+import (
+	"errors"
+	"os"
+)
+
+// MapFile is not supported on this platform, files are read in chunks instead
+func MapFile(file *os.File) ([]byte, func() error, error) {
+	return nil, nil, errors.New("memory-mapped files are not supported on this platform")
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package readers
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// MapFile maps the content of file into memory read-only, so large files are scanned without
+// reading them into the heap. The data must not be used after unmap has been called.
+func MapFile(file *os.File) ([]byte, func() error, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size <= 0 || int64(int(size)) != size {
+		return nil, nil, errors.New("file cannot be mapped")
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,409 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// NetCDFHeader is what ReadNetCDFHeader learns from the header of a NetCDF or HDF5 file
+type NetCDFHeader struct {
+	Format           string   // "NetCDF classic", "NetCDF 64-bit offset", "NetCDF 64-bit data" or "HDF5"
+	GlobalAttributes []string // Names of the global attributes, of the root group in HDF5 files
+	DenseAttributes  bool     // The HDF5 file stores its global attributes in a heap, which is not read
+}
+
+// ErrNotNetCDF is returned by ReadNetCDFHeader for files that are neither NetCDF nor HDF5
+var ErrNotNetCDF = errors.New("neither a NetCDF nor an HDF5 file")
+
+// hdf5Signature starts the superblock of HDF5 files, NetCDF-4 files are HDF5 files
+var hdf5Signature = []byte("\x89HDF\r\n\x1a\n")
+
+// maxHeaderItems limits the dimensions, attributes and variables read from a header, larger
+// counts come from corrupt files
+const maxHeaderItems = 1 << 20
+
+// ReadNetCDFHeader reads the header of a NetCDF classic or HDF5 (NetCDF-4) file. Errors other
+// than ErrNotNetCDF and those opening the file describe how the file is corrupt.
+func ReadNetCDFHeader(file structs.File) (NetCDFHeader, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return NetCDFHeader{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return NetCDFHeader{}, err
+	}
+
+	magic := make([]byte, 8)
+	n, _ := io.ReadFull(f, magic)
+	switch {
+	case n >= 4 && bytes.Equal(magic[:3], []byte("CDF")):
+		return readClassicHeader(f, magic[3], info.Size())
+	case n == 8 && bytes.Equal(magic, hdf5Signature):
+		return readHDF5Header(f, 0, info.Size())
+	}
+	// HDF5 files may start with a user block of 512, 1024, 2048, ... bytes
+	for offset := int64(512); offset+8 <= info.Size(); offset *= 2 {
+		if _, err := f.ReadAt(magic, offset); err == nil && bytes.Equal(magic, hdf5Signature) {
+			return readHDF5Header(f, offset, info.Size())
+		}
+	}
+	return NetCDFHeader{}, ErrNotNetCDF
+}
+
+// classicReader reads the big-endian header of a NetCDF classic file, keeping the first error
+type classicReader struct {
+	r       *bufio.Reader
+	version byte
+	err     error
+}
+
+func (c *classicReader) read(n int64) []byte {
+	if c.err != nil {
+		return nil
+	}
+	if n < 0 || n > 1<<24 {
+		c.err = fmt.Errorf("the header has an implausible size of %d bytes", n)
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(c.r, b); err != nil {
+		c.err = errors.New("the header is truncated")
+		return nil
+	}
+	return b
+}
+
+func (c *classicReader) uint32() uint32 {
+	if b := c.read(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (c *classicReader) uint64() uint64 {
+	if b := c.read(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// length reads a length or number of elements, 8 bytes long in the 64-bit data format (CDF-5)
+func (c *classicReader) length() int64 {
+	if c.version == 5 {
+		return int64(c.uint64())
+	}
+	return int64(c.uint32())
+}
+
+// count reads the number of items of a list or name
+func (c *classicReader) count() int64 {
+	n := c.length()
+	if c.err == nil && (n < 0 || n > maxHeaderItems) {
+		c.err = fmt.Errorf("the header has an implausible count of %d", n)
+		return 0
+	}
+	return n
+}
+
+// name reads a name, padded to 4 bytes
+func (c *classicReader) name() string {
+	n := c.count()
+	name := c.read(n)
+	c.read((4 - n%4) % 4)
+	return string(name)
+}
+
+// classicTypeSizes are the sizes of the NetCDF types by their number
+var classicTypeSizes = map[uint32]int64{1: 1, 2: 1, 3: 2, 4: 4, 5: 4, 6: 8, 7: 1, 8: 2, 9: 4, 10: 8, 11: 8}
+
+// list reads the tag and the number of elements of a list, 0 for an absent list
+func (c *classicReader) list(tag uint32) int64 {
+	found := c.uint32()
+	n := c.count()
+	if c.err == nil && found != tag && (found != 0 || n != 0) {
+		c.err = fmt.Errorf("the header has the tag %#x where %#x is expected", found, tag)
+	}
+	return n
+}
+
+// attributes reads a list of attributes and returns their names
+func (c *classicReader) attributes() []string {
+	var names []string
+	for i, n := int64(0), c.list(0x0C); i < n && c.err == nil; i++ {
+		names = append(names, c.name())
+		ncType := c.uint32()
+		size, ok := classicTypeSizes[ncType]
+		if !ok && c.err == nil {
+			c.err = fmt.Errorf("the attribute '%s' has the unknown type %d", names[len(names)-1], ncType)
+		}
+		length := c.length() * size
+		c.read(length + (4-length%4)%4)
+	}
+	return names
+}
+
+func readClassicHeader(f *os.File, version byte, size int64) (NetCDFHeader, error) {
+	header := NetCDFHeader{}
+	switch version {
+	case 1:
+		header.Format = "NetCDF classic"
+	case 2:
+		header.Format = "NetCDF 64-bit offset"
+	case 5:
+		header.Format = "NetCDF 64-bit data"
+	default:
+		return header, fmt.Errorf("the NetCDF version %d is unknown", version)
+	}
+	c := &classicReader{r: bufio.NewReader(io.NewSectionReader(f, 4, size-4)), version: version}
+
+	numRecords := c.length()
+	var dimensions []int64
+	for i, n := int64(0), c.list(0x0A); i < n && c.err == nil; i++ {
+		c.name()
+		dimensions = append(dimensions, c.length())
+	}
+	header.GlobalAttributes = c.attributes()
+
+	type variable struct {
+		name         string
+		record       bool
+		size, offset int64
+	}
+	var variables []variable
+	var recordSize int64
+	for i, n := int64(0), c.list(0x0B); i < n && c.err == nil; i++ {
+		v := variable{name: c.name()}
+		dimensionCount := c.count()
+		for j := int64(0); j < dimensionCount && c.err == nil; j++ {
+			id := c.count()
+			if id >= int64(len(dimensions)) {
+				c.err = fmt.Errorf("the variable '%s' has the unknown dimension %d", v.name, id)
+			} else if j == 0 && dimensions[id] == 0 {
+				v.record = true
+			}
+		}
+		c.attributes()
+		c.uint32()
+		v.size = c.length()
+		if version == 1 {
+			v.offset = int64(c.uint32())
+		} else {
+			v.offset = int64(c.uint64())
+		}
+		if v.record {
+			recordSize += v.size
+		}
+		variables = append(variables, v)
+	}
+	if c.err != nil {
+		return header, c.err
+	}
+
+	// Variables larger than 4 GiB have no size in the older formats
+	for _, v := range variables {
+		end := v.offset + v.size
+		if v.record && numRecords > 0 && numRecords != 0xFFFFFFFF {
+			end = v.offset + (numRecords-1)*recordSize + v.size
+		} else if v.record {
+			continue
+		}
+		if v.size != 0xFFFFFFFF && end > size {
+			return header, fmt.Errorf("the file is truncated, the data of the variable '%s' ends at byte %d but the file has %d bytes", v.name, end, size)
+		}
+	}
+	return header, nil
+}
+
+// hdf5Reader reads little-endian structures of an HDF5 file at their addresses
+type hdf5Reader struct {
+	f                      io.ReaderAt
+	size                   int64
+	base                   int64
+	offsetSize, lengthSize int
+}
+
+func (h *hdf5Reader) read(at int64, n int) ([]byte, error) {
+	if at < 0 || n < 0 || at+int64(n) > h.size {
+		return nil, fmt.Errorf("the structure at byte %d lies beyond the end of the file", at)
+	}
+	b := make([]byte, n)
+	if _, err := h.f.ReadAt(b, at); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// littleEndian decodes a little-endian number of up to 8 bytes
+func littleEndian(b []byte) uint64 {
+	var n uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		n = n<<8 | uint64(b[i])
+	}
+	return n
+}
+
+// isUndefined reports whether an address is the undefined address, all bits set
+func isUndefined(address uint64, size int) bool {
+	return size < 8 && address == 1<<(8*size)-1 || address == ^uint64(0)
+}
+
+func readHDF5Header(f io.ReaderAt, offset int64, size int64) (NetCDFHeader, error) {
+	header := NetCDFHeader{Format: "HDF5"}
+	h := &hdf5Reader{f: f, size: size}
+	superblock, err := h.read(offset, 16)
+	if err != nil {
+		return header, errors.New("the superblock is truncated")
+	}
+
+	// The addresses are the base address, the end of file address and the root group object header
+	var at int64
+	var addresses [3]int
+	switch version := superblock[8]; version {
+	case 0, 1:
+		h.offsetSize, h.lengthSize = int(superblock[13]), int(superblock[14])
+		at = 24
+		if version == 1 {
+			at = 28
+		}
+		addresses = [3]int{0, 2, 5}
+	case 2, 3:
+		h.offsetSize, h.lengthSize = int(superblock[9]), int(superblock[10])
+		at = 12
+		addresses = [3]int{0, 2, 3}
+	default:
+		return header, fmt.Errorf("the superblock version %d is unknown", version)
+	}
+	if h.offsetSize < 2 || h.offsetSize > 8 || h.lengthSize < 2 || h.lengthSize > 8 {
+		return header, errors.New("the superblock is corrupt")
+	}
+	fields, err := h.read(offset+at, 6*h.offsetSize)
+	if err != nil {
+		return header, errors.New("the superblock is truncated")
+	}
+	field := func(i int) uint64 { return littleEndian(fields[i*h.offsetSize : (i+1)*h.offsetSize]) }
+	h.base = int64(field(addresses[0]))
+	if end := h.base + int64(field(addresses[1])); end > size {
+		return header, fmt.Errorf("the file is truncated, it should have %d bytes but has %d", end, size)
+	}
+	header.GlobalAttributes, header.DenseAttributes, err = h.objectAttributes(h.base + int64(field(addresses[2])))
+	if err != nil {
+		return header, fmt.Errorf("the root group is unreadable: %w", err)
+	}
+	return header, nil
+}
+
+// messageBlock is a part of an object header holding messages
+type messageBlock struct {
+	start, end int64
+}
+
+// objectAttributes returns the names of the attributes in the object header at address and
+// whether further attributes are stored densely in a heap
+func (h *hdf5Reader) objectAttributes(address int64) ([]string, bool, error) {
+	prefix, err := h.read(address, 16)
+	if err != nil {
+		return nil, false, err
+	}
+	var blocks []messageBlock
+	messageHeaderSize := 8
+	v2 := string(prefix[:4]) == "OHDR"
+	switch {
+	case v2:
+		flags := prefix[5]
+		at := address + 6
+		if flags&0x20 != 0 {
+			at += 16
+		}
+		if flags&0x10 != 0 {
+			at += 4
+		}
+		width := 1 << (flags & 0x03)
+		b, err := h.read(at, width)
+		if err != nil {
+			return nil, false, err
+		}
+		at += int64(width)
+		blocks = append(blocks, messageBlock{at, at + int64(littleEndian(b))})
+		messageHeaderSize = 4
+		if flags&0x04 != 0 {
+			messageHeaderSize = 6
+		}
+	case prefix[0] == 1:
+		blocks = append(blocks, messageBlock{address + 16, address + 16 + int64(binary.LittleEndian.Uint32(prefix[8:12]))})
+	default:
+		return nil, false, errors.New("the object header is corrupt")
+	}
+
+	var names []string
+	dense := false
+	// Continuations could point back at earlier blocks in corrupt files
+	for i := 0; i < len(blocks) && i < 64; i++ {
+		for at := blocks[i].start; at+int64(messageHeaderSize) <= blocks[i].end; {
+			b, err := h.read(at, messageHeaderSize)
+			if err != nil {
+				return nil, false, err
+			}
+			var messageType, size int
+			if v2 {
+				messageType, size = int(b[0]), int(binary.LittleEndian.Uint16(b[1:3]))
+			} else {
+				messageType, size = int(binary.LittleEndian.Uint16(b[0:2])), int(binary.LittleEndian.Uint16(b[2:4]))
+			}
+			data, err := h.read(at+int64(messageHeaderSize), size)
+			if err != nil {
+				return nil, false, err
+			}
+			at += int64(messageHeaderSize + size)
+
+			switch messageType {
+			case 0x0C: // Attribute
+				if name, ok := attributeName(data); ok {
+					names = append(names, name)
+				}
+			case 0x10: // Continuation
+				if len(data) < h.offsetSize+h.lengthSize {
+					return nil, false, errors.New("a continuation message is truncated")
+				}
+				start := h.base + int64(littleEndian(data[:h.offsetSize]))
+				end := start + int64(littleEndian(data[h.offsetSize:h.offsetSize+h.lengthSize]))
+				if v2 {
+					// Continuation chunks start with OCHK and end with a checksum
+					start, end = start+4, end-4
+				}
+				blocks = append(blocks, messageBlock{start, end})
+			case 0x15: // Attribute info
+				at := 2
+				if len(data) > 1 && data[1]&0x01 != 0 {
+					at += 2
+				}
+				if len(data) >= at+h.offsetSize && !isUndefined(littleEndian(data[at:at+h.offsetSize]), h.offsetSize) {
+					dense = true
+				}
+			}
+		}
+	}
+	return names, dense, nil
+}
+
+// attributeName returns the name of an attribute message of version 1, 2 or 3
+func attributeName(data []byte) (string, bool) {
+	if len(data) < 8 {
+		return "", false
+	}
+	start := map[byte]int{1: 8, 2: 8, 3: 9}[data[0]]
+	end := start + int(binary.LittleEndian.Uint16(data[2:4]))
+	if start == 0 || end > len(data) {
+		return "", false
+	}
+	return strings.TrimRight(string(data[start:end]), "\x00"), true
+}
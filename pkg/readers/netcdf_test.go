@@ -0,0 +1,197 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func writeTestFile(t *testing.T, name string, content []byte) structs.File {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Path: path, Name: name}
+}
+
+// classicNetCDF builds a NetCDF classic file with a dimension x of length 4, the given text
+// attributes and a float variable over x, whose data is cut to dataSize bytes
+func classicNetCDF(attributes map[string]string, dataSize int) []byte {
+	var b bytes.Buffer
+	u32 := func(n int) { binary.Write(&b, binary.BigEndian, uint32(n)) }
+	name := func(s string) {
+		u32(len(s))
+		b.WriteString(s + strings.Repeat("\x00", (4-len(s)%4)%4))
+	}
+	b.WriteString("CDF\x01")
+	u32(0)
+	u32(0x0A)
+	u32(1)
+	name("x")
+	u32(4)
+	u32(0x0C)
+	u32(len(attributes))
+	for _, key := range []string{"title", "institution", "Conventions"} {
+		if value, ok := attributes[key]; ok {
+			name(key)
+			u32(2)
+			name(value)
+		}
+	}
+	u32(0x0B)
+	u32(1)
+	name("temperature")
+	u32(1)
+	u32(0)
+	u32(0)
+	u32(0)
+	u32(5)
+	u32(16)
+	u32(b.Len() + 4)
+	b.Write(make([]byte, dataSize))
+	return b.Bytes()
+}
+
+func TestReadNetCDFHeaderClassic(t *testing.T) {
+	content := classicNetCDF(map[string]string{"title": "Lake temperature", "Conventions": "CF-1.8"}, 16)
+	header, err := ReadNetCDFHeader(writeTestFile(t, "lake.nc", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Format != "NetCDF classic" || !reflect.DeepEqual(header.GlobalAttributes, []string{"title", "Conventions"}) {
+		t.Errorf("unexpected header %+v", header)
+	}
+
+	truncated := classicNetCDF(map[string]string{"title": "Lake temperature"}, 8)
+	if _, err := ReadNetCDFHeader(writeTestFile(t, "truncated.nc", truncated)); err == nil || !strings.Contains(err.Error(), "the data of the variable 'temperature' ends at byte") {
+		t.Errorf("expected the missing data to be reported, got %v", err)
+	}
+	if _, err := ReadNetCDFHeader(writeTestFile(t, "header.nc", content[:40])); err == nil || err.Error() != "the header is truncated" {
+		t.Errorf("expected a truncated header, got %v", err)
+	}
+}
+
+// hdf5Message appends an object header message of version 1 to b
+func hdf5Message(b *bytes.Buffer, messageType int, data []byte) {
+	data = append(data, make([]byte, (8-len(data)%8)%8)...)
+	binary.Write(b, binary.LittleEndian, uint16(messageType))
+	binary.Write(b, binary.LittleEndian, uint16(len(data)))
+	b.Write(make([]byte, 4))
+	b.Write(data)
+}
+
+// hdf5Attribute returns an attribute message of the given version without datatype and dataspace
+func hdf5Attribute(version byte, name string) []byte {
+	data := []byte{version, 0}
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(name)+1))
+	data = append(data, 0, 0, 0, 0)
+	if version == 3 {
+		data = append(data, 0)
+	}
+	return append(data, name+"\x00"...)
+}
+
+// hdf5File builds an HDF5 file with a version 0 superblock whose root group has the attribute
+// title and, in a continuation block, the attribute institution. eofSlack is added to the
+// end of file address.
+func hdf5File(eofSlack int) []byte {
+	var messages, continuation bytes.Buffer
+	hdf5Message(&continuation, 0x0C, hdf5Attribute(1, "institution"))
+	continuationAddress := 112 + 2*(8+16) // Both messages of the first block are 16 bytes long
+	hdf5Message(&messages, 0x0C, hdf5Attribute(1, "title"))
+	hdf5Message(&messages, 0x10, binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, uint64(continuationAddress)), uint64(continuation.Len())))
+
+	var b bytes.Buffer
+	u64 := func(n uint64) { binary.Write(&b, binary.LittleEndian, n) }
+	b.Write(hdf5Signature)
+	b.Write([]byte{0, 0, 0, 0, 0, 8, 8, 0, 4, 0, 16, 0, 0, 0, 0, 0})
+	size := 112 + messages.Len() + continuation.Len()
+	u64(0)
+	u64(^uint64(0))
+	u64(uint64(size + eofSlack))
+	u64(^uint64(0))
+	u64(0)
+	u64(96)
+	b.Write(make([]byte, 24))
+	b.Write([]byte{1, 0, 3, 0, 1, 0, 0, 0})
+	binary.Write(&b, binary.LittleEndian, uint32(messages.Len()))
+	b.Write(make([]byte, 4))
+	b.Write(messages.Bytes())
+	b.Write(continuation.Bytes())
+	return b.Bytes()
+}
+
+// hdf5DenseFile builds an HDF5 file with a version 2 superblock and object header whose root
+// group has the attribute Conventions and further attributes in a heap. The file is preceded by
+// a user block of base bytes.
+func hdf5DenseFile(base int) []byte {
+	var messages bytes.Buffer
+	message := func(messageType byte, data []byte) {
+		messages.WriteByte(messageType)
+		binary.Write(&messages, binary.LittleEndian, uint16(len(data)))
+		messages.WriteByte(0)
+		messages.Write(data)
+	}
+	message(0x0C, hdf5Attribute(3, "Conventions"))
+	message(0x15, append([]byte{0, 0}, append(binary.LittleEndian.AppendUint64(nil, 4096), make([]byte, 8)...)...))
+
+	var b bytes.Buffer
+	u64 := func(n uint64) { binary.Write(&b, binary.LittleEndian, n) }
+	b.Write(hdf5Signature)
+	b.Write([]byte{2, 8, 8, 0})
+	u64(uint64(base))
+	u64(^uint64(0))
+	u64(uint64(48 + 7 + messages.Len() + 4))
+	u64(48)
+	b.Write(make([]byte, 4))
+	b.WriteString("OHDR")
+	b.Write([]byte{2, 0, byte(messages.Len())})
+	b.Write(messages.Bytes())
+	b.Write(make([]byte, 4))
+	return append(make([]byte, base), b.Bytes()...)
+}
+
+func TestReadNetCDFHeaderHDF5(t *testing.T) {
+	header, err := ReadNetCDFHeader(writeTestFile(t, "lake.nc", hdf5File(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Format != "HDF5" || header.DenseAttributes || !reflect.DeepEqual(header.GlobalAttributes, []string{"title", "institution"}) {
+		t.Errorf("unexpected header %+v", header)
+	}
+
+	for _, base := range []int{0, 512} {
+		header, err = ReadNetCDFHeader(writeTestFile(t, "dense.h5", hdf5DenseFile(base)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !header.DenseAttributes || !reflect.DeepEqual(header.GlobalAttributes, []string{"Conventions"}) {
+			t.Errorf("unexpected header with a user block of %d bytes: %+v", base, header)
+		}
+	}
+
+	if _, err := ReadNetCDFHeader(writeTestFile(t, "truncated.nc", hdf5File(100))); err == nil || !strings.Contains(err.Error(), "the file is truncated") {
+		t.Errorf("expected a truncated file, got %v", err)
+	}
+	corrupt := hdf5File(0)
+	corrupt[96] = 7
+	if _, err := ReadNetCDFHeader(writeTestFile(t, "corrupt.nc", corrupt)); err == nil || !strings.Contains(err.Error(), "the root group is unreadable") {
+		t.Errorf("expected a corrupt root group, got %v", err)
+	}
+}
+
+func TestReadNetCDFHeaderOtherFiles(t *testing.T) {
+	if _, err := ReadNetCDFHeader(writeTestFile(t, "table.nc", []byte("id,value\n1,2\n"))); !errors.Is(err, ErrNotNetCDF) {
+		t.Errorf("expected ErrNotNetCDF, got %v", err)
+	}
+	if _, err := ReadNetCDFHeader(structs.File{Path: filepath.Join(t.TempDir(), "missing.nc")}); err == nil || errors.Is(err, ErrNotNetCDF) {
+		t.Errorf("expected the error opening the file, got %v", err)
+	}
+}
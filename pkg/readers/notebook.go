@@ -0,0 +1,138 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NotebookCell is a cell of a Jupyter notebook
+type NotebookCell struct {
+	Type        string // "code", "markdown" or "raw"
+	Source      string
+	Outputs     []NotebookOutput
+	Attachments []NotebookBlob // Images pasted into markdown cells
+}
+
+// NotebookOutput is an output of a code cell
+type NotebookOutput struct {
+	Type  string // "stream", "execute_result", "display_data" or "error"
+	Text  string // Streamed text, the plain text representation or the traceback of an error
+	Blobs []NotebookBlob
+}
+
+// NotebookBlob is binary data embedded into the notebook in base64, such as a plot
+type NotebookBlob struct {
+	MIMEType string
+	Size     int // Size of the decoded data in bytes
+}
+
+// ansiEscape matches the color codes of the tracebacks
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// multiline is a text of a notebook, stored as a string or as a list of lines
+type multiline string
+
+func (m *multiline) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*m = multiline(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*m = multiline(text)
+	return nil
+}
+
+// rawNotebook is the nbformat 4 layout of a notebook
+type rawNotebook struct {
+	Cells []struct {
+		CellType    string                          `json:"cell_type"`
+		Source      multiline                       `json:"source"`
+		Outputs     []rawOutput                     `json:"outputs"`
+		Attachments map[string]map[string]multiline `json:"attachments"`
+	} `json:"cells"`
+}
+
+type rawOutput struct {
+	OutputType string               `json:"output_type"`
+	Text       multiline            `json:"text"`
+	Data       map[string]multiline `json:"data"`
+	EName      string               `json:"ename"`
+	EValue     string               `json:"evalue"`
+	Traceback  []string             `json:"traceback"`
+}
+
+// ReadNotebook reads the cells of a Jupyter notebook in nbformat 4
+func ReadNotebook(data []byte) ([]NotebookCell, error) {
+	var notebook rawNotebook
+	if err := json.Unmarshal(data, &notebook); err != nil {
+		return nil, fmt.Errorf("invalid notebook: %v", err)
+	}
+
+	cells := make([]NotebookCell, 0, len(notebook.Cells))
+	for _, raw := range notebook.Cells {
+		cell := NotebookCell{Type: raw.CellType, Source: string(raw.Source)}
+		for _, rawOutput := range raw.Outputs {
+			output := NotebookOutput{Type: rawOutput.OutputType}
+			switch rawOutput.OutputType {
+			case "stream":
+				output.Text = string(rawOutput.Text)
+			case "error":
+				lines := append([]string{rawOutput.EName + ": " + rawOutput.EValue}, rawOutput.Traceback...)
+				output.Text = ansiEscape.ReplaceAllString(strings.Join(lines, "\n"), "")
+			default:
+				output.Text = string(rawOutput.Data["text/plain"])
+				output.Blobs = blobs(rawOutput.Data)
+			}
+			cell.Outputs = append(cell.Outputs, output)
+		}
+		names := make([]string, 0, len(raw.Attachments))
+		for name := range raw.Attachments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			cell.Attachments = append(cell.Attachments, blobs(raw.Attachments[name])...)
+		}
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
+
+// blobs returns the binary data among the representations of an output or attachment, sorted
+// by MIME type. Text, JSON and SVG are stored as they are, everything else in base64.
+func blobs(data map[string]multiline) []NotebookBlob {
+	var found []NotebookBlob
+	for mimeType, value := range data {
+		if strings.HasPrefix(mimeType, "text/") || strings.HasSuffix(mimeType, "json") ||
+			strings.HasSuffix(mimeType, "+xml") || strings.HasSuffix(mimeType, "javascript") {
+			continue
+		}
+		encoded := strings.Join(strings.Fields(string(value)), "")
+		size := base64.StdEncoding.DecodedLen(len(encoded)) - strings.Count(encoded[max(0, len(encoded)-2):], "=")
+		found = append(found, NotebookBlob{MIMEType: mimeType, Size: size})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].MIMEType < found[j].MIMEType })
+	return found
+}
+
+// Text returns the source of the cell followed by the text of its outputs
+func (c NotebookCell) Text() []byte {
+	var b bytes.Buffer
+	b.WriteString(c.Source)
+	for _, output := range c.Outputs {
+		if output.Text != "" {
+			b.WriteString("\n")
+			b.WriteString(output.Text)
+		}
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,46 @@
+package readers
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testNotebook = `{
+ "cells": [
+  {"cell_type": "markdown", "source": ["# Lake\n", "![plot](attachment:plot.png)"],
+   "attachments": {"plot.png": {"image/png": "iVBORw0K\nGgo="}}},
+  {"cell_type": "code", "source": "df = load()", "outputs": [
+   {"output_type": "stream", "name": "stdout", "text": ["loaded\n", "42 rows\n"]},
+   {"output_type": "display_data", "data": {"text/plain": ["<Figure>"], "image/png": "AAAA", "image/svg+xml": "<svg/>"}},
+   {"output_type": "error", "ename": "KeyError", "evalue": "'depth'",
+    "traceback": ["\u001b[0;31mKeyError\u001b[0m Traceback", "File /home/jdoe/lake.py:3"]}
+  ]}
+ ],
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestReadNotebook(t *testing.T) {
+	cells, err := ReadNotebook([]byte(testNotebook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []NotebookCell{
+		{Type: "markdown", Source: "# Lake\n![plot](attachment:plot.png)", Attachments: []NotebookBlob{{MIMEType: "image/png", Size: 8}}},
+		{Type: "code", Source: "df = load()", Outputs: []NotebookOutput{
+			{Type: "stream", Text: "loaded\n42 rows\n"},
+			{Type: "display_data", Text: "<Figure>", Blobs: []NotebookBlob{{MIMEType: "image/png", Size: 3}}},
+			{Type: "error", Text: "KeyError: 'depth'\nKeyError Traceback\nFile /home/jdoe/lake.py:3"},
+		}},
+	}
+	if !reflect.DeepEqual(cells, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cells)
+	}
+	if text := string(cells[1].Text()); text != "df = load()\nloaded\n42 rows\n\n<Figure>\nKeyError: 'depth'\nKeyError Traceback\nFile /home/jdoe/lake.py:3" {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	if _, err := ReadNotebook([]byte("print('not a notebook')")); err == nil {
+		t.Error("expected an error for a file that is no notebook")
+	}
+}
@@ -0,0 +1,332 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+var (
+	parquetMagic          = []byte("PAR1")
+	parquetEncryptedMagic = []byte("PARE")
+)
+
+// Types of the Thrift compact protocol the footer of a Parquet file is encoded with
+const (
+	thriftStop byte = iota
+	thriftTrue
+	thriftFalse
+	thriftByte
+	thriftI16
+	thriftI32
+	thriftI64
+	thriftDouble
+	thriftBinary
+	thriftList
+	thriftSet
+	thriftMap
+	thriftStruct
+)
+
+// maxThriftDepth limits the nesting of structs and lists skipped in a footer
+const maxThriftDepth = 64
+
+// ReadParquetSchema reads the column names and key-value metadata from the footer of a Parquet
+// file. Files with an encrypted footer cannot be read.
+func ReadParquetSchema(file structs.File) (ColumnarSchema, error) {
+	footer, err := readFooter(file.Path, parquetMagic, parquetEncryptedMagic)
+	if err != nil {
+		return ColumnarSchema{}, err
+	}
+
+	r := &thriftReader{data: footer}
+	var elements []parquetSchemaElement
+	var metadata []MetadataEntry
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 2 && typ == thriftList:
+			r.readList(thriftStruct, func() {
+				elements = append(elements, r.readSchemaElement())
+			})
+		case id == 5 && typ == thriftList:
+			r.readList(thriftStruct, func() {
+				metadata = append(metadata, r.readKeyValue())
+			})
+		default:
+			return false
+		}
+		return true
+	})
+	if r.err != nil {
+		return ColumnarSchema{}, fmt.Errorf("the footer is corrupt: %w", r.err)
+	}
+
+	schema := ColumnarSchema{Format: "Parquet", Metadata: metadata}
+	// The first element is the root of the schema, its children follow depth first
+	if len(elements) > 0 {
+		next := 1
+		schema.Columns = appendParquetColumns(nil, elements, &next, elements[0].numChildren, "")
+	}
+	return schema, nil
+}
+
+type parquetSchemaElement struct {
+	name        string
+	numChildren int32
+}
+
+// appendParquetColumns appends the names of count columns starting at elements[*next] and of
+// their children to columns
+func appendParquetColumns(columns []string, elements []parquetSchemaElement, next *int, count int32, prefix string) []string {
+	for i := int32(0); i < count && *next < len(elements); i++ {
+		element := elements[*next]
+		*next++
+		name := prefix + element.name
+		columns = append(columns, name)
+		columns = appendParquetColumns(columns, elements, next, element.numChildren, name+".")
+	}
+	return columns
+}
+
+// readFooter returns the footer of a file that starts and ends with magic, the footer preceding
+// its 4-byte little-endian length and the final magic
+func readFooter(path string, magic []byte, encryptedMagic []byte) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := info.Size()
+	start := make([]byte, len(magic))
+	if _, err := f.ReadAt(start, 0); err != nil || !bytes.Equal(start, magic) {
+		return nil, ErrNotColumnar
+	}
+	trailerSize := int64(4 + len(magic))
+	if size < int64(len(magic))+trailerSize {
+		return nil, errors.New("the file is truncated")
+	}
+	trailer := make([]byte, trailerSize)
+	if _, err := f.ReadAt(trailer, size-trailerSize); err != nil {
+		return nil, err
+	}
+	if encryptedMagic != nil && bytes.Equal(trailer[4:], encryptedMagic) {
+		return nil, errors.New("the footer is encrypted")
+	}
+	if !bytes.Equal(trailer[4:], magic) {
+		return nil, errors.New("the file is truncated")
+	}
+
+	footerSize := int64(binary.LittleEndian.Uint32(trailer))
+	if footerSize > maxFooterSize || footerSize > size-int64(len(magic))-trailerSize {
+		return nil, fmt.Errorf("the footer has an implausible size of %d bytes", footerSize)
+	}
+	footer := make([]byte, footerSize)
+	if _, err := f.ReadAt(footer, size-trailerSize-footerSize); err != nil {
+		return nil, err
+	}
+	return footer, nil
+}
+
+// thriftReader decodes the Thrift compact protocol, keeping the first error
+type thriftReader struct {
+	data  []byte
+	pos   int
+	depth int
+	err   error
+}
+
+func (r *thriftReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *thriftReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.data) {
+		r.fail(errors.New("unexpected end"))
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) varint() uint64 {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b := r.byte()
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value
+		}
+	}
+	r.fail(errors.New("varint too long"))
+	return 0
+}
+
+// int reads a zigzag encoded i16, i32 or i64
+func (r *thriftReader) int() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) binary() []byte {
+	n := r.varint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)-r.pos) {
+		r.fail(fmt.Errorf("implausible length of %d bytes", n))
+		return nil
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b
+}
+
+// readStruct reads the fields of a struct, passing each to field, which reads it and returns
+// true or returns false to have it skipped
+func (r *thriftReader) readStruct(field func(id int16, typ byte) bool) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > maxThriftDepth {
+		r.fail(errors.New("nested too deeply"))
+		return
+	}
+
+	var id int16
+	for r.err == nil {
+		header := r.byte()
+		typ := header & 0x0f
+		if typ == thriftStop {
+			return
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.int())
+		}
+		if !field(id, typ) {
+			r.skip(typ)
+		}
+	}
+}
+
+// listHeader reads the type and size of the elements of a list or set
+func (r *thriftReader) listHeader() (byte, uint64) {
+	header := r.byte()
+	size := uint64(header >> 4)
+	if size == 15 {
+		size = r.varint()
+	}
+	return header & 0x0f, size
+}
+
+// readList reads a list of elements of type typ with element, skipping lists of other types
+func (r *thriftReader) readList(typ byte, element func()) {
+	elementType, size := r.listHeader()
+	if elementType != typ {
+		r.skipElements(elementType, size)
+		return
+	}
+	for i := uint64(0); i < size && r.err == nil; i++ {
+		element()
+	}
+}
+
+func (r *thriftReader) skipElements(typ byte, size uint64) {
+	for i := uint64(0); i < size && r.err == nil; i++ {
+		if typ == thriftTrue || typ == thriftFalse {
+			// Booleans in lists take a byte each
+			r.byte()
+			continue
+		}
+		r.skip(typ)
+	}
+}
+
+// skip skips a value of type typ
+func (r *thriftReader) skip(typ byte) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > maxThriftDepth {
+		r.fail(errors.New("nested too deeply"))
+		return
+	}
+
+	switch typ {
+	case thriftTrue, thriftFalse:
+		// The value of a boolean field is its type
+	case thriftByte:
+		r.byte()
+	case thriftI16, thriftI32, thriftI64:
+		r.varint()
+	case thriftDouble:
+		for i := 0; i < 8; i++ {
+			r.byte()
+		}
+	case thriftBinary:
+		r.binary()
+	case thriftList, thriftSet:
+		r.skipElements(r.listHeader())
+	case thriftMap:
+		size := r.varint()
+		if size == 0 {
+			return
+		}
+		types := r.byte()
+		for i := uint64(0); i < size && r.err == nil; i++ {
+			r.skipElements(types>>4, 1)
+			r.skipElements(types&0x0f, 1)
+		}
+	case thriftStruct:
+		r.readStruct(func(int16, byte) bool { return false })
+	default:
+		r.fail(fmt.Errorf("unknown type %d", typ))
+	}
+}
+
+// readSchemaElement reads the name and number of children of a SchemaElement
+func (r *thriftReader) readSchemaElement() parquetSchemaElement {
+	var element parquetSchemaElement
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 4 && typ == thriftBinary:
+			element.name = string(r.binary())
+		case id == 5 && typ == thriftI32:
+			element.numChildren = int32(r.int())
+		default:
+			return false
+		}
+		return true
+	})
+	return element
+}
+
+func (r *thriftReader) readKeyValue() MetadataEntry {
+	var entry MetadataEntry
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 1 && typ == thriftBinary:
+			entry.Key = string(r.binary())
+		case id == 2 && typ == thriftBinary:
+			entry.Value = string(r.binary())
+		default:
+			return false
+		}
+		return true
+	})
+	return entry
+}
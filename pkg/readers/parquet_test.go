@@ -0,0 +1,51 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadParquetSchema(t *testing.T) {
+	schema, err := ReadParquetSchema(structs.File{Path: "../../testdata/test.parquet", Name: "test.parquet", Suffix: ".parquet"})
+	if err != nil {
+		t.Fatalf("Failed to read Parquet file: %v", err)
+	}
+	assert.Equal(t, "Parquet", schema.Format)
+	assert.Equal(t, []string{"sample_id", "patient_name", "location", "location.lat", "location.lon"}, schema.Columns)
+	assert.Equal(t, []MetadataEntry{
+		{Key: "pandas", Value: `{"index_columns": [], "creator": "jdoe"}`},
+		{Key: "source", Value: `Q:\lake\raw.csv`},
+	}, schema.Metadata)
+}
+
+func TestReadParquetSchema_Corrupt(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/test.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"truncated.parquet": data[:len(data)-10],
+		"encrypted.parquet": append(append([]byte{}, data[:len(data)-4]...), "PARE"...),
+		// The footer claims to be larger than the file
+		"footer.parquet": append(append(append([]byte{}, data[:len(data)-8]...), 0xff, 0xff, 0, 0), "PAR1"...),
+		// The footer ends in the middle of the schema
+		"schema.parquet": append(append(append([]byte("PAR1"), data[8:40]...), 32, 0, 0, 0), "PAR1"...),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ReadParquetSchema(structs.File{Path: path, Name: name})
+		assert.Error(t, err, name)
+		assert.NotErrorIs(t, err, ErrNotColumnar, name)
+	}
+
+	_, err = ReadParquetSchema(structs.File{Path: "../../testdata/readme.txt", Name: "readme.txt"})
+	assert.ErrorIs(t, err, ErrNotColumnar)
+}
@@ -0,0 +1,167 @@
+package readers
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// relationship is an entry of the .rels part next to a part of an Office file
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+type relationships struct {
+	Relationships []relationship `xml:"Relationship"`
+}
+
+type presentation struct {
+	Slides []struct {
+		RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sldIdLst>sldId"`
+}
+
+// ReadPPTXFile returns the text of each slide of a .pptx file, followed by its speaker notes,
+// in the order of the presentation. Each paragraph is a line.
+func ReadPPTXFile(file structs.File) ([][]byte, error) {
+	reader, err := zip.OpenReader(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	parts := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		parts[f.Name] = f
+	}
+
+	var pres presentation
+	if err := decodePart(parts, "ppt/presentation.xml", &pres); err != nil {
+		return nil, err
+	}
+	presRels, err := readRelationships(parts, "ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	PPTXContent := make([][]byte, 0, len(pres.Slides))
+	for _, slide := range pres.Slides {
+		rel, ok := presRels[slide.RelationshipID]
+		if !ok {
+			// Kept empty, so findings are reported on the slide numbers PowerPoint shows
+			PPTXContent = append(PPTXContent, nil)
+			continue
+		}
+		slidePath := resolveTarget("ppt/presentation.xml", rel.Target)
+		text, err := readSlideText(parts, slidePath)
+		if err != nil {
+			return nil, err
+		}
+
+		slideRels, err := readRelationships(parts, slidePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range slideRels {
+			if !strings.HasSuffix(r.Type, "/notesSlide") {
+				continue
+			}
+			notes, err := readSlideText(parts, resolveTarget(slidePath, r.Target))
+			if err != nil {
+				return nil, err
+			}
+			text += notes
+		}
+		PPTXContent = append(PPTXContent, []byte(text))
+	}
+	return PPTXContent, nil
+}
+
+// readSlideText returns the paragraphs of a slide or notes part, one per line
+func readSlideText(parts map[string]*zip.File, name string) (string, error) {
+	part, ok := parts[name]
+	if !ok {
+		return "", nil
+	}
+	rc, err := part.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var text, paragraph strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return text.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			inText = t.Name.Local == "t"
+			if t.Name.Local == "br" {
+				paragraph.WriteByte('\n')
+			}
+		case xml.EndElement:
+			inText = false
+			if t.Name.Local == "p" && paragraph.Len() > 0 {
+				text.WriteString(paragraph.String())
+				text.WriteByte('\n')
+				paragraph.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		}
+	}
+}
+
+// readRelationships returns the relationships of a part by their id, none if it has no .rels part
+func readRelationships(parts map[string]*zip.File, name string) (map[string]relationship, error) {
+	relsPath := path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+	if _, ok := parts[relsPath]; !ok {
+		return nil, nil
+	}
+	var rels relationships
+	if err := decodePart(parts, relsPath, &rels); err != nil {
+		return nil, err
+	}
+	byID := make(map[string]relationship, len(rels.Relationships))
+	for _, r := range rels.Relationships {
+		byID[r.ID] = r
+	}
+	return byID, nil
+}
+
+// resolveTarget returns the name of the part a relationship of the part source points to
+func resolveTarget(source string, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(source), target)
+}
+
+func decodePart(parts map[string]*zip.File, name string, v interface{}) error {
+	part, ok := parts[name]
+	if !ok {
+		return fmt.Errorf("%s is missing, not a PowerPoint file", name)
+	}
+	rc, err := part.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
@@ -0,0 +1,24 @@
+package readers
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPPTXFile(t *testing.T) {
+	pptxFile := structs.File{Path: "../../testdata/test.pptx", Name: "test.pptx", Size: 0, Suffix: ".pptx"}
+	content, err := ReadPPTXFile(pptxFile)
+	if err != nil {
+		t.Fatalf("Failed to read PPTX file: %v", err)
+	}
+	// Slides in the order of the presentation, each followed by its notes
+	expectedContent := [][]byte{[]byte("Greifensee 2024\n"), []byte("Results\nDepth profiles\nRaw data on Q:\\lake\n")}
+	assert.Equal(t, expectedContent, content)
+}
+
+func TestReadPPTXFile_NotPPTX(t *testing.T) {
+	_, err := ReadPPTXFile(structs.File{Path: "../../testdata/test.docx", Name: "test.docx", Suffix: ".docx"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// FileEnv exposes a file to rule expressions as `file`
+type FileEnv struct {
+	Name        string `expr:"name"`
+	DisplayName string `expr:"display_name"`
+	Path        string `expr:"path"`
+	Size        int64  `expr:"size"`
+	Ext         string `expr:"ext"` // lower case suffix including the dot, e.g. ".csv"
+	IsArchive   bool   `expr:"is_archive"`
+	ArchiveName string `expr:"archive_name"`
+	InArchive   bool   `expr:"in_archive"`
+}
+
+// Env is the environment rule expressions are evaluated in
+type Env struct {
+	File FileEnv `expr:"file"`
+}
+
+// Rule is a compiled [rule.<name>] section
+type Rule struct {
+	Name     string
+	program  *vm.Program
+	template *template.Template
+}
+
+func newFileEnv(file structs.File) FileEnv {
+	return FileEnv{
+		Name:        file.Name,
+		DisplayName: file.GetDisplayName(),
+		Path:        file.Path,
+		Size:        file.Size,
+		Ext:         strings.ToLower(file.Suffix),
+		IsArchive:   file.IsArchive,
+		ArchiveName: file.ArchiveName,
+		InArchive:   file.ArchiveName != "",
+	}
+}
+
+// Compile checks the expression and message template of a rule
+func Compile(name string, rc *config.RuleConfig) (*Rule, error) {
+	program, err := expr.Compile(rc.Rule, expr.Env(Env{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: invalid expression: %w", name, err)
+	}
+
+	message := rc.Message
+	if message == "" {
+		message = fmt.Sprintf("File matches rule '%s'.", name)
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: invalid message template: %w", name, err)
+	}
+
+	return &Rule{Name: name, program: program, template: tmpl}, nil
+}
+
+// Load compiles all rules of the config, sorted by name
+func Load(cfg config.Config) ([]*Rule, error) {
+	names := make([]string, 0, len(cfg.Rules))
+	for name := range cfg.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]*Rule, 0, len(names))
+	for _, name := range names {
+		rule, err := Compile(name, cfg.Rules[name])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Check evaluates the rule on a file and returns a message if it matches
+func (r *Rule) Check(file structs.File) ([]structs.Message, error) {
+	env := Env{File: newFileEnv(file)}
+	result, err := expr.Run(r.program, env)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+	if matched, _ := result.(bool); !matched {
+		return nil, nil
+	}
+
+	// The template sees the same names as the expression, e.g. {{.file.size}}
+	data := map[string]interface{}{
+		"file": map[string]interface{}{
+			"name":         env.File.Name,
+			"display_name": env.File.DisplayName,
+			"path":         env.File.Path,
+			"size":         env.File.Size,
+			"ext":          env.File.Ext,
+			"is_archive":   env.File.IsArchive,
+			"archive_name": env.File.ArchiveName,
+			"in_archive":   env.File.InArchive,
+		},
+	}
+	var content bytes.Buffer
+	if err := r.template.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("rule %s: failed to render message: %w", r.Name, err)
+	}
+	return []structs.Message{{Content: content.String(), Source: file, TestName: r.Name}}, nil
+}
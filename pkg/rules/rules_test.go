@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestRuleCheck(t *testing.T) {
+	rule, err := Compile("LargeCSV", &config.RuleConfig{
+		Rule:    "file.size > 1e9 && file.ext == '.csv'",
+		Message: "{{.file.name}} is {{.file.size}} bytes, consider a compressed format.",
+	})
+	if err != nil {
+		t.Fatalf("Compile returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     structs.File
+		expected string
+	}{
+		{"large csv", structs.File{Name: "data.CSV", Suffix: ".CSV", Size: 2000000000}, "data.CSV is 2000000000 bytes, consider a compressed format."},
+		{"small csv", structs.File{Name: "data.csv", Suffix: ".csv", Size: 10}, ""},
+		{"large txt", structs.File{Name: "data.txt", Suffix: ".txt", Size: 2000000000}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := rule.Check(tt.file)
+			if err != nil {
+				t.Fatalf("Check returned an error: %v", err)
+			}
+			if tt.expected == "" {
+				if len(messages) != 0 {
+					t.Errorf("expected no message, got %+v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 || messages[0].Content != tt.expected || messages[0].TestName != "LargeCSV" {
+				t.Errorf("unexpected messages: %+v", messages)
+			}
+		})
+	}
+}
+
+func TestRuleDefaultMessage(t *testing.T) {
+	rule, err := Compile("InArchive", &config.RuleConfig{Rule: "file.in_archive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, _ := rule.Check(structs.File{Name: "a.txt", ArchiveName: "x.zip"})
+	if len(messages) != 1 || messages[0].Content != "File matches rule 'InArchive'." {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := map[string]*config.RuleConfig{
+		"syntax error":     {Rule: "file.size >"},
+		"unknown field":    {Rule: "file.colour == 'red'"},
+		"not boolean":      {Rule: "file.size + 1"},
+		"invalid template": {Rule: "true", Message: "{{.file.name"},
+	}
+	for name, rc := range tests {
+		if _, err := Compile(name, rc); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cfg := config.Config{Rules: map[string]*config.RuleConfig{
+		"B": {Rule: "true"},
+		"A": {Rule: "false"},
+	}}
+	rules, err := Load(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || rules[0].Name != "A" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+
+	cfg.Rules["C"] = &config.RuleConfig{Rule: "("}
+	if _, err := Load(cfg); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/eawag-rdm/pc/pkg/config"
 )
@@ -11,15 +12,37 @@ type Config struct {
 	// Address is the server listen address (e.g., ":8080")
 	Address string
 
-	// ConfigPath is the path to the PC config file (pc.toml)
+	// ConfigPath is the path or https:// URL of the PC config file (pc.toml)
 	ConfigPath string
 
+	// DefaultConfig is the PC config used if ConfigPath is empty, e.g. the built-in default of pc,
+	// so that containers can be configured with PC_* environment variables alone
+	DefaultConfig []byte
+
 	// CKANBaseURL is the CKAN instance URL for authentication
 	// If empty, will be read from the PC config
 	CKANBaseURL string
 
 	// VerifyTLS controls whether to verify TLS certificates for CKAN API calls
 	VerifyTLS bool
+
+	// Profile selects an [operation.<profile>] section of the PC config, empty uses [operation.main]
+	Profile string
+
+	// Overrides are key=value pairs overriding values of the PC config, see config.LoadConfigWithOverrides
+	Overrides []string
+}
+
+// DefaultConfigSource names Config.DefaultConfig in messages
+const DefaultConfigSource = "built-in default config"
+
+// DefaultAddress returns the listen address for the PORT environment variable set by container
+// platforms, ":8080" if it is not set
+func DefaultAddress() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
 }
 
 // Validate ensures configuration is valid
@@ -27,7 +50,7 @@ func (c Config) Validate() error {
 	if c.Address == "" {
 		return fmt.Errorf("server address is required")
 	}
-	if c.ConfigPath == "" {
+	if c.ConfigPath == "" && c.DefaultConfig == nil {
 		return fmt.Errorf("PC config path is required")
 	}
 	return nil
@@ -35,7 +58,20 @@ func (c Config) Validate() error {
 
 // LoadPCConfig loads and returns the PC configuration from the config file
 func (c Config) LoadPCConfig() (*config.Config, error) {
-	return config.LoadConfig(c.ConfigPath)
+	var cfg *config.Config
+	var err error
+	if c.ConfigPath == "" {
+		cfg, err = config.LoadConfigData(c.DefaultConfig, DefaultConfigSource, c.Overrides, false)
+	} else {
+		cfg, err = config.LoadConfigWithOverrides(c.ConfigPath, c.Overrides)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyProfile(c.Profile); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // GetCKANBaseURL returns the CKAN base URL, either from server config or PC config
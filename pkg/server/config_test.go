@@ -36,6 +36,14 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "default config without config path",
+			config: Config{
+				Address:       ":8080",
+				DefaultConfig: []byte("[operation.main]\ncollector = \"LocalCollector\"\n"),
+			},
+			wantErr: false,
+		},
 		{
 			name: "both missing",
 			config: Config{
@@ -145,3 +153,29 @@ func TestConfig_GetVerifyTLS(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultAddress(t *testing.T) {
+	t.Setenv("PORT", "")
+	if addr := DefaultAddress(); addr != ":8080" {
+		t.Errorf("Expected :8080 without PORT, got %s", addr)
+	}
+	t.Setenv("PORT", "9000")
+	if addr := DefaultAddress(); addr != ":9000" {
+		t.Errorf("Expected :9000, got %s", addr)
+	}
+}
+
+func TestConfig_LoadPCConfigDefault(t *testing.T) {
+	cfg := Config{
+		Address:       ":8080",
+		DefaultConfig: []byte("[operation.main]\ncollector = \"LocalCollector\"\n"),
+	}
+	t.Setenv("PC_COLLECTOR", "CkanCollector")
+	pcConfig, err := cfg.LoadPCConfig()
+	if err != nil {
+		t.Fatalf("LoadPCConfig() error = %v", err)
+	}
+	if collector := pcConfig.Operation["main"].Collector; collector != "CkanCollector" {
+		t.Errorf("Expected the collector of PC_COLLECTOR, got %s", collector)
+	}
+}
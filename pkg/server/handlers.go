@@ -1,21 +1,32 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/eawag-rdm/pc/pkg/collectors"
 	"github.com/eawag-rdm/pc/pkg/config"
 	"github.com/eawag-rdm/pc/pkg/helpers"
+	"github.com/eawag-rdm/pc/pkg/history"
+	"github.com/eawag-rdm/pc/pkg/notify"
+	"github.com/eawag-rdm/pc/pkg/output"
 	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+	"github.com/eawag-rdm/pc/pkg/structs"
 	"github.com/eawag-rdm/pc/pkg/utils"
+	"github.com/eawag-rdm/pc/pkg/workspace"
 )
 
 // Handler processes HTTP requests for the PC server
 type Handler struct {
-	pcConfig    *config.Config
-	serverCfg   Config
+	pcConfig  *config.Config
+	serverCfg Config
+	queue     *scanQueue // Packages to scan for notifications of the webhook, see Webhook
 }
 
 // NewHandler creates a new handler with the given configuration
@@ -23,6 +34,7 @@ func NewHandler(pcConfig *config.Config, serverCfg Config) *Handler {
 	return &Handler{
 		pcConfig:  pcConfig,
 		serverCfg: serverCfg,
+		queue:     newScanQueue(webhookQueueSize),
 	}
 }
 
@@ -54,6 +66,42 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Ready handles GET /ready, the readiness probe of container platforms. The server is not ready
+// if the configured scan history directory cannot be written, e.g. because its volume is missing.
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.pcConfig.General != nil && h.pcConfig.General.HistoryDir != "" {
+		if err := checkWritableDir(h.pcConfig.General.HistoryDir); err != nil {
+			respondError(w, http.StatusServiceUnavailable, "history_unavailable", "The scan history directory cannot be written: "+err.Error())
+			return
+		}
+	}
+	respondJSON(w, http.StatusOK, HealthResponse{
+		Status:    "ready",
+		Version:   "1.0.0",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// checkWritableDir creates the directory if needed and a file in it, which is removed again
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".pc-ready-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// Schema handles GET /api/v1/schema, returning the JSON Schema of the analyze results
+func (h *Handler) Schema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonformatter.Schema)
+}
+
 // Analyze handles POST /api/v1/analyze
 func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
 	// 1. Parse request body
@@ -87,27 +135,105 @@ func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 5. Verify CKAN access with the user's token
-	verifyTLS := h.serverCfg.GetVerifyTLS(h.pcConfig)
-	if err := VerifyCKANAccess(ckanURL, req.PackageID, token, verifyTLS); err != nil {
-		if statusCode, isAuthErr := IsCKANAuthError(err); isAuthErr {
-			switch statusCode {
-			case http.StatusUnauthorized:
-				respondError(w, http.StatusUnauthorized, "unauthorized", err.Error())
-			case http.StatusForbidden:
-				respondError(w, http.StatusForbidden, "forbidden", err.Error())
-			case http.StatusNotFound:
-				respondError(w, http.StatusNotFound, "not_found", err.Error())
-			default:
-				respondError(w, http.StatusBadGateway, "ckan_error", err.Error())
-			}
-			return
-		}
-		respondError(w, http.StatusInternalServerError, "ckan_error", "Failed to verify CKAN access: "+err.Error())
+	if !h.verifyAccess(w, ckanURL, req.PackageID, token) {
 		return
 	}
 
-	// 6. Create a copy of PC config with the user's token for collection
-	pcConfigCopy := *h.pcConfig
+	// 6. Scan the package, which stops when the client disconnects
+	jsonResult, err := h.scanPackage(r.Context(), req.PackageID, token, req.CkanURL)
+	var failed *scanError
+	if errors.As(err, &failed) {
+		respondError(w, failed.status, failed.code, failed.message)
+		return
+	} else if err != nil {
+		logger.Warning("Analysis of package '%s' cancelled: %v", req.PackageID, err)
+		return
+	}
+
+	// 7. Return JSON response directly (already formatted)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(jsonResult))
+}
+
+// scanError is a scan that failed, with the status and code of its error response
+type scanError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *scanError) Error() string {
+	return e.message
+}
+
+// scanPackage scans the CKAN package with the token, at ckanURL instead of the configured URL if
+// it is not empty. It sends the notifications, stores the result in the history and returns the
+// JSON report. The scan stops when ctx is cancelled, returning its error.
+func (h *Handler) scanPackage(ctx context.Context, packageID, token, ckanURL string) (string, error) {
+	// The scan lists its own PDF and skipped files, requests are scanned at the same time
+	pcConfigCopy := h.configWithToken(ctx, token, ckanURL).WithPDFTracker(helpers.NewPDFTracker()).WithSkippedFiles(output.NewSkippedFiles())
+
+	// Collect files from CKAN into the workspace of the scan, which is removed with the
+	// downloads once the scan is done
+	ws := pcConfigCopy.NewWorkspace()
+	defer ws.Close()
+	pcConfigCopy, removeDownloads, err := collectors.PrepareDownloads(pcConfigCopy.WithWorkspace(ws))
+	if err != nil {
+		return "", &scanError{http.StatusInternalServerError, "collector_error", "Failed to collect files: " + err.Error()}
+	}
+	defer removeDownloads()
+	pcConfigCopy, files, err := collectors.CkanCollectorWithMetadata(packageID, pcConfigCopy)
+	if errors.Is(err, workspace.ErrDiskLimit) {
+		return "", &scanError{http.StatusInsufficientStorage, "workspace_full", "Failed to collect files: " + err.Error()}
+	} else if err != nil {
+		return "", &scanError{http.StatusInternalServerError, "collector_error", "Failed to collect files: " + err.Error()}
+	}
+
+	// Scans of the metadata only have no files
+	if len(files) == 0 && pcConfigCopy.Metadata() == nil {
+		return "", &scanError{http.StatusNotFound, "no_files", "No files found in package '" + packageID + "'"}
+	}
+
+	// Run checks
+	messages := utils.ApplyAllChecks(pcConfigCopy, files, true)
+	if err := ctx.Err(); err != nil {
+		// Nobody waits for the incomplete result, do not notify or store it
+		return "", err
+	}
+
+	messages = utils.TranslateMessages(messages)
+
+	// Send notifications in the background so the response is not delayed
+	h.sendNotifications(packageID, messages, len(files))
+
+	// Format results as JSON
+	formatter := jsonformatter.NewJSONFormatter()
+	formatter.Skipped = pcConfigCopy.SkippedFiles().Files()
+	if pcConfigCopy.General.ListArchives {
+		formatter.Archives = jsonformatter.ListArchives(pcConfigCopy, files, nil)
+	}
+	if pcConfigCopy.General.FileMetadata || pcConfigCopy.General.HashFiles {
+		formatter.Files = jsonformatter.ListFiles(pcConfigCopy, files, pcConfigCopy.General.HashFiles, nil)
+	}
+	jsonResult, err := formatter.FormatResults(packageID, "CkanCollector", messages, len(files), pcConfigCopy.PDFTracker().Files)
+	if err != nil {
+		return "", &scanError{http.StatusInternalServerError, "format_error", "Failed to format results: " + err.Error()}
+	}
+
+	// Keep the result for later diffs; a failure does not invalidate the scan
+	if store := h.historyStore(); store != nil {
+		if _, err := store.Save(packageID, jsonResult); err != nil {
+			logger.Error("Failed to store scan history for package '%s': %v", packageID, err)
+		}
+	}
+	return jsonResult, nil
+}
+
+// configWithToken returns a copy of the PC config whose CkanCollector uses the token, and
+// ckanURL instead of the configured URL if it is not empty
+func (h *Handler) configWithToken(ctx context.Context, token, ckanURL string) config.Config {
+	pcConfigCopy := h.pcConfig.WithContext(ctx)
 	if ckanCollector, ok := pcConfigCopy.Collectors["CkanCollector"]; ok {
 		// Create a copy of attrs map
 		newAttrs := make(map[string]interface{})
@@ -116,40 +242,143 @@ func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
 		}
 		// Override token and URL
 		newAttrs["token"] = token
-		if req.CkanURL != "" {
-			newAttrs["url"] = req.CkanURL
+		if ckanURL != "" {
+			newAttrs["url"] = ckanURL
+		}
+		// The collectors of the server config are shared by all requests, the copy gets its own
+		collectorCopy := *ckanCollector
+		collectorCopy.Attrs = newAttrs
+		pcConfigCopy.Collectors = make(map[string]*config.CollectorConfig, len(h.pcConfig.Collectors))
+		for name, collector := range h.pcConfig.Collectors {
+			pcConfigCopy.Collectors[name] = collector
 		}
-		ckanCollector.Attrs = newAttrs
-		pcConfigCopy.Collectors["CkanCollector"] = ckanCollector
+		pcConfigCopy.Collectors["CkanCollector"] = &collectorCopy
 	}
+	return pcConfigCopy
+}
 
-	// 7. Collect files from CKAN
-	files, err := collectors.CkanCollector(req.PackageID, pcConfigCopy)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "collector_error", "Failed to collect files: "+err.Error())
+// Diff handles GET /api/v1/diff?package_id=...
+// It compares the two most recent scans of the package stored in the history.
+func (h *Handler) Diff(w http.ResponseWriter, r *http.Request) {
+	packageID := r.URL.Query().Get("package_id")
+	if packageID == "" {
+		respondError(w, http.StatusBadRequest, "missing_package_id", "package_id is required")
 		return
 	}
 
-	if len(files) == 0 {
-		respondError(w, http.StatusNotFound, "no_files", "No files found in package '"+req.PackageID+"'")
+	token := GetTokenFromContext(r)
+	if token == "" {
+		respondError(w, http.StatusUnauthorized, "no_token", "CKAN API token is required")
 		return
 	}
 
-	// 8. Run checks
-	messages := utils.ApplyAllChecks(pcConfigCopy, files, true)
+	store := h.historyStore()
+	if store == nil {
+		respondError(w, http.StatusNotImplemented, "no_history", "Scan history is not configured")
+		return
+	}
 
-	// 9. Format results as JSON
-	formatter := jsonformatter.NewJSONFormatter()
-	jsonResult, err := formatter.FormatResults(req.PackageID, "CkanCollector", messages, len(files), helpers.PDFTracker.Files)
+	// Only users who can see the package may see its scan history
+	ckanURL := h.serverCfg.GetCKANBaseURL(h.pcConfig)
+	if ckanURL == "" {
+		respondError(w, http.StatusInternalServerError, "no_ckan_url", "CKAN URL is not configured")
+		return
+	}
+	if !h.verifyAccess(w, ckanURL, packageID, token) {
+		return
+	}
+
+	diff, err := store.DiffLatest(packageID)
+	if errors.Is(err, history.ErrNoPrevious) {
+		respondError(w, http.StatusNotFound, "no_previous_scan", "Package '"+packageID+"' needs at least two stored scans to compare")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, "history_error", "Failed to compare scans: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, diff)
+}
+
+// Stats handles GET /api/v1/stats, summarizing the stored scans of all packages. The statistics
+// count issues per check and period but name no package, so they need no authentication.
+func (h *Handler) Stats(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period != "" && !slices.Contains(history.Periods, period) {
+		respondError(w, http.StatusBadRequest, "invalid_period", "period must be one of "+strings.Join(history.Periods, ", "))
+		return
+	}
+
+	store := h.historyStore()
+	if store == nil {
+		respondError(w, http.StatusNotImplemented, "no_history", "Scan history is not configured")
+		return
+	}
+	entries, err := store.All()
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "format_error", "Failed to format results: "+err.Error())
+		respondError(w, http.StatusInternalServerError, "history_error", "Failed to read scans: "+err.Error())
 		return
 	}
+	stats, err := history.ComputeStats(entries, period)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "history_error", "Failed to compute statistics: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, stats)
+}
 
-	// 10. Return JSON response directly (already formatted)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(jsonResult))
+// verifyAccess checks the user's access to the package and writes the error response if it is denied
+func (h *Handler) verifyAccess(w http.ResponseWriter, ckanURL, packageID, token string) bool {
+	verifyTLS := h.serverCfg.GetVerifyTLS(h.pcConfig)
+	err := VerifyCKANAccess(ckanURL, packageID, token, verifyTLS)
+	if err == nil {
+		return true
+	}
+	if statusCode, isAuthErr := IsCKANAuthError(err); isAuthErr {
+		switch statusCode {
+		case http.StatusUnauthorized:
+			respondError(w, http.StatusUnauthorized, "unauthorized", err.Error())
+		case http.StatusForbidden:
+			respondError(w, http.StatusForbidden, "forbidden", err.Error())
+		case http.StatusNotFound:
+			respondError(w, http.StatusNotFound, "not_found", err.Error())
+		default:
+			respondError(w, http.StatusBadGateway, "ckan_error", err.Error())
+		}
+		return false
+	}
+	respondError(w, http.StatusInternalServerError, "ckan_error", "Failed to verify CKAN access: "+err.Error())
+	return false
+}
+
+// historyStore returns the scan history store, or nil if no history directory is configured
+func (h *Handler) historyStore() *history.Store {
+	if h.pcConfig.General == nil || h.pcConfig.General.HistoryDir == "" {
+		return nil
+	}
+	store, err := history.NewStore(h.pcConfig.General.HistoryDir)
+	if err != nil {
+		logger.Error("Failed to open scan history: %v", err)
+		return nil
+	}
+	return store
+}
+
+// sendNotifications delivers the scan summary to the configured notifiers, logging any failure
+func (h *Handler) sendNotifications(packageID string, messages []structs.Message, totalFiles int) {
+	notifiers, err := notify.FromConfig(*h.pcConfig)
+	if err != nil {
+		logger.Error("Failed to set up notifiers: %v", err)
+		return
+	}
+	if len(notifiers) == 0 {
+		return
+	}
+	summary := notify.NewSummary(packageID, "CkanCollector", messages, totalFiles)
+	go func() {
+		for _, err := range notify.NotifyAll(notifiers, summary) {
+			logger.Warning("Notification for package '%s' failed: %v", packageID, err)
+		}
+	}()
 }
 
 // Helper functions for JSON responses
@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/history"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
 )
 
 func TestHandler_Health(t *testing.T) {
@@ -42,6 +46,62 @@ func TestHandler_Health(t *testing.T) {
 	}
 }
 
+func TestHandler_Ready(t *testing.T) {
+	historyDir := filepath.Join(t.TempDir(), "history")
+	handler := &Handler{
+		pcConfig:  &config.Config{General: &config.GeneralConfig{HistoryDir: historyDir}},
+		serverCfg: Config{},
+	}
+
+	rr := httptest.NewRecorder()
+	handler.Ready(rr, httptest.NewRequest("GET", "/ready", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// A file in place of the history directory cannot be written to
+	blocked := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(blocked, nil, 0644)
+	handler.pcConfig.General.HistoryDir = blocked
+	rr = httptest.NewRecorder()
+	handler.Ready(rr, httptest.NewRequest("GET", "/ready", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rr.Code)
+	}
+	var response ErrorResponse
+	json.NewDecoder(rr.Body).Decode(&response)
+	if response.Code != "history_unavailable" {
+		t.Errorf("Expected code 'history_unavailable', got '%s'", response.Code)
+	}
+}
+
+func TestHandler_Schema(t *testing.T) {
+	handler := &Handler{
+		pcConfig:  &config.Config{},
+		serverCfg: Config{},
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/schema", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Schema(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/schema+json" {
+		t.Errorf("Expected Content-Type 'application/schema+json', got '%s'", contentType)
+	}
+
+	var schema map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&schema); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+	if _, ok := schema["properties"].(map[string]interface{})["schema_version"]; !ok {
+		t.Error("Expected schema_version in the schema properties")
+	}
+}
+
 func TestHandler_Analyze_MissingPackageID(t *testing.T) {
 	handler := &Handler{
 		pcConfig:  &config.Config{},
@@ -154,6 +214,184 @@ func TestHandler_Analyze_NoCKANURL(t *testing.T) {
 	}
 }
 
+func TestHandler_Analyze_KeepsServerConfig(t *testing.T) {
+	// Access is granted, the package cannot be read
+	ckan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/3/action/package_show" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ckan.Close()
+
+	collector := &config.CollectorConfig{Attrs: map[string]interface{}{"token": "server-token", "url": ckan.URL}}
+	handler := &Handler{
+		pcConfig: &config.Config{
+			General:    &config.GeneralConfig{},
+			Collectors: map[string]*config.CollectorConfig{"CkanCollector": collector},
+		},
+		serverCfg: Config{CKANBaseURL: ckan.URL},
+	}
+
+	body := bytes.NewBufferString(`{"package_id": "test-package"}`)
+	req := httptest.NewRequest("POST", "/api/v1/analyze", body)
+	req = req.WithContext(context.WithValue(req.Context(), CKANTokenKey, "user-token"))
+	handler.Analyze(httptest.NewRecorder(), req)
+
+	// Requests are scanned at the same time, the token of one must not reach the others
+	if handler.pcConfig.Collectors["CkanCollector"] != collector || collector.Attrs["token"] != "server-token" {
+		t.Errorf("Expected the server config to keep its collector, got %+v", handler.pcConfig.Collectors["CkanCollector"])
+	}
+}
+
+func TestHandler_Analyze_SkippedPerRequest(t *testing.T) {
+	// Package "broken" has a resource that cannot be downloaded
+	var ckan *httptest.Server
+	ckan = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/3/action/package_show":
+			names := []string{"data.csv"}
+			if r.URL.Query().Get("id") == "broken" {
+				names = append(names, "missing.csv")
+			}
+			var resources []interface{}
+			for _, name := range names {
+				resources = append(resources, map[string]interface{}{"name": name, "url": ckan.URL + "/download/" + name, "url_type": "upload", "size": 8})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": map[string]interface{}{"resources": resources}})
+		case "/download/data.csv":
+			w.Write([]byte("id,value"))
+		case "/download/missing.csv":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ckan.Close()
+
+	pcConfig, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	pcConfig.Collectors["CkanCollector"].Attrs = map[string]interface{}{"url": ckan.URL, "verify": true, "ckan_storage_path": "", "download": true, "download_dir": t.TempDir()}
+	handler := &Handler{pcConfig: pcConfig, serverCfg: Config{CKANBaseURL: ckan.URL}}
+	analyze := func(packageID string) jsonformatter.ScanResult {
+		req := httptest.NewRequest("POST", "/api/v1/analyze", bytes.NewBufferString(`{"package_id": "`+packageID+`"}`))
+		req = req.WithContext(context.WithValue(req.Context(), CKANTokenKey, "user-token"))
+		rr := httptest.NewRecorder()
+		handler.Analyze(rr, req)
+		var result jsonformatter.ScanResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to decode the report of %s: %v: %s", packageID, err, rr.Body.String())
+		}
+		return result
+	}
+
+	if skipped := analyze("broken").Skipped; len(skipped) != 1 || skipped[0].Filename != "missing.csv" {
+		t.Errorf("Expected the failed download to be skipped, got %+v", skipped)
+	}
+	if skipped := analyze("fine").Skipped; len(skipped) != 0 {
+		t.Errorf("Expected the next request not to report the skipped files of the first, got %+v", skipped)
+	}
+}
+
+func TestHandler_Diff_NoHistory(t *testing.T) {
+	handler := &Handler{
+		pcConfig:  &config.Config{},
+		serverCfg: Config{},
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/diff?package_id=test-package", nil)
+	req = req.WithContext(context.WithValue(req.Context(), CKANTokenKey, "test-token"))
+	rr := httptest.NewRecorder()
+
+	handler.Diff(rr, req)
+
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501, got %d", rr.Code)
+	}
+}
+
+func TestHandler_Diff(t *testing.T) {
+	ckan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ckan.Close()
+
+	historyDir := t.TempDir()
+	handler := &Handler{
+		pcConfig:  &config.Config{General: &config.GeneralConfig{HistoryDir: historyDir}},
+		serverCfg: Config{CKANBaseURL: ckan.URL},
+	}
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/diff?package_id=test-package", nil)
+		req = req.WithContext(context.WithValue(req.Context(), CKANTokenKey, "test-token"))
+		rr := httptest.NewRecorder()
+		handler.Diff(rr, req)
+		return rr
+	}
+
+	// A single scan cannot be compared
+	store, _ := history.NewStore(historyDir)
+	store.Save("test-package", `{"details_check_focused": [{"checkname": "A", "issues": [{"subject": "a.txt", "message": "m"}]}]}`)
+	if rr := request(); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+
+	store.Save("test-package", `{"details_check_focused": []}`)
+	rr := request()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var diff history.Diff
+	if err := json.NewDecoder(rr.Body).Decode(&diff); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(diff.Fixed) != 1 || len(diff.New) != 0 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+}
+
+func TestHandler_Stats(t *testing.T) {
+	historyDir := t.TempDir()
+	handler := &Handler{
+		pcConfig:  &config.Config{General: &config.GeneralConfig{HistoryDir: historyDir}},
+		serverCfg: Config{},
+	}
+	request := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.Stats(rr, httptest.NewRequest("GET", url, nil))
+		return rr
+	}
+
+	store, _ := history.NewStore(historyDir)
+	store.Save("package-a", `{"details_check_focused": [{"checkname": "A", "issues": [{"subject": "a.txt", "message": "m"}, {"subject": "b.txt", "message": "m"}]}]}`)
+	store.Save("package-b", `{"details_check_focused": []}`)
+
+	rr := request("/api/v1/stats?period=day")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var stats history.Stats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if stats.Locations != 2 || stats.Issues != 2 || len(stats.Checks) != 1 || len(stats.Trend) != 1 {
+		t.Errorf("Unexpected statistics: %+v", stats)
+	}
+
+	if rr := request("/api/v1/stats?period=year"); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown period, got %d", rr.Code)
+	}
+	handler.pcConfig = &config.Config{}
+	if rr := request("/api/v1/stats"); rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without history, got %d", rr.Code)
+	}
+}
+
 func TestRespondJSON(t *testing.T) {
 	rr := httptest.NewRecorder()
 
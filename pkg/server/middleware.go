@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/output"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -43,6 +46,28 @@ func ExtractToken(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// ExtractUIToken extracts the CKAN token of the pages of the web UI, from a Bearer token or, as
+// browsers cannot send one, from the password of HTTP basic authentication. Without a token the
+// browser is asked for it; the user name is ignored.
+func ExtractUIToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := ""
+		if _, password, ok := r.BasicAuth(); ok {
+			token = strings.TrimSpace(password)
+		} else if parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2); len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+			token = strings.TrimSpace(parts[1])
+		}
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="pc-server: enter your CKAN API token as password", charset="UTF-8"`)
+			http.Error(w, "Enter your CKAN API token as password to see the scans", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), CKANTokenKey, token)
+		next(w, r.WithContext(ctx))
+	}
+}
+
 // GetTokenFromContext retrieves the CKAN token from the request context
 func GetTokenFromContext(r *http.Request) string {
 	if token, ok := r.Context().Value(CKANTokenKey).(string); ok {
@@ -51,11 +76,28 @@ func GetTokenFromContext(r *http.Request) string {
 	return ""
 }
 
-// LoggingMiddleware logs incoming requests
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// LoggingMiddleware logs incoming requests with their status and duration at the debug level
+// (-verbose). The query is left out, it may name packages.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Simple logging - could be enhanced with timestamps, response time, etc.
-		// For now, keeping it minimal as the user requested simple implementation
-		next.ServeHTTP(w, r)
+		if !output.GlobalLogger.Enabled(output.LevelDebug) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		logger.Debug("%s %s %d %s", r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond))
 	})
 }
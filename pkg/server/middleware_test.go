@@ -1,9 +1,13 @@
 package server
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/output"
 )
 
 func TestExtractToken_Valid(t *testing.T) {
@@ -128,3 +132,57 @@ func TestGetTokenFromContext_NoToken(t *testing.T) {
 		t.Errorf("Expected empty string, got '%s'", token)
 	}
 }
+
+func TestLoggingMiddleware(t *testing.T) {
+	var logged bytes.Buffer
+	output.GlobalLogger.SetWriter(&logged)
+	defer output.GlobalLogger.SetWriter(nil)
+
+	handler := LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	// Requests are only logged at the debug level
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/diff?package_id=secret", nil))
+	if logged.Len() != 0 {
+		t.Errorf("Expected no log at the info level, got %q", logged.String())
+	}
+
+	output.GlobalLogger.SetLevel(output.LevelDebug)
+	defer output.GlobalLogger.SetLevel(output.LevelInfo)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/diff?package_id=secret", nil))
+	line := logged.String()
+	if !strings.Contains(line, "debug   [server] GET /api/v1/diff 404") || strings.Contains(line, "secret") {
+		t.Errorf("Unexpected request log %q", line)
+	}
+}
+
+func TestExtractUIToken(t *testing.T) {
+	handler := ExtractUIToken(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(GetTokenFromContext(r)))
+	})
+
+	// The token is the password of basic authentication, or a Bearer token
+	req := httptest.NewRequest("GET", "/ui", nil)
+	req.SetBasicAuth("curator", "test-token-123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Body.String() != "test-token-123" {
+		t.Errorf("Expected the password as token, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/ui", nil)
+	req.Header.Set("Authorization", "Bearer test-token-456")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Body.String() != "test-token-456" {
+		t.Errorf("Expected the Bearer token, got %q", rr.Body.String())
+	}
+
+	// Browsers are asked for the token
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/ui", nil))
+	if rr.Code != http.StatusUnauthorized || !strings.HasPrefix(rr.Header().Get("WWW-Authenticate"), "Basic ") {
+		t.Errorf("Expected status 401 with a basic authentication challenge, got %d %q", rr.Code, rr.Header().Get("WWW-Authenticate"))
+	}
+}
@@ -2,20 +2,32 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/eawag-rdm/pc/pkg/checks"
 	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/rules"
 )
 
+// logger logs the requests and the life cycle of the server
+var logger = output.GlobalLogger.Component("server")
+
 // Server wraps the HTTP server with PC functionality
 type Server struct {
 	httpServer *http.Server
 	pcConfig   *config.Config
 	serverCfg  Config
 	handler    *Handler
+
+	stopScans context.CancelFunc // Stops the scans queued by the webhook
 }
 
 // New creates a new server instance
@@ -30,6 +42,18 @@ func New(cfg Config) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load PC config: %w", err)
 	}
+	if _, err := rules.Load(*pcConfig); err != nil {
+		return nil, fmt.Errorf("failed to load PC config: %w", err)
+	}
+	checks.SetSeverities(*pcConfig)
+	if pcConfig.General != nil {
+		if err := i18n.SetLanguage(pcConfig.General.Language); err != nil {
+			return nil, fmt.Errorf("failed to load PC config: %w", err)
+		}
+		if err := i18n.SetTimezone(pcConfig.General.Timezone); err != nil {
+			return nil, fmt.Errorf("failed to load PC config: %w", err)
+		}
+	}
 
 	// Create handler
 	handler := NewHandler(pcConfig, cfg)
@@ -40,12 +64,37 @@ func New(cfg Config) (*Server, error) {
 	// Health endpoint (no auth required)
 	mux.HandleFunc("GET /health", handler.Health)
 
+	// Readiness probe (no auth required)
+	mux.HandleFunc("GET /ready", handler.Ready)
+
+	// JSON Schema of the analyze results (no auth required)
+	mux.HandleFunc("GET /api/v1/schema", handler.Schema)
+
 	// Analyze endpoint (auth required - token extraction middleware)
 	mux.HandleFunc("POST /api/v1/analyze", ExtractToken(handler.Analyze))
 
+	// Diff of the two most recent scans of a package (auth required)
+	mux.HandleFunc("GET /api/v1/diff", ExtractToken(handler.Diff))
+
+	// Statistics of the stored scans of all packages (no auth required, no package is named)
+	mux.HandleFunc("GET /api/v1/stats", handler.Stats)
+
+	// Web UI listing the stored scans and showing their HTML reports (auth required, the browser
+	// asks for the token)
+	mux.HandleFunc("GET /ui", ExtractUIToken(handler.UI))
+	mux.HandleFunc("GET /ui/report", ExtractUIToken(handler.UIReport))
+
+	// CKAN webhook queueing scans of changed packages (authenticated by the signature of the
+	// payload with the shared secret)
+	mux.HandleFunc("POST /api/v1/webhook", handler.Webhook)
+
 	// Wrap with logging middleware
 	loggedMux := LoggingMiddleware(mux)
 
+	// Scan the packages queued by the webhook in the background
+	scansCtx, stopScans := context.WithCancel(context.Background())
+	go handler.queue.run(scansCtx, handler.webhookScan)
+
 	return &Server{
 		httpServer: &http.Server{
 			Addr:         cfg.Address,
@@ -57,23 +106,59 @@ func New(cfg Config) (*Server, error) {
 		pcConfig:  pcConfig,
 		serverCfg: cfg,
 		handler:   handler,
+		stopScans: stopScans,
 	}, nil
 }
 
 // ListenAndServe starts the HTTP server
 func (s *Server) ListenAndServe() error {
-	log.Printf("PC Server starting on %s", s.serverCfg.Address)
-	log.Printf("PC Config loaded from: %s", s.serverCfg.ConfigPath)
+	logger.Info("PC Server starting on %s", s.serverCfg.Address)
+	if s.serverCfg.ConfigPath != "" {
+		logger.Info("PC Config loaded from: %s", s.serverCfg.ConfigPath)
+	} else {
+		logger.Info("PC Config: %s", DefaultConfigSource)
+	}
 
 	ckanURL := s.serverCfg.GetCKANBaseURL(s.pcConfig)
 	if ckanURL != "" {
-		log.Printf("CKAN URL: %s", ckanURL)
+		logger.Info("CKAN URL: %s", ckanURL)
 	}
 
 	return s.httpServer.ListenAndServe()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server; scans queued by the webhook are cancelled
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopScans()
 	return s.httpServer.Shutdown(ctx)
 }
+
+// Run serves until SIGINT or SIGTERM is received and then shuts down gracefully
+func (s *Server) Run() error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-quit:
+		logger.Info("Server is shutting down...")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		return fmt.Errorf("could not gracefully shutdown the server: %w", err)
+	}
+	logger.Info("Server stopped")
+	return nil
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/history"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	htmlformatter "github.com/eawag-rdm/pc/pkg/output/html"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// uiRecentScans is the number of scans listed by the web UI
+const uiRecentScans = 50
+
+// uiScan is a stored scan listed by the web UI
+type uiScan struct {
+	Package   string
+	Time      string
+	ReportURL string
+	Errors    int
+	Warnings  int
+	Info      int
+}
+
+// UI handles GET /ui, a page listing the most recent stored scans of the packages the user can
+// see, newest first, with links to their HTML reports
+func (h *Handler) UI(w http.ResponseWriter, r *http.Request) {
+	store := h.historyStore()
+	if store == nil {
+		http.Error(w, "Scan history is not configured, set historyDir in the [general] section", http.StatusNotImplemented)
+		return
+	}
+	ckanURL := h.serverCfg.GetCKANBaseURL(h.pcConfig)
+	if ckanURL == "" {
+		http.Error(w, "CKAN URL is not configured", http.StatusInternalServerError)
+		return
+	}
+	entries, err := store.All()
+	if err != nil {
+		http.Error(w, "Failed to read scans: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+
+	// Only the packages the user can see are listed, each is checked once with CKAN
+	token := GetTokenFromContext(r)
+	verifyTLS := h.serverCfg.GetVerifyTLS(h.pcConfig)
+	visible := map[string]bool{}
+	var scans []uiScan
+	for _, entry := range entries {
+		if len(scans) == uiRecentScans {
+			break
+		}
+		allowed, checked := visible[entry.Location]
+		if !checked {
+			allowed = VerifyCKANAccess(ckanURL, entry.Location, token, verifyTLS) == nil
+			visible[entry.Location] = allowed
+		}
+		if !allowed {
+			continue
+		}
+		scan := uiScan{
+			Package:   entry.Location,
+			Time:      i18n.FormatTime(entry.Timestamp),
+			ReportURL: "ui/report?" + url.Values{"package_id": {entry.Location}, "scan": {entry.Timestamp.Format(time.RFC3339Nano)}}.Encode(),
+		}
+		scan.Errors, scan.Warnings, scan.Info = countIssues(entry.Report)
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	data := struct {
+		Scans  []uiScan
+		Recent int
+	}{scans, uiRecentScans}
+	if err := uiTemplate.Execute(w, data); err != nil {
+		logger.Error("Failed to render the scan list: %v", err)
+	}
+}
+
+// UIReport handles GET /ui/report?package_id=...&scan=..., the HTML report of a stored scan of
+// the package; without scan, of the latest one
+func (h *Handler) UIReport(w http.ResponseWriter, r *http.Request) {
+	packageID := r.URL.Query().Get("package_id")
+	if packageID == "" {
+		http.Error(w, "package_id is required", http.StatusBadRequest)
+		return
+	}
+	store := h.historyStore()
+	if store == nil {
+		http.Error(w, "Scan history is not configured, set historyDir in the [general] section", http.StatusNotImplemented)
+		return
+	}
+	ckanURL := h.serverCfg.GetCKANBaseURL(h.pcConfig)
+	if ckanURL == "" {
+		http.Error(w, "CKAN URL is not configured", http.StatusInternalServerError)
+		return
+	}
+	if !h.verifyAccess(w, ckanURL, packageID, GetTokenFromContext(r)) {
+		return
+	}
+
+	entries, err := store.List(packageID)
+	if err != nil {
+		http.Error(w, "Failed to read scans: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var entry *history.Entry
+	if scan := r.URL.Query().Get("scan"); scan == "" {
+		if len(entries) > 0 {
+			entry = entries[len(entries)-1]
+		}
+	} else {
+		timestamp, err := time.Parse(time.RFC3339Nano, scan)
+		if err != nil {
+			http.Error(w, "scan must be the timestamp of a scan, e.g. 2024-07-01T12:30:00Z", http.StatusBadRequest)
+			return
+		}
+		for _, candidate := range entries {
+			if candidate.Timestamp.Equal(timestamp) {
+				entry = candidate
+			}
+		}
+	}
+	if entry == nil {
+		http.Error(w, "No stored scan of package '"+packageID+"' found", http.StatusNotFound)
+		return
+	}
+
+	formatter, err := htmlformatter.NewHTMLFormatterWithOptions(htmlformatter.Options{Title: packageID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page, err := formatter.SelfContained(string(entry.Report))
+	if err != nil {
+		http.Error(w, "Failed to render the report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(page)
+}
+
+// countIssues counts the issues of a JSON report by the severity of their checks
+func countIssues(report []byte) (errors, warnings, info int) {
+	var result jsonformatter.ScanResult
+	if err := json.Unmarshal(report, &result); err != nil {
+		return 0, 0, 0
+	}
+	for _, check := range result.DetailsCheckFocused {
+		switch check.Severity {
+		case "warning":
+			warnings += len(check.Issues)
+		case "info":
+			info += len(check.Issues)
+		default:
+			errors += len(check.Issues)
+		}
+	}
+	return errors, warnings, info
+}
+
+// uiTemplate is the page of GET /ui. The links are relative, so the UI also works behind a
+// reverse proxy serving pc-server below a path.
+var uiTemplate = template.Must(template.New("ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Package Checker Scans</title>
+    <style>
+        :root {
+            --primary-color: #035C77;
+            --warning-color: #b45309;
+            --error-color: #dc2626;
+            --surface-color: #f8fafc;
+            --text-color: #1e293b;
+            --text-secondary: #64748b;
+            --border-color: #e2e8f0;
+        }
+
+        body {
+            margin: 0;
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            color: var(--text-color);
+            font-size: 13px;
+        }
+
+        header {
+            padding: 12px 20px;
+            background: var(--surface-color);
+            border-bottom: 1px solid var(--border-color);
+        }
+
+        h1 {
+            margin: 0;
+            color: var(--primary-color);
+            font-size: 1.5rem;
+            font-weight: 600;
+        }
+
+        main {
+            padding: 15px 20px;
+        }
+
+        .hint {
+            color: var(--text-secondary);
+        }
+
+        table {
+            border-collapse: collapse;
+            width: 100%;
+            margin-top: 10px;
+        }
+
+        th, td {
+            text-align: left;
+            padding: 6px 10px;
+            border-bottom: 1px solid var(--border-color);
+        }
+
+        th {
+            background: var(--surface-color);
+        }
+
+        td.count {
+            text-align: right;
+            font-variant-numeric: tabular-nums;
+        }
+
+        .errors {
+            color: var(--error-color);
+            font-weight: 600;
+        }
+
+        .warnings {
+            color: var(--warning-color);
+        }
+
+        a {
+            color: var(--primary-color);
+        }
+    </style>
+</head>
+<body>
+    <header>
+        <h1>Package Checker Scans</h1>
+    </header>
+    <main>
+        {{if .Scans}}
+        <p class="hint">The most recent scans of the packages you can see in CKAN, newest first (at most {{.Recent}}).</p>
+        <table>
+            <thead>
+                <tr><th scope="col">Package</th><th scope="col">Scanned</th><th scope="col">Errors</th><th scope="col">Warnings</th><th scope="col">Info</th><th scope="col">Report</th></tr>
+            </thead>
+            <tbody>
+                {{range .Scans}}
+                <tr>
+                    <td>{{.Package}}</td>
+                    <td>{{.Time}}</td>
+                    <td class="count{{if .Errors}} errors{{end}}">{{.Errors}}</td>
+                    <td class="count{{if .Warnings}} warnings{{end}}">{{.Warnings}}</td>
+                    <td class="count">{{.Info}}</td>
+                    <td><a href="{{.ReportURL}}">Open report</a></td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{else}}
+        <p class="hint">No scans of the packages you can see in CKAN are stored yet.</p>
+        {{end}}
+    </main>
+</body>
+</html>
+`))
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/history"
+)
+
+func TestHandler_UI(t *testing.T) {
+	ckan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "visible-package" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ckan.Close()
+
+	historyDir := t.TempDir()
+	handler := &Handler{
+		pcConfig:  &config.Config{General: &config.GeneralConfig{HistoryDir: historyDir}},
+		serverCfg: Config{CKANBaseURL: ckan.URL},
+	}
+	store, _ := history.NewStore(historyDir)
+	store.Save("visible-package", `{"details_check_focused": []}`)
+	entry, _ := store.Save("visible-package", `{"details_check_focused": [{"checkname": "A", "severity": "warning", "issues": [{"subject": "a.txt", "message": "m"}]}]}`)
+	store.Save("private-package", `{"details_check_focused": []}`)
+
+	request := func(handle http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), CKANTokenKey, "test-token"))
+		rr := httptest.NewRecorder()
+		handle(rr, req)
+		return rr
+	}
+
+	rr := request(handler.UI, "/ui")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	page := rr.Body.String()
+	if strings.Count(page, "<td>visible-package</td>") != 2 {
+		t.Errorf("Expected both scans of the visible package, got:\n%s", page)
+	}
+	if strings.Contains(page, "private-package") {
+		t.Error("Scans of packages the user cannot see must not be listed")
+	}
+	scan := entry.Timestamp.Format(time.RFC3339Nano)
+	if !strings.Contains(page, "ui/report?package_id=visible-package&amp;scan="+strings.ReplaceAll(scan, ":", "%3A")) {
+		t.Errorf("Expected a link to the report of the latest scan, got:\n%s", page)
+	}
+
+	rr = request(handler.UIReport, "/ui/report?package_id=visible-package&scan="+scan)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/html") || !strings.Contains(rr.Body.String(), `"checkname":"A"`) {
+		t.Error("Expected the HTML report of the scan")
+	}
+
+	if rr := request(handler.UIReport, "/ui/report?package_id=private-package"); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a package the user cannot see, got %d", rr.Code)
+	}
+	if rr := request(handler.UIReport, "/ui/report?package_id=visible-package&scan=2000-01-01T00:00:00Z"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown scan, got %d", rr.Code)
+	}
+}
+
+func TestHandler_UI_NoHistory(t *testing.T) {
+	handler := &Handler{
+		pcConfig:  &config.Config{},
+		serverCfg: Config{CKANBaseURL: "http://ckan.example.org"},
+	}
+	rr := httptest.NewRecorder()
+	handler.UI(rr, httptest.NewRequest("GET", "/ui", nil))
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501, got %d", rr.Code)
+	}
+}
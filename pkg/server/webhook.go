@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/eawag-rdm/pc/pkg/collectors"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 of the webhook payload with the shared secret,
+// as "sha256=<hex>"
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+const (
+	// maxWebhookPayload is the size of the largest webhook payload read (bytes)
+	maxWebhookPayload = 1 << 20
+	// webhookQueueSize is the number of packages waiting for their scan before notifications
+	// are rejected
+	webhookQueueSize = 100
+)
+
+// webhookTopics are the CKAN events that scan the package of the resource
+var webhookTopics = []string{"resource/create", "resource/update"}
+
+var (
+	errScanPending = errors.New("the scan of the package is pending")
+	errQueueFull   = errors.New("too many scans are pending")
+)
+
+// WebhookPayload is the notification of CKAN about a changed resource, e.g. sent by
+// ckanext-webhooks
+type WebhookPayload struct {
+	Topic  string `json:"topic"` // "resource/create" or "resource/update", other topics are ignored
+	Entity struct {
+		ID        string `json:"id"`
+		PackageID string `json:"package_id"`
+	} `json:"entity"` // The resource
+}
+
+// WebhookResponse tells CKAN whether the package of the notification is scanned
+type WebhookResponse struct {
+	Status    string `json:"status"` // "queued", "pending" if it is queued already, or "ignored"
+	PackageID string `json:"package_id,omitempty"`
+	Reason    string `json:"reason,omitempty"` // Why the notification is ignored
+}
+
+// Webhook handles POST /api/v1/webhook, queueing a scan of the package whose resource was created
+// or updated. The scan runs in the background with the token of the server config; its result
+// is stored in the history and notified like those of /api/v1/analyze.
+func (h *Handler) Webhook(w http.ResponseWriter, r *http.Request) {
+	secret, _, _ := h.webhookSettings()
+	if secret == "" {
+		respondError(w, http.StatusNotImplemented, "no_webhook_secret", "The webhook is disabled, set webhook_secret of the CkanCollector to enable it")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "Failed to read the payload: "+err.Error())
+		return
+	}
+	if len(body) > maxWebhookPayload {
+		respondError(w, http.StatusRequestEntityTooLarge, "payload_too_large", "The payload is larger than 1 MiB")
+		return
+	}
+	if !validSignature(body, r.Header.Get(WebhookSignatureHeader), secret) {
+		respondError(w, http.StatusUnauthorized, "invalid_signature", "The "+WebhookSignatureHeader+" header does not match the payload")
+		return
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body: "+err.Error())
+		return
+	}
+	if !slices.Contains(webhookTopics, payload.Topic) {
+		respondJSON(w, http.StatusOK, WebhookResponse{Status: "ignored", Reason: "topic '" + payload.Topic + "' does not change a resource, expected " + strings.Join(webhookTopics, " or ")})
+		return
+	}
+	packageID := payload.Entity.PackageID
+	if packageID == "" {
+		respondError(w, http.StatusBadRequest, "missing_package_id", "entity.package_id is required")
+		return
+	}
+
+	switch err := h.queue.enqueue(packageID); {
+	case errors.Is(err, errScanPending):
+		respondJSON(w, http.StatusAccepted, WebhookResponse{Status: "pending", PackageID: packageID})
+	case errors.Is(err, errQueueFull):
+		respondError(w, http.StatusServiceUnavailable, "queue_full", "Too many scans are pending, try again later")
+	default:
+		logger.Info("Scan of package '%s' queued by the webhook (%s)", packageID, payload.Topic)
+		respondJSON(w, http.StatusAccepted, WebhookResponse{Status: "queued", PackageID: packageID})
+	}
+}
+
+// validSignature reports whether signature is "sha256=" followed by the hex HMAC-SHA256 of the
+// body with the secret
+func validSignature(body []byte, signature, secret string) bool {
+	digest, ok := strings.CutPrefix(strings.TrimSpace(signature), "sha256=")
+	if !ok {
+		return false
+	}
+	received, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+// webhookSettings returns the shared secret of the webhook and the organizations whose packages
+// are scanned (all if empty) and never scanned, from the attrs of the CkanCollector
+func (h *Handler) webhookSettings() (string, []string, []string) {
+	ckanCollector, ok := h.pcConfig.Collectors["CkanCollector"]
+	if !ok {
+		return "", nil, nil
+	}
+	secret, _ := ckanCollector.Attrs["webhook_secret"].(string)
+	enabled, _ := ckanCollector.Attrs["webhook_organizations"].([]string)
+	disabled, _ := ckanCollector.Attrs["webhook_disabled_organizations"].([]string)
+	return strings.TrimSpace(secret), enabled, disabled
+}
+
+// organizationEnabled reports whether packages of the organization, known by its id and name,
+// are scanned for webhook notifications
+func organizationEnabled(id, name string, enabled, disabled []string) bool {
+	listed := func(organizations []string) bool {
+		return (id != "" && slices.Contains(organizations, id)) || (name != "" && slices.Contains(organizations, name))
+	}
+	if listed(disabled) {
+		return false
+	}
+	return len(enabled) == 0 || listed(enabled)
+}
+
+// webhookScan scans a package queued by the webhook with the token of the server config, unless
+// its organization is disabled
+func (h *Handler) webhookScan(ctx context.Context, packageID string) {
+	token := ""
+	if ckanCollector, ok := h.pcConfig.Collectors["CkanCollector"]; ok {
+		token, _ = ckanCollector.Attrs["token"].(string)
+	}
+
+	_, enabled, disabled := h.webhookSettings()
+	if len(enabled) > 0 || len(disabled) > 0 {
+		id, name, err := collectors.PackageOrganization(packageID, h.configWithToken(ctx, token, ""))
+		if err != nil {
+			logger.Warning("Webhook scan of package '%s' skipped, its organization is unknown: %v", packageID, err)
+			return
+		}
+		if !organizationEnabled(id, name, enabled, disabled) {
+			logger.Info("Webhook scan of package '%s' skipped, organization '%s' is not enabled", packageID, name)
+			return
+		}
+	}
+
+	if _, err := h.scanPackage(ctx, packageID, token, ""); err != nil {
+		logger.Error("Webhook scan of package '%s' failed: %v", packageID, err)
+		return
+	}
+	logger.Info("Webhook scan of package '%s' done", packageID)
+}
+
+// scanQueue holds the packages to scan for webhook notifications. A package changed again before
+// its scan starts is scanned once.
+type scanQueue struct {
+	mu      sync.Mutex
+	pending map[string]bool
+	jobs    chan string
+}
+
+func newScanQueue(size int) *scanQueue {
+	return &scanQueue{pending: map[string]bool{}, jobs: make(chan string, size)}
+}
+
+// enqueue adds the package, errScanPending if it is queued already and errQueueFull if the
+// queue is full
+func (q *scanQueue) enqueue(packageID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending[packageID] {
+		return errScanPending
+	}
+	select {
+	case q.jobs <- packageID:
+		q.pending[packageID] = true
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+// run scans the queued packages one after another until ctx is cancelled
+func (q *scanQueue) run(ctx context.Context, scan func(ctx context.Context, packageID string)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case packageID := <-q.jobs:
+			// Changes during the scan queue the package again
+			q.mu.Lock()
+			delete(q.pending, packageID)
+			q.mu.Unlock()
+			scan(ctx, packageID)
+		}
+	}
+}
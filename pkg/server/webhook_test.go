@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+)
+
+func webhookHandler(attrs map[string]interface{}) *Handler {
+	pcConfig := &config.Config{
+		General:    &config.GeneralConfig{},
+		Collectors: map[string]*config.CollectorConfig{"CkanCollector": {Attrs: attrs}},
+	}
+	return NewHandler(pcConfig, Config{})
+}
+
+func sendWebhook(handler *Handler, payload, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/webhook", bytes.NewBufferString(payload))
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(payload))
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	rr := httptest.NewRecorder()
+	handler.Webhook(rr, req)
+	return rr
+}
+
+func TestHandler_Webhook(t *testing.T) {
+	handler := webhookHandler(map[string]interface{}{"webhook_secret": "s3cret"})
+	payload := `{"topic": "resource/update", "entity": {"id": "r1", "package_id": "lake-ice"}}`
+
+	tests := []struct {
+		name    string
+		payload string
+		secret  string
+		status  int
+		result  string // Status of the response, or code of the error
+	}{
+		{"unsigned", payload, "", http.StatusUnauthorized, "invalid_signature"},
+		{"wrong secret", payload, "guess", http.StatusUnauthorized, "invalid_signature"},
+		{"other topic", `{"topic": "dataset/delete", "entity": {"id": "lake-ice"}}`, "s3cret", http.StatusOK, "ignored"},
+		{"no package", `{"topic": "resource/create", "entity": {"id": "r1"}}`, "s3cret", http.StatusBadRequest, "missing_package_id"},
+		{"queued", payload, "s3cret", http.StatusAccepted, "queued"},
+		{"queued again", payload, "s3cret", http.StatusAccepted, "pending"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := sendWebhook(handler, tt.payload, tt.secret)
+			if rr.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+			var response struct {
+				Status string `json:"status"`
+				Code   string `json:"code"`
+			}
+			json.NewDecoder(rr.Body).Decode(&response)
+			if response.Status != tt.result && response.Code != tt.result {
+				t.Errorf("Expected %q, got %+v", tt.result, response)
+			}
+		})
+	}
+	if len(handler.queue.jobs) != 1 {
+		t.Errorf("Expected the package to be queued once, got %d scans", len(handler.queue.jobs))
+	}
+}
+
+func TestHandler_Webhook_Disabled(t *testing.T) {
+	rr := sendWebhook(webhookHandler(map[string]interface{}{}), `{}`, "")
+	if rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without a secret, got %d", rr.Code)
+	}
+}
+
+func TestScanQueue(t *testing.T) {
+	queue := newScanQueue(1)
+	if err := queue.enqueue("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.enqueue("a"); err != errScanPending {
+		t.Errorf("Expected errScanPending, got %v", err)
+	}
+	if err := queue.enqueue("b"); err != errQueueFull {
+		t.Errorf("Expected errQueueFull, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scanned := make(chan string)
+	go queue.run(ctx, func(ctx context.Context, packageID string) {
+		scanned <- packageID
+	})
+	defer cancel()
+	select {
+	case packageID := <-scanned:
+		if packageID != "a" {
+			t.Errorf("Expected package a to be scanned, got %s", packageID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The queued package was not scanned")
+	}
+	// Once its scan started, the package can be queued again
+	if err := queue.enqueue("a"); err != nil {
+		t.Errorf("Expected the package to be queued again, got %v", err)
+	}
+}
+
+func TestOrganizationEnabled(t *testing.T) {
+	tests := []struct {
+		enabled, disabled []string
+		expected          bool
+	}{
+		{nil, nil, true},
+		{[]string{"limnology"}, nil, true},
+		{[]string{"5f3c2a9e"}, nil, true},
+		{[]string{"fish-ecology"}, nil, false},
+		{nil, []string{"limnology"}, false},
+		{[]string{"limnology"}, []string{"5f3c2a9e"}, false},
+	}
+	for _, tt := range tests {
+		if got := organizationEnabled("5f3c2a9e", "limnology", tt.enabled, tt.disabled); got != tt.expected {
+			t.Errorf("organizationEnabled(%v, %v) = %v, expected %v", tt.enabled, tt.disabled, got, tt.expected)
+		}
+	}
+}
+
+func TestWebhookScan_DisabledOrganization(t *testing.T) {
+	ckan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success": true, "result": {"name": "lake-ice", "owner_org": "5f3c2a9e", "organization": {"name": "limnology"}, "resources": []}}`))
+	}))
+	defer ckan.Close()
+
+	historyDir := t.TempDir()
+	handler := webhookHandler(map[string]interface{}{
+		"url":                            ckan.URL,
+		"token":                          "server-token",
+		"verify":                         true,
+		"metadata":                       "only",
+		"webhook_secret":                 "s3cret",
+		"webhook_disabled_organizations": []string{"limnology"},
+	})
+	handler.pcConfig.General.HistoryDir = historyDir
+
+	handler.webhookScan(context.Background(), "lake-ice")
+	if entries, _ := os.ReadDir(historyDir); len(entries) != 0 {
+		t.Errorf("Expected no scan of a package of a disabled organization, got %d", len(entries))
+	}
+
+	// The metadata of the package is scanned once its organization is enabled
+	handler.pcConfig.Collectors["CkanCollector"].Attrs["webhook_organizations"] = []string{"limnology"}
+	delete(handler.pcConfig.Collectors["CkanCollector"].Attrs, "webhook_disabled_organizations")
+	handler.webhookScan(context.Background(), "lake-ice")
+	if entries, _ := os.ReadDir(historyDir); len(entries) != 1 {
+		t.Errorf("Expected the scan of the package to be stored, got %d", len(entries))
+	}
+}
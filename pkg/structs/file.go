@@ -38,7 +38,7 @@ func ToFileWithDisplay(fpath string, name string, displayName string, size int64
 		panic("file path cannot be empty")
 	}
 	if name == "" {
-		name = path.Base(fpath)
+		name = filepath.Base(fpath)
 	}
 	if displayName == "" {
 		displayName = name
@@ -51,7 +51,7 @@ func ToFileWithDisplay(fpath string, name string, displayName string, size int64
 	}
 	isArchive := false
 	ext := path.Ext(name)
-	if ext == ".zip" || ext == ".tar" || ext == ".gz" || ext == ".7z" {
+	if ext == ".zip" || ext == ".tar" || ext == ".gz" || ext == ".bz2" || ext == ".xz" || ext == ".zst" || ext == ".7z" {
 		isArchive = true
 	}
 	return File{
@@ -1,6 +1,7 @@
 package structs
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -40,6 +41,13 @@ func TestNewFile(t *testing.T) {
 			suffix: "",
 			want:   File{Path: "/path/to/file.zip", Name: "file.zip", Size: 0, Suffix: ".zip", IsArchive: true},
 		},
+		{
+			fpath:  "/path/to/reads.tar.zst",
+			name:   "reads.tar.zst",
+			size:   0,
+			suffix: "",
+			want:   File{Path: "/path/to/reads.tar.zst", Name: "reads.tar.zst", Size: 0, Suffix: ".zst", IsArchive: true},
+		},
 	}
 
 	for _, tt := range tests {
@@ -49,3 +57,12 @@ func TestNewFile(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFileWindowsPath(t *testing.T) {
+	// Paths use the separator of the platform, backslashes on Windows
+	fpath := filepath.Join("C:", "Users", "data", "table.csv")
+	got := ToFile(fpath, "", 0, "")
+	if got.Name != "table.csv" || got.Suffix != ".csv" {
+		t.Errorf("ToFile(%q) = %+v; want the name table.csv", fpath, got)
+	}
+}
@@ -1,5 +1,15 @@
 package structs
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/i18n"
+)
+
 type Source interface {
 	GetValue() []File
 }
@@ -20,21 +30,119 @@ type Message struct {
 	Source Source
 	// The test name that generated this message.
 	TestName string
+	// The line of the file the issue was found on, 0 if unknown.
+	Line int
+	// The text around the issue, with the finding marked »like this«.
+	Snippet string
+	// The number of identical findings merged into this message, 0 for a single finding.
+	Count int
+	// The lines of the merged findings, Line is the first of them.
+	Lines []int
+	// The number of further findings the check stopped reporting after this one, see maxFindingsPerCheck.
+	Omitted int
+}
+
+// maxLocationLines is the number of lines listed by Location
+const maxLocationLines = 10
+
+// Location describes where in the file the issue was found, e.g. "line 3: password = »secret«"
+// or "4 occurrences, lines 3, 8, 12, 20: password = »secret«"
+func (m Message) Location() string {
+	var parts []string
+	if m.Count > 1 {
+		parts = append(parts, i18n.Tf("%d occurrences", m.Count))
+	}
+	switch {
+	case len(m.Lines) > 1:
+		lines := make([]string, 0, maxLocationLines)
+		for i, line := range m.Lines {
+			if i == maxLocationLines {
+				lines = append(lines, "...")
+				break
+			}
+			lines = append(lines, strconv.Itoa(line))
+		}
+		parts = append(parts, i18n.Tf("lines %s", strings.Join(lines, ", ")))
+	case m.Line > 0:
+		parts = append(parts, i18n.Tf("line %d", m.Line))
+	}
+
+	location := strings.Join(parts, ", ")
+	if m.Snippet != "" {
+		if location != "" {
+			location += ": "
+		}
+		location += m.Snippet
+	}
+	return location
 }
 
 // define a method for displaying the message
 func (m Message) Format() string {
+	content := m.Content
+	if location := m.Location(); location != "" {
+		content += " (" + location + ")"
+	}
 	switch m.Source.(type) {
 	case File:
 		file := m.Source.(File)
 		displayName := file.GetDisplayName()
 		if file.ArchiveName != "" {
-			return "- File issue in '" + file.ArchiveName + " > " + displayName + "': " + m.Content
+			return "- File issue in '" + file.ArchiveName + " > " + displayName + "': " + content
 		}
-		return "- File issue in '" + displayName + "': " + m.Content
+		return "- File issue in '" + displayName + "': " + content
 	case Repository:
-		return "- Repository issue: " + m.Content
+		return "- Repository issue: " + content
+	case Metadata:
+		return "- Metadata issue: " + content
 	default:
-		return "- Unknown source issue: " + m.Content
+		return "- Unknown source issue: " + content
+	}
+}
+
+// IssueID returns the stable ID of a finding: a hash of its check, path, archive and message. The
+// path is relative to the scanned package, see RelativePath. The message is compared in English
+// with its white space collapsed, so the ID is the same in every scan and language that reports
+// the finding, also when its line moves. Findings of the whole package have no path and are
+// identified by their subject, "repository" or "metadata", instead.
+func IssueID(checkname, path, archiveName, message string) string {
+	normalized := strings.Join(strings.Fields(i18n.ToEnglish(message)), " ")
+	sum := sha256.Sum256([]byte(checkname + "\x00" + path + "\x00" + archiveName + "\x00" + normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RelativePath returns path relative to root, the folder of the scanned package, with slashes, so
+// a finding has the same ID however the location was given, e.g. "data", "./data" or "/srv/data".
+// Paths outside root, and all paths if root is empty, are returned as they are.
+func RelativePath(root, path string) string {
+	if root == "" || path == "" {
+		return path
+	}
+	base, target := root, path
+	if filepath.IsAbs(base) != filepath.IsAbs(target) {
+		var err error
+		if base, err = filepath.Abs(base); err != nil {
+			return path
+		}
+		if target, err = filepath.Abs(target); err != nil {
+			return path
+		}
+	}
+	relative, err := filepath.Rel(base, target)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(relative)
+}
+
+// ID returns the stable ID of the finding of the message in the package scanned at root, see
+// IssueID
+func (m Message) ID(root string) string {
+	switch source := m.Source.(type) {
+	case File:
+		return IssueID(m.TestName, RelativePath(root, source.Path), source.ArchiveName, m.Content)
+	case Metadata:
+		return IssueID(m.TestName, "metadata", "", m.Content)
 	}
+	return IssueID(m.TestName, "repository", "", m.Content)
 }
@@ -1,6 +1,8 @@
 package structs
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -43,6 +45,48 @@ func TestMessage_Format_RepositorySource(t *testing.T) {
 	}
 }
 
+func TestMessage_Format_MetadataSource(t *testing.T) {
+	message := Message{
+		Content: "The package has no license.",
+		Source:  Metadata{Package: "lake-ice"},
+	}
+
+	formatted := message.Format()
+	expected := "- Metadata issue: The package has no license."
+
+	if formatted != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, formatted)
+	}
+}
+
+func TestMessage_Format_WithLocation(t *testing.T) {
+	message := Message{
+		Content: "Security credentials detected 'password'",
+		Source:  File{Name: "settings.py"},
+		Line:    12,
+		Snippet: "db_»password« = 'x'",
+	}
+
+	formatted := message.Format()
+	expected := "- File issue in 'settings.py': Security credentials detected 'password' (line 12: db_»password« = 'x')"
+	if formatted != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, formatted)
+	}
+
+	message.Snippet = ""
+	if location := message.Location(); location != "line 12" {
+		t.Errorf("Expected 'line 12', got '%s'", location)
+	}
+}
+
+func TestMessage_Location_Merged(t *testing.T) {
+	message := Message{Line: 1, Lines: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, Count: 12, Snippet: "»token«"}
+	expected := "12 occurrences, lines 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, ...: »token«"
+	if location := message.Location(); location != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, location)
+	}
+}
+
 // CustomSource is a test type that implements Source interface
 type CustomSource struct{}
 
@@ -231,6 +275,89 @@ func TestMessage_ComplexScenarios(t *testing.T) {
 	}
 }
 
+func TestMessage_ID(t *testing.T) {
+	file := File{Name: "data.csv", Path: "/data/lake-ice/data.csv"}
+	message := Message{Content: "Credentials detected 'password'", Source: file, TestName: "IsFreeOfKeywords", Line: 3}
+
+	id := message.ID("")
+	if len(id) != 16 {
+		t.Errorf("Expected an ID of 16 hexadecimal digits, got '%s'", id)
+	}
+	if id != IssueID("IsFreeOfKeywords", "/data/lake-ice/data.csv", "", "Credentials detected 'password'") {
+		t.Error("Expected the ID of the message to be that of its check, path and content")
+	}
+
+	// The line and spacing do not change the ID
+	moved := message
+	moved.Line = 12
+	moved.Content = "Credentials  detected 'password' "
+	if moved.ID("") != id {
+		t.Errorf("Expected the ID to be stable, got '%s' and '%s'", id, moved.ID(""))
+	}
+
+	other := message
+	other.Source = File{Name: "data.csv", Path: "/data/lake-ice/raw/data.csv"}
+	if other.ID("") == id {
+		t.Error("Expected findings in different files to have different IDs")
+	}
+
+	// Findings of the repository and the metadata differ by their subject
+	repository := Message{Content: "The package has no license.", Source: Repository{}, TestName: "HasLicense"}
+	metadata := Message{Content: "The package has no license.", Source: Metadata{}, TestName: "HasLicense"}
+	if repository.ID("") == metadata.ID("") {
+		t.Error("Expected findings of the repository and the metadata to have different IDs")
+	}
+
+	// The ID does not depend on the language of the message
+	german := repository
+	german.Content = "Das Paket hat keine Lizenz."
+	if german.ID("") != repository.ID("") {
+		t.Errorf("Expected the same ID in every language, got '%s' and '%s'", repository.ID(""), german.ID(""))
+	}
+}
+
+func TestMessage_ID_Root(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := Message{Content: "Found 'password'.", TestName: "IsFreeOfKeywords"}
+
+	// The same file, found in scans of "data", "./data/" and the absolute path
+	var ids []string
+	for _, root := range []string{"data", "./data/", filepath.Join(wd, "data")} {
+		message.Source = File{Name: "notes.txt", Path: filepath.Join(root, "raw", "notes.txt")}
+		ids = append(ids, message.ID(root))
+	}
+	if ids[0] != ids[1] || ids[0] != ids[2] {
+		t.Errorf("Expected the same ID for every spelling of the location, got %v", ids)
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		root     string
+		path     string
+		expected string
+	}{
+		{"/data/lake-ice", "/data/lake-ice/raw/ice.csv", "raw/ice.csv"},
+		{"/data/lake-ice/", "/data/lake-ice/raw/ice.csv", "raw/ice.csv"},
+		{"lake-ice", "lake-ice/raw/ice.csv", "raw/ice.csv"},
+		{"lake-ice", filepath.Join(wd, "lake-ice", "raw", "ice.csv"), "raw/ice.csv"},
+		{"/data/lake-ice", "/data/lake-ice-old/ice.csv", "/data/lake-ice-old/ice.csv"},
+		{"", "/data/lake-ice/raw/ice.csv", "/data/lake-ice/raw/ice.csv"},
+	}
+	for _, tt := range tests {
+		if got := RelativePath(tt.root, tt.path); got != tt.expected {
+			t.Errorf("RelativePath(%q, %q) = %q, expected %q", tt.root, tt.path, got, tt.expected)
+		}
+	}
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
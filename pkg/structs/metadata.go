@@ -0,0 +1,19 @@
+package structs
+
+// Metadata holds the fields of a CKAN package that metadata checks look at. Issues of the
+// metadata have the subject "metadata".
+type Metadata struct {
+	Package         string // Name of the package
+	Title           string
+	Notes           string // Description of the package
+	LicenseID       string
+	LicenseTitle    string
+	Author          string
+	AuthorEmail     string
+	Maintainer      string
+	MaintainerEmail string
+}
+
+func (m Metadata) GetValue() []File {
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package trace records the decisions of a scan per file, for -trace: why a file was checked or
+// not, which checks ran or were skipped and why, and how long each check took.
+package trace
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// Status of a check in the trace of a file
+const (
+	StatusRan     = "ran"
+	StatusSkipped = "skipped"
+)
+
+// CheckTrace is a check of a file, run or skipped
+type CheckTrace struct {
+	Check      string  `json:"check"`
+	Status     string  `json:"status"`
+	Reason     string  `json:"reason,omitempty"` // Why the check was skipped
+	DurationMs float64 `json:"duration_ms"`      // Time the check took, 0 if it was skipped
+	Findings   int     `json:"findings"`
+}
+
+// FileTrace is the decision path of a file: whether it was collected and the checks of it
+type FileTrace struct {
+	Path        string       `json:"path"`
+	Name        string       `json:"name"`
+	ArchiveName string       `json:"archive_name,omitempty"` // Parent archive if the file is inside an archive
+	Included    bool         `json:"included"`
+	Reason      string       `json:"reason,omitempty"` // Why the file was not checked or not in full
+	Checks      []CheckTrace `json:"checks"`
+}
+
+// Tracer collects the traces of the files of a scan. It is safe for concurrent use; a nil tracer
+// records nothing, so the checks need not know whether -trace is used.
+type Tracer struct {
+	mutex sync.Mutex
+	files map[string]*FileTrace
+	order []string
+}
+
+// New returns an empty tracer
+func New() *Tracer {
+	return &Tracer{files: map[string]*FileTrace{}}
+}
+
+// file returns the trace of a file, created if needed. The caller holds the mutex.
+func (t *Tracer) file(path, name, archiveName string) *FileTrace {
+	key := archiveName + "\x00" + path
+	if archiveName != "" {
+		key = archiveName + "\x00" + name
+	}
+	trace, ok := t.files[key]
+	if !ok {
+		trace = &FileTrace{Path: path, Name: name, ArchiveName: archiveName}
+		t.files[key] = trace
+		t.order = append(t.order, key)
+	}
+	return trace
+}
+
+// check records check for file, replacing an earlier record of the same check
+func (t *Tracer) check(file structs.File, check CheckTrace) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	trace := t.file(file.Path, file.Name, file.ArchiveName)
+	if file.ArchiveName == "" {
+		trace.Included = true
+	}
+	for i := range trace.Checks {
+		if trace.Checks[i].Check == check.Check {
+			trace.Checks[i] = check
+			return
+		}
+	}
+	trace.Checks = append(trace.Checks, check)
+}
+
+// Collected records the files the collector returned as included
+func (t *Tracer) Collected(files []structs.File) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, file := range files {
+		t.file(file.Path, file.Name, file.ArchiveName).Included = true
+	}
+}
+
+// Excluded records why a file was not collected or not checked in full, e.g. a skipped file of
+// the logger. Files that were collected stay included.
+func (t *Tracer) Excluded(path, name, archiveName, reason string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	trace := t.file(path, name, archiveName)
+	if trace.Reason == "" {
+		trace.Reason = reason
+	}
+}
+
+// CheckRan records that check ran on file, taking duration and reporting findings
+func (t *Tracer) CheckRan(file structs.File, check string, duration time.Duration, findings int) {
+	t.check(file, CheckTrace{
+		Check:      check,
+		Status:     StatusRan,
+		DurationMs: float64(duration.Microseconds()) / 1000,
+		Findings:   findings,
+	})
+}
+
+// CheckSkipped records that check was skipped for file and why
+func (t *Tracer) CheckSkipped(file structs.File, check, reason string) {
+	t.check(file, CheckTrace{Check: check, Status: StatusSkipped, Reason: reason})
+}
+
+// Files returns the traces in the order the files were first recorded, with the checks of each
+// file sorted by name
+func (t *Tracer) Files() []FileTrace {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	files := make([]FileTrace, 0, len(t.order))
+	for _, key := range t.order {
+		trace := *t.files[key]
+		trace.Checks = append([]CheckTrace{}, trace.Checks...)
+		sort.Slice(trace.Checks, func(i, j int) bool { return trace.Checks[i].Check < trace.Checks[j].Check })
+		files = append(files, trace)
+	}
+	return files
+}
+
+// Reset forgets the recorded files, for a new scan
+func (t *Tracer) Reset() {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.files = map[string]*FileTrace{}
+	t.order = nil
+}
+
+// Write writes the traces to path as indented JSON
+func (t *Tracer) Write(path string) error {
+	data, err := json.MarshalIndent(struct {
+		Files []FileTrace `json:"files"`
+	}{t.Files()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
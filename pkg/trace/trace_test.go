@@ -0,0 +1,71 @@
+package trace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracer(t *testing.T) {
+	tracer := New()
+	data := structs.File{Path: "/pkg/data.csv", Name: "data.csv"}
+	archived := structs.File{Path: "/pkg/data.zip", Name: "notes.txt", ArchiveName: "data.zip"}
+
+	tracer.Collected([]structs.File{data})
+	tracer.Excluded("/pkg/.hidden", ".hidden", "", "Hidden file")
+	tracer.CheckSkipped(data, "IsFreeOfKeywords", "file type not matched")
+	tracer.CheckRan(data, "HasNoWhiteSpace", 1500*time.Microsecond, 2)
+	// A check recorded twice, as by the parallel scan filtering the checks, is listed once
+	tracer.CheckSkipped(data, "IsFreeOfKeywords", "file type not matched")
+	tracer.CheckRan(archived, "IsValidName", time.Millisecond, 0)
+
+	files := tracer.Files()
+	assert.Len(t, files, 3)
+
+	assert.Equal(t, "data.csv", files[0].Name)
+	assert.True(t, files[0].Included)
+	assert.Equal(t, []CheckTrace{
+		{Check: "HasNoWhiteSpace", Status: StatusRan, DurationMs: 1.5, Findings: 2},
+		{Check: "IsFreeOfKeywords", Status: StatusSkipped, Reason: "file type not matched"},
+	}, files[0].Checks)
+
+	assert.False(t, files[1].Included)
+	assert.Equal(t, "Hidden file", files[1].Reason)
+
+	assert.Equal(t, "data.zip", files[2].ArchiveName)
+	assert.False(t, files[2].Included, "archive members are not collected")
+	assert.Len(t, files[2].Checks, 1)
+
+	tracer.Reset()
+	assert.Empty(t, tracer.Files())
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	tracer.Collected([]structs.File{{Name: "data.csv"}})
+	tracer.CheckRan(structs.File{Name: "data.csv"}, "IsValidName", time.Second, 1)
+	tracer.CheckSkipped(structs.File{Name: "data.csv"}, "IsValidName", "not enabled in the profile")
+	tracer.Excluded("data.csv", "data.csv", "", "Hidden file")
+	assert.Nil(t, tracer.Files())
+}
+
+func TestWrite(t *testing.T) {
+	tracer := New()
+	tracer.CheckRan(structs.File{Path: "/pkg/data.csv", Name: "data.csv"}, "IsValidName", 0, 0)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	assert.NoError(t, tracer.Write(path))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var written struct {
+		Files []FileTrace `json:"files"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Len(t, written.Files, 1)
+	assert.Equal(t, "IsValidName", written.Files[0].Checks[0].Check)
+}
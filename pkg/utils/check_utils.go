@@ -1,44 +1,39 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/eawag-rdm/pc/pkg/checks"
 	"github.com/eawag-rdm/pc/pkg/config"
-	"github.com/eawag-rdm/pc/pkg/helpers"
+	"github.com/eawag-rdm/pc/pkg/i18n"
 	"github.com/eawag-rdm/pc/pkg/optimization"
 	"github.com/eawag-rdm/pc/pkg/output"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/plugins"
 	"github.com/eawag-rdm/pc/pkg/readers"
+	"github.com/eawag-rdm/pc/pkg/rules"
 	"github.com/eawag-rdm/pc/pkg/structs"
 )
 
-var BY_FILE = []func(file structs.File, config config.Config) []structs.Message{
-	checks.HasOnlyASCII,
-	checks.HasNoWhiteSpace,
-	checks.IsFreeOfKeywords,
-	checks.IsValidName,
-	checks.HasFileNameSpecialChars,
-	checks.IsFileNameTooLong,
-}
-var BY_REPOSITORY = []func(repository structs.Repository, config config.Config) []structs.Message{
-	checks.HasReadme,
-	checks.ReadMeContainsTOC,
-}
+// logger logs the progress and problems of running the checks over a scan
+var logger = output.GlobalLogger.Component("scan")
 
-var BY_FILE_ON_ARCHIVE = []func(file structs.File, config config.Config) []structs.Message{
-	checks.IsArchiveFreeOfKeywords,
-}
+// The checks run per scope are taken from the check registry
+var BY_FILE = checks.FileChecks(checks.ScopeFile)
+var BY_REPOSITORY = checks.RepositoryChecks()
+var BY_METADATA = checks.MetadataChecks()
 
-var BY_FILE_ON_ARCHIVE_FILE_LIST = []func(file structs.File, config config.Config) []structs.Message{
-	checks.HasOnlyASCII,
-	checks.HasNoWhiteSpace,
-	checks.IsValidName,
-}
+var BY_FILE_ON_ARCHIVE = checks.FileChecks(checks.ScopeArchiveContent)
+
+var BY_FILE_ON_ARCHIVE_FILE_LIST = checks.FileChecks(checks.ScopeArchiveFileList)
 
 func getFunctionName(i interface{}) string {
 	fullName := runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name()
@@ -46,11 +41,13 @@ func getFunctionName(i interface{}) string {
 	return parts[len(parts)-1]
 }
 
-func matchPatterns(list []string, str string) bool {
+// matchPatterns reports whether str matches any of the patterns in list, compiled once per scan
+// in cache
+func matchPatterns(cache *performance.PatternCache, list []string, str string) bool {
 	combinedPattern := strings.Join(list, "|")
-	combinedRegex, err := regexp.Compile(combinedPattern)
-	if err != nil {
-		output.GlobalLogger.Warning("Error compiling regex pattern '%s': %v", combinedPattern, err)
+	combinedRegex := cache.Regexp(combinedPattern)
+	if combinedRegex == nil {
+		logger.Warning("Error compiling regex pattern '%s'", combinedPattern)
 		return false
 	}
 	return combinedRegex.MatchString(str)
@@ -61,24 +58,105 @@ func matchPatterns(list []string, str string) bool {
 // the functiion will return true or false
 func skipFileCheck(config config.Config, fileCheck func(file structs.File, config config.Config) []structs.Message, file structs.File) bool {
 	checkName := getFunctionName(fileCheck)
-	
-	// Handle special case: IsArchiveFreeOfKeywords uses IsFreeOfKeywords config
+	if !config.IsCheckEnabled(checkName) {
+		return traceSkip(config, checkName, file, "not enabled in the profile")
+	}
+
+	// Some checks share the config of another check (e.g. IsArchiveFreeOfKeywords uses IsFreeOfKeywords)
 	configName := checkName
-	if checkName == "IsArchiveFreeOfKeywords" {
-		configName = "IsFreeOfKeywords"
+	if info, ok := checks.Lookup(checkName); ok {
+		configName = info.GetConfigName()
 	}
-	
-	if _, exists := config.Tests[configName]; !exists {
+	return traceSkip(config, checkName, file, skipReason(config, configName, file))
+}
+
+// traceSkip records in the tracer of the config that the check was skipped for file, unless
+// reason is empty. It returns whether the check is skipped.
+func traceSkip(config config.Config, checkName string, file structs.File, reason string) bool {
+	if reason == "" {
 		return false
 	}
+	config.Tracer().CheckSkipped(file, checkName, reason)
+	return true
+}
+
+// traceRun records in the tracer of the config that the check ran on file since start
+func traceRun(config config.Config, checkName string, file structs.File, start time.Time, findings int) {
+	config.Tracer().CheckRan(file, checkName, time.Since(start), findings)
+}
+
+// cancelled reports whether the scan run with config was cancelled, see config.WithContext.
+// The remaining files and checks are skipped, so the scan returns the findings so far.
+func cancelled(config config.Config) bool {
+	return config.Context().Err() != nil
+}
+
+// withScanTimeout limits the scan run with config to the scanTimeout of the [general] section.
+// The returned function ends the scan and warns if the timeout left files unchecked.
+func withScanTimeout(config config.Config) (config.Config, func()) {
+	if config.General == nil || config.General.ScanTimeout <= 0 {
+		return config, func() {}
+	}
+	ctx, cancel := context.WithTimeout(config.Context(), config.General.ScanTimeout)
+	timeout := config.General.ScanTimeout
+	return config.WithContext(ctx), func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.Warning("The scan took longer than the scanTimeout of %s, the remaining files were not checked", timeout)
+		}
+		cancel()
+	}
+}
+
+// withMemoryBudget shares the maxTotalMemory of the [general] section between the checks of the
+// scan run with config, unless the config has a budget already
+func withMemoryBudget(config config.Config) config.Config {
+	if config.MemoryBudget() != nil || config.General == nil {
+		return config
+	}
+	return config.WithMemoryBudget(performance.NewMemoryBudget(config.General.MaxTotalMemory))
+}
+
+// withScanState gives the scan run with config its own pattern cache, unless the config has one
+// already. Scans running at the same time, as in pc-server, thus share no compiled patterns.
+func withScanState(config config.Config) config.Config {
+	if config.PatternCache() != nil {
+		return config
+	}
+	return config.WithPatternCache(performance.NewPatternCache())
+}
+
+// skipFileCheckByName applies the file types, whitelist and blacklist of the [test.<configName>] section
+func skipFileCheckByName(config config.Config, configName string, file structs.File) bool {
+	return traceSkip(config, configName, file, skipReason(config, configName, file))
+}
+
+// skipReason returns why the [test.<configName>] section skips the check for file, empty if the
+// check runs
+func skipReason(config config.Config, configName string, file structs.File) string {
+	if _, exists := config.Tests[configName]; !exists {
+		return ""
+	}
+	if !config.Tests[configName].MatchesFileType(file.Name) {
+		return "file type not matched"
+	}
 	if len(config.Tests[configName].Whitelist) > 0 {
-		return !matchPatterns(config.Tests[configName].Whitelist, file.Name)
+		if !matchPatterns(config.PatternCache(), config.Tests[configName].Whitelist, file.Name) {
+			return "not in the whitelist"
+		}
+		return ""
 	}
 
-	if len(config.Tests[configName].Blacklist) > 0 {
-		return matchPatterns(config.Tests[configName].Blacklist, file.Name)
+	if len(config.Tests[configName].Blacklist) > 0 && matchPatterns(config.PatternCache(), config.Tests[configName].Blacklist, file.Name) {
+		config.SkippedFiles().Add(output.SkippedFile{
+			Filename:    file.Name,
+			Path:        file.Path,
+			ArchiveName: file.ArchiveName,
+			Code:        output.SkipBlacklisted,
+			Reason:      fmt.Sprintf("Matched by the blacklist of %s.", configName),
+		})
+		return "matched by the blacklist"
 	}
-	return false
+	return ""
 }
 
 func ApplyChecksFilteredByFile(config config.Config, checks []func(file structs.File, config config.Config) []structs.Message, files []structs.File) []structs.Message {
@@ -91,22 +169,12 @@ func ApplyChecksFilteredByFile(config config.Config, checks []func(file structs.
 	// Sequential processing for small workloads
 	var messages = []structs.Message{}
 	for _, file := range files {
-		helpers.PDFTracker.AddFileIfPDF("", file)
-		// apply checks by file but only for file.Name
-		for _, check := range checks {
-			if skipFileCheck(config, check, file) {
-				continue
-			}
-			testName := getFunctionName(check)
-			ret := check(file, config)
-			if ret != nil {
-				// Add test name to each message
-				for i := range ret {
-					ret[i].TestName = testName
-				}
-				messages = append(messages, ret...)
-			}
+		if cancelled(config) {
+			break
 		}
+		config.PDFTracker().AddFileIfPDF("", file)
+		// apply checks by file but only for file.Name
+		messages = append(messages, runFileChecks(config, checks, file, nil)...)
 	}
 	return messages
 }
@@ -117,7 +185,10 @@ func ApplyChecksFilteredByFileWithProgress(config config.Config, checks []func(f
 	var messages = []structs.Message{}
 
 	for i, file := range files {
-		helpers.PDFTracker.AddFileIfPDF("", file)
+		if cancelled(config) {
+			break
+		}
+		config.PDFTracker().AddFileIfPDF("", file)
 
 		// Report progress for this file
 		if progressCallback != nil {
@@ -125,20 +196,7 @@ func ApplyChecksFilteredByFileWithProgress(config config.Config, checks []func(f
 		}
 
 		// apply checks by file but only for file.Name
-		for _, check := range checks {
-			if skipFileCheck(config, check, file) {
-				continue
-			}
-			testName := getFunctionName(check)
-			ret := check(file, config)
-			if ret != nil {
-				// Add test name to each message
-				for j := range ret {
-					ret[j].TestName = testName
-				}
-				messages = append(messages, ret...)
-			}
-		}
+		messages = append(messages, runFileChecks(config, checks, file, nil)...)
 	}
 	return messages
 }
@@ -149,31 +207,63 @@ func ApplyChecksFilteredByFileWithTestProgress(config config.Config, checks []fu
 	testsProcessed := 0
 
 	for _, file := range files {
-		helpers.PDFTracker.AddFileIfPDF("", file)
+		if cancelled(config) {
+			break
+		}
+		config.PDFTracker().AddFileIfPDF("", file)
 
-		// Process all checks for this file (including skipped ones)
-		for _, check := range checks {
-			// Count this test (whether run or skipped)
+		// Process all checks for this file, counting each test whether run or skipped
+		filesTests := testsProcessed + len(checks)
+		messages = append(messages, runFileChecks(config, checks, file, func() {
 			testsProcessed++
 			if progressCallback != nil {
 				progressCallback(testsProcessed)
 			}
+		})...)
 
-			if skipFileCheck(config, check, file) {
-				continue // Skip this test, but we already counted it
+		// Tests cut off by the timeout are counted as well
+		if testsProcessed != filesTests {
+			testsProcessed = filesTests
+			if progressCallback != nil {
+				progressCallback(testsProcessed)
 			}
+		}
+	}
+	return messages
+}
 
-			testName := getFunctionName(check)
-			ret := check(file, config)
-			if ret != nil {
-				// Add test name to each message
-				for j := range ret {
-					ret[j].TestName = testName
+// runFileChecks applies the checks to one file, giving up after the fileTimeout of the config.
+// The checks share the content of the file. started is called before each check, run or
+// skipped, until the file times out.
+func runFileChecks(cfg config.Config, checks []func(file structs.File, config config.Config) []structs.Message, file structs.File, started func()) []structs.Message {
+	logger.Debug("Checking '%s' with %d checks", file.Name, len(checks))
+	content := performance.NewFileContent(file.Path, cfg.MemoryBudget())
+	defer content.Close()
+	cfg = cfg.WithFileContent(content)
+
+	// A file that timed out may still be checked in the background, it must not report progress
+	var mutex sync.Mutex
+	timedOut := false
+	messages := optimization.WithFileTimeout(cfg, file, func(fileConfig config.Config) []structs.Message {
+		var messages []structs.Message
+		for _, check := range checks {
+			if cancelled(fileConfig) {
+				break
+			}
+			if started != nil {
+				mutex.Lock()
+				if !timedOut {
+					started()
 				}
-				messages = append(messages, ret...)
+				mutex.Unlock()
 			}
+			messages = append(messages, runFileCheck(fileConfig, check, file)...)
 		}
-	}
+		return messages
+	})
+	mutex.Lock()
+	timedOut = true
+	mutex.Unlock()
 	return messages
 }
 
@@ -196,7 +286,7 @@ func applyChecksParallel(cfg config.Config, checks []func(file structs.File, con
 	// Submit work items - one per file with all applicable checks
 	go func() {
 		for _, file := range files {
-			helpers.PDFTracker.AddFileIfPDF("", file)
+			cfg.PDFTracker().AddFileIfPDF("", file)
 
 			// Filter checks for this specific file
 			var validChecks []func(structs.File, config.Config) []structs.Message
@@ -282,20 +372,40 @@ func processArchiveFileList(cfg config.Config, checks []func(file structs.File,
 	var messages []structs.Message
 
 	fileList, err := readers.ReadArchiveFileList(archiveFile)
+	if readers.IsEncrypted(err) {
+		// Recorded once with the archive content checks, which skip it for the same reason
+		cfg.SkippedFiles().Add(output.SkippedFile{
+			Filename: archiveFile.Name,
+			Path:     archiveFile.Path,
+			Code:     output.SkipEncrypted,
+			Reason:   readers.EncryptedArchiveReason,
+		})
+		return messages
+	}
 	if err != nil {
-		output.GlobalLogger.Warning("Error (archive filelist checks) reading archive file list of '%s' -> %v", archiveFile.Name, err)
+		cfg.SkippedFiles().Add(output.SkippedFile{
+			Filename: archiveFile.Name,
+			Path:     archiveFile.Path,
+			Code:     output.SkipReadError,
+			Reason:   fmt.Sprintf("Error reading the file list of the archive: %v", err),
+		})
 		return messages
 	}
 
 	for _, archivedFile := range fileList {
-		helpers.PDFTracker.AddFileIfPDF(archiveFile.Name+" -> ", archivedFile)
+		if cancelled(cfg) {
+			break
+		}
+		cfg.PDFTracker().AddFileIfPDF(archiveFile.Name+" -> ", archivedFile)
 
 		for _, check := range checks {
 			if skipFileCheck(cfg, check, archivedFile) {
 				continue
 			}
 			testName := getFunctionName(check)
+			start := time.Now()
 			ret := check(archivedFile, cfg)
+			traceRun(cfg, testName, archivedFile, start, len(ret))
 
 			if ret != nil {
 				for i := range ret {
@@ -328,7 +438,9 @@ func applyArchiveFileListChecksParallel(cfg config.Config, checks []func(file st
 		go func() {
 			defer wg.Done()
 			for archiveFile := range archiveChan {
-				messages := processArchiveFileList(cfg, checks, archiveFile)
+				messages := optimization.SafeCheck(cfg, archiveFile, func(cfg config.Config) []structs.Message {
+					return processArchiveFileList(cfg, checks, archiveFile)
+				})
 				resultChan <- messages
 			}
 		}()
@@ -376,20 +488,10 @@ func ApplyChecksFilteredByFileOnArchive(config config.Config, checks []func(file
 	// Sequential processing for single archives
 	var messages = []structs.Message{}
 	for _, file := range archiveFiles {
-		for _, check := range checks {
-			if skipFileCheck(config, check, file) {
-				continue
-			}
-			testName := getFunctionName(check)
-			ret := check(file, config)
-			if ret != nil {
-				// Add test name to each message
-				for i := range ret {
-					ret[i].TestName = testName
-				}
-				messages = append(messages, ret...)
-			}
+		if cancelled(config) {
+			break
 		}
+		messages = append(messages, runFileChecks(config, checks, file, nil)...)
 	}
 	return messages
 }
@@ -463,9 +565,19 @@ func applyArchiveChecksParallel(cfg config.Config, checks []func(file structs.Fi
 
 func ApplyChecksFilteredByRepository(config config.Config, checks []func(repository structs.Repository, config config.Config) []structs.Message, files []structs.File) []structs.Message {
 	var messages = []structs.Message{}
+	if len(files) == 0 {
+		// Scans of the metadata only have no files to check
+		return messages
+	}
 	repo := structs.Repository{Files: files}
 	for _, check := range checks {
+		if cancelled(config) {
+			break
+		}
 		testName := getFunctionName(check)
+		if !config.IsCheckEnabled(testName) {
+			continue
+		}
 		ret := check(repo, config)
 		if ret != nil {
 			// Add test name to each message
@@ -478,10 +590,177 @@ func ApplyChecksFilteredByRepository(config config.Config, checks []func(reposit
 	return messages
 }
 
+// ApplyChecksOnMetadata runs the metadata checks on the package metadata of the config, if the
+// collector set any
+func ApplyChecksOnMetadata(config config.Config, checks []func(metadata structs.Metadata, config config.Config) []structs.Message) []structs.Message {
+	var messages = []structs.Message{}
+	metadata := config.Metadata()
+	if metadata == nil {
+		return messages
+	}
+	for _, check := range checks {
+		if cancelled(config) {
+			break
+		}
+		testName := getFunctionName(check)
+		if !config.IsCheckEnabled(testName) {
+			continue
+		}
+		ret := check(*metadata, config)
+		for i := range ret {
+			ret[i].TestName = testName
+		}
+		messages = append(messages, ret...)
+	}
+	return messages
+}
+
+// ApplyPluginChecks runs the external checks configured in [plugin.*] sections.
+// A failing plugin is reported as a warning and does not stop the scan.
+func ApplyPluginChecks(config config.Config, files []structs.File, checksAcrossFiles bool) []structs.Message {
+	var messages = []structs.Message{}
+	for _, plugin := range plugins.Load(config) {
+		if !config.IsCheckEnabled(plugin.Name) {
+			continue
+		}
+		if plugin.Scope == "repository" {
+			if !checksAcrossFiles {
+				continue
+			}
+			ret, err := plugin.CheckRepository(structs.Repository{Files: files}, config)
+			if err != nil {
+				logger.Warning("%v", err)
+				continue
+			}
+			messages = append(messages, ret...)
+			continue
+		}
+
+		for _, file := range files {
+			if cancelled(config) {
+				break
+			}
+			if skipFileCheckByName(config, plugin.Name, file) {
+				continue
+			}
+			start := time.Now()
+			ret, err := plugin.CheckFile(file, config)
+			traceRun(config, plugin.Name, file, start, len(ret))
+			if err != nil {
+				logger.Warning("%v (file: '%s')", err, file.Name)
+				continue
+			}
+			messages = append(messages, ret...)
+		}
+	}
+	return messages
+}
+
+// ApplyRuleChecks evaluates the expression checks configured in [rule.*] sections on every file
+// and, like the archive file list checks, on the members of the archives among them
+func ApplyRuleChecks(config config.Config, files []structs.File) []structs.Message {
+	if len(config.Rules) == 0 {
+		return []structs.Message{}
+	}
+	return applyRuleChecks(config, withArchiveMembers(config, files))
+}
+
+// withArchiveMembers returns the files followed by the members of the archives among them. The
+// archive file list checks record the archives whose members cannot be listed.
+func withArchiveMembers(config config.Config, files []structs.File) []structs.File {
+	all := append([]structs.File(nil), files...)
+	for _, file := range files {
+		if !file.IsArchive || cancelled(config) {
+			continue
+		}
+		members, err := readers.ReadArchiveFileList(file)
+		if err != nil {
+			continue
+		}
+		all = append(all, members...)
+	}
+	return all
+}
+
+// pluginTests counts the runs of the plugins of the config on the files, including skipped ones
+func pluginTests(config config.Config, files []structs.File, checksAcrossFiles bool) int {
+	total := 0
+	for _, plugin := range config.Plugins {
+		if plugin.Scope != "repository" {
+			total += len(files)
+		} else if checksAcrossFiles {
+			total++
+		}
+	}
+	return total
+}
+
+// applyRuleChecks evaluates the expression checks on the files as given
+func applyRuleChecks(config config.Config, files []structs.File) []structs.Message {
+	var messages = []structs.Message{}
+	ruleChecks, err := rules.Load(config)
+	if err != nil {
+		logger.Warning("%v", err)
+		return messages
+	}
+	for _, rule := range ruleChecks {
+		if !config.IsCheckEnabled(rule.Name) {
+			continue
+		}
+		for _, file := range files {
+			if cancelled(config) {
+				break
+			}
+			if skipFileCheckByName(config, rule.Name, file) {
+				continue
+			}
+			start := time.Now()
+			ret, err := rule.Check(file)
+			traceRun(config, rule.Name, file, start, len(ret))
+			if err != nil {
+				logger.Warning("%v (file: '%s')", err, file.Name)
+				continue
+			}
+			messages = append(messages, ret...)
+		}
+	}
+	return messages
+}
+
 // ProgressCallback is called during scanning to report progress
 type ProgressCallback func(current, total int, message string)
 
+// archiveProgressInterval is the minimum time between two reports of the progress within archives
+const archiveProgressInterval = 200 * time.Millisecond
+
+// throttleArchiveProgress passes on at most one report per interval, as redrawing the progress for
+// every member would slow down archives of many small files
+func throttleArchiveProgress(progress config.ArchiveProgress, interval time.Duration) config.ArchiveProgress {
+	var mutex sync.Mutex
+	var last time.Time
+	return func(archive string, member, total int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if time.Since(last) < interval {
+			return
+		}
+		last = time.Now()
+		progress(archive, member, total)
+	}
+}
+
+// describeArchiveProgress formats the progress within an archive, e.g. "data.zip: member 12 of 500"
+func describeArchiveProgress(archive string, member, total int) string {
+	if total > 0 {
+		return fmt.Sprintf("%s: member %d of %d", archive, member, total)
+	}
+	return fmt.Sprintf("%s: member %d", archive, member)
+}
+
 func ApplyAllChecks(config config.Config, files []structs.File, checksAcrossFiles bool) []structs.Message {
+	config, stop := withScanTimeout(config)
+	defer stop()
+	config = withScanState(withMemoryBudget(config))
 	var messages []structs.Message
 
 	messages = append(messages, ApplyChecksFilteredByFile(config, BY_FILE, files)...)
@@ -490,14 +769,20 @@ func ApplyAllChecks(config config.Config, files []structs.File, checksAcrossFile
 	if checksAcrossFiles {
 		messages = append(messages, ApplyChecksFilteredByRepository(config, BY_REPOSITORY, files)...)
 	}
+	messages = append(messages, ApplyChecksOnMetadata(config, BY_METADATA)...)
+	messages = append(messages, ApplyRuleChecks(config, files)...)
+	messages = append(messages, ApplyPluginChecks(config, files, checksAcrossFiles)...)
 
 	// Message truncation disabled to prevent archive messages from being lost
 	// messages = TruncateMessages(messages, config.General.MaxMessagesPerType)
 
-	return messages
+	return sortMessages(LimitFindings(DeduplicateMessages(messages), maxFindingsPerCheck(config)))
 }
 
 func ApplyAllChecksWithProgress(config config.Config, files []structs.File, checksAcrossFiles bool, progressCallback ProgressCallback) []structs.Message {
+	config, stop := withScanTimeout(config)
+	defer stop()
+	config = withScanState(withMemoryBudget(config))
 	var messages []structs.Message
 
 	// Calculate total number of tests (including skipped tests)
@@ -527,6 +812,19 @@ func ApplyAllChecksWithProgress(config config.Config, files []structs.File, chec
 		totalTests += len(BY_REPOSITORY)
 	}
 
+	// Count metadata tests
+	if config.Metadata() != nil {
+		totalTests += len(BY_METADATA)
+	}
+
+	// Count rule checks, which also see the archive members, and plugin checks
+	var ruleFiles []structs.File
+	if len(config.Rules) > 0 {
+		ruleFiles = withArchiveMembers(config, files)
+		totalTests += len(config.Rules) * len(ruleFiles)
+	}
+	totalTests += pluginTests(config, files, checksAcrossFiles)
+
 	testsRun := 0
 
 	// Step 1: File checks (with per-test progress)
@@ -554,11 +852,15 @@ func ApplyAllChecksWithProgress(config config.Config, files []structs.File, chec
 		}
 	}
 
-	// Step 3: Archive content checks
+	// Step 3: Archive content checks, reporting the members scanned as huge archives take minutes
+	archiveConfig := config
 	if progressCallback != nil {
 		progressCallback(testsRun, totalTests, "Running archive content tests...")
+		archiveConfig = config.WithArchiveProgress(throttleArchiveProgress(func(archive string, member, total int) {
+			progressCallback(testsRun, totalTests, "Running archive content tests... "+describeArchiveProgress(archive, member, total))
+		}, archiveProgressInterval))
 	}
-	archiveContentTests := ApplyChecksFilteredByFileOnArchive(config, BY_FILE_ON_ARCHIVE, files)
+	archiveContentTests := ApplyChecksFilteredByFileOnArchive(archiveConfig, BY_FILE_ON_ARCHIVE, files)
 	messages = append(messages, archiveContentTests...)
 	// Update count for archive content tests (including skipped ones)
 	for _, file := range files {
@@ -577,6 +879,33 @@ func ApplyAllChecksWithProgress(config config.Config, files []structs.File, chec
 		testsRun += len(BY_REPOSITORY)
 	}
 
+	// Step 5: Metadata checks (if the collector retrieved the metadata)
+	if config.Metadata() != nil {
+		if progressCallback != nil {
+			progressCallback(testsRun, totalTests, "Running metadata tests...")
+		}
+		messages = append(messages, ApplyChecksOnMetadata(config, BY_METADATA)...)
+		testsRun += len(BY_METADATA)
+	}
+
+	// Step 6: Rule checks defined in the config
+	if len(config.Rules) > 0 {
+		if progressCallback != nil {
+			progressCallback(testsRun, totalTests, "Running rule checks...")
+		}
+		messages = append(messages, applyRuleChecks(config, ruleFiles)...)
+		testsRun += len(config.Rules) * len(ruleFiles)
+	}
+
+	// Step 7: External plugin checks
+	if len(config.Plugins) > 0 {
+		if progressCallback != nil {
+			progressCallback(testsRun, totalTests, "Running plugin checks...")
+		}
+		messages = append(messages, ApplyPluginChecks(config, files, checksAcrossFiles)...)
+		testsRun += pluginTests(config, files, checksAcrossFiles)
+	}
+
 	// Final step: Finalize results (message truncation disabled)
 	if progressCallback != nil {
 		progressCallback(testsRun, totalTests, "Finalizing results...")
@@ -584,7 +913,7 @@ func ApplyAllChecksWithProgress(config config.Config, files []structs.File, chec
 	// Message truncation disabled to prevent archive messages from being lost
 	// messages = TruncateMessages(messages, config.General.MaxMessagesPerType)
 
-	return messages
+	return sortMessages(LimitFindings(DeduplicateMessages(messages), maxFindingsPerCheck(config)))
 }
 
 // getMessageType extracts a type identifier from a message content
@@ -622,21 +951,163 @@ func getMessageType(content string) string {
 	return content
 }
 
+// DeduplicateMessages merges identical findings of a check in the same file or repository into the
+// first of them, with the number of occurrences and the lines they were found on. Messages are
+// identical if their content is equal up to whitespace.
+func DeduplicateMessages(messages []structs.Message) []structs.Message {
+	var result []structs.Message
+	index := make(map[string]int)
+	for _, msg := range messages {
+		key := msg.TestName + "\x00" + sourceKey(msg.Source) + "\x00" + strings.Join(strings.Fields(msg.Content), " ")
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(result)
+			msg.Count = 1
+			if msg.Line > 0 {
+				msg.Lines = []int{msg.Line}
+			}
+			result = append(result, msg)
+			continue
+		}
+		merged := &result[i]
+		merged.Count++
+		merged.Omitted += msg.Omitted
+		if msg.Line > 0 {
+			merged.Lines = append(merged.Lines, msg.Line)
+		}
+	}
+
+	for i := range result {
+		if result[i].Count == 1 {
+			// Single findings keep their plain form
+			result[i].Count = 0
+			result[i].Lines = nil
+			continue
+		}
+		sort.Ints(result[i].Lines)
+		result[i].Lines = uniqueInts(result[i].Lines)
+		if len(result[i].Lines) > 0 {
+			result[i].Line = result[i].Lines[0]
+		}
+	}
+	return result
+}
+
+// LimitFindings keeps the first max findings of each check in each file or repository and replaces
+// the rest, including the findings the checks stopped reporting themselves, by one "... and N more"
+// message. A max of 0 keeps all findings.
+func LimitFindings(messages []structs.Message, max int) []structs.Message {
+	if max <= 0 {
+		return messages
+	}
+
+	kept := make(map[string]int)
+	more := make(map[string]int)
+	last := make(map[string]int) // Index of the last kept message of a group
+	keep := make([]bool, len(messages))
+	for i, msg := range messages {
+		key := msg.TestName + "\x00" + sourceKey(msg.Source)
+		more[key] += msg.Omitted
+		if kept[key] == max {
+			more[key]++
+			continue
+		}
+		kept[key]++
+		keep[i] = true
+		last[key] = i
+	}
+
+	result := make([]structs.Message, 0, len(messages))
+	for i, msg := range messages {
+		if !keep[i] {
+			continue
+		}
+		msg.Omitted = 0
+		result = append(result, msg)
+		key := msg.TestName + "\x00" + sourceKey(msg.Source)
+		if last[key] == i && more[key] > 0 {
+			result = append(result, structs.Message{
+				Content:  fmt.Sprintf("... and %d more findings (maxFindingsPerCheck = %d)", more[key], max),
+				Source:   msg.Source,
+				TestName: msg.TestName,
+			})
+		}
+	}
+	return result
+}
+
+// sortMessages orders the messages by file, then the metadata and the repository, so that
+// parallel scans return them in the same order. The messages of a file keep the order of the
+// checks that reported them.
+func sortMessages(messages []structs.Message) []structs.Message {
+	rank := func(source structs.Source) int {
+		switch source.(type) {
+		case structs.File:
+			return 0
+		case structs.Metadata:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		a, b := messages[i].Source, messages[j].Source
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		return sourceKey(a) < sourceKey(b)
+	})
+	return messages
+}
+
+// maxFindingsPerCheck returns the configured limit of LimitFindings, 0 if the config has no [general] section
+func maxFindingsPerCheck(config config.Config) int {
+	if config.General == nil {
+		return 0
+	}
+	return config.General.MaxFindingsPerCheck
+}
+
+// sourceKey identifies the file, repository or metadata of a message
+func sourceKey(source structs.Source) string {
+	switch source := source.(type) {
+	case structs.File:
+		return source.ArchiveName + "\x00" + source.Path + "\x00" + source.Name
+	case structs.Metadata:
+		return "metadata"
+	}
+	return "repository"
+}
+
+func uniqueInts(values []int) []int {
+	result := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 // TruncateMessages groups messages by type and truncates them if they exceed the limit
 func TruncateMessages(messages []structs.Message, maxPerType int) []structs.Message {
 	if maxPerType <= 0 {
 		return messages
 	}
 
-	// Group messages by type
+	// Group messages by type, in the order the types first occur
 	messageGroups := make(map[string][]structs.Message)
+	var types []string
 	for _, msg := range messages {
 		msgType := getMessageType(msg.Content)
+		if _, ok := messageGroups[msgType]; !ok {
+			types = append(types, msgType)
+		}
 		messageGroups[msgType] = append(messageGroups[msgType], msg)
 	}
 
 	var result []structs.Message
-	for _, msgs := range messageGroups {
+	for _, msgType := range types {
+		msgs := messageGroups[msgType]
 		if len(msgs) <= maxPerType {
 			// Add all messages if under the limit
 			result = append(result, msgs...)
@@ -656,3 +1127,35 @@ func TruncateMessages(messages []structs.Message, maxPerType int) []structs.Mess
 
 	return result
 }
+
+// TranslateMessages translates the contents of the messages into the selected language
+func TranslateMessages(messages []structs.Message) []structs.Message {
+	if i18n.Language() == i18n.English {
+		return messages
+	}
+	translated := make([]structs.Message, len(messages))
+	for i, message := range messages {
+		message.Content = i18n.Translate(message.Content)
+		translated[i] = message
+	}
+	return translated
+}
+
+// RedactMessages removes the user names and absolute paths from the contents, snippets and
+// files of the messages, for reports shared outside the institution
+func RedactMessages(messages []structs.Message, redactor *output.Redactor) []structs.Message {
+	if redactor == nil {
+		return messages
+	}
+	redacted := make([]structs.Message, len(messages))
+	for i, message := range messages {
+		message.Content = redactor.Text(message.Content)
+		message.Snippet = redactor.Text(message.Snippet)
+		if file, ok := message.Source.(structs.File); ok {
+			file.Path = redactor.Path(file.Path)
+			message.Source = file
+		}
+		redacted[i] = message
+	}
+	return redacted
+}
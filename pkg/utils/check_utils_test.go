@@ -1,10 +1,19 @@
 package utils
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/helpers"
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/eawag-rdm/pc/pkg/trace"
 
 	"github.com/eawag-rdm/pc/pkg/config"
 )
@@ -141,6 +150,116 @@ func TestSkipFileCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipFileCheckRecordsBlacklist(t *testing.T) {
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"mockCheck": {Blacklist: []string{`\.log$`}},
+	}}.WithSkippedFiles(output.NewSkippedFiles())
+	skipFileCheck(cfg, mockCheck, structs.File{Name: "debug.log", Path: "/data/debug.log"})
+	skipFileCheck(cfg, mockCheck, structs.File{Name: "data.csv", Path: "/data/data.csv"})
+
+	skipped := cfg.SkippedFiles().Files()
+	if len(skipped) != 1 || skipped[0].Filename != "debug.log" || skipped[0].Code != output.SkipBlacklisted || !strings.Contains(skipped[0].Reason, "mockCheck") {
+		t.Errorf("expected the blacklisted file to be recorded with its test, got %+v", skipped)
+	}
+}
+
+func TestSkipFileCheckTracesReason(t *testing.T) {
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.ClearMessages()
+
+	tracer := trace.New()
+	cfg := config.Config{Tests: map[string]*config.TestConfig{
+		"mockCheck": {Whitelist: []string{`\.csv$`}},
+	}}
+	cfg = cfg.WithTracer(tracer)
+	skipped := structs.File{Name: "notes.txt", Path: "/data/notes.txt"}
+	checked := structs.File{Name: "data.csv", Path: "/data/data.csv"}
+	runFileCheck(cfg, mockCheck, skipped)
+	runFileCheck(cfg, mockCheck, checked)
+
+	files := tracer.Files()
+	if len(files) != 2 {
+		t.Fatalf("expected both files to be traced, got %+v", files)
+	}
+	if check := files[0].Checks[0]; check.Status != trace.StatusSkipped || check.Reason != "not in the whitelist" {
+		t.Errorf("expected the check to be skipped by the whitelist, got %+v", check)
+	}
+	if check := files[1].Checks[0]; check.Check != "mockCheck" || check.Status != trace.StatusRan {
+		t.Errorf("expected the check to run, got %+v", check)
+	}
+}
+
+func TestPDFTrackerPerScan(t *testing.T) {
+	first := config.Config{}.WithPDFTracker(helpers.NewPDFTracker())
+	second := config.Config{}.WithPDFTracker(helpers.NewPDFTracker())
+	checks := []func(structs.File, config.Config) []structs.Message{mockCheck}
+	ApplyChecksFilteredByFile(first, checks, []structs.File{{Name: "report.pdf", Suffix: ".pdf"}})
+	ApplyChecksFilteredByFile(second, checks, []structs.File{{Name: "data.csv", Suffix: ".csv"}})
+
+	if files := first.PDFTracker().Files; len(files) != 1 || files[0] != "report.pdf" {
+		t.Errorf("expected the first scan to list its PDF file, got %v", files)
+	}
+	if files := second.PDFTracker().Files; len(files) != 0 {
+		t.Errorf("expected the second scan to list no PDF files, got %v", files)
+	}
+}
+
+func TestSkippedFilesPerScan(t *testing.T) {
+	tests := map[string]*config.TestConfig{"mockCheck": {Blacklist: []string{`\.log$`}}}
+	first := config.Config{Tests: tests}.WithSkippedFiles(output.NewSkippedFiles())
+	second := config.Config{Tests: tests}.WithSkippedFiles(output.NewSkippedFiles())
+	checks := []func(structs.File, config.Config) []structs.Message{mockCheck}
+	ApplyChecksFilteredByFile(first, checks, []structs.File{{Name: "debug.log", Path: "/data/debug.log"}})
+	ApplyChecksFilteredByFile(first, checks, []structs.File{{Name: "debug.log", Path: "/data/debug.log"}})
+	ApplyChecksFilteredByFile(second, checks, []structs.File{{Name: "data.csv", Path: "/data/data.csv"}})
+
+	if skipped := first.SkippedFiles().Files(); len(skipped) != 1 || skipped[0].Filename != "debug.log" {
+		t.Errorf("expected the first scan to record its skipped file once, got %+v", skipped)
+	}
+	if skipped := second.SkippedFiles().Files(); len(skipped) != 0 {
+		t.Errorf("expected the second scan to record no skipped files, got %+v", skipped)
+	}
+}
+
+func TestEncryptedArchiveFileList(t *testing.T) {
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.ClearMessages()
+
+	archive := structs.File{Name: "encrypted_headers.7z", Path: "../../testdata/archives/encrypted_headers.7z"}
+	cfg := config.Config{}.WithSkippedFiles(output.NewSkippedFiles())
+	messages := processArchiveFileList(cfg, []func(structs.File, config.Config) []structs.Message{mockCheck}, archive)
+	if len(messages) != 0 {
+		t.Errorf("expected no messages for an archive whose file list is encrypted, got %v", messages)
+	}
+	skipped := cfg.SkippedFiles().Files()
+	if len(skipped) != 1 || skipped[0].Code != output.SkipEncrypted {
+		t.Errorf("expected the archive to be skipped as encrypted, got %+v", skipped)
+	}
+	if warnings := output.GlobalLogger.GetMessages(); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestCorruptArchiveSkippedOnce(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	archive := structs.File{Name: "corrupt.zip", Path: filepath.Join(t.TempDir(), "corrupt.zip"), IsArchive: true}
+	if err := os.WriteFile(archive.Path, []byte("PK\x03\x04 not a zip file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file list and the content checks both fail to open it
+	scan := cfg.WithSkippedFiles(output.NewSkippedFiles())
+	processArchiveFileList(scan, []func(structs.File, config.Config) []structs.Message{mockCheck}, archive)
+	ApplyChecksFilteredByFileOnArchive(scan, []func(structs.File, config.Config) []structs.Message{checks.IsArchiveFreeOfKeywords}, []structs.File{archive})
+	if skipped := scan.SkippedFiles().Files(); len(skipped) != 1 || skipped[0].Code != output.SkipReadError {
+		t.Errorf("expected the corrupt archive to be skipped once, got %+v", skipped)
+	}
+}
+
 func TestMatchPatterns(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -206,7 +325,7 @@ func TestMatchPatterns(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result := matchPatterns(test.list, test.str)
+			result := matchPatterns(nil, test.list, test.str)
 			if result != test.expectedMatch {
 				t.Errorf("%v: matchPatterns(%v, %v) = %v; want %v", test.name, test.list, test.str, result, test.expectedMatch)
 			}
@@ -305,3 +424,323 @@ func TestApplyChecksFilteredByFile(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyPluginChecks(t *testing.T) {
+	cfg := config.Config{
+		Plugins: map[string]*config.PluginConfig{
+			"NetCDFMetadata": {Command: []string{"sh", "-c", `cat > /dev/null; echo '{"messages": [{"content": "missing units"}]}'`}, Scope: "file"},
+			"Licence":        {Command: []string{"sh", "-c", `cat > /dev/null; echo '{"messages": [{"content": "no licence"}]}'`}, Scope: "repository"},
+		},
+		Tests: map[string]*config.TestConfig{
+			"NetCDFMetadata": {Whitelist: []string{`\.nc$`}},
+		},
+	}
+	files := []structs.File{{Name: "a.nc"}, {Name: "b.txt"}}
+
+	messages := ApplyPluginChecks(cfg, files, true)
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %+v", messages)
+	}
+	if messages[0].TestName != "Licence" || messages[1].TestName != "NetCDFMetadata" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+	if file, ok := messages[1].Source.(structs.File); !ok || file.Name != "a.nc" {
+		t.Errorf("expected the whitelist to restrict the plugin to a.nc, got %+v", messages[1].Source)
+	}
+
+	// Repository plugins only run with checks across files
+	if messages := ApplyPluginChecks(cfg, files, false); len(messages) != 1 {
+		t.Errorf("expected only the file plugin to run, got %+v", messages)
+	}
+}
+
+func TestApplyRuleChecks(t *testing.T) {
+	cfg := config.Config{
+		Rules: map[string]*config.RuleConfig{
+			"LargeCSV": {Rule: "file.size > 1e9 && file.ext == '.csv'", Message: "{{.file.name}} is large"},
+		},
+		Tests: map[string]*config.TestConfig{
+			"LargeCSV": {Blacklist: []string{"^raw_"}},
+		},
+	}
+	files := []structs.File{
+		{Name: "big.csv", Suffix: ".csv", Size: 2e9},
+		{Name: "raw_big.csv", Suffix: ".csv", Size: 2e9},
+		{Name: "small.csv", Suffix: ".csv", Size: 10},
+	}
+
+	messages := ApplyRuleChecks(cfg, files)
+	if len(messages) != 1 || messages[0].Content != "big.csv is large" || messages[0].TestName != "LargeCSV" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
+
+// writeNotes writes a text file to check next to an archive
+func writeNotes(t *testing.T) structs.File {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("Lake ice cover"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return structs.File{Name: "notes.txt", Path: path, Suffix: ".txt", Size: 14}
+}
+
+func TestApplyRuleChecksArchiveMembers(t *testing.T) {
+	cfg := config.Config{
+		Rules: map[string]*config.RuleConfig{
+			"TextInArchive": {Rule: "file.in_archive && file.ext == '.txt'", Message: "{{.file.name}} in {{.file.archive_name}}"},
+		},
+	}
+	notes := writeNotes(t)
+	archive := structs.File{Name: "test.zip", Path: "../../testdata/archives/test.zip", Suffix: ".zip", IsArchive: true}
+	files := []structs.File{notes, archive}
+
+	// The rules see the members of archives, like the archive file list checks
+	messages := ApplyRuleChecks(cfg, files)
+	if len(messages) != 1 || messages[0].Content != "test/file1.txt in test.zip" {
+		t.Errorf("expected the text file in the archive, got %+v", messages)
+	}
+
+	scanConfig, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	scanConfig.Rules = cfg.Rules
+	var streamed []structs.Message
+	ApplyAllChecksStreaming(scanConfig.WithSkippedFiles(output.NewSkippedFiles()), files, false, func(batch []structs.Message) {
+		for _, message := range batch {
+			if message.TestName == "TextInArchive" {
+				streamed = append(streamed, message)
+			}
+		}
+	})
+	if len(streamed) != 1 || streamed[0].Content != "test/file1.txt in test.zip" {
+		t.Errorf("expected the streamed scan to check the archive members, got %+v", streamed)
+	}
+}
+
+func TestProgressCountsRulesAndPlugins(t *testing.T) {
+	cfg, err := config.LoadConfig("../../testdata/test_config.toml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Rules = map[string]*config.RuleConfig{
+		"Always": {Rule: "true"},
+	}
+	cfg.Plugins = map[string]*config.PluginConfig{
+		"Licence": {Command: []string{"sh", "-c", `cat > /dev/null; echo '{"messages": []}'`}, Scope: "repository"},
+		"Units":   {Command: []string{"sh", "-c", `cat > /dev/null; echo '{"messages": []}'`}, Scope: "file"},
+	}
+	files := []structs.File{
+		writeNotes(t),
+		{Name: "test.zip", Path: "../../testdata/archives/test.zip", Suffix: ".zip", IsArchive: true},
+	}
+
+	var current, total int
+	messages := ApplyAllChecksWithProgress(cfg.WithSkippedFiles(output.NewSkippedFiles()), files, true, func(c, t int, message string) {
+		current, total = c, t
+	})
+	builtIn := 2*len(BY_FILE) + len(BY_FILE_ON_ARCHIVE_FILE_LIST) + len(BY_FILE_ON_ARCHIVE) + len(BY_REPOSITORY)
+	// The rule runs on the 2 files and the 3 members of test.zip, the plugins on the 2 files and
+	// the repository
+	if want := builtIn + 5 + 2 + 1; current != want || total != want {
+		t.Errorf("expected the progress to end at %d of %d tests, got %d of %d", want, want, current, total)
+	}
+	var always int
+	for _, message := range messages {
+		if message.TestName == "Always" {
+			always++
+		}
+	}
+	if always != 5 {
+		t.Errorf("expected the rule to match the files and the archive members, got %d matches", always)
+	}
+}
+
+func TestProfileDisablesChecks(t *testing.T) {
+	cfg := config.Config{
+		Operation: map[string]*config.OperationConfig{
+			"main": {Collector: "LocalCollector", Checks: []string{"HasNoWhiteSpace"}},
+		},
+		Rules: map[string]*config.RuleConfig{
+			"Always": {Rule: "true"},
+		},
+	}
+	files := []structs.File{{Name: "with space.txt"}, {Name: "nöascii.txt"}}
+
+	messages := ApplyAllChecks(cfg, files, true)
+	if len(messages) != 1 || messages[0].TestName != "HasNoWhiteSpace" {
+		t.Errorf("expected only HasNoWhiteSpace to run, got %+v", messages)
+	}
+}
+
+func TestCancelledScan(t *testing.T) {
+	cfg := config.Config{
+		Operation: map[string]*config.OperationConfig{
+			"main": {Collector: "LocalCollector", Checks: []string{"HasNoWhiteSpace"}},
+		},
+	}
+	files := []structs.File{{Name: "with space.txt"}, {Name: "more space.txt"}, {Name: "last space.txt"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg = cfg.WithContext(ctx)
+	if messages := ApplyAllChecks(cfg, files, true); len(messages) != 3 {
+		t.Fatalf("expected 3 messages before cancelling, got %+v", messages)
+	}
+
+	cancel()
+	if messages := ApplyAllChecks(cfg, files, true); len(messages) != 0 {
+		t.Errorf("expected a cancelled scan to skip the checks, got %+v", messages)
+	}
+	if messages := ApplyChecksFilteredByFileWithTestProgress(cfg, BY_FILE, files, nil); len(messages) != 0 {
+		t.Errorf("expected a cancelled sequential scan to skip the checks, got %+v", messages)
+	}
+	var streamed []structs.Message
+	ApplyAllChecksStreaming(cfg, files, true, func(messages []structs.Message) {
+		streamed = append(streamed, messages...)
+	})
+	if len(streamed) != 0 {
+		t.Errorf("expected a cancelled streaming scan to skip the checks, got %+v", streamed)
+	}
+}
+
+func TestFileTypeScoping(t *testing.T) {
+	cfg := config.Config{
+		Operation: map[string]*config.OperationConfig{
+			"main": {Checks: []string{"HasNoWhiteSpace"}},
+		},
+		Tests: map[string]*config.TestConfig{
+			"HasNoWhiteSpace": {IncludeExtensions: []string{"csv"}},
+		},
+	}
+	files := []structs.File{{Name: "my data.csv"}, {Name: "my plot.png"}}
+
+	var checked []string
+	for _, message := range ApplyChecksFilteredByFile(cfg, BY_FILE, files) {
+		if message.TestName == "HasNoWhiteSpace" {
+			checked = append(checked, message.Source.(structs.File).Name)
+		}
+	}
+	if !reflect.DeepEqual(checked, []string{"my data.csv"}) {
+		t.Errorf("expected only the csv file to be checked, got %v", checked)
+	}
+}
+
+func TestThrottleArchiveProgress(t *testing.T) {
+	var reported []string
+	progress := throttleArchiveProgress(func(archive string, member, total int) {
+		reported = append(reported, describeArchiveProgress(archive, member, total))
+	}, time.Hour)
+
+	progress("data.zip", 1, 500)
+	progress("data.zip", 2, 500)
+	progress("data.tar", 1, 0)
+	if len(reported) != 1 || reported[0] != "data.zip: member 1 of 500" {
+		t.Errorf("Expected only the first report within the interval, got %v", reported)
+	}
+
+	progress = throttleArchiveProgress(func(archive string, member, total int) {
+		reported = append(reported, describeArchiveProgress(archive, member, total))
+	}, 0)
+	progress("data.tar", 3, 0)
+	if reported[len(reported)-1] != "data.tar: member 3" {
+		t.Errorf("Expected the member without a total for tar archives, got %v", reported)
+	}
+}
+
+func TestScanTimeout(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	cfg, stop := withScanTimeout(config.Config{General: &config.GeneralConfig{}})
+	stop()
+	if cancelled(cfg) || len(output.GlobalLogger.GetMessages()) != 0 {
+		t.Errorf("expected no timeout without a scanTimeout, got %+v", output.GlobalLogger.GetMessages())
+	}
+
+	cfg, stop = withScanTimeout(config.Config{General: &config.GeneralConfig{ScanTimeout: time.Millisecond}})
+	<-cfg.Context().Done()
+	if !cancelled(cfg) {
+		t.Error("expected the scan to end after the scanTimeout")
+	}
+	stop()
+	warnings := output.GlobalLogger.GetMessages()
+	if len(warnings) != 1 || warnings[0].Level != "warning" {
+		t.Errorf("expected a warning about the unchecked files, got %+v", warnings)
+	}
+}
+
+func TestFileTimeoutProgress(t *testing.T) {
+	output.GlobalLogger.SetJSONMode(true)
+	output.GlobalLogger.ClearMessages()
+	defer output.GlobalLogger.SetJSONMode(false)
+	defer output.GlobalLogger.ClearMessages()
+
+	release := make(chan struct{})
+	defer close(release)
+	fast := func(file structs.File, config config.Config) []structs.Message {
+		return []structs.Message{{Content: "found"}}
+	}
+	slow := func(file structs.File, config config.Config) []structs.Message {
+		if file.Name == "huge.log" {
+			<-release
+		}
+		return nil
+	}
+	cfg := config.Config{General: &config.GeneralConfig{FileTimeout: 20 * time.Millisecond}}.WithSkippedFiles(output.NewSkippedFiles())
+	files := []structs.File{{Name: "huge.log"}, {Name: "small.txt"}}
+
+	var progress []int
+	messages := ApplyChecksFilteredByFileWithTestProgress(cfg, []func(file structs.File, config config.Config) []structs.Message{slow, fast}, files, func(current int) {
+		progress = append(progress, current)
+	})
+	if len(messages) != 1 {
+		t.Errorf("expected only the findings of the file in time, got %+v", messages)
+	}
+	if !reflect.DeepEqual(progress, []int{1, 2, 3, 4}) {
+		t.Errorf("expected the checks cut off by the timeout to be counted, got %v", progress)
+	}
+	if skipped := cfg.SkippedFiles().Files(); len(skipped) != 1 || skipped[0].Code != output.SkipTimeout {
+		t.Errorf("expected the timed out file to be recorded as skipped, got %+v", skipped)
+	}
+}
+
+func TestWithMemoryBudget(t *testing.T) {
+	cfg := withMemoryBudget(config.Config{General: &config.GeneralConfig{MaxTotalMemory: 1024}})
+	if cfg.MemoryBudget().Limit() != 1024 {
+		t.Fatalf("expected a budget of maxTotalMemory, got %d bytes", cfg.MemoryBudget().Limit())
+	}
+	if withMemoryBudget(cfg).MemoryBudget() != cfg.MemoryBudget() {
+		t.Error("expected the budget of the config to be kept")
+	}
+	if withMemoryBudget(config.Config{General: &config.GeneralConfig{}}).MemoryBudget() != nil {
+		t.Error("expected no budget for a maxTotalMemory of 0")
+	}
+}
+
+func TestMetadataChecks(t *testing.T) {
+	cfg := config.Config{}.WithMetadata(structs.Metadata{Package: "lake-ice", Notes: "Ice cover"})
+
+	// Without files only the metadata is checked, the repository checks find no readme otherwise
+	messages := ApplyAllChecks(cfg, nil, true)
+	if len(messages) != 1 || messages[0].TestName != "HasLicense" || messages[0].Source != (structs.Metadata{Package: "lake-ice", Notes: "Ice cover"}) {
+		t.Errorf("expected only the missing license, got %+v", messages)
+	}
+
+	var progress []string
+	messages = ApplyAllChecksWithProgress(cfg, nil, true, func(current, total int, message string) {
+		progress = append(progress, message)
+	})
+	if len(messages) != 1 || !strings.Contains(strings.Join(progress, "\n"), "Running metadata tests...") {
+		t.Errorf("expected the metadata checks to be reported, got %+v (%v)", messages, progress)
+	}
+
+	var streamed []structs.Message
+	ApplyAllChecksStreaming(cfg, nil, true, func(batch []structs.Message) {
+		streamed = append(streamed, batch...)
+	})
+	if len(streamed) != 1 || streamed[0].TestName != "HasLicense" {
+		t.Errorf("expected the streamed metadata issue, got %+v", streamed)
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateMessages(t *testing.T) {
+	logFile := structs.File{Path: "/data/run.log", Name: "run.log"}
+	otherFile := structs.File{Path: "/data/other.log", Name: "other.log"}
+	messages := []structs.Message{
+		{Content: "Security credentials detected 'password'", Source: logFile, TestName: "IsFreeOfKeywords", Line: 40, Snippet: "»password« = 1"},
+		{Content: "Security credentials detected 'password'", Source: logFile, TestName: "IsFreeOfKeywords", Line: 7, Snippet: "»password« = 2"},
+		{Content: "Security credentials  detected 'password' ", Source: logFile, TestName: "IsFreeOfKeywords", Line: 7},
+		{Content: "Security credentials detected 'password'", Source: otherFile, TestName: "IsFreeOfKeywords", Line: 1},
+		{Content: "Security credentials detected 'password'", Source: logFile, TestName: "Rule", Line: 3},
+		{Content: "File name contains spaces", Source: logFile, TestName: "HasNoWhiteSpace"},
+	}
+
+	result := DeduplicateMessages(messages)
+	if assert.Len(t, result, 4) {
+		merged := result[0]
+		assert.Equal(t, 3, merged.Count)
+		assert.Equal(t, []int{7, 40}, merged.Lines)
+		assert.Equal(t, 7, merged.Line)
+		assert.Equal(t, "»password« = 1", merged.Snippet, "the first snippet is kept")
+
+		for _, single := range result[1:] {
+			assert.Zero(t, single.Count)
+			assert.Nil(t, single.Lines)
+		}
+		assert.Equal(t, otherFile, result[1].Source)
+		assert.Equal(t, "Rule", result[2].TestName)
+	}
+
+	repository := structs.Repository{}
+	result = DeduplicateMessages([]structs.Message{
+		{Content: "No README file found", Source: repository, TestName: "HasReadme"},
+		{Content: "No README file found", Source: repository, TestName: "HasReadme"},
+	})
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, 2, result[0].Count)
+		assert.Empty(t, result[0].Lines)
+		assert.Equal(t, "2 occurrences", result[0].Location())
+	}
+}
+
+func TestLimitFindings(t *testing.T) {
+	logFile := structs.File{Path: "/data/run.log", Name: "run.log"}
+	otherFile := structs.File{Path: "/data/other.log", Name: "other.log"}
+	messages := []structs.Message{
+		{Content: "a", Source: logFile, TestName: "IsFreeOfKeywords"},
+		{Content: "b", Source: logFile, TestName: "IsFreeOfKeywords"},
+		{Content: "spaces", Source: logFile, TestName: "HasNoWhiteSpace"},
+		{Content: "c", Source: logFile, TestName: "IsFreeOfKeywords", Omitted: 5},
+		{Content: "a", Source: otherFile, TestName: "IsFreeOfKeywords", Omitted: 2},
+	}
+
+	result := LimitFindings(messages, 2)
+	if assert.Len(t, result, 6) {
+		assert.Equal(t, "b", result[1].Content)
+		assert.Equal(t, "... and 6 more findings (maxFindingsPerCheck = 2)", result[2].Content)
+		assert.Equal(t, logFile, result[2].Source)
+		assert.Equal(t, "IsFreeOfKeywords", result[2].TestName)
+		assert.Equal(t, "spaces", result[3].Content)
+		assert.Zero(t, result[4].Omitted)
+		assert.Equal(t, "... and 2 more findings (maxFindingsPerCheck = 2)", result[5].Content)
+	}
+
+	assert.Equal(t, messages, LimitFindings(messages, 0))
+}
+
+func TestSortMessages(t *testing.T) {
+	data := structs.File{Path: "/data/data.csv", Name: "data.csv"}
+	log := structs.File{Path: "/data/run.log", Name: "run.log"}
+	member := structs.File{Path: "/data/a.zip", Name: "notes.txt", ArchiveName: "a.zip"}
+	messages := []structs.Message{
+		{Content: "No ReadMe file in repository.", Source: structs.Repository{}, TestName: "HasReadme"},
+		{Content: "File name contains spaces", Source: log, TestName: "HasNoWhiteSpace"},
+		{Content: "The package has no license.", Source: structs.Metadata{}, TestName: "HasLicense"},
+		{Content: "Credentials detected 'secret'", Source: member, TestName: "IsFreeOfKeywords"},
+		{Content: "Credentials detected 'password'", Source: log, TestName: "IsFreeOfKeywords"},
+		{Content: "Credentials detected 'token'", Source: data, TestName: "IsFreeOfKeywords"},
+	}
+
+	result := sortMessages(messages)
+	var order []string
+	for _, msg := range result {
+		order = append(order, msg.Content)
+	}
+	assert.Equal(t, []string{
+		"Credentials detected 'token'",
+		"File name contains spaces",
+		"Credentials detected 'password'",
+		"Credentials detected 'secret'",
+		"The package has no license.",
+		"No ReadMe file in repository.",
+	}, order, "files first, the messages of a file in the order of their checks")
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/output"
 	"github.com/eawag-rdm/pc/pkg/structs"
 	"github.com/stretchr/testify/assert"
 )
@@ -151,4 +154,47 @@ func TestTruncateMessages(t *testing.T) {
 			assert.NotContains(t, msg.Content, "truncated")
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestTranslateMessages(t *testing.T) {
+	messages := []structs.Message{{Content: "The package has no license.", TestName: "HasLicense", Line: 3}}
+	if got := TranslateMessages(messages); !reflect.DeepEqual(got, messages) {
+		t.Errorf("Expected English messages to stay as they are, got %v", got)
+	}
+	if err := i18n.SetLanguage(i18n.German); err != nil {
+		t.Fatal(err)
+	}
+	defer i18n.SetLanguage(i18n.English)
+	expected := []structs.Message{{Content: "Das Paket hat keine Lizenz.", TestName: "HasLicense", Line: 3}}
+	if got := TranslateMessages(messages); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if messages[0].Content != "The package has no license." {
+		t.Errorf("Expected the messages to be left unchanged, got %v", messages)
+	}
+}
+
+func TestRedactMessages(t *testing.T) {
+	redactor := output.NewRedactor("/data/lake-ice")
+	messages := []structs.Message{{
+		Content:  "Found a key in /data/lake-ice/raw/config.yml",
+		Snippet:  "key_file = /opt/keys/lake.pem",
+		TestName: "IsFreeOfKeywords",
+		Source:   structs.File{Name: "config.yml", Path: "/data/lake-ice/raw/config.yml"},
+	}}
+	expected := []structs.Message{{
+		Content:  "Found a key in raw/config.yml",
+		Snippet:  "key_file = <redacted>/lake.pem",
+		TestName: "IsFreeOfKeywords",
+		Source:   structs.File{Name: "config.yml", Path: "raw/config.yml"},
+	}}
+	if got := RedactMessages(messages, redactor); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if messages[0].Source.(structs.File).Path != "/data/lake-ice/raw/config.yml" {
+		t.Errorf("Expected the messages to be left unchanged, got %v", messages)
+	}
+	if got := RedactMessages(messages, nil); !reflect.DeepEqual(got, messages) {
+		t.Errorf("Expected no redactor to keep the messages, got %v", got)
+	}
+}
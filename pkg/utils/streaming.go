@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/optimization"
+	"github.com/eawag-rdm/pc/pkg/performance"
+	"github.com/eawag-rdm/pc/pkg/plugins"
+	"github.com/eawag-rdm/pc/pkg/rules"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+// ApplyAllChecksStreaming runs the same checks as ApplyAllChecks but hands the findings of each
+// file to emit as soon as the file is checked, instead of collecting all of them. Files are checked
+// in parallel and emitted in the order they finish; emit is never called concurrently. Findings
+// are merged and limited per file as in ApplyAllChecks. Metadata and repository checks are
+// emitted last.
+func ApplyAllChecksStreaming(config config.Config, files []structs.File, checksAcrossFiles bool, emit func([]structs.Message)) {
+	config, stop := withScanTimeout(config)
+	defer stop()
+	config = withScanState(withMemoryBudget(config))
+	ruleChecks, err := rules.Load(config)
+	if err != nil {
+		logger.Warning("%v", err)
+	}
+	pluginChecks := plugins.Load(config)
+
+	numWorkers := runtime.NumCPU()
+	if len(files) < numWorkers {
+		numWorkers = len(files)
+	}
+	// Archive contents are extracted into memory, as in applyArchiveChecksParallel only half of
+	// the workers may do so at the same time
+	archiveSlots := make(chan struct{}, max(1, runtime.NumCPU()/2))
+
+	fileChan := make(chan structs.File)
+	resultChan := make(chan []structs.Message)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range fileChan {
+				resultChan <- checkFileWithTimeout(config, file, ruleChecks, pluginChecks, archiveSlots)
+			}
+		}()
+	}
+	go func() {
+		for _, file := range files {
+			fileChan <- file
+		}
+		close(fileChan)
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	limit := maxFindingsPerCheck(config)
+	for messages := range resultChan {
+		if len(messages) > 0 {
+			emit(LimitFindings(DeduplicateMessages(messages), limit))
+		}
+	}
+
+	if messages := ApplyChecksOnMetadata(config, BY_METADATA); len(messages) > 0 {
+		emit(LimitFindings(DeduplicateMessages(messages), limit))
+	}
+	if !checksAcrossFiles {
+		return
+	}
+	messages := ApplyChecksFilteredByRepository(config, BY_REPOSITORY, files)
+	for _, plugin := range pluginChecks {
+		if plugin.Scope != "repository" || !config.IsCheckEnabled(plugin.Name) {
+			continue
+		}
+		ret, err := plugin.CheckRepository(structs.Repository{Files: files}, config)
+		if err != nil {
+			logger.Warning("%v", err)
+			continue
+		}
+		messages = append(messages, ret...)
+	}
+	if len(messages) > 0 {
+		emit(LimitFindings(DeduplicateMessages(messages), limit))
+	}
+}
+
+// checkFileWithTimeout runs checkFile within the fileTimeout of the config, the checks share the
+// content of the file
+func checkFileWithTimeout(cfg config.Config, file structs.File, ruleChecks []*rules.Rule, pluginChecks []*plugins.ExternalCheck, archiveSlots chan struct{}) []structs.Message {
+	content := performance.NewFileContent(file.Path, cfg.MemoryBudget())
+	defer content.Close()
+	cfg = cfg.WithFileContent(content)
+	return optimization.WithFileTimeout(cfg, file, func(cfg config.Config) []structs.Message {
+		return checkFile(cfg, file, ruleChecks, pluginChecks, archiveSlots)
+	})
+}
+
+// checkFile runs all checks of a single file: the file checks, the checks of the files listed in
+// and contained in an archive, and the rule and plugin checks
+func checkFile(cfg config.Config, file structs.File, ruleChecks []*rules.Rule, pluginChecks []*plugins.ExternalCheck, archiveSlots chan struct{}) []structs.Message {
+	if cancelled(cfg) {
+		return nil
+	}
+	cfg.PDFTracker().AddFileIfPDF("", file)
+
+	var messages []structs.Message
+	for _, check := range BY_FILE {
+		messages = append(messages, runFileCheck(cfg, check, file)...)
+	}
+
+	if file.IsArchive {
+		messages = append(messages, processArchiveFileList(cfg, BY_FILE_ON_ARCHIVE_FILE_LIST, file)...)
+
+		archiveSlots <- struct{}{}
+		for _, check := range BY_FILE_ON_ARCHIVE {
+			messages = append(messages, runFileCheck(cfg, check, file)...)
+		}
+		<-archiveSlots
+	}
+
+	// The rules also see the members of an archive, like the archive file list checks
+	if len(ruleChecks) > 0 {
+		for _, ruleFile := range withArchiveMembers(cfg, []structs.File{file}) {
+			for _, rule := range ruleChecks {
+				if !cfg.IsCheckEnabled(rule.Name) || skipFileCheckByName(cfg, rule.Name, ruleFile) {
+					continue
+				}
+				start := time.Now()
+				ret, err := rule.Check(ruleFile)
+				traceRun(cfg, rule.Name, ruleFile, start, len(ret))
+				if err != nil {
+					logger.Warning("%v (file: '%s')", err, ruleFile.Name)
+					continue
+				}
+				messages = append(messages, ret...)
+			}
+		}
+	}
+
+	for _, plugin := range pluginChecks {
+		if plugin.Scope == "repository" || !cfg.IsCheckEnabled(plugin.Name) || skipFileCheckByName(cfg, plugin.Name, file) {
+			continue
+		}
+		start := time.Now()
+		ret, err := plugin.CheckFile(file, cfg)
+		traceRun(cfg, plugin.Name, file, start, len(ret))
+		if err != nil {
+			logger.Warning("%v (file: '%s')", err, file.Name)
+			continue
+		}
+		messages = append(messages, ret...)
+	}
+	return messages
+}
+
+// runFileCheck runs a single file check unless it is skipped for the file
+func runFileCheck(cfg config.Config, check func(file structs.File, config config.Config) []structs.Message, file structs.File) []structs.Message {
+	if skipFileCheck(cfg, check, file) {
+		return nil
+	}
+	start := time.Now()
+	ret := check(file, cfg)
+	testName := getFunctionName(check)
+	traceRun(cfg, testName, file, start, len(ret))
+	for i := range ret {
+		ret[i].TestName = testName
+	}
+	return ret
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/structs"
+)
+
+func TestApplyAllChecksStreaming(t *testing.T) {
+	cfg := config.Config{
+		Operation: map[string]*config.OperationConfig{
+			"main": {Collector: "LocalCollector", Checks: []string{"HasNoWhiteSpace", "Always", "Licence"}},
+		},
+		Rules: map[string]*config.RuleConfig{
+			"Always": {Rule: "true", Message: "{{.file.name}} checked"},
+		},
+		Plugins: map[string]*config.PluginConfig{
+			"Licence": {Command: []string{"sh", "-c", `cat > /dev/null; echo '{"messages": [{"content": "no licence"}]}'`}, Scope: "repository"},
+		},
+	}
+	files := []structs.File{{Name: "with space.txt"}, {Name: "a.txt"}, {Name: "b.txt"}}
+
+	var batches [][]structs.Message
+	ApplyAllChecksStreaming(cfg, files, true, func(messages []structs.Message) {
+		batches = append(batches, messages)
+	})
+
+	if len(batches) != 4 {
+		t.Fatalf("expected one batch per file and one for the repository, got %+v", batches)
+	}
+	total := 0
+	for _, batch := range batches[:3] {
+		source := batch[0].Source.(structs.File)
+		for _, message := range batch {
+			if message.Source.(structs.File).Name != source.Name {
+				t.Errorf("expected the findings of one file per batch, got %+v", batch)
+			}
+		}
+		total += len(batch)
+	}
+	if repo := batches[3]; len(repo) != 1 || repo[0].TestName != "Licence" {
+		t.Errorf("expected the repository checks last, got %+v", repo)
+	}
+	if expected := ApplyAllChecks(cfg, files, true); total+1 != len(expected) {
+		t.Errorf("expected the findings of ApplyAllChecks, got %d of %d", total+1, len(expected))
+	}
+
+	batches = nil
+	ApplyAllChecksStreaming(cfg, files, false, func(messages []structs.Message) {
+		batches = append(batches, messages)
+	})
+	if len(batches) != 3 {
+		t.Errorf("expected no repository batch without checks across files, got %+v", batches)
+	}
+}
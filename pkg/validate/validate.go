@@ -0,0 +1,1014 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	"github.com/eawag-rdm/pc/pkg/notify"
+	"github.com/eawag-rdm/pc/pkg/rules"
+)
+
+// Severity of a diagnostic; errors make the config unusable, warnings point at likely mistakes
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single problem found in a config file
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"` // 0 if the problem has no position, e.g. a missing section
+	Field    string   `json:"field,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic as "file:line: severity: field: message"
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+	if d.Field == "" {
+		return fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", location, d.Severity, d.Field, d.Message)
+}
+
+// HasErrors reports whether any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Collectors maps the supported collectors to the attrs they require and their types
+var Collectors = map[string]map[string]string{
+	"LocalCollector": {},
+	"CkanCollector": {
+		"url":               "string",
+		"token":             "string",
+		"verify":            "bool",
+		"ckan_storage_path": "string",
+	},
+}
+
+var (
+	knownSections  = []string{"general", "operation", "test", "collector", "notify", "plugin", "rule"}
+	sizeKeys       = []string{"maxArchiveFileSize", "maxTotalArchiveMemory", "maxContentScanFileSize", "maxTotalMemory", "maxWorkspaceSize", "sampleThreshold", "sampleWindow"}
+	generalKeys    = append([]string{"historyDir", "outputDir", "workspaceDir", "baseline", "language", "timezone", "letterTemplate", "redact", "listArchives", "fileMetadata", "hashFiles", "summaryMaxIssues", "summaryMinGroupSize", "maxFindingsPerCheck", "fileTimeout", "scanTimeout", "scanStrategy", "sampleBlocks"}, sizeKeys...)
+	testKeys       = []string{"blacklist", "whitelist", "include_extensions", "exclude_extensions", "keywordArguments", "severity", "report_only"}
+	ruleKeys       = []string{"rule", "message"}
+	pluginKeys     = []string{"command", "scope", "timeout"}
+	profileKeys    = []string{"collector", "checks", "maxArchiveFileSize", "maxTotalArchiveMemory", "maxContentScanFileSize", "test"}
+	publishModes   = []string{"", "resource", "extra"}
+	metadataModes  = []string{"", "check", "only"}
+	tableHeader    = regexp.MustCompile(`^\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+	keyAssignment  = regexp.MustCompile(`^("[^"]*"|'[^']*'|[A-Za-z0-9_.\-" ]+?)\s*=`)
+	inlineTableKey = regexp.MustCompile(`[{,]\s*("[^"]*"|[A-Za-z0-9_\-]+)\s*=`)
+)
+
+// validator collects the diagnostics of one file
+type validator struct {
+	file        string
+	lines       map[string]int
+	diagnostics []Diagnostic
+}
+
+// File validates the config file or https:// URL at path and returns all problems found, sorted by line
+func File(path string) []Diagnostic {
+	v := &validator{file: path}
+
+	content, err := config.ReadConfig(path, false)
+	if err != nil {
+		v.report(SeverityError, "", 0, "cannot read config: %v", err)
+		return v.diagnostics
+	}
+
+	var raw map[string]interface{}
+	if _, err := toml.Decode(string(content), &raw); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			// Error() reads "toml: line N (last key ...): message", the position is reported separately
+			message := parseErr.Error()
+			if parts := strings.SplitN(message, ": ", 3); len(parts) == 3 {
+				message = parts[2]
+			}
+			v.report(SeverityError, "", parseErr.Position.Line, "invalid TOML: %s", message)
+		} else {
+			v.report(SeverityError, "", 0, "invalid TOML: %v", err)
+		}
+		return v.diagnostics
+	}
+	v.lines = indexLines(string(content))
+
+	v.checkSections(raw)
+	v.checkGeneral(raw)
+	v.checkCollectors(raw, v.checkOperations(raw))
+	v.checkRules(raw)
+	v.checkPlugins(raw)
+	v.checkTests(raw)
+	v.checkNotifiers(raw)
+
+	// Problems without a position, such as missing sections, come last
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		a, b := v.diagnostics[i].Line, v.diagnostics[j].Line
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return v.diagnostics
+}
+
+// indexLines maps dotted key paths to the line they are defined on.
+// Keys of inline tables are attributed to the line of the inline table, the
+// inline tables of a multi-line array are recorded as key[0], key[1], ...
+func indexLines(content string) map[string]int {
+	lines := map[string]int{}
+	record := func(path string, line int) {
+		if _, ok := lines[path]; !ok {
+			lines[path] = line
+		}
+	}
+
+	table := ""
+	array, element, depth := "", 0, 0
+	for i, text := range strings.Split(content, "\n") {
+		line := i + 1
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if depth > 0 {
+			if strings.HasPrefix(text, "{") {
+				record(fmt.Sprintf("%s[%d]", array, element), line)
+				element++
+			}
+			depth += strings.Count(text, "[") - strings.Count(text, "]")
+			continue
+		}
+		if m := tableHeader.FindStringSubmatch(text); m != nil {
+			table = normalizeKey(m[1])
+			record(table, line)
+			continue
+		}
+		m := keyAssignment.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		path := normalizeKey(m[1])
+		if table != "" {
+			path = table + "." + path
+		}
+		record(path, line)
+		if value := text[len(m[0]):]; strings.HasPrefix(strings.TrimSpace(value), "[") {
+			array, element, depth = path, 0, strings.Count(value, "[")-strings.Count(value, "]")
+		}
+		for _, inner := range inlineTableKey.FindAllStringSubmatch(text[len(m[0]):], -1) {
+			record(path+"."+normalizeKey(inner[1]), line)
+		}
+	}
+	return lines
+}
+
+// normalizeKey strips quotes and the spaces around dots of a TOML key
+func normalizeKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// lineOf returns the line of field, falling back to its closest defined parent
+func (v *validator) lineOf(field string) int {
+	for field != "" {
+		if line, ok := v.lines[field]; ok {
+			return line
+		}
+		i := strings.LastIndex(field, ".")
+		if j := strings.LastIndex(field, "["); j > i {
+			i = j
+		}
+		if i < 0 {
+			break
+		}
+		field = field[:i]
+	}
+	return 0
+}
+
+func (v *validator) report(severity Severity, field string, line int, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		File:     v.file,
+		Line:     line,
+		Field:    field,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) errorf(field string, format string, args ...interface{}) {
+	v.report(SeverityError, field, v.lineOf(field), format, args...)
+}
+
+func (v *validator) warnf(field string, format string, args ...interface{}) {
+	v.report(SeverityWarning, field, v.lineOf(field), format, args...)
+}
+
+// tables returns the subtables of a top level section such as [test.*], sorted by name
+func (v *validator) tables(raw map[string]interface{}, section string) ([]string, map[string]map[string]interface{}) {
+	data, ok := raw[section].(map[string]interface{})
+	if !ok {
+		if _, exists := raw[section]; exists {
+			v.errorf(section, "expected a table")
+		}
+		return nil, nil
+	}
+	var names []string
+	result := map[string]map[string]interface{}{}
+	for name, value := range data {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			v.errorf(section+"."+name, "expected a table, got %s", typeName(value))
+			continue
+		}
+		names = append(names, name)
+		result[name] = table
+	}
+	sort.Strings(names)
+	return names, result
+}
+
+// unknownKeys warns about keys of a table that are not in known, they are silently ignored otherwise
+func (v *validator) unknownKeys(field string, table map[string]interface{}, known []string) {
+	for _, key := range sortedKeys(table) {
+		if !contains(known, key) {
+			v.warnf(field+"."+key, "unknown key, expected one of %s%s", strings.Join(known, ", "), suggestion(key, known))
+		}
+	}
+}
+
+func (v *validator) checkSections(raw map[string]interface{}) {
+	for _, key := range sortedKeys(raw) {
+		if !contains(knownSections, key) {
+			v.warnf(key, "unknown section, expected one of %s%s", strings.Join(knownSections, ", "), suggestion(key, knownSections))
+		}
+	}
+}
+
+func (v *validator) checkGeneral(raw map[string]interface{}) {
+	general, ok := raw["general"].(map[string]interface{})
+	if !ok {
+		if _, exists := raw["general"]; exists {
+			v.errorf("general", "expected a table")
+		}
+		return
+	}
+	v.unknownKeys("general", general, generalKeys)
+	if value, exists := general["historyDir"]; exists && typeName(value) != "string" {
+		v.errorf("general.historyDir", "expected string, got %s", typeName(value))
+	}
+	if value, exists := general["outputDir"]; exists && typeName(value) != "string" {
+		v.errorf("general.outputDir", "expected string, got %s", typeName(value))
+	}
+	if value, exists := general["workspaceDir"]; exists && typeName(value) != "string" {
+		v.errorf("general.workspaceDir", "expected string, got %s", typeName(value))
+	}
+	if value, exists := general["baseline"]; exists && typeName(value) != "string" {
+		v.errorf("general.baseline", "expected string, got %s", typeName(value))
+	}
+	if value, exists := general["redact"]; exists && typeName(value) != "bool" {
+		v.errorf("general.redact", "expected bool, got %s", typeName(value))
+	}
+	for _, key := range []string{"listArchives", "fileMetadata", "hashFiles"} {
+		if value, exists := general[key]; exists && typeName(value) != "bool" {
+			v.errorf("general."+key, "expected bool, got %s", typeName(value))
+		}
+	}
+	if value, exists := general["language"]; exists {
+		if language, ok := value.(string); !ok {
+			v.errorf("general.language", "expected string, got %s", typeName(value))
+		} else if !i18n.IsLanguage(language) {
+			v.errorf("general.language", "unknown language '%s', expected one of %s", language, strings.Join(i18n.Languages(), ", "))
+		}
+	}
+	if value, exists := general["timezone"]; exists {
+		if timezone, ok := value.(string); !ok {
+			v.errorf("general.timezone", "expected string, got %s", typeName(value))
+		} else if _, err := i18n.LoadTimezone(timezone); err != nil {
+			v.errorf("general.timezone", "%v", err)
+		}
+	}
+	if value, exists := general["scanStrategy"]; exists {
+		strategies := []string{config.ScanStrategyFull, config.ScanStrategySample}
+		if strategy, ok := value.(string); !ok {
+			v.errorf("general.scanStrategy", "expected string, got %s", typeName(value))
+		} else if !contains(strategies, strategy) {
+			v.errorf("general.scanStrategy", "unknown scan strategy '%s', expected one of %s", strategy, strings.Join(strategies, ", "))
+		}
+	}
+	if value, exists := general["sampleBlocks"]; exists {
+		if blocks, ok := value.(int64); !ok || blocks < 0 {
+			v.errorf("general.sampleBlocks", "expected a number of blocks, got %v", value)
+		}
+	}
+	if value, exists := general["letterTemplate"]; exists {
+		if path, ok := value.(string); !ok {
+			v.errorf("general.letterTemplate", "expected string, got %s", typeName(value))
+		} else if _, err := os.Stat(path); path != "" && err != nil {
+			v.errorf("general.letterTemplate", "cannot read letter template: %v", err)
+		}
+	}
+	if value, exists := general["maxFindingsPerCheck"]; exists {
+		if limit, ok := value.(int64); !ok || limit < 0 {
+			v.errorf("general.maxFindingsPerCheck", "expected a number of findings (0 for no limit), got %s", typeName(value))
+		}
+	}
+	for _, key := range []string{"summaryMaxIssues", "summaryMinGroupSize"} {
+		if value, exists := general[key]; exists {
+			if limit, ok := value.(int64); !ok || limit < 0 {
+				v.errorf("general."+key, "expected a number of issues, got %s", typeName(value))
+			}
+		}
+	}
+	for _, key := range []string{"fileTimeout", "scanTimeout"} {
+		if value, exists := general[key]; exists {
+			if _, err := config.ParseDuration(value); err != nil {
+				v.errorf("general."+key, "%v", err)
+			}
+		}
+	}
+	v.checkSizes("general", general)
+}
+
+// checkSizes validates the size limits of [general] or a profile, given in bytes or with a unit
+func (v *validator) checkSizes(field string, table map[string]interface{}) {
+	for _, key := range sizeKeys {
+		value, exists := table[key]
+		if !exists {
+			continue
+		}
+		if size, err := config.ParseSize(value); err != nil {
+			v.errorf(field+"."+key, "%v", err)
+		} else if size == 0 && key != "maxTotalMemory" && key != "maxWorkspaceSize" {
+			// Only the memory and disk budgets of the scan have 0 for no limit
+			v.errorf(field+"."+key, "must be greater than 0")
+		}
+	}
+}
+
+// checkOperations validates the [operation.*] profiles and returns the collectors they select
+func (v *validator) checkOperations(raw map[string]interface{}) map[string]bool {
+	names, profiles := v.tables(raw, "operation")
+	collectors, _ := raw["collector"].(map[string]interface{})
+	known := sortedKeys(Collectors)
+	used := map[string]bool{}
+
+	if _, ok := profiles[config.DefaultProfile]; !ok {
+		v.errorf("operation.main", "missing [operation.main] section selecting the collector")
+	}
+	for _, name := range names {
+		field := "operation." + name
+		profile := profiles[name]
+		v.unknownKeys(field, profile, profileKeys)
+
+		switch collector := profile["collector"].(type) {
+		case nil:
+			if name == config.DefaultProfile {
+				v.errorf(field+".collector", "missing collector, expected one of %s", strings.Join(known, ", "))
+			}
+		case string:
+			if _, ok := Collectors[collector]; !ok {
+				v.errorf(field+".collector", "unknown collector '%s', expected one of %s%s", collector, strings.Join(known, ", "), suggestion(collector, known))
+			} else if _, ok := collectors[collector]; !ok {
+				v.errorf(field+".collector", "collector '%s' has no [collector.%s] section", collector, collector)
+			} else {
+				used[collector] = true
+			}
+		default:
+			v.errorf(field+".collector", "expected string, got %s", typeName(collector))
+		}
+
+		if value, exists := profile["checks"]; exists {
+			enabled, ok := value.([]interface{})
+			if !ok || !isStringList(enabled) {
+				v.errorf(field+".checks", "expected a list of strings, got %s", typeName(value))
+			} else {
+				available := checkNames(raw)
+				for _, check := range enabled {
+					if !contains(available, check.(string)) {
+						v.errorf(field+".checks", "unknown check '%s'%s", check, suggestion(check.(string), available))
+					}
+				}
+			}
+		}
+		v.checkSizes(field, profile)
+		if _, exists := profile["test"]; exists {
+			tests, sections := v.tables(profile, "test")
+			for _, test := range tests {
+				v.checkTest(raw, field+".test."+test, test, sections[test])
+			}
+		}
+	}
+	return used
+}
+
+func (v *validator) checkCollectors(raw map[string]interface{}, used map[string]bool) {
+	names, sections := v.tables(raw, "collector")
+	known := sortedKeys(Collectors)
+
+	for _, name := range names {
+		field := "collector." + name
+		if _, ok := Collectors[name]; !ok {
+			v.warnf(field, "unknown collector, expected one of %s%s", strings.Join(known, ", "), suggestion(name, known))
+			continue
+		}
+		if len(used) > 0 && !used[name] {
+			v.warnf(field, "collector is never used, no [operation.*] profile selects it")
+		}
+
+		attrs, ok := sections[name]["attrs"].(map[string]interface{})
+		if !ok {
+			if sections[name]["attrs"] != nil {
+				v.errorf(field+".attrs", "expected a table, got %s", typeName(sections[name]["attrs"]))
+				continue
+			}
+			attrs = map[string]interface{}{}
+		}
+		for _, attr := range sortedKeys(Collectors[name]) {
+			expected := Collectors[name][attr]
+			value, exists := attrs[attr]
+			if !exists {
+				if used[name] {
+					v.errorf(field+".attrs", "missing required attribute '%s' (%s)", attr, expected)
+				}
+				continue
+			}
+			if typeName(value) != expected {
+				v.errorf(field+".attrs."+attr, "expected %s, got %s", expected, typeName(value))
+			}
+		}
+		if name == "LocalCollector" {
+			v.checkLocalAttrs(field+".attrs", attrs)
+		}
+		if value, exists := attrs["publish"]; exists && name == "CkanCollector" {
+			mode, ok := value.(string)
+			if !ok {
+				v.errorf(field+".attrs.publish", "expected string, got %s", typeName(value))
+			} else if !contains(publishModes, mode) {
+				v.errorf(field+".attrs.publish", "unknown publish mode '%s', expected 'resource', 'extra' or \"\"", mode)
+			}
+		}
+		if value, exists := attrs["metadata"]; exists && name == "CkanCollector" {
+			mode, ok := value.(string)
+			if !ok {
+				v.errorf(field+".attrs.metadata", "expected string, got %s", typeName(value))
+			} else if !contains(metadataModes, mode) {
+				v.errorf(field+".attrs.metadata", "unknown metadata mode '%s', expected 'check', 'only' or \"\"", mode)
+			}
+		}
+		if name == "CkanCollector" {
+			v.checkDownloadAttrs(field+".attrs", attrs)
+			v.checkWebhookAttrs(field+".attrs", attrs)
+		}
+	}
+}
+
+// checkLocalAttrs validates the optional attrs of the LocalCollector
+func (v *validator) checkLocalAttrs(field string, attrs map[string]interface{}) {
+	for _, attr := range []string{"includeFolders", "followSymlinks", "crossFilesystems", "includeHidden", "useIgnoreFile"} {
+		if value, exists := attrs[attr]; exists && typeName(value) != "bool" {
+			v.errorf(field+"."+attr, "expected bool, got %s", typeName(value))
+		}
+	}
+	if value, exists := attrs["maxDepth"]; exists {
+		if n, ok := value.(int64); !ok || n < 0 {
+			v.errorf(field+".maxDepth", "expected a depth of directories (0 for no limit), got %v", value)
+		}
+	}
+}
+
+// checkDownloadAttrs validates the optional attrs of the CkanCollector for downloading resources
+func (v *validator) checkDownloadAttrs(field string, attrs map[string]interface{}) {
+	if value, exists := attrs["download"]; exists && typeName(value) != "bool" {
+		v.errorf(field+".download", "expected bool, got %s", typeName(value))
+	}
+	for _, attr := range []string{"download_dir", "staging_path"} {
+		if value, exists := attrs[attr]; exists && typeName(value) != "string" {
+			v.errorf(field+"."+attr, "expected string, got %s", typeName(value))
+		}
+	}
+	if value, exists := attrs["download_parallelism"]; exists {
+		if n, ok := value.(int64); !ok || n < 1 {
+			v.errorf(field+".download_parallelism", "expected a number of parallel downloads greater than 0, got %v", value)
+		}
+	}
+	if value, exists := attrs["download_retries"]; exists {
+		if n, ok := value.(int64); !ok || n < 0 {
+			v.errorf(field+".download_retries", "expected a number of retries (0 for none), got %v", value)
+		}
+	}
+}
+
+// checkWebhookAttrs validates the optional attrs of the CkanCollector for the webhook of pc-server
+func (v *validator) checkWebhookAttrs(field string, attrs map[string]interface{}) {
+	if value, exists := attrs["webhook_secret"]; exists && typeName(value) != "string" {
+		v.errorf(field+".webhook_secret", "expected string, got %s", typeName(value))
+	}
+	for _, attr := range []string{"webhook_organizations", "webhook_disabled_organizations"} {
+		if value, exists := attrs[attr]; exists && typeName(value) != "list of strings" {
+			v.errorf(field+"."+attr, "expected list of strings, got %s", typeName(value))
+		}
+	}
+}
+
+func (v *validator) checkRules(raw map[string]interface{}) {
+	names, sections := v.tables(raw, "rule")
+	for _, name := range names {
+		field := "rule." + name
+		section := sections[name]
+		v.unknownKeys(field, section, ruleKeys)
+
+		rule, ok := section["rule"].(string)
+		if !ok {
+			if section["rule"] == nil {
+				v.errorf(field, "missing required key 'rule'")
+			} else {
+				v.errorf(field+".rule", "expected string, got %s", typeName(section["rule"]))
+			}
+			continue
+		}
+		message, ok := section["message"].(string)
+		if !ok && section["message"] != nil {
+			v.errorf(field+".message", "expected string, got %s", typeName(section["message"]))
+			continue
+		}
+		if _, err := rules.Compile(name, &config.RuleConfig{Rule: rule, Message: message}); err != nil {
+			// Expression errors continue with a source excerpt on further lines
+			message := strings.TrimPrefix(err.Error(), "rule "+name+": ")
+			v.errorf(field+".rule", "%s", strings.SplitN(message, "\n", 2)[0])
+		}
+		if _, ok := checks.Lookup(name); ok {
+			v.errorf(field, "name clashes with the built-in check %s", name)
+		}
+	}
+}
+
+func (v *validator) checkPlugins(raw map[string]interface{}) {
+	names, sections := v.tables(raw, "plugin")
+	for _, name := range names {
+		field := "plugin." + name
+		section := sections[name]
+		v.unknownKeys(field, section, pluginKeys)
+
+		switch command := section["command"].(type) {
+		case nil:
+			v.errorf(field, "missing required key 'command'")
+		case string:
+			if command == "" {
+				v.errorf(field+".command", "must not be empty")
+			}
+		case []interface{}:
+			if len(command) == 0 {
+				v.errorf(field+".command", "must not be empty")
+			} else if !isStringList(command) {
+				v.errorf(field+".command", "expected a list of strings")
+			}
+		default:
+			v.errorf(field+".command", "expected string or list of strings, got %s", typeName(command))
+		}
+
+		if scope, exists := section["scope"]; exists {
+			if s, ok := scope.(string); !ok || (s != "file" && s != "repository") {
+				v.errorf(field+".scope", "must be 'file' or 'repository', got %v", scope)
+			}
+		}
+		if timeout, exists := section["timeout"]; exists {
+			if _, err := config.ParseDuration(timeout); err != nil {
+				v.errorf(field+".timeout", "%v", err)
+			}
+		}
+		if _, ok := checks.Lookup(name); ok {
+			v.errorf(field, "name clashes with the built-in check %s", name)
+		}
+	}
+}
+
+// configArguments maps the names a [test.*] section may configure to the arguments the check reads
+func configArguments() map[string][]checks.ArgumentSpec {
+	arguments := map[string][]checks.ArgumentSpec{}
+	for _, check := range checks.Registry {
+		if len(check.Arguments) > 0 || arguments[check.GetConfigName()] == nil {
+			arguments[check.GetConfigName()] = check.Arguments
+		}
+	}
+	return arguments
+}
+
+// checkNames returns the built-in checks and the rules and plugins of the config, sorted
+func checkNames(raw map[string]interface{}) []string {
+	var names []string
+	for _, check := range checks.Registry {
+		names = append(names, check.Name)
+	}
+	for _, section := range []string{"rule", "plugin"} {
+		if data, ok := raw[section].(map[string]interface{}); ok {
+			names = append(names, sortedKeys(data)...)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v *validator) checkTests(raw map[string]interface{}) {
+	names, sections := v.tables(raw, "test")
+	for _, name := range names {
+		v.checkTest(raw, "test."+name, name, sections[name])
+	}
+
+	// Checks that read keywordArguments fail at scan time without their section
+	arguments := configArguments()
+	for _, name := range sortedKeys(arguments) {
+		if _, ok := sections[name]; !ok && hasRequired(arguments[name]) {
+			v.errorf("test."+name, "missing [test.%s] section, required keywordArguments keys: %s", name, requiredNames(arguments[name]))
+		}
+	}
+}
+
+// checkTest validates a [test.<name>] section, field is the path of the section
+func (v *validator) checkTest(raw map[string]interface{}, field, name string, section map[string]interface{}) {
+	arguments := configArguments()
+	specs, builtin := arguments[name]
+	ruleNames, _ := raw["rule"].(map[string]interface{})
+	pluginNames, _ := raw["plugin"].(map[string]interface{})
+	_, isRule := ruleNames[name]
+	_, isPlugin := pluginNames[name]
+	if !builtin && !isRule && !isPlugin {
+		v.errorf(field, "unknown check '%s', expected a built-in check, rule or plugin%s", name, suggestion(name, sortedKeys(arguments)))
+		return
+	}
+	v.unknownKeys(field, section, testKeys)
+
+	var lists [2]int
+	for i, key := range []string{"blacklist", "whitelist"} {
+		value, exists := section[key]
+		if !exists {
+			continue
+		}
+		patterns, ok := value.([]interface{})
+		if !ok || !isStringList(patterns) {
+			v.errorf(field+"."+key, "expected a list of strings, got %s", typeName(value))
+			continue
+		}
+		lists[i] = len(patterns)
+		for _, p := range patterns {
+			if _, err := regexp.Compile(p.(string)); err != nil {
+				v.errorf(field+"."+key, "invalid regular expression %q: %v", p, err)
+			}
+		}
+	}
+	if lists[0] > 0 && lists[1] > 0 {
+		v.errorf(field, "only one of blacklist and whitelist may have entries")
+	}
+
+	for _, key := range []string{"include_extensions", "exclude_extensions"} {
+		value, exists := section[key]
+		if !exists {
+			continue
+		}
+		types, ok := value.([]interface{})
+		if !ok || !isStringList(types) {
+			v.errorf(field+"."+key, "expected a list of strings, got %s", typeName(value))
+			continue
+		}
+		for _, t := range types {
+			if _, err := path.Match(t.(string), ""); err != nil || strings.TrimSpace(t.(string)) == "" {
+				v.errorf(field+"."+key, "invalid extension or MIME glob %q", t)
+			}
+		}
+	}
+
+	if severity, exists := section["severity"]; exists {
+		if s, ok := severity.(string); !ok || (s != string(checks.SeverityError) && s != string(checks.SeverityWarning) && s != string(checks.SeverityInfo)) {
+			v.errorf(field+".severity", "must be 'error', 'warning' or 'info', got %v", severity)
+		}
+	}
+	if reportOnly, exists := section["report_only"]; exists {
+		if _, ok := reportOnly.(bool); !ok {
+			v.errorf(field+".report_only", "expected bool, got %s", typeName(reportOnly))
+		}
+	}
+
+	value, exists := section["keywordArguments"]
+	if !exists {
+		if builtin && hasRequired(specs) {
+			v.errorf(field, "missing keywordArguments, required keys: %s", requiredNames(specs))
+		}
+		return
+	}
+	sets, ok := tableList(value)
+	if !ok {
+		v.errorf(field+".keywordArguments", "expected a list of tables, e.g. [{ key = \"value\" }], got %s", typeName(value))
+		return
+	}
+	if len(sets) == 0 && builtin && hasRequired(specs) {
+		v.errorf(field+".keywordArguments", "is empty, required keys: %s", requiredNames(specs))
+	}
+	for i, set := range sets {
+		v.checkArguments(fmt.Sprintf("%s.keywordArguments[%d]", field, i), set, specs, builtin)
+	}
+}
+
+// checkArguments validates one keywordArguments set against the arguments the check reads
+func (v *validator) checkArguments(field string, set map[string]interface{}, specs []checks.ArgumentSpec, builtin bool) {
+	known := map[string]string{}
+	for _, spec := range specs {
+		known[spec.Name] = spec.Type
+		value, exists := set[spec.Name]
+		if !exists {
+			if spec.Required && !hasAny(set, spec.Alternatives) {
+				alternatives := ""
+				if len(spec.Alternatives) > 0 {
+					alternatives = " or " + strings.Join(spec.Alternatives, ", ")
+				}
+				v.report(SeverityError, field, v.lineOf(field), "missing required key '%s' (%s)%s", spec.Name, typeLabel(spec.Type), alternatives)
+			}
+			continue
+		}
+		if argumentType(value) != spec.Type {
+			v.report(SeverityError, field+"."+spec.Name, v.lineOf(field), "expected %s, got %s", typeLabel(spec.Type), typeName(value))
+			continue
+		}
+		v.checkKeywordSource(field+"."+spec.Name, value)
+	}
+	for _, key := range sortedKeys(set) {
+		if _, ok := known[key]; ok {
+			continue
+		}
+		if builtin {
+			names := sortedKeys(known)
+			v.report(SeverityWarning, field+"."+key, v.lineOf(field), "unknown key, the check reads %s%s", strings.Join(names, ", "), suggestion(key, names))
+			continue
+		}
+		// Values other than strings, bools, integers and lists of strings are dropped by the config parser
+		if argumentType(set[key]) == "" {
+			v.report(SeverityError, field+"."+key, v.lineOf(field), "expected string, bool, integer or list of strings, got %s", typeName(set[key]))
+		}
+	}
+}
+
+// checkKeywordSource checks that a keywords_file can be read, keyword_lists are known, patterns
+// compile, lengths and sizes are positive, date formats are known and bounding boxes are valid
+func (v *validator) checkKeywordSource(field string, value interface{}) {
+	switch {
+	case strings.HasSuffix(field, ".patterns"):
+		for _, pattern := range value.([]interface{}) {
+			if _, err := regexp.Compile(pattern.(string)); err != nil {
+				v.report(SeverityError, field, v.lineOf(field), "invalid regular expression %q: %v", pattern, err)
+			}
+		}
+	case strings.HasSuffix(field, ".pattern"):
+		if _, err := regexp.Compile(value.(string)); err != nil {
+			v.report(SeverityError, field, v.lineOf(field), "invalid regular expression %q: %v", value, err)
+		}
+	case strings.HasSuffix(field, ".max_length"), strings.HasSuffix(field, ".max_path_length"):
+		if value.(int64) < 1 {
+			v.report(SeverityError, field, v.lineOf(field), "expected a length of at least 1, got %d", value)
+		}
+	case strings.HasSuffix(field, ".max_embedded_bytes"):
+		if value.(int64) < 1 {
+			v.report(SeverityError, field, v.lineOf(field), "expected a size of at least 1 byte, got %d", value)
+		}
+	case strings.HasSuffix(field, ".preferred_formats"):
+		for _, format := range value.([]interface{}) {
+			if !checks.IsDateFormat(format.(string)) {
+				v.report(SeverityError, field, v.lineOf(field), "unknown date format '%s', expected a layout such as YYYY-MM-DD, YYYYMMDD or DD.MM.YYYY", format)
+			}
+		}
+	case strings.HasSuffix(field, ".bounding_box"):
+		if _, err := checks.ParseBoundingBox(value.(string)); err != nil {
+			v.report(SeverityError, field, v.lineOf(field), "invalid bounding box '%s': %v", value, err)
+		}
+	case strings.HasSuffix(field, ".keywords_file"):
+		path := config.KeywordFilePath(value.(string), v.file)
+		if config.IsRemote(path) {
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			v.report(SeverityError, field, v.lineOf(field), "cannot read keywords file: %v", err)
+		}
+	case strings.HasSuffix(field, ".keyword_lists"):
+		known := config.WellKnownKeywordLists()
+		for _, name := range value.([]interface{}) {
+			if !contains(known, name.(string)) {
+				v.report(SeverityError, field, v.lineOf(field), "unknown keyword list '%s', expected one of %s%s", name, strings.Join(known, ", "), suggestion(name.(string), known))
+			}
+		}
+	}
+}
+
+func (v *validator) checkNotifiers(raw map[string]interface{}) {
+	names, sections := v.tables(raw, "notify")
+	for _, name := range names {
+		// Same conversion as the config parser, which is not used here so other errors do not hide these
+		attrs := map[string]interface{}{}
+		for key, value := range sections[name] {
+			switch val := value.(type) {
+			case string, bool, int64:
+				attrs[key] = val
+			case []interface{}:
+				var list []string
+				for _, item := range val {
+					if s, ok := item.(string); ok {
+						list = append(list, s)
+					}
+				}
+				attrs[key] = list
+			}
+		}
+		single := config.Config{Notifiers: map[string]*config.NotifierConfig{name: {Attrs: attrs}}}
+		if _, err := notify.FromConfig(single); err != nil {
+			v.errorf("notify."+name, "%v", err)
+		}
+	}
+}
+
+// typeLabel describes an ArgumentSpec type for messages
+func typeLabel(specType string) string {
+	switch specType {
+	case "list":
+		return "list of strings"
+	case "int":
+		return "integer"
+	}
+	return specType
+}
+
+// tableList returns the tables of an array of (inline) tables
+func tableList(value interface{}) ([]map[string]interface{}, bool) {
+	switch val := value.(type) {
+	case []map[string]interface{}:
+		return val, true
+	case []interface{}:
+		tables := make([]map[string]interface{}, 0, len(val))
+		for _, item := range val {
+			table, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			tables = append(tables, table)
+		}
+		return tables, true
+	}
+	return nil, false
+}
+
+// argumentType returns "string", "bool" or "list" for values a keywordArguments key may have
+func argumentType(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case []interface{}:
+		if isStringList(val) {
+			return "list"
+		}
+	}
+	return ""
+}
+
+// typeName names the type of a decoded TOML value for messages
+func typeName(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case []interface{}:
+		if isStringList(val) {
+			return "list of strings"
+		}
+		return "list"
+	case []map[string]interface{}:
+		return "list of tables"
+	case map[string]interface{}:
+		return "table"
+	case nil:
+		return "nothing"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func isStringList(values []interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAny(set map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := set[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRequired(specs []checks.ArgumentSpec) bool {
+	for _, spec := range specs {
+		if spec.Required {
+			return true
+		}
+	}
+	return false
+}
+
+func requiredNames(specs []checks.ArgumentSpec) string {
+	var names []string
+	for _, spec := range specs {
+		if spec.Required {
+			names = append(names, spec.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// suggestion returns a "did you mean" hint for the candidate closest to value, if any is close
+func suggestion(value string, candidates []string) string {
+	best, bestDistance := "", len(value)/2+1
+	for _, candidate := range candidates {
+		if d := distance(strings.ToLower(value), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", best)
+}
+
+// distance is the Levenshtein distance between a and b
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,529 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "pc.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const validConfig = `[operation.main]
+collector = "LocalCollector"
+
+[test.IsFreeOfKeywords]
+keywordArguments = [
+    { keywords = ["password"], info = "Credentials detected" }
+]
+
+[test.IsValidName]
+keywordArguments = [{ disallowed_names = [".DS_Store"] }]
+
+[collector.LocalCollector]
+attrs = {includeFolders = true}
+`
+
+// find returns the diagnostic reported for field, failing the test if there is none
+func find(t *testing.T, diagnostics []Diagnostic, field string) Diagnostic {
+	t.Helper()
+	for _, d := range diagnostics {
+		if d.Field == field {
+			return d
+		}
+	}
+	t.Fatalf("no diagnostic for %s in %v", field, diagnostics)
+	return Diagnostic{}
+}
+
+func TestValidConfig(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig))
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestExampleConfigHasNoErrors(t *testing.T) {
+	diagnostics := File("../../pc.toml.example")
+	if HasErrors(diagnostics) {
+		t.Errorf("pc.toml.example has errors: %v", diagnostics)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nmaxArchiveFileSize = =\n"))
+	if len(diagnostics) != 1 || diagnostics[0].Line != 2 || !strings.Contains(diagnostics[0].Message, "invalid TOML") {
+		t.Errorf("unexpected diagnostics: %v", diagnostics)
+	}
+}
+
+func TestUnknownNames(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.HasOnlyASCI]
+blacklist = []
+
+[tests.HasReadme]
+`))
+	d := find(t, diagnostics, "test.HasOnlyASCI")
+	if d.Line != 15 || d.Severity != SeverityError || !strings.Contains(d.Message, "did you mean 'HasOnlyASCII'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "tests"); d.Severity != SeverityWarning || !strings.Contains(d.Message, "did you mean 'test'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestKeywordArguments(t *testing.T) {
+	diagnostics := File(writeConfig(t, `[operation.main]
+collector = "LocalCollector"
+
+[test.IsFreeOfKeywords]
+keywordArguments = [
+    { keywords = ["password"], info = 3 },
+    { keywords = "secret", infos = "typo" }
+]
+
+[collector.LocalCollector]
+attrs = {}
+`))
+
+	tests := []struct {
+		field   string
+		line    int
+		message string
+	}{
+		{"test.IsFreeOfKeywords.keywordArguments[0].info", 6, "expected string, got integer"},
+		{"test.IsFreeOfKeywords.keywordArguments[1].keywords", 7, "expected list of strings, got string"},
+		{"test.IsFreeOfKeywords.keywordArguments[1]", 7, "missing required key 'info'"},
+		{"test.IsFreeOfKeywords.keywordArguments[1].infos", 7, "did you mean 'info'"},
+		{"test.IsValidName", 0, "missing [test.IsValidName] section"},
+	}
+	for _, tt := range tests {
+		d := find(t, diagnostics, tt.field)
+		if d.Line != tt.line || !strings.Contains(d.Message, tt.message) {
+			t.Errorf("%s: unexpected diagnostic: %v", tt.field, d)
+		}
+	}
+	if last := diagnostics[len(diagnostics)-1]; last.Line != 0 {
+		t.Errorf("expected diagnostics without a line to come last, got %v", diagnostics)
+	}
+}
+
+func TestTestSectionTypes(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.HasNoWhiteSpace]
+blacklist = ["("]
+whitelist = "x"
+keywordArguments = { a = "b" }
+include_extensions = ["csv", "text/[a-"]
+severity = "fatal"
+report_only = "yes"
+`))
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.blacklist"); d.Line != 16 || !strings.Contains(d.Message, "invalid regular expression") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.whitelist"); !strings.Contains(d.Message, "expected a list of strings, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.keywordArguments"); d.Line != 18 || !strings.Contains(d.Message, "expected a list of tables") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.include_extensions"); d.Line != 19 || !strings.Contains(d.Message, "text/[a-") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.severity"); !strings.Contains(d.Message, "must be 'error', 'warning' or 'info', got fatal") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.HasNoWhiteSpace.report_only"); !strings.Contains(d.Message, "expected bool, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestCollectors(t *testing.T) {
+	diagnostics := File(writeConfig(t, `[general]
+maxArchiveFileSize = "10 MBs"
+maxTotalArchiveMemory = "1GiB"
+
+[operation.main]
+collector = "CkanCollector"
+
+[collector.CkanCollector]
+attrs = {url = "https://ckan.example.com", verify = "yes", publish = "email", download = true, download_parallelism = 0, download_retries = "3", staging_path = true, metadata = "all", webhook_secret = 42, webhook_organizations = "limnology"}
+
+[collector.LocalCollector]
+attrs = {followSymlinks = "yes", maxDepth = -1}
+`))
+
+	tests := []struct {
+		field    string
+		severity Severity
+		message  string
+	}{
+		{"general.maxArchiveFileSize", SeverityError, "invalid size '10 MBs'"},
+		{"collector.CkanCollector.attrs", SeverityError, "missing required attribute 'ckan_storage_path'"},
+		{"collector.CkanCollector.attrs.verify", SeverityError, "expected bool, got string"},
+		{"collector.CkanCollector.attrs.publish", SeverityError, "unknown publish mode 'email'"},
+		{"collector.CkanCollector.attrs.download_parallelism", SeverityError, "greater than 0, got 0"},
+		{"collector.CkanCollector.attrs.download_retries", SeverityError, "expected a number of retries"},
+		{"collector.CkanCollector.attrs.staging_path", SeverityError, "expected string, got bool"},
+		{"collector.CkanCollector.attrs.metadata", SeverityError, "unknown metadata mode 'all'"},
+		{"collector.CkanCollector.attrs.webhook_secret", SeverityError, "expected string, got integer"},
+		{"collector.CkanCollector.attrs.webhook_organizations", SeverityError, "expected list of strings, got string"},
+		{"collector.LocalCollector.attrs.followSymlinks", SeverityError, "expected bool, got string"},
+		{"collector.LocalCollector.attrs.maxDepth", SeverityError, "0 for no limit"},
+		{"collector.LocalCollector", SeverityWarning, "never used"},
+	}
+	for _, tt := range tests {
+		d := find(t, diagnostics, tt.field)
+		if d.Severity != tt.severity || !strings.Contains(d.Message, tt.message) {
+			t.Errorf("%s: unexpected diagnostic: %v", tt.field, d)
+		}
+	}
+}
+
+func TestOperationCollector(t *testing.T) {
+	diagnostics := File(writeConfig(t, strings.Replace(validConfig, `"LocalCollector"`, `"LocalColector"`, 1)))
+	if d := find(t, diagnostics, "operation.main.collector"); d.Line != 2 || !strings.Contains(d.Message, "did you mean 'LocalCollector'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+
+	diagnostics = File(writeConfig(t, "[general]\n"))
+	find(t, diagnostics, "operation.main")
+}
+
+func TestRulesPluginsAndNotifiers(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[rule.LargeCSV]
+rule = "file.size >"
+
+[plugin.NetCDF]
+scope = "folder"
+timeout = "1 minute"
+
+[notify.webhook]
+url = ""
+`))
+	if d := find(t, diagnostics, "rule.LargeCSV.rule"); d.Line != 16 || !strings.Contains(d.Message, "invalid expression") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "plugin.NetCDF"); !strings.Contains(d.Message, "missing required key 'command'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "plugin.NetCDF.scope"); d.Line != 19 {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "plugin.NetCDF.timeout"); d.Line != 20 || !strings.Contains(d.Message, "invalid duration") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "notify.webhook"); d.Line != 22 {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	d := Diagnostic{File: "pc.toml", Line: 3, Field: "general.historyDir", Severity: SeverityError, Message: "expected string, got integer"}
+	if s := d.String(); s != "pc.toml:3: error: general.historyDir: expected string, got integer" {
+		t.Errorf("unexpected string %q", s)
+	}
+	d = Diagnostic{File: "pc.toml", Severity: SeverityWarning, Message: "m"}
+	if s := d.String(); s != "pc.toml: warning: m" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[collector.CkanCollector]
+attrs = {url = "https://ckan.example.com", token = "", verify = true, ckan_storage_path = "/data"}
+
+[operation.quick]
+checks = ["HasOnlyASCII", "HasReadMe"]
+maxArchiveFileSize = "1M"
+
+[operation.quick.test.IsValidName]
+keywordArguments = [{ names = [".DS_Store"] }]
+
+[operation.server]
+collector = "CkanCollector"
+`))
+	if d := find(t, diagnostics, "operation.quick.checks"); d.Line != 19 || !strings.Contains(d.Message, "did you mean 'HasReadme'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "operation.quick.maxArchiveFileSize"); !strings.Contains(d.Message, "invalid size '1M'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "operation.quick.test.IsValidName.keywordArguments[0]"); d.Line != 23 || !strings.Contains(d.Message, "missing required key 'disallowed_names'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	// The CkanCollector is selected by the server profile
+	for _, d := range diagnostics {
+		if d.Field == "collector.CkanCollector" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestKeywordSources(t *testing.T) {
+	diagnostics := File(writeConfig(t, `[operation.main]
+collector = "LocalCollector"
+
+[test.IsFreeOfKeywords]
+keywordArguments = [
+    { keywords_file = "secrets.txt", info = "Credentials detected" },
+    { keyword_lists = ["private-key"], info = "Private key detected" },
+    { info = "Nothing to find" }
+]
+
+[test.IsValidName]
+keywordArguments = [{ disallowed_names = [".DS_Store"] }]
+
+[collector.LocalCollector]
+attrs = {}
+`))
+	if d := find(t, diagnostics, "test.IsFreeOfKeywords.keywordArguments[0].keywords_file"); d.Line != 6 || !strings.Contains(d.Message, "cannot read keywords file") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.IsFreeOfKeywords.keywordArguments[1].keyword_lists"); d.Line != 7 || !strings.Contains(d.Message, "did you mean 'private-keys'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.IsFreeOfKeywords.keywordArguments[2]"); !strings.Contains(d.Message, "missing required key 'keywords' (list of strings) or keywords_file, keyword_lists") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if len(diagnostics) != 3 {
+		t.Errorf("expected 3 diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestKeywordPatterns(t *testing.T) {
+	diagnostics := File(writeConfig(t, strings.Replace(validConfig, `{ keywords = ["password"], info = "Credentials detected" }`,
+		`{ name = "aws", patterns = ["AKIA[0-9A-Z]{16}", "token=("], redact = "yes", info = "AWS key detected" }`, 1)))
+	if d := find(t, diagnostics, "test.IsFreeOfKeywords.keywordArguments[0].patterns"); d.Line != 6 || !strings.Contains(d.Message, `invalid regular expression "token=("`) {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "test.IsFreeOfKeywords.keywordArguments[0].redact"); !strings.Contains(d.Message, "expected bool, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if len(diagnostics) != 2 {
+		t.Errorf("expected 2 diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestFileNamePolicy(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.MatchesFileNamePolicy]
+keywordArguments = [
+    { pattern = "[a-z", max_length = 0 },
+    { max_length = "64", allowed_characters = ["digits"], reserved_names = true },
+    { name = "Eawag", pattern = "[a-z0-9_.]+", max_length = 64 }
+]
+`))
+	tests := []struct {
+		field   string
+		message string
+	}{
+		{"test.MatchesFileNamePolicy.keywordArguments[0].pattern", `invalid regular expression "[a-z"`},
+		{"test.MatchesFileNamePolicy.keywordArguments[0].max_length", "expected a length of at least 1, got 0"},
+		{"test.MatchesFileNamePolicy.keywordArguments[1].max_length", "expected integer, got string"},
+	}
+	for _, tt := range tests {
+		if d := find(t, diagnostics, tt.field); !strings.Contains(d.Message, tt.message) {
+			t.Errorf("%s: unexpected diagnostic: %v", tt.field, d)
+		}
+	}
+	if len(diagnostics) != len(tests) {
+		t.Errorf("expected %d diagnostics, got %v", len(tests), diagnostics)
+	}
+
+	diagnostics = File(writeConfig(t, validConfig+`
+[test.IsFileNameTooLong]
+keywordArguments = [{ full_path = true, max_path_length = -1 }]
+`))
+	if d := find(t, diagnostics, "test.IsFileNameTooLong.keywordArguments[0].max_path_length"); !strings.Contains(d.Message, "expected a length of at least 1, got -1") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestMaxEmbeddedBytes(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.HasCleanNotebookOutputs]
+keywordArguments = [{ max_embedded_bytes = 0 }]
+`))
+	if d := find(t, diagnostics, "test.HasCleanNotebookOutputs.keywordArguments[0].max_embedded_bytes"); !strings.Contains(d.Message, "expected a size of at least 1 byte, got 0") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestDateFormats(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.HasConsistentDateFormats]
+keywordArguments = [{ preferred_formats = ["YYYY-MM-DD", "DD/MM/YYYY"] }]
+`))
+	if d := find(t, diagnostics, "test.HasConsistentDateFormats.keywordArguments[0].preferred_formats"); !strings.Contains(d.Message, "unknown date format 'DD/MM/YYYY'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if len(diagnostics) != 1 {
+		t.Errorf("expected 1 diagnostic, got %v", diagnostics)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	diagnostics := File(writeConfig(t, validConfig+`
+[test.HasPlausibleCoordinates]
+keywordArguments = [{ bounding_box = "5.9,45.8,10.5,47.9" }, { bounding_box = "45.8,5.9,47.9" }]
+`))
+	if d := find(t, diagnostics, "test.HasPlausibleCoordinates.keywordArguments[1].bounding_box"); !strings.Contains(d.Message, "invalid bounding box '45.8,5.9,47.9': expected four numbers") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if len(diagnostics) != 1 {
+		t.Errorf("expected 1 diagnostic, got %v", diagnostics)
+	}
+}
+
+func TestMaxFindingsPerCheck(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nmaxFindingsPerCheck = \"many\"\n"))
+	if d := find(t, diagnostics, "general.maxFindingsPerCheck"); d.Line != 2 || !strings.Contains(d.Message, "got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\nmaxFindingsPerCheck = 50\n")) {
+		if d.Field == "general.maxFindingsPerCheck" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nfileTimeout = \"soon\"\nscanTimeout = \"1h\"\n"))
+	if d := find(t, diagnostics, "general.fileTimeout"); d.Line != 2 || !strings.Contains(d.Message, "invalid duration") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range diagnostics {
+		if d.Field == "general.scanTimeout" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestMaxTotalMemory(t *testing.T) {
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\nmaxTotalMemory = 0\n")) {
+		if d.Field == "general.maxTotalMemory" {
+			t.Errorf("expected 0 to disable the memory budget, got %v", d)
+		}
+	}
+	diagnostics := File(writeConfig(t, "[general]\nmaxTotalMemory = \"lots\"\n"))
+	if d := find(t, diagnostics, "general.maxTotalMemory"); d.Line != 2 || !strings.Contains(d.Message, "invalid size") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\nmaxWorkspaceSize = 0\nworkspaceDir = \"/scratch\"\n")) {
+		if strings.HasPrefix(d.Field, "general.") {
+			t.Errorf("expected 0 to disable the disk limit, got %v", d)
+		}
+	}
+	diagnostics := File(writeConfig(t, "[general]\nworkspaceDir = 1\n"))
+	if d := find(t, diagnostics, "general.workspaceDir"); d.Line != 2 || !strings.Contains(d.Message, "expected string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestScanStrategy(t *testing.T) {
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\nscanStrategy = \"sample\"\nsampleThreshold = \"100GiB\"\nsampleBlocks = 0\n")) {
+		if strings.HasPrefix(d.Field, "general.") {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+	diagnostics := File(writeConfig(t, "[general]\nscanStrategy = \"quick\"\nsampleWindow = 0\nsampleBlocks = -1\n"))
+	if d := find(t, diagnostics, "general.scanStrategy"); d.Line != 2 || !strings.Contains(d.Message, "expected one of full, sample") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "general.sampleWindow"); !strings.Contains(d.Message, "greater than 0") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "general.sampleBlocks"); !strings.Contains(d.Message, "number of blocks") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nlanguage = \"fr\"\n"))
+	if d := find(t, diagnostics, "general.language"); d.Line != 2 || !strings.Contains(d.Message, "unknown language 'fr'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\nlanguage = \"de\"\n")) {
+		if d.Field == "general.language" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\ntimezone = \"Europe/Zurch\"\n"))
+	if d := find(t, diagnostics, "general.timezone"); d.Line != 2 || !strings.Contains(d.Message, "unknown time zone 'Europe/Zurch'") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range File(writeConfig(t, validConfig+"\n[general]\ntimezone = \"Europe/Zurich\"\n")) {
+		if d.Field == "general.timezone" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestLetterTemplate(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nletterTemplate = \"missing.tmpl\"\n"))
+	if d := find(t, diagnostics, "general.letterTemplate"); d.Line != 2 || !strings.Contains(d.Message, "cannot read letter template") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestSummaryTruncation(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nsummaryMaxIssues = 10\nsummaryMinGroupSize = \"3\"\n"))
+	if d := find(t, diagnostics, "general.summaryMinGroupSize"); d.Line != 3 || !strings.Contains(d.Message, "expected a number of issues, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range diagnostics {
+		if d.Field == "general.summaryMaxIssues" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestRedactSetting(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nredact = \"yes\"\n"))
+	if d := find(t, diagnostics, "general.redact"); d.Line != 2 || !strings.Contains(d.Message, "expected bool, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestListArchivesSetting(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nlistArchives = 1\n"))
+	if d := find(t, diagnostics, "general.listArchives"); d.Line != 2 || !strings.Contains(d.Message, "expected bool, got integer") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range File(writeConfig(t, "[general]\nlistArchives = true\n")) {
+		if d.Field == "general.listArchives" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
+
+func TestFileMetadataSettings(t *testing.T) {
+	diagnostics := File(writeConfig(t, "[general]\nfileMetadata = \"yes\"\nhashFiles = 1\n"))
+	if d := find(t, diagnostics, "general.fileMetadata"); d.Line != 2 || !strings.Contains(d.Message, "expected bool, got string") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if d := find(t, diagnostics, "general.hashFiles"); d.Line != 3 || !strings.Contains(d.Message, "expected bool, got integer") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	for _, d := range File(writeConfig(t, "[general]\nfileMetadata = true\nhashFiles = false\n")) {
+		if d.Field == "general.fileMetadata" || d.Field == "general.hashFiles" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package workspace keeps the temporary files of a scan, such as CKAN downloads, in one directory
+// that is removed with the scan and may hold at most a configured number of bytes.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrDiskLimit is returned when the files of a scan would exceed the maxWorkspaceSize
+var ErrDiskLimit = errors.New("the temporary files of the scan exceed maxWorkspaceSize")
+
+// Workspace is the directory of the temporary files of one scan. It is created when the first
+// directory is made in it and removed with everything in it by Close, which the scan defers so
+// the files are removed when it completes, is cancelled or panics. The bytes written through
+// Writer are limited to the limit of the workspace. A nil workspace has no limit; its Writer
+// writes as is and Mkdir creates directories in the system default that nobody removes.
+type Workspace struct {
+	parent string
+	limit  int64
+
+	mutex  sync.Mutex
+	dir    string
+	used   int64
+	closed bool
+}
+
+// New returns a workspace in parent (the system default if empty) holding at most limit bytes, 0
+// for no limit. Nothing is created until the workspace is used.
+func New(parent string, limit int64) *Workspace {
+	return &Workspace{parent: parent, limit: limit}
+}
+
+// Mkdir creates a new directory in the workspace, named after pattern as in os.MkdirTemp
+func (w *Workspace) Mkdir(pattern string) (string, error) {
+	if w == nil {
+		return os.MkdirTemp("", pattern)
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return "", errors.New("the workspace of the scan was removed")
+	}
+	if w.dir == "" {
+		dir, err := os.MkdirTemp(w.parent, "pc-scan-")
+		if err != nil {
+			return "", fmt.Errorf("cannot create the workspace of the scan: %w", err)
+		}
+		w.dir = dir
+	}
+	return os.MkdirTemp(w.dir, pattern)
+}
+
+// Reserve takes size bytes from the limit, failing with ErrDiskLimit if they do not fit
+func (w *Workspace) Reserve(size int64) error {
+	if w == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.limit > 0 && w.used+size > w.limit {
+		return fmt.Errorf("%w (%d bytes): set a larger limit or a workspaceDir with more space", ErrDiskLimit, w.limit)
+	}
+	w.used += size
+	return nil
+}
+
+// Release gives back size bytes, e.g. of a file that was removed or truncated
+func (w *Workspace) Release(size int64) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.used -= size
+	if w.used < 0 {
+		w.used = 0
+	}
+}
+
+// Used returns the bytes written to the workspace
+func (w *Workspace) Used() int64 {
+	if w == nil {
+		return 0
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.used
+}
+
+// Writer returns a writer to dst, a file in the workspace, counting the bytes against the limit.
+// A write that does not fit writes nothing and fails with ErrDiskLimit.
+func (w *Workspace) Writer(dst io.Writer) io.Writer {
+	if w == nil {
+		return dst
+	}
+	return &limitedWriter{workspace: w, dst: dst}
+}
+
+type limitedWriter struct {
+	workspace *Workspace
+	dst       io.Writer
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if err := l.workspace.Reserve(int64(len(p))); err != nil {
+		return 0, err
+	}
+	n, err := l.dst.Write(p)
+	l.workspace.Release(int64(len(p) - n))
+	return n, err
+}
+
+// Remove removes path, a file or directory in the workspace, and gives back its bytes
+func (w *Workspace) Remove(path string) error {
+	var size int64
+	filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	err := os.RemoveAll(path)
+	w.Release(size)
+	return err
+}
+
+// Close removes the workspace with all files in it. It may be called more than once.
+func (w *Workspace) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.closed = true
+	w.used = 0
+	if w.dir == "" {
+		return nil
+	}
+	dir := w.dir
+	w.dir = ""
+	return os.RemoveAll(dir)
+}
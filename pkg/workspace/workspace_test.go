@@ -0,0 +1,83 @@
+package workspace
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspace(t *testing.T) {
+	parent := t.TempDir()
+	ws := New(parent, 0)
+
+	entries, _ := os.ReadDir(parent)
+	assert.Empty(t, entries, "nothing is created until the workspace is used")
+
+	dir, err := ws.Mkdir("downloads-")
+	assert.NoError(t, err)
+	assert.DirExists(t, dir)
+	assert.Equal(t, parent, filepath.Dir(filepath.Dir(dir)), "directories are made in the workspace of the scan")
+	other, err := ws.Mkdir("downloads-")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Dir(dir), filepath.Dir(other), "a scan has one workspace")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "data.csv"), []byte("a,b"), 0600))
+	assert.NoError(t, ws.Close())
+	assert.NoError(t, ws.Close(), "Close may be called again")
+	entries, _ = os.ReadDir(parent)
+	assert.Empty(t, entries, "Close removes the workspace")
+
+	_, err = ws.Mkdir("downloads-")
+	assert.Error(t, err, "a closed workspace cannot be used")
+}
+
+func TestWorkspaceLimit(t *testing.T) {
+	ws := New(t.TempDir(), 10)
+	var buffer bytes.Buffer
+	writer := ws.Writer(&buffer)
+
+	n, err := writer.Write([]byte("12345678"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, int64(8), ws.Used())
+
+	n, err = writer.Write([]byte("123"))
+	assert.True(t, errors.Is(err, ErrDiskLimit))
+	assert.ErrorContains(t, err, "maxWorkspaceSize")
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "12345678", buffer.String(), "a write that does not fit writes nothing")
+
+	ws.Release(8)
+	assert.NoError(t, ws.Reserve(10))
+	assert.Error(t, ws.Reserve(1))
+}
+
+func TestWorkspaceRemove(t *testing.T) {
+	ws := New(t.TempDir(), 100)
+	dir, err := ws.Mkdir("package-")
+	assert.NoError(t, err)
+	file, err := os.Create(filepath.Join(dir, "data.csv"))
+	assert.NoError(t, err)
+	ws.Writer(file).Write([]byte("0123456789"))
+	file.Close()
+	assert.Equal(t, int64(10), ws.Used())
+
+	assert.NoError(t, ws.Remove(dir))
+	assert.NoDirExists(t, dir)
+	assert.Equal(t, int64(0), ws.Used(), "the bytes of removed files are given back")
+	ws.Close()
+}
+
+func TestNilWorkspace(t *testing.T) {
+	var ws *Workspace
+	var buffer bytes.Buffer
+	ws.Writer(&buffer).Write([]byte("data"))
+	assert.Equal(t, "data", buffer.String())
+	assert.NoError(t, ws.Reserve(1<<40))
+	assert.Equal(t, int64(0), ws.Used())
+	assert.NoError(t, ws.Close())
+}
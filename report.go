@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/history"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	csvformatter "github.com/eawag-rdm/pc/pkg/output/csv"
+	htmlformatter "github.com/eawag-rdm/pc/pkg/output/html"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+	sarifformatter "github.com/eawag-rdm/pc/pkg/output/sarif"
+	templateformatter "github.com/eawag-rdm/pc/pkg/output/template"
+)
+
+// runReport implements `pc report`, converting a saved JSON report to HTML, Markdown, CSV, SARIF
+// or a custom template
+func runReport(args []string) {
+	if len(args) > 0 && args[0] == "diff" {
+		runReportDiff(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "merge" {
+		runReportMerge(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "stats" {
+		runReportStats(args[1:])
+		return
+	}
+
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	htmlOutput := flags.String("html", "", "Write the HTML report to this file")
+	htmlOptions := addHTMLFlags(flags)
+	markdownOutput := flags.Bool("markdown", false, "Print a Markdown summary for issue trackers")
+	templateOutput := flags.String("template", "", "Print the report rendered through this Go text/template file")
+	csvOutput := flags.Bool("csv", false, "Print the issues as CSV, one row per issue")
+	sarifOutput := flags.Bool("sarif", false, "Print the issues as SARIF 2.1.0 for code scanning tools")
+	fullSummary := flags.Bool("full-summary", false, fullSummaryUsage)
+	lang := flags.String("lang", "", "Language of the Markdown summary: "+strings.Join(i18n.Languages(), ", ")+" (default $PC_LANGUAGE or en)")
+	timezone := flags.String("timezone", "", timezoneFlagUsage+" (default $PC_TIMEZONE or the local one)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc report -html <report.html> [-html-mode single|gzip|split] [-html-title <title>] [-html-logo <image>] <report.json>")
+		fmt.Fprintln(flags.Output(), "       pc report -markdown|-csv|-sarif <report.json>")
+		fmt.Fprintln(flags.Output(), "       pc report -template <letter.tmpl> <report.json>")
+		fmt.Fprintln(flags.Output(), "       pc report diff [-html <diff.html>] [-json] <old.json> <new.json>")
+		fmt.Fprintln(flags.Output(), "       pc report merge [-o <combined.json>] <report.json|directory>...")
+		fmt.Fprintln(flags.Output(), "       pc report stats [-html <stats.html>] [-json] [-period day|week|month] <history-dir|report.json|directory>...")
+		flags.PrintDefaults()
+	}
+	reports := parseInterspersed(flags, args)
+
+	// Exactly one output format
+	formats := 0
+	for _, selected := range []bool{*htmlOutput != "", *markdownOutput, *csvOutput, *sarifOutput, *templateOutput != ""} {
+		if selected {
+			formats++
+		}
+	}
+	if len(reports) != 1 || formats != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := selectLanguage(*lang, os.Getenv("PC_LANGUAGE")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := selectTimezone(*timezone, os.Getenv("PC_TIMEZONE")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	result, err := readReport(reports[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The HTML report embeds the JSON as it is, the other formats are rendered from the decoded report
+	var report *jsonformatter.ScanResult
+	if *markdownOutput || *csvOutput || *sarifOutput || *templateOutput != "" {
+		if report, err = jsonformatter.Parse(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: '%s' is not a valid report: %v\n", reports[0], err)
+			os.Exit(1)
+		}
+	}
+
+	if *markdownOutput {
+		fmt.Print(markdownSummary(report, "", summaryTruncation(nil, *fullSummary)))
+		return
+	}
+
+	if *csvOutput || *sarifOutput {
+		var output string
+		if *csvOutput {
+			output, err = csvformatter.NewCSVFormatter().Format(report)
+		} else {
+			output, err = sarifformatter.NewSARIFFormatter().Format(report)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+		return
+	}
+
+	if *templateOutput != "" {
+		templateFormatter, err := templateformatter.NewTemplateFormatter(*templateOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := templateFormatter.Format(report, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+		return
+	}
+
+	htmlFormatter, err := htmlOptions.formatter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := htmlFormatter.GenerateReport(string(result), *htmlOutput); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating HTML report: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("HTML report generated: %s\n", *htmlOutput)
+}
+
+// runReportDiff implements `pc report diff`, comparing two saved JSON reports
+func runReportDiff(args []string) {
+	flags := flag.NewFlagSet("report diff", flag.ExitOnError)
+	htmlOutput := flags.String("html", "", "Write a side-by-side HTML comparison to this file")
+	htmlTitle := flags.String("html-title", "", "Title of the HTML comparison (default \""+htmlformatter.DefaultDiffTitle+"\")")
+	htmlLogo := flags.String("html-logo", "", "Image file shown next to the title of the HTML comparison")
+	jsonOutput := flags.Bool("json", false, "Print the new, fixed and unchanged issues as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc report diff [-html <diff.html>] [-json] <old.json> <new.json>")
+		fmt.Fprintln(flags.Output(), "Without -html or -json the new and fixed issues are printed as text.")
+		flags.PrintDefaults()
+	}
+	reports := parseInterspersed(flags, args)
+
+	if len(reports) != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	previous, err := readReport(reports[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	current, err := readReport(reports[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	diff, err := history.CompareReports(previous, current)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	diff.Location = reports[1]
+
+	diffJSON, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *htmlOutput != "" {
+		htmlFormatter, err := htmlformatter.NewHTMLFormatterWithOptions(htmlformatter.Options{Title: *htmlTitle, Logo: *htmlLogo})
+		if err == nil {
+			err = htmlFormatter.GenerateDiffReport(string(diffJSON), *htmlOutput)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating HTML report: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	switch {
+	case *jsonOutput:
+		fmt.Println(string(diffJSON))
+	case *htmlOutput != "":
+		fmt.Printf("HTML report generated: %s\n", *htmlOutput)
+	default:
+		fmt.Print(diff.FormatText())
+	}
+}
+
+// runReportMerge implements `pc report merge`, combining the JSON reports of several locations
+func runReportMerge(args []string) {
+	flags := flag.NewFlagSet("report merge", flag.ExitOnError)
+	outputPath := flags.String("o", "", "Write the combined report to this file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc report merge [-o <combined.json>] <report.json|directory>...")
+		fmt.Fprintln(flags.Output(), "Issues, files and messages keep the location they were found in; reports without one take their path.")
+		flags.PrintDefaults()
+	}
+	paths, err := reportPaths(parseInterspersed(flags, args))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(paths) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	sources := make([]jsonformatter.Source, 0, len(paths))
+	for _, path := range paths {
+		raw, err := readReport(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		source := jsonformatter.Source{Location: path}
+		if err := json.Unmarshal(raw, &source.Result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: '%s' is not a valid report: %v\n", path, err)
+			os.Exit(1)
+		}
+		sources = append(sources, source)
+	}
+
+	merged, err := json.MarshalIndent(jsonformatter.Merge(sources), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *outputPath == "" {
+		fmt.Println(string(merged))
+		return
+	}
+	if err := os.WriteFile(*outputPath, append(merged, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Merged %d reports into %s\n", len(sources), *outputPath)
+}
+
+// runReportStats implements `pc report stats`, summarizing a history directory or saved JSON reports
+func runReportStats(args []string) {
+	flags := flag.NewFlagSet("report stats", flag.ExitOnError)
+	htmlOutput := flags.String("html", "", "Write a static HTML dashboard to this file")
+	htmlTitle := flags.String("html-title", "", "Title of the HTML dashboard (default \""+htmlformatter.DefaultStatsTitle+"\")")
+	htmlLogo := flags.String("html-logo", "", "Image file shown next to the title of the HTML dashboard")
+	jsonOutput := flags.Bool("json", false, "Print the statistics as JSON")
+	period := flags.String("period", "month", "Group the trend by "+strings.Join(history.Periods, ", "))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc report stats [-html <stats.html>] [-json] [-period day|week|month] <history-dir|report.json|directory>...")
+		fmt.Fprintln(flags.Output(), "Without -html or -json the statistics are printed as text.")
+		flags.PrintDefaults()
+	}
+	paths := parseInterspersed(flags, args)
+
+	if len(paths) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	entries, err := history.ReadScans(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	stats, err := history.ComputeStats(entries, *period)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	statsJSON, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *htmlOutput != "" {
+		htmlFormatter, err := htmlformatter.NewHTMLFormatterWithOptions(htmlformatter.Options{Title: *htmlTitle, Logo: *htmlLogo})
+		if err == nil {
+			err = htmlFormatter.GenerateStatsReport(string(statsJSON), *htmlOutput)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating HTML report: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	switch {
+	case *jsonOutput:
+		fmt.Println(string(statsJSON))
+	case *htmlOutput != "":
+		fmt.Printf("HTML report generated: %s\n", *htmlOutput)
+	default:
+		fmt.Print(stats.FormatText())
+	}
+}
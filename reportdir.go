@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// resolveReportPath places a relative report path in the output directory, which is created if
+// needed. Absolute paths and an empty output directory leave the path as it is.
+func resolveReportPath(path string, outputDir string) (string, error) {
+	if path == "" || outputDir == "" || filepath.IsAbs(path) {
+		return path, nil
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("cannot create the output directory: %w", err)
+	}
+	return filepath.Join(outputDir, path), nil
+}
+
+// checkWritable reports whether a report can be written to path by creating its directory, as
+// the reports do, and a file in it, which is removed again. Scans of large packages thus do not
+// fail only at the end. Read-only file systems, as in containers with a read-only root, get a
+// hint to mount a volume.
+func checkWritable(path string) error {
+	var probe *os.File
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		probe, err = os.CreateTemp(filepath.Dir(path), ".pc-write-test-*")
+	}
+	if errors.Is(err, syscall.EROFS) {
+		return fmt.Errorf("cannot write '%s', the file system is read-only: use -output-dir or PC_OUTPUT_DIR with a writable volume", path)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write '%s': %w", path, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
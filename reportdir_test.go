@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReportPath(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "reports")
+
+	path, err := resolveReportPath("report.html", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "report.html", path)
+
+	path, err = resolveReportPath("/tmp/report.html", outputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/report.html", path)
+	assert.NoDirExists(t, outputDir)
+
+	path, err = resolveReportPath("report.html", outputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "report.html"), path)
+	assert.DirExists(t, outputDir)
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, checkWritable(filepath.Join(dir, "report.html")))
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries, "the probe file is removed")
+
+	// Missing directories are created, as the reports do
+	assert.NoError(t, checkWritable(filepath.Join(dir, "sub", "report.html")))
+	assert.DirExists(t, filepath.Join(dir, "sub"))
+
+	// A file in place of the directory cannot be written to
+	blocked := filepath.Join(dir, "file")
+	os.WriteFile(blocked, nil, 0644)
+	err := checkWritable(filepath.Join(blocked, "report.html"))
+	assert.ErrorContains(t, err, "cannot write")
+}
@@ -0,0 +1,851 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"time"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+	"github.com/eawag-rdm/pc/pkg/checks"
+	"github.com/eawag-rdm/pc/pkg/collectors"
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/helpers"
+	"github.com/eawag-rdm/pc/pkg/history"
+	"github.com/eawag-rdm/pc/pkg/notify"
+	"github.com/eawag-rdm/pc/pkg/output"
+	htmlformatter "github.com/eawag-rdm/pc/pkg/output/html"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+	plainformatter "github.com/eawag-rdm/pc/pkg/output/plain"
+	templateformatter "github.com/eawag-rdm/pc/pkg/output/template"
+	"github.com/eawag-rdm/pc/pkg/output/tui"
+	"github.com/eawag-rdm/pc/pkg/rules"
+	"github.com/eawag-rdm/pc/pkg/structs"
+	"github.com/eawag-rdm/pc/pkg/trace"
+	"github.com/eawag-rdm/pc/pkg/utils"
+)
+
+// runScan runs the checks on a location. It is used by `pc scan` and by the
+// flag-only invocation kept for backward compatibility.
+func runScan(args []string) {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc scan [flags]")
+		flags.PrintDefaults()
+		fmt.Fprintln(flags.Output(), "\nRun 'pc help' for the other commands.")
+	}
+
+	// implement small cli to call pc with config and a folder (both can have default args)
+	// then the files will be collected with the local_collector and the checks will be applied
+	// the results will be printed to the console
+	// the exit code will be 0 if no errors were found, otherwise 1
+	// the cli should have a help command to show the usage
+
+	// Define default values for the config and folder arguments
+	defaultConfigPath := config.FindConfigFile()
+	// current word directory
+	defaultFolder := "."
+
+	// Parse CLI arguments
+	cfg := flags.String("config", defaultConfigPath, "Path or https:// URL of the config file (the built-in default is used if none is found)")
+	offline := flags.Bool("offline", false, "Use the cached copy of a remote -config URL without fetching it")
+	var overrides stringList
+	flags.Var(&overrides, "set", setFlagUsage)
+	profile := flags.String("profile", "", "Use the [operation.<profile>] section of the config instead of [operation.main]")
+	onlyChecks := flags.String("only-checks", "", "Run only these checks, comma-separated (overrides the checks of the profile)")
+	skipChecks := flags.String("skip-checks", "", "Do not run these checks, comma-separated")
+	folder_or_url := flags.String("location", defaultFolder, "Path to local folder or CKAN package name. It depends on the set collector.")
+	help := flags.Bool("help", false, "Show usage information")
+	noTui := flags.Bool("no-tui", false, "Disable interactive TUI viewer")
+	jsonOutput := flags.Bool("json", false, "Output JSON format to stdout")
+	htmlOutput := flags.String("html", "", "Generate HTML report to specified file (e.g., --html report.html)")
+	htmlOptions := addHTMLFlags(flags)
+	plainOutput := flags.Bool("plain", false, "Output plain text summary to stdout")
+	markdownOutput := flags.Bool("markdown", false, "Output a Markdown summary for issue trackers to stdout")
+	templateOutput := flags.String("template", "", "Render the results through this Go text/template file to stdout")
+	ndjsonOutput := flags.Bool("ndjson", false, "Stream one JSON object per finding to stdout as files are checked (no reports, notifications or history)")
+	ckanPublish := flags.String("ckan-publish", "", "Post the report back to the CKAN package as 'resource' or 'extra' (overrides the CkanCollector 'publish' attribute)")
+	historyDir := flags.String("history-dir", "", "Store scan results in this directory (overrides 'historyDir' in the config)")
+	outputDir := flags.String("output-dir", "", "Write reports given with a relative path to this directory (overrides 'outputDir' in the config)")
+	traceOutput := flags.String("trace", "", "Write per file why it was checked or not, which checks ran or were skipped and why, and how long each took as JSON to this file")
+	lang := flags.String("lang", "", langFlagUsage)
+	timezone := flags.String("timezone", "", timezoneFlagUsage+" (overrides 'timezone' in the config, default the local one)")
+	fullSummary := flags.Bool("full-summary", false, fullSummaryUsage)
+	redact := flags.Bool("redact", false, "Redact matched secrets, the user name and absolute paths in all output, for reports shared outside the institution (overrides 'redact' in the config)")
+	baselineFlag := flags.String("baseline", "", "File of findings triaged in the TUI that are hidden (overrides 'baseline' in the config, default "+baseline.DefaultPath+")")
+	noBaseline := flags.Bool("no-baseline", false, "Report all findings, ignoring the baseline")
+	diff := flags.Bool("diff", false, "Show new, fixed and unchanged issues compared to the previous scan of the location (requires a history directory)")
+	listArchives := flags.Bool("list-archives", false, "List the members of the archives with their sizes and types without running the checks (as text, or with -json/-html as report)")
+	listChecks := flags.Bool("list-checks", false, "List all available checks with their configuration status and exit")
+	schema := flags.Bool("schema", false, "Print the JSON Schema of the -json output and exit")
+	logging := addLogFlags(flags)
+	cpuprofile := flags.String("cpuprofile", "", "write cpu profile to file")
+	memprofile := flags.String("memprofile", "", "write memory profile to file")
+	flags.Parse(args)
+
+	// Validate mutually exclusive flags
+	if *jsonOutput && *plainOutput {
+		fmt.Fprintln(os.Stderr, "Error: --json and --plain cannot be used together. Please choose one output format.")
+		os.Exit(1)
+	}
+	if *markdownOutput && (*jsonOutput || *plainOutput) {
+		fmt.Fprintln(os.Stderr, "Error: --markdown cannot be used together with --json or --plain. Please choose one output format.")
+		os.Exit(1)
+	}
+	if *templateOutput != "" && (*jsonOutput || *plainOutput || *markdownOutput) {
+		fmt.Fprintln(os.Stderr, "Error: --template cannot be used together with --json, --plain or --markdown. Please choose one output format.")
+		os.Exit(1)
+	}
+	if *ndjsonOutput && (*jsonOutput || *plainOutput || *markdownOutput || *templateOutput != "" || *htmlOutput != "" || *diff) {
+		fmt.Fprintln(os.Stderr, "Error: --ndjson streams the findings and cannot be used together with other output formats, --html or --diff.")
+		os.Exit(1)
+	}
+
+	if *listArchives && (*ndjsonOutput || *plainOutput || *markdownOutput || *templateOutput != "" || *diff) {
+		fmt.Fprintln(os.Stderr, "Error: --list-archives writes a text listing, a JSON or an HTML report and cannot be used together with other output formats or --diff.")
+		os.Exit(1)
+	}
+
+	htmlFormatter, err := htmlOptions.formatter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Template mistakes show before scanning
+	var templateFormatter *templateformatter.TemplateFormatter
+	if *templateOutput != "" {
+		templateFormatter, err = templateformatter.NewTemplateFormatter(*templateOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Configure logger for JSON mode by default
+	output.GlobalLogger.SetJSONMode(true)
+	if err := logging.apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	
+	// Enable CPU profiling if requested
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			log.Fatal(err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if *help {
+		flags.Usage()
+		return
+	}
+
+	if *schema {
+		os.Stdout.Write(jsonformatter.Schema)
+		return
+	}
+
+	if *listChecks {
+		listChecksWithConfig(*cfg, *profile, overrides, *offline, *jsonOutput)
+		return
+	}
+
+	generalConfig, err := loadConfig(*cfg, overrides, *offline)
+	if err != nil {
+		// Output config error in JSON format
+		errorResult := map[string]interface{}{
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"error": map[string]string{
+				"type": "config_error",
+				"message": fmt.Sprintf("Error loading config: %v", err),
+			},
+		}
+		if jsonBytes, marshalErr := json.MarshalIndent(errorResult, "", "  "); marshalErr == nil {
+			fmt.Println(string(jsonBytes))
+		} else {
+			fmt.Printf("{\"error\": \"Error loading config: %v\"}\n", err)
+		}
+		return
+	}
+
+	var (
+		files    []structs.File
+		filesErr error
+	)
+
+	// Helper function to output error in JSON format
+	outputError := func(errorType, message string) {
+		errorResult := map[string]interface{}{
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"error": map[string]string{
+				"type":    errorType,
+				"message": message,
+			},
+		}
+		if jsonBytes, marshalErr := json.MarshalIndent(errorResult, "", "  "); marshalErr == nil {
+			fmt.Println(string(jsonBytes))
+		} else {
+			fmt.Printf("{\"error\": \"%s\"}\n", message)
+		}
+	}
+
+	if err := generalConfig.ApplyProfile(*profile); err != nil {
+		outputError("config_error", fmt.Sprintf("Error loading config: %v", err))
+		return
+	}
+	if err := selectChecks(generalConfig, *onlyChecks, *skipChecks); err != nil {
+		outputError("config_error", err.Error())
+		return
+	}
+	if err := selectLanguage(*lang, generalConfig.General.Language); err != nil {
+		outputError("config_error", err.Error())
+		return
+	}
+	if err := selectTimezone(*timezone, generalConfig.General.Timezone); err != nil {
+		outputError("config_error", err.Error())
+		return
+	}
+	if *redact {
+		generalConfig.General.Redact = true
+	}
+	checks.SetSeverities(*generalConfig)
+
+	// Ctrl-C cancels the scan: the checks stop early and release the archives they have open
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		// A second Ctrl-C quits at once
+		<-ctx.Done()
+		stop()
+	}()
+	*generalConfig = generalConfig.WithContext(ctx)
+
+	// Rule expressions are compiled up front so mistakes show before scanning
+	if _, err := rules.Load(*generalConfig); err != nil {
+		outputError("config_error", fmt.Sprintf("Error loading config: %v", err))
+		return
+	}
+
+	// The report directory is checked up front, read-only containers would fail only after the scan
+	if *outputDir == "" {
+		*outputDir = generalConfig.General.OutputDir
+	}
+	if *htmlOutput != "" {
+		if *htmlOutput, err = resolveReportPath(*htmlOutput, *outputDir); err == nil {
+			err = checkWritable(*htmlOutput)
+		}
+		if err != nil {
+			outputError("html_error", fmt.Sprintf("Error generating HTML report: %v", err))
+			return
+		}
+	}
+	var tracer *trace.Tracer
+	if *traceOutput != "" {
+		if *traceOutput, err = resolveReportPath(*traceOutput, *outputDir); err == nil {
+			err = checkWritable(*traceOutput)
+		}
+		if err != nil {
+			outputError("trace_error", fmt.Sprintf("Error writing trace: %v", err))
+			return
+		}
+		tracer = trace.New()
+		*generalConfig = generalConfig.WithTracer(tracer)
+	}
+
+	// Triaged findings are hidden from every output; without a baseline nothing is hidden and
+	// the TUI cannot triage
+	var triaged *baseline.Baseline
+	baselinePath := resolveBaselinePath(*baselineFlag, *generalConfig)
+	if !*noBaseline {
+		triaged, err = baseline.Load(baselinePath)
+		if err != nil {
+			outputError("config_error", fmt.Sprintf("Error loading baseline: %v", err))
+			return
+		}
+	}
+
+	// The temporary files of the scan are removed when pc exits, also if the scan is cancelled
+	// or panics
+	ws := generalConfig.NewWorkspace()
+	defer ws.Close()
+	*generalConfig = generalConfig.WithWorkspace(ws)
+
+	// The reports list the files the scan does not check, from failed downloads on
+	*generalConfig = generalConfig.WithSkippedFiles(output.NewSkippedFiles())
+
+	// Resources downloaded from CKAN are kept until pc exits or the TUI rescans, the TUI opens them
+	removeDownloads := func() {}
+	if generalConfig.Operation["main"].Collector == "CkanCollector" {
+		downloadConfig, remove, err := collectors.PrepareDownloads(*generalConfig)
+		if err != nil {
+			outputError("collector_error", err.Error())
+			return
+		}
+		removeDownloads = remove
+		*generalConfig = downloadConfig
+	}
+
+	// Collect the files with the collector of the config, the scan lists the PDF files it finds
+	*generalConfig = generalConfig.WithPDFTracker(helpers.NewPDFTracker())
+	*generalConfig, files, filesErr = collectFiles(*generalConfig, *folder_or_url)
+	if ctx.Err() != nil {
+		outputError("cancelled", "Scan cancelled")
+		return
+	} else if errors.Is(filesErr, errNoFiles) {
+		outputError("no_files", filesErr.Error())
+		return
+	} else if filesErr != nil {
+		outputError("collector_error", filesErr.Error())
+		return
+	}
+	tracer.Collected(files)
+
+	// Shared reports name the package without the folders and the user it was scanned by
+	var redactor *output.Redactor
+	if generalConfig.General.Redact {
+		redactor = output.NewRedactor(generalConfig.PackageRoot())
+		output.GlobalLogger.SetRedactor(redactor)
+		// The checks add the user names they find
+		*generalConfig = generalConfig.WithRedactor(redactor)
+	}
+	reportLocation := redactor.Path(*folder_or_url)
+
+	// Archives are listed from their headers, the checks do not run
+	if *listArchives {
+		listings := jsonformatter.ListArchives(*generalConfig, files, redactor)
+		if ctx.Err() != nil {
+			outputError("cancelled", "Scan cancelled")
+			return
+		}
+		skipped := redactor.SkippedFiles(generalConfig.SkippedFiles().Files())
+		if err := writeArchiveListing(listings, skipped, reportLocation, generalConfig.Operation["main"].Collector, len(files), *jsonOutput, htmlFormatter, *htmlOutput); err != nil {
+			outputError("formatting_error", err.Error())
+		}
+		return
+	}
+
+	// Findings are streamed as they are found, nothing is kept for reports
+	if *ndjsonOutput {
+		if err := streamFindings(*generalConfig, files, reportLocation, triaged, redactor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			ws.Close()
+			os.Exit(1)
+		}
+		if err := writeTrace(tracer, redactor.SkippedFiles(generalConfig.SkippedFiles().Files()), *traceOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing trace: %v\n", err)
+		}
+		return
+	}
+
+	// Determine output modes
+	generateHtml := *htmlOutput != ""
+	var htmlUpload *htmlformatter.HTMLFormatter
+	if generateHtml {
+		htmlUpload = htmlFormatter
+	}
+
+	// Publishing reports back to CKAN only makes sense for CKAN packages
+	publishMode := *ckanPublish
+	if publishMode == "" {
+		publishMode = collectors.PublishMode(*generalConfig)
+	}
+	if generalConfig.Operation["main"].Collector != "CkanCollector" {
+		publishMode = ""
+	}
+	showTui := !*noTui && !*jsonOutput && !*plainOutput && !*markdownOutput && *templateOutput == "" && !*diff
+
+	// Scan history, needed for diffing
+	if *historyDir == "" {
+		*historyDir = generalConfig.General.HistoryDir
+	}
+	if *diff && *historyDir == "" {
+		outputError("config_error", "--diff requires a history directory (use '-history-dir' or set 'historyDir' in the config)")
+		return
+	}
+
+	// Set up notifiers from the [notify.*] config sections
+	notifiers, err := notify.FromConfig(*generalConfig)
+	if err != nil {
+		outputError("config_error", fmt.Sprintf("Error loading notifiers: %v", err))
+		return
+	}
+
+	if showTui {
+		// TUI mode (default behavior)
+		app := tui.NewScanningApp()
+		app.SetLocation(reportLocation)
+		if triaged != nil {
+			app.SetBaseline(baselinePath, triaged)
+		}
+		if generalConfig.General.LetterTemplate != "" {
+			letter, err := tui.LoadLetter(generalConfig.General.LetterTemplate)
+			if err != nil {
+				outputError("config_error", fmt.Sprintf("Error loading letter template: %v", err))
+				return
+			}
+			app.SetLetter(letter)
+		}
+		app.SetTruncation(summaryTruncation(generalConfig, *fullSummary))
+
+		// Quitting the TUI cancels a running scan
+		scanCtx, cancelScan := context.WithCancel(ctx)
+		defer cancelScan()
+		scanConfig := generalConfig.WithContext(scanCtx)
+
+		// Channel for scan completion
+		scanComplete := make(chan *tui.ScanResult)
+		scanErrors := make(chan error)
+
+		// Store JSON result for potential HTML generation
+		var jsonResultForHtml string
+		var publishErr error
+		var notifyErrs []error
+		var historyErr error
+		var traceErr error
+
+		// scanFiles checks files with cfg and shows the results in the TUI. Reports are posted to CKAN and
+		// notifications are sent for the first scan only, not for rescans.
+		scanFiles := func(cfg config.Config, files []structs.File, firstScan bool) {
+			// Start scanning in a goroutine
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						scanErrors <- fmt.Errorf("scan panic: %v", r)
+					}
+				}()
+
+				// Update progress to show scanning started
+				app.UpdateProgress(0, 1, "Starting scan...")
+
+				// Run scanning with progress updates
+				messages := utils.ApplyAllChecksWithProgress(cfg, files, true, func(current, total int, message string) {
+					app.UpdateProgress(current, total, message)
+				})
+				if scanCtx.Err() != nil {
+					// The findings of a cancelled scan are incomplete, do not publish them
+					return
+				}
+				// Rescans replace the trace; failures are reported after the TUI exits
+				skipped := redactor.SkippedFiles(cfg.SkippedFiles().Files())
+				traceErr = writeTrace(tracer, skipped, *traceOutput)
+
+				// Create JSON formatter and generate output
+				formatter := jsonformatter.NewJSONFormatter()
+				formatter.Skipped = skipped
+				formatter.Root = issueRoot(cfg, redactor)
+				if generalConfig.General.ListArchives {
+					formatter.Archives = jsonformatter.ListArchives(*generalConfig, files, redactor)
+				}
+				if generalConfig.General.FileMetadata || generalConfig.General.HashFiles {
+					formatter.Files = jsonformatter.ListFiles(*generalConfig, files, generalConfig.General.HashFiles, redactor)
+				}
+				messages = hideTriaged(triaged, messages, cfg.PackageRoot(), formatter)
+				messages = utils.RedactMessages(utils.TranslateMessages(messages), redactor)
+
+				// Get collector name from config
+				collectorName := generalConfig.Operation["main"].Collector
+
+				result := formatter.Result(reportLocation, collectorName, messages, len(files), redactor.Paths(cfg.PDFTracker().Files))
+				jsonResult, err := result.JSON()
+				if err != nil {
+					scanErrors <- fmt.Errorf("formatting error: %v", err)
+					return
+				}
+
+				// Store for HTML generation if needed
+				jsonResultForHtml = jsonResult
+
+				// Generate HTML if requested (during TUI scan)
+				if generateHtml {
+					if err := htmlFormatter.GenerateReport(jsonResult, *htmlOutput); err != nil {
+						scanErrors <- fmt.Errorf("HTML generation error: %v", err)
+						return
+					}
+				}
+
+				if firstScan {
+					// Post reports back to CKAN; failures are reported after the TUI exits
+					if publishMode != "" {
+						publishErr = publishToCkan(*folder_or_url, *generalConfig, publishMode, jsonResult, htmlUpload)
+					}
+
+					// Send notifications; failures are reported after the TUI exits
+					summary := notify.NewSummary(reportLocation, collectorName, messages, len(files))
+					if htmlOptions.attachable() {
+						summary.HTMLReportPath = *htmlOutput
+					}
+					notifyErrs = notify.NotifyAll(notifiers, summary)
+				}
+
+				// Keep the result for later diffs; failures are reported after the TUI exits
+				if *historyDir != "" {
+					historyErr = saveToHistory(*historyDir, *folder_or_url, jsonResult)
+				}
+
+				// Send results
+				scanComplete <- result
+			}()
+
+			// Handle scan completion
+			go func() {
+				select {
+				case result := <-scanComplete:
+					app.UpdateData(result)
+				case err := <-scanErrors:
+					app.ScanFailed(err)
+				}
+			}()
+		}
+
+		// Scan when the TUI starts and again on "R", with the files collected anew
+		app.SetStartupCallback(func() {
+			scanFiles(scanConfig, files, true)
+		})
+		app.SetRescanFunc(func() {
+			output.GlobalLogger.ClearMessages()
+			tracer.Reset()
+			app.UpdateProgress(0, 1, "Collecting files...")
+			collectConfig := scanConfig.WithPDFTracker(helpers.NewPDFTracker()).WithSkippedFiles(output.NewSkippedFiles()).WithDownloadProgress(func(done, total int64) {
+				app.UpdateProgress(int(done), int(total), "Downloading files...")
+			})
+			// The downloads of the previous scan are removed once the new ones are in place, so
+			// rescans do not fill the workspace
+			collectConfig, remove, err := collectors.PrepareDownloads(collectConfig)
+			if err != nil {
+				app.ScanFailed(err)
+				return
+			}
+			collectConfig, files, err := collectFiles(collectConfig, *folder_or_url)
+			if err != nil {
+				remove()
+				app.ScanFailed(err)
+				return
+			}
+			removeDownloads()
+			removeDownloads = remove
+			tracer.Collected(files)
+			scanFiles(collectConfig, files, false)
+		})
+
+		// Run TUI (this blocks until user exits)
+		if err := app.Run(); err != nil {
+			outputError("tui_error", fmt.Sprintf("Error running TUI: %v", err))
+			return
+		}
+		cancelScan()
+
+		// Issues triaged in the TUI are hidden by the next scans
+		if saved, err := app.SaveBaseline(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving baseline: %v\n", err)
+		} else if saved {
+			fmt.Printf("Triaged issues saved to %s\n", baselinePath)
+		}
+
+		// After TUI exits, print HTML generation message if applicable
+		if generateHtml && jsonResultForHtml != "" {
+			fmt.Printf("HTML report generated: %s\n", *htmlOutput)
+		}
+		if publishErr != nil {
+			fmt.Fprintf(os.Stderr, "Error publishing report to CKAN: %v\n", publishErr)
+		}
+		for _, notifyErr := range notifyErrs {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", notifyErr)
+		}
+		if historyErr != nil {
+			fmt.Fprintf(os.Stderr, "Error storing scan history: %v\n", historyErr)
+		}
+		if traceErr != nil {
+			fmt.Fprintf(os.Stderr, "Error writing trace: %v\n", traceErr)
+		}
+	} else {
+		// Non-TUI mode: run regular scan
+		messages := utils.ApplyAllChecks(*generalConfig, files, true)
+		if ctx.Err() != nil {
+			outputError("cancelled", "Scan cancelled")
+			return
+		}
+		if err := writeTrace(tracer, redactor.SkippedFiles(generalConfig.SkippedFiles().Files()), *traceOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing trace: %v\n", err)
+		}
+
+		// Get collector name from config
+		collectorName := generalConfig.Operation["main"].Collector
+
+		// Generate JSON result (needed for HTML and JSON output)
+		skipped := redactor.SkippedFiles(generalConfig.SkippedFiles().Files())
+		formatter := jsonformatter.NewJSONFormatter()
+		formatter.Skipped = skipped
+		formatter.Root = issueRoot(*generalConfig, redactor)
+		if generalConfig.General.ListArchives {
+			formatter.Archives = jsonformatter.ListArchives(*generalConfig, files, redactor)
+		}
+		if generalConfig.General.FileMetadata || generalConfig.General.HashFiles {
+			formatter.Files = jsonformatter.ListFiles(*generalConfig, files, generalConfig.General.HashFiles, redactor)
+		}
+		messages = hideTriaged(triaged, messages, generalConfig.PackageRoot(), formatter)
+		messages = utils.RedactMessages(utils.TranslateMessages(messages), redactor)
+		scanResult := formatter.Result(reportLocation, collectorName, messages, len(files), redactor.Paths(generalConfig.PDFTracker().Files))
+		jsonResult, err := scanResult.JSON()
+		if err != nil {
+			outputError("formatting_error", fmt.Sprintf("Error formatting output: %v", err))
+			return
+		}
+
+		// Generate HTML if requested
+		if generateHtml {
+			if err := htmlFormatter.GenerateReport(jsonResult, *htmlOutput); err != nil {
+				outputError("html_error", fmt.Sprintf("Error generating HTML report: %v", err))
+				return
+			}
+			fmt.Printf("HTML report generated: %s\n", *htmlOutput)
+		}
+
+		// Post reports back to CKAN (a failure does not invalidate the scan output)
+		if publishMode != "" {
+			if err := publishToCkan(*folder_or_url, *generalConfig, publishMode, jsonResult, htmlUpload); err != nil {
+				fmt.Fprintf(os.Stderr, "Error publishing report to CKAN: %v\n", err)
+			}
+		}
+
+		// Send notifications (a failure does not invalidate the scan output)
+		summary := notify.NewSummary(reportLocation, collectorName, messages, len(files))
+		if htmlOptions.attachable() {
+			summary.HTMLReportPath = *htmlOutput
+		}
+		for _, notifyErr := range notify.NotifyAll(notifiers, summary) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", notifyErr)
+		}
+
+		// Keep the result for later diffs
+		if *historyDir != "" {
+			if err := saveToHistory(*historyDir, *folder_or_url, jsonResult); err != nil {
+				if *diff {
+					outputError("history_error", fmt.Sprintf("Error storing scan history: %v", err))
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error storing scan history: %v\n", err)
+			}
+		}
+
+		// Output to stdout based on flags
+		if *diff {
+			store, _ := history.NewStore(*historyDir)
+			scanDiff, err := store.DiffLatest(*folder_or_url)
+			if errors.Is(err, history.ErrNoPrevious) {
+				outputError("no_previous_scan", fmt.Sprintf("No previous scan of '%s' to compare with", *folder_or_url))
+				return
+			} else if err != nil {
+				outputError("history_error", fmt.Sprintf("Error comparing scans: %v", err))
+				return
+			}
+			scanDiff.Location = reportLocation
+			if *jsonOutput {
+				jsonBytes, _ := json.MarshalIndent(scanDiff, "", "  ")
+				fmt.Println(string(jsonBytes))
+			} else {
+				fmt.Print(scanDiff.FormatText())
+			}
+		} else if *jsonOutput {
+			fmt.Println(jsonResult)
+		} else if *plainOutput {
+			plainFormatter := plainformatter.NewPlainFormatter()
+			plainFormatter.Skipped = skipped
+			plainResult := plainFormatter.FormatResults(reportLocation, collectorName, messages, len(files), redactor.Paths(generalConfig.PDFTracker().Files))
+			fmt.Print(plainResult)
+		} else if *markdownOutput {
+			fmt.Print(markdownSummary(scanResult, reportLocation, summaryTruncation(generalConfig, *fullSummary)))
+		} else if templateFormatter != nil {
+			result, err := templateFormatter.Format(scanResult, reportLocation)
+			if err != nil {
+				outputError("formatting_error", fmt.Sprintf("Error formatting output: %v", err))
+				return
+			}
+			fmt.Print(result)
+		}
+		// If only --no-tui (with or without --html), no stdout output beyond HTML message
+	}
+	
+	// Enable memory profiling if requested
+	if *memprofile != "" {
+		f, err := os.Create(*memprofile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// saveToHistory stores the JSON result of a scan of location in the history directory
+func saveToHistory(dir string, location string, jsonResult string) error {
+	store, err := history.NewStore(dir)
+	if err != nil {
+		return err
+	}
+	_, err = store.Save(location, jsonResult)
+	return err
+}
+
+// errNoFiles is returned by collectFiles if the location has no files
+var errNoFiles = errors.New("No files found")
+
+// collectFiles collects the files of location with the collector of the config. The returned
+// config carries the package metadata if the collector retrieved it for the metadata checks.
+func collectFiles(cfg config.Config, location string) (config.Config, []structs.File, error) {
+	var files []structs.File
+	var err error
+	switch cfg.Operation["main"].Collector {
+	case "LocalCollector":
+		files, err = collectors.LocalCollector(location, cfg)
+		cfg = cfg.WithPackageRoot(location)
+	case "CkanCollector":
+		if location == "." {
+			return cfg, nil, errors.New("Please provide a CKAN package name (use the location flag '-location')")
+		}
+		cfg, files, err = collectors.CkanCollectorWithMetadata(location, cfg)
+	default:
+		return cfg, nil, errors.New("Unknown collector")
+	}
+	if err != nil {
+		return cfg, nil, err
+	}
+	// Scans of the metadata only have no files
+	if len(files) == 0 && cfg.Metadata() == nil {
+		return cfg, nil, fmt.Errorf("%w in location: %s", errNoFiles, location)
+	}
+	return cfg, files, nil
+}
+
+// writeTrace writes the trace of the scan to path, with the files the scan skipped and why.
+// Without a tracer nothing is written.
+func writeTrace(tracer *trace.Tracer, skippedFiles []output.SkippedFile, path string) error {
+	if tracer == nil {
+		return nil
+	}
+	for _, skipped := range skippedFiles {
+		tracer.Excluded(skipped.Path, skipped.Filename, skipped.ArchiveName, fmt.Sprintf("%s: %s", skipped.Code, skipped.Reason))
+	}
+	return tracer.Write(path)
+}
+
+// resolveBaselinePath returns the -baseline flag, 'baseline' in the config or baseline.DefaultPath
+func resolveBaselinePath(flagValue string, cfg config.Config) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if cfg.General != nil && cfg.General.Baseline != "" {
+		return cfg.General.Baseline
+	}
+	return baseline.DefaultPath
+}
+
+// hideTriaged removes the findings of the baseline from messages of the package scanned at root
+// and reports their number in the JSON result
+func hideTriaged(triaged *baseline.Baseline, messages []structs.Message, root string, formatter *jsonformatter.JSONFormatter) []structs.Message {
+	messages, counts := triaged.Filter(messages, root)
+	formatter.Suppressed = jsonformatter.Suppressed{Accepted: counts.Accepted, FalsePositives: counts.FalsePositives}
+	return messages
+}
+
+// issueRoot returns the folder the IDs of the findings hash their paths relative to, so they do not
+// depend on how the location was given. Redacted paths are relative to it already.
+func issueRoot(cfg config.Config, redactor *output.Redactor) string {
+	if redactor != nil {
+		return ""
+	}
+	return cfg.PackageRoot()
+}
+
+// streamFindings checks files and writes each finding as a line of JSON as soon as its file is
+// checked, followed by a summary line. The scan fails if it is cancelled or the findings cannot
+// be written.
+func streamFindings(cfg config.Config, files []structs.File, location string, triaged *baseline.Baseline, redactor *output.Redactor) error {
+	writer := jsonformatter.NewNDJSONWriter(os.Stdout)
+	writer.Root = issueRoot(cfg, redactor)
+	var writeErr error
+	utils.ApplyAllChecksStreaming(cfg, files, true, func(messages []structs.Message) {
+		messages, counts := triaged.Filter(messages, cfg.PackageRoot())
+		writer.Suppressed.Accepted += counts.Accepted
+		writer.Suppressed.FalsePositives += counts.FalsePositives
+		if writeErr == nil {
+			writeErr = writer.WriteMessages(utils.RedactMessages(utils.TranslateMessages(messages), redactor))
+		}
+	})
+	if cfg.Context().Err() != nil {
+		// Without the summary line readers can tell the findings are incomplete
+		return errors.New("scan cancelled")
+	}
+	if writeErr == nil {
+		writer.Skipped = redactor.SkippedFiles(cfg.SkippedFiles().Files())
+		writeErr = writer.WriteSummary(location, len(files))
+	}
+	if writeErr != nil {
+		return fmt.Errorf("writing findings: %w", writeErr)
+	}
+	return nil
+}
+
+// writeArchiveListing writes the archive listing as JSON, as HTML report to htmlPath or otherwise as text
+func writeArchiveListing(listings []jsonformatter.ArchiveListing, skipped []jsonformatter.SkippedFile, location, collectorName string, totalFiles int, jsonOutput bool, htmlFormatter *htmlformatter.HTMLFormatter, htmlPath string) error {
+	if !jsonOutput && htmlPath == "" {
+		fmt.Print(plainformatter.NewPlainFormatter().FormatArchives(location, listings))
+		return nil
+	}
+
+	formatter := jsonformatter.NewJSONFormatter()
+	formatter.Archives = listings
+	formatter.Skipped = skipped
+	jsonResult, err := formatter.Result(location, collectorName, nil, totalFiles, nil).JSON()
+	if err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+	if htmlPath != "" {
+		if err := htmlFormatter.GenerateReport(jsonResult, htmlPath); err != nil {
+			return fmt.Errorf("error generating HTML report: %w", err)
+		}
+		fmt.Printf("HTML report generated: %s\n", htmlPath)
+	}
+	if jsonOutput {
+		fmt.Println(jsonResult)
+	}
+	return nil
+}
+
+// markdownSummary renders the report as a Markdown summary for issue trackers
+func markdownSummary(scanResult *jsonformatter.ScanResult, location string, truncation tui.Truncation) string {
+	generator := tui.NewSummaryGenerator(scanResult, location)
+	generator.SetTruncation(truncation)
+	return generator.GenerateMarkdown()
+}
+
+// publishToCkan uploads the JSON report (and the HTML report, if one was generated) to the CKAN package
+// htmlFormatter is nil if no HTML report was generated.
+func publishToCkan(packageID string, cfg config.Config, mode string, jsonResult string, htmlFormatter *htmlformatter.HTMLFormatter) error {
+	uploads := []collectors.ReportUpload{
+		{Name: "pc_report.json", Format: "JSON", Content: []byte(jsonResult)},
+	}
+	if htmlFormatter != nil {
+		// The uploaded report is a single file, also if the local one is split
+		htmlContent, err := htmlFormatter.SelfContained(jsonResult)
+		if err != nil {
+			return fmt.Errorf("failed to render HTML report: %w", err)
+		}
+		uploads = append(uploads, collectors.ReportUpload{Name: "pc_report.html", Format: "HTML", Content: htmlContent})
+	}
+	return collectors.PublishReport(packageID, cfg, mode, uploads)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+)
+
+// runSchema implements `pc schema`, printing the JSON Schema of the JSON reports
+func runSchema(args []string) {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc schema")
+		fmt.Fprintf(flags.Output(), "Prints the JSON Schema (version %s) of the reports written with -json.\n", jsonformatter.SchemaVersion)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	os.Stdout.Write(jsonformatter.Schema)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/server"
+)
+
+// runServe implements `pc serve`, the same server as the pc-server binary
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", server.DefaultAddress(), "Server listen address (e.g., :8080 or 0.0.0.0:8080, default :$PORT if PORT is set)")
+	configPath := flags.String("config", config.FindConfigFile(), "Path or https:// URL of the config file (the built-in default is used if none is found)")
+	ckanURL := flags.String("ckan-url", "", "CKAN base URL (overrides config)")
+	profile := flags.String("profile", "", "Use the [operation.<profile>] section of the config instead of [operation.main]")
+	var overrides stringList
+	flags.Var(&overrides, "set", setFlagUsage)
+	logging := addLogFlags(flags)
+	flags.Parse(args)
+
+	if err := logging.apply(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if *configPath == "" {
+		log.Println("No config file found, using the built-in default and the PC_* environment variables.")
+	}
+
+	srv, err := server.New(server.Config{
+		Address:       *addr,
+		ConfigPath:    *configPath,
+		DefaultConfig: defaultConfig,
+		CKANBaseURL:   *ckanURL,
+		Profile:       *profile,
+		Overrides:     overrides,
+		VerifyTLS:     true,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+
+	if err := srv.Run(); err != nil {
+		log.Fatalf("Server stopped: %v", err)
+	}
+}
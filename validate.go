@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/eawag-rdm/pc/pkg/config"
+	"github.com/eawag-rdm/pc/pkg/validate"
+)
+
+// runConfig implements the `pc config` commands
+func runConfig(args []string) {
+	if len(args) > 0 && args[0] == "validate" {
+		runConfigValidate(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "init" {
+		runConfigInit(args[1:])
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  pc config validate [-config pc.toml] [-json]")
+	fmt.Fprintln(os.Stderr, "  pc config init [-xdg | -o path] [-force]")
+	os.Exit(2)
+}
+
+// runConfigInit implements `pc config init`, writing the built-in default config as a starting point
+func runConfigInit(args []string) {
+	flags := flag.NewFlagSet("config init", flag.ExitOnError)
+	output := flags.String("o", "pc.toml", "Path of the config file to write")
+	xdg := flags.Bool("xdg", false, "Write the per-user config "+config.XDGConfigPath()+" instead")
+	force := flags.Bool("force", false, "Overwrite an existing config file")
+	flags.Parse(args)
+
+	path := *output
+	if *xdg {
+		path = config.XDGConfigPath()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, mode, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		fmt.Fprintf(os.Stderr, "Error: '%s' already exists, use -force to overwrite it\n", path)
+		os.Exit(1)
+	}
+	if err == nil {
+		_, err = file.Write(defaultConfig)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote '%s'. Edit it and check it with 'pc config validate -config %s'.\n", path, path)
+}
+
+// runConfigValidate implements `pc config validate`, reporting every problem with its line and field
+func runConfigValidate(args []string) {
+	flags := flag.NewFlagSet("config validate", flag.ExitOnError)
+	configPath := flags.String("config", config.FindConfigFile(), "Path or https:// URL of the config file")
+	asJSON := flags.Bool("json", false, "Print the problems as JSON")
+	flags.Parse(args)
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: No config file found. Please specify with -config flag or create one with 'pc config init'.")
+		os.Exit(1)
+	}
+
+	diagnostics := validate.File(*configPath)
+	if *asJSON {
+		if diagnostics == nil {
+			diagnostics = []validate.Diagnostic{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(diagnostics)
+	} else {
+		printDiagnostics(*configPath, diagnostics)
+	}
+
+	if validate.HasErrors(diagnostics) {
+		os.Exit(1)
+	}
+}
+
+// printDiagnostics prints one problem per line followed by a summary
+func printDiagnostics(path string, diagnostics []validate.Diagnostic) {
+	errors, warnings := 0, 0
+	for _, d := range diagnostics {
+		fmt.Println(d)
+		if d.Severity == validate.SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	switch {
+	case errors > 0:
+		fmt.Printf("Config '%s' is invalid: %d error(s), %d warning(s).\n", path, errors, warnings)
+	case warnings > 0:
+		fmt.Printf("Config '%s' is valid with %d warning(s).\n", path, warnings)
+	default:
+		fmt.Printf("Config '%s' is valid.\n", path)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eawag-rdm/pc/pkg/baseline"
+	"github.com/eawag-rdm/pc/pkg/i18n"
+	jsonformatter "github.com/eawag-rdm/pc/pkg/output/json"
+	"github.com/eawag-rdm/pc/pkg/output/tui"
+)
+
+// runView implements `pc view report.json...`, opening saved JSON reports in the TUI. Several
+// reports, or a directory of them, are switched between in the TUI.
+func runView(args []string) {
+	flags := flag.NewFlagSet("view", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pc view <report.json|directory>...")
+		flags.PrintDefaults()
+	}
+	baselinePath := flags.String("baseline", baseline.DefaultPath, "File issues triaged in the TUI are saved to, hiding them in later scans")
+	letterPath := flags.String("letter", "", "Go text/template the copy-paste summary is written with (default $PC_LETTER_TEMPLATE or the built-in text)")
+	fullSummary := flags.Bool("full-summary", false, fullSummaryUsage)
+	lang := flags.String("lang", "", "Language of the viewer: "+strings.Join(i18n.Languages(), ", ")+" (default $PC_LANGUAGE or en)")
+	timezone := flags.String("timezone", "", timezoneFlagUsage+" (default $PC_TIMEZONE or the local one)")
+	flags.Parse(args)
+
+	if err := selectLanguage(*lang, os.Getenv("PC_LANGUAGE")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := selectTimezone(*timezone, os.Getenv("PC_TIMEZONE")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	paths, err := reportPaths(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var reports []tui.Report
+	for _, path := range paths {
+		report, err := loadReport(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		reports = append(reports, report)
+	}
+
+	triaged, err := baseline.Load(*baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	app := tui.NewApp(reports[0].Data)
+	app.SetReports(reports)
+	app.SetBaseline(*baselinePath, triaged)
+	app.SetTruncation(summaryTruncation(nil, *fullSummary))
+	if *letterPath == "" {
+		*letterPath = os.Getenv("PC_LETTER_TEMPLATE")
+	}
+	if *letterPath != "" {
+		letter, err := tui.LoadLetter(*letterPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		app.SetLetter(letter)
+	}
+	if err := app.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+	if saved, err := app.SaveBaseline(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving baseline: %v\n", err)
+		os.Exit(1)
+	} else if saved {
+		fmt.Printf("Triaged issues saved to %s\n", *baselinePath)
+	}
+}
+
+// reportPaths expands the directories among paths to the JSON reports in them, sorted by name
+func reportPaths(paths []string) ([]string, error) {
+	var reports []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if path == "-" || err != nil || !info.IsDir() {
+			// Missing files are reported when they are read
+			reports = append(reports, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no JSON reports found in '%s'", path)
+		}
+		sort.Strings(matches)
+		reports = append(reports, matches...)
+	}
+	return reports, nil
+}
+
+// loadReport reads and decodes a JSON report for the TUI
+func loadReport(path string) (tui.Report, error) {
+	raw, err := readReport(path)
+	if err != nil {
+		return tui.Report{}, err
+	}
+	scanResult, err := jsonformatter.Parse(raw)
+	if err != nil {
+		return tui.Report{}, fmt.Errorf("'%s' is not a valid report: %w", path, err)
+	}
+	return tui.Report{Name: path, Raw: raw, Data: scanResult}, nil
+}
+
+// readReport reads a JSON report written by `pc scan -json`, "-" reads from stdin
+func readReport(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("'%s' does not contain valid JSON", path)
+	}
+	return data, nil
+}